@@ -0,0 +1,129 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+)
+
+// accessLevel is who may run a slash command or call a REST endpoint
+type accessLevel int
+
+const (
+	// accessAnyUser allows every logged-in user. Handlers check channel permissions themselves.
+	accessAnyUser accessLevel = iota
+
+	// accessControl allows system admins and the roles and users granted backend control
+	// in the plugin settings
+	accessControl
+
+	// accessSystemAdmin allows system admins only
+	accessSystemAdmin
+)
+
+// controlAccess lists the roles and users granted backend control besides system admins.
+// Users are granted by ID, since users can change their username.
+type controlAccess struct {
+	roles   []string
+	userIDs []string
+}
+
+// controlAccess returns the roles and users granted backend control in the plugin settings
+func (c *configuration) controlAccess() controlAccess {
+	return controlAccess{
+		roles:   splitAccessList(c.ControlRoles),
+		userIDs: splitAccessList(c.ControlUsers),
+	}
+}
+
+// validateControlUsers checks that the users granted backend control are listed by user ID
+func (c *configuration) validateControlUsers() error {
+	for _, userID := range c.controlAccess().userIDs {
+		if !model.IsValidId(userID) {
+			return errors.Errorf("backend control users must be listed by user ID ('%s' is not a user ID)", userID)
+		}
+	}
+	return nil
+}
+
+// splitAccessList splits a comma or whitespace separated settings list into lowercase entries
+func splitAccessList(list string) []string {
+	return strings.FieldsFunc(strings.ToLower(list), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// grantsUser reports whether a user is granted backend control by ID
+func (c controlAccess) grantsUser(userID string) bool {
+	return slices.Contains(c.userIDs, userID)
+}
+
+// grantsRole reports whether a user has one of the granted roles
+func (c controlAccess) grantsRole(user *model.User) bool {
+	for _, role := range c.roles {
+		if user.IsInRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAuthorized reports whether a user has an access level. System admins have every level.
+func (p *Plugin) isAuthorized(userID string, level accessLevel) bool {
+	if level == accessAnyUser {
+		return userID != ""
+	}
+	if userID == "" {
+		return false
+	}
+
+	if p.client.User.HasPermissionTo(userID, model.PermissionManageSystem) {
+		return true
+	}
+	if level == accessSystemAdmin {
+		return false
+	}
+
+	access := p.getConfiguration().controlAccess()
+	if access.grantsUser(userID) {
+		return true
+	}
+	if len(access.roles) == 0 {
+		return false
+	}
+
+	user, err := p.client.User.Get(userID)
+	if err != nil {
+		p.API.LogWarn("Failed to get user to check backend control access", "userId", userID, "error", err.Error())
+		return false
+	}
+	return access.grantsRole(user)
+}
+
+// readableChannels returns a function reporting whether a user can read a channel, checking
+// each channel once. Backend control doesn't extend to the content of channels the user can't read.
+func (p *Plugin) readableChannels(userID string) func(channelID string) bool {
+	readable := make(map[string]bool)
+	return func(channelID string) bool {
+		allowed, checked := readable[channelID]
+		if !checked {
+			allowed = p.API.HasPermissionToChannel(userID, channelID, model.PermissionReadChannel)
+			readable[channelID] = allowed
+		}
+		return allowed
+	}
+}
+
+// requireAccess wraps a REST handler so it only runs for users with an access level
+func (p *Plugin) requireAccess(level accessLevel, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !p.isAuthorized(r.Header.Get("Mattermost-User-ID"), level) {
+			http.Error(w, "Not authorized", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}
+}
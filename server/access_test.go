@@ -0,0 +1,128 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	operatorUserID = "8cz6k9dr5jrh7q1ztzqmuzy5ko"
+	managerUserID  = "qdw3n1ofg7fe7kmr1n6gt5dmyw"
+)
+
+func TestConfiguration_ControlAccess(t *testing.T) {
+	config := &configuration{ControlRoles: " system_user_manager, Custom_Operator ", ControlUsers: operatorUserID + ",  " + managerUserID}
+
+	access := config.controlAccess()
+	assert.Equal(t, []string{"system_user_manager", "custom_operator"}, access.roles)
+	assert.Equal(t, []string{operatorUserID, managerUserID}, access.userIDs)
+	require.NoError(t, config.validateControlUsers())
+
+	assert.Empty(t, (&configuration{}).controlAccess().roles)
+
+	// Usernames can change hands, so users are only granted by ID
+	err := (&configuration{ControlUsers: operatorUserID + ", jane.doe"}).validateControlUsers()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'jane.doe' is not a user ID")
+}
+
+func TestIsAuthorized(t *testing.T) {
+	newPlugin := func(api *plugintest.API, config *configuration) *Plugin {
+		p := newCommandTestPlugin(api)
+		p.configuration = config
+		return p
+	}
+
+	t.Run("any user", func(t *testing.T) {
+		p := newPlugin(&plugintest.API{}, &configuration{})
+		assert.True(t, p.isAuthorized("user-id", accessAnyUser))
+		assert.False(t, p.isAuthorized("", accessAnyUser))
+	})
+
+	t.Run("system admins have every access level", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("HasPermissionTo", "admin-id", model.PermissionManageSystem).Return(true)
+
+		p := newPlugin(api, &configuration{})
+		assert.True(t, p.isAuthorized("admin-id", accessControl))
+		assert.True(t, p.isAuthorized("admin-id", accessSystemAdmin))
+	})
+
+	t.Run("granted roles and users have backend control only", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("HasPermissionTo", "manager-id", model.PermissionManageSystem).Return(false)
+		api.On("HasPermissionTo", operatorUserID, model.PermissionManageSystem).Return(false)
+		api.On("HasPermissionTo", "user-id", model.PermissionManageSystem).Return(false)
+		api.On("GetUser", "manager-id").Return(&model.User{Id: "manager-id", Username: "manager", Roles: "system_user system_user_manager"}, nil)
+		api.On("GetUser", "user-id").Return(&model.User{Id: "user-id", Username: "jane.doe", Roles: "system_user"}, nil)
+
+		p := newPlugin(api, &configuration{ControlRoles: "system_user_manager", ControlUsers: operatorUserID})
+		assert.True(t, p.isAuthorized("manager-id", accessControl))
+		assert.True(t, p.isAuthorized(operatorUserID, accessControl))
+		assert.False(t, p.isAuthorized("user-id", accessControl))
+		assert.False(t, p.isAuthorized(operatorUserID, accessSystemAdmin))
+		api.AssertNotCalled(t, "GetUser", operatorUserID)
+	})
+
+	t.Run("a granted user keeps access after changing their username", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("HasPermissionTo", operatorUserID, model.PermissionManageSystem).Return(false)
+
+		p := newPlugin(api, &configuration{ControlUsers: operatorUserID})
+		assert.True(t, p.isAuthorized(operatorUserID, accessControl))
+		api.AssertNotCalled(t, "GetUser", operatorUserID)
+	})
+
+	t.Run("no backend control granted", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("HasPermissionTo", "user-id", model.PermissionManageSystem).Return(false)
+
+		p := newPlugin(api, &configuration{})
+		assert.False(t, p.isAuthorized("user-id", accessControl))
+		api.AssertNotCalled(t, "GetUser", "user-id")
+	})
+}
+
+func TestServeHTTP_ControlAccess(t *testing.T) {
+	api := &plugintest.API{}
+	api.On("HasPermissionTo", operatorUserID, model.PermissionManageSystem).Return(false)
+
+	p := newCommandTestPlugin(api)
+	p.configuration = &configuration{ControlUsers: operatorUserID}
+	p.registry = backend.NewRegistry()
+
+	serve := func(method, path string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, nil)
+		r.Header.Set("Mattermost-User-ID", operatorUserID)
+		p.ServeHTTP(nil, w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/backends/status"))
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/api/v1/config/export"))
+}
+
+func TestExecuteCommand_ControlAccess(t *testing.T) {
+	api := &plugintest.API{}
+	api.On("HasPermissionTo", "operator-id", model.PermissionManageSystem).Return(false)
+	api.On("GetUser", "operator-id").Return(&model.User{Id: "operator-id", Username: "operator", Roles: "system_user custom_operator"}, nil)
+
+	p := newCommandTestPlugin(api)
+	p.configuration = &configuration{ControlRoles: "custom_operator"}
+	p.registry = backend.NewRegistry()
+
+	resp, appErr := p.ExecuteCommand(nil, &model.CommandArgs{Command: "/dataminr state show Missing", UserId: "operator-id"})
+	require.Nil(t, appErr)
+	assert.Equal(t, "Backend `Missing` not found.", resp.Text)
+}
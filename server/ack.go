@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/ack"
+)
+
+const (
+	// pluginID is the plugin ID from plugin.json, used to build message action URLs
+	pluginID = "com.mattermost.plugin-dataminr"
+
+	// ackActionPath is the plugin HTTP path called by the Acknowledge button on alert posts
+	ackActionPath = "/actions/acknowledge"
+
+	// ackReminderJobID is the cluster job ID for the acknowledgment deadline check
+	ackReminderJobID = "dataminr_ack_reminder"
+
+	// ackCheckInterval is how often acknowledgment deadlines are checked
+	ackCheckInterval = time.Minute
+)
+
+// ackActionURL is the integration URL of the Acknowledge button
+func ackActionURL() string {
+	return "/plugins/" + pluginID + ackActionPath
+}
+
+// AckReminder posts a reminder in the thread of Flash alerts that were not acknowledged
+// within their backend's window and sends a direct message to the on-call user.
+// It runs as a cluster job so each reminder is sent by a single node.
+type AckReminder struct {
+	api     plugin.API
+	botID   string
+	tracker *ack.Tracker
+	job     *cluster.Job
+}
+
+// NewAckReminder creates a new acknowledgment reminder job
+func NewAckReminder(api plugin.API, botID string, tracker *ack.Tracker) *AckReminder {
+	return &AckReminder{
+		api:     api,
+		botID:   botID,
+		tracker: tracker,
+	}
+}
+
+// Start schedules the periodic cluster-aware deadline check
+func (a *AckReminder) Start() error {
+	job, err := cluster.Schedule(a.api, ackReminderJobID, cluster.MakeWaitForInterval(ackCheckInterval), a.check)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule acknowledgment reminder job")
+	}
+
+	a.job = job
+	return nil
+}
+
+// Stop cancels the deadline check
+func (a *AckReminder) Stop() {
+	if a.job == nil {
+		return
+	}
+
+	if err := a.job.Close(); err != nil {
+		a.api.LogWarn("Failed to close acknowledgment reminder job", "error", err.Error())
+	}
+	a.job = nil
+}
+
+// check sends reminders for alerts whose acknowledgment deadline has passed
+func (a *AckReminder) check() {
+	overdue, err := a.tracker.TakeOverdue()
+	if err != nil {
+		a.api.LogError("Failed to check acknowledgment deadlines", "error", err.Error())
+	}
+
+	for _, entry := range overdue {
+		a.remind(entry)
+	}
+}
+
+// remind posts the reminder in the alert's thread and notifies the on-call user
+func (a *AckReminder) remind(entry ack.Entry) {
+	window := formatAckWindow(entry.Deadline.Sub(entry.PostedAt))
+
+	message := fmt.Sprintf(":alarm_clock: This Flash alert has not been acknowledged after %s.", window)
+	if entry.OnCallUsername != "" {
+		message += fmt.Sprintf(" @%s please review.", entry.OnCallUsername)
+	}
+
+	if _, appErr := a.api.CreatePost(&model.Post{
+		UserId:    a.botID,
+		ChannelId: entry.ChannelID,
+		RootId:    entry.PostID,
+		Message:   message,
+	}); appErr != nil {
+		a.api.LogError("Failed to post acknowledgment reminder", "postId", entry.PostID, "error", appErr.Error())
+	}
+
+	if entry.OnCallUsername == "" {
+		return
+	}
+
+	user, appErr := a.api.GetUserByUsername(entry.OnCallUsername)
+	if appErr != nil {
+		a.api.LogError("Failed to find on-call user", "username", entry.OnCallUsername, "error", appErr.Error())
+		return
+	}
+
+	channel, appErr := a.api.GetDirectChannel(user.Id, a.botID)
+	if appErr != nil {
+		a.api.LogError("Failed to get direct channel for acknowledgment reminder", "userId", user.Id, "error", appErr.Error())
+		return
+	}
+
+	directMessage := fmt.Sprintf(":alarm_clock: A Flash alert from **%s** has not been acknowledged after %s:\n> %s", entry.BackendName, window, entry.Headline)
+	if alertChannel, appErr := a.api.GetChannel(entry.ChannelID); appErr == nil {
+		directMessage += fmt.Sprintf("\nPosted in ~%s", alertChannel.Name)
+	}
+
+	if _, appErr := a.api.CreatePost(&model.Post{
+		UserId:    a.botID,
+		ChannelId: channel.Id,
+		Message:   directMessage,
+	}); appErr != nil {
+		a.api.LogError("Failed to send acknowledgment reminder", "userId", user.Id, "error", appErr.Error())
+	}
+}
+
+// formatAckWindow describes the acknowledgment window (e.g., "15 minutes")
+func formatAckWindow(window time.Duration) string {
+	minutes := int(window.Round(time.Minute) / time.Minute)
+	if minutes == 1 {
+		return "1 minute"
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
+
+// acknowledgeAlert handles the Acknowledge button on Flash alert posts. Any member of the
+// alert's channel may acknowledge it; later clicks report who acknowledged it first.
+func (p *Plugin) acknowledgeAlert(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request model.PostActionIntegrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.PostId == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	post, appErr := p.API.GetPost(request.PostId)
+	if appErr != nil {
+		http.Error(w, "Alert post not found", http.StatusNotFound)
+		return
+	}
+	if !p.API.HasPermissionToChannel(userID, post.ChannelId, model.PermissionReadChannel) {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	entry, acknowledged, err := p.ackTracker.Acknowledge(post.Id, userID)
+	if err != nil {
+		p.API.LogError("Failed to acknowledge alert", "postId", post.Id, "userId", userID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	var response model.PostActionIntegrationResponse
+	switch {
+	case entry == nil:
+		response.EphemeralText = "This alert is no longer awaiting acknowledgment."
+	case !acknowledged:
+		response.EphemeralText = fmt.Sprintf("This alert was already acknowledged by @%s.", p.username(entry.AcknowledgedBy))
+	default:
+		username := p.username(userID)
+		if err := p.poster.MarkAcknowledged(post.Id, username, entry.AcknowledgedAt); err != nil {
+			p.API.LogWarn("Failed to mark alert post as acknowledged", "postId", post.Id, "error", err.Error())
+		}
+
+		// Close the loop on the reminder posted in the thread
+		if entry.Reminded {
+			if _, appErr := p.API.CreatePost(&model.Post{
+				UserId:    p.botID,
+				ChannelId: post.ChannelId,
+				RootId:    post.Id,
+				Message:   fmt.Sprintf(":white_check_mark: Acknowledged by @%s.", username),
+			}); appErr != nil {
+				p.API.LogWarn("Failed to post acknowledgment reply", "postId", post.Id, "error", appErr.Error())
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("Failed to encode acknowledgment response", "error", err.Error())
+	}
+}
+
+// username returns the username of a user, falling back to the user ID if the lookup fails
+func (p *Plugin) username(userID string) string {
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		return userID
+	}
+	return user.Username
+}
@@ -0,0 +1,223 @@
+// Package ack tracks acknowledgment of alert posts and the deadlines of their acknowledgment SLA.
+package ack
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+)
+
+const (
+	// Retention is how long the acknowledgment state of a post is kept
+	Retention = 7 * 24 * time.Hour
+
+	// entryKeyPrefix prefixes the KV key holding the acknowledgment state of a post
+	entryKeyPrefix = "ack_post_"
+
+	// pendingKey holds the posts whose acknowledgment deadline has not been checked yet
+	pendingKey = "ack_pending"
+
+	// maxUpdateAttempts is how often a pending list update is retried when another node changed it concurrently
+	maxUpdateAttempts = 5
+)
+
+// Entry is the acknowledgment state of an alert post
+type Entry struct {
+	PostID         string    `json:"postId"`
+	ChannelID      string    `json:"channelId"`
+	BackendID      string    `json:"backendId"`
+	BackendName    string    `json:"backendName"`
+	AlertID        string    `json:"alertId"`
+	Headline       string    `json:"headline"`
+	PostedAt       time.Time `json:"postedAt"`
+	Deadline       time.Time `json:"deadline"`
+	OnCallUsername string    `json:"onCallUsername,omitempty"`
+	AcknowledgedBy string    `json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt time.Time `json:"acknowledgedAt,omitempty"`
+	Reminded       bool      `json:"reminded,omitempty"`
+}
+
+// Acknowledged reports whether someone has acknowledged the alert
+func (e Entry) Acknowledged() bool {
+	return e.AcknowledgedBy != ""
+}
+
+// pendingDeadline is a post awaiting its acknowledgment deadline
+type pendingDeadline struct {
+	PostID   string    `json:"postId"`
+	Deadline time.Time `json:"deadline"`
+}
+
+// Tracker stores the acknowledgment state of alert posts in the KV store.
+// Pending list updates use compare-and-set so multiple cluster nodes can track alerts concurrently.
+type Tracker struct {
+	api plugin.API
+	now func() time.Time
+}
+
+// NewTracker creates a new acknowledgment tracker
+func NewTracker(api plugin.API) *Tracker {
+	return &Tracker{
+		api: api,
+		now: time.Now,
+	}
+}
+
+// Track starts tracking an alert post until it is acknowledged or its deadline passes
+func (t *Tracker) Track(entry Entry) error {
+	if err := t.save(entry); err != nil {
+		return err
+	}
+
+	return t.updatePending(func(pending []pendingDeadline) []pendingDeadline {
+		return append(pending, pendingDeadline{PostID: entry.PostID, Deadline: entry.Deadline})
+	})
+}
+
+// Get returns the acknowledgment state of a post, or nil if the post is not tracked
+func (t *Tracker) Get(postID string) (*Entry, error) {
+	data, appErr := t.api.KVGet(entryKeyPrefix + postID)
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get acknowledgment state: %w", appErr)
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal acknowledgment state: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// Acknowledge records that a user acknowledged an alert post. Returns the updated entry and
+// whether this call acknowledged it (false if the post was already acknowledged).
+// Returns a nil entry if the post is not tracked.
+func (t *Tracker) Acknowledge(postID, userID string) (*Entry, bool, error) {
+	entry, err := t.Get(postID)
+	if err != nil || entry == nil {
+		return nil, false, err
+	}
+
+	if entry.Acknowledged() {
+		return entry, false, nil
+	}
+
+	entry.AcknowledgedBy = userID
+	entry.AcknowledgedAt = t.now()
+	if err := t.save(*entry); err != nil {
+		return nil, false, err
+	}
+
+	if err := t.updatePending(func(pending []pendingDeadline) []pendingDeadline {
+		remaining := make([]pendingDeadline, 0, len(pending))
+		for _, p := range pending {
+			if p.PostID != postID {
+				remaining = append(remaining, p)
+			}
+		}
+		return remaining
+	}); err != nil {
+		return nil, false, err
+	}
+
+	return entry, true, nil
+}
+
+// TakeOverdue removes the posts whose deadline has passed from the pending list and returns
+// those still unacknowledged, marked as reminded. Each overdue post is returned only once.
+func (t *Tracker) TakeOverdue() ([]Entry, error) {
+	now := t.now()
+
+	var due []pendingDeadline
+	if err := t.updatePending(func(pending []pendingDeadline) []pendingDeadline {
+		due = nil
+		remaining := make([]pendingDeadline, 0, len(pending))
+		for _, p := range pending {
+			if now.Before(p.Deadline) {
+				remaining = append(remaining, p)
+			} else {
+				due = append(due, p)
+			}
+		}
+		return remaining
+	}); err != nil {
+		return nil, err
+	}
+
+	var overdue []Entry
+	for _, p := range due {
+		entry, err := t.Get(p.PostID)
+		if err != nil {
+			return overdue, err
+		}
+		if entry == nil || entry.Acknowledged() {
+			continue
+		}
+
+		entry.Reminded = true
+		if err := t.save(*entry); err != nil {
+			return overdue, err
+		}
+		overdue = append(overdue, *entry)
+	}
+
+	return overdue, nil
+}
+
+// save stores the acknowledgment state of a post until the retention period ends
+func (t *Tracker) save(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal acknowledgment state: %w", err)
+	}
+
+	if _, appErr := t.api.KVSetWithOptions(entryKeyPrefix+entry.PostID, data, model.PluginKVSetOptions{
+		ExpireInSeconds: int64(Retention / time.Second),
+	}); appErr != nil {
+		return fmt.Errorf("failed to save acknowledgment state: %w", appErr)
+	}
+
+	return nil
+}
+
+// updatePending applies a change to the pending deadline list with compare-and-set
+func (t *Tracker) updatePending(update func([]pendingDeadline) []pendingDeadline) error {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		oldData, appErr := t.api.KVGet(pendingKey)
+		if appErr != nil {
+			return fmt.Errorf("failed to get pending acknowledgments: %w", appErr)
+		}
+
+		var pending []pendingDeadline
+		if oldData != nil {
+			if err := json.Unmarshal(oldData, &pending); err != nil {
+				return fmt.Errorf("failed to unmarshal pending acknowledgments: %w", err)
+			}
+		}
+
+		newData, err := json.Marshal(update(pending))
+		if err != nil {
+			return fmt.Errorf("failed to marshal pending acknowledgments: %w", err)
+		}
+
+		saved, appErr := t.api.KVSetWithOptions(pendingKey, newData, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to save pending acknowledgments: %w", appErr)
+		}
+		if saved {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to save pending acknowledgments: too many concurrent updates")
+}
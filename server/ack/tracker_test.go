@@ -0,0 +1,113 @@
+package ack
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newMemoryKVAPI returns a mock API backed by an in-memory KV store supporting atomic sets
+func newMemoryKVAPI() (*plugintest.API, map[string][]byte) {
+	store := make(map[string][]byte)
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return store[key], nil
+	})
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+			if options.Atomic && !bytes.Equal(store[key], options.OldValue) {
+				return false, nil
+			}
+			store[key] = value
+			return true, nil
+		})
+	return api, store
+}
+
+func newTestTracker(now *time.Time) *Tracker {
+	api, _ := newMemoryKVAPI()
+	tracker := NewTracker(api)
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+func TestTracker_Acknowledge(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(&now)
+
+	require.NoError(t, tracker.Track(Entry{PostID: "post-1", ChannelID: "channel-1", PostedAt: now, Deadline: now.Add(15 * time.Minute)}))
+
+	entry, err := tracker.Get("post-1")
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.False(t, entry.Acknowledged())
+
+	now = now.Add(5 * time.Minute)
+	entry, acknowledged, err := tracker.Acknowledge("post-1", "user-1")
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.True(t, acknowledged)
+	assert.Equal(t, "user-1", entry.AcknowledgedBy)
+	assert.Equal(t, now, entry.AcknowledgedAt)
+
+	// A second click reports the first acknowledgment
+	entry, acknowledged, err = tracker.Acknowledge("post-1", "user-2")
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.False(t, acknowledged)
+	assert.Equal(t, "user-1", entry.AcknowledgedBy)
+
+	// Acknowledged alerts are not reminded about
+	now = now.Add(time.Hour)
+	overdue, err := tracker.TakeOverdue()
+	require.NoError(t, err)
+	assert.Empty(t, overdue)
+
+	// Untracked posts
+	entry, acknowledged, err = tracker.Acknowledge("post-unknown", "user-1")
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+	assert.False(t, acknowledged)
+}
+
+func TestTracker_TakeOverdue(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(&now)
+
+	require.NoError(t, tracker.Track(Entry{PostID: "post-1", PostedAt: now, Deadline: now.Add(10 * time.Minute)}))
+	require.NoError(t, tracker.Track(Entry{PostID: "post-2", PostedAt: now, Deadline: now.Add(30 * time.Minute)}))
+
+	overdue, err := tracker.TakeOverdue()
+	require.NoError(t, err)
+	assert.Empty(t, overdue)
+
+	now = now.Add(10 * time.Minute)
+	overdue, err = tracker.TakeOverdue()
+	require.NoError(t, err)
+	require.Len(t, overdue, 1)
+	assert.Equal(t, "post-1", overdue[0].PostID)
+	assert.True(t, overdue[0].Reminded)
+
+	// Each overdue alert is reminded about only once
+	overdue, err = tracker.TakeOverdue()
+	require.NoError(t, err)
+	assert.Empty(t, overdue)
+
+	// Overdue alerts can still be acknowledged
+	entry, acknowledged, err := tracker.Acknowledge("post-1", "user-1")
+	require.NoError(t, err)
+	assert.True(t, acknowledged)
+	assert.True(t, entry.Reminded)
+
+	now = now.Add(time.Hour)
+	overdue, err = tracker.TakeOverdue()
+	require.NoError(t, err)
+	require.Len(t, overdue, 1)
+	assert.Equal(t, "post-2", overdue[0].PostID)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/ack"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
+)
+
+// mockMemoryKV backs the KV calls used by the acknowledgment tracker with an in-memory map
+func mockMemoryKV(api *plugintest.API) {
+	store := make(map[string][]byte)
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return store[key], nil
+	})
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+			if options.Atomic && !bytes.Equal(store[key], options.OldValue) {
+				return false, nil
+			}
+			store[key] = value
+			return true, nil
+		})
+}
+
+func TestFormatAckWindow(t *testing.T) {
+	assert.Equal(t, "1 minute", formatAckWindow(time.Minute))
+	assert.Equal(t, "15 minutes", formatAckWindow(15*time.Minute))
+}
+
+func TestAckReminder_Check(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	mockMemoryKV(api)
+
+	tracker := ack.NewTracker(api)
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, tracker.Track(ack.Entry{
+		PostID:         "post-1",
+		ChannelID:      "channel-1",
+		BackendName:    "Weather Watch",
+		Headline:       "Tornado warning",
+		PostedAt:       past,
+		Deadline:       past.Add(15 * time.Minute),
+		OnCallUsername: "duty.officer",
+	}))
+
+	var posts []*model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posts = append(posts, args.Get(0).(*model.Post))
+	}).Return(&model.Post{}, nil)
+	api.On("GetUserByUsername", "duty.officer").Return(&model.User{Id: "oncall-id"}, nil)
+	api.On("GetDirectChannel", "oncall-id", "bot-id").Return(&model.Channel{Id: "dm-id"}, nil)
+	api.On("GetChannel", "channel-1").Return(&model.Channel{Id: "channel-1", Name: "alerts"}, nil)
+
+	reminder := NewAckReminder(api, "bot-id", tracker)
+	reminder.check()
+
+	require.Len(t, posts, 2)
+	assert.Equal(t, "channel-1", posts[0].ChannelId)
+	assert.Equal(t, "post-1", posts[0].RootId)
+	assert.Equal(t, ":alarm_clock: This Flash alert has not been acknowledged after 15 minutes. @duty.officer please review.", posts[0].Message)
+	assert.Equal(t, "dm-id", posts[1].ChannelId)
+	assert.Contains(t, posts[1].Message, "A Flash alert from **Weather Watch** has not been acknowledged after 15 minutes")
+	assert.Contains(t, posts[1].Message, "> Tornado warning")
+	assert.Contains(t, posts[1].Message, "Posted in ~alerts")
+
+	// The reminder is only sent once
+	reminder.check()
+	assert.Len(t, posts, 2)
+}
+
+func TestAcknowledgeAlert(t *testing.T) {
+	api := &plugintest.API{}
+	mockMemoryKV(api)
+
+	p := newCommandTestPlugin(api)
+	p.botID = "bot-id"
+	p.ackTracker = ack.NewTracker(api)
+	p.poster = poster.New(api, "bot-id")
+
+	now := time.Now()
+	require.NoError(t, p.ackTracker.Track(ack.Entry{PostID: "post-1", ChannelID: "channel-1", PostedAt: now, Deadline: now.Add(15 * time.Minute)}))
+
+	alertPost := &model.Post{Id: "post-1", ChannelId: "channel-1"}
+	api.On("GetPost", "post-1").Return(alertPost, nil)
+	api.On("GetPost", "post-missing").Return(nil, model.NewAppError("GetPost", "not_found", nil, "", http.StatusNotFound))
+	api.On("HasPermissionToChannel", "user-1", "channel-1", model.PermissionReadChannel).Return(true)
+	api.On("HasPermissionToChannel", "user-2", "channel-1", model.PermissionReadChannel).Return(true)
+	api.On("HasPermissionToChannel", "outsider", "channel-1", model.PermissionReadChannel).Return(false)
+	api.On("GetUser", "user-1").Return(&model.User{Id: "user-1", Username: "jane"}, nil)
+	api.On("UpdatePost", mock.Anything).Return(alertPost, nil).Once()
+
+	acknowledge := func(userID, postID string) (*httptest.ResponseRecorder, model.PostActionIntegrationResponse) {
+		body, err := json.Marshal(model.PostActionIntegrationRequest{UserId: userID, PostId: postID})
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, "/actions/acknowledge", strings.NewReader(string(body)))
+		r.Header.Set("Mattermost-User-ID", userID)
+		w := httptest.NewRecorder()
+		p.ServeHTTP(nil, w, r)
+
+		var response model.PostActionIntegrationResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		}
+		return w, response
+	}
+
+	w, _ := acknowledge("outsider", "post-1")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w, _ = acknowledge("user-1", "post-missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w, response := acknowledge("user-1", "post-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, response.EphemeralText)
+
+	entry, err := p.ackTracker.Get("post-1")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", entry.AcknowledgedBy)
+
+	w, response = acknowledge("user-2", "post-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "This alert was already acknowledged by @jane.", response.EphemeralText)
+
+	api.AssertExpectations(t)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+)
+
+// alertExportColumns is the header row of the posted alerts CSV export
+var alertExportColumns = []string{"time", "type", "headline", "location", "channel", "post_link"}
+
+// exportBackendAlerts handles GET /api/v1/backends/{id}/alerts.csv?from=&to= and streams the
+// alerts the backend posted in the period to channels the user can read as CSV, oldest first.
+// from and to accept RFC 3339 times or dates (YYYY-MM-DD, to includes the whole day) and
+// default to the alert index retention period.
+func (p *Plugin) exportBackendAlerts(w http.ResponseWriter, r *http.Request) {
+	b := p.registry.Get(mux.Vars(r)["id"])
+	if b == nil {
+		http.Error(w, "Backend not found", http.StatusNotFound)
+		return
+	}
+
+	query := alertindex.Query{BackendID: b.GetID()}
+	var err error
+	if query.Since, err = parseExportTime(r.URL.Query().Get("from"), false); err != nil {
+		http.Error(w, "Invalid from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if query.Until, err = parseExportTime(r.URL.Query().Get("to"), true); err != nil {
+		http.Error(w, "Invalid to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !query.Since.IsZero() && !query.Until.IsZero() && query.Until.Before(query.Since) {
+		http.Error(w, "to must not be before from", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := p.alertIndex.Search(query)
+	if err != nil {
+		p.API.LogError("Failed to search alerts for export", "id", b.GetID(), "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Only alerts posted in channels the user can read are exported
+	entries = p.filterReadable(r.Header.Get("Mattermost-User-ID"), entries)
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="dataminr-alerts-`+b.GetID()+`.csv"`)
+
+	siteURL := p.siteURL()
+	channelNames := make(map[string]string)
+	writer := csv.NewWriter(w)
+	_ = writer.Write(alertExportColumns)
+
+	// The index returns the most recent alerts first
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		channelName, found := channelNames[entry.ChannelID]
+		if !found {
+			channelName = entry.ChannelID
+			if channel, appErr := p.API.GetChannel(entry.ChannelID); appErr == nil {
+				channelName = channel.Name
+			}
+			channelNames[entry.ChannelID] = channelName
+		}
+
+		if err := writer.Write([]string{
+			entry.PostedAt.UTC().Format(time.RFC3339),
+			escapeCSVFormula(entry.AlertType),
+			escapeCSVFormula(entry.Headline),
+			escapeCSVFormula(entry.Location),
+			escapeCSVFormula(channelName),
+			siteURL + "/_redirect/pl/" + entry.PostID,
+		}); err != nil {
+			p.API.LogError("Failed to write alert export", "id", b.GetID(), "error", err.Error())
+			return
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		p.API.LogError("Failed to write alert export", "id", b.GetID(), "error", err.Error())
+	}
+}
+
+// escapeCSVFormula prefixes a cell starting like a formula with a single quote, so alert
+// content can't run as a formula when the export is opened in a spreadsheet
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// parseExportTime parses an export period bound given as an RFC 3339 time or a date.
+// A date as the end of the period includes the whole day. An empty value is a zero time.
+func parseExportTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("'%s' is not an RFC 3339 time or a YYYY-MM-DD date", value)
+	}
+	if endOfDay {
+		return day.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return day, nil
+}
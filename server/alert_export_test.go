@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestExportBackendAlerts(t *testing.T) {
+	api := &plugintest.API{}
+	mockMemoryKV(api)
+	siteURL := "https://chat.example.com/"
+	api.On("GetConfig").Return(&model.Config{ServiceSettings: model.ServiceSettings{SiteURL: &siteURL}})
+	api.On("GetChannel", "channel-1").Return(&model.Channel{Id: "channel-1", Name: "alerts"}, nil)
+	api.On("HasPermissionToChannel", "operator-id", "channel-1", model.PermissionReadChannel).Return(true)
+	api.On("HasPermissionToChannel", "operator-id", "private-channel", model.PermissionReadChannel).Return(false)
+
+	p := newCommandTestPlugin(api)
+	p.registry = backend.NewRegistry()
+	require.NoError(t, p.registry.Register(&fakeBackend{id: "backend-1", name: "Weather Watch"}))
+	p.alertIndex = alertindex.New(api)
+
+	posted := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+	for _, entry := range []alertindex.Entry{
+		{BackendID: "backend-1", AlertType: "Flash", Headline: "Flood warning, downtown", Location: "Springfield", ChannelID: "channel-1", PostID: "post-1", PostedAt: posted},
+		{BackendID: "backend-1", AlertType: "Alert", Headline: "Road closed", ChannelID: "channel-1", PostID: "post-2", PostedAt: posted.Add(time.Minute)},
+		{BackendID: "backend-1", AlertType: "Alert", Headline: "Private channel", ChannelID: "private-channel", PostID: "post-4", PostedAt: posted.Add(time.Minute)},
+		{BackendID: "backend-2", AlertType: "Flash", Headline: "Other backend", ChannelID: "channel-2", PostID: "post-3", PostedAt: posted},
+	} {
+		require.NoError(t, p.alertIndex.Add(entry))
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/backends/{id}/alerts.csv", p.exportBackendAlerts).Methods(http.MethodGet)
+
+	export := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		r.Header.Set("Mattermost-User-ID", "operator-id")
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("all alerts of the backend in channels the user can read", func(t *testing.T) {
+		w := export("/api/v1/backends/backend-1/alerts.csv")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, alertExportColumns, records[0])
+		assert.Equal(t, []string{
+			posted.Format(time.RFC3339), "Flash", "Flood warning, downtown", "Springfield", "alerts",
+			"https://chat.example.com/_redirect/pl/post-1",
+		}, records[1])
+		assert.Equal(t, "Road closed", records[2][2])
+	})
+
+	t.Run("period", func(t *testing.T) {
+		w := export("/api/v1/backends/backend-1/alerts.csv?from=" + posted.Add(30*time.Second).Format(time.RFC3339))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "Road closed", records[1][2])
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, export("/api/v1/backends/unknown/alerts.csv").Code)
+		assert.Equal(t, http.StatusBadRequest, export("/api/v1/backends/backend-1/alerts.csv?from=yesterday").Code)
+		assert.Equal(t, http.StatusBadRequest, export("/api/v1/backends/backend-1/alerts.csv?from=2026-10-16&to=2026-10-15").Code)
+	})
+}
+
+func TestEscapeCSVFormula(t *testing.T) {
+	for value, expected := range map[string]string{
+		"":                       "",
+		"Flood warning":          "Flood warning",
+		"=HYPERLINK(\"x\")":      "'=HYPERLINK(\"x\")",
+		"+1 casualties":          "'+1 casualties",
+		"-5 degrees":             "'-5 degrees",
+		"@SUM(A1)":               "'@SUM(A1)",
+		"\t=1":                   "'\t=1",
+		"Road closed - downtown": "Road closed - downtown",
+	} {
+		assert.Equal(t, expected, escapeCSVFormula(value), value)
+	}
+}
+
+func TestParseExportTime(t *testing.T) {
+	parsed, err := parseExportTime("", false)
+	require.NoError(t, err)
+	assert.True(t, parsed.IsZero())
+
+	parsed, err = parseExportTime("2026-10-16T09:30:00Z", false)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC), parsed)
+
+	parsed, err = parseExportTime("2026-10-16", false)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), parsed)
+
+	parsed, err = parseExportTime("2026-10-16", true)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 16, 23, 59, 59, 999999999, time.UTC), parsed)
+
+	_, err = parseExportTime("16/10/2026", false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
+)
+
+const (
+	// linkActionPath is the plugin HTTP path called by the Copy alert link button on alert posts
+	linkActionPath = "/actions/copy-link"
+
+	// alertLinkPathPrefix prefixes the plugin HTTP path of alert deep links:
+	// /alert/{backendID}/{alertID}?channel={channelID}&sig={signature}
+	alertLinkPathPrefix = "/alert/"
+
+	// alertLinkSigningKey is the KV key of the secret signing alert deep links
+	alertLinkSigningKey = "alert_link_signing_key"
+
+	// alertLinkSignatureSize is the number of HMAC bytes kept in a deep link signature
+	alertLinkSignatureSize = 16
+)
+
+// linkActionURL is the integration URL of the Copy alert link button
+func linkActionURL() string {
+	return "/plugins/" + pluginID + linkActionPath
+}
+
+// copyAlertLink handles the Copy alert link button on alert posts. It replies with an
+// ephemeral message holding a signed deep link to the post, which resolves for as long as the
+// alert post store keeps the alert's post (alertposts.TTL), and the alert's Dataminr link.
+func (p *Plugin) copyAlertLink(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request model.PostActionIntegrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.PostId == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	post, appErr := p.API.GetPost(request.PostId)
+	if appErr != nil {
+		http.Error(w, "Alert post not found", http.StatusNotFound)
+		return
+	}
+	if !p.API.HasPermissionToChannel(userID, post.ChannelId, model.PermissionReadChannel) {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	backendID, _ := post.GetProp(poster.BackendIDProp).(string)
+	alertID, _ := post.GetProp(poster.AlertIDProp).(string)
+	if backendID == "" || alertID == "" {
+		http.Error(w, "Not an alert post", http.StatusBadRequest)
+		return
+	}
+
+	link, err := p.createAlertLink(backendID, alertID, post.ChannelId)
+	if err != nil {
+		p.API.LogError("Failed to create alert link", "postId", post.Id, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	text := fmt.Sprintf("Link to this alert post:\n```\n%s\n```", link)
+	if alertURL, _ := request.Context["alert_url"].(string); alertURL != "" {
+		text += fmt.Sprintf("\nLink to the alert in Dataminr:\n```\n%s\n```", alertURL)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(model.PostActionIntegrationResponse{EphemeralText: text}); err != nil {
+		p.API.LogError("Failed to encode alert link response", "error", err.Error())
+	}
+}
+
+// createAlertLink returns the signed deep link to the post of an alert in a channel, resolved
+// through the alert post store
+func (p *Plugin) createAlertLink(backendID, alertID, channelID string) (string, error) {
+	key, err := p.alertLinkKey()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/plugins/%s%s%s/%s?channel=%s&sig=%s",
+		p.siteURL(),
+		pluginID,
+		alertLinkPathPrefix,
+		url.PathEscape(backendID),
+		url.PathEscape(alertID),
+		url.QueryEscape(channelID),
+		signAlertLink(key, backendID, alertID, channelID),
+	), nil
+}
+
+// resolveAlertLink redirects a signed alert deep link to the permalink of the alert post.
+// Mattermost checks the user's access to the post when following the permalink.
+func (p *Plugin) resolveAlertLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	backendID, alertID, found := strings.Cut(strings.TrimPrefix(r.URL.Path, alertLinkPathPrefix), "/")
+	if !found || backendID == "" || alertID == "" {
+		http.Error(w, "Alert not found", http.StatusNotFound)
+		return
+	}
+
+	key, err := p.alertLinkKey()
+	if err != nil {
+		p.API.LogError("Failed to get alert link signing key", "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Unsigned links are reported as missing so alert IDs cannot be probed
+	channelID := r.URL.Query().Get("channel")
+	if !hmac.Equal([]byte(r.URL.Query().Get("sig")), []byte(signAlertLink(key, backendID, alertID, channelID))) {
+		http.Error(w, "Alert not found", http.StatusNotFound)
+		return
+	}
+
+	postID, err := p.GetPostForAlert(backendID, alertID, channelID)
+	if err != nil {
+		p.API.LogError("Failed to get alert link", "backendId", backendID, "alertId", alertID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if postID == "" {
+		http.Error(w, "Alert not found", http.StatusNotFound)
+		return
+	}
+
+	http.Redirect(w, r, p.siteURL()+"/_redirect/pl/"+postID, http.StatusFound)
+}
+
+// alertLinkKey returns the secret signing alert deep links, generating it on first use
+func (p *Plugin) alertLinkKey() ([]byte, error) {
+	key, appErr := p.API.KVGet(alertLinkSigningKey)
+	if appErr != nil {
+		return nil, errors.Wrap(appErr, "failed to get alert link signing key")
+	}
+	if key != nil {
+		return key, nil
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Wrap(err, "failed to generate alert link signing key")
+	}
+
+	saved, appErr := p.API.KVSetWithOptions(alertLinkSigningKey, key, model.PluginKVSetOptions{Atomic: true, OldValue: nil})
+	if appErr != nil {
+		return nil, errors.Wrap(appErr, "failed to save alert link signing key")
+	}
+	if saved {
+		return key, nil
+	}
+
+	// Another node generated the key first
+	key, appErr = p.API.KVGet(alertLinkSigningKey)
+	if appErr != nil || key == nil {
+		return nil, errors.New("failed to get alert link signing key")
+	}
+	return key, nil
+}
+
+// signAlertLink returns the signature of the deep link to an alert's post in a channel
+func signAlertLink(key []byte, backendID, alertID, channelID string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(backendID + "/" + alertID + "/" + channelID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:alertLinkSignatureSize])
+}
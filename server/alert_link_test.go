@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertposts"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
+)
+
+func TestAlertLink(t *testing.T) {
+	api := &plugintest.API{}
+	mockMemoryKV(api)
+	siteURL := "https://chat.example.com"
+	api.On("GetConfig").Return(&model.Config{ServiceSettings: model.ServiceSettings{SiteURL: &siteURL}})
+
+	p := newCommandTestPlugin(api)
+	p.alertPosts = alertposts.New(api)
+
+	alertPost := &model.Post{Id: "post-1", ChannelId: "channel-1"}
+	alertPost.AddProp(poster.BackendIDProp, "backend-1")
+	alertPost.AddProp(poster.AlertIDProp, "alert-1")
+	api.On("GetPost", "post-1").Return(alertPost, nil)
+	api.On("GetPost", "post-plain").Return(&model.Post{Id: "post-plain", ChannelId: "channel-1"}, nil)
+	api.On("HasPermissionToChannel", "user-1", "channel-1", model.PermissionReadChannel).Return(true)
+	api.On("HasPermissionToChannel", "outsider", "channel-1", model.PermissionReadChannel).Return(false)
+
+	copyLink := func(userID, postID string) (*httptest.ResponseRecorder, model.PostActionIntegrationResponse) {
+		body, err := json.Marshal(model.PostActionIntegrationRequest{
+			UserId:  userID,
+			PostId:  postID,
+			Context: map[string]any{"alert_url": "https://app.dataminr.com/alerts/alert-1"},
+		})
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, linkActionPath, strings.NewReader(string(body)))
+		r.Header.Set("Mattermost-User-ID", userID)
+		w := httptest.NewRecorder()
+		p.ServeHTTP(nil, w, r)
+
+		var response model.PostActionIntegrationResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		}
+		return w, response
+	}
+
+	resolve := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set("Mattermost-User-ID", "user-1")
+		w := httptest.NewRecorder()
+		p.ServeHTTP(nil, w, r)
+		return w
+	}
+
+	w, _ := copyLink("outsider", "post-1")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w, _ = copyLink("user-1", "post-plain")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	_, err := p.alertPosts.Put(alertposts.Record{BackendID: "backend-1", AlertID: "alert-1", PostID: "post-1", ChannelID: "channel-1"})
+	require.NoError(t, err)
+
+	w, response := copyLink("user-1", "post-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, response.EphemeralText, "https://chat.example.com/plugins/"+pluginID+"/alert/backend-1/alert-1?channel=channel-1&sig=")
+	assert.Contains(t, response.EphemeralText, "https://app.dataminr.com/alerts/alert-1")
+
+	// The deep link redirects to the alert post
+	start := strings.Index(response.EphemeralText, "https://chat.example.com/")
+	link, err := url.Parse(strings.Fields(response.EphemeralText[start:])[0])
+	require.NoError(t, err)
+	path := strings.TrimPrefix(link.Path, "/plugins/"+pluginID)
+
+	w = resolve(path + "?" + link.RawQuery)
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://chat.example.com/_redirect/pl/post-1", w.Header().Get("Location"))
+
+	// Links without a valid signature are not resolved
+	w = resolve(path)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = resolve(path + "?sig=invalid")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Signed links to alerts without a recorded post are not found
+	key, err := p.alertLinkKey()
+	require.NoError(t, err)
+	w = resolve("/alert/backend-1/alert-2?channel=channel-1&sig=" + signAlertLink(key, "backend-1", "alert-2", "channel-1"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Links resolve to the alert's post in their channel
+	_, err = p.alertPosts.Put(alertposts.Record{BackendID: "backend-1", AlertID: "alert-1", PostID: "post-2", ChannelID: "channel-2"})
+	require.NoError(t, err)
+	link2, err := p.createAlertLink("backend-1", "alert-1", "channel-2")
+	require.NoError(t, err)
+	w = resolve(strings.TrimPrefix(link2, "https://chat.example.com/plugins/"+pluginID))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://chat.example.com/_redirect/pl/post-2", w.Header().Get("Location"))
+
+	// The channel is covered by the signature
+	w = resolve(path + "?channel=channel-2&sig=" + link.Query().Get("sig"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAlertLinkKey(t *testing.T) {
+	api := &plugintest.API{}
+	mockMemoryKV(api)
+	p := newCommandTestPlugin(api)
+
+	key, err := p.alertLinkKey()
+	require.NoError(t, err)
+	assert.Len(t, key, 32)
+
+	again, err := p.alertLinkKey()
+	require.NoError(t, err)
+	assert.Equal(t, key, again, "the key is generated once")
+
+	assert.NotEqual(t, signAlertLink(key, "backend-1", "alert-1", "channel-1"), signAlertLink(key, "backend-1", "alert-2", "channel-1"))
+	assert.NotEqual(t, signAlertLink(key, "backend-1", "alert-1", "channel-1"), signAlertLink(key, "backend-1", "alert-1", "channel-2"))
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/pkg/errors"
+)
+
+// GetPostForAlert returns the ID of the post an alert of a backend was posted as in a channel,
+// or an empty string if it is unknown or was posted more than alertposts.TTL ago. An empty
+// channel ID returns the first post of an alert posted to several channels.
+func (p *Plugin) GetPostForAlert(backendID, alertID, channelID string) (string, error) {
+	if p.alertPosts == nil {
+		return "", nil
+	}
+
+	record, err := p.alertPosts.Get(backendID, alertID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get alert post")
+	}
+	if record == nil {
+		return "", nil
+	}
+	if channelID == "" {
+		return record.PostID, nil
+	}
+	return record.PostIn(channelID), nil
+}
+
+// pruneAlertPosts deletes the post mapping of every alert of a removed backend
+func (p *Plugin) pruneAlertPosts(backendID string) {
+	if p.alertPosts == nil {
+		return
+	}
+
+	pruned, err := p.alertPosts.Prune(backendID)
+	if err != nil {
+		p.API.LogWarn("Failed to prune alert posts", "id", backendID, "error", err.Error())
+		return
+	}
+	if pruned > 0 {
+		p.API.LogInfo("Pruned alert posts of removed backend", "id", backendID, "count", pruned)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertposts"
+)
+
+func TestGetPostForAlert(t *testing.T) {
+	t.Run("returns the recorded post", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("KVGet", mock.Anything).Return([]byte(`{"backendId":"backend-1","alertId":"alert-1","postId":"post-1","channelId":"channel-1"}`), nil).Once()
+
+		p := &Plugin{alertPosts: alertposts.New(api)}
+		p.SetAPI(api)
+
+		postID, err := p.GetPostForAlert("backend-1", "alert-1", "")
+		require.NoError(t, err)
+		assert.Equal(t, "post-1", postID)
+	})
+
+	t.Run("returns the post in a channel", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("KVGet", mock.Anything).Return([]byte(`{"backendId":"backend-1","alertId":"alert-1","postId":"post-1","channelId":"channel-1","copies":{"channel-2":"post-2"}}`), nil).Times(3)
+
+		p := &Plugin{alertPosts: alertposts.New(api)}
+		p.SetAPI(api)
+
+		for channelID, expected := range map[string]string{"channel-1": "post-1", "channel-2": "post-2", "channel-3": ""} {
+			postID, err := p.GetPostForAlert("backend-1", "alert-1", channelID)
+			require.NoError(t, err)
+			assert.Equal(t, expected, postID, channelID)
+		}
+	})
+
+	t.Run("unknown alert", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("KVGet", mock.Anything).Return(nil, nil).Once()
+
+		p := &Plugin{alertPosts: alertposts.New(api)}
+		p.SetAPI(api)
+
+		postID, err := p.GetPostForAlert("backend-1", "alert-1", "")
+		require.NoError(t, err)
+		assert.Empty(t, postID)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("KVGet", mock.Anything).Return(nil, model.NewAppError("KVGet", "app.error", nil, "", 500)).Once()
+
+		p := &Plugin{alertPosts: alertposts.New(api)}
+		p.SetAPI(api)
+
+		_, err := p.GetPostForAlert("backend-1", "alert-1", "")
+		require.Error(t, err)
+	})
+}
+
+func TestPruneAlertPosts(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	keys := []string{"alert_post_backend-1_abc", "alert_post_backend-2_def", "alert_index_2026101609"}
+	api.On("KVList", 0, mock.Anything).Return(keys, nil).Once()
+	api.On("KVDelete", "alert_post_backend-1_abc").Return(nil).Once()
+	api.On("LogInfo", "Pruned alert posts of removed backend", "id", "backend-1", "count", 1).Once()
+
+	p := &Plugin{alertPosts: alertposts.New(api)}
+	p.SetAPI(api)
+
+	p.pruneAlertPosts("backend-1")
+}
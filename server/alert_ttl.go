@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+)
+
+const (
+	// alertTTLJobID is the cluster job ID for expiring alert posts past their TTL
+	alertTTLJobID = "dataminr_alert_ttl"
+
+	// alertTTLCheckInterval is how often alert posts past their TTL are expired
+	alertTTLCheckInterval = 5 * time.Minute
+
+	// alertTTLCheckpointKey holds when the alert TTL job last ran, so each run only handles
+	// the alerts that expired since
+	alertTTLCheckpointKey = "alert_ttl_checkpoint"
+)
+
+// alertTTLTypes are the alert types an alert TTL can be configured for
+var alertTTLTypes = []string{"Flash", "Urgent", "Alert"}
+
+// AlertExpiry strikes through or deletes alert posts once their backend's TTL for the alert
+// type has passed. Posted alerts are found in the alert index, so alerts posted more than
+// its retention period ago are not expired. It runs as a cluster job so each post is
+// expired by a single node.
+type AlertExpiry struct {
+	api    plugin.API
+	index  alertindex.Store
+	config func() *configuration
+	expire func(postID, action string, at time.Time) error
+	job    *cluster.Job
+	now    func() time.Time
+}
+
+// NewAlertExpiry creates an alert TTL job reading the active configuration on each run
+func NewAlertExpiry(api plugin.API, index alertindex.Store, config func() *configuration, expire func(postID, action string, at time.Time) error) *AlertExpiry {
+	return &AlertExpiry{
+		api:    api,
+		index:  index,
+		config: config,
+		expire: expire,
+		now:    time.Now,
+	}
+}
+
+// Start schedules the periodic cluster-aware expiry of alert posts
+func (e *AlertExpiry) Start() error {
+	job, err := cluster.Schedule(e.api, alertTTLJobID, cluster.MakeWaitForInterval(alertTTLCheckInterval), e.run)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule alert TTL job")
+	}
+
+	e.job = job
+	return nil
+}
+
+// Stop cancels the alert TTL job
+func (e *AlertExpiry) Stop() {
+	if e.job == nil {
+		return
+	}
+
+	if err := e.job.Close(); err != nil {
+		e.api.LogWarn("Failed to close alert TTL job", "error", err.Error())
+	}
+	e.job = nil
+}
+
+// run expires the alert posts whose TTL passed since the previous run. Alerts are looked up
+// per backend and alert type by the time they were posted, so each run reads only the index
+// buckets holding alerts that just expired.
+func (e *AlertExpiry) run() {
+	now := e.now()
+	since := e.loadCheckpoint(now)
+
+	expired := 0
+	for _, cfg := range e.config().backends() {
+		if cfg.AlertTTL == nil {
+			continue
+		}
+
+		action := cfg.AlertTTL.ActionOrDefault()
+		for _, alertType := range alertTTLTypes {
+			ttl := cfg.AlertTTL.TTL(alertType)
+			if ttl <= 0 {
+				continue
+			}
+
+			entries, err := e.index.Search(alertindex.Query{
+				BackendID: cfg.ID,
+				AlertType: alertType,
+				Since:     since.Add(-ttl),
+				Until:     now.Add(-ttl),
+			})
+			if err != nil {
+				// Keep the checkpoint so the next run retries these alerts
+				e.api.LogError("Failed to find expired alerts", "backendId", cfg.ID, "alertType", alertType, "error", err.Error())
+				return
+			}
+
+			for _, entry := range entries {
+				if err := e.expire(entry.PostID, action, now); err != nil {
+					e.api.LogWarn("Failed to expire alert post", "backendId", cfg.ID, "postId", entry.PostID, "error", err.Error())
+					continue
+				}
+				expired++
+			}
+		}
+	}
+
+	if expired > 0 {
+		e.api.LogInfo("Expired alert posts past their TTL", "count", expired)
+	}
+	e.saveCheckpoint(now)
+}
+
+// loadCheckpoint returns when the job last ran, limited to the alert index retention period.
+// Without a checkpoint every indexed alert past its TTL is expired.
+func (e *AlertExpiry) loadCheckpoint(now time.Time) time.Time {
+	oldest := now.Add(-alertindex.Retention)
+
+	data, appErr := e.api.KVGet(alertTTLCheckpointKey)
+	if appErr != nil {
+		e.api.LogWarn("Failed to get alert TTL checkpoint", "error", appErr.Error())
+		return oldest
+	}
+	if data == nil {
+		return oldest
+	}
+
+	var checkpoint time.Time
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		e.api.LogWarn("Failed to unmarshal alert TTL checkpoint", "error", err.Error())
+		return oldest
+	}
+	if checkpoint.Before(oldest) {
+		return oldest
+	}
+	return checkpoint
+}
+
+// saveCheckpoint stores when the job last ran
+func (e *AlertExpiry) saveCheckpoint(t time.Time) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		e.api.LogError("Failed to marshal alert TTL checkpoint", "error", err.Error())
+		return
+	}
+	if appErr := e.api.KVSet(alertTTLCheckpointKey, data); appErr != nil {
+		e.api.LogError("Failed to save alert TTL checkpoint", "error", appErr.Error())
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// newAlertExpiryTestJob creates an alert TTL job over a KV store holding the given index entries,
+// and returns it with the KV store and the expired post IDs by action
+func newAlertExpiryTestJob(t *testing.T, config *configuration, entries []alertindex.Entry) (*AlertExpiry, map[string][]byte, map[string][]string) {
+	kv := make(map[string][]byte)
+	buckets := make(map[string][]alertindex.Entry)
+	for _, entry := range entries {
+		key := "alert_index_" + entry.PostedAt.UTC().Format("2006010215")
+		buckets[key] = append(buckets[key], entry)
+	}
+	for key, bucket := range buckets {
+		data, err := json.Marshal(bucket)
+		require.NoError(t, err)
+		kv[key] = data
+	}
+
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return kv[key], nil
+	})
+	api.On("KVSet", mock.Anything, mock.Anything).Return(func(key string, value []byte) *model.AppError {
+		kv[key] = value
+		return nil
+	})
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	expired := make(map[string][]string)
+	expiry := NewAlertExpiry(api, alertindex.New(api), func() *configuration { return config }, func(postID, action string, at time.Time) error {
+		expired[action] = append(expired[action], postID)
+		return nil
+	})
+	return expiry, kv, expired
+}
+
+func TestAlertExpiry_run(t *testing.T) {
+	now := time.Now()
+	config := &configuration{Backends: []backend.Config{
+		{ID: "weather", AlertTTL: &backend.AlertTTLSettings{AlertHours: 6, UrgentHours: 24}},
+		{ID: "security", AlertTTL: &backend.AlertTTLSettings{Action: backend.AlertTTLActionDelete, FlashHours: 2}},
+		{ID: "kept"},
+	}}
+	entries := []alertindex.Entry{
+		{BackendID: "weather", AlertType: "Alert", PostID: "advisory-old", PostedAt: now.Add(-7 * time.Hour)},
+		{BackendID: "weather", AlertType: "Alert", PostID: "advisory-new", PostedAt: now.Add(-time.Hour)},
+		{BackendID: "weather", AlertType: "Urgent", PostID: "urgent-recent", PostedAt: now.Add(-7 * time.Hour)},
+		{BackendID: "weather", AlertType: "Flash", PostID: "flash-no-ttl", PostedAt: now.Add(-48 * time.Hour)},
+		{BackendID: "security", AlertType: "Flash", PostID: "flash-old", PostedAt: now.Add(-3 * time.Hour)},
+		{BackendID: "kept", AlertType: "Alert", PostID: "kept-old", PostedAt: now.Add(-72 * time.Hour)},
+	}
+
+	expiry, kv, expired := newAlertExpiryTestJob(t, config, entries)
+	expiry.run()
+
+	assert.Equal(t, []string{"advisory-old"}, expired[backend.AlertTTLActionStrikethrough])
+	assert.Equal(t, []string{"flash-old"}, expired[backend.AlertTTLActionDelete])
+
+	// The checkpoint limits the next run to alerts that expired since
+	require.Contains(t, kv, alertTTLCheckpointKey)
+	var checkpoint time.Time
+	require.NoError(t, json.Unmarshal(kv[alertTTLCheckpointKey], &checkpoint))
+	assert.WithinDuration(t, now, checkpoint, time.Minute)
+}
+
+func TestAlertExpiry_runFromCheckpoint(t *testing.T) {
+	now := time.Now()
+	config := &configuration{Backends: []backend.Config{
+		{ID: "weather", AlertTTL: &backend.AlertTTLSettings{AlertHours: 6}},
+	}}
+	entries := []alertindex.Entry{
+		{BackendID: "weather", AlertType: "Alert", PostID: "expired-earlier", PostedAt: now.Add(-8 * time.Hour)},
+		{BackendID: "weather", AlertType: "Alert", PostID: "expired-since", PostedAt: now.Add(-6*time.Hour - 10*time.Minute)},
+	}
+
+	expiry, kv, expired := newAlertExpiryTestJob(t, config, entries)
+	data, err := json.Marshal(now.Add(-30 * time.Minute))
+	require.NoError(t, err)
+	kv[alertTTLCheckpointKey] = data
+
+	expiry.run()
+
+	assert.Equal(t, []string{"expired-since"}, expired[backend.AlertTTLActionStrikethrough])
+}
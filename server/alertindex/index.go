@@ -0,0 +1,287 @@
+// Package alertindex keeps a searchable record of posted alerts in the plugin KV store or,
+// for large deployments, in the Mattermost database.
+package alertindex
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// Retention is how long posted alerts remain searchable
+	Retention = 7 * 24 * time.Hour
+
+	// bucketDuration is the time span covered by a single KV bucket
+	bucketDuration = time.Hour
+
+	// bucketKeyPrefix prefixes the KV key of each hourly bucket
+	bucketKeyPrefix = "alert_index_"
+
+	// fingerprintKeyPrefix prefixes the KV key mapping the content fingerprint of an alert
+	// posted to a channel to its latest entry
+	fingerprintKeyPrefix = "alert_fingerprint_"
+
+	// maxUpdateAttempts is how often a bucket update is retried when another node changed it concurrently
+	maxUpdateAttempts = 5
+)
+
+// Entry is the indexed record of a posted alert.
+type Entry struct {
+	AlertID     string    `json:"alertId"`
+	BackendID   string    `json:"backendId"`
+	BackendName string    `json:"backendName"`
+	AlertType   string    `json:"alertType"`
+	Headline    string    `json:"headline"`
+	Topics      []string  `json:"topics,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	EventTime   time.Time `json:"eventTime"`
+	ChannelID   string    `json:"channelId"`
+	PostID      string    `json:"postId"`
+	PostedAt    time.Time `json:"postedAt"`
+
+	// Fingerprint is the hash of the alert's content, shared by alerts reissued under a new ID
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// NewEntry creates the index entry for an alert posted as the given post
+func NewEntry(alert backend.Alert, post *model.Post, postedAt time.Time) Entry {
+	location := ""
+	if alert.Location != nil {
+		location = alert.Location.Address
+	}
+
+	return Entry{
+		AlertID:     alert.AlertID,
+		BackendID:   alert.BackendID,
+		BackendName: alert.BackendName,
+		AlertType:   alert.AlertType,
+		Headline:    alert.Headline,
+		Topics:      alert.Topics,
+		Location:    location,
+		EventTime:   alert.EventTime,
+		ChannelID:   post.ChannelId,
+		PostID:      post.Id,
+		PostedAt:    postedAt,
+		Fingerprint: alert.Fingerprint(),
+	}
+}
+
+// Query filters index entries. Empty fields match every entry.
+type Query struct {
+	// Keywords must all appear (case-insensitive) in the headline or a topic
+	Keywords []string
+
+	// AlertType restricts results to an alert type (e.g., "Flash")
+	AlertType string
+
+	// BackendID restricts results to the alerts of a backend
+	BackendID string
+
+	// Since and Until bound the time the alert was posted
+	Since time.Time
+	Until time.Time
+
+	// Limit caps the number of results (0 means no limit)
+	Limit int
+}
+
+// Matches reports whether an entry satisfies the query filters
+func (q Query) Matches(entry Entry) bool {
+	if q.AlertType != "" && !strings.EqualFold(q.AlertType, entry.AlertType) {
+		return false
+	}
+	if q.BackendID != "" && q.BackendID != entry.BackendID {
+		return false
+	}
+	if !q.Since.IsZero() && entry.PostedAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && entry.PostedAt.After(q.Until) {
+		return false
+	}
+
+	text := strings.ToLower(entry.Headline + "\n" + strings.Join(entry.Topics, "\n"))
+	for _, keyword := range q.Keywords {
+		if !strings.Contains(text, strings.ToLower(keyword)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Store records posted alerts and searches them. Index keeps them in the KV store and
+// SQLIndex in the Mattermost database; both behave the same.
+type Store interface {
+	// Add records a posted alert
+	Add(entry Entry) error
+
+	// FindByFingerprint returns the latest entry posted to a channel with a content
+	// fingerprint within the retention period, or nil if there is none
+	FindByFingerprint(channelID, fingerprint string) (*Entry, error)
+
+	// Search returns the entries matching the query, most recently posted first.
+	// The search is limited to the retention period.
+	Search(query Query) ([]Entry, error)
+}
+
+// Index stores posted alerts in hourly KV buckets that expire after the retention period.
+// Bucket updates use compare-and-set so multiple cluster nodes can record alerts concurrently.
+type Index struct {
+	api plugin.API
+	now func() time.Time
+}
+
+// New creates a new alert index
+func New(api plugin.API) *Index {
+	return &Index{
+		api: api,
+		now: time.Now,
+	}
+}
+
+// Add records a posted alert in the bucket for its post time
+func (i *Index) Add(entry Entry) error {
+	key := bucketKey(entry.PostedAt)
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		oldData, appErr := i.api.KVGet(key)
+		if appErr != nil {
+			return fmt.Errorf("failed to get alert index bucket: %w", appErr)
+		}
+
+		entries, err := decodeBucket(oldData)
+		if err != nil {
+			return err
+		}
+
+		newData, err := json.Marshal(append(entries, entry))
+		if err != nil {
+			return fmt.Errorf("failed to marshal alert index bucket: %w", err)
+		}
+
+		saved, appErr := i.api.KVSetWithOptions(key, newData, model.PluginKVSetOptions{
+			Atomic:          true,
+			OldValue:        oldData,
+			ExpireInSeconds: int64((Retention + bucketDuration) / time.Second),
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to save alert index bucket: %w", appErr)
+		}
+		if saved {
+			return i.saveFingerprint(entry)
+		}
+	}
+
+	return fmt.Errorf("failed to save alert index bucket: too many concurrent updates")
+}
+
+// saveFingerprint maps the content fingerprint of an entry in its channel to the entry
+func (i *Index) saveFingerprint(entry Entry) error {
+	if entry.Fingerprint == "" || entry.ChannelID == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert fingerprint: %w", err)
+	}
+
+	if _, appErr := i.api.KVSetWithOptions(fingerprintKey(entry.ChannelID, entry.Fingerprint), data, model.PluginKVSetOptions{
+		ExpireInSeconds: int64(Retention / time.Second),
+	}); appErr != nil {
+		return fmt.Errorf("failed to save alert fingerprint: %w", appErr)
+	}
+	return nil
+}
+
+// FindByFingerprint returns the latest entry posted to a channel with a content fingerprint
+// within the retention period, or nil if there is none
+func (i *Index) FindByFingerprint(channelID, fingerprint string) (*Entry, error) {
+	if fingerprint == "" {
+		return nil, nil
+	}
+
+	data, appErr := i.api.KVGet(fingerprintKey(channelID, fingerprint))
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get alert fingerprint: %w", appErr)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert fingerprint: %w", err)
+	}
+	return &entry, nil
+}
+
+// Search returns the entries matching the query, most recently posted first.
+// The search is limited to the retention period.
+func (i *Index) Search(query Query) ([]Entry, error) {
+	now := i.now()
+	until := query.Until
+	if until.IsZero() || until.After(now) {
+		until = now
+	}
+	since := query.Since
+	if oldest := now.Add(-Retention); since.IsZero() || since.Before(oldest) {
+		since = oldest
+	}
+
+	var results []Entry
+	for bucket := until.Truncate(bucketDuration); !bucket.Before(since.Truncate(bucketDuration)); bucket = bucket.Add(-bucketDuration) {
+		data, appErr := i.api.KVGet(bucketKey(bucket))
+		if appErr != nil {
+			return nil, fmt.Errorf("failed to get alert index bucket: %w", appErr)
+		}
+
+		entries, err := decodeBucket(data)
+		if err != nil {
+			return nil, err
+		}
+
+		// Entries are appended in posting order, so walk each bucket backwards
+		for j := len(entries) - 1; j >= 0; j-- {
+			if !query.Matches(entries[j]) {
+				continue
+			}
+			results = append(results, entries[j])
+			if query.Limit > 0 && len(results) >= query.Limit {
+				return results, nil
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// bucketKey returns the KV key of the hourly bucket containing a time
+func bucketKey(t time.Time) string {
+	return bucketKeyPrefix + t.UTC().Format("2006010215")
+}
+
+// fingerprintKey returns the KV key of a content fingerprint in a channel
+func fingerprintKey(channelID, fingerprint string) string {
+	return fingerprintKeyPrefix + channelID + "_" + fingerprint
+}
+
+// decodeBucket unmarshals the entries stored in a bucket (nil data is an empty bucket)
+func decodeBucket(data []byte) ([]Entry, error) {
+	var entries []Entry
+	if data == nil {
+		return entries, nil
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert index bucket: %w", err)
+	}
+	return entries, nil
+}
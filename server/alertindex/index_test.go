@@ -0,0 +1,188 @@
+package alertindex
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// newMemoryKVAPI returns a mock API backed by an in-memory KV store supporting atomic sets
+func newMemoryKVAPI() (*plugintest.API, map[string][]byte) {
+	store := make(map[string][]byte)
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return store[key], nil
+	})
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+			if options.Atomic && !bytes.Equal(store[key], options.OldValue) {
+				return false, nil
+			}
+			store[key] = value
+			return true, nil
+		})
+	return api, store
+}
+
+func TestNewEntry(t *testing.T) {
+	eventTime := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	postedAt := eventTime.Add(time.Minute)
+	alert := backend.Alert{
+		AlertID:     "alert-1",
+		BackendID:   "backend-1",
+		BackendName: "Corporate Security",
+		AlertType:   "Flash",
+		Headline:    "Flooding reported downtown",
+		Topics:      []string{"Weather"},
+		Location:    &backend.Location{Address: "Austin, TX, USA"},
+		EventTime:   eventTime,
+	}
+
+	entry := NewEntry(alert, &model.Post{Id: "post-1", ChannelId: "channel-1"}, postedAt)
+
+	assert.Equal(t, Entry{
+		AlertID:     "alert-1",
+		BackendID:   "backend-1",
+		BackendName: "Corporate Security",
+		AlertType:   "Flash",
+		Headline:    "Flooding reported downtown",
+		Topics:      []string{"Weather"},
+		Location:    "Austin, TX, USA",
+		EventTime:   eventTime,
+		ChannelID:   "channel-1",
+		PostID:      "post-1",
+		PostedAt:    postedAt,
+		Fingerprint: alert.Fingerprint(),
+	}, entry)
+}
+
+func TestQuery_Matches(t *testing.T) {
+	postedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	entry := Entry{
+		BackendID: "backend-1",
+		AlertType: "Flash",
+		Headline:  "Flooding reported downtown",
+		Topics:    []string{"Severe Weather"},
+		PostedAt:  postedAt,
+	}
+
+	tests := []struct {
+		name    string
+		query   Query
+		matches bool
+	}{
+		{"empty query", Query{}, true},
+		{"headline keyword", Query{Keywords: []string{"FLOOD"}}, true},
+		{"topic keyword", Query{Keywords: []string{"weather"}}, true},
+		{"all keywords required", Query{Keywords: []string{"flood", "fire"}}, false},
+		{"matching type", Query{AlertType: "flash"}, true},
+		{"other type", Query{AlertType: "Urgent"}, false},
+		{"matching backend", Query{BackendID: "backend-1"}, true},
+		{"other backend", Query{BackendID: "backend-2"}, false},
+		{"within range", Query{Since: postedAt.Add(-time.Hour), Until: postedAt.Add(time.Hour)}, true},
+		{"before range", Query{Since: postedAt.Add(time.Minute)}, false},
+		{"after range", Query{Until: postedAt.Add(-time.Minute)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matches, tt.query.Matches(entry))
+		})
+	}
+}
+
+func TestIndex_AddAndSearch(t *testing.T) {
+	api, store := newMemoryKVAPI()
+	now := time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC)
+
+	index := New(api)
+	index.now = func() time.Time { return now }
+
+	entries := []Entry{
+		{AlertID: "old", AlertType: "Flash", Headline: "Flood warning", PostedAt: now.Add(-8 * 24 * time.Hour)},
+		{AlertID: "a", AlertType: "Flash", Headline: "Flood warning", PostedAt: now.Add(-3 * time.Hour)},
+		{AlertID: "b", AlertType: "Urgent", Headline: "Power outage", PostedAt: now.Add(-2 * time.Hour)},
+		{AlertID: "c", AlertType: "Alert", Headline: "Flood barrier closed", PostedAt: now.Add(-10 * time.Minute)},
+		{AlertID: "d", AlertType: "Flash", Headline: "Flooding spreads", PostedAt: now.Add(-5 * time.Minute)},
+	}
+	for _, entry := range entries {
+		require.NoError(t, index.Add(entry))
+	}
+	assert.Contains(t, store, "alert_index_2026101612")
+
+	ids := func(results []Entry) []string {
+		var result []string
+		for _, entry := range results {
+			result = append(result, entry.AlertID)
+		}
+		return result
+	}
+
+	results, err := index.Search(Query{Keywords: []string{"flood"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d", "c", "a"}, ids(results), "newest first, excluding entries past retention")
+
+	results, err = index.Search(Query{Keywords: []string{"flood"}, AlertType: "flash", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d"}, ids(results))
+
+	results, err = index.Search(Query{Since: now.Add(-time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d", "c"}, ids(results))
+}
+
+func TestIndex_AddRetriesConcurrentUpdate(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	postedAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	api.On("KVGet", "alert_index_2026101612").Return(nil, nil).Twice()
+	api.On("KVSetWithOptions", "alert_index_2026101612", mock.Anything, mock.Anything).Return(false, nil).Once()
+	api.On("KVSetWithOptions", "alert_index_2026101612", mock.Anything, mock.MatchedBy(func(options model.PluginKVSetOptions) bool {
+		return options.Atomic && options.ExpireInSeconds == int64((Retention+time.Hour)/time.Second)
+	})).Return(true, nil).Once()
+
+	require.NoError(t, New(api).Add(Entry{AlertID: "a", PostedAt: postedAt}))
+}
+
+func TestIndex_AddGivesUpAfterRepeatedConflicts(t *testing.T) {
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(nil, nil)
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+
+	err := New(api).Add(Entry{AlertID: "a", PostedAt: time.Now()})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too many concurrent updates")
+}
+
+func TestIndex_FindByFingerprint(t *testing.T) {
+	api, _ := newMemoryKVAPI()
+	index := New(api)
+
+	postedAt := time.Now()
+	alert := backend.Alert{AlertID: "alert-1", Headline: "Flooding reported downtown", EventTime: postedAt.Add(-time.Minute)}
+	require.NoError(t, index.Add(NewEntry(alert, &model.Post{Id: "post-1", ChannelId: "channel-1"}, postedAt)))
+
+	found, err := index.FindByFingerprint("channel-1", alert.Fingerprint())
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "alert-1", found.AlertID)
+	assert.Equal(t, "post-1", found.PostID)
+
+	// Fingerprints are kept per channel
+	found, err = index.FindByFingerprint("channel-2", alert.Fingerprint())
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	found, err = index.FindByFingerprint("channel-1", "")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
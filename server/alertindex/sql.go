@@ -0,0 +1,263 @@
+package alertindex
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/sqlstore"
+)
+
+const (
+	// tableName is the table holding the entries of the SQL index
+	tableName = "dataminr_alert_index"
+
+	// migrationsTableName records the schema migrations applied to the SQL index
+	migrationsTableName = "dataminr_schema_migrations"
+
+	// likeEscape escapes the wildcards of keywords matched with LIKE. A backslash is avoided
+	// since MySQL also treats it as an escape in string literals.
+	likeEscape = "!"
+)
+
+// migrations are the schema changes of the SQL index, in the order they are applied.
+// Applied migrations must never change; add a new migration instead.
+var migrations = []sqlstore.Migration{
+	{
+		Version: 1,
+		Postgres: []string{
+			`CREATE TABLE IF NOT EXISTS ` + tableName + ` (
+				post_id VARCHAR(26) NOT NULL PRIMARY KEY,
+				alert_id VARCHAR(255) NOT NULL,
+				backend_id VARCHAR(36) NOT NULL,
+				backend_name VARCHAR(255) NOT NULL,
+				alert_type VARCHAR(64) NOT NULL,
+				headline TEXT NOT NULL,
+				topics TEXT NOT NULL,
+				location TEXT NOT NULL,
+				event_time BIGINT NOT NULL,
+				channel_id VARCHAR(26) NOT NULL,
+				posted_at BIGINT NOT NULL,
+				fingerprint VARCHAR(64) NOT NULL,
+				search_text TEXT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_dataminr_alert_index_posted_at ON ` + tableName + ` (posted_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_dataminr_alert_index_fingerprint ON ` + tableName + ` (channel_id, fingerprint, posted_at)`,
+		},
+		MySQL: []string{
+			`CREATE TABLE IF NOT EXISTS ` + tableName + ` (
+				post_id VARCHAR(26) NOT NULL PRIMARY KEY,
+				alert_id VARCHAR(255) NOT NULL,
+				backend_id VARCHAR(36) NOT NULL,
+				backend_name VARCHAR(255) NOT NULL,
+				alert_type VARCHAR(64) NOT NULL,
+				headline TEXT NOT NULL,
+				topics TEXT NOT NULL,
+				location TEXT NOT NULL,
+				event_time BIGINT NOT NULL,
+				channel_id VARCHAR(26) NOT NULL,
+				posted_at BIGINT NOT NULL,
+				fingerprint VARCHAR(64) NOT NULL,
+				search_text TEXT NOT NULL,
+				INDEX idx_dataminr_alert_index_posted_at (posted_at),
+				INDEX idx_dataminr_alert_index_fingerprint (channel_id, fingerprint, posted_at)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+		},
+	},
+}
+
+// entryColumns are the columns an entry is read from, in the order scanned by scanEntry
+const entryColumns = "post_id, alert_id, backend_id, backend_name, alert_type, headline, topics, location, event_time, channel_id, posted_at, fingerprint"
+
+// SQLIndex stores posted alerts in a table of the Mattermost database, for deployments
+// posting more alerts than hourly KV buckets hold comfortably. Entries past the retention
+// period are not returned and are deleted by Prune.
+type SQLIndex struct {
+	db     *sql.DB
+	driver string
+	now    func() time.Time
+}
+
+// NewSQL creates an alert index in a Mattermost database of the given driver ("postgres" or
+// "mysql"). Migrate must be called before the index is used.
+func NewSQL(db *sql.DB, driver string) (*SQLIndex, error) {
+	if err := sqlstore.CheckDriver(driver); err != nil {
+		return nil, err
+	}
+
+	return &SQLIndex{
+		db:     db,
+		driver: driver,
+		now:    time.Now,
+	}, nil
+}
+
+// Migrate applies the schema migrations not applied yet. Nodes of a cluster must not
+// migrate concurrently.
+func (s *SQLIndex) Migrate() error {
+	return sqlstore.Migrate(s.db, s.driver, migrationsTableName, migrations)
+}
+
+// Add records a posted alert
+func (s *SQLIndex) Add(entry Entry) error {
+	topics, err := json.Marshal(entry.Topics)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert topics: %w", err)
+	}
+
+	_, err = s.db.Exec(s.rebind(`INSERT INTO `+tableName+` (`+entryColumns+`, search_text) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		entry.PostID,
+		entry.AlertID,
+		entry.BackendID,
+		entry.BackendName,
+		entry.AlertType,
+		entry.Headline,
+		string(topics),
+		entry.Location,
+		entry.EventTime.UnixMilli(),
+		entry.ChannelID,
+		entry.PostedAt.UnixMilli(),
+		entry.Fingerprint,
+		searchText(entry),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save alert index entry: %w", err)
+	}
+	return nil
+}
+
+// FindByFingerprint returns the latest entry posted to a channel with a content fingerprint
+// within the retention period, or nil if there is none
+func (s *SQLIndex) FindByFingerprint(channelID, fingerprint string) (*Entry, error) {
+	if fingerprint == "" {
+		return nil, nil
+	}
+
+	rows, err := s.db.Query(s.rebind(`SELECT `+entryColumns+` FROM `+tableName+
+		` WHERE channel_id = ? AND fingerprint = ? AND posted_at >= ? ORDER BY posted_at DESC LIMIT 1`),
+		channelID, fingerprint, s.now().Add(-Retention).UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert fingerprint: %w", err)
+	}
+
+	entries, err := scanEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return &entries[0], nil
+}
+
+// Search returns the entries matching the query, most recently posted first.
+// The search is limited to the retention period.
+func (s *SQLIndex) Search(query Query) ([]Entry, error) {
+	now := s.now()
+	until := query.Until
+	if until.IsZero() || until.After(now) {
+		until = now
+	}
+	since := query.Since
+	if oldest := now.Add(-Retention); since.IsZero() || since.Before(oldest) {
+		since = oldest
+	}
+
+	conditions := []string{"posted_at >= ?", "posted_at <= ?"}
+	args := []any{since.UnixMilli(), until.UnixMilli()}
+	if query.AlertType != "" {
+		conditions = append(conditions, "LOWER(alert_type) = ?")
+		args = append(args, strings.ToLower(query.AlertType))
+	}
+	if query.BackendID != "" {
+		conditions = append(conditions, "backend_id = ?")
+		args = append(args, query.BackendID)
+	}
+	for _, keyword := range query.Keywords {
+		conditions = append(conditions, "search_text LIKE ? ESCAPE '"+likeEscape+"'")
+		args = append(args, "%"+escapeLike(strings.ToLower(keyword))+"%")
+	}
+
+	statement := `SELECT ` + entryColumns + ` FROM ` + tableName + ` WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY posted_at DESC`
+	if query.Limit > 0 {
+		statement += " LIMIT " + strconv.Itoa(query.Limit)
+	}
+
+	rows, err := s.db.Query(s.rebind(statement), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search alert index: %w", err)
+	}
+	return scanEntries(rows)
+}
+
+// Prune deletes the entries posted before a time, e.g. past the retention period.
+// Returns the number of deleted entries.
+func (s *SQLIndex) Prune(before time.Time) (int64, error) {
+	result, err := s.db.Exec(s.rebind(`DELETE FROM `+tableName+` WHERE posted_at < ?`), before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune alert index: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune alert index: %w", err)
+	}
+	return deleted, nil
+}
+
+// rebind replaces the placeholders of a statement for the database driver
+func (s *SQLIndex) rebind(statement string) string {
+	return sqlstore.Rebind(s.driver, statement)
+}
+
+// searchText is the lowercased text matched against keywords, the same as Query.Matches
+func searchText(entry Entry) string {
+	return strings.ToLower(entry.Headline + "\n" + strings.Join(entry.Topics, "\n"))
+}
+
+// escapeLike escapes the LIKE wildcards of a keyword so they match literally
+func escapeLike(keyword string) string {
+	return strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_").Replace(keyword)
+}
+
+// scanEntries reads the entries of the rows of a query and closes them
+func scanEntries(rows *sql.Rows) ([]Entry, error) {
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var entry Entry
+		var topics string
+		var eventTime, postedAt int64
+		if err := rows.Scan(
+			&entry.PostID,
+			&entry.AlertID,
+			&entry.BackendID,
+			&entry.BackendName,
+			&entry.AlertType,
+			&entry.Headline,
+			&topics,
+			&entry.Location,
+			&eventTime,
+			&entry.ChannelID,
+			&postedAt,
+			&entry.Fingerprint,
+		); err != nil {
+			return nil, fmt.Errorf("failed to read alert index entry: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(topics), &entry.Topics); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal alert topics: %w", err)
+		}
+		entry.EventTime = time.UnixMilli(eventTime).UTC()
+		entry.PostedAt = time.UnixMilli(postedAt).UTC()
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read alert index entries: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,194 @@
+package alertindex
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/sqlstore/sqlstoretest"
+)
+
+// newTestSQLIndex creates an SQL index on a fake database at a fixed time
+func newTestSQLIndex(t *testing.T, driverName string, now time.Time) (*SQLIndex, *sqlstoretest.FakeDatabase) {
+	fake, db := sqlstoretest.Open(t)
+
+	index, err := NewSQL(db, driverName)
+	require.NoError(t, err)
+	index.now = func() time.Time { return now }
+	return index, fake
+}
+
+// entryRow is the row of an entry as read by entryColumns
+func entryRow(entry Entry, topics string) []driver.Value {
+	return []driver.Value{
+		entry.PostID, entry.AlertID, entry.BackendID, entry.BackendName, entry.AlertType, entry.Headline,
+		topics, entry.Location, entry.EventTime.UnixMilli(), entry.ChannelID, entry.PostedAt.UnixMilli(), entry.Fingerprint,
+	}
+}
+
+func TestNewSQL(t *testing.T) {
+	_, err := NewSQL(nil, "sqlite3")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported database driver 'sqlite3'")
+}
+
+func TestSQLIndex_Migrate(t *testing.T) {
+	t.Run("postgres", func(t *testing.T) {
+		index, fake := newTestSQLIndex(t, "postgres", time.Now())
+
+		require.NoError(t, index.Migrate())
+
+		require.Len(t, fake.Execs, 2+len(migrations[0].Postgres))
+		assert.Contains(t, fake.Execs[0].Query, "CREATE TABLE IF NOT EXISTS dataminr_schema_migrations")
+		assert.Contains(t, fake.Execs[1].Query, "CREATE TABLE IF NOT EXISTS dataminr_alert_index")
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		index, fake := newTestSQLIndex(t, "mysql", time.Now())
+
+		require.NoError(t, index.Migrate())
+
+		require.Len(t, fake.Execs, 2+len(migrations[0].MySQL))
+		assert.Contains(t, fake.Execs[1].Query, "ENGINE=InnoDB")
+	})
+}
+
+func TestSQLIndex_Add(t *testing.T) {
+	index, fake := newTestSQLIndex(t, "postgres", time.Now())
+	postedAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	require.NoError(t, index.Add(Entry{
+		AlertID:     "alert-1",
+		BackendID:   "backend-1",
+		BackendName: "Production",
+		AlertType:   "Flash",
+		Headline:    "Fire at Main St",
+		Topics:      []string{"Fires"},
+		Location:    "Main St",
+		EventTime:   postedAt.Add(-time.Minute),
+		ChannelID:   "channel-1",
+		PostID:      "post-1",
+		PostedAt:    postedAt,
+		Fingerprint: "abc",
+	}))
+
+	require.Len(t, fake.Execs, 1)
+	assert.Contains(t, fake.Execs[0].Query, "INSERT INTO dataminr_alert_index")
+	assert.Contains(t, fake.Execs[0].Query, "VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)")
+	assert.Equal(t, []driver.Value{
+		"post-1", "alert-1", "backend-1", "Production", "Flash", "Fire at Main St", `["Fires"]`, "Main St",
+		postedAt.Add(-time.Minute).UnixMilli(), "channel-1", postedAt.UnixMilli(), "abc", "fire at main st\nfires",
+	}, fake.Execs[0].Args)
+}
+
+func TestSQLIndex_Search(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	t.Run("filters in the database", func(t *testing.T) {
+		index, fake := newTestSQLIndex(t, "postgres", now)
+
+		_, err := index.Search(Query{Keywords: []string{"Fire", "100%"}, AlertType: "flash", BackendID: "backend-1", Limit: 5})
+		require.NoError(t, err)
+
+		require.Len(t, fake.Queries, 1)
+		assert.Equal(t, "SELECT "+entryColumns+" FROM dataminr_alert_index WHERE posted_at >= $1 AND posted_at <= $2"+
+			" AND LOWER(alert_type) = $3 AND backend_id = $4 AND search_text LIKE $5 ESCAPE '!' AND search_text LIKE $6 ESCAPE '!'"+
+			" ORDER BY posted_at DESC LIMIT 5", fake.Queries[0].Query)
+		assert.Equal(t, []driver.Value{
+			now.Add(-Retention).UnixMilli(), now.UnixMilli(), "flash", "backend-1", "%fire%", "%100!%%",
+		}, fake.Queries[0].Args)
+	})
+
+	t.Run("bounds the time range by the retention period", func(t *testing.T) {
+		index, fake := newTestSQLIndex(t, "mysql", now)
+
+		_, err := index.Search(Query{Since: now.Add(-30 * 24 * time.Hour), Until: now.Add(-time.Hour)})
+		require.NoError(t, err)
+
+		require.Len(t, fake.Queries, 1)
+		assert.Equal(t, "SELECT "+entryColumns+" FROM dataminr_alert_index WHERE posted_at >= ? AND posted_at <= ? ORDER BY posted_at DESC", fake.Queries[0].Query)
+		assert.Equal(t, []driver.Value{now.Add(-Retention).UnixMilli(), now.Add(-time.Hour).UnixMilli()}, fake.Queries[0].Args)
+	})
+
+	t.Run("reads the entries", func(t *testing.T) {
+		index, fake := newTestSQLIndex(t, "postgres", now)
+		entry := Entry{
+			AlertID:     "alert-1",
+			BackendID:   "backend-1",
+			BackendName: "Production",
+			AlertType:   "Flash",
+			Headline:    "Fire at Main St",
+			Topics:      []string{"Fires"},
+			EventTime:   now.Add(-2 * time.Hour),
+			ChannelID:   "channel-1",
+			PostID:      "post-1",
+			PostedAt:    now.Add(-time.Hour),
+			Fingerprint: "abc",
+		}
+		fake.Rows["FROM dataminr_alert_index"] = [][]driver.Value{entryRow(entry, `["Fires"]`)}
+
+		entries, err := index.Search(Query{})
+		require.NoError(t, err)
+		assert.Equal(t, []Entry{entry}, entries)
+	})
+}
+
+func TestSQLIndex_FindByFingerprint(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	t.Run("latest entry", func(t *testing.T) {
+		index, fake := newTestSQLIndex(t, "postgres", now)
+		entry := Entry{AlertID: "alert-2", PostID: "post-2", ChannelID: "channel-1", Fingerprint: "abc", PostedAt: now.Add(-time.Hour), EventTime: now.Add(-time.Hour)}
+		fake.Rows["WHERE channel_id = $1 AND fingerprint = $2"] = [][]driver.Value{entryRow(entry, "null")}
+
+		found, err := index.FindByFingerprint("channel-1", "abc")
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, entry, *found)
+		assert.Contains(t, fake.Queries[0].Query, "ORDER BY posted_at DESC LIMIT 1")
+		assert.Equal(t, []driver.Value{"channel-1", "abc", now.Add(-Retention).UnixMilli()}, fake.Queries[0].Args)
+	})
+
+	t.Run("no entry", func(t *testing.T) {
+		index, _ := newTestSQLIndex(t, "postgres", now)
+
+		found, err := index.FindByFingerprint("channel-1", "abc")
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("empty fingerprint", func(t *testing.T) {
+		index, fake := newTestSQLIndex(t, "postgres", now)
+
+		found, err := index.FindByFingerprint("channel-1", "")
+		require.NoError(t, err)
+		assert.Nil(t, found)
+		assert.Empty(t, fake.Queries)
+	})
+}
+
+func TestSQLIndex_Prune(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	index, fake := newTestSQLIndex(t, "postgres", now)
+	fake.RowsAffected = 3
+
+	deleted, err := index.Prune(now.Add(-Retention))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	require.Len(t, fake.Execs, 1)
+	assert.Equal(t, "DELETE FROM dataminr_alert_index WHERE posted_at < $1", fake.Execs[0].Query)
+	assert.Equal(t, []driver.Value{now.Add(-Retention).UnixMilli()}, fake.Execs[0].Args)
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, "100!% off!_sale!!", escapeLike("100% off_sale!"))
+}
+
+// Both implementations satisfy the Store interface
+var (
+	_ Store = (*Index)(nil)
+	_ Store = (*SQLIndex)(nil)
+)
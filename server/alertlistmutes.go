@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// backendParamPrefix prefixes the backend an alert list is muted for, e.g. backend:"Weather Watch"
+const backendParamPrefix = "backend:"
+
+// muteListParams are the parameters of /dataminr mute list and /dataminr unmute list
+type muteListParams struct {
+	alertList   string
+	backendName string
+	duration    time.Duration
+}
+
+// commandArg is a word of command parameters, possibly quoted
+type commandArg struct {
+	text   string
+	quoted bool
+}
+
+// splitQuotedArgs splits command parameters into words, keeping double-quoted text (which may
+// contain spaces) together. The quotes are removed.
+func splitQuotedArgs(params []string) []commandArg {
+	var args []commandArg
+	var current strings.Builder
+	inQuotes, quoted, started := false, false, false
+	for _, r := range strings.Join(params, " ") {
+		switch {
+		case r == '"' || r == '“' || r == '”':
+			inQuotes = !inQuotes
+			quoted, started = true, true
+		case r == ' ' && !inQuotes:
+			if started {
+				args = append(args, commandArg{text: current.String(), quoted: quoted})
+			}
+			current.Reset()
+			quoted, started = false, false
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		args = append(args, commandArg{text: current.String(), quoted: quoted})
+	}
+	return args
+}
+
+// parseMuteListParams parses /dataminr mute list "<alert list>" [duration] [backend:<name>].
+// Unquoted names may span several words; the last unquoted word is the duration when it
+// parses as one (e.g., 4h, 2d).
+func parseMuteListParams(params []string) (muteListParams, error) {
+	parsed := muteListParams{duration: backend.DefaultAlertListMuteDuration}
+
+	var words []commandArg
+	for _, arg := range splitQuotedArgs(params) {
+		if name, found := strings.CutPrefix(arg.text, backendParamPrefix); found {
+			if name == "" {
+				return muteListParams{}, errors.New("missing backend name")
+			}
+			parsed.backendName = name
+			continue
+		}
+		words = append(words, arg)
+	}
+
+	if last := len(words) - 1; last > 0 && !words[last].quoted {
+		if duration, err := parseDurationParam(words[last].text); err == nil {
+			parsed.duration = duration
+			words = words[:last]
+		}
+	}
+
+	names := make([]string, 0, len(words))
+	for _, word := range words {
+		names = append(names, word.text)
+	}
+	parsed.alertList = strings.TrimSpace(strings.Join(names, " "))
+	return parsed, nil
+}
+
+// alertListMuteScope resolves where an alert list is muted: every channel of the named
+// backend, or the channel the command was run in. Returns a response for the user if the
+// scope can't be used.
+func (p *Plugin) alertListMuteScope(args *model.CommandArgs, backendName string) (backend.AlertListMuteScope, string, string) {
+	if backendName == "" {
+		return backend.AlertListMuteScope{ChannelID: args.ChannelId}, "in this channel", ""
+	}
+
+	if !p.isAuthorized(args.UserId, accessControl) {
+		return backend.AlertListMuteScope{}, "", "You don't have permission to mute alert lists for a whole backend."
+	}
+
+	b := p.findBackend(backendName)
+	if b == nil {
+		return backend.AlertListMuteScope{}, "", fmt.Sprintf("Backend `%s` not found.", backendName)
+	}
+	return backend.AlertListMuteScope{BackendID: b.GetID()}, fmt.Sprintf("for backend **%s**", b.GetName()), ""
+}
+
+// executeMuteAlertList handles /dataminr mute list "<alert list>" [duration] [backend:<name>]
+func (p *Plugin) executeMuteAlertList(args *model.CommandArgs, params []string) string {
+	parsed, err := parseMuteListParams(params)
+	if err != nil {
+		return fmt.Sprintf("Invalid parameters: %s.", err.Error())
+	}
+	if parsed.alertList == "" {
+		return fmt.Sprintf("Please specify an alert list, e.g. `/%s mute list \"Gulf Coast Weather\" 4h`.", commandTrigger)
+	}
+	if parsed.duration > backend.MaxAlertListMuteDuration {
+		return fmt.Sprintf("Alert lists can be muted for at most %d days.", int(backend.MaxAlertListMuteDuration.Hours()/24))
+	}
+
+	scope, where, response := p.alertListMuteScope(args, parsed.backendName)
+	if response != "" {
+		return response
+	}
+
+	now := time.Now()
+	mute := backend.AlertListMute{AlertList: parsed.alertList, Until: now.Add(parsed.duration).UTC(), CreatedBy: args.UserId}
+	if err := p.alertListMutes.Mute(scope, mute, now); err != nil {
+		p.API.LogError("Failed to mute alert list", "channelId", scope.ChannelID, "backendId", scope.BackendID, "alertList", parsed.alertList, "error", err.Error())
+		return fmt.Sprintf("Failed to mute alert list **%s**.", parsed.alertList)
+	}
+
+	return fmt.Sprintf("Alerts in the alert list **%s** are muted %s until %s.", parsed.alertList, where, mute.Until.Format(time.RFC1123))
+}
+
+// executeUnmuteAlertList handles /dataminr unmute list "<alert list>" [backend:<name>]
+func (p *Plugin) executeUnmuteAlertList(args *model.CommandArgs, params []string) string {
+	parsed, err := parseMuteListParams(params)
+	if err != nil {
+		return fmt.Sprintf("Invalid parameters: %s.", err.Error())
+	}
+	if parsed.alertList == "" {
+		return fmt.Sprintf("Please specify an alert list, e.g. `/%s unmute list \"Gulf Coast Weather\"`.", commandTrigger)
+	}
+
+	scope, where, response := p.alertListMuteScope(args, parsed.backendName)
+	if response != "" {
+		return response
+	}
+
+	removed, err := p.alertListMutes.Unmute(scope, parsed.alertList, time.Now())
+	if err != nil {
+		p.API.LogError("Failed to unmute alert list", "channelId", scope.ChannelID, "backendId", scope.BackendID, "alertList", parsed.alertList, "error", err.Error())
+		return fmt.Sprintf("Failed to unmute alert list **%s**.", parsed.alertList)
+	}
+
+	if !removed {
+		return fmt.Sprintf("The alert list **%s** is not muted %s.", parsed.alertList, where)
+	}
+	return fmt.Sprintf("Alerts in the alert list **%s** are shown %s again.", parsed.alertList, where)
+}
+
+// listAlertListMutes describes the alert lists muted in a channel and for each backend.
+// Returns an empty string if none are muted.
+func (p *Plugin) listAlertListMutes(channelID string) (string, error) {
+	now := time.Now()
+	var sb strings.Builder
+
+	mutes, err := p.alertListMutes.List(backend.AlertListMuteScope{ChannelID: channelID}, now)
+	if err != nil {
+		return "", err
+	}
+	for _, mute := range mutes {
+		sb.WriteString(fmt.Sprintf("* **%s** until %s\n", mute.AlertList, mute.Until.Format(time.RFC1123)))
+	}
+
+	backends := p.registry.List()
+	sort.Slice(backends, func(i, j int) bool { return backends[i].GetName() < backends[j].GetName() })
+	for _, b := range backends {
+		mutes, err := p.alertListMutes.List(backend.AlertListMuteScope{BackendID: b.GetID()}, now)
+		if err != nil {
+			return "", err
+		}
+		for _, mute := range mutes {
+			sb.WriteString(fmt.Sprintf("* **%s** for backend **%s** until %s\n", mute.AlertList, b.GetName(), mute.Until.Format(time.RFC1123)))
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "", nil
+	}
+	return "###### Muted alert lists\n" + sb.String(), nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// newAlertListMuteTestPlugin returns a plugin with the Weather Watch backend registered and
+// an in-memory KV store for the mutes of channel-1 and the backend
+func newAlertListMuteTestPlugin(t *testing.T, admin bool) (*Plugin, *plugintest.API) {
+	api := &plugintest.API{}
+	p := newCommandTestPlugin(api)
+	p.registry = backend.NewRegistry()
+	require.NoError(t, p.registry.Register(&fakeBackend{id: "weather-id", name: "Weather Watch"}))
+
+	api.On("HasPermissionToChannel", "user-id", "channel-1", model.PermissionReadChannel).Return(true)
+	api.On("HasPermissionTo", "user-id", model.PermissionManageSystem).Return(admin)
+	api.On("KVGet", "channel_channel-1_topic_mutes").Return(nil, nil)
+
+	stored := make(map[string][]byte)
+	for _, key := range []string{"channel_channel-1_alert_list_mutes", "backend_weather-id_alert_list_mutes"} {
+		key := key
+		api.On("KVGet", key).Return(func(string) []byte { return stored[key] }, nil)
+		api.On("KVSet", key, mock.Anything).Run(func(args mock.Arguments) {
+			stored[key] = args.Get(1).([]byte)
+		}).Return(nil)
+		api.On("KVDelete", key).Run(func(mock.Arguments) { delete(stored, key) }).Return(nil)
+	}
+	return p, api
+}
+
+func TestParseMuteListParams(t *testing.T) {
+	parsed, err := parseMuteListParams([]string{`"Gulf`, `Coast`, `Weather"`, "4h"})
+	require.NoError(t, err)
+	assert.Equal(t, muteListParams{alertList: "Gulf Coast Weather", duration: 4 * time.Hour}, parsed)
+
+	parsed, err = parseMuteListParams([]string{"Gulf", "Coast", "Weather", `backend:"Weather`, `Watch"`, "2d"})
+	require.NoError(t, err)
+	assert.Equal(t, muteListParams{alertList: "Gulf Coast Weather", backendName: "Weather Watch", duration: 48 * time.Hour}, parsed)
+
+	parsed, err = parseMuteListParams([]string{"“Wildfires”"})
+	require.NoError(t, err)
+	assert.Equal(t, muteListParams{alertList: "Wildfires", duration: backend.DefaultAlertListMuteDuration}, parsed)
+
+	parsed, err = parseMuteListParams([]string{"Watchlist", `"4h"`})
+	require.NoError(t, err)
+	assert.Equal(t, "Watchlist 4h", parsed.alertList, "quoted words are never the duration")
+
+	parsed, err = parseMuteListParams([]string{"2d"})
+	require.NoError(t, err)
+	assert.Equal(t, "2d", parsed.alertList, "a single word is always the alert list")
+
+	_, err = parseMuteListParams([]string{"Wildfires", "backend:"})
+	assert.EqualError(t, err, "missing backend name")
+}
+
+func TestExecuteMuteAlertList(t *testing.T) {
+	args := &model.CommandArgs{UserId: "user-id", ChannelId: "channel-1"}
+
+	t.Run("mute, list and unmute in a channel", func(t *testing.T) {
+		p, _ := newAlertListMuteTestPlugin(t, false)
+
+		text := p.executeMute(args, []string{"list", `"Gulf`, "Coast", `Weather"`, "2h"})
+		assert.Contains(t, text, "Alerts in the alert list **Gulf Coast Weather** are muted in this channel until")
+
+		text = p.executeMute(args, []string{"list"})
+		assert.Contains(t, text, "###### Muted alert lists\n* **Gulf Coast Weather** until")
+		assert.NotContains(t, text, "Muted topics")
+
+		assert.Equal(t, "Alerts in the alert list **gulf coast weather** are shown in this channel again.",
+			p.executeUnmute(args, []string{"list", `"gulf`, "coast", `weather"`}))
+		assert.Equal(t, "The alert list **gulf coast weather** is not muted in this channel.",
+			p.executeUnmute(args, []string{"list", `"gulf`, "coast", `weather"`}))
+	})
+
+	t.Run("mute for a backend", func(t *testing.T) {
+		p, _ := newAlertListMuteTestPlugin(t, true)
+
+		text := p.executeMute(args, []string{"list", "Wildfires", "1d", `backend:"Weather`, `Watch"`})
+		assert.Contains(t, text, "Alerts in the alert list **Wildfires** are muted for backend **Weather Watch** until")
+
+		assert.Contains(t, p.executeMute(args, []string{"list"}), "* **Wildfires** for backend **Weather Watch** until")
+
+		assert.Equal(t, "Alerts in the alert list **Wildfires** are shown for backend **Weather Watch** again.",
+			p.executeUnmute(args, []string{"list", "Wildfires", "backend:weather-id"}))
+	})
+
+	t.Run("muting for a backend requires backend control", func(t *testing.T) {
+		p, api := newAlertListMuteTestPlugin(t, false)
+		p.configuration = &configuration{}
+
+		assert.Equal(t, "You don't have permission to mute alert lists for a whole backend.",
+			p.executeMute(args, []string{"list", "Wildfires", "backend:weather-id"}))
+		api.AssertNotCalled(t, "KVSet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		p, _ := newAlertListMuteTestPlugin(t, true)
+
+		assert.Contains(t, p.executeUnmute(args, []string{"list"}), "Please specify an alert list")
+		assert.Contains(t, p.executeMute(args, []string{"list", `""`}), "Please specify an alert list")
+		assert.Equal(t, "Alert lists can be muted for at most 30 days.", p.executeMute(args, []string{"list", "Wildfires", "31d"}))
+		assert.Equal(t, "Backend `Missing` not found.", p.executeMute(args, []string{"list", "Wildfires", "backend:Missing"}))
+		assert.Equal(t, "Invalid parameters: missing backend name.", p.executeMute(args, []string{"list", "Wildfires", "backend:"}))
+	})
+}
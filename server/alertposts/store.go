@@ -0,0 +1,287 @@
+// Package alertposts maps the alerts of each backend to the post they were posted as, so
+// features following up on an alert can find its post.
+package alertposts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// TTL is how long the post of an alert can be looked up
+	TTL = 30 * 24 * time.Hour
+
+	// keyPrefix prefixes the KV key of each alert's post, followed by the backend ID
+	keyPrefix = "alert_post_"
+
+	// maxUpdateAttempts is how often a mapping is retried when another node changed it concurrently
+	maxUpdateAttempts = 5
+
+	// listPageSize is the number of KV keys read per page when pruning
+	listPageSize = 1000
+)
+
+// Record is the post an alert was posted as. The alert is stored along with the post so a
+// lookup never returns the post of another alert whose key collides. The compared content
+// of the alert is kept so updates reissued under the same alert ID can be diffed.
+type Record struct {
+	BackendID string    `json:"backendId"`
+	AlertID   string    `json:"alertId"`
+	PostID    string    `json:"postId"`
+	ChannelID string    `json:"channelId"`
+	PostedAt  time.Time `json:"postedAt"`
+
+	// Copies maps the other channels the alert was posted to, e.g. subscribed or routed
+	// channels, to the alert's post there
+	Copies map[string]string `json:"copies,omitempty"`
+
+	Headline string            `json:"headline,omitempty"`
+	Location *backend.Location `json:"location,omitempty"`
+	Topics   []string          `json:"topics,omitempty"`
+}
+
+// NewRecord creates the record of an alert posted as the given post. The headline is
+// recorded before machine translation, as updates of the alert are fetched untranslated.
+func NewRecord(alert backend.Alert, post *model.Post, postedAt time.Time) Record {
+	headline := alert.Headline
+	if alert.OriginalHeadline != "" {
+		headline = alert.OriginalHeadline
+	}
+
+	return Record{
+		BackendID: alert.BackendID,
+		AlertID:   alert.AlertID,
+		PostID:    post.Id,
+		ChannelID: post.ChannelId,
+		PostedAt:  postedAt,
+		Headline:  headline,
+		Location:  alert.Location,
+		Topics:    alert.Topics,
+	}
+}
+
+// PostIn returns the post of the alert in a channel, or an empty string if it wasn't posted there
+func (r *Record) PostIn(channelID string) string {
+	if channelID == r.ChannelID {
+		return r.PostID
+	}
+	return r.Copies[channelID]
+}
+
+// Posts returns the post of the alert in every channel it was posted to, keyed by channel ID
+func (r *Record) Posts() map[string]string {
+	posts := make(map[string]string, len(r.Copies)+1)
+	for channelID, postID := range r.Copies {
+		posts[channelID] = postID
+	}
+	posts[r.ChannelID] = r.PostID
+	return posts
+}
+
+// HasContent reports whether the content of the alert was recorded, which records saved by
+// earlier versions lack
+func (r *Record) HasContent() bool {
+	return r.Headline != ""
+}
+
+// Alert returns the recorded content of the alert, for diffing against an update
+func (r *Record) Alert() backend.Alert {
+	return backend.Alert{
+		AlertID:   r.AlertID,
+		BackendID: r.BackendID,
+		Headline:  r.Headline,
+		Location:  r.Location,
+		Topics:    r.Topics,
+	}
+}
+
+// Store keeps the post of each alert in the KV store, namespaced per backend, until the TTL
+// passes. An alert posted to several channels maps to its first post and records its post
+// in each other channel as a copy; writes use compare-and-set so concurrent posts on multiple
+// cluster nodes agree on them.
+type Store struct {
+	api plugin.API
+}
+
+// New creates a new alert post store
+func New(api plugin.API) *Store {
+	return &Store{api: api}
+}
+
+// Put records the post of an alert, as its first post unless the alert already has one, or
+// else as its post in the record's channel. Returns the record as saved.
+func (s *Store) Put(record Record) (Record, error) {
+	key := postKey(record.BackendID, record.AlertID)
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		oldData, appErr := s.api.KVGet(key)
+		if appErr != nil {
+			return Record{}, fmt.Errorf("failed to get alert post: %w", appErr)
+		}
+
+		// A record of another alert under the same key is replaced
+		updated := record
+		if existing, err := decodeRecord(oldData); err == nil && existing != nil && existing.matches(record.BackendID, record.AlertID) {
+			if existing.PostIn(record.ChannelID) != "" {
+				return *existing, nil
+			}
+
+			updated = *existing
+			updated.Copies = make(map[string]string, len(existing.Copies)+1)
+			for channelID, postID := range existing.Copies {
+				updated.Copies[channelID] = postID
+			}
+			updated.Copies[record.ChannelID] = record.PostID
+		}
+
+		data, err := json.Marshal(updated)
+		if err != nil {
+			return Record{}, fmt.Errorf("failed to marshal alert post: %w", err)
+		}
+
+		saved, appErr := s.api.KVSetWithOptions(key, data, model.PluginKVSetOptions{
+			Atomic:          true,
+			OldValue:        oldData,
+			ExpireInSeconds: int64(TTL / time.Second),
+		})
+		if appErr != nil {
+			return Record{}, fmt.Errorf("failed to save alert post: %w", appErr)
+		}
+		if saved {
+			return updated, nil
+		}
+	}
+
+	return Record{}, fmt.Errorf("failed to save alert post: too many concurrent updates")
+}
+
+// UpdateContent replaces the recorded content of an alert with an update reissued under the
+// same alert ID, keeping its post. Returns the record as it was before the update, or nil
+// if the alert has no recorded post. Nothing is saved when the compared content is unchanged.
+func (s *Store) UpdateContent(alert backend.Alert) (*Record, error) {
+	key := postKey(alert.BackendID, alert.AlertID)
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		oldData, appErr := s.api.KVGet(key)
+		if appErr != nil {
+			return nil, fmt.Errorf("failed to get alert post: %w", appErr)
+		}
+
+		existing, err := decodeRecord(oldData)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil || !existing.matches(alert.BackendID, alert.AlertID) {
+			return nil, nil
+		}
+		if existing.HasContent() && existing.Alert().Diff(alert).IsEmpty() {
+			return existing, nil
+		}
+
+		updated := *existing
+		updated.Headline = alert.Headline
+		updated.Location = alert.Location
+		updated.Topics = alert.Topics
+		data, err := json.Marshal(updated)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal alert post: %w", err)
+		}
+
+		saved, appErr := s.api.KVSetWithOptions(key, data, model.PluginKVSetOptions{
+			Atomic:          true,
+			OldValue:        oldData,
+			ExpireInSeconds: int64(TTL / time.Second),
+		})
+		if appErr != nil {
+			return nil, fmt.Errorf("failed to save alert post: %w", appErr)
+		}
+		if saved {
+			return existing, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to save alert post: too many concurrent updates")
+}
+
+// Get returns the post of an alert, or nil if it is unknown or its TTL passed
+func (s *Store) Get(backendID, alertID string) (*Record, error) {
+	data, appErr := s.api.KVGet(postKey(backendID, alertID))
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get alert post: %w", appErr)
+	}
+
+	record, err := decodeRecord(data)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || !record.matches(backendID, alertID) {
+		return nil, nil
+	}
+	return record, nil
+}
+
+// Prune deletes the posts of every alert of a backend, e.g. once the backend is removed.
+// Returns the number of deleted records.
+func (s *Store) Prune(backendID string) (int, error) {
+	prefix := keyPrefix + backendID + "_"
+
+	// Collect the keys first since deleting while listing shifts the pages
+	var keys []string
+	for page := 0; ; page++ {
+		pageKeys, appErr := s.api.KVList(page, listPageSize)
+		if appErr != nil {
+			return 0, fmt.Errorf("failed to list alert posts: %w", appErr)
+		}
+
+		for _, key := range pageKeys {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+
+		if len(pageKeys) < listPageSize {
+			break
+		}
+	}
+
+	for i, key := range keys {
+		if appErr := s.api.KVDelete(key); appErr != nil {
+			return i, fmt.Errorf("failed to delete alert post: %w", appErr)
+		}
+	}
+	return len(keys), nil
+}
+
+// matches reports whether the record belongs to an alert
+func (r *Record) matches(backendID, alertID string) bool {
+	return r.BackendID == backendID && r.AlertID == alertID
+}
+
+// postKey returns the KV key of an alert's post. Alert IDs are hashed since they are not
+// guaranteed to fit the KV key length limit.
+func postKey(backendID, alertID string) string {
+	sum := sha256.Sum256([]byte(alertID))
+	return keyPrefix + backendID + "_" + hex.EncodeToString(sum[:])
+}
+
+// decodeRecord unmarshals a stored record (nil data is no record)
+func decodeRecord(data []byte) (*Record, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert post: %w", err)
+	}
+	return &record, nil
+}
@@ -0,0 +1,350 @@
+package alertposts
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// memoryKV is an in-memory KV store supporting atomic sets from concurrent writers
+type memoryKV struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+// newMemoryKVAPI returns a mock API backed by an in-memory KV store
+func newMemoryKVAPI() (*plugintest.API, *memoryKV) {
+	kv := &memoryKV{data: make(map[string][]byte)}
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		kv.mu.Lock()
+		defer kv.mu.Unlock()
+		return kv.data[key], nil
+	})
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+			kv.mu.Lock()
+			defer kv.mu.Unlock()
+			if options.Atomic && !bytes.Equal(kv.data[key], options.OldValue) {
+				return false, nil
+			}
+			kv.data[key] = value
+			return true, nil
+		})
+	api.On("KVList", mock.Anything, mock.Anything).Return(func(page, perPage int) ([]string, *model.AppError) {
+		kv.mu.Lock()
+		defer kv.mu.Unlock()
+		keys := make([]string, 0, len(kv.data))
+		for key := range kv.data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		start := min(page*perPage, len(keys))
+		end := min(start+perPage, len(keys))
+		return keys[start:end], nil
+	})
+	api.On("KVDelete", mock.Anything).Return(func(key string) *model.AppError {
+		kv.mu.Lock()
+		defer kv.mu.Unlock()
+		delete(kv.data, key)
+		return nil
+	})
+	return api, kv
+}
+
+func TestNewRecord(t *testing.T) {
+	postedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	location := &backend.Location{Address: "Austin, TX, USA"}
+	alert := backend.Alert{
+		AlertID:   "alert-1",
+		BackendID: "backend-1",
+		Headline:  "Flooding reported downtown",
+		Location:  location,
+		Topics:    []string{"Weather"},
+		AlertType: "Urgent",
+	}
+
+	record := NewRecord(alert, &model.Post{Id: "post-1", ChannelId: "channel-1"}, postedAt)
+
+	assert.Equal(t, Record{
+		BackendID: "backend-1",
+		AlertID:   "alert-1",
+		PostID:    "post-1",
+		ChannelID: "channel-1",
+		PostedAt:  postedAt,
+		Headline:  "Flooding reported downtown",
+		Location:  location,
+		Topics:    []string{"Weather"},
+	}, record)
+	assert.True(t, record.HasContent())
+	assert.Equal(t, backend.Alert{
+		AlertID:   "alert-1",
+		BackendID: "backend-1",
+		Headline:  "Flooding reported downtown",
+		Location:  location,
+		Topics:    []string{"Weather"},
+	}, record.Alert())
+
+	// The headline is recorded before machine translation
+	alert.Headline = "Inondations signalées au centre-ville"
+	alert.OriginalHeadline = "Flooding reported downtown"
+	assert.Equal(t, "Flooding reported downtown", NewRecord(alert, &model.Post{Id: "post-1"}, postedAt).Headline)
+
+	// Records saved before the content was recorded
+	assert.False(t, (&Record{BackendID: "backend-1", AlertID: "alert-1", PostID: "post-1"}).HasContent())
+}
+
+func TestStore_PutAndGet(t *testing.T) {
+	api, _ := newMemoryKVAPI()
+	store := New(api)
+
+	first := Record{BackendID: "backend-1", AlertID: "alert-1", PostID: "post-1", ChannelID: "channel-1"}
+	recorded, err := store.Put(first)
+	require.NoError(t, err)
+	assert.Equal(t, first, recorded)
+
+	// Later posts of the alert, e.g. to subscribed channels, keep the first post and are
+	// recorded as copies
+	recorded, err = store.Put(Record{BackendID: "backend-1", AlertID: "alert-1", PostID: "post-2", ChannelID: "channel-2"})
+	require.NoError(t, err)
+	assert.Equal(t, "post-1", recorded.PostID)
+	assert.Equal(t, map[string]string{"channel-2": "post-2"}, recorded.Copies)
+
+	// Another post in a channel the alert was already posted to is ignored
+	recorded, err = store.Put(Record{BackendID: "backend-1", AlertID: "alert-1", PostID: "post-3", ChannelID: "channel-2"})
+	require.NoError(t, err)
+	assert.Equal(t, "post-2", recorded.PostIn("channel-2"))
+
+	record, err := store.Get("backend-1", "alert-1")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "post-1", record.PostID)
+	assert.Equal(t, "post-1", record.PostIn("channel-1"))
+	assert.Equal(t, "post-2", record.PostIn("channel-2"))
+	assert.Empty(t, record.PostIn("channel-3"))
+	assert.Equal(t, map[string]string{"channel-1": "post-1", "channel-2": "post-2"}, record.Posts())
+
+	// Alert IDs are namespaced per backend
+	record, err = store.Get("backend-2", "alert-1")
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	record, err = store.Get("backend-1", "alert-unknown")
+	require.NoError(t, err)
+	assert.Nil(t, record)
+}
+
+func TestStore_UpdateContent(t *testing.T) {
+	api, _ := newMemoryKVAPI()
+	store := New(api)
+
+	original := Record{BackendID: "backend-1", AlertID: "alert-1", PostID: "post-1", ChannelID: "channel-1", Headline: "Flooding reported downtown", Topics: []string{"Weather"}}
+	_, err := store.Put(original)
+	require.NoError(t, err)
+
+	previous, err := store.UpdateContent(backend.Alert{
+		BackendID: "backend-1",
+		AlertID:   "alert-1",
+		Headline:  "Flooding reported across the city",
+		Location:  &backend.Location{Address: "Austin, TX, USA"},
+		Topics:    []string{"Weather", "Evacuation"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, previous)
+	assert.Equal(t, original, *previous)
+
+	record, err := store.Get("backend-1", "alert-1")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "post-1", record.PostID, "The post of the alert must be kept")
+	assert.Equal(t, "channel-1", record.ChannelID)
+	assert.Equal(t, "Flooding reported across the city", record.Headline)
+	assert.Equal(t, &backend.Location{Address: "Austin, TX, USA"}, record.Location)
+	assert.Equal(t, []string{"Weather", "Evacuation"}, record.Topics)
+
+	// Nothing is saved when the content is unchanged
+	api.AssertNumberOfCalls(t, "KVSetWithOptions", 2)
+	previous, err = store.UpdateContent(backend.Alert{
+		BackendID: "backend-1",
+		AlertID:   "alert-1",
+		Headline:  "Flooding reported across the city ",
+		Location:  &backend.Location{Address: "Austin, TX, USA"},
+		Topics:    []string{"Evacuation", "Weather"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, previous)
+	assert.Equal(t, "Flooding reported across the city", previous.Headline)
+	api.AssertNumberOfCalls(t, "KVSetWithOptions", 2)
+
+	// Nothing is saved for alerts without a post
+	previous, err = store.UpdateContent(backend.Alert{BackendID: "backend-1", AlertID: "alert-unknown", Headline: "Unknown"})
+	require.NoError(t, err)
+	assert.Nil(t, previous)
+
+	record, err = store.Get("backend-1", "alert-unknown")
+	require.NoError(t, err)
+	assert.Nil(t, record)
+}
+
+func TestStore_PutSetsTTL(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("KVGet", postKey("backend-1", "alert-1")).Return(nil, nil)
+	api.On("KVSetWithOptions", postKey("backend-1", "alert-1"), mock.Anything, model.PluginKVSetOptions{
+		Atomic:          true,
+		OldValue:        nil,
+		ExpireInSeconds: int64(TTL / time.Second),
+	}).Return(true, nil)
+
+	_, err := New(api).Put(Record{BackendID: "backend-1", AlertID: "alert-1", PostID: "post-1"})
+	require.NoError(t, err)
+}
+
+func TestStore_KeyCollision(t *testing.T) {
+	api, kv := newMemoryKVAPI()
+	store := New(api)
+
+	// Simulate another alert stored under the key of alert-1
+	kv.data[postKey("backend-1", "alert-1")] = []byte(`{"backendId":"backend-1","alertId":"alert-other","postId":"post-other"}`)
+
+	record, err := store.Get("backend-1", "alert-1")
+	require.NoError(t, err)
+	assert.Nil(t, record, "The post of another alert must not be returned")
+
+	recorded, err := store.Put(Record{BackendID: "backend-1", AlertID: "alert-1", PostID: "post-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "post-1", recorded.PostID)
+
+	record, err = store.Get("backend-1", "alert-1")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "post-1", record.PostID)
+}
+
+func TestStore_PutGivesUpAfterRepeatedConflicts(t *testing.T) {
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(nil, nil)
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+
+	_, err := New(api).Put(Record{BackendID: "backend-1", AlertID: "alert-1", PostID: "post-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too many concurrent updates")
+	api.AssertNumberOfCalls(t, "KVSetWithOptions", maxUpdateAttempts)
+}
+
+func TestStore_ConcurrentWriters(t *testing.T) {
+	t.Run("writers of the same alert agree on one post", func(t *testing.T) {
+		api, _ := newMemoryKVAPI()
+		store := New(api)
+
+		const writers = 20
+		results := make([]Record, writers)
+		var wg sync.WaitGroup
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				recorded, err := store.Put(Record{BackendID: "backend-1", AlertID: "alert-1", PostID: fmt.Sprintf("post-%d", i)})
+				assert.NoError(t, err)
+				results[i] = recorded
+			}(i)
+		}
+		wg.Wait()
+
+		stored, err := store.Get("backend-1", "alert-1")
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		for _, recorded := range results {
+			assert.Equal(t, *stored, recorded)
+		}
+	})
+
+	t.Run("writers of different channels keep every copy", func(t *testing.T) {
+		api, _ := newMemoryKVAPI()
+		store := New(api)
+
+		const writers = 20
+		var wg sync.WaitGroup
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := store.Put(Record{BackendID: "backend-1", AlertID: "alert-1", PostID: fmt.Sprintf("post-%d", i), ChannelID: fmt.Sprintf("channel-%d", i)})
+				assert.NoError(t, err)
+			}(i)
+		}
+		wg.Wait()
+
+		stored, err := store.Get("backend-1", "alert-1")
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		posts := stored.Posts()
+		assert.Len(t, posts, writers)
+		for i := 0; i < writers; i++ {
+			assert.Equal(t, fmt.Sprintf("post-%d", i), posts[fmt.Sprintf("channel-%d", i)])
+		}
+	})
+
+	t.Run("writers of different alerts keep every post", func(t *testing.T) {
+		api, _ := newMemoryKVAPI()
+		store := New(api)
+
+		const writers = 20
+		var wg sync.WaitGroup
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := store.Put(Record{BackendID: "backend-1", AlertID: fmt.Sprintf("alert-%d", i), PostID: fmt.Sprintf("post-%d", i)})
+				assert.NoError(t, err)
+			}(i)
+		}
+		wg.Wait()
+
+		for i := 0; i < writers; i++ {
+			record, err := store.Get("backend-1", fmt.Sprintf("alert-%d", i))
+			require.NoError(t, err)
+			require.NotNil(t, record)
+			assert.Equal(t, fmt.Sprintf("post-%d", i), record.PostID)
+		}
+	})
+}
+
+func TestStore_Prune(t *testing.T) {
+	api, kv := newMemoryKVAPI()
+	store := New(api)
+
+	for i := 0; i < listPageSize+5; i++ {
+		_, err := store.Put(Record{BackendID: "backend-1", AlertID: fmt.Sprintf("alert-%d", i), PostID: "post"})
+		require.NoError(t, err)
+	}
+	_, err := store.Put(Record{BackendID: "backend-2", AlertID: "alert-1", PostID: "post-kept"})
+	require.NoError(t, err)
+	kv.data["unrelated_key"] = []byte("kept")
+
+	pruned, err := store.Prune("backend-1")
+	require.NoError(t, err)
+	assert.Equal(t, listPageSize+5, pruned)
+
+	record, err := store.Get("backend-1", "alert-1")
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	record, err = store.Get("backend-2", "alert-1")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "post-kept", record.PostID)
+	assert.Contains(t, kv.data, "unrelated_key")
+}
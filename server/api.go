@@ -3,17 +3,27 @@ package main
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
 
 // ServeHTTP handles HTTP requests for the plugin.
-// All endpoints require system admin permissions.
+// All endpoints except the health check, message actions and alert deep links require system
+// admin permissions or, for backend control endpoints, the backend control access granted in
+// the plugin settings.
 func (p *Plugin) ServeHTTP(_ *plugin.Context, w http.ResponseWriter, r *http.Request) {
+	// The health check is public so external probes can call it without a session
+	if r.URL.Path == "/health" {
+		p.getHealth(w, r)
+		return
+	}
+
 	// All HTTP endpoints require a logged-in user
 	userID := r.Header.Get("Mattermost-User-ID")
 	if userID == "" {
@@ -21,14 +31,53 @@ func (p *Plugin) ServeHTTP(_ *plugin.Context, w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// All HTTP endpoints require system admin permissions
-	if !p.client.User.HasPermissionTo(userID, model.PermissionManageSystem) {
-		http.Error(w, "Not authorized", http.StatusUnauthorized)
+	// Message actions are sent on behalf of the clicking user, who needs only channel access
+	if r.URL.Path == ackActionPath {
+		p.acknowledgeAlert(w, r, userID)
+		return
+	}
+	if r.URL.Path == linkActionPath {
+		p.copyAlertLink(w, r, userID)
+		return
+	}
+	if r.URL.Path == forwardActionPath {
+		p.forwardAlert(w, r, userID)
+		return
+	}
+	if r.URL.Path == forwardDialogPath {
+		p.submitForwardAlert(w, r, userID)
+		return
+	}
+
+	// Alert deep links redirect to the alert post, whose permalink checks the user's access
+	if strings.HasPrefix(r.URL.Path, alertLinkPathPrefix) {
+		p.resolveAlertLink(w, r)
 		return
 	}
 
+	// Backend control endpoints are open to the roles and users granted backend control;
+	// configuration and simulator endpoints require system admin permissions
 	router := mux.NewRouter()
-	router.HandleFunc("/api/v1/backends/status", p.getBackendsStatus).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/overview", p.requireAccess(accessControl, p.getOverview)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/backends/status", p.requireAccess(accessControl, p.getBackendsStatus)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/startup-report", p.requireAccess(accessControl, p.getStartupReport)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/backends/{id}/history", p.requireAccess(accessControl, p.getBackendHistory)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/backends/{id}/alerts.csv", p.requireAccess(accessControl, p.exportBackendAlerts)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/backends/{id}/recent", p.requireAccess(accessControl, p.getRecentAlerts)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/backends/{id}/logs", p.requireAccess(accessControl, p.getBackendLogs)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/backends/{id}/pause", p.requireAccess(accessControl, p.pauseBackend)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/backends/{id}/resume", p.requireAccess(accessControl, p.resumeBackend)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/backends/{id}/clone", p.requireAccess(accessSystemAdmin, p.postCloneBackend)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/backends/{id}/taxonomy", p.requireAccess(accessSystemAdmin, p.getBackendTaxonomy)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/config/export", p.requireAccess(accessSystemAdmin, p.exportConfig)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/config/validate", p.requireAccess(accessSystemAdmin, p.validateConfig)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/config/import", p.requireAccess(accessSystemAdmin, p.importConfig)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/metrics", p.requireAccess(accessSystemAdmin, p.getMetrics)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/audit", p.requireAccess(accessSystemAdmin, p.getAuditLog)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/schema", p.requireAccess(accessSystemAdmin, p.getConfigSchema)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/preview", p.requireAccess(accessSystemAdmin, p.previewAlert)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/simulator/fixtures/{name}", p.requireAccess(accessSystemAdmin, p.uploadSimulatorFixture)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/simulator/fixtures/{name}", p.requireAccess(accessSystemAdmin, p.deleteSimulatorFixture)).Methods(http.MethodDelete)
 
 	router.ServeHTTP(w, r)
 }
@@ -53,3 +102,52 @@ func (p *Plugin) getBackendsStatus(w http.ResponseWriter, _ *http.Request) {
 		return
 	}
 }
+
+// backendHistoryResponse is the poll history of a backend with a summary of the requested period
+type backendHistoryResponse struct {
+	Summary backend.PollHistorySummary `json:"summary"`
+	Samples []backend.PollSample       `json:"samples"`
+}
+
+// getBackendHistory handles GET /api/v1/backends/{id}/history?hours=N and returns the poll
+// samples of the last N hours (default and maximum: the poll history retention)
+func (p *Plugin) getBackendHistory(w http.ResponseWriter, r *http.Request) {
+	b := p.registry.Get(mux.Vars(r)["id"])
+	if b == nil {
+		http.Error(w, "Backend not found", http.StatusNotFound)
+		return
+	}
+
+	period := backend.PollHistoryRetention
+	if hoursParam := r.URL.Query().Get("hours"); hoursParam != "" {
+		hours, err := strconv.Atoi(hoursParam)
+		if err != nil || hours < 1 || time.Duration(hours)*time.Hour > backend.PollHistoryRetention {
+			http.Error(w, "hours must be between 1 and "+strconv.Itoa(int(backend.PollHistoryRetention.Hours())), http.StatusBadRequest)
+			return
+		}
+		period = time.Duration(hours) * time.Hour
+	}
+
+	history, err := b.GetPollHistory()
+	if err != nil {
+		p.API.LogError("Failed to get poll history", "id", b.GetID(), "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	since := time.Now().Add(-period)
+	response := backendHistoryResponse{
+		Summary: backend.SummarizePollHistory(history, since),
+		Samples: []backend.PollSample{},
+	}
+	for _, sample := range history {
+		if !sample.Time.Before(since) {
+			response.Samples = append(response.Samples, sample)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("Failed to encode backend history response", "error", err.Error())
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestGetBackendHistory(t *testing.T) {
+	p := newCommandTestPlugin(&plugintest.API{})
+	p.registry = backend.NewRegistry()
+
+	now := time.Now()
+	b := &fakeBackend{id: "backend-1", name: "Weather Watch", history: []backend.PollSample{
+		{Time: now.Add(-5 * time.Hour), Success: true, Alerts: 3, LatencyMs: 100},
+		{Time: now.Add(-30 * time.Minute), Success: false, LatencyMs: 300, Error: "timeout"},
+		{Time: now.Add(-10 * time.Minute), Success: true, Alerts: 1, NewAlerts: 1, LatencyMs: 100},
+	}}
+	require.NoError(t, p.registry.Register(b))
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/backends/{id}/history", p.getBackendHistory).Methods(http.MethodGet)
+
+	t.Run("default period", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/backends/backend-1/history", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response backendHistoryResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Len(t, response.Samples, 3)
+		assert.Equal(t, 3, response.Summary.Polls)
+		assert.Equal(t, 1, response.Summary.Failures)
+		assert.InDelta(t, 2.0/3.0, response.Summary.SuccessRate, 0.001)
+	})
+
+	t.Run("last hour", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/backends/backend-1/history?hours=1", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response backendHistoryResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		require.Len(t, response.Samples, 2)
+		assert.Equal(t, "timeout", response.Samples[0].Error)
+		assert.Equal(t, 2, response.Summary.Polls)
+		assert.Equal(t, 0.5, response.Summary.SuccessRate)
+		assert.Equal(t, int64(200), response.Summary.AverageLatencyMs)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/backends/missing/history", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		for _, hours := range []string{"0", "25", "abc"} {
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/backends/backend-1/history?hours="+hours, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code, hours)
+		}
+	})
+}
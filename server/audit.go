@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/audit"
+)
+
+const (
+	// defaultAuditLimit is how many audit entries GET /api/v1/audit returns by default
+	defaultAuditLimit = 100
+
+	// maxAuditLimit caps the audit entries returned by GET /api/v1/audit
+	maxAuditLimit = 1000
+)
+
+// logAudit records an action a user took on alert posts in the audit log of the storage
+// driver and in the server log. The plugin API can't write to the Mattermost audit log, so
+// audit entries are also log lines prefixed with "Audit:" and naming the acting user, which
+// log shipping can route to an audit store.
+func (p *Plugin) logAudit(event, userID string, keyValuePairs ...any) {
+	p.API.LogInfo("Audit: "+event, append([]any{"userId", userID}, keyValuePairs...)...)
+
+	if p.auditLog == nil {
+		return
+	}
+
+	entry := audit.Entry{Event: event, UserID: userID, Time: time.Now()}
+	for i := 0; i+1 < len(keyValuePairs); i += 2 {
+		if entry.Details == nil {
+			entry.Details = make(map[string]string)
+		}
+		entry.Details[fmt.Sprint(keyValuePairs[i])] = fmt.Sprint(keyValuePairs[i+1])
+	}
+	if err := p.auditLog.Add(entry); err != nil {
+		p.API.LogWarn("Failed to record audit entry", "event", event, "error", err.Error())
+	}
+}
+
+// getAuditLog handles GET /api/v1/audit?user_id=ID&limit=N and returns the most recent audit
+// entries, optionally of a single user
+func (p *Plugin) getAuditLog(w http.ResponseWriter, r *http.Request) {
+	if p.auditLog == nil {
+		http.Error(w, "Plugin not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := audit.Query{UserID: r.URL.Query().Get("user_id"), Limit: defaultAuditLimit}
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			http.Error(w, "limit must be between 1 and "+strconv.Itoa(maxAuditLimit), http.StatusBadRequest)
+			return
+		}
+		query.Limit = limit
+	}
+
+	entries, err := p.auditLog.List(query)
+	if err != nil {
+		p.API.LogError("Failed to list audit entries", "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if entries == nil {
+		entries = []audit.Entry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		p.API.LogError("Failed to encode audit log response", "error", err.Error())
+	}
+}
@@ -0,0 +1,186 @@
+// Package audit keeps the history of actions users took through the plugin in the plugin
+// KV store or, for large deployments, in the Mattermost database.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+)
+
+const (
+	// Retention is how long audit entries are kept
+	Retention = 90 * 24 * time.Hour
+
+	// bucketDuration is the time span covered by a single KV bucket
+	bucketDuration = 24 * time.Hour
+
+	// bucketKeyPrefix prefixes the KV key of each daily bucket
+	bucketKeyPrefix = "audit_log_"
+
+	// maxUpdateAttempts is how often a bucket update is retried when another node changed it concurrently
+	maxUpdateAttempts = 5
+)
+
+// Entry is the record of an action a user took
+type Entry struct {
+	Event   string            `json:"event"`
+	UserID  string            `json:"userId"`
+	Details map[string]string `json:"details,omitempty"`
+	Time    time.Time         `json:"time"`
+}
+
+// Query filters audit entries. Empty fields match every entry.
+type Query struct {
+	// UserID restricts results to the actions of a user
+	UserID string
+
+	// Since and Until bound the time of the action
+	Since time.Time
+	Until time.Time
+
+	// Limit caps the number of results (0 means no limit)
+	Limit int
+}
+
+// Matches reports whether an entry satisfies the query filters
+func (q Query) Matches(entry Entry) bool {
+	if q.UserID != "" && q.UserID != entry.UserID {
+		return false
+	}
+	if !q.Since.IsZero() && entry.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && entry.Time.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Store records audit entries and lists them. Log keeps them in the KV store and SQLLog in
+// the Mattermost database; both behave the same.
+type Store interface {
+	// Add records an audit entry
+	Add(entry Entry) error
+
+	// List returns the entries matching the query, most recent first.
+	// The list is limited to the retention period.
+	List(query Query) ([]Entry, error)
+}
+
+// Log stores audit entries in daily KV buckets that expire after the retention period.
+// Bucket updates use compare-and-set so multiple cluster nodes can record entries concurrently.
+type Log struct {
+	api plugin.API
+	now func() time.Time
+}
+
+// New creates a new audit log
+func New(api plugin.API) *Log {
+	return &Log{
+		api: api,
+		now: time.Now,
+	}
+}
+
+// Add records an audit entry in the bucket for its time
+func (l *Log) Add(entry Entry) error {
+	key := bucketKey(entry.Time)
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		oldData, appErr := l.api.KVGet(key)
+		if appErr != nil {
+			return fmt.Errorf("failed to get audit log bucket: %w", appErr)
+		}
+
+		entries, err := decodeBucket(oldData)
+		if err != nil {
+			return err
+		}
+
+		newData, err := json.Marshal(append(entries, entry))
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit log bucket: %w", err)
+		}
+
+		saved, appErr := l.api.KVSetWithOptions(key, newData, model.PluginKVSetOptions{
+			Atomic:          true,
+			OldValue:        oldData,
+			ExpireInSeconds: int64((Retention + bucketDuration) / time.Second),
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to save audit log bucket: %w", appErr)
+		}
+		if saved {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to save audit log bucket: too many concurrent updates")
+}
+
+// List returns the entries matching the query, most recent first.
+// The list is limited to the retention period.
+func (l *Log) List(query Query) ([]Entry, error) {
+	since, until := bounds(query, l.now())
+
+	var results []Entry
+	for bucket := until.Truncate(bucketDuration); !bucket.Before(since.Truncate(bucketDuration)); bucket = bucket.Add(-bucketDuration) {
+		data, appErr := l.api.KVGet(bucketKey(bucket))
+		if appErr != nil {
+			return nil, fmt.Errorf("failed to get audit log bucket: %w", appErr)
+		}
+
+		entries, err := decodeBucket(data)
+		if err != nil {
+			return nil, err
+		}
+
+		// Entries are appended in the order they happened, so walk each bucket backwards
+		for j := len(entries) - 1; j >= 0; j-- {
+			if entries[j].Time.Before(since) || !query.Matches(entries[j]) {
+				continue
+			}
+			results = append(results, entries[j])
+			if query.Limit > 0 && len(results) >= query.Limit {
+				return results, nil
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// bounds returns the time range of a query, limited to the retention period
+func bounds(query Query, now time.Time) (since, until time.Time) {
+	until = query.Until
+	if until.IsZero() || until.After(now) {
+		until = now
+	}
+	since = query.Since
+	if oldest := now.Add(-Retention); since.IsZero() || since.Before(oldest) {
+		since = oldest
+	}
+	return since, until
+}
+
+// bucketKey returns the KV key of the daily bucket containing a time
+func bucketKey(t time.Time) string {
+	return bucketKeyPrefix + t.UTC().Format("20060102")
+}
+
+// decodeBucket unmarshals the entries stored in a bucket (nil data is an empty bucket)
+func decodeBucket(data []byte) ([]Entry, error) {
+	var entries []Entry
+	if data == nil {
+		return entries, nil
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal audit log bucket: %w", err)
+	}
+	return entries, nil
+}
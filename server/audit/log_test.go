@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newMemoryKVAPI returns a mock API backed by an in-memory KV store supporting atomic sets
+func newMemoryKVAPI() (*plugintest.API, map[string][]byte) {
+	store := make(map[string][]byte)
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return store[key], nil
+	})
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+			if options.Atomic && !bytes.Equal(store[key], options.OldValue) {
+				return false, nil
+			}
+			store[key] = value
+			return true, nil
+		})
+	return api, store
+}
+
+func TestQuery_Matches(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	entry := Entry{Event: "alert forwarded", UserID: "user-1", Time: at}
+
+	assert.True(t, Query{}.Matches(entry))
+	assert.True(t, Query{UserID: "user-1", Since: at, Until: at}.Matches(entry))
+	assert.False(t, Query{UserID: "user-2"}.Matches(entry))
+	assert.False(t, Query{Since: at.Add(time.Second)}.Matches(entry))
+	assert.False(t, Query{Until: at.Add(-time.Second)}.Matches(entry))
+}
+
+func TestLog_Add(t *testing.T) {
+	api, store := newMemoryKVAPI()
+	log := New(api)
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, log.Add(Entry{Event: "alert forwarded", UserID: "user-1", Time: at}))
+	require.NoError(t, log.Add(Entry{Event: "alert forwarded", UserID: "user-2", Time: at.Add(time.Hour)}))
+
+	entries, err := decodeBucket(store["audit_log_20261016"])
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "user-2", entries[1].UserID)
+}
+
+func TestLog_AddConcurrentUpdate(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	api.On("KVGet", "audit_log_20261016").Return(nil, nil).Times(maxUpdateAttempts)
+	api.On("KVSetWithOptions", "audit_log_20261016", mock.Anything, mock.Anything).Return(false, nil).Times(maxUpdateAttempts)
+
+	err := New(api).Add(Entry{Event: "alert forwarded", Time: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too many concurrent updates")
+}
+
+func TestLog_List(t *testing.T) {
+	api, _ := newMemoryKVAPI()
+	log := New(api)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	log.now = func() time.Time { return now }
+
+	entries := []Entry{
+		{Event: "expired", UserID: "user-1", Time: now.Add(-Retention - time.Hour)},
+		{Event: "yesterday", UserID: "user-1", Time: now.Add(-24 * time.Hour)},
+		{Event: "other user", UserID: "user-2", Time: now.Add(-2 * time.Hour)},
+		{Event: "today", UserID: "user-1", Time: now.Add(-time.Hour)},
+	}
+	for _, entry := range entries {
+		require.NoError(t, log.Add(entry))
+	}
+
+	t.Run("most recent first within the retention period", func(t *testing.T) {
+		results, err := log.List(Query{})
+		require.NoError(t, err)
+		assert.Equal(t, []Entry{entries[3], entries[2], entries[1]}, results)
+	})
+
+	t.Run("filters and limits", func(t *testing.T) {
+		results, err := log.List(Query{UserID: "user-1", Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []Entry{entries[3]}, results)
+	})
+}
+
+// Both implementations satisfy the Store interface
+var (
+	_ Store = (*Log)(nil)
+	_ Store = (*SQLLog)(nil)
+)
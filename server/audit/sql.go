@@ -0,0 +1,169 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/sqlstore"
+)
+
+const (
+	// tableName is the table holding the entries of the SQL audit log
+	tableName = "dataminr_audit_log"
+
+	// migrationsTableName records the schema migrations applied to the SQL audit log
+	migrationsTableName = "dataminr_audit_log_migrations"
+
+	// entryColumns are the columns an entry is read from, in the order scanned by scanEntries
+	entryColumns = "event, user_id, details, created_at"
+)
+
+// migrations are the schema changes of the SQL audit log, in the order they are applied.
+// Applied migrations must never change; add a new migration instead.
+var migrations = []sqlstore.Migration{
+	{
+		Version: 1,
+		Postgres: []string{
+			`CREATE TABLE IF NOT EXISTS ` + tableName + ` (
+				id VARCHAR(26) NOT NULL PRIMARY KEY,
+				event VARCHAR(255) NOT NULL,
+				user_id VARCHAR(26) NOT NULL,
+				details TEXT NOT NULL,
+				created_at BIGINT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_dataminr_audit_log_created_at ON ` + tableName + ` (created_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_dataminr_audit_log_user_id ON ` + tableName + ` (user_id, created_at)`,
+		},
+		MySQL: []string{
+			`CREATE TABLE IF NOT EXISTS ` + tableName + ` (
+				id VARCHAR(26) NOT NULL PRIMARY KEY,
+				event VARCHAR(255) NOT NULL,
+				user_id VARCHAR(26) NOT NULL,
+				details TEXT NOT NULL,
+				created_at BIGINT NOT NULL,
+				INDEX idx_dataminr_audit_log_created_at (created_at),
+				INDEX idx_dataminr_audit_log_user_id (user_id, created_at)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+		},
+	},
+}
+
+// SQLLog stores audit entries in a table of the Mattermost database. Entries past the
+// retention period are not returned and are deleted by Prune.
+type SQLLog struct {
+	db     *sql.DB
+	driver string
+	now    func() time.Time
+	newID  func() string
+}
+
+// NewSQL creates an audit log in a Mattermost database of the given driver ("postgres" or
+// "mysql"). Migrate must be called before the log is used.
+func NewSQL(db *sql.DB, driver string) (*SQLLog, error) {
+	if err := sqlstore.CheckDriver(driver); err != nil {
+		return nil, err
+	}
+
+	return &SQLLog{
+		db:     db,
+		driver: driver,
+		now:    time.Now,
+		newID:  model.NewId,
+	}, nil
+}
+
+// Migrate applies the schema migrations not applied yet. Nodes of a cluster must not
+// migrate concurrently.
+func (l *SQLLog) Migrate() error {
+	return sqlstore.Migrate(l.db, l.driver, migrationsTableName, migrations)
+}
+
+// Add records an audit entry
+func (l *SQLLog) Add(entry Entry) error {
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+
+	_, err = l.db.Exec(sqlstore.Rebind(l.driver, `INSERT INTO `+tableName+` (id, `+entryColumns+`) VALUES (?, ?, ?, ?, ?)`),
+		l.newID(),
+		entry.Event,
+		entry.UserID,
+		string(details),
+		entry.Time.UnixMilli(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save audit entry: %w", err)
+	}
+	return nil
+}
+
+// List returns the entries matching the query, most recent first.
+// The list is limited to the retention period.
+func (l *SQLLog) List(query Query) ([]Entry, error) {
+	since, until := bounds(query, l.now())
+
+	conditions := []string{"created_at >= ?", "created_at <= ?"}
+	args := []any{since.UnixMilli(), until.UnixMilli()}
+	if query.UserID != "" {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, query.UserID)
+	}
+
+	statement := `SELECT ` + entryColumns + ` FROM ` + tableName + ` WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY created_at DESC`
+	if query.Limit > 0 {
+		statement += " LIMIT " + strconv.Itoa(query.Limit)
+	}
+
+	rows, err := l.db.Query(sqlstore.Rebind(l.driver, statement), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	return scanEntries(rows)
+}
+
+// Prune deletes the entries recorded before a time, e.g. past the retention period.
+// Returns the number of deleted entries.
+func (l *SQLLog) Prune(before time.Time) (int64, error) {
+	result, err := l.db.Exec(sqlstore.Rebind(l.driver, `DELETE FROM `+tableName+` WHERE created_at < ?`), before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune audit log: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune audit log: %w", err)
+	}
+	return deleted, nil
+}
+
+// scanEntries reads the entries of the rows of a query and closes them
+func scanEntries(rows *sql.Rows) ([]Entry, error) {
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var entry Entry
+		var details string
+		var createdAt int64
+		if err := rows.Scan(&entry.Event, &entry.UserID, &details, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to read audit entry: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(details), &entry.Details); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit details: %w", err)
+		}
+		entry.Time = time.UnixMilli(createdAt).UTC()
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit entries: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/sqlstore/sqlstoretest"
+)
+
+// newTestSQLLog creates an SQL audit log on a fake database at a fixed time
+func newTestSQLLog(t *testing.T, driverName string, now time.Time) (*SQLLog, *sqlstoretest.FakeDatabase) {
+	fake, db := sqlstoretest.Open(t)
+
+	log, err := NewSQL(db, driverName)
+	require.NoError(t, err)
+	log.now = func() time.Time { return now }
+	log.newID = func() string { return "entry-1" }
+	return log, fake
+}
+
+func TestNewSQL(t *testing.T) {
+	_, err := NewSQL(nil, "sqlite3")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported database driver 'sqlite3'")
+}
+
+func TestSQLLog_Migrate(t *testing.T) {
+	log, fake := newTestSQLLog(t, "postgres", time.Now())
+
+	require.NoError(t, log.Migrate())
+
+	require.Len(t, fake.Execs, 2+len(migrations[0].Postgres))
+	assert.Contains(t, fake.Execs[0].Query, "CREATE TABLE IF NOT EXISTS dataminr_audit_log_migrations")
+	assert.Contains(t, fake.Execs[1].Query, "CREATE TABLE IF NOT EXISTS dataminr_audit_log")
+}
+
+func TestSQLLog_Add(t *testing.T) {
+	log, fake := newTestSQLLog(t, "postgres", time.Now())
+	at := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	require.NoError(t, log.Add(Entry{Event: "alert forwarded", UserID: "user-1", Details: map[string]string{"postId": "post-1"}, Time: at}))
+
+	require.Len(t, fake.Execs, 1)
+	assert.Equal(t, "INSERT INTO dataminr_audit_log (id, event, user_id, details, created_at) VALUES ($1, $2, $3, $4, $5)", fake.Execs[0].Query)
+	assert.Equal(t, []driver.Value{"entry-1", "alert forwarded", "user-1", `{"postId":"post-1"}`, at.UnixMilli()}, fake.Execs[0].Args)
+}
+
+func TestSQLLog_List(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	t.Run("filters in the database", func(t *testing.T) {
+		log, fake := newTestSQLLog(t, "mysql", now)
+
+		_, err := log.List(Query{UserID: "user-1", Limit: 10})
+		require.NoError(t, err)
+
+		require.Len(t, fake.Queries, 1)
+		assert.Equal(t, "SELECT event, user_id, details, created_at FROM dataminr_audit_log WHERE created_at >= ? AND created_at <= ?"+
+			" AND user_id = ? ORDER BY created_at DESC LIMIT 10", fake.Queries[0].Query)
+		assert.Equal(t, []driver.Value{now.Add(-Retention).UnixMilli(), now.UnixMilli(), "user-1"}, fake.Queries[0].Args)
+	})
+
+	t.Run("reads the entries", func(t *testing.T) {
+		log, fake := newTestSQLLog(t, "postgres", now)
+		at := now.Add(-time.Hour)
+		fake.Rows["FROM dataminr_audit_log"] = [][]driver.Value{{"alert forwarded", "user-1", `{"postId":"post-1"}`, at.UnixMilli()}}
+
+		entries, err := log.List(Query{})
+		require.NoError(t, err)
+		assert.Equal(t, []Entry{{Event: "alert forwarded", UserID: "user-1", Details: map[string]string{"postId": "post-1"}, Time: at}}, entries)
+	})
+}
+
+func TestSQLLog_Prune(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	log, fake := newTestSQLLog(t, "postgres", now)
+	fake.RowsAffected = 2
+
+	deleted, err := log.Prune(now.Add(-Retention))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.Equal(t, "DELETE FROM dataminr_audit_log WHERE created_at < $1", fake.Execs[0].Query)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/audit"
+)
+
+func TestLogAudit(t *testing.T) {
+	t.Run("server log only before activation", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("LogInfo", "Audit: alert forwarded", "userId", "user-1", "postId", "post-1").Once()
+
+		p := &Plugin{}
+		p.SetAPI(api)
+		p.logAudit("alert forwarded", "user-1", "postId", "post-1")
+	})
+
+	t.Run("records the entry in the audit log", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		mockMemoryKV(api)
+		api.On("LogInfo", "Audit: alert forwarded", "userId", "user-1", "postId", "post-1", "channels", 2).Once()
+
+		p := &Plugin{}
+		p.SetAPI(api)
+		p.auditLog = audit.New(api)
+		p.logAudit("alert forwarded", "user-1", "postId", "post-1", "channels", 2)
+
+		entries, err := p.auditLog.List(audit.Query{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "alert forwarded", entries[0].Event)
+		assert.Equal(t, "user-1", entries[0].UserID)
+		assert.Equal(t, map[string]string{"postId": "post-1", "channels": "2"}, entries[0].Details)
+	})
+}
+
+func TestGetAuditLog(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	mockMemoryKV(api)
+
+	p := &Plugin{}
+	p.SetAPI(api)
+	p.auditLog = audit.New(api)
+	now := time.Now()
+	require.NoError(t, p.auditLog.Add(audit.Entry{Event: "alert forwarded", UserID: "user-1", Time: now.Add(-time.Minute)}))
+	require.NoError(t, p.auditLog.Add(audit.Entry{Event: "alert forwarded", UserID: "user-2", Time: now}))
+
+	t.Run("filters by user", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		p.getAuditLog(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit?user_id=user-1", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var entries []audit.Entry
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		require.Len(t, entries, 1)
+		assert.Equal(t, "user-1", entries[0].UserID)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		p.getAuditLog(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit?limit=0", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,42 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AckSLASettings adds an Acknowledge button to Flash alert posts and sends a reminder when
+// an alert is not acknowledged within the window
+type AckSLASettings struct {
+	// WindowMinutes is how long a Flash alert may stay unacknowledged before the reminder
+	// (default: DefaultAckWindowMinutes)
+	WindowMinutes int `json:"windowMinutes,omitempty"`
+
+	// OnCallUsername is the user sent a direct message along with the reminder (optional)
+	OnCallUsername string `json:"onCallUsername,omitempty"`
+}
+
+// Validate checks that the window is within range.
+func (a *AckSLASettings) Validate() error {
+	if a.WindowMinutes < 0 || a.WindowMinutes > MaxAckWindowMinutes {
+		return fmt.Errorf("acknowledgment window must be between 0 and %d minutes (got %d)", MaxAckWindowMinutes, a.WindowMinutes)
+	}
+	if strings.ContainsAny(strings.TrimPrefix(a.OnCallUsername, "@"), "@ ") {
+		return fmt.Errorf("invalid on-call username '%s'", a.OnCallUsername)
+	}
+	return nil
+}
+
+// Window returns how long an alert may stay unacknowledged, applying the default when unset
+func (a AckSLASettings) Window() time.Duration {
+	if a.WindowMinutes <= 0 {
+		return DefaultAckWindowMinutes * time.Minute
+	}
+	return time.Duration(a.WindowMinutes) * time.Minute
+}
+
+// OnCallUser returns the on-call username without a leading @ (empty if unset)
+func (a AckSLASettings) OnCallUser() string {
+	return strings.TrimPrefix(a.OnCallUsername, "@")
+}
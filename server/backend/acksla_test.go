@@ -0,0 +1,36 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckSLASettings_Validate(t *testing.T) {
+	require.NoError(t, (&AckSLASettings{}).Validate())
+	require.NoError(t, (&AckSLASettings{WindowMinutes: 30, OnCallUsername: "@oncall"}).Validate())
+
+	err := (&AckSLASettings{WindowMinutes: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "acknowledgment window must be between 0 and 1440 minutes (got -1)")
+
+	err = (&AckSLASettings{WindowMinutes: MaxAckWindowMinutes + 1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "acknowledgment window must be between 0 and 1440 minutes (got 1441)")
+
+	err = (&AckSLASettings{OnCallUsername: "on call"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid on-call username 'on call'")
+}
+
+func TestAckSLASettings_Defaults(t *testing.T) {
+	settings := AckSLASettings{}
+	assert.Equal(t, 15*time.Minute, settings.Window())
+	assert.Empty(t, settings.OnCallUser())
+
+	settings = AckSLASettings{WindowMinutes: 5, OnCallUsername: "@oncall"}
+	assert.Equal(t, 5*time.Minute, settings.Window())
+	assert.Equal(t, "oncall", settings.OnCallUser())
+}
@@ -1,6 +1,9 @@
 package backend
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Location represents geographic location data for an alert.
 type Location struct {
@@ -15,11 +18,17 @@ type Location struct {
 
 	// ConfidenceRadius is the uncertainty radius in meters
 	ConfidenceRadius float64 `json:"confidenceRadius,omitempty"`
+
+	// MGRS is the Military Grid Reference System coordinate (if available)
+	MGRS string `json:"mgrs,omitempty"`
 }
 
 // Alert represents a normalized alert from any backend type.
 // This is the common format that all backends must convert their alerts into.
 type Alert struct {
+	// BackendID is the ID of the backend that produced this alert
+	BackendID string `json:"backendId"`
+
 	// BackendName is the name of the backend that produced this alert
 	BackendName string `json:"backendName"`
 
@@ -53,16 +62,48 @@ type Alert struct {
 	// LinkedAlerts is a list of related alert IDs
 	LinkedAlerts []string `json:"linkedAlerts,omitempty"`
 
+	// ParentAlertIDs are the IDs of the parent alerts of the linked alert chains this alert
+	// belongs to, used to group related alerts into incidents
+	ParentAlertIDs []string `json:"parentAlertIds,omitempty"`
+
 	// SourceText is the original source text (may be truncated for display)
 	SourceText string `json:"sourceText,omitempty"`
 
 	// TranslatedText is the translated text (if available, may be truncated)
 	TranslatedText string `json:"translatedText,omitempty"`
 
+	// Summary is a short summary of the source or translated text, added when the backend
+	// has a summarizer and the text is long (empty otherwise)
+	Summary string `json:"summary,omitempty"`
+
+	// TranslationLanguage is the language the headline and source text were machine translated
+	// to for the channel the alert is posted in (empty if not machine translated)
+	TranslationLanguage string `json:"translationLanguage,omitempty"`
+
+	// OriginalHeadline is the headline before machine translation (empty if not machine translated)
+	OriginalHeadline string `json:"originalHeadline,omitempty"`
+
 	// PublicSourceURL is a link to the public source (if available)
 	PublicSourceURL string `json:"publicSourceUrl,omitempty"`
 
 	// MediaURLs is a list of media URLs associated with this alert
 	// The first media URL is typically displayed as an embedded image
 	MediaURLs []string `json:"mediaUrls,omitempty"`
+
+	// RawPayload is the original alert JSON from the backend.
+	// Only set when the backend is configured to attach raw payloads to alert posts.
+	RawPayload string `json:"rawPayload,omitempty"`
+}
+
+// Priority ranks the alert by type for posting order: Flash alerts (0) are posted before
+// Urgent alerts (1), which are posted before all other alerts (2).
+func (a Alert) Priority() int {
+	switch strings.ToLower(a.AlertType) {
+	case "flash":
+		return 0
+	case "urgent":
+		return 1
+	default:
+		return 2
+	}
 }
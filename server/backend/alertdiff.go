@@ -0,0 +1,92 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AlertDiff describes how an alert changed when it was reissued under the same alert ID
+type AlertDiff struct {
+	// PreviousHeadline and Headline are the headlines before and after the update (both
+	// empty if the headline didn't change)
+	PreviousHeadline string
+	Headline         string
+
+	// LocationChanged reports whether the address or coordinates changed.
+	// PreviousLocation and Location describe the locations (empty for no location).
+	LocationChanged  bool
+	PreviousLocation string
+	Location         string
+
+	// AddedTopics and RemovedTopics are the topics added to and removed from the alert
+	AddedTopics   []string
+	RemovedTopics []string
+}
+
+// IsEmpty reports whether none of the compared content changed
+func (d AlertDiff) IsEmpty() bool {
+	return d.Headline == "" && d.PreviousHeadline == "" && !d.LocationChanged &&
+		len(d.AddedTopics) == 0 && len(d.RemovedTopics) == 0
+}
+
+// Diff compares the alert with an updated version of it: its headline, location and topics.
+// Whitespace around the headline and address is ignored, and coordinates are compared to
+// three decimals (about 100m) like the content fingerprint.
+func (a Alert) Diff(updated Alert) AlertDiff {
+	var diff AlertDiff
+
+	if previous, current := strings.TrimSpace(a.Headline), strings.TrimSpace(updated.Headline); previous != current {
+		diff.PreviousHeadline = previous
+		diff.Headline = current
+	}
+
+	if locationKey(a.Location) != locationKey(updated.Location) {
+		diff.LocationChanged = true
+		diff.PreviousLocation = describeLocation(a.Location)
+		diff.Location = describeLocation(updated.Location)
+	}
+
+	diff.AddedTopics = missingTopics(updated.Topics, a.Topics)
+	diff.RemovedTopics = missingTopics(a.Topics, updated.Topics)
+	return diff
+}
+
+// locationKey returns the compared content of a location (empty for no location)
+func locationKey(location *Location) string {
+	if describeLocation(location) == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s|%.3f|%.3f", strings.TrimSpace(location.Address), location.Latitude, location.Longitude)
+}
+
+// describeLocation returns the address of a location, or its coordinates if it has no
+// address (empty for no location)
+func describeLocation(location *Location) string {
+	if location == nil {
+		return ""
+	}
+	if address := strings.TrimSpace(location.Address); address != "" {
+		return address
+	}
+	if location.Latitude == 0 && location.Longitude == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.4f, %.4f", location.Latitude, location.Longitude)
+}
+
+// missingTopics returns the topics that are not in others, in their order
+func missingTopics(topics, others []string) []string {
+	known := make(map[string]bool, len(others))
+	for _, topic := range others {
+		known[topic] = true
+	}
+
+	var missing []string
+	for _, topic := range topics {
+		if !known[topic] {
+			missing = append(missing, topic)
+			known[topic] = true
+		}
+	}
+	return missing
+}
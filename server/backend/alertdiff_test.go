@@ -0,0 +1,90 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlert_Diff(t *testing.T) {
+	alert := Alert{
+		AlertID:  "alert-1",
+		Headline: "Flooding reported downtown",
+		Location: &Location{Address: "Austin, TX, USA", Latitude: 30.2672, Longitude: -97.7431},
+		Topics:   []string{"Weather", "Flooding"},
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		updated := alert
+		updated.Headline = " Flooding reported downtown "
+		updated.Location = &Location{Address: "Austin, TX, USA", Latitude: 30.26721, Longitude: -97.74312}
+		updated.Topics = []string{"Flooding", "Weather"}
+		updated.SubHeadline = "Not compared"
+
+		diff := alert.Diff(updated)
+		assert.True(t, diff.IsEmpty())
+		assert.Equal(t, AlertDiff{}, diff)
+	})
+
+	t.Run("headline changed", func(t *testing.T) {
+		updated := alert
+		updated.Headline = "Flooding reported across the city"
+
+		diff := alert.Diff(updated)
+		assert.False(t, diff.IsEmpty())
+		assert.Equal(t, AlertDiff{
+			PreviousHeadline: "Flooding reported downtown",
+			Headline:         "Flooding reported across the city",
+		}, diff)
+	})
+
+	t.Run("location changed", func(t *testing.T) {
+		updated := alert
+		updated.Location = &Location{Address: "Round Rock, TX, USA", Latitude: 30.5083, Longitude: -97.6789}
+
+		diff := alert.Diff(updated)
+		assert.Equal(t, AlertDiff{
+			LocationChanged:  true,
+			PreviousLocation: "Austin, TX, USA",
+			Location:         "Round Rock, TX, USA",
+		}, diff)
+	})
+
+	t.Run("coordinates moved", func(t *testing.T) {
+		previous := alert
+		previous.Location = &Location{Latitude: 30.2672, Longitude: -97.7431}
+		updated := alert
+		updated.Location = &Location{Latitude: 30.2872, Longitude: -97.7431}
+
+		diff := previous.Diff(updated)
+		assert.True(t, diff.LocationChanged)
+		assert.Equal(t, "30.2672, -97.7431", diff.PreviousLocation)
+		assert.Equal(t, "30.2872, -97.7431", diff.Location)
+	})
+
+	t.Run("location added and removed", func(t *testing.T) {
+		previous := alert
+		previous.Location = nil
+
+		diff := previous.Diff(alert)
+		assert.True(t, diff.LocationChanged)
+		assert.Empty(t, diff.PreviousLocation)
+		assert.Equal(t, "Austin, TX, USA", diff.Location)
+
+		diff = alert.Diff(previous)
+		assert.True(t, diff.LocationChanged)
+		assert.Equal(t, "Austin, TX, USA", diff.PreviousLocation)
+		assert.Empty(t, diff.Location)
+	})
+
+	t.Run("topics changed", func(t *testing.T) {
+		updated := alert
+		updated.Topics = []string{"Flooding", "Evacuation", "Road Closure", "Evacuation"}
+
+		diff := alert.Diff(updated)
+		assert.Equal(t, []string{"Evacuation", "Road Closure"}, diff.AddedTopics)
+		assert.Equal(t, []string{"Weather"}, diff.RemovedTopics)
+		assert.False(t, diff.LocationChanged)
+		assert.Empty(t, diff.Headline)
+	})
+}
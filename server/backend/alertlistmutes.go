@@ -0,0 +1,157 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+)
+
+const (
+	// kvKeyChannelAlertListMutes stores the alert lists muted in a channel, keyed by channel ID
+	kvKeyChannelAlertListMutes = "channel_%s_alert_list_mutes"
+
+	// kvKeyBackendAlertListMutes stores the alert lists muted for every channel of a backend,
+	// keyed by backend ID
+	kvKeyBackendAlertListMutes = "backend_%s_alert_list_mutes"
+)
+
+// AlertListMute suppresses the alerts belonging to a Dataminr alert list until it expires
+type AlertListMute struct {
+	// AlertList is the name of the muted alert list, matched case-insensitively
+	AlertList string `json:"alertList"`
+
+	// Until is when the mute expires
+	Until time.Time `json:"until"`
+
+	// CreatedBy is the user who muted the alert list
+	CreatedBy string `json:"createdBy"`
+}
+
+// AlertListMuteScope is where an alert list is muted: every channel of a backend when
+// BackendID is set, otherwise a single channel
+type AlertListMuteScope struct {
+	ChannelID string
+	BackendID string
+}
+
+// key returns the KV key of the mutes of the scope
+func (s AlertListMuteScope) key() string {
+	if s.BackendID != "" {
+		return fmt.Sprintf(kvKeyBackendAlertListMutes, s.BackendID)
+	}
+	return fmt.Sprintf(kvKeyChannelAlertListMutes, s.ChannelID)
+}
+
+// MutedAlertList returns the first active mute matching one of the alert lists of the alert,
+// or nil if none match
+func MutedAlertList(mutes []AlertListMute, alert Alert, now time.Time) *AlertListMute {
+	for i, mute := range mutes {
+		if !now.Before(mute.Until) {
+			continue
+		}
+		for _, list := range alert.AlertLists {
+			if strings.EqualFold(list, mute.AlertList) {
+				return &mutes[i]
+			}
+		}
+	}
+	return nil
+}
+
+// AlertListMuteStore keeps the alert list mutes of channels and backends in the plugin KV store
+type AlertListMuteStore struct {
+	api plugin.API
+}
+
+// NewAlertListMuteStore creates an alert list mute store
+func NewAlertListMuteStore(api plugin.API) *AlertListMuteStore {
+	return &AlertListMuteStore{api: api}
+}
+
+// List returns the active alert list mutes of a scope. Expired mutes are left out.
+func (s *AlertListMuteStore) List(scope AlertListMuteScope, now time.Time) ([]AlertListMute, error) {
+	data, appErr := s.api.KVGet(scope.key())
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get alert list mutes: %w", appErr)
+	}
+
+	if data == nil {
+		return []AlertListMute{}, nil
+	}
+
+	var mutes []AlertListMute
+	if err := json.Unmarshal(data, &mutes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert list mutes: %w", err)
+	}
+
+	active := make([]AlertListMute, 0, len(mutes))
+	for _, mute := range mutes {
+		if now.Before(mute.Until) {
+			active = append(active, mute)
+		}
+	}
+	return active, nil
+}
+
+// Mute mutes an alert list in a scope, replacing the expiry of an existing mute of the same
+// alert list. Expired mutes are cleaned up.
+func (s *AlertListMuteStore) Mute(scope AlertListMuteScope, mute AlertListMute, now time.Time) error {
+	mutes, err := s.List(scope, now)
+	if err != nil {
+		return err
+	}
+
+	for i, existing := range mutes {
+		if strings.EqualFold(existing.AlertList, mute.AlertList) {
+			mutes[i] = mute
+			return s.save(scope, mutes)
+		}
+	}
+
+	return s.save(scope, append(mutes, mute))
+}
+
+// Unmute removes an alert list mute from a scope.
+// Returns true if the alert list was muted.
+func (s *AlertListMuteStore) Unmute(scope AlertListMuteScope, alertList string, now time.Time) (bool, error) {
+	mutes, err := s.List(scope, now)
+	if err != nil {
+		return false, err
+	}
+
+	remaining := make([]AlertListMute, 0, len(mutes))
+	for _, existing := range mutes {
+		if !strings.EqualFold(existing.AlertList, alertList) {
+			remaining = append(remaining, existing)
+		}
+	}
+
+	if len(remaining) == len(mutes) {
+		return false, nil
+	}
+	return true, s.save(scope, remaining)
+}
+
+// save stores the alert list mutes of a scope (an empty list deletes the key)
+func (s *AlertListMuteStore) save(scope AlertListMuteScope, mutes []AlertListMute) error {
+	key := scope.key()
+	if len(mutes) == 0 {
+		if appErr := s.api.KVDelete(key); appErr != nil {
+			return fmt.Errorf("failed to clear alert list mutes: %w", appErr)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(mutes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert list mutes: %w", err)
+	}
+
+	if appErr := s.api.KVSet(key, data); appErr != nil {
+		return fmt.Errorf("failed to save alert list mutes: %w", appErr)
+	}
+	return nil
+}
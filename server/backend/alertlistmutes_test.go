@@ -0,0 +1,104 @@
+package backend
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutedAlertList(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	alert := Alert{AlertLists: []string{"Gulf Coast Weather", "Critical Infrastructure"}}
+
+	mutes := []AlertListMute{
+		{AlertList: "critical infrastructure", Until: now.Add(-time.Minute)},
+		{AlertList: "gulf coast weather", Until: now.Add(time.Hour)},
+	}
+
+	mute := MutedAlertList(mutes, alert, now)
+	require.NotNil(t, mute)
+	assert.Equal(t, "gulf coast weather", mute.AlertList)
+
+	assert.Nil(t, MutedAlertList(mutes[:1], alert, now), "expired mutes are ignored")
+	assert.Nil(t, MutedAlertList(mutes, Alert{AlertLists: []string{"Wildfires"}}, now))
+	assert.Nil(t, MutedAlertList(mutes, Alert{Topics: []string{"Gulf Coast Weather"}}, now), "topics are not alert lists")
+}
+
+func TestAlertListMuteStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("scopes are stored separately", func(t *testing.T) {
+		assert.Equal(t, "channel_channel-1_alert_list_mutes", AlertListMuteScope{ChannelID: "channel-1"}.key())
+		assert.Equal(t, "backend_backend-1_alert_list_mutes", AlertListMuteScope{BackendID: "backend-1"}.key())
+		assert.Equal(t, "backend_backend-1_alert_list_mutes", AlertListMuteScope{ChannelID: "channel-1", BackendID: "backend-1"}.key())
+	})
+
+	t.Run("lists active mutes only", func(t *testing.T) {
+		api := &plugintest.API{}
+		data, err := json.Marshal([]AlertListMute{
+			{AlertList: "Wildfires", Until: now.Add(-time.Hour)},
+			{AlertList: "Gulf Coast Weather", Until: now.Add(time.Hour)},
+		})
+		require.NoError(t, err)
+		api.On("KVGet", "channel_channel-1_alert_list_mutes").Return(data, nil)
+
+		mutes, err := NewAlertListMuteStore(api).List(AlertListMuteScope{ChannelID: "channel-1"}, now)
+		require.NoError(t, err)
+		require.Len(t, mutes, 1)
+		assert.Equal(t, "Gulf Coast Weather", mutes[0].AlertList)
+	})
+
+	t.Run("mute, extend and unmute", func(t *testing.T) {
+		const key = "backend_backend-1_alert_list_mutes"
+		scope := AlertListMuteScope{BackendID: "backend-1"}
+
+		api := &plugintest.API{}
+		var stored []byte
+		api.On("KVGet", key).Return(func(string) []byte { return stored }, nil)
+		api.On("KVSet", key, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).([]byte)
+		}).Return(nil)
+		api.On("KVDelete", key).Run(func(mock.Arguments) { stored = nil }).Return(nil)
+
+		store := NewAlertListMuteStore(api)
+		require.NoError(t, store.Mute(scope, AlertListMute{AlertList: "Gulf Coast Weather", Until: now.Add(time.Hour)}, now))
+		require.NoError(t, store.Mute(scope, AlertListMute{AlertList: "gulf coast weather", Until: now.Add(2 * time.Hour)}, now))
+
+		mutes, err := store.List(scope, now)
+		require.NoError(t, err)
+		require.Len(t, mutes, 1)
+		assert.Equal(t, now.Add(2*time.Hour), mutes[0].Until)
+
+		removed, err := store.Unmute(scope, "GULF COAST WEATHER", now)
+		require.NoError(t, err)
+		assert.True(t, removed)
+		assert.Nil(t, stored)
+
+		removed, err = store.Unmute(scope, "Gulf Coast Weather", now)
+		require.NoError(t, err)
+		assert.False(t, removed)
+	})
+
+	t.Run("expired mutes are cleaned up", func(t *testing.T) {
+		const key = "channel_channel-1_alert_list_mutes"
+		expired, err := json.Marshal([]AlertListMute{{AlertList: "Wildfires", Until: now.Add(-time.Hour)}})
+		require.NoError(t, err)
+
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("KVGet", key).Return(expired, nil)
+		api.On("KVSet", key, mock.MatchedBy(func(data []byte) bool {
+			var mutes []AlertListMute
+			require.NoError(t, json.Unmarshal(data, &mutes))
+			return len(mutes) == 1 && mutes[0].AlertList == "Gulf Coast Weather"
+		})).Return(nil).Once()
+
+		require.NoError(t, NewAlertListMuteStore(api).Mute(AlertListMuteScope{ChannelID: "channel-1"},
+			AlertListMute{AlertList: "Gulf Coast Weather", Until: now.Add(time.Hour)}, now))
+	})
+}
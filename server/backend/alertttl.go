@@ -0,0 +1,73 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// AlertTTLActionStrikethrough crosses out the headline of expired alert posts and greys them out
+	AlertTTLActionStrikethrough = "strikethrough"
+
+	// AlertTTLActionDelete deletes expired alert posts
+	AlertTTLActionDelete = "delete"
+)
+
+// AlertTTLSettings expire alert posts a number of hours after they were posted, e.g. to clear
+// informational alerts such as weather advisories from a channel. Each alert type has its own
+// TTL; alert types without one are kept.
+type AlertTTLSettings struct {
+	// Action is what happens to expired alert posts: AlertTTLActionStrikethrough (default)
+	// or AlertTTLActionDelete
+	Action string `json:"action,omitempty"`
+
+	// FlashHours, UrgentHours and AlertHours are the TTL of each alert type (0 keeps its posts)
+	FlashHours  int `json:"flashHours,omitempty"`
+	UrgentHours int `json:"urgentHours,omitempty"`
+	AlertHours  int `json:"alertHours,omitempty"`
+}
+
+// Validate checks that the action is supported and the TTLs are within range.
+func (s *AlertTTLSettings) Validate() error {
+	if s.Action != "" && s.Action != AlertTTLActionStrikethrough && s.Action != AlertTTLActionDelete {
+		return fmt.Errorf("invalid alert TTL action '%s' (must be %s or %s)", s.Action, AlertTTLActionStrikethrough, AlertTTLActionDelete)
+	}
+
+	for _, ttl := range []struct {
+		alertType string
+		hours     int
+	}{
+		{"Flash", s.FlashHours},
+		{"Urgent", s.UrgentHours},
+		{"Alert", s.AlertHours},
+	} {
+		if ttl.hours < 0 || ttl.hours > MaxAlertTTLHours {
+			return fmt.Errorf("TTL of %s alerts must be between 0 and %d hours (got %d)", ttl.alertType, MaxAlertTTLHours, ttl.hours)
+		}
+	}
+	return nil
+}
+
+// ActionOrDefault returns the action for expired alert posts, applying the default when unset
+func (s AlertTTLSettings) ActionOrDefault() string {
+	if s.Action == "" {
+		return AlertTTLActionStrikethrough
+	}
+	return s.Action
+}
+
+// TTL returns how long alerts of a type are kept, or zero if they don't expire.
+// Alert types are matched case-insensitively.
+func (s AlertTTLSettings) TTL(alertType string) time.Duration {
+	var hours int
+	switch strings.ToLower(alertType) {
+	case "flash":
+		hours = s.FlashHours
+	case "urgent":
+		hours = s.UrgentHours
+	case "alert":
+		hours = s.AlertHours
+	}
+	return time.Duration(hours) * time.Hour
+}
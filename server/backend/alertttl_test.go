@@ -0,0 +1,38 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertTTLSettings_Validate(t *testing.T) {
+	require.NoError(t, (&AlertTTLSettings{}).Validate())
+	require.NoError(t, (&AlertTTLSettings{Action: AlertTTLActionDelete, AlertHours: 6, UrgentHours: MaxAlertTTLHours}).Validate())
+
+	err := (&AlertTTLSettings{Action: "archive"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid alert TTL action 'archive' (must be strikethrough or delete)")
+
+	err = (&AlertTTLSettings{FlashHours: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TTL of Flash alerts must be between 0 and 168 hours (got -1)")
+
+	err = (&AlertTTLSettings{AlertHours: MaxAlertTTLHours + 1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TTL of Alert alerts must be between 0 and 168 hours (got 169)")
+}
+
+func TestAlertTTLSettings_Defaults(t *testing.T) {
+	settings := AlertTTLSettings{AlertHours: 6}
+	assert.Equal(t, AlertTTLActionStrikethrough, settings.ActionOrDefault())
+	assert.Equal(t, 6*time.Hour, settings.TTL("Alert"))
+	assert.Equal(t, 6*time.Hour, settings.TTL("alert"))
+	assert.Zero(t, settings.TTL("Flash"))
+	assert.Zero(t, settings.TTL("Unknown"))
+
+	settings = AlertTTLSettings{Action: AlertTTLActionDelete}
+	assert.Equal(t, AlertTTLActionDelete, settings.ActionOrDefault())
+}
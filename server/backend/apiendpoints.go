@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// APIEndpointSettings overrides the API version and endpoint paths of the First Alert API,
+// so backends can follow Dataminr API upgrades or use a compatible gateway
+type APIEndpointSettings struct {
+	// AlertVersion is the alertversion requested from the alerts endpoint (default: DefaultAlertVersion)
+	AlertVersion int `json:"alertVersion,omitempty"`
+
+	// AuthPath is the path of the authentication endpoint, appended to the backend URL
+	// (default: DefaultAuthPath)
+	AuthPath string `json:"authPath,omitempty"`
+
+	// AlertsPath is the path of the alerts endpoint, appended to the backend URL
+	// (default: DefaultAlertsPath)
+	AlertsPath string `json:"alertsPath,omitempty"`
+
+	// ListsPath is the path of the alert lists endpoint, appended to the backend URL
+	// (default: DefaultListsPath)
+	ListsPath string `json:"listsPath,omitempty"`
+}
+
+// Validate checks that the alert version is not negative and the paths are well-formed.
+func (e *APIEndpointSettings) Validate() error {
+	if e.AlertVersion < 0 {
+		return fmt.Errorf("API alert version must not be negative (got %d)", e.AlertVersion)
+	}
+	if err := validateEndpointPath("auth", e.AuthPath); err != nil {
+		return err
+	}
+	if err := validateEndpointPath("alerts", e.AlertsPath); err != nil {
+		return err
+	}
+	return validateEndpointPath("lists", e.ListsPath)
+}
+
+// validateEndpointPath checks that an endpoint path is empty or an absolute path without
+// a query or fragment
+func validateEndpointPath(endpoint, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(path)
+	if err != nil || !strings.HasPrefix(path, "/") || parsed.Host != "" || parsed.RawQuery != "" || parsed.Fragment != "" ||
+		strings.ContainsAny(path, " ?#") {
+		return fmt.Errorf("API %s path must be an absolute path such as '/%s' (got '%s')", endpoint, endpoint, path)
+	}
+	return nil
+}
+
+// Version returns the requested alert version, applying the default when unset or nil
+func (e *APIEndpointSettings) Version() int {
+	if e == nil || e.AlertVersion <= 0 {
+		return DefaultAlertVersion
+	}
+	return e.AlertVersion
+}
+
+// AuthEndpoint returns the path of the authentication endpoint, applying the default when unset or nil
+func (e *APIEndpointSettings) AuthEndpoint() string {
+	if e == nil || e.AuthPath == "" {
+		return DefaultAuthPath
+	}
+	return e.AuthPath
+}
+
+// AlertsEndpoint returns the path of the alerts endpoint, applying the default when unset or nil
+func (e *APIEndpointSettings) AlertsEndpoint() string {
+	if e == nil || e.AlertsPath == "" {
+		return DefaultAlertsPath
+	}
+	return e.AlertsPath
+}
+
+// ListsEndpoint returns the path of the alert lists endpoint, applying the default when unset or nil
+func (e *APIEndpointSettings) ListsEndpoint() string {
+	if e == nil || e.ListsPath == "" {
+		return DefaultListsPath
+	}
+	return e.ListsPath
+}
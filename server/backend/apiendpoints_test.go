@@ -0,0 +1,47 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIEndpointSettings_Validate(t *testing.T) {
+	require.NoError(t, (&APIEndpointSettings{}).Validate())
+	require.NoError(t, (&APIEndpointSettings{AlertVersion: 20, AuthPath: "/gateway/auth/1/userAuthorization", AlertsPath: "/gateway/alerts/2/alerts"}).Validate())
+
+	err := (&APIEndpointSettings{AlertVersion: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "API alert version must not be negative (got -1)")
+
+	for _, path := range []string{"alerts/1/alerts", "https://gateway.example.com/alerts", "/alerts?alertversion=19", "/alerts#top", "/my alerts"} {
+		err = (&APIEndpointSettings{AlertsPath: path}).Validate()
+		require.Error(t, err, path)
+		assert.Contains(t, err.Error(), "API alerts path must be an absolute path")
+	}
+
+	err = (&APIEndpointSettings{ListsPath: "/lists?all=true"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "API lists path must be an absolute path such as '/lists'")
+
+	err = (&APIEndpointSettings{AuthPath: "auth"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "API auth path must be an absolute path such as '/auth' (got 'auth')")
+}
+
+func TestAPIEndpointSettings_Defaults(t *testing.T) {
+	var unset *APIEndpointSettings
+	assert.Equal(t, DefaultAlertVersion, unset.Version())
+	assert.Equal(t, DefaultAuthPath, unset.AuthEndpoint())
+	assert.Equal(t, DefaultAlertsPath, unset.AlertsEndpoint())
+	assert.Equal(t, DefaultListsPath, unset.ListsEndpoint())
+
+	assert.Equal(t, DefaultAlertVersion, (&APIEndpointSettings{}).Version())
+
+	settings := &APIEndpointSettings{AlertVersion: 20, AuthPath: "/v2/auth", AlertsPath: "/v2/alerts", ListsPath: "/v2/lists"}
+	assert.Equal(t, 20, settings.Version())
+	assert.Equal(t, "/v2/auth", settings.AuthEndpoint())
+	assert.Equal(t, "/v2/alerts", settings.AlertsEndpoint())
+	assert.Equal(t, "/v2/lists", settings.ListsEndpoint())
+}
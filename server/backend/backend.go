@@ -11,7 +11,7 @@ type Config struct {
 	// Name is the display name for this backend (mutable, must be unique)
 	Name string `json:"name"`
 
-	// Type is the backend type (e.g., "dataminr")
+	// Type is the backend type (e.g., "dataminr", or SimulatorType to replay a fixture)
 	Type string `json:"type"`
 
 	// Enabled indicates whether this backend should be actively polling
@@ -23,14 +23,229 @@ type Config struct {
 	// APIId is the API user ID or client ID (backend-specific)
 	APIId string `json:"apiId"`
 
-	// APIKey is the API key or password (backend-specific)
+	// APIKey is the API key or password (backend-specific). Plaintext keys are moved to the
+	// encrypted secret store and blanked; "env:NAME" references an environment variable instead.
 	APIKey string `json:"apiKey"`
 
+	// APIKeyStored indicates the API key is kept in the encrypted secret store
+	APIKeyStored bool `json:"apiKeyStored,omitempty"`
+
+	// TLS optionally configures custom CAs and a client certificate for the API connection
+	TLS *TLSSettings `json:"tls,omitempty"`
+
+	// ProxyURL optionally routes API traffic through an HTTP(S) or SOCKS5 proxy
+	ProxyURL string `json:"proxyUrl,omitempty"`
+
+	// ProxyUsername and ProxyPassword optionally authenticate with the proxy, instead of credentials
+	// in ProxyURL. A plaintext password is moved to the encrypted secret store and blanked like the
+	// API key; "env:NAME" references an environment variable instead.
+	ProxyUsername string `json:"proxyUsername,omitempty"`
+	ProxyPassword string `json:"proxyPassword,omitempty"`
+
+	// ProxyPasswordStored indicates the proxy password is kept in the encrypted secret store
+	ProxyPasswordStored bool `json:"proxyPasswordStored,omitempty"`
+
+	// DebugHTTPLogging logs sanitized API request and response details at debug level
+	DebugHTTPLogging bool `json:"debugHttpLogging,omitempty"`
+
+	// LogLevel is the minimum level of the backend's log lines written to the server log and
+	// kept in its in-memory log capture ("debug", "info", "warn" or "error"; empty writes every
+	// line to the server log and captures info and above)
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// AlertListIDs optionally restricts polling to these alert lists (watchlists).
+	// Empty polls every list available to the account.
+	AlertListIDs []string `json:"alertListIds,omitempty"`
+
 	// ChannelID is the Mattermost channel ID to post alerts to
 	ChannelID string `json:"channelId"`
 
+	// AttachRawPayload posts the original alert JSON as a thread reply under each alert
+	AttachRawPayload bool `json:"attachRawPayload,omitempty"`
+
+	// DryRun runs fetched alerts through the whole pipeline but only logs the alerts that would
+	// have been posted, e.g. to validate a new backend's filters against live traffic
+	DryRun bool `json:"dryRun,omitempty"`
+
 	// PollIntervalSeconds is how often to poll this backend (minimum: MinPollIntervalSeconds)
 	PollIntervalSeconds int `json:"pollIntervalSeconds"`
+
+	// StartupJitterSeconds is the maximum random delay added to the first poll after the
+	// backend starts, on top of the automatic stagger within the poll interval
+	StartupJitterSeconds int `json:"startupJitterSeconds,omitempty"`
+
+	// CatchUpHours is how far back alerts published before the backend started without a
+	// cursor are posted (0 uses DefaultCatchUpHours, maximum MaxCatchUpHours). It requires
+	// PostHistoricalAlerts, without which all of them are skipped. The window also bounds how
+	// far back polling by time window reaches after the API rejects the cursor.
+	CatchUpHours int `json:"catchUpHours,omitempty"`
+
+	// PostHistoricalAlerts posts alerts within the catch-up window instead of skipping them
+	PostHistoricalAlerts bool `json:"postHistoricalAlerts,omitempty"`
+
+	// DedupTTLHours is how long the backend's alert IDs are remembered to skip alerts that
+	// are fetched again (0 uses DefaultDedupTTLHours, maximum MaxDedupTTLHours)
+	DedupTTLHours int `json:"dedupTTLHours,omitempty"`
+
+	// FailureThreshold is how many consecutive failed polls disable the backend, or start
+	// a cool-down with a circuit breaker (0 uses MaxConsecutiveFailures, maximum MaxFailureThreshold)
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+
+	// PostConcurrency is the number of channels alerts are posted to in parallel
+	// (0 uses DefaultPostConcurrency, maximum MaxPostConcurrency). Alerts to the same channel
+	// are posted one at a time to keep their order, so it only speeds up backends routing or
+	// subscribing alerts to several channels.
+	PostConcurrency int `json:"postConcurrency,omitempty"`
+
+	// MaxAlertsPerBatch caps the alerts posted individually per poll; older alerts beyond
+	// the cap are summarized in a single post (0 uses DefaultMaxAlertsPerBatch)
+	MaxAlertsPerBatch int `json:"maxAlertsPerBatch,omitempty"`
+
+	// MediaUpload optionally downloads the first media item and uploads it to Mattermost
+	// instead of linking the original URL
+	MediaUpload *MediaUploadSettings `json:"mediaUpload,omitempty"`
+
+	// MediaBundle optionally shows several media items of an alert as images, as separate
+	// attachments or uploaded files, instead of embedding the first one and linking the rest
+	MediaBundle *MediaBundleSettings `json:"mediaBundle,omitempty"`
+
+	// ContentLimits optionally changes how much alert content is included in posts
+	ContentLimits *ContentLimits `json:"contentLimits,omitempty"`
+
+	// CircuitBreaker optionally backs off failing backends in cool-down cycles
+	// instead of disabling them after their failure threshold
+	CircuitBreaker *CircuitBreakerSettings `json:"circuitBreaker,omitempty"`
+
+	// RequestLimits optionally changes the request timeout and limits the rate of requests to the API
+	RequestLimits *RequestLimitSettings `json:"requestLimits,omitempty"`
+
+	// RequestTagging optionally adds to the User-Agent of requests to the API and sends a
+	// correlation ID with them, so the API provider's support can trace them
+	RequestTagging *RequestTaggingSettings `json:"requestTagging,omitempty"`
+
+	// Failover optionally configures standby credentials polled while the backend is
+	// auto-disabled or keeps failing to authenticate
+	Failover *FailoverSettings `json:"failover,omitempty"`
+
+	// APIEndpoints optionally overrides the API version and endpoint paths, e.g. after a
+	// Dataminr API upgrade or to use a compatible gateway
+	APIEndpoints *APIEndpointSettings `json:"apiEndpoints,omitempty"`
+
+	// QuietHours optionally holds back non-Flash alerts during configured time windows
+	QuietHours *QuietHours `json:"quietHours,omitempty"`
+
+	// Routes optionally post alerts to other channels depending on the time of day and weekday.
+	// The first active route wins; alerts go to ChannelID when none is active.
+	Routes []ChannelRoute `json:"routes,omitempty"`
+
+	// TimeDisplay optionally shows event times in a fixed timezone and with a relative duration
+	TimeDisplay *TimeDisplaySettings `json:"timeDisplay,omitempty"`
+
+	// Locale is the language of alert post field titles and the event time format
+	// (e.g., "fr"; empty uses the server's default locale)
+	Locale string `json:"locale,omitempty"`
+
+	// HashtagLocale is the language used for country and state hashtags (e.g., "fr"; empty means English)
+	HashtagLocale string `json:"hashtagLocale,omitempty"`
+
+	// Hashtags optionally customizes which hashtags are added to alert posts
+	Hashtags *HashtagSettings `json:"hashtags,omitempty"`
+
+	// TopicStyles optionally customize the emoji and color of alerts by topic or alert list
+	TopicStyles []TopicStyle `json:"topicStyles,omitempty"`
+
+	// BotIdentity optionally overrides the bot name and icon shown on this backend's alert posts
+	BotIdentity *BotIdentity `json:"botIdentity,omitempty"`
+
+	// Mentions optionally lists the users, groups or channel mentions added to alert posts by alert type
+	Mentions *MentionRules `json:"mentions,omitempty"`
+
+	// AckSLA optionally adds an Acknowledge button to Flash alerts and reminds when they go unacknowledged
+	AckSLA *AckSLASettings `json:"ackSla,omitempty"`
+
+	// AlertTTL optionally strikes through or deletes alert posts of some alert types after a number of hours
+	AlertTTL *AlertTTLSettings `json:"alertTtl,omitempty"`
+
+	// Incidents optionally groups related alerts into one incident thread per channel
+	Incidents *IncidentSettings `json:"incidents,omitempty"`
+
+	// Workflow optionally starts a playbook run or creates a board card for critical alerts
+	Workflow *WorkflowSettings `json:"workflow,omitempty"`
+
+	// Summarizer optionally summarizes long alert source text with an external text generation service
+	Summarizer *SummarizerSettings `json:"summarizer,omitempty"`
+
+	// Translator optionally machine translates alerts without a Dataminr translation into channel languages
+	Translator *TranslatorSettings `json:"translator,omitempty"`
+
+	// Simulator optionally configures the fixture replayed by a simulator backend
+	Simulator *SimulatorSettings `json:"simulator,omitempty"`
+}
+
+// CatchUpWindow returns the configured catch-up window, applying the default when unset
+func (c Config) CatchUpWindow() time.Duration {
+	hours := c.CatchUpHours
+	if hours == 0 {
+		hours = DefaultCatchUpHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// DedupTTL returns how long the backend's alert IDs are deduplicated, applying the default when unset
+func (c Config) DedupTTL() time.Duration {
+	hours := c.DedupTTLHours
+	if hours == 0 {
+		hours = DefaultDedupTTLHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// MaxFailures returns the number of consecutive failed polls that disable the backend,
+// applying the default when unset
+func (c Config) MaxFailures() int {
+	if c.FailureThreshold == 0 {
+		return MaxConsecutiveFailures
+	}
+	return c.FailureThreshold
+}
+
+// Phase is the stage of a backend's polling lifecycle
+type Phase string
+
+const (
+	// PhaseStarting means the backend was started but has not completed a poll cycle yet
+	PhaseStarting Phase = "starting"
+
+	// PhaseCatchingUp means the backend is fetching the alerts available when it started without a cursor
+	PhaseCatchingUp Phase = "catching_up"
+
+	// PhasePolling means the backend is polling for new alerts normally
+	PhasePolling Phase = "polling"
+
+	// PhaseCoolingDown means polling is backing off after failures or a rate limit error
+	PhaseCoolingDown Phase = "cooling_down"
+
+	// PhaseDisabled means the backend is disabled and not polling
+	PhaseDisabled Phase = "disabled"
+)
+
+// CatchUpProgress is the progress of a catch-up, which skips (or posts) the backlog of alerts
+// available when a backend starts without a cursor
+type CatchUpProgress struct {
+	// StartedAt is when the catch-up started; alerts published since are posted as new
+	StartedAt time.Time `json:"startedAt"`
+
+	// Skipped and Posted count the backlog alerts skipped and posted so far
+	Skipped int `json:"skipped"`
+	Posted  int `json:"posted"`
+}
+
+// Equal reports whether two catch-up progress values are the same; nil only equals nil
+func (c *CatchUpProgress) Equal(other *CatchUpProgress) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return c.StartedAt.Equal(other.StartedAt) && c.Skipped == other.Skipped && c.Posted == other.Posted
 }
 
 // Status represents the current operational status of a backend instance.
@@ -38,12 +253,25 @@ type Status struct {
 	// Enabled indicates whether the backend is enabled in configuration
 	Enabled bool `json:"enabled"`
 
+	// Running indicates whether the backend was started on this server node and not stopped since
+	Running bool `json:"running"`
+
+	// Health classifies the status when it was taken (see Classify)
+	Health Health `json:"health"`
+
+	// Phase is the current stage of the polling lifecycle
+	Phase Phase `json:"phase"`
+
 	// LastPollTime is the timestamp of the last poll attempt
 	LastPollTime time.Time `json:"lastPollTime"`
 
 	// LastSuccessTime is the timestamp of the last successful poll
 	LastSuccessTime time.Time `json:"lastSuccessTime"`
 
+	// NextPollTime is when the poll job of this server node next runs (zero if not scheduled).
+	// A time in the past means a poll cycle is running or the job is stuck.
+	NextPollTime time.Time `json:"nextPollTime"`
+
 	// ConsecutiveFailures is the count of consecutive polling failures
 	ConsecutiveFailures int `json:"consecutiveFailures"`
 
@@ -52,4 +280,84 @@ type Status struct {
 
 	// LastError contains the error message from the most recent failure (empty if no error)
 	LastError string `json:"lastError"`
+
+	// Paused indicates whether polling is temporarily paused
+	Paused bool `json:"paused"`
+
+	// PausedUntil is when a pause ends automatically (zero if paused until resumed)
+	PausedUntil time.Time `json:"pausedUntil"`
+
+	// CooldownUntil is when the circuit breaker cool-down ends (zero if not cooling down)
+	CooldownUntil time.Time `json:"cooldownUntil"`
+
+	// CooldownCycles is the number of consecutive circuit breaker cool-down cycles
+	CooldownCycles int `json:"cooldownCycles"`
+
+	// CatchUp is the progress of the catch-up in progress (nil when not catching up)
+	CatchUp *CatchUpProgress `json:"catchUp,omitempty"`
+
+	// CredentialCheck is the outcome of the last credential check (nil if never checked)
+	CredentialCheck *CredentialCheck `json:"credentialCheck,omitempty"`
+
+	// RecentErrors lists the most recent polling errors, newest first (at most MaxRecentErrors)
+	RecentErrors []ErrorRecord `json:"recentErrors"`
+
+	// FailedDeliveries lists the most recent alerts dropped after failing to post
+	// MaxAlertPostAttempts times, newest first (at most MaxFailedDeliveries)
+	FailedDeliveries []FailedDelivery `json:"failedDeliveries,omitempty"`
+
+	// History summarizes the poll cycles of the last PollHistoryRetention
+	History PollHistorySummary `json:"history"`
+}
+
+// Health classifies the operational status of a backend
+type Health string
+
+const (
+	// HealthDisabled means the backend is disabled in configuration
+	HealthDisabled Health = "disabled"
+
+	// HealthPaused means polling is paused
+	HealthPaused Health = "paused"
+
+	// HealthFailing means recent polls failed or the circuit breaker is cooling down
+	HealthFailing Health = "failing"
+
+	// HealthUnauthenticated means the backend is polling without a valid auth token
+	HealthUnauthenticated Health = "unauthenticated"
+
+	// HealthHealthy means the backend is polling successfully
+	HealthHealthy Health = "healthy"
+)
+
+// Classify returns the health of the status at the given time
+func (s Status) Classify(now time.Time) Health {
+	switch {
+	case !s.Enabled:
+		return HealthDisabled
+	case s.Paused:
+		return HealthPaused
+	case s.ConsecutiveFailures > 0 || now.Before(s.CooldownUntil):
+		return HealthFailing
+	case !s.IsAuthenticated:
+		return HealthUnauthenticated
+	default:
+		return HealthHealthy
+	}
+}
+
+// ErrorRecord is a polling error with the time it occurred
+type ErrorRecord struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// FailedDelivery is an alert that was dropped after failing to post to a channel
+type FailedDelivery struct {
+	AlertID   string    `json:"alertId"`
+	Headline  string    `json:"headline"`
+	ChannelID string    `json:"channelId"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError"`
+	FailedAt  time.Time `json:"failedAt"`
 }
@@ -0,0 +1,56 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_CatchUpWindow(t *testing.T) {
+	assert.Equal(t, DefaultCatchUpHours*time.Hour, Config{}.CatchUpWindow())
+	assert.Equal(t, 2*time.Hour, Config{CatchUpHours: 2}.CatchUpWindow())
+	assert.Equal(t, 168*time.Hour, Config{CatchUpHours: 168}.CatchUpWindow())
+}
+
+func TestConfig_DedupTTL(t *testing.T) {
+	assert.Equal(t, DefaultDedupTTLHours*time.Hour, Config{}.DedupTTL())
+	assert.Equal(t, 6*time.Hour, Config{DedupTTLHours: 6}.DedupTTL())
+	assert.Equal(t, 168*time.Hour, Config{DedupTTLHours: 168}.DedupTTL())
+}
+
+func TestConfig_MaxFailures(t *testing.T) {
+	assert.Equal(t, MaxConsecutiveFailures, Config{}.MaxFailures())
+	assert.Equal(t, 10, Config{FailureThreshold: 10}.MaxFailures())
+}
+
+func TestAlert_Priority(t *testing.T) {
+	assert.Equal(t, 0, Alert{AlertType: "Flash"}.Priority())
+	assert.Equal(t, 1, Alert{AlertType: "urgent"}.Priority())
+	assert.Equal(t, 2, Alert{AlertType: "Alert"}.Priority())
+	assert.Equal(t, 2, Alert{}.Priority())
+}
+
+func TestStatus_Classify(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		status   Status
+		expected Health
+	}{
+		{"disabled", Status{Paused: true, ConsecutiveFailures: 2}, HealthDisabled},
+		{"paused", Status{Enabled: true, Paused: true, ConsecutiveFailures: 2}, HealthPaused},
+		{"failing", Status{Enabled: true, ConsecutiveFailures: 1, IsAuthenticated: true}, HealthFailing},
+		{"cooling down", Status{Enabled: true, CooldownUntil: now.Add(time.Minute), IsAuthenticated: true}, HealthFailing},
+		{"cool-down ended", Status{Enabled: true, CooldownUntil: now.Add(-time.Minute), IsAuthenticated: true}, HealthHealthy},
+		{"unauthenticated", Status{Enabled: true}, HealthUnauthenticated},
+		{"healthy", Status{Enabled: true, IsAuthenticated: true}, HealthHealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.status.Classify(now))
+		})
+	}
+}
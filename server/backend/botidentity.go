@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxBotDisplayNameLength is the longest display name allowed for a bot identity override
+const maxBotDisplayNameLength = 64
+
+// BotIdentity overrides the name and icon shown on a backend's alert posts.
+// Mattermost only honors the overrides when post username and icon overrides are enabled
+// in the system console (ServiceSettings.EnablePostUsernameOverride/EnablePostIconOverride).
+type BotIdentity struct {
+	// DisplayName replaces the bot's name on alert posts
+	DisplayName string `json:"displayName,omitempty"`
+
+	// IconURL is a profile image URL that replaces the bot's icon on alert posts
+	IconURL string `json:"iconUrl,omitempty"`
+
+	// IconEmoji is an emoji name (e.g., "cloud" or ":cloud:") used as the icon instead of an image
+	IconEmoji string `json:"iconEmoji,omitempty"`
+}
+
+// Validate checks the display name length, the icon URL format and the emoji name.
+func (b *BotIdentity) Validate() error {
+	if utf8.RuneCountInString(b.DisplayName) > maxBotDisplayNameLength {
+		return fmt.Errorf("bot display name must be at most %d characters", maxBotDisplayNameLength)
+	}
+
+	if b.IconURL != "" && b.IconEmoji != "" {
+		return fmt.Errorf("bot icon URL and icon emoji cannot both be configured")
+	}
+
+	if b.IconURL != "" {
+		parsed, err := url.Parse(b.IconURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid bot icon URL '%s' (must be an http or https URL)", b.IconURL)
+		}
+	}
+
+	if b.IconEmoji != "" {
+		name := b.EmojiName()
+		if name == "" {
+			return fmt.Errorf("bot icon emoji must not be empty")
+		}
+		for _, r := range name {
+			if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '_' && r != '-' && r != '+' {
+				return fmt.Errorf("invalid bot icon emoji '%s' (only lowercase letters, digits, '_', '-' and '+' are allowed)", b.IconEmoji)
+			}
+		}
+	}
+
+	return nil
+}
+
+// EmojiName returns the icon emoji name without surrounding colons
+func (b *BotIdentity) EmojiName() string {
+	return strings.Trim(strings.TrimSpace(b.IconEmoji), ":")
+}
@@ -0,0 +1,47 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotIdentity_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		identity    BotIdentity
+		expectedErr string
+	}{
+		{"empty", BotIdentity{}, ""},
+		{"display name only", BotIdentity{DisplayName: "Weather Watch"}, ""},
+		{"icon URL", BotIdentity{IconURL: "https://example.com/icon.png"}, ""},
+		{"icon emoji with colons", BotIdentity{IconEmoji: ":cloud_with_rain:"}, ""},
+		{"icon emoji with plus", BotIdentity{IconEmoji: "+1"}, ""},
+		{"display name too long", BotIdentity{DisplayName: strings.Repeat("a", 65)}, "bot display name must be at most 64 characters"},
+		{"icon URL and emoji", BotIdentity{IconURL: "https://example.com/icon.png", IconEmoji: "cloud"}, "cannot both be configured"},
+		{"relative icon URL", BotIdentity{IconURL: "/icon.png"}, "invalid bot icon URL '/icon.png'"},
+		{"unsupported icon URL scheme", BotIdentity{IconURL: "ftp://example.com/icon.png"}, "invalid bot icon URL"},
+		{"colons only emoji", BotIdentity{IconEmoji: "::"}, "bot icon emoji must not be empty"},
+		{"invalid emoji", BotIdentity{IconEmoji: "Cloud Rain"}, "invalid bot icon emoji 'Cloud Rain'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.identity.Validate()
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestBotIdentity_EmojiName(t *testing.T) {
+	assert.Equal(t, "cloud", (&BotIdentity{IconEmoji: ":cloud:"}).EmojiName())
+	assert.Equal(t, "cloud", (&BotIdentity{IconEmoji: " cloud "}).EmojiName())
+	assert.Equal(t, "", (&BotIdentity{}).EmojiName())
+}
@@ -0,0 +1,46 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+)
+
+// CircuitBreakerSettings replaces the immediate auto-disable after MaxConsecutiveFailures
+// with cool-down cycles: polling backs off for the cool-down period, then a single probe
+// poll decides whether normal polling resumes or another cycle starts. The backend is only
+// disabled once the maximum number of consecutive cool-down cycles is exceeded.
+type CircuitBreakerSettings struct {
+	// CooldownMinutes is how long polling backs off per cycle (default: DefaultCooldownMinutes)
+	CooldownMinutes int `json:"cooldownMinutes,omitempty"`
+
+	// MaxCooldownCycles is how many consecutive cycles may fail before the backend is
+	// disabled (default: DefaultMaxCooldownCycles)
+	MaxCooldownCycles int `json:"maxCooldownCycles,omitempty"`
+}
+
+// Validate checks that the cool-down period and cycle count are within range.
+func (c *CircuitBreakerSettings) Validate() error {
+	if c.CooldownMinutes < 0 || c.CooldownMinutes > MaxCooldownMinutes {
+		return fmt.Errorf("circuit breaker cool-down must be between 0 and %d minutes (got %d)", MaxCooldownMinutes, c.CooldownMinutes)
+	}
+	if c.MaxCooldownCycles < 0 {
+		return fmt.Errorf("circuit breaker max cool-down cycles must not be negative (got %d)", c.MaxCooldownCycles)
+	}
+	return nil
+}
+
+// Cooldown returns the cool-down period, applying the default when unset
+func (c *CircuitBreakerSettings) Cooldown() time.Duration {
+	if c.CooldownMinutes <= 0 {
+		return DefaultCooldownMinutes * time.Minute
+	}
+	return time.Duration(c.CooldownMinutes) * time.Minute
+}
+
+// MaxCycles returns the number of cool-down cycles allowed before disabling, applying the default when unset
+func (c *CircuitBreakerSettings) MaxCycles() int {
+	if c.MaxCooldownCycles <= 0 {
+		return DefaultMaxCooldownCycles
+	}
+	return c.MaxCooldownCycles
+}
@@ -0,0 +1,35 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreakerSettings_Validate(t *testing.T) {
+	require.NoError(t, (&CircuitBreakerSettings{}).Validate())
+	require.NoError(t, (&CircuitBreakerSettings{CooldownMinutes: 30, MaxCooldownCycles: 5}).Validate())
+
+	err := (&CircuitBreakerSettings{CooldownMinutes: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cool-down must be between 0 and 1440 minutes (got -1)")
+
+	err = (&CircuitBreakerSettings{CooldownMinutes: MaxCooldownMinutes + 1}).Validate()
+	require.Error(t, err)
+
+	err = (&CircuitBreakerSettings{MaxCooldownCycles: -2}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max cool-down cycles must not be negative (got -2)")
+}
+
+func TestCircuitBreakerSettings_Defaults(t *testing.T) {
+	settings := &CircuitBreakerSettings{}
+	assert.Equal(t, 15*time.Minute, settings.Cooldown())
+	assert.Equal(t, DefaultMaxCooldownCycles, settings.MaxCycles())
+
+	settings = &CircuitBreakerSettings{CooldownMinutes: 5, MaxCooldownCycles: 10}
+	assert.Equal(t, 5*time.Minute, settings.Cooldown())
+	assert.Equal(t, 10, settings.MaxCycles())
+}
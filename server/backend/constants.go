@@ -4,17 +4,215 @@ import "time"
 
 // Constants for backend behavior and thresholds
 const (
-	// MaxConsecutiveFailures is the number of consecutive polling failures
-	// before a backend is automatically disabled. This prevents runaway
-	// error conditions and excessive API calls to failing backends.
+	// MaxConsecutiveFailures is the default number of consecutive polling failures
+	// before a backend is automatically disabled, or enters a cool-down when
+	// its circuit breaker is configured. This prevents runaway error
+	// conditions and excessive API calls to failing backends.
 	MaxConsecutiveFailures = 5
 
+	// MaxFailureThreshold is the largest allowed per-backend failure threshold
+	MaxFailureThreshold = 100
+
+	// FailureWarningMargin is how many failures before the failure threshold operators
+	// are warned that the backend is about to be disabled
+	FailureWarningMargin = 2
+
+	// MaxRecentErrors is how many of the most recent polling errors are kept per backend
+	MaxRecentErrors = 5
+
+	// MaxFailedDeliveries is how many of the most recent failed alert deliveries are kept per backend
+	MaxFailedDeliveries = 10
+
+	// PollHistoryRetention is how long poll samples are kept per backend
+	PollHistoryRetention = 24 * time.Hour
+
+	// MaxPollHistorySamples caps the poll samples kept per backend, so backends with short
+	// poll intervals keep less than PollHistoryRetention of history
+	MaxPollHistorySamples = 1440
+
 	// MinPollIntervalSeconds is the minimum allowed poll interval
 	MinPollIntervalSeconds = 10
 
 	// DefaultPollIntervalSeconds is the recommended default poll interval
 	DefaultPollIntervalSeconds = 30
 
+	// DefaultCatchUpHours is how far back alerts are considered when a backend
+	// starts without a cursor, unless configured otherwise
+	DefaultCatchUpHours = 24
+
+	// MaxCatchUpHours is the largest allowed catch-up window (one week)
+	MaxCatchUpHours = 168
+
+	// DefaultDedupTTLHours is how long a backend's alert IDs are remembered to skip
+	// duplicates, unless configured otherwise
+	DefaultDedupTTLHours = 24
+
+	// MaxDedupTTLHours is the longest allowed dedup TTL (one week)
+	MaxDedupTTLHours = 168
+
+	// DefaultPostConcurrency is the default number of channels alerts are posted to in parallel
+	DefaultPostConcurrency = 4
+
+	// MaxPostConcurrency is the largest allowed posting concurrency
+	MaxPostConcurrency = 16
+
+	// DefaultMaxAlertsPerBatch is the default cap on alerts posted individually per poll;
+	// older alerts beyond the cap are combined into a summary post
+	DefaultMaxAlertsPerBatch = 200
+
+	// DefaultCooldownMinutes is the default circuit breaker cool-down period
+	DefaultCooldownMinutes = 15
+
+	// MaxCooldownMinutes is the longest allowed circuit breaker cool-down period (one day)
+	MaxCooldownMinutes = 24 * 60
+
+	// DefaultMaxCooldownCycles is the default number of consecutive failed cool-down
+	// cycles before a backend using the circuit breaker is disabled
+	DefaultMaxCooldownCycles = 3
+
+	// DefaultMaxSourceTextChars is the default length at which source and translated text is truncated
+	DefaultMaxSourceTextChars = 500
+
+	// DefaultMaxMediaLinks is the default number of additional media links listed below the embedded media
+	DefaultMaxMediaLinks = 3
+
+	// DefaultMediaUploadSizeMB is the default size limit for media uploaded to Mattermost
+	DefaultMediaUploadSizeMB = 10
+
+	// MaxMediaUploadSizeMB is the largest allowed size limit for media uploaded to Mattermost
+	MaxMediaUploadSizeMB = 50
+
+	// DefaultMediaBundleItems is the default number of media items shown as images when bundling media
+	DefaultMediaBundleItems = 4
+
+	// MaxMediaBundleItems is the largest number of media items shown as images, which is also
+	// the number of files Mattermost attaches to a post
+	MaxMediaBundleItems = 10
+
+	// DefaultMediaBundleSizeMB is the default total size limit of the media files uploaded for an alert
+	DefaultMediaBundleSizeMB = 20
+
+	// MaxMediaBundleSizeMB is the largest allowed total size limit of the media files uploaded for an alert
+	MaxMediaBundleSizeMB = 50
+
+	// DefaultRequestTimeoutSeconds is the default timeout of a single request to a backend API
+	DefaultRequestTimeoutSeconds = 30
+
+	// MaxRequestTimeoutSeconds is the longest allowed request timeout
+	MaxRequestTimeoutSeconds = 300
+
+	// MaxRequestIntervalMs is the longest allowed minimum spacing between requests (one minute)
+	MaxRequestIntervalMs = 60 * 1000
+
+	// DefaultMaxResponseSizeMB is the default size limit of a single backend API response
+	DefaultMaxResponseSizeMB = 10
+
+	// MaxResponseSizeMB is the largest allowed response size limit
+	MaxResponseSizeMB = 100
+
+	// DefaultAlertVersion is the First Alert API alert version requested by default
+	DefaultAlertVersion = 19
+
+	// DefaultAuthPath is the default path of the First Alert API authentication endpoint
+	DefaultAuthPath = "/auth/1/userAuthorization"
+
+	// DefaultAlertsPath is the default path of the First Alert API alerts endpoint
+	DefaultAlertsPath = "/alerts/1/alerts"
+
+	// DefaultListsPath is the default path of the First Alert API alert lists endpoint
+	DefaultListsPath = "/account/2/get_lists"
+
+	// DefaultFailoverAuthFailures is how many consecutive polls failing without a valid token
+	// start a backend's warm standby by default
+	DefaultFailoverAuthFailures = 3
+
 	// AuthTokenRefreshBuffer is how long before token expiry to refresh
 	AuthTokenRefreshBuffer = 5 * time.Minute
+
+	// MaxAlertPostAttempts is how many times posting an alert is attempted before it is
+	// dropped from the pending queue and recorded as a failed delivery
+	MaxAlertPostAttempts = 5
+
+	// AlertPostRetryDelay is how long a poll cycle waits before retrying an alert that failed
+	// to post; the delay doubles with each failed attempt
+	AlertPostRetryDelay = 30 * time.Second
+
+	// MaxAlertPostRetryDelay caps the delay between attempts to post an alert
+	MaxAlertPostRetryDelay = 15 * time.Minute
+
+	// ShutdownDrainTimeout is how long stopping a backend waits for an in-flight poll cycle
+	// to finish before cancelling the alerts it has not posted yet
+	ShutdownDrainTimeout = 10 * time.Second
+
+	// StopWaitTimeout is how long registering a backend waits for the backend previously
+	// registered under its ID to finish stopping
+	StopWaitTimeout = 30 * time.Second
+
+	// DefaultAckWindowMinutes is how long a Flash alert may stay unacknowledged before a reminder
+	DefaultAckWindowMinutes = 15
+
+	// MaxAckWindowMinutes is the longest allowed acknowledgment window (one day)
+	MaxAckWindowMinutes = 24 * 60
+
+	// DefaultIncidentWindowMinutes is how long after its latest alert an incident thread
+	// takes related alerts matched by location and topic
+	DefaultIncidentWindowMinutes = 60
+
+	// MaxIncidentWindowMinutes is the longest allowed incident window (one day)
+	MaxIncidentWindowMinutes = 24 * 60
+
+	// DefaultIncidentRadiusKm is how far apart the locations of alerts in an incident may be
+	DefaultIncidentRadiusKm = 10.0
+
+	// MaxIncidentRadiusKm is the largest allowed incident radius
+	MaxIncidentRadiusKm = 500.0
+
+	// MaxAlertTTLHours is the longest allowed alert TTL; posted alerts are only tracked for
+	// the alert index retention period of one week
+	MaxAlertTTLHours = 7 * 24
+
+	// DefaultWorkflowNameTemplate is the playbook run name and board card title of an alert
+	DefaultWorkflowNameTemplate = "{alertType}: {headline}"
+
+	// MaxWorkflowNameLength caps the length of playbook run names and board card titles
+	MaxWorkflowNameLength = 128
+
+	// DefaultOnCallCacheMinutes is how long a username resolved from an on-call service is reused
+	DefaultOnCallCacheMinutes = 5
+
+	// MaxOnCallCacheMinutes is the longest allowed on-call cache duration (one day)
+	MaxOnCallCacheMinutes = 24 * 60
+
+	// DefaultMentionDeferMinutes is how long the mentions of unavailable users are held back
+	DefaultMentionDeferMinutes = 60
+
+	// MaxMentionDeferMinutes is the longest allowed mention deferral (one day)
+	MaxMentionDeferMinutes = 24 * 60
+
+	// DefaultSimulatorAlertsPerPoll is how many fixture alerts a simulator backend replays per poll cycle
+	DefaultSimulatorAlertsPerPoll = 3
+
+	// MaxSimulatorAlertsPerPoll is the largest allowed number of alerts replayed per poll cycle
+	MaxSimulatorAlertsPerPoll = 40
+
+	// DefaultSummaryMinTextLength is the shortest alert text, in characters, sent to a summarizer
+	DefaultSummaryMinTextLength = 600
+
+	// MaxSummaryChars caps the length of a summary added to an alert post
+	MaxSummaryChars = 1000
+
+	// LogCaptureLines is how many of its most recent log lines each backend keeps in memory
+	LogCaptureLines = 200
+
+	// DefaultTopicMuteDuration is how long a topic stays muted in a channel when no duration is given
+	DefaultTopicMuteDuration = 24 * time.Hour
+
+	// MaxTopicMuteDuration is the longest a topic can be muted in a channel
+	MaxTopicMuteDuration = 30 * 24 * time.Hour
+
+	// DefaultAlertListMuteDuration is how long an alert list stays muted when no duration is given
+	DefaultAlertListMuteDuration = 24 * time.Hour
+
+	// MaxAlertListMuteDuration is the longest an alert list can be muted
+	MaxAlertListMuteDuration = 30 * 24 * time.Hour
 )
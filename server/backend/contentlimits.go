@@ -0,0 +1,48 @@
+package backend
+
+import "fmt"
+
+// ContentLimits caps how much alert content is included in a post.
+// Zero values apply the defaults.
+type ContentLimits struct {
+	// MaxSourceTextChars is the length at which source and translated text is truncated
+	// (default: DefaultMaxSourceTextChars)
+	MaxSourceTextChars int `json:"maxSourceTextChars,omitempty"`
+
+	// MaxMediaLinks is the number of additional media links listed below the embedded media
+	// (default: DefaultMaxMediaLinks)
+	MaxMediaLinks int `json:"maxMediaLinks,omitempty"`
+
+	// MaxTopics is the number of topics listed in the post (0 means no limit)
+	MaxTopics int `json:"maxTopics,omitempty"`
+}
+
+// Validate checks that the limits are not negative.
+func (c *ContentLimits) Validate() error {
+	if c.MaxSourceTextChars < 0 {
+		return fmt.Errorf("max source text characters must not be negative (got %d)", c.MaxSourceTextChars)
+	}
+	if c.MaxMediaLinks < 0 {
+		return fmt.Errorf("max media links must not be negative (got %d)", c.MaxMediaLinks)
+	}
+	if c.MaxTopics < 0 {
+		return fmt.Errorf("max topics must not be negative (got %d)", c.MaxTopics)
+	}
+	return nil
+}
+
+// SourceTextChars returns the source text truncation length, applying the default when unset
+func (c ContentLimits) SourceTextChars() int {
+	if c.MaxSourceTextChars <= 0 {
+		return DefaultMaxSourceTextChars
+	}
+	return c.MaxSourceTextChars
+}
+
+// MediaLinks returns the number of additional media links, applying the default when unset
+func (c ContentLimits) MediaLinks() int {
+	if c.MaxMediaLinks <= 0 {
+		return DefaultMaxMediaLinks
+	}
+	return c.MaxMediaLinks
+}
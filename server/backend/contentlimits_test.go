@@ -0,0 +1,35 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentLimits_Validate(t *testing.T) {
+	require.NoError(t, (&ContentLimits{}).Validate())
+	require.NoError(t, (&ContentLimits{MaxSourceTextChars: 2000, MaxMediaLinks: 10, MaxTopics: 5}).Validate())
+
+	err := (&ContentLimits{MaxSourceTextChars: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max source text characters must not be negative (got -1)")
+
+	err = (&ContentLimits{MaxMediaLinks: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max media links must not be negative (got -1)")
+
+	err = (&ContentLimits{MaxTopics: -3}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max topics must not be negative (got -3)")
+}
+
+func TestContentLimits_Defaults(t *testing.T) {
+	limits := ContentLimits{}
+	assert.Equal(t, 500, limits.SourceTextChars())
+	assert.Equal(t, 3, limits.MediaLinks())
+
+	limits = ContentLimits{MaxSourceTextChars: 1000, MaxMediaLinks: 1}
+	assert.Equal(t, 1000, limits.SourceTextChars())
+	assert.Equal(t, 1, limits.MediaLinks())
+}
@@ -0,0 +1,46 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCredentialCheckUnsupported is returned by backends whose source needs no credentials
+var ErrCredentialCheckUnsupported = errors.New("backend does not use credentials")
+
+// CredentialCheck is the outcome of proactively validating a backend's credentials with an
+// authentication-only call, so credential problems surface before a poll fails
+type CredentialCheck struct {
+	// CheckedAt is when the credentials were checked
+	CheckedAt time.Time `json:"checkedAt"`
+
+	// Valid indicates whether the source accepted the credentials
+	Valid bool `json:"valid"`
+
+	// Rejected indicates whether the source rejected the credentials. A check that could not
+	// complete, e.g. because the source was unreachable, is neither valid nor rejected.
+	Rejected bool `json:"rejected"`
+
+	// Error contains the error message of a failed check (empty if the credentials are valid)
+	Error string `json:"error,omitempty"`
+
+	// TokenExpiry is when the token issued by the check expires (zero if none was issued)
+	TokenExpiry time.Time `json:"tokenExpiry,omitempty"`
+}
+
+// Problem describes the credential problem an administrator should act on, or returns an
+// empty string if there is none. Rejected credentials stop every poll; a token expiring
+// within AuthTokenRefreshBuffer of being issued makes every poll authenticate again. Checks
+// that could not complete are not problems of the credentials.
+func (c CredentialCheck) Problem() string {
+	switch {
+	case c.Rejected:
+		return fmt.Sprintf("the credentials were rejected: %s", c.Error)
+	case c.Valid && !c.TokenExpiry.IsZero() && c.TokenExpiry.Sub(c.CheckedAt) <= AuthTokenRefreshBuffer:
+		return fmt.Sprintf("the issued token expires after %s, so every poll has to authenticate again",
+			c.TokenExpiry.Sub(c.CheckedAt).Round(time.Second))
+	default:
+		return ""
+	}
+}
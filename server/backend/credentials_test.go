@@ -0,0 +1,50 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialCheck_Problem(t *testing.T) {
+	checkedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		check    CredentialCheck
+		expected string
+	}{
+		{
+			name:     "valid credentials",
+			check:    CredentialCheck{CheckedAt: checkedAt, Valid: true, TokenExpiry: checkedAt.Add(time.Hour)},
+			expected: "",
+		},
+		{
+			name:     "valid credentials without token expiry",
+			check:    CredentialCheck{CheckedAt: checkedAt, Valid: true},
+			expected: "",
+		},
+		{
+			name:     "rejected credentials",
+			check:    CredentialCheck{CheckedAt: checkedAt, Rejected: true, Error: "authentication failed with HTTP 401"},
+			expected: "the credentials were rejected: authentication failed with HTTP 401",
+		},
+		{
+			name:     "check could not complete",
+			check:    CredentialCheck{CheckedAt: checkedAt, Error: "network error: connection refused"},
+			expected: "",
+		},
+		{
+			name:     "short-lived token",
+			check:    CredentialCheck{CheckedAt: checkedAt, Valid: true, TokenExpiry: checkedAt.Add(2 * time.Minute)},
+			expected: "the issued token expires after 2m0s, so every poll has to authenticate again",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.check.Problem())
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package dataminr
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
@@ -11,6 +12,19 @@ const (
 	MilesToMeters = 1609.34
 )
 
+// ParseAlert parses a single alert as returned by the Dataminr API and normalizes it
+func ParseAlert(data []byte, backendName string) (*backend.Alert, error) {
+	var alert Alert
+	if err := json.Unmarshal(data, &alert); err != nil {
+		return nil, fmt.Errorf("invalid Dataminr alert: %w", err)
+	}
+	if alert.AlertID == "" {
+		return nil, fmt.Errorf("alert is missing 'alertId'")
+	}
+
+	return NormalizeAlert(alert, backendName), nil
+}
+
 // NormalizeAlert converts a Dataminr alert to a normalized backend.Alert
 func NormalizeAlert(alert Alert, backendName string) *backend.Alert {
 	normalized := &backend.Alert{
@@ -29,6 +43,7 @@ func NormalizeAlert(alert Alert, backendName string) *backend.Alert {
 			Latitude:         alert.Location.Latitude,
 			Longitude:        alert.Location.Longitude,
 			ConfidenceRadius: alert.Location.ConfidenceRadiusMiles * MilesToMeters,
+			MGRS:             alert.Location.MGRSCode,
 		}
 	}
 
@@ -67,6 +82,9 @@ func NormalizeAlert(alert Alert, backendName string) *backend.Alert {
 				normalized.LinkedAlerts = append(normalized.LinkedAlerts,
 					fmt.Sprintf("%d linked alerts (parent: %s)", linked.Count, linked.ParentID))
 			}
+			if linked.ParentID != "" {
+				normalized.ParentAlertIDs = append(normalized.ParentAlertIDs, linked.ParentID)
+			}
 		}
 	}
 
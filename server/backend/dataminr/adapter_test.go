@@ -5,8 +5,40 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func TestParseAlert(t *testing.T) {
+	t.Run("raw API alert", func(t *testing.T) {
+		alert, err := ParseAlert([]byte(`{
+			"alertId": "alert-123",
+			"alertType": {"name": "Urgent", "color": "orange"},
+			"eventTime": 1700000000000,
+			"headline": "Flooding reported downtown",
+			"estimatedEventLocation": ["Springfield", 39.8, -89.6, 1.5, "16SBJ"]
+		}`), "Weather Watch")
+		require.NoError(t, err)
+
+		assert.Equal(t, "alert-123", alert.AlertID)
+		assert.Equal(t, "Urgent", alert.AlertType)
+		assert.Equal(t, "Weather Watch", alert.BackendName)
+		assert.Equal(t, "Flooding reported downtown", alert.Headline)
+		assert.Equal(t, time.UnixMilli(1700000000000).UTC(), alert.EventTime)
+		require.NotNil(t, alert.Location)
+		assert.Equal(t, "Springfield", alert.Location.Address)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := ParseAlert([]byte(`{"alertId": `), "Weather Watch")
+		assert.ErrorContains(t, err, "invalid Dataminr alert")
+	})
+
+	t.Run("missing alert ID", func(t *testing.T) {
+		_, err := ParseAlert([]byte(`{"headline": "Flooding"}`), "Weather Watch")
+		assert.EqualError(t, err, "alert is missing 'alertId'")
+	})
+}
+
 func TestNormalizeAlert(t *testing.T) {
 	eventTime := time.Now().UTC()
 
@@ -65,6 +97,7 @@ func TestNormalizeAlert(t *testing.T) {
 		assert.Equal(t, 40.7128, normalized.Location.Latitude)
 		assert.Equal(t, -74.0060, normalized.Location.Longitude)
 		assert.InDelta(t, 4023.35, normalized.Location.ConfidenceRadius, 0.01) // 2.5 miles in meters
+		assert.Equal(t, "18TWL8308", normalized.Location.MGRS)
 
 		// Check sub-headline formatting
 		assert.Equal(t, "**Additional Context**\nMore details about the event", normalized.SubHeadline)
@@ -77,6 +110,7 @@ func TestNormalizeAlert(t *testing.T) {
 
 		// Check linked alerts
 		assert.Equal(t, []string{"3 linked alerts (parent: parent-alert-456)"}, normalized.LinkedAlerts)
+		assert.Equal(t, []string{"parent-alert-456"}, normalized.ParentAlertIDs)
 
 		// Check public post
 		assert.Equal(t, "Original tweet text", normalized.SourceText)
@@ -112,6 +146,7 @@ func TestNormalizeAlert(t *testing.T) {
 		assert.Empty(t, normalized.Topics)
 		assert.Empty(t, normalized.AlertLists)
 		assert.Empty(t, normalized.LinkedAlerts)
+		assert.Empty(t, normalized.ParentAlertIDs)
 		assert.Empty(t, normalized.SourceText)
 		assert.Empty(t, normalized.TranslatedText)
 		assert.Empty(t, normalized.PublicSourceURL)
@@ -1,7 +1,8 @@
 package dataminr
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -9,7 +10,6 @@ import (
 	"time"
 
 	"github.com/mattermost/mattermost/server/public/plugin"
-	"github.com/mattermost/mattermost/server/public/pluginapi"
 
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
@@ -22,23 +22,47 @@ type AuthManager struct {
 	apiPassword string
 	httpClient  *http.Client
 	stateStore  *StateStore
-	logger      pluginapi.LogService
+	logger      backend.Logger
+	authPath    string
+
+	// maxResponseBytes is the largest response read before the request fails
+	maxResponseBytes int64
 }
 
 // NewAuthManager creates a new authentication manager
-func NewAuthManager(baseURL, apiUserID, apiPassword string, api plugin.API, backendID string, logger pluginapi.LogService) *AuthManager {
+// A nil transport uses the default HTTP transport.
+func NewAuthManager(baseURL, apiUserID, apiPassword string, api plugin.API, backendID string, logger backend.Logger, transport http.RoundTripper) *AuthManager {
 	return &AuthManager{
 		baseURL:     baseURL,
 		apiUserID:   apiUserID,
 		apiPassword: apiPassword,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   backend.DefaultRequestTimeoutSeconds * time.Second,
+			Transport: transport,
 		},
 		stateStore: NewStateStore(api, backendID),
 		logger:     logger,
+		authPath:   backend.DefaultAuthPath,
+
+		maxResponseBytes: (*backend.RequestLimitSettings)(nil).MaxResponseBytes(),
 	}
 }
 
+// SetTimeout changes how long a single authentication request may take
+func (a *AuthManager) SetTimeout(timeout time.Duration) {
+	a.httpClient.Timeout = timeout
+}
+
+// SetMaxResponseSize changes the largest authentication response read, in bytes
+func (a *AuthManager) SetMaxResponseSize(maxBytes int64) {
+	a.maxResponseBytes = maxBytes
+}
+
+// SetAuthPath changes the path of the authentication endpoint, appended to the base URL
+func (a *AuthManager) SetAuthPath(path string) {
+	a.authPath = path
+}
+
 // GetValidToken returns a valid authentication token, refreshing if necessary
 // Returns the token string and expiry time, or an error if authentication fails
 func (a *AuthManager) GetValidToken() (string, time.Time, error) {
@@ -53,12 +77,30 @@ func (a *AuthManager) GetValidToken() (string, time.Time, error) {
 	}
 
 	a.logger.Info("Acquiring new authentication token")
-	return a.authenticate()
+	return a.authenticate(context.Background())
+}
+
+// CheckCredentials validates the credentials by acquiring a new token, even if the cached
+// one is still valid, and replaces the cached token with it
+func (a *AuthManager) CheckCredentials(ctx context.Context, now time.Time) backend.CredentialCheck {
+	check := backend.CredentialCheck{CheckedAt: now}
+
+	_, expiry, err := a.authenticate(ctx)
+	if err != nil {
+		var authErr *AuthError
+		check.Rejected = errors.As(err, &authErr)
+		check.Error = err.Error()
+		return check
+	}
+
+	check.Valid = true
+	check.TokenExpiry = expiry
+	return check
 }
 
 // authenticate performs the authentication flow with Dataminr API
-func (a *AuthManager) authenticate() (string, time.Time, error) {
-	authURL := fmt.Sprintf("%s/auth/1/userAuthorization", a.baseURL)
+func (a *AuthManager) authenticate(ctx context.Context) (string, time.Time, error) {
+	authURL := a.baseURL + a.authPath
 
 	formData := url.Values{}
 	formData.Set("grant_type", "api_key")
@@ -66,7 +108,7 @@ func (a *AuthManager) authenticate() (string, time.Time, error) {
 	formData.Set("api_user_id", a.apiUserID)
 	formData.Set("api_password", a.apiPassword)
 
-	req, err := http.NewRequest(http.MethodPost, authURL, strings.NewReader(formData.Encode()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authURL, strings.NewReader(formData.Encode()))
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("failed to create auth request: %w", err)
 	}
@@ -75,20 +117,21 @@ func (a *AuthManager) authenticate() (string, time.Time, error) {
 
 	resp, err := a.httpClient.Do(req)
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("authentication request failed: %w", err)
+		return "", time.Time{}, &NetworkError{Op: "authentication request failed", Err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		var authErr AuthError
-		if err := json.NewDecoder(resp.Body).Decode(&authErr); err == nil && authErr.Error != "" {
-			return "", time.Time{}, fmt.Errorf("authentication failed (HTTP %d): %s - %s", resp.StatusCode, authErr.Error, authErr.ErrorDescription)
+		message := fmt.Sprintf("authentication failed with HTTP %d", resp.StatusCode)
+		var authErr AuthErrorResponse
+		if err := decodeResponse(resp, a.maxResponseBytes, &authErr); err == nil && authErr.Error != "" {
+			message = fmt.Sprintf("authentication failed (HTTP %d): %s - %s", resp.StatusCode, authErr.Error, authErr.ErrorDescription)
 		}
-		return "", time.Time{}, fmt.Errorf("authentication failed with HTTP %d", resp.StatusCode)
+		return "", time.Time{}, authFailure(resp, message)
 	}
 
 	var authResp AuthResponse
-	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+	if err := decodeResponse(resp, a.maxResponseBytes, &authResp); err != nil {
 		return "", time.Time{}, fmt.Errorf("failed to parse auth response: %w", err)
 	}
 
@@ -104,6 +147,18 @@ func (a *AuthManager) authenticate() (string, time.Time, error) {
 	return authResp.AuthorizationToken, authResp.ExpirationTime, nil
 }
 
+// authFailure classifies a failed authentication response by its HTTP status
+func authFailure(resp *http.Response, message string) error {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &RateLimitError{RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), Message: message}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return &ServerError{StatusCode: resp.StatusCode, Message: message}
+	default:
+		return &AuthError{StatusCode: resp.StatusCode, Message: message}
+	}
+}
+
 // isTokenValid checks if a token is valid and not expiring soon
 // Returns true if the token has more than AuthTokenRefreshBuffer (5 minutes) remaining
 func (a *AuthManager) isTokenValid(expiry time.Time) bool {
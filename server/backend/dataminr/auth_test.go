@@ -64,7 +64,7 @@ func TestAuthManager_GetValidToken_SuccessfulAuthentication(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager
-	authManager := NewAuthManager(server.URL, "test_user", "test_password", api, "test-backend-id", client.Log)
+	authManager := NewAuthManager(server.URL, "test_user", "test_password", api, "test-backend-id", &client.Log, nil)
 
 	// Get valid token
 	token, expiry, err := authManager.GetValidToken()
@@ -97,7 +97,7 @@ func TestAuthManager_GetValidToken_UsesCachedToken(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager (server URL doesn't matter - shouldn't be called)
-	authManager := NewAuthManager("http://should-not-call", "test_user", "test_password", api, "test-backend-id", client.Log)
+	authManager := NewAuthManager("http://should-not-call", "test_user", "test_password", api, "test-backend-id", &client.Log, nil)
 
 	// Get valid token
 	token, expiry, err := authManager.GetValidToken()
@@ -147,7 +147,7 @@ func TestAuthManager_GetValidToken_RefreshesExpiringToken(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager
-	authManager := NewAuthManager(server.URL, "test_user", "test_password", api, "test-backend-id", client.Log)
+	authManager := NewAuthManager(server.URL, "test_user", "test_password", api, "test-backend-id", &client.Log, nil)
 
 	// Get valid token - should trigger refresh
 	token, expiry, err := authManager.GetValidToken()
@@ -166,7 +166,7 @@ func TestAuthManager_Authenticate_InvalidCredentials(t *testing.T) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
 
-		authErr := AuthError{
+		authErr := AuthErrorResponse{
 			Error:            "unauthorized_client",
 			ErrorDescription: "Invalid API User ID/Password",
 		}
@@ -184,7 +184,7 @@ func TestAuthManager_Authenticate_InvalidCredentials(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager
-	authManager := NewAuthManager(server.URL, "bad_user", "bad_password", api, "test-backend-id", client.Log)
+	authManager := NewAuthManager(server.URL, "bad_user", "bad_password", api, "test-backend-id", &client.Log, nil)
 
 	// Get valid token - should fail
 	token, expiry, err := authManager.GetValidToken()
@@ -194,6 +194,10 @@ func TestAuthManager_Authenticate_InvalidCredentials(t *testing.T) {
 	assert.Contains(t, err.Error(), "authentication failed")
 	assert.Contains(t, err.Error(), "unauthorized_client")
 	assert.Contains(t, err.Error(), "Invalid API User ID/Password")
+	var authErr *AuthError
+	require.ErrorAs(t, err, &authErr)
+	assert.Equal(t, http.StatusUnauthorized, authErr.StatusCode)
+	assert.False(t, authErr.TokenRejected)
 	assert.Equal(t, "", token)
 	assert.True(t, expiry.IsZero())
 
@@ -217,7 +221,7 @@ func TestAuthManager_Authenticate_ServerError(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager
-	authManager := NewAuthManager(server.URL, "test_user", "test_password", api, "test-backend-id", client.Log)
+	authManager := NewAuthManager(server.URL, "test_user", "test_password", api, "test-backend-id", &client.Log, nil)
 
 	// Get valid token - should fail
 	token, expiry, err := authManager.GetValidToken()
@@ -225,6 +229,8 @@ func TestAuthManager_Authenticate_ServerError(t *testing.T) {
 	// Assertions
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "authentication failed with HTTP 500")
+	var serverErr *ServerError
+	require.ErrorAs(t, err, &serverErr)
 	assert.Equal(t, "", token)
 	assert.True(t, expiry.IsZero())
 
@@ -252,7 +258,7 @@ func TestAuthManager_Authenticate_MissingToken(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager
-	authManager := NewAuthManager(server.URL, "test_user", "test_password", api, "test-backend-id", client.Log)
+	authManager := NewAuthManager(server.URL, "test_user", "test_password", api, "test-backend-id", &client.Log, nil)
 
 	// Get valid token - should fail
 	token, expiry, err := authManager.GetValidToken()
@@ -285,7 +291,7 @@ func TestAuthManager_Authenticate_InvalidJSON(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager
-	authManager := NewAuthManager(server.URL, "test_user", "test_password", api, "test-backend-id", client.Log)
+	authManager := NewAuthManager(server.URL, "test_user", "test_password", api, "test-backend-id", &client.Log, nil)
 
 	// Get valid token - should fail
 	token, expiry, err := authManager.GetValidToken()
@@ -305,10 +311,10 @@ func TestAuthManager_ClearCachedToken(t *testing.T) {
 	api.On("KVSet", "backend_test-backend-id_auth", mock.Anything).Return(nil).Once()
 
 	// Create logger
-	logger := pluginapi.LogService{}
+	logger := &pluginapi.LogService{}
 
 	// Create auth manager
-	authManager := NewAuthManager("http://test", "test_user", "test_password", api, "test-backend-id", logger)
+	authManager := NewAuthManager("http://test", "test_user", "test_password", api, "test-backend-id", logger, nil)
 
 	// Clear cached token
 	err := authManager.ClearCachedToken()
@@ -353,9 +359,9 @@ func TestAuthManager_isTokenValid(t *testing.T) {
 
 	// Mock plugin API (not used in this test)
 	api := &plugintest.API{}
-	logger := pluginapi.LogService{}
+	logger := &pluginapi.LogService{}
 
-	authManager := NewAuthManager("http://test", "test_user", "test_password", api, "test-backend-id", logger)
+	authManager := NewAuthManager("http://test", "test_user", "test_password", api, "test-backend-id", logger, nil)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1,52 +1,149 @@
 package dataminr
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
-	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
 
 // APIClient handles communication with the Dataminr First Alert API
-// for fetching alerts using cursor-based pagination
+// for fetching alerts using cursor-based pagination, or by time window without a valid cursor
 type APIClient struct {
 	baseURL     string
 	httpClient  *http.Client
 	authManager *AuthManager
-	logger      pluginapi.LogService
+	logger      backend.Logger
+	alertLists  []string
+	limiter     *requestLimiter
+	endpoints   *backend.APIEndpointSettings
+
+	// maxResponseBytes is the largest response read before the request fails
+	maxResponseBytes int64
 }
 
 // NewAPIClient creates a new API client
-func NewAPIClient(baseURL string, authManager *AuthManager, logger pluginapi.LogService) *APIClient {
+// A nil transport uses the default HTTP transport.
+func NewAPIClient(baseURL string, authManager *AuthManager, logger backend.Logger, transport http.RoundTripper) *APIClient {
 	return &APIClient{
 		baseURL:     baseURL,
 		authManager: authManager,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   backend.DefaultRequestTimeoutSeconds * time.Second,
+			Transport: transport,
 		},
-		logger: logger,
+		logger:           logger,
+		maxResponseBytes: (*backend.RequestLimitSettings)(nil).MaxResponseBytes(),
 	}
 }
 
+// SetAlertLists restricts fetched alerts to the given alert list IDs.
+// An empty list fetches alerts from every list available to the account.
+func (c *APIClient) SetAlertLists(ids []string) {
+	c.alertLists = ids
+}
+
+// SetEndpoints overrides the alert version and alerts endpoint path requested.
+// Nil settings use the defaults.
+func (c *APIClient) SetEndpoints(settings *backend.APIEndpointSettings) {
+	c.endpoints = settings
+}
+
+// SetRequestLimits applies the request timeout, rate limits and response size limit of the
+// backend configuration. Nil settings keep the default timeout and size limit and send
+// requests without rate limiting.
+func (c *APIClient) SetRequestLimits(settings *backend.RequestLimitSettings) {
+	c.httpClient.Timeout = settings.Timeout()
+	c.maxResponseBytes = settings.MaxResponseBytes()
+	c.limiter = nil
+	if settings.RequestsPerMinute() > 0 || settings.MinInterval() > 0 {
+		c.limiter = newRequestLimiter(settings.RequestsPerMinute(), settings.MinInterval())
+	}
+}
+
+// ResetAuth clears the cached authentication token so the next request authenticates again
+func (c *APIClient) ResetAuth() error {
+	return c.authManager.ClearCachedToken()
+}
+
+// alertsQuery selects the alerts of a request: those after a cursor, or those with an event
+// time in a window when start is set
+type alertsQuery struct {
+	cursor string
+	start  time.Time
+	end    time.Time
+}
+
 // FetchAlerts polls the Dataminr alerts endpoint with cursor-based pagination
-// Returns the alerts response containing alerts array and new cursor, or an error
-func (c *APIClient) FetchAlerts(cursor string) (*AlertsResponse, error) {
+// Returns the alerts response containing alerts array and new cursor, or an error.
+// If the token is rejected (e.g., revoked before its expiry), the cached token is cleared
+// and the request is retried once with a freshly obtained token. Cancelling ctx aborts the request.
+func (c *APIClient) FetchAlerts(ctx context.Context, cursor string) (*AlertsResponse, error) {
+	return c.fetchWithReauth(ctx, alertsQuery{cursor: cursor})
+}
+
+// FetchAlertsWindow fetches the alerts with an event time between start and end, for polling
+// without a valid cursor. The response cursor, if any, continues after the window.
+func (c *APIClient) FetchAlertsWindow(ctx context.Context, start, end time.Time) (*AlertsResponse, error) {
+	return c.fetchWithReauth(ctx, alertsQuery{start: start, end: end})
+}
+
+// fetchWithReauth requests alerts, retrying once with a new token if the cached one is rejected
+func (c *APIClient) fetchWithReauth(ctx context.Context, query alertsQuery) (*AlertsResponse, error) {
+	resp, err := c.fetchAlerts(ctx, query)
+	var authErr *AuthError
+	if !errors.As(err, &authErr) || !authErr.TokenRejected {
+		return resp, err
+	}
+
+	c.logger.Warn("Alerts request was unauthorized, re-authenticating and retrying once", "error", err.Error())
+	if clearErr := c.authManager.ClearCachedToken(); clearErr != nil {
+		return nil, fmt.Errorf("failed to clear cached auth token: %w", clearErr)
+	}
+
+	return c.fetchAlerts(ctx, query)
+}
+
+// fetchAlerts performs a single request to the alerts endpoint
+func (c *APIClient) fetchAlerts(ctx context.Context, query alertsQuery) (*AlertsResponse, error) {
+	cursor := query.cursor
+
 	// Get valid authentication token
 	token, _, err := c.authManager.GetValidToken()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get auth token: %w", err)
 	}
 
-	// Build request URL with hardcoded alertversion=19
-	alertsURL := fmt.Sprintf("%s/alerts/1/alerts?alertversion=19", c.baseURL)
+	// Build request URL with the configured alert version, requesting full pages explicitly
+	// so a shorter page reliably means the backlog is drained
+	alertsURL := fmt.Sprintf("%s%s?alertversion=%d&num=%d", c.baseURL, c.endpoints.AlertsEndpoint(), c.endpoints.Version(), alertsPageSize)
+	if len(c.alertLists) > 0 {
+		alertsURL += fmt.Sprintf("&lists=%s", url.QueryEscape(strings.Join(c.alertLists, ",")))
+	}
 	if cursor != "" {
 		alertsURL += fmt.Sprintf("&from=%s", url.QueryEscape(cursor))
 	}
+	if !query.start.IsZero() {
+		alertsURL += fmt.Sprintf("&startTime=%d&endTime=%d", query.start.UnixMilli(), query.end.UnixMilli())
+	}
 
-	req, err := http.NewRequest(http.MethodGet, alertsURL, nil)
+	// Respect the configured rate limits before sending the request
+	if c.limiter != nil {
+		waited, err := c.limiter.Wait(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("alerts request cancelled while waiting for rate limits: %w", err)
+		}
+		if waited > 0 {
+			c.logger.Debug("Delayed alerts request to respect rate limits", "waited", waited.String())
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, alertsURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create alerts request: %w", err)
 	}
@@ -58,7 +155,7 @@ func (c *APIClient) FetchAlerts(cursor string) (*AlertsResponse, error) {
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("alerts request failed: %w", err)
+		return nil, &NetworkError{Op: "alerts request failed", Err: err}
 	}
 	defer resp.Body.Close()
 
@@ -69,35 +166,50 @@ func (c *APIClient) FetchAlerts(cursor string) (*AlertsResponse, error) {
 	case http.StatusUnauthorized:
 		// 401 - Token expired or invalid, suggest re-authentication
 		var apiErr APIError
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err == nil {
-			return nil, fmt.Errorf("authentication error (HTTP 401): %s", apiErr.Error())
+		if err := decodeResponse(resp, c.maxResponseBytes, &apiErr); err == nil {
+			return nil, &AuthError{StatusCode: resp.StatusCode, TokenRejected: true, Message: fmt.Sprintf("authentication error (HTTP 401): %s", apiErr.Error())}
 		}
-		return nil, fmt.Errorf("authentication error (HTTP 401): token invalid or expired")
+		return nil, &AuthError{StatusCode: resp.StatusCode, TokenRejected: true, Message: "authentication error (HTTP 401): token invalid or expired"}
 	case http.StatusTooManyRequests:
 		// 429 - Rate limit exceeded
-		return nil, fmt.Errorf("rate limit exceeded (HTTP 429): too many requests")
+		return nil, &RateLimitError{
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Message:    "rate limit exceeded (HTTP 429): too many requests",
+		}
 	case http.StatusInternalServerError:
 		// 500 - Server error
 		var apiErr APIError
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err == nil {
-			return nil, fmt.Errorf("server error (HTTP 500): %s", apiErr.Error())
+		if err := decodeResponse(resp, c.maxResponseBytes, &apiErr); err == nil {
+			return nil, &ServerError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("server error (HTTP 500): %s", apiErr.Error())}
 		}
-		return nil, fmt.Errorf("server error (HTTP 500): Dataminr API internal error")
+		return nil, &ServerError{StatusCode: resp.StatusCode, Message: "server error (HTTP 500): Dataminr API internal error"}
 	case http.StatusBadRequest:
-		// 400 - Bad request (configuration issue)
+		// 400 - Bad request (configuration issue, or an expired cursor)
 		var apiErr APIError
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err == nil {
-			return nil, fmt.Errorf("bad request (HTTP 400): %s", apiErr.Error())
+		if err := decodeResponse(resp, c.maxResponseBytes, &apiErr); err == nil {
+			if cursor != "" && strings.Contains(strings.ToLower(apiErr.Error()), "cursor") {
+				return nil, &CursorError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("cursor rejected (HTTP 400): %s", apiErr.Error())}
+			}
+			return nil, &ValidationError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("bad request (HTTP 400): %s", apiErr.Error())}
+		}
+		return nil, &ValidationError{StatusCode: resp.StatusCode, Message: "bad request (HTTP 400): invalid request parameters"}
+	case http.StatusGone:
+		// 410 - The cursor expired
+		if cursor != "" {
+			return nil, &CursorError{StatusCode: resp.StatusCode, Message: "cursor rejected (HTTP 410): cursor expired"}
 		}
-		return nil, fmt.Errorf("bad request (HTTP 400): invalid request parameters")
+		return nil, fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
 	default:
-		// Other errors
+		// Other errors; server-side failures such as 502 or 503 are expected to be temporary
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return nil, &ServerError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("unexpected HTTP status %d", resp.StatusCode)}
+		}
 		return nil, fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
 	}
 
 	// Parse successful response
 	var alertsResp AlertsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&alertsResp); err != nil {
+	if err := decodeResponse(resp, c.maxResponseBytes, &alertsResp); err != nil {
 		return nil, fmt.Errorf("failed to parse alerts response: %w", err)
 	}
 
@@ -108,3 +220,69 @@ func (c *APIClient) FetchAlerts(cursor string) (*AlertsResponse, error) {
 
 	return &alertsResp, nil
 }
+
+// FetchAlertLists fetches the alert lists available to the account, retrying once with a new
+// token if the cached one is rejected. Returns backend.ErrTaxonomyUnsupported if the API has
+// no alert lists endpoint.
+func (c *APIClient) FetchAlertLists(ctx context.Context) (*ListsResponse, error) {
+	resp, err := c.fetchAlertLists(ctx)
+	var authErr *AuthError
+	if !errors.As(err, &authErr) || !authErr.TokenRejected {
+		return resp, err
+	}
+
+	c.logger.Warn("Alert lists request was unauthorized, re-authenticating and retrying once", "error", err.Error())
+	if clearErr := c.authManager.ClearCachedToken(); clearErr != nil {
+		return nil, fmt.Errorf("failed to clear cached auth token: %w", clearErr)
+	}
+
+	return c.fetchAlertLists(ctx)
+}
+
+// fetchAlertLists performs a single request to the alert lists endpoint
+func (c *APIClient) fetchAlertLists(ctx context.Context) (*ListsResponse, error) {
+	token, _, err := c.authManager.GetValidToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	if c.limiter != nil {
+		waited, err := c.limiter.Wait(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("alert lists request cancelled while waiting for rate limits: %w", err)
+		}
+		if waited > 0 {
+			c.logger.Debug("Delayed alert lists request to respect rate limits", "waited", waited.String())
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+c.endpoints.ListsEndpoint(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create alert lists request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Dmauth %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &NetworkError{Op: "alert lists request failed", Err: err}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// Success - parse response below
+	case http.StatusUnauthorized:
+		return nil, &AuthError{StatusCode: resp.StatusCode, TokenRejected: true, Message: "authentication error (HTTP 401): token invalid or expired"}
+	case http.StatusNotFound:
+		return nil, backend.ErrTaxonomyUnsupported
+	default:
+		return nil, fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+
+	var listsResp ListsResponse
+	if err := decodeResponse(resp, c.maxResponseBytes, &listsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse alert lists response: %w", err)
+	}
+	return &listsResp, nil
+}
@@ -1,9 +1,15 @@
 package dataminr
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +18,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
 
 // createTestServerWithAuth creates a test server that handles authentication and custom alerts handling
@@ -64,6 +72,7 @@ func TestAPIClient_FetchAlerts_Success(t *testing.T) {
 	server := createTestServerWithAuth(func(w http.ResponseWriter, r *http.Request) {
 		// Verify query parameters
 		assert.Equal(t, "19", r.URL.Query().Get("alertversion"))
+		assert.Equal(t, strconv.Itoa(alertsPageSize), r.URL.Query().Get("num"))
 
 		// Verify authorization header
 		authHeader := r.Header.Get("Authorization")
@@ -88,13 +97,13 @@ func TestAPIClient_FetchAlerts_Success(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager (with cached token to avoid auth call)
-	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", client.Log)
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
 
 	// Create API client
-	apiClient := NewAPIClient(server.URL, authManager, client.Log)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
 
 	// Test fetching without cursor
-	resp, err := apiClient.FetchAlerts("")
+	resp, err := apiClient.FetchAlerts(context.Background(), "")
 
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -104,6 +113,45 @@ func TestAPIClient_FetchAlerts_Success(t *testing.T) {
 	assert.Equal(t, "alert-2", resp.Alerts[1].AlertID)
 }
 
+func TestAPIClient_FetchAlerts_CustomEndpoints(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/gateway/auth":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"authorizationToken": "test-token",
+				"expirationTime":     time.Now().Add(1 * time.Hour).UnixMilli(),
+			})
+		case "/gateway/alerts":
+			assert.Equal(t, "21", r.URL.Query().Get("alertversion"))
+			_ = json.NewEncoder(w).Encode(AlertsResponse{To: "cursor-123"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	api := &plugintest.API{}
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+	api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	settings := &backend.APIEndpointSettings{AlertVersion: 21, AuthPath: "/gateway/auth", AlertsPath: "/gateway/alerts"}
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	authManager.SetAuthPath(settings.AuthEndpoint())
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
+	apiClient.SetEndpoints(settings)
+
+	resp, err := apiClient.FetchAlerts(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "cursor-123", resp.To)
+	assert.Equal(t, []string{"/gateway/auth", "/gateway/alerts"}, paths)
+}
+
 func TestAPIClient_FetchAlerts_WithCursor(t *testing.T) {
 	// Create test server with auth handling
 	server := createTestServerWithAuth(func(w http.ResponseWriter, r *http.Request) {
@@ -133,20 +181,191 @@ func TestAPIClient_FetchAlerts_WithCursor(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager and API client
-	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", client.Log)
-	apiClient := NewAPIClient(server.URL, authManager, client.Log)
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
 
 	// Test fetching with cursor
-	resp, err := apiClient.FetchAlerts("previous-cursor")
+	resp, err := apiClient.FetchAlerts(context.Background(), "previous-cursor")
 
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 	assert.Equal(t, "new-cursor", resp.To)
 }
 
+func TestAPIClient_FetchAlerts_WithAlertLists(t *testing.T) {
+	var queries []url.Values
+	server := createTestServerWithAuth(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(AlertsResponse{Alerts: []Alert{}, To: "new-cursor"})
+	})
+	defer server.Close()
+
+	api := &plugintest.API{}
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+	api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
+
+	// Without alert lists the parameter is omitted
+	_, err := apiClient.FetchAlerts(context.Background(), "")
+	require.NoError(t, err)
+
+	apiClient.SetAlertLists([]string{"12345", "67890"})
+	_, err = apiClient.FetchAlerts(context.Background(), "cursor")
+	require.NoError(t, err)
+
+	require.Len(t, queries, 2)
+	assert.False(t, queries[0].Has("lists"))
+	assert.Equal(t, "12345,67890", queries[1].Get("lists"))
+	assert.Equal(t, "cursor", queries[1].Get("from"))
+	assert.Equal(t, "19", queries[1].Get("alertversion"))
+}
+
+func TestAPIClient_FetchAlerts_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	server := createTestServerWithAuth(func(w http.ResponseWriter, r *http.Request) {
+		// Cancel while the request is in flight, and hold the response until it is aborted
+		cancel()
+		<-r.Context().Done()
+	})
+	defer server.Close()
+
+	api := &plugintest.API{}
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+	api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
+
+	_, err := apiClient.FetchAlerts(ctx, "cursor")
+	var networkErr *NetworkError
+	require.ErrorAs(t, err, &networkErr)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestAPIClient_SetRequestLimits(t *testing.T) {
+	api := &plugintest.API{}
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	authManager := NewAuthManager("https://api.example.com", "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient("https://api.example.com", authManager, &client.Log, nil)
+
+	// Defaults apply without settings
+	assert.Equal(t, 30*time.Second, apiClient.httpClient.Timeout)
+	assert.Nil(t, apiClient.limiter)
+
+	apiClient.SetRequestLimits(&backend.RequestLimitSettings{TimeoutSeconds: 5})
+	assert.Equal(t, 5*time.Second, apiClient.httpClient.Timeout)
+	assert.Nil(t, apiClient.limiter)
+
+	apiClient.SetRequestLimits(&backend.RequestLimitSettings{MaxRequestsPerMinute: 6, MinIntervalMs: 2000})
+	assert.Equal(t, 30*time.Second, apiClient.httpClient.Timeout)
+	require.NotNil(t, apiClient.limiter)
+	assert.Equal(t, 6, apiClient.limiter.maxPerMinute)
+	assert.Equal(t, 2*time.Second, apiClient.limiter.minInterval)
+
+	apiClient.SetRequestLimits(nil)
+	assert.Nil(t, apiClient.limiter)
+	assert.Equal(t, int64(10*1024*1024), apiClient.maxResponseBytes)
+
+	apiClient.SetRequestLimits(&backend.RequestLimitSettings{MaxResponseSizeMB: 2})
+	assert.Equal(t, int64(2*1024*1024), apiClient.maxResponseBytes)
+}
+
+func TestAPIClient_FetchAlerts_PayloadTooLarge(t *testing.T) {
+	server := createTestServerWithAuth(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		// Stream the response without a Content-Length so the limit applies while decoding
+		_, _ = w.Write([]byte(`{"alerts":[`))
+		w.(http.Flusher).Flush()
+		for i := 0; i < 100; i++ {
+			_, _ = w.Write([]byte(`{"alertId":"alert-` + strings.Repeat("x", 100) + `"},`))
+		}
+		_, _ = w.Write([]byte(`{"alertId":"last"}],"to":"new-cursor"}`))
+	})
+	defer server.Close()
+
+	api := &plugintest.API{}
+	api.On("LogDebug", mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+	api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
+	apiClient.maxResponseBytes = 1024
+
+	resp, err := apiClient.FetchAlerts(context.Background(), "")
+	require.Error(t, err)
+	assert.Nil(t, resp)
+
+	var tooLarge *PayloadTooLargeError
+	require.ErrorAs(t, err, &tooLarge)
+	assert.Equal(t, int64(1024), tooLarge.Limit)
+	assert.Equal(t, "failed to parse alerts response: response exceeds the size limit of 1024 bytes", err.Error())
+
+	var authErr *AuthError
+	assert.False(t, errors.As(err, &authErr), "an oversized response is not an authentication failure")
+}
+
+func TestAPIClient_FetchAlerts_RateLimited(t *testing.T) {
+	requests := 0
+	server := createTestServerWithAuth(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(AlertsResponse{Alerts: []Alert{}, To: "new-cursor"})
+	})
+	defer server.Close()
+
+	api := &plugintest.API{}
+	api.On("LogDebug", "Delayed alerts request to respect rate limits", "waited", "2s").Once()
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+	api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
+	apiClient.SetRequestLimits(&backend.RequestLimitSettings{MinIntervalMs: 2000})
+
+	// Use a fake clock so the spacing does not slow down the test
+	clock := time.Now()
+	var slept []time.Duration
+	apiClient.limiter.now = func() time.Time { return clock }
+	apiClient.limiter.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		clock = clock.Add(d)
+		return nil
+	}
+
+	_, err := apiClient.FetchAlerts(context.Background(), "")
+	require.NoError(t, err)
+	_, err = apiClient.FetchAlerts(context.Background(), "cursor")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, []time.Duration{2 * time.Second}, slept)
+	api.AssertExpectations(t)
+}
+
 func TestAPIClient_FetchAlerts_Unauthorized(t *testing.T) {
 	// Create test server with auth handling
+	alertRequests := 0
 	server := createTestServerWithAuth(func(w http.ResponseWriter, r *http.Request) {
+		alertRequests++
 		apiErr := APIError{
 			Errors: []ErrorDetail{
 				{Code: "103", Message: "Authentication error. Invalid token"},
@@ -162,6 +381,7 @@ func TestAPIClient_FetchAlerts_Unauthorized(t *testing.T) {
 	api := &plugintest.API{}
 	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything).Maybe()
 	api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
 	api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
 
@@ -169,21 +389,80 @@ func TestAPIClient_FetchAlerts_Unauthorized(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager and API client
-	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", client.Log)
-	apiClient := NewAPIClient(server.URL, authManager, client.Log)
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
 
-	// Test fetching - should return 401 error
-	resp, err := apiClient.FetchAlerts("")
+	// Test fetching - should return 401 error after a single retry
+	resp, err := apiClient.FetchAlerts(context.Background(), "")
 
 	require.Error(t, err)
 	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "authentication error")
 	assert.Contains(t, err.Error(), "401")
+	assert.Equal(t, 2, alertRequests, "should retry exactly once")
+	var authErr *AuthError
+	require.ErrorAs(t, err, &authErr)
+	assert.True(t, authErr.TokenRejected)
+}
+
+func TestAPIClient_FetchAlerts_UnauthorizedRetrySucceeds(t *testing.T) {
+	authRequests := 0
+	alertRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/auth/1/userAuthorization" {
+			authRequests++
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"authorizationToken": fmt.Sprintf("token-%d", authRequests),
+				"expirationTime":     time.Now().Add(1 * time.Hour).UnixMilli(),
+			})
+			return
+		}
+
+		alertRequests++
+		// The first token has been revoked
+		if r.Header.Get("Authorization") == "Dmauth token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(AlertsResponse{To: "new-cursor"})
+	}))
+	defer server.Close()
+
+	api := &plugintest.API{}
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogDebug", mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything).Maybe()
+	api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything).Once()
+
+	// Back the token cache with an in-memory KV store
+	kvStore := make(map[string][]byte)
+	api.On("KVSet", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		kvStore[args.String(0)] = args.Get(1).([]byte)
+	}).Return(nil)
+	api.On("KVGet", mock.Anything).Return(func(key string) []byte {
+		return kvStore[key]
+	}, nil)
+
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
+
+	resp, err := apiClient.FetchAlerts(context.Background(), "cursor")
+
+	require.NoError(t, err)
+	assert.Equal(t, "new-cursor", resp.To)
+	assert.Equal(t, 2, authRequests, "should re-authenticate once")
+	assert.Equal(t, 2, alertRequests)
+	api.AssertExpectations(t)
 }
 
 func TestAPIClient_FetchAlerts_RateLimitExceeded(t *testing.T) {
 	// Create test server with auth handling
 	server := createTestServerWithAuth(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
 		w.WriteHeader(http.StatusTooManyRequests)
 	})
 	defer server.Close()
@@ -199,16 +478,48 @@ func TestAPIClient_FetchAlerts_RateLimitExceeded(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager and API client
-	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", client.Log)
-	apiClient := NewAPIClient(server.URL, authManager, client.Log)
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
 
 	// Test fetching - should return 429 error
-	resp, err := apiClient.FetchAlerts("")
+	resp, err := apiClient.FetchAlerts(context.Background(), "")
 
 	require.Error(t, err)
 	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "rate limit exceeded")
 	assert.Contains(t, err.Error(), "429")
+	var rateLimitErr *RateLimitError
+	require.ErrorAs(t, err, &rateLimitErr)
+	assert.Equal(t, 2*time.Minute, rateLimitErr.RetryAfter)
+}
+
+func TestAPIClient_FetchAlerts_NetworkError(t *testing.T) {
+	server := createTestServerWithAuth(func(w http.ResponseWriter, r *http.Request) {
+		// Drop the connection without a response
+		hijacker, ok := w.(http.Hijacker)
+		require.True(t, ok)
+		conn, _, err := hijacker.Hijack()
+		require.NoError(t, err)
+		conn.Close()
+	})
+	defer server.Close()
+
+	api := &plugintest.API{}
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+	api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
+
+	resp, err := apiClient.FetchAlerts(context.Background(), "")
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "alerts request failed")
+	var networkErr *NetworkError
+	require.ErrorAs(t, err, &networkErr)
 }
 
 func TestAPIClient_FetchAlerts_ServerError(t *testing.T) {
@@ -236,16 +547,19 @@ func TestAPIClient_FetchAlerts_ServerError(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager and API client
-	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", client.Log)
-	apiClient := NewAPIClient(server.URL, authManager, client.Log)
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
 
 	// Test fetching - should return 500 error
-	resp, err := apiClient.FetchAlerts("")
+	resp, err := apiClient.FetchAlerts(context.Background(), "")
 
 	require.Error(t, err)
 	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "server error")
 	assert.Contains(t, err.Error(), "500")
+	var serverErr *ServerError
+	require.ErrorAs(t, err, &serverErr)
+	assert.Equal(t, http.StatusInternalServerError, serverErr.StatusCode)
 }
 
 func TestAPIClient_FetchAlerts_BadRequest(t *testing.T) {
@@ -273,16 +587,109 @@ func TestAPIClient_FetchAlerts_BadRequest(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager and API client
-	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", client.Log)
-	apiClient := NewAPIClient(server.URL, authManager, client.Log)
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
 
 	// Test fetching - should return 400 error
-	resp, err := apiClient.FetchAlerts("")
+	resp, err := apiClient.FetchAlerts(context.Background(), "")
 
 	require.Error(t, err)
 	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "bad request")
 	assert.Contains(t, err.Error(), "400")
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestAPIClient_FetchAlerts_CursorRejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       interface{}
+		expected   string
+	}{
+		{
+			name:       "invalid cursor",
+			statusCode: http.StatusBadRequest,
+			body:       APIError{Errors: []ErrorDetail{{Code: "400", Message: "Invalid cursor"}}},
+			expected:   "cursor rejected (HTTP 400)",
+		},
+		{
+			name:       "expired cursor",
+			statusCode: http.StatusGone,
+			expected:   "cursor rejected (HTTP 410)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createTestServerWithAuth(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "old-cursor", r.URL.Query().Get("from"))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.statusCode)
+				if tt.body != nil {
+					_ = json.NewEncoder(w).Encode(tt.body)
+				}
+			})
+			defer server.Close()
+
+			api := &plugintest.API{}
+			api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+			api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
+			client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+			authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+			apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
+
+			resp, err := apiClient.FetchAlerts(context.Background(), "old-cursor")
+
+			require.Error(t, err)
+			assert.Nil(t, resp)
+			assert.Contains(t, err.Error(), tt.expected)
+			var cursorErr *CursorError
+			require.ErrorAs(t, err, &cursorErr)
+			assert.Equal(t, tt.statusCode, cursorErr.StatusCode)
+		})
+	}
+}
+
+func TestAPIClient_FetchAlertsWindow(t *testing.T) {
+	start := time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	server := createTestServerWithAuth(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Empty(t, query.Get("from"))
+		assert.Equal(t, fmt.Sprintf("%d", start.UnixMilli()), query.Get("startTime"))
+		assert.Equal(t, fmt.Sprintf("%d", end.UnixMilli()), query.Get("endTime"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(AlertsResponse{
+			Alerts: []Alert{{AlertID: "alert-1", Headline: "Test Alert"}},
+			To:     "new-cursor",
+		})
+	})
+	defer server.Close()
+
+	api := &plugintest.API{}
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+	api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
+
+	resp, err := apiClient.FetchAlertsWindow(context.Background(), start, end)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Alerts, 1)
+	assert.Equal(t, "alert-1", resp.Alerts[0].AlertID)
+	assert.Equal(t, "new-cursor", resp.To)
 }
 
 func TestAPIClient_FetchAlerts_InvalidJSON(t *testing.T) {
@@ -305,11 +712,11 @@ func TestAPIClient_FetchAlerts_InvalidJSON(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager and API client
-	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", client.Log)
-	apiClient := NewAPIClient(server.URL, authManager, client.Log)
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
 
 	// Test fetching - should return parse error
-	resp, err := apiClient.FetchAlerts("")
+	resp, err := apiClient.FetchAlerts(context.Background(), "")
 
 	require.Error(t, err)
 	assert.Nil(t, resp)
@@ -340,11 +747,11 @@ func TestAPIClient_FetchAlerts_AuthenticationFailure(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager with no cached token
-	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", client.Log)
-	apiClient := NewAPIClient(server.URL, authManager, client.Log)
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
 
 	// Test fetching - should fail during authentication
-	resp, err := apiClient.FetchAlerts("")
+	resp, err := apiClient.FetchAlerts(context.Background(), "")
 
 	require.Error(t, err)
 	assert.Nil(t, resp)
@@ -377,14 +784,96 @@ func TestAPIClient_FetchAlerts_EmptyAlerts(t *testing.T) {
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 	// Create auth manager and API client
-	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", client.Log)
-	apiClient := NewAPIClient(server.URL, authManager, client.Log)
+	authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+	apiClient := NewAPIClient(server.URL, authManager, &client.Log, nil)
 
 	// Test fetching - should succeed with empty alerts
-	resp, err := apiClient.FetchAlerts("cursor-123")
+	resp, err := apiClient.FetchAlerts(context.Background(), "cursor-123")
 
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 	assert.Empty(t, resp.Alerts)
 	assert.Equal(t, "cursor-456", resp.To)
 }
+
+func TestAPIClient_FetchAlertLists(t *testing.T) {
+	newClient := func(t *testing.T, server *httptest.Server) *APIClient {
+		api := &plugintest.API{}
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+		api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
+		api.On("KVDelete", mock.Anything).Return(nil).Maybe()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		authManager := NewAuthManager(server.URL, "test-user", "test-pass", api, "test-backend", &client.Log, nil)
+		return NewAPIClient(server.URL, authManager, &client.Log, nil)
+	}
+
+	newServer := func(listsHandler http.HandlerFunc) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path == "/auth/1/userAuthorization" {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"authorizationToken": "test-token",
+					"expirationTime":     time.Now().Add(1 * time.Hour).UnixMilli(),
+				})
+				return
+			}
+			if r.URL.Path == backend.DefaultListsPath {
+				listsHandler(w, r)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+	}
+
+	t.Run("parses the alert lists", func(t *testing.T) {
+		server := newServer(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Dmauth test-token", r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"watchlists": {"TOPIC": [{"id": 101, "name": "Severe Weather", "type": "TOPIC"}], "COMPANY": [{"id": 102, "name": "Acme Corp", "type": "COMPANY"}]}}`))
+		})
+		defer server.Close()
+
+		resp, err := newClient(t, server).FetchAlertLists(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []Watchlist{{ID: "101", Name: "Severe Weather", Type: "TOPIC"}}, resp.Watchlists["TOPIC"])
+		assert.Equal(t, []Watchlist{{ID: "102", Name: "Acme Corp", Type: "COMPANY"}}, resp.Watchlists["COMPANY"])
+	})
+
+	t.Run("retries once with a new token", func(t *testing.T) {
+		requests := 0
+		server := newServer(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		defer server.Close()
+
+		_, err := newClient(t, server).FetchAlertLists(context.Background())
+		var authErr *AuthError
+		require.ErrorAs(t, err, &authErr)
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("endpoint not available", func(t *testing.T) {
+		server := newServer(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		defer server.Close()
+
+		_, err := newClient(t, server).FetchAlertLists(context.Background())
+		require.ErrorIs(t, err, backend.ErrTaxonomyUnsupported)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		server := newServer(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		})
+		defer server.Close()
+
+		_, err := newClient(t, server).FetchAlertLists(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected HTTP status 502")
+	})
+}
@@ -1,7 +1,9 @@
 package dataminr
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -9,12 +11,14 @@ import (
 	"github.com/mattermost/mattermost/server/public/pluginapi"
 
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/summarizer"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/translator"
 )
 
 // init registers the Dataminr backend factory
 func init() {
-	backend.RegisterBackendFactory("dataminr", func(config backend.Config, api *pluginapi.Client, papi plugin.API, poster backend.AlertPoster, deduplicator backend.Deduplicator, disableCallback backend.DisableCallback) (backend.Backend, error) {
-		return New(config, api, papi, poster, deduplicator, disableCallback)
+	backend.RegisterBackendFactory("dataminr", func(config backend.Config, api *pluginapi.Client, papi plugin.API, poster backend.AlertPoster, deduplicator backend.Deduplicator, disableCallback backend.DisableCallback, userAgent string) (backend.Backend, error) {
+		return New(config, api, papi, poster, deduplicator, disableCallback, userAgent)
 	})
 }
 
@@ -28,13 +32,15 @@ type Backend struct {
 	apiClient   *APIClient
 	processor   *AlertProcessor
 	stateStore  *StateStore
+	history     backend.PollHistoryStore
 	poller      *Poller
+	logger      *backend.CapturingLogger
 	mu          sync.RWMutex
 	running     bool
 }
 
-// New creates a new Dataminr backend instance
-func New(config backend.Config, api *pluginapi.Client, papi plugin.API, poster backend.AlertPoster, deduplicator backend.Deduplicator, disableCallback backend.DisableCallback) (*Backend, error) {
+// New creates a new Dataminr backend instance, identifying its requests with the user agent
+func New(config backend.Config, api *pluginapi.Client, papi plugin.API, poster backend.AlertPoster, deduplicator backend.Deduplicator, disableCallback backend.DisableCallback, userAgent string) (*Backend, error) {
 	// Validate configuration
 	if config.Type != "dataminr" {
 		return nil, fmt.Errorf("invalid backend type: %s (expected: dataminr)", config.Type)
@@ -55,8 +61,16 @@ func New(config backend.Config, api *pluginapi.Client, papi plugin.API, poster b
 		return nil, fmt.Errorf("channel ID is required")
 	}
 
-	// Create state store
+	// Create state store and the logger capturing the backend's recent log lines
 	stateStore := NewStateStore(papi, config.ID)
+	logger := backend.NewCapturingLogger(&api.Log, config.LogLevel, backend.LogCaptureLines)
+
+	// Create the HTTP transport for custom TLS, proxy and logging settings (shared by auth and API requests)
+	transport, err := newTransport(config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP transport: %w", err)
+	}
+	tagging := newTaggingTransport(transport, config.RequestTagging.UserAgentHeader(userAgent), config.RequestTagging.CorrelationHeader())
 
 	// Create auth manager
 	authManager := NewAuthManager(
@@ -65,26 +79,68 @@ func New(config backend.Config, api *pluginapi.Client, papi plugin.API, poster b
 		config.APIKey,
 		papi,
 		config.ID,
-		api.Log,
+		logger,
+		tagging,
 	)
+	authManager.SetTimeout(config.RequestLimits.Timeout())
+	authManager.SetMaxResponseSize(config.RequestLimits.MaxResponseBytes())
+	authManager.SetAuthPath(config.APIEndpoints.AuthEndpoint())
 
 	// Create API client
-	apiClient := NewAPIClient(config.URL, authManager, api.Log)
+	apiClient := NewAPIClient(config.URL, authManager, logger, tagging)
+	apiClient.SetAlertLists(config.AlertListIDs)
+	apiClient.SetRequestLimits(config.RequestLimits)
+	apiClient.SetEndpoints(config.APIEndpoints)
+
+	b := newBackend(config, api, papi, poster, deduplicator, disableCallback, stateStore, logger, apiClient)
+	b.authManager = authManager
+	b.apiClient = apiClient
+	b.poller.SetRequestTagging(tagging)
+	return b, nil
+}
 
-	// Create backend instance
+// newBackend creates a backend that polls alerts from the given fetcher and posts them
+// through the regular processing pipeline
+func newBackend(
+	config backend.Config,
+	api *pluginapi.Client,
+	papi plugin.API,
+	poster backend.AlertPoster,
+	deduplicator backend.Deduplicator,
+	disableCallback backend.DisableCallback,
+	stateStore *StateStore,
+	logger *backend.CapturingLogger,
+	fetcher AlertFetcher,
+) *Backend {
 	b := &Backend{
-		config:      config,
-		api:         api,
-		papi:        papi,
-		poster:      poster,
-		authManager: authManager,
-		apiClient:   apiClient,
-		stateStore:  stateStore,
-		running:     false,
+		config:     config,
+		api:        api,
+		papi:       papi,
+		poster:     poster,
+		stateStore: stateStore,
+		history:    stateStore,
+		logger:     logger,
+		running:    false,
 	}
 
-	// Create alert processor with poster, channel ID, and shared deduplicator
-	b.processor = NewAlertProcessor(api, config.Type, config.Name, poster, config.ChannelID, deduplicator)
+	// Create alert processor with poster, channel ID, shared deduplicator and optional quiet hours
+	quietHours := NewQuietHoursGate(config.QuietHours, stateStore)
+	b.processor = NewAlertProcessor(api, config.ID, config.Type, config.Name, poster, config.ChannelID, deduplicator, quietHours)
+	b.processor.SetRoutes(config.Routes)
+	b.processor.SetBatchLimits(config.PostConcurrency, config.MaxAlertsPerBatch)
+	b.processor.SetAttachRawPayload(config.AttachRawPayload)
+	b.processor.SetDryRun(config.DryRun)
+	b.processor.SetPendingStore(stateStore)
+	b.processor.SetSubscriptions(backend.NewSubscriptionStore(papi))
+	b.processor.SetTopicMutes(backend.NewTopicMuteStore(papi))
+	b.processor.SetAlertListMutes(backend.NewAlertListMuteStore(papi))
+	b.processor.SetLogger(logger)
+	if config.Summarizer != nil {
+		b.processor.SetSummarizer(summarizer.NewHTTPSummarizer(*config.Summarizer), config.Summarizer.MinLength())
+	}
+	if config.Translator != nil {
+		b.processor.SetTranslator(translator.NewHTTPTranslator(*config.Translator), *config.Translator)
+	}
 
 	// Create poller
 	pollInterval := time.Duration(config.PollIntervalSeconds) * time.Second
@@ -94,20 +150,30 @@ func New(config backend.Config, api *pluginapi.Client, papi plugin.API, poster b
 		config.ID,
 		config.Name,
 		pollInterval,
-		apiClient,
+		fetcher,
 		b.processor,
 		stateStore,
 		disableCallback,
 	)
-
-	return b, nil
+	b.poller.SetLogger(logger)
+	b.poller.SetStartupJitter(time.Duration(config.StartupJitterSeconds) * time.Second)
+	b.poller.SetCatchUp(config.CatchUpWindow(), config.PostHistoricalAlerts)
+	b.poller.SetCircuitBreaker(config.CircuitBreaker)
+	b.poller.SetFailureThreshold(config.MaxFailures())
+	b.poller.SetFailureWarning(poster)
+
+	return b
 }
 
-// Start begins the backend's polling lifecycle
-func (b *Backend) Start() error {
+// Start begins the backend's polling lifecycle. Polling continues after the context is cancelled.
+func (b *Backend) Start(ctx context.Context) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("backend not started: %w", err)
+	}
+
 	if b.running {
 		return fmt.Errorf("backend already running")
 	}
@@ -118,11 +184,16 @@ func (b *Backend) Start() error {
 
 	// Reset failure state when starting an enabled backend
 	// This ensures a fresh start when re-enabling after failures
-	if err := b.stateStore.ResetFailures(); err != nil {
-		b.api.Log.Warn("Failed to reset failure count on start", "id", b.config.ID, "error", err.Error())
+	if err := b.stateStore.updatePollState(func(state *PollState) {
+		state.Failures = 0
+		state.LastError = ""
+	}); err != nil {
+		b.logger.Warn("Failed to reset failure state on start", "id", b.config.ID, "error", err.Error())
 	}
-	if err := b.stateStore.SaveLastError(""); err != nil {
-		b.api.Log.Warn("Failed to clear last error on start", "id", b.config.ID, "error", err.Error())
+	if b.config.CircuitBreaker != nil {
+		if err := b.stateStore.ClearCooldown(); err != nil {
+			b.logger.Warn("Failed to clear cool-down state on start", "id", b.config.ID, "error", err.Error())
+		}
 	}
 
 	// Start the poller
@@ -131,12 +202,16 @@ func (b *Backend) Start() error {
 	}
 
 	b.running = true
-	b.api.Log.Info("Dataminr backend started", "id", b.config.ID, "name", b.config.Name)
+	b.logger.Info("Dataminr backend started", "id", b.config.ID, "name", b.config.Name)
+	if b.config.DryRun {
+		b.logger.Warn("Dry-run mode is enabled: alerts are logged instead of posted", "id", b.config.ID, "name", b.config.Name)
+	}
 	return nil
 }
 
-// Stop gracefully shuts down the backend
-func (b *Backend) Stop() error {
+// Stop gracefully shuts down the backend. An in-flight poll cycle is cancelled once the
+// context is done.
+func (b *Backend) Stop(ctx context.Context) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -144,14 +219,77 @@ func (b *Backend) Stop() error {
 		return nil
 	}
 
-	// Stop the poller
-	if err := b.poller.Stop(); err != nil {
-		b.api.Log.Error("Failed to stop poller", "id", b.config.ID, "error", err.Error())
+	// Stop the poller. It gives up its job and cancels the in-flight poll cycle even when
+	// closing the job fails or takes too long, so the backend is marked stopped either way.
+	if err := b.poller.Stop(ctx); err != nil {
+		b.running = false
+		b.logger.Error("Failed to stop poller", "id", b.config.ID, "error", err.Error())
 		return fmt.Errorf("failed to stop poller: %w", err)
 	}
 
 	b.running = false
-	b.api.Log.Info("Dataminr backend stopped", "id", b.config.ID, "name", b.config.Name)
+	b.logger.Info("Dataminr backend stopped", "id", b.config.ID, "name", b.config.Name)
+	return nil
+}
+
+// SetStatusListener sets the function called when a poll cycle may have changed the backend's
+// status, e.g. it started failing or a cool-down started or ended
+func (b *Backend) SetStatusListener(listener func()) {
+	b.poller.SetStatusListener(listener)
+}
+
+// SetPollHistoryStore records the outcomes of poll cycles in a store other than the backend's
+// state, e.g. in the database. It must be set before the backend is started.
+func (b *Backend) SetPollHistoryStore(store backend.PollHistoryStore) {
+	b.history = store
+	b.poller.SetPollHistoryStore(store)
+}
+
+// AddStage adds a stage to the backend's alert pipeline, running it after the stages already
+// added to its phase. It must be called before the backend is started.
+func (b *Backend) AddStage(phase StagePhase, name string, process StageFunc) {
+	b.processor.AddStage(phase, name, process)
+}
+
+// Stopped returns a channel closed once the polling job has closed, so the registry waits for
+// a poll cycle Stop gave up on before registering the backend again
+func (b *Backend) Stopped() <-chan struct{} {
+	return b.poller.Stopped()
+}
+
+// UpdateConfig applies a new name, channel, channel routes, alert lists, quiet hours, batch
+// limit and poll interval without restarting the backend, so its cursor, authentication token and failure count are
+// kept. Polling pauses while the in-flight poll cycle finishes, at most until the context is done.
+func (b *Backend) UpdateConfig(ctx context.Context, config backend.Config) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if config.ID != b.config.ID {
+		return fmt.Errorf("configuration of backend %s can't be applied to backend %s", config.ID, b.config.ID)
+	}
+
+	interval := time.Duration(config.PollIntervalSeconds) * time.Second
+	if err := b.poller.Reconfigure(ctx, config.Name, interval, func() {
+		b.processor.SetTarget(config.Name, config.ChannelID)
+		b.processor.SetRoutes(config.Routes)
+		b.processor.SetQuietHours(config.QuietHours, b.stateStore)
+		b.processor.SetBatchLimits(b.config.PostConcurrency, config.MaxAlertsPerBatch)
+		if b.apiClient != nil {
+			b.apiClient.SetAlertLists(config.AlertListIDs)
+		}
+	}); err != nil {
+		return fmt.Errorf("failed to reconfigure poller: %w", err)
+	}
+
+	b.config.Name = config.Name
+	b.config.ChannelID = config.ChannelID
+	b.config.Routes = config.Routes
+	b.config.AlertListIDs = config.AlertListIDs
+	b.config.QuietHours = config.QuietHours
+	b.config.MaxAlertsPerBatch = config.MaxAlertsPerBatch
+	b.config.PollIntervalSeconds = config.PollIntervalSeconds
+
+	b.logger.Info("Dataminr backend configuration updated", "id", b.config.ID, "name", b.config.Name)
 	return nil
 }
 
@@ -162,6 +300,9 @@ func (b *Backend) GetID() string {
 
 // GetName returns the display name for this backend
 func (b *Backend) GetName() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	return b.config.Name
 }
 
@@ -176,51 +317,108 @@ func (b *Backend) GetStatus() backend.Status {
 	defer b.mu.RUnlock()
 
 	status := backend.Status{
-		Enabled: b.config.Enabled,
+		Enabled:      b.config.Enabled,
+		Running:      b.running,
+		NextPollTime: b.poller.NextRunAt(),
+	}
+
+	// Get the poll times, failure tracking and phase from state
+	pollState, err := b.stateStore.GetPollState()
+	if err != nil {
+		b.logger.Warn("Failed to get poll state", "id", b.config.ID, "error", err.Error())
+	} else {
+		status.LastPollTime = pollState.LastPoll
+		status.LastSuccessTime = pollState.LastSuccess
+		status.ConsecutiveFailures = pollState.Failures
+		status.LastError = pollState.LastError
+		status.CatchUp = pollState.CatchUp
 	}
 
-	// Get last poll time from state
-	lastPoll, err := b.stateStore.GetLastPoll()
+	// Get recent error history
+	recentErrors, err := b.stateStore.GetRecentErrors()
 	if err != nil {
-		b.api.Log.Warn("Failed to get last poll time", "id", b.config.ID, "error", err.Error())
+		b.logger.Warn("Failed to get recent errors", "id", b.config.ID, "error", err.Error())
 	} else {
-		status.LastPollTime = lastPoll
+		status.RecentErrors = recentErrors
 	}
 
-	// Get last success time from state
-	lastSuccess, err := b.stateStore.GetLastSuccess()
+	// Get the alerts dropped after failing to post
+	failedDeliveries, err := b.stateStore.GetFailedDeliveries()
 	if err != nil {
-		b.api.Log.Warn("Failed to get last success time", "id", b.config.ID, "error", err.Error())
+		b.logger.Warn("Failed to get failed deliveries", "id", b.config.ID, "error", err.Error())
 	} else {
-		status.LastSuccessTime = lastSuccess
+		status.FailedDeliveries = failedDeliveries
 	}
 
-	// Get consecutive failures from state
-	failures, err := b.stateStore.GetFailures()
+	// Summarize the poll history
+	history, err := b.history.GetPollHistory()
 	if err != nil {
-		b.api.Log.Warn("Failed to get failure count", "id", b.config.ID, "error", err.Error())
+		b.logger.Warn("Failed to get poll history", "id", b.config.ID, "error", err.Error())
 	} else {
-		status.ConsecutiveFailures = failures
+		status.History = backend.SummarizePollHistory(history, time.Now().Add(-backend.PollHistoryRetention))
+	}
+
+	// Get pause state
+	paused, pausedUntil, err := b.stateStore.GetPause(time.Now())
+	if err != nil {
+		b.logger.Warn("Failed to get pause state", "id", b.config.ID, "error", err.Error())
+	} else {
+		status.Paused = paused
+		status.PausedUntil = pausedUntil
+	}
+
+	// Get circuit breaker cool-down state
+	if b.config.CircuitBreaker != nil {
+		cooldown, err := b.stateStore.GetCooldown()
+		if err != nil {
+			b.logger.Warn("Failed to get cool-down state", "id", b.config.ID, "error", err.Error())
+		} else {
+			status.CooldownCycles = cooldown.Cycles
+			if time.Now().Before(cooldown.Until) {
+				status.CooldownUntil = cooldown.Until
+			}
+		}
+	}
+
+	// Get the polling lifecycle phase; a disabled backend is never polling
+	switch {
+	case !b.config.Enabled:
+		status.Phase = backend.PhaseDisabled
+	case err != nil:
+		// Failure already logged when loading the poll state
+	case pollState.Phase == "" || pollState.Phase == backend.PhaseDisabled:
+		// Enabled again but the poller has not saved a phase since
+		status.Phase = backend.PhaseStarting
+	default:
+		status.Phase = pollState.Phase
 	}
 
-	// Get last error from state
-	lastError, err := b.stateStore.GetLastError()
+	// Backends without an auth manager replay alerts locally and need no authentication
+	if b.authManager == nil {
+		status.IsAuthenticated = true
+		status.Health = status.Classify(time.Now())
+		return status
+	}
+
+	// Get the outcome of the last credential check
+	credentialCheck, err := b.stateStore.GetCredentialCheck()
 	if err != nil {
-		b.api.Log.Warn("Failed to get last error", "id", b.config.ID, "error", err.Error())
+		b.logger.Warn("Failed to get credential check", "id", b.config.ID, "error", err.Error())
 	} else {
-		status.LastError = lastError
+		status.CredentialCheck = credentialCheck
 	}
 
 	// Check authentication status
 	token, expiry, err := b.stateStore.GetAuthToken()
 	if err != nil {
-		b.api.Log.Warn("Failed to check auth token", "id", b.config.ID, "error", err.Error())
+		b.logger.Warn("Failed to check auth token", "id", b.config.ID, "error", err.Error())
 		status.IsAuthenticated = false
 	} else {
 		// Token is valid if it exists and hasn't expired
 		status.IsAuthenticated = token != "" && time.Now().Before(expiry)
 	}
 
+	status.Health = status.Classify(time.Now())
 	return status
 }
 
@@ -229,3 +427,185 @@ func (b *Backend) GetStatus() backend.Status {
 func (b *Backend) ClearOperationalState() error {
 	return b.stateStore.ClearOperationalState()
 }
+
+// GetState returns the backend's persisted polling state without the authentication token
+func (b *Backend) GetState() (backend.StateSnapshot, error) {
+	var state backend.StateSnapshot
+	var err error
+
+	pollState, err := b.stateStore.GetPollState()
+	if err != nil {
+		return state, err
+	}
+	state.Cursor = pollState.Cursor
+	state.WindowStart = pollState.WindowStart
+	state.ConsecutiveFailures = pollState.Failures
+	state.LastPollTime = pollState.LastPoll
+	state.LastSuccessTime = pollState.LastSuccess
+	state.NextPollTime = b.poller.NextRunAt()
+	state.LastError = pollState.LastError
+	state.Phase = pollState.Phase
+	state.CatchUp = pollState.CatchUp
+
+	if state.CursorResetPending, err = b.stateStore.IsCursorResetPending(); err != nil {
+		return state, err
+	}
+
+	token, expiry, err := b.stateStore.GetAuthToken()
+	if err != nil {
+		return state, err
+	}
+	state.HasAuthToken = token != ""
+	if state.HasAuthToken {
+		state.AuthTokenExpiry = expiry
+	}
+
+	if state.Paused, state.PausedUntil, err = b.stateStore.GetPause(time.Now()); err != nil {
+		return state, err
+	}
+
+	cooldown, err := b.stateStore.GetCooldown()
+	if err != nil {
+		return state, err
+	}
+	state.CooldownUntil = cooldown.Until
+	state.CooldownCycles = cooldown.Cycles
+
+	buffered, err := b.stateStore.GetBufferedAlerts()
+	if err != nil {
+		return state, err
+	}
+	state.BufferedAlerts = len(buffered)
+
+	pending, err := b.stateStore.GetPendingAlerts()
+	if err != nil {
+		return state, err
+	}
+	state.PendingAlerts = len(pending)
+
+	failedDeliveries, err := b.stateStore.GetFailedDeliveries()
+	if err != nil {
+		return state, err
+	}
+	state.FailedDeliveries = len(failedDeliveries)
+
+	return state, nil
+}
+
+// ResetState resets part of the backend's persisted polling state. A cursor reset is applied
+// at the start of the next poll cycle, which then runs the catch-up routine.
+func (b *Backend) ResetState(scope backend.StateResetScope) error {
+	resetCursor := scope == backend.StateResetCursor || scope == backend.StateResetAll
+	resetAuth := scope == backend.StateResetAuth || scope == backend.StateResetAll
+	resetFailures := scope == backend.StateResetFailures || scope == backend.StateResetAll
+	if !resetCursor && !resetAuth && !resetFailures {
+		return fmt.Errorf("unknown state reset scope %q", scope)
+	}
+
+	if resetCursor {
+		if err := b.stateStore.RequestCursorReset(); err != nil {
+			return err
+		}
+	}
+	if resetAuth {
+		if err := b.stateStore.ClearAuthToken(); err != nil {
+			return err
+		}
+	}
+	if resetFailures {
+		if _, err := b.stateStore.ResetFailures(); err != nil {
+			return err
+		}
+		if err := b.stateStore.ClearLastError(); err != nil {
+			return err
+		}
+		if err := b.stateStore.ClearCooldown(); err != nil {
+			return err
+		}
+		if err := b.stateStore.ClearBackoff(); err != nil {
+			return err
+		}
+		if err := b.stateStore.ClearFailedDeliveries(); err != nil {
+			return err
+		}
+	}
+
+	b.logger.Info("Dataminr backend state reset", "id", b.config.ID, "name", b.GetName(), "scope", string(scope))
+	return nil
+}
+
+// GetPollHistory returns the recorded poll cycle outcomes, oldest first
+func (b *Backend) GetPollHistory() ([]backend.PollSample, error) {
+	return b.history.GetPollHistory()
+}
+
+// GetRecentLogs returns the backend's most recent log lines captured on this server node
+func (b *Backend) GetRecentLogs() []backend.LogEntry {
+	return b.logger.Recent()
+}
+
+// FetchTaxonomy fetches the alert lists available to the account. The names of topic alert
+// lists are the topics alerts can be tagged with. The simulator has no taxonomy to fetch.
+func (b *Backend) FetchTaxonomy(ctx context.Context) (backend.Taxonomy, error) {
+	if b.apiClient == nil {
+		return backend.Taxonomy{}, backend.ErrTaxonomyUnsupported
+	}
+
+	lists, err := b.apiClient.FetchAlertLists(ctx)
+	if err != nil {
+		return backend.Taxonomy{}, err
+	}
+
+	taxonomy := backend.Taxonomy{AlertLists: []backend.TaxonomyList{}, Topics: []string{}, FetchedAt: time.Now()}
+	for listType, watchlists := range lists.Watchlists {
+		for _, list := range watchlists {
+			taxonomy.AlertLists = append(taxonomy.AlertLists, backend.TaxonomyList{ID: list.ID.String(), Name: list.Name, Type: listType})
+			if listType == topicListType {
+				taxonomy.Topics = append(taxonomy.Topics, list.Name)
+			}
+		}
+	}
+	sort.Slice(taxonomy.AlertLists, func(i, j int) bool { return taxonomy.AlertLists[i].Name < taxonomy.AlertLists[j].Name })
+	sort.Strings(taxonomy.Topics)
+	return taxonomy, nil
+}
+
+// CheckCredentials validates the credentials by acquiring a new token and records the
+// outcome for GetStatus. The simulator needs no credentials.
+func (b *Backend) CheckCredentials(ctx context.Context) (backend.CredentialCheck, error) {
+	if b.authManager == nil {
+		return backend.CredentialCheck{}, backend.ErrCredentialCheckUnsupported
+	}
+
+	check := b.authManager.CheckCredentials(ctx, time.Now())
+	if check.Valid {
+		b.logger.Info("Credential check passed", "id", b.config.ID, "tokenExpiry", check.TokenExpiry.Format(time.RFC3339))
+	} else {
+		b.logger.Warn("Credential check failed", "id", b.config.ID, "rejected", check.Rejected, "error", check.Error)
+	}
+
+	if err := b.stateStore.SaveCredentialCheck(check); err != nil {
+		return check, err
+	}
+	return check, nil
+}
+
+// Pause temporarily stops polling until the given time (zero pauses until resumed)
+func (b *Backend) Pause(until time.Time) error {
+	if err := b.stateStore.SavePause(until); err != nil {
+		return err
+	}
+
+	b.logger.Info("Dataminr backend paused", "id", b.config.ID, "name", b.GetName(), "until", until)
+	return nil
+}
+
+// Resume ends a pause so polling continues from the saved cursor
+func (b *Backend) Resume() error {
+	if err := b.stateStore.ClearPause(); err != nil {
+		return err
+	}
+
+	b.logger.Info("Dataminr backend resumed", "id", b.config.ID, "name", b.GetName())
+	return nil
+}
@@ -1,6 +1,7 @@
 package dataminr
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -31,6 +32,7 @@ func TestDataminrBackend_FullIntegration(t *testing.T) {
 			// Handle authentication request
 			assert.Equal(t, "POST", r.Method)
 			assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+			assert.Equal(t, "mattermost-plugin-dataminr/1.0.0 Mattermost/10.5.0 Integration Tests", r.Header.Get("User-Agent"))
 
 			resp := AuthResponse{
 				AuthorizationToken: authToken,
@@ -44,6 +46,7 @@ func TestDataminrBackend_FullIntegration(t *testing.T) {
 			assert.Equal(t, "GET", r.Method)
 			assert.Equal(t, "Dmauth "+authToken, r.Header.Get("Authorization"))
 			assert.Equal(t, "19", r.URL.Query().Get("alertversion"))
+			assert.Equal(t, "mattermost-plugin-dataminr/1.0.0 Mattermost/10.5.0 Integration Tests", r.Header.Get("User-Agent"))
 
 			// Return test alerts
 			resp := AlertsResponse{
@@ -83,22 +86,14 @@ func TestDataminrBackend_FullIntegration(t *testing.T) {
 		APIKey:              "test-api-key",
 		ChannelID:           "test-channel-id",
 		PollIntervalSeconds: 30,
+		RequestTagging:      &backend.RequestTaggingSettings{UserAgent: "Integration Tests"},
 	}
 
 	// Set up mock plugin API
 	mockAPI := &plugintest.API{}
 
 	// Mock KV operations for state storage
-	kvStore := make(map[string][]byte)
-	mockAPI.On("KVSet", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
-		key := args.String(0)
-		value := args.Get(1).([]byte)
-		kvStore[key] = value
-	}).Return(nil)
-
-	mockAPI.On("KVGet", mock.Anything).Return(func(key string) []byte {
-		return kvStore[key]
-	}, nil)
+	mockKVStore(mockAPI)
 
 	mockAPI.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
@@ -116,7 +111,7 @@ func TestDataminrBackend_FullIntegration(t *testing.T) {
 	}
 
 	// Create backend
-	b, err := New(config, client, mockAPI, mockPoster, NewMockDeduplicator(), nil)
+	b, err := New(config, client, mockAPI, mockPoster, NewMockDeduplicator(), nil, "mattermost-plugin-dataminr/1.0.0 Mattermost/10.5.0")
 	require.NoError(t, err)
 	require.NotNil(t, b)
 
@@ -136,7 +131,7 @@ func TestDataminrBackend_FullIntegration(t *testing.T) {
 
 	// Test fetching alerts
 	t.Run("fetch alerts from API", func(t *testing.T) {
-		response, err := b.apiClient.FetchAlerts("")
+		response, err := b.apiClient.FetchAlerts(context.Background(), "")
 		require.NoError(t, err)
 		require.NotNil(t, response)
 		assert.Len(t, response.Alerts, 2)
@@ -156,12 +151,17 @@ func TestDataminrBackend_FullIntegration(t *testing.T) {
 			},
 		}
 
-		newCount, err := b.processor.ProcessAlerts(alerts)
+		newCount, err := b.processor.ProcessAlerts(context.Background(), alerts)
 		require.NoError(t, err)
 		assert.Equal(t, 1, newCount)
 		assert.Len(t, postedAlerts, 1)
 		assert.Equal(t, "alert-003", postedAlerts[0].AlertID)
 		assert.Equal(t, "Integration Test Backend", postedAlerts[0].BackendName)
+
+		// Posted alerts are removed from the pending queue
+		pending, err := b.stateStore.GetPendingAlerts()
+		require.NoError(t, err)
+		assert.Empty(t, pending)
 	})
 
 	// Test deduplication
@@ -182,7 +182,7 @@ func TestDataminrBackend_FullIntegration(t *testing.T) {
 		}
 
 		initialCount := len(postedAlerts)
-		newCount, err := b.processor.ProcessAlerts(alerts)
+		newCount, err := b.processor.ProcessAlerts(context.Background(), alerts)
 		require.NoError(t, err)
 		assert.Equal(t, 1, newCount) // Only 1 new alert (alert-004)
 		assert.Len(t, postedAlerts, initialCount+1)
@@ -206,7 +206,7 @@ func TestDataminrBackend_FullIntegration(t *testing.T) {
 		b.poller.SetScheduler(mockScheduler)
 
 		// Start the backend
-		err = b.Start()
+		err = b.Start(context.Background())
 		require.NoError(t, err)
 		assert.True(t, b.running)
 
@@ -240,7 +240,7 @@ func TestDataminrBackend_FullIntegration(t *testing.T) {
 		assert.Greater(t, len(postedAlerts), initialHandledCount)
 
 		// Stop the backend
-		err = b.Stop()
+		err = b.Stop(context.Background())
 		require.NoError(t, err)
 		assert.False(t, b.running)
 	})
@@ -305,25 +305,17 @@ func TestDataminrBackend_ErrorHandling(t *testing.T) {
 	mockAPI := &plugintest.API{}
 
 	// Mock KV operations
-	kvStore := make(map[string][]byte)
-	mockAPI.On("KVSet", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
-		key := args.String(0)
-		value := args.Get(1).([]byte)
-		kvStore[key] = value
-	}).Return(nil)
-
-	mockAPI.On("KVGet", mock.Anything).Return(func(key string) []byte {
-		return kvStore[key]
-	}, nil)
+	mockKVStore(mockAPI)
 
 	mockAPI.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	mockAPI.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	mockAPI.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	mockAPI.On("LogWarn", "Backend approaching max consecutive failures", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 
 	client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
 
-	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
 	require.NoError(t, err)
 
 	t.Run("handle API errors and track failures", func(t *testing.T) {
@@ -343,7 +335,7 @@ func TestDataminrBackend_ErrorHandling(t *testing.T) {
 		b.poller.SetScheduler(mockScheduler)
 
 		// Start the backend
-		err = b.Start()
+		err = b.Start(context.Background())
 		require.NoError(t, err)
 
 		// Trigger poll cycles that will fail
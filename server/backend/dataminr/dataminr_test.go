@@ -1,7 +1,10 @@
 package dataminr
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -163,7 +166,7 @@ func TestNew(t *testing.T) {
 			mockAPI := &plugintest.API{}
 			client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
 
-			b, err := New(tt.config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+			b, err := New(tt.config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -202,7 +205,7 @@ func TestDataminrBackend_Getters(t *testing.T) {
 	mockAPI := &plugintest.API{}
 	client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
 
-	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
 	require.NoError(t, err)
 
 	assert.Equal(t, "backend-123", b.GetID())
@@ -252,16 +255,14 @@ func TestDataminrBackend_Start(t *testing.T) {
 
 			mockAPI := &plugintest.API{}
 			mockAPI.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
-			// Mock GetCursor for Start() check (return existing cursor to avoid catch-up)
-			mockAPI.On("KVGet", "backend_test-backend_cursor").Return([]byte("existing-cursor"), nil).Maybe()
-			// When enabled, expect KVSet calls to reset failure state
+			// When enabled, expect the failure state reset to load the poll state
 			if tt.enabled {
-				mockAPI.On("KVSet", "backend_test-backend_failures", []byte("0")).Return(nil)
-				mockAPI.On("KVSet", "backend_test-backend_last_error", []byte("")).Return(nil)
+				mockAPI.On("KVGet", mock.Anything).Return(nil, nil)
+				mockAPI.On("KVSetWithOptions", "backend_test-backend_state", mock.Anything, mock.Anything).Return(true, nil).Maybe()
 			}
 			client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
 
-			b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+			b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
 			require.NoError(t, err)
 
 			// Inject mock scheduler
@@ -275,7 +276,7 @@ func TestDataminrBackend_Start(t *testing.T) {
 			}
 			b.poller.SetScheduler(mockScheduler)
 
-			err = b.Start()
+			err = b.Start(context.Background())
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -311,7 +312,7 @@ func TestDataminrBackend_StartAlreadyRunning(t *testing.T) {
 	mockAPI := &plugintest.API{}
 	client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
 
-	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
 	require.NoError(t, err)
 
 	// Manually set running to true
@@ -319,11 +320,39 @@ func TestDataminrBackend_StartAlreadyRunning(t *testing.T) {
 	b.running = true
 	b.mu.Unlock()
 
-	err = b.Start()
+	err = b.Start(context.Background())
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "backend already running")
 }
 
+func TestDataminrBackend_StartCancelled(t *testing.T) {
+	config := backend.Config{
+		ID:                  "test-backend",
+		Name:                "Test Backend",
+		Type:                "dataminr",
+		Enabled:             true,
+		URL:                 "https://api.dataminr.com",
+		APIId:               "test-id",
+		APIKey:              "test-key",
+		ChannelID:           "channel123",
+		PollIntervalSeconds: 30,
+	}
+
+	mockAPI := &plugintest.API{}
+	client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = b.Start(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, b.running)
+	mockAPI.AssertExpectations(t)
+}
+
 func TestDataminrBackend_StartResetsFailureState(t *testing.T) {
 	config := backend.Config{
 		ID:                  "test-backend",
@@ -339,12 +368,11 @@ func TestDataminrBackend_StartResetsFailureState(t *testing.T) {
 
 	mockAPI := &plugintest.API{}
 	mockAPI.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
-	// Expect KVSet calls to reset failures and clear error
-	mockAPI.On("KVSet", "backend_test-backend_failures", []byte("0")).Return(nil)
-	mockAPI.On("KVSet", "backend_test-backend_last_error", []byte("")).Return(nil)
+	kvStore := mockKVStore(mockAPI)
+	kvStore["backend_test-backend_state"] = mustMarshalPollState(PollState{Cursor: "cursor-1", Failures: 4, LastError: "timeout"})
 	client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
 
-	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
 	require.NoError(t, err)
 
 	// Inject mock scheduler
@@ -356,12 +384,12 @@ func TestDataminrBackend_StartResetsFailureState(t *testing.T) {
 	}
 	b.poller.SetScheduler(mockScheduler)
 
-	err = b.Start()
+	err = b.Start(context.Background())
 	require.NoError(t, err)
 	assert.True(t, b.running)
 
 	// Verify that failure state was reset
-	mockAPI.AssertExpectations(t)
+	assert.Equal(t, PollState{Cursor: "cursor-1", Phase: backend.PhaseStarting}, storedPollState(t, kvStore, "test-backend"))
 }
 
 func TestDataminrBackend_Stop(t *testing.T) {
@@ -381,10 +409,10 @@ func TestDataminrBackend_Stop(t *testing.T) {
 		mockAPI := &plugintest.API{}
 		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
 
-		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
 		require.NoError(t, err)
 
-		err = b.Stop()
+		err = b.Stop(context.Background())
 		assert.NoError(t, err)
 		assert.False(t, b.running)
 	})
@@ -392,12 +420,12 @@ func TestDataminrBackend_Stop(t *testing.T) {
 	t.Run("stop when running", func(t *testing.T) {
 		mockAPI := &plugintest.API{}
 		mockAPI.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
-		// Expect KVSet calls when Start resets failure state
-		mockAPI.On("KVSet", "backend_test-backend_failures", []byte("0")).Return(nil)
-		mockAPI.On("KVSet", "backend_test-backend_last_error", []byte("")).Return(nil)
+		// Expect the poll state to be loaded when Start resets failure state
+		mockAPI.On("KVGet", mock.Anything).Return(nil, nil)
+		mockAPI.On("KVSetWithOptions", "backend_test-backend_state", mock.Anything, mock.Anything).Return(true, nil)
 		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
 
-		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
 		require.NoError(t, err)
 
 		// Set up mock job
@@ -410,12 +438,12 @@ func TestDataminrBackend_Stop(t *testing.T) {
 		b.poller.SetScheduler(mockScheduler)
 
 		// Start the backend
-		err = b.Start()
+		err = b.Start(context.Background())
 		require.NoError(t, err)
 		assert.True(t, b.running)
 
 		// Stop the backend
-		err = b.Stop()
+		err = b.Stop(context.Background())
 		assert.NoError(t, err)
 		assert.False(t, b.running)
 		assert.True(t, mockJob.closed)
@@ -424,6 +452,90 @@ func TestDataminrBackend_Stop(t *testing.T) {
 	})
 }
 
+func TestDataminrBackend_UpdateConfig(t *testing.T) {
+	config := backend.Config{
+		ID:                  "test-backend",
+		Name:                "Test Backend",
+		Type:                "dataminr",
+		Enabled:             true,
+		URL:                 "https://api.dataminr.com",
+		APIId:               "test-id",
+		APIKey:              "test-key",
+		ChannelID:           "channel123",
+		PollIntervalSeconds: 30,
+		AlertListIDs:        []string{"list-1"},
+	}
+
+	mockAPI := &plugintest.API{}
+	mockAPI.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	mockAPI.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	mockKVStore(mockAPI)
+	client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
+	require.NoError(t, err)
+
+	var jobs []*MockJob
+	b.poller.SetScheduler(&MockJobScheduler{
+		ScheduleFn: func(jobID string, nextWaitInterval cluster.NextWaitInterval, callback func()) (Job, error) {
+			job := &MockJob{}
+			jobs = append(jobs, job)
+			return job, nil
+		},
+	})
+	require.NoError(t, b.Start(context.Background()))
+	require.NoError(t, b.stateStore.SaveCursor("cursor-1"))
+
+	updated := config
+	updated.Name = "Storm Watch"
+	updated.ChannelID = "channel456"
+	updated.Routes = []backend.ChannelRoute{{ChannelID: "after-hours", Ranges: []backend.TimeRange{{Start: "09:00", End: "17:00"}}, Outside: true}}
+	updated.AlertListIDs = []string{"list-2"}
+	updated.QuietHours = &backend.QuietHours{Ranges: []backend.TimeRange{{Start: "22:00", End: "06:00"}}}
+	updated.MaxAlertsPerBatch = 10
+	updated.PollIntervalSeconds = 60
+	require.NoError(t, b.UpdateConfig(context.Background(), updated))
+
+	// The poll job is rescheduled with the new settings while the backend keeps running
+	require.Len(t, jobs, 2)
+	assert.True(t, jobs[0].closed)
+	assert.False(t, jobs[1].closed)
+	assert.True(t, b.running)
+	assert.Equal(t, "Storm Watch", b.GetName())
+	assert.Equal(t, "channel456", b.processor.channelID)
+	assert.Equal(t, updated.Routes, b.processor.routes)
+	assert.Equal(t, "Storm Watch", b.processor.backendName)
+	assert.Equal(t, []string{"list-2"}, b.apiClient.alertLists)
+	require.NotNil(t, b.processor.quietHours)
+	assert.Equal(t, updated.QuietHours, b.processor.quietHours.schedule)
+	assert.Equal(t, 10, b.processor.maxBatch)
+	assert.Equal(t, time.Minute, b.poller.interval)
+
+	// The cursor is kept, so polling continues where it left off
+	cursor, err := b.stateStore.GetCursor()
+	require.NoError(t, err)
+	assert.Equal(t, "cursor-1", cursor)
+
+	t.Run("removed filters", func(t *testing.T) {
+		removed := updated
+		removed.QuietHours = nil
+		removed.MaxAlertsPerBatch = 0
+		require.NoError(t, b.UpdateConfig(context.Background(), removed))
+
+		// The gate is kept without a schedule, so alerts it buffered are still posted
+		require.NotNil(t, b.processor.quietHours)
+		assert.False(t, b.processor.quietHours.IsActive())
+		assert.Equal(t, backend.DefaultMaxAlertsPerBatch, b.processor.maxBatch)
+	})
+
+	t.Run("another backend's configuration", func(t *testing.T) {
+		other := config
+		other.ID = "other-backend"
+		assert.Error(t, b.UpdateConfig(context.Background(), other))
+		assert.Equal(t, "Storm Watch", b.GetName())
+	})
+}
+
 func TestDataminrBackend_GetStatus(t *testing.T) {
 	config := backend.Config{
 		ID:                  "test-backend",
@@ -444,7 +556,7 @@ func TestDataminrBackend_GetStatus(t *testing.T) {
 
 		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
 
-		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
 		require.NoError(t, err)
 
 		status := b.GetStatus()
@@ -452,9 +564,13 @@ func TestDataminrBackend_GetStatus(t *testing.T) {
 		assert.True(t, status.Enabled) // backend is enabled in config
 		assert.True(t, status.LastPollTime.IsZero())
 		assert.True(t, status.LastSuccessTime.IsZero())
+		assert.True(t, status.NextPollTime.IsZero(), "not scheduled before Start")
 		assert.Equal(t, 0, status.ConsecutiveFailures)
 		assert.False(t, status.IsAuthenticated)
 		assert.Empty(t, status.LastError)
+		assert.Equal(t, backend.PhaseStarting, status.Phase)
+		assert.False(t, status.Running)
+		assert.Equal(t, backend.HealthUnauthenticated, status.Health)
 
 		mockAPI.AssertExpectations(t)
 	})
@@ -465,32 +581,72 @@ func TestDataminrBackend_GetStatus(t *testing.T) {
 		lastPoll := now.Add(-1 * time.Minute)
 		lastSuccess := now.Add(-2 * time.Minute)
 		tokenExpiry := now.Add(30 * time.Minute)
+		pausedUntil := now.Add(time.Hour)
 
 		// Mock KVGet responses
-		mockAPI.On("KVGet", "backend_test-backend_last_poll").Return(mustMarshalTime(lastPoll), nil)
-		mockAPI.On("KVGet", "backend_test-backend_last_success").Return(mustMarshalTime(lastSuccess), nil)
-		mockAPI.On("KVGet", "backend_test-backend_failures").Return([]byte(`3`), nil)
-		mockAPI.On("KVGet", "backend_test-backend_last_error").Return([]byte("rate limit exceeded"), nil)
+		mockAPI.On("KVGet", "backend_test-backend_pause").Return([]byte(`{"until":"`+pausedUntil.Format(time.RFC3339Nano)+`"}`), nil)
+		mockAPI.On("KVGet", "backend_test-backend_state").Return(mustMarshalPollState(PollState{
+			LastPoll:    lastPoll,
+			LastSuccess: lastSuccess,
+			Failures:    3,
+			LastError:   "rate limit exceeded",
+			Phase:       backend.PhaseCatchingUp,
+		}), nil)
+		errorsData, _ := json.Marshal([]backend.ErrorRecord{{Time: now, Message: "rate limit exceeded"}})
+		mockAPI.On("KVGet", "backend_test-backend_errors").Return(errorsData, nil)
+		historyData, _ := json.Marshal([]backend.PollSample{
+			{Time: now.Add(-25 * time.Hour), Success: true, Alerts: 9, LatencyMs: 900},
+			{Time: now.Add(-3 * time.Minute), Success: true, Alerts: 4, NewAlerts: 2, LatencyMs: 100},
+			{Time: now.Add(-2 * time.Minute), Success: false, LatencyMs: 300, Error: "rate limit exceeded"},
+		})
+		mockAPI.On("KVGet", "backend_test-backend_poll_history").Return(historyData, nil)
+		failedData, _ := json.Marshal([]backend.FailedDelivery{{AlertID: "alert-1", ChannelID: "channel123", Attempts: 5, LastError: "channel archived", FailedAt: now}})
+		mockAPI.On("KVGet", "backend_test-backend_failed").Return(failedData, nil)
 		mockAPI.On("KVGet", "backend_test-backend_auth").Return(mustMarshalAuthToken("test-token", tokenExpiry), nil)
+		credentialsData, _ := json.Marshal(backend.CredentialCheck{CheckedAt: now, Valid: true, TokenExpiry: tokenExpiry})
+		mockAPI.On("KVGet", "backend_test-backend_credentials").Return(credentialsData, nil)
 
 		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
 
-		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
 		require.NoError(t, err)
 
 		// Set backend as running
 		b.mu.Lock()
 		b.running = true
 		b.mu.Unlock()
+		nextPoll := now.Add(20 * time.Second)
+		b.poller.mu.Lock()
+		b.poller.nextRunAt = nextPoll
+		b.poller.mu.Unlock()
 
 		status := b.GetStatus()
 
 		assert.True(t, status.Enabled)
 		assert.Equal(t, lastPoll.Unix(), status.LastPollTime.Unix())
 		assert.Equal(t, lastSuccess.Unix(), status.LastSuccessTime.Unix())
+		assert.Equal(t, nextPoll, status.NextPollTime)
 		assert.Equal(t, 3, status.ConsecutiveFailures)
 		assert.True(t, status.IsAuthenticated)
 		assert.Equal(t, "rate limit exceeded", status.LastError)
+		require.Len(t, status.RecentErrors, 1)
+		assert.Equal(t, "rate limit exceeded", status.RecentErrors[0].Message)
+		require.Len(t, status.FailedDeliveries, 1)
+		assert.Equal(t, "channel archived", status.FailedDeliveries[0].LastError)
+		assert.True(t, status.Paused)
+		assert.True(t, pausedUntil.Equal(status.PausedUntil))
+		assert.Equal(t, 2, status.History.Polls)
+		assert.Equal(t, 1, status.History.Failures)
+		assert.Equal(t, 0.5, status.History.SuccessRate)
+		assert.Equal(t, 4, status.History.Alerts)
+		assert.Equal(t, 2, status.History.NewAlerts)
+		assert.Equal(t, int64(200), status.History.AverageLatencyMs)
+		assert.Equal(t, int64(300), status.History.MaxLatencyMs)
+		assert.Equal(t, backend.PhaseCatchingUp, status.Phase)
+		assert.True(t, status.Running)
+		assert.Equal(t, backend.HealthPaused, status.Health)
+		require.NotNil(t, status.CredentialCheck)
+		assert.True(t, status.CredentialCheck.Valid)
 
 		mockAPI.AssertExpectations(t)
 	})
@@ -500,23 +656,262 @@ func TestDataminrBackend_GetStatus(t *testing.T) {
 		now := time.Now()
 		tokenExpiry := now.Add(-10 * time.Minute) // expired
 
-		mockAPI.On("KVGet", "backend_test-backend_last_poll").Return(nil, nil)
-		mockAPI.On("KVGet", "backend_test-backend_last_success").Return(nil, nil)
-		mockAPI.On("KVGet", "backend_test-backend_failures").Return(nil, nil)
-		mockAPI.On("KVGet", "backend_test-backend_last_error").Return(nil, nil)
+		mockAPI.On("KVGet", "backend_test-backend_state").Return(mustMarshalPollState(PollState{Phase: backend.PhasePolling}), nil)
+		mockAPI.On("KVGet", "backend_test-backend_errors").Return(nil, nil)
+		mockAPI.On("KVGet", "backend_test-backend_poll_history").Return(nil, nil)
+		mockAPI.On("KVGet", "backend_test-backend_failed").Return(nil, nil)
+		mockAPI.On("KVGet", "backend_test-backend_pause").Return(nil, nil)
 		mockAPI.On("KVGet", "backend_test-backend_auth").Return(mustMarshalAuthToken("expired-token", tokenExpiry), nil)
+		mockAPI.On("KVGet", "backend_test-backend_credentials").Return(nil, nil)
 
 		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
 
-		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
 		require.NoError(t, err)
 
 		status := b.GetStatus()
 
 		assert.False(t, status.IsAuthenticated)
+		assert.False(t, status.Paused)
+		assert.Equal(t, backend.PhasePolling, status.Phase)
+		assert.Nil(t, status.CredentialCheck)
 
 		mockAPI.AssertExpectations(t)
 	})
+
+	t.Run("disabled backend", func(t *testing.T) {
+		mockAPI := &plugintest.API{}
+		mockAPI.On("KVGet", "backend_test-backend_state").Return(mustMarshalPollState(PollState{Phase: backend.PhasePolling}), nil)
+		mockAPI.On("KVGet", mock.Anything).Return(nil, nil)
+
+		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+		disabledConfig := config
+		disabledConfig.Enabled = false
+		b, err := New(disabledConfig, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, backend.PhaseDisabled, b.GetStatus().Phase)
+	})
+
+	t.Run("simulator needs no authentication", func(t *testing.T) {
+		mockAPI := &plugintest.API{}
+		mockAPI.On("KVGet", "backend_test-backend_auth").Return(nil, nil).Maybe()
+		mockAPI.On("KVGet", mock.Anything).Return(nil, nil)
+
+		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+		simulatorConfig := backend.Config{
+			ID:                  "test-backend",
+			Name:                "Demo",
+			Type:                backend.SimulatorType,
+			Enabled:             true,
+			ChannelID:           "channel123",
+			PollIntervalSeconds: 30,
+		}
+		b, err := NewSimulator(simulatorConfig, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+		require.NoError(t, err)
+
+		assert.True(t, b.GetStatus().IsAuthenticated)
+		mockAPI.AssertNotCalled(t, "KVGet", "backend_test-backend_auth")
+	})
+}
+
+func TestDataminrBackend_PauseResume(t *testing.T) {
+	config := backend.Config{
+		ID:                  "backend-123",
+		Name:                "Production Alerts",
+		Type:                "dataminr",
+		Enabled:             true,
+		URL:                 "https://api.dataminr.com",
+		APIId:               "test-id",
+		APIKey:              "test-key",
+		ChannelID:           "channel123",
+		PollIntervalSeconds: 30,
+	}
+
+	until := time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)
+	pauseData, err := json.Marshal(PauseState{Until: until})
+	require.NoError(t, err)
+
+	mockAPI := &plugintest.API{}
+	defer mockAPI.AssertExpectations(t)
+	mockAPI.On("KVSet", "backend_backend-123_pause", pauseData).Return(nil).Once()
+	mockAPI.On("KVDelete", "backend_backend-123_pause").Return(nil).Once()
+	mockAPI.On("LogInfo", "Dataminr backend paused", "id", "backend-123", "name", "Production Alerts", "until", until).Once()
+	mockAPI.On("LogInfo", "Dataminr backend resumed", "id", "backend-123", "name", "Production Alerts").Once()
+	client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
+	require.NoError(t, err)
+
+	require.NoError(t, b.Pause(until))
+	require.NoError(t, b.Resume())
+}
+
+func TestDataminrBackend_State(t *testing.T) {
+	config := backend.Config{
+		ID:                  "backend-123",
+		Name:                "Production Alerts",
+		Type:                "dataminr",
+		Enabled:             true,
+		URL:                 "https://api.dataminr.com",
+		APIId:               "test-id",
+		APIKey:              "test-key",
+		ChannelID:           "channel123",
+		PollIntervalSeconds: 30,
+	}
+
+	newBackend := func(t *testing.T) (*Backend, map[string][]byte) {
+		mockAPI := &plugintest.API{}
+		kvStore := mockKVStore(mockAPI)
+		mockAPI.On("LogInfo", "Dataminr backend state reset", "id", "backend-123", "name", "Production Alerts", "scope", mock.Anything).Maybe()
+		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
+		require.NoError(t, err)
+		return b, kvStore
+	}
+
+	t.Run("reports the stored state without the token", func(t *testing.T) {
+		b, _ := newBackend(t)
+		expiry := time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)
+		require.NoError(t, b.stateStore.SaveCursor("cursor-1"))
+		require.NoError(t, b.stateStore.SaveAuthToken("secret-token", expiry))
+		_, err := b.stateStore.IncrementFailures()
+		require.NoError(t, err)
+		require.NoError(t, b.stateStore.SaveLastError("timeout"))
+		require.NoError(t, b.stateStore.SavePendingAlerts([]PendingAlert{{Alert: backend.Alert{AlertID: "alert-1"}, ChannelID: "channel123"}}))
+
+		state, err := b.GetState()
+		require.NoError(t, err)
+		assert.Equal(t, backend.StateSnapshot{
+			Cursor:              "cursor-1",
+			HasAuthToken:        true,
+			AuthTokenExpiry:     expiry,
+			ConsecutiveFailures: 1,
+			LastError:           "timeout",
+			PendingAlerts:       1,
+		}, state)
+	})
+
+	t.Run("resets the failure tracking only", func(t *testing.T) {
+		b, _ := newBackend(t)
+		require.NoError(t, b.stateStore.SaveCursor("cursor-1"))
+		_, err := b.stateStore.IncrementFailures()
+		require.NoError(t, err)
+		require.NoError(t, b.stateStore.SaveLastError("timeout"))
+		require.NoError(t, b.stateStore.SaveCooldown(CooldownState{Until: time.Now().Add(time.Hour), Cycles: 2}))
+
+		require.NoError(t, b.ResetState(backend.StateResetFailures))
+
+		state, err := b.GetState()
+		require.NoError(t, err)
+		assert.Equal(t, "cursor-1", state.Cursor)
+		assert.Zero(t, state.ConsecutiveFailures)
+		assert.Empty(t, state.LastError)
+		assert.True(t, state.CooldownUntil.IsZero())
+	})
+
+	t.Run("defers the cursor reset to the next poll", func(t *testing.T) {
+		b, kvStore := newBackend(t)
+		require.NoError(t, b.stateStore.SaveCursor("cursor-1"))
+		require.NoError(t, b.stateStore.SaveAuthToken("secret-token", time.Now().Add(time.Hour)))
+
+		require.NoError(t, b.ResetState(backend.StateResetAll))
+
+		state, err := b.GetState()
+		require.NoError(t, err)
+		assert.Equal(t, "cursor-1", state.Cursor)
+		assert.True(t, state.CursorResetPending)
+		assert.False(t, state.HasAuthToken)
+		assert.NotContains(t, kvStore, "backend_backend-123_auth")
+	})
+
+	t.Run("rejects unknown scopes", func(t *testing.T) {
+		b, _ := newBackend(t)
+		assert.Error(t, b.ResetState("pause"))
+	})
+}
+
+// memoryPollHistory is a poll history store kept in memory
+type memoryPollHistory struct {
+	samples []backend.PollSample
+}
+
+func (h *memoryPollHistory) RecordPollSample(sample backend.PollSample, _ time.Time) error {
+	h.samples = append(h.samples, sample)
+	return nil
+}
+
+func (h *memoryPollHistory) GetPollHistory() ([]backend.PollSample, error) {
+	return h.samples, nil
+}
+
+func TestDataminrBackend_SetPollHistoryStore(t *testing.T) {
+	mockAPI := &plugintest.API{}
+	kvStore := mockKVStore(mockAPI)
+	client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+	config := backend.Config{
+		ID:                  "backend-123",
+		Name:                "Production Alerts",
+		Type:                "dataminr",
+		URL:                 "https://api.dataminr.com",
+		APIId:               "test-id",
+		APIKey:              "test-key",
+		ChannelID:           "channel123",
+		PollIntervalSeconds: 30,
+	}
+
+	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
+	require.NoError(t, err)
+
+	history := &memoryPollHistory{}
+	b.SetPollHistoryStore(history)
+	sample := backend.PollSample{Time: time.Now(), Success: true, Alerts: 2}
+	require.NoError(t, b.poller.history.RecordPollSample(sample, sample.Time))
+
+	samples, err := b.GetPollHistory()
+	require.NoError(t, err)
+	assert.Equal(t, []backend.PollSample{sample}, samples)
+	assert.NotContains(t, kvStore, "backend_backend-123_poll_history")
+}
+
+func TestDataminrBackend_LogLevel(t *testing.T) {
+	config := backend.Config{
+		ID:                  "backend-123",
+		Name:                "Production Alerts",
+		Type:                "dataminr",
+		Enabled:             true,
+		URL:                 "https://api.dataminr.com",
+		APIId:               "test-id",
+		APIKey:              "test-key",
+		ChannelID:           "channel123",
+		PollIntervalSeconds: 30,
+		LogLevel:            backend.LogLevelWarn,
+	}
+
+	// Info lines are below the backend's log level, so they are neither written nor captured
+	mockAPI := &plugintest.API{}
+	defer mockAPI.AssertExpectations(t)
+	mockAPI.On("KVSet", "backend_backend-123_pause", mock.Anything).Return(nil).Once()
+	client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+	b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
+	require.NoError(t, err)
+	require.NoError(t, b.Pause(time.Time{}))
+	assert.Empty(t, b.GetRecentLogs())
+
+	mockAPI.On("LogWarn", "Failed to get poll state", "id", "backend-123", "error", mock.Anything).Once()
+	mockAPI.On("KVGet", "backend_backend-123_state").Return([]byte("not a document"), nil)
+	mockAPI.On("KVGet", mock.Anything).Return(nil, nil)
+	b.GetStatus()
+
+	logs := b.GetRecentLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, backend.LogLevelWarn, logs[0].Level)
+	assert.Equal(t, "Failed to get poll state", logs[0].Message)
+	assert.Contains(t, logs[0].Fields, "id=backend-123")
 }
 
 // Helper functions for marshaling test data
@@ -539,3 +934,204 @@ func mustMarshalAuthToken(token string, expiry time.Time) []byte {
 	}
 	return data
 }
+
+func mustMarshalPollState(state PollState) []byte {
+	data, err := json.Marshal(state)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func TestDataminrBackend_FetchTaxonomy(t *testing.T) {
+	t.Run("builds the taxonomy from the alert lists", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path == backend.DefaultAuthPath {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"authorizationToken": "test-token",
+					"expirationTime":     time.Now().Add(time.Hour).UnixMilli(),
+				})
+				return
+			}
+			_, _ = w.Write([]byte(`{"watchlists": {"TOPIC": [{"id": 2, "name": "Wildfires"}, {"id": 1, "name": "Cybersecurity"}], "COMPANY": [{"id": 3, "name": "Acme Corp"}]}}`))
+		}))
+		defer server.Close()
+
+		mockAPI := &plugintest.API{}
+		mockAPI.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+		mockAPI.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
+		mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		mockAPI.On("LogInfo", mock.Anything, mock.Anything, mock.Anything).Maybe()
+		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+		config := backend.Config{
+			ID:                  "test-backend",
+			Name:                "Test Backend",
+			Type:                "dataminr",
+			Enabled:             true,
+			URL:                 server.URL,
+			APIId:               "test-id",
+			APIKey:              "test-key",
+			ChannelID:           "channel123",
+			PollIntervalSeconds: 30,
+		}
+		b, err := New(config, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
+		require.NoError(t, err)
+
+		taxonomy, err := b.FetchTaxonomy(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []backend.TaxonomyList{
+			{ID: "3", Name: "Acme Corp", Type: "COMPANY"},
+			{ID: "1", Name: "Cybersecurity", Type: "TOPIC"},
+			{ID: "2", Name: "Wildfires", Type: "TOPIC"},
+		}, taxonomy.AlertLists)
+		assert.Equal(t, []string{"Cybersecurity", "Wildfires"}, taxonomy.Topics)
+		assert.WithinDuration(t, time.Now(), taxonomy.FetchedAt, time.Minute)
+	})
+
+	t.Run("simulator has no taxonomy", func(t *testing.T) {
+		mockAPI := &plugintest.API{}
+		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+		simulatorConfig := backend.Config{
+			ID:                  "test-backend",
+			Name:                "Demo",
+			Type:                backend.SimulatorType,
+			Enabled:             true,
+			ChannelID:           "channel123",
+			PollIntervalSeconds: 30,
+		}
+		b, err := NewSimulator(simulatorConfig, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+		require.NoError(t, err)
+
+		_, err = b.FetchTaxonomy(context.Background())
+		require.ErrorIs(t, err, backend.ErrTaxonomyUnsupported)
+	})
+}
+
+func TestDataminrBackend_CheckCredentials(t *testing.T) {
+	config := backend.Config{
+		ID:                  "test-backend",
+		Name:                "Test Backend",
+		Type:                "dataminr",
+		Enabled:             true,
+		APIId:               "test-id",
+		APIKey:              "test-key",
+		ChannelID:           "channel123",
+		PollIntervalSeconds: 30,
+	}
+
+	newMockAPI := func() *plugintest.API {
+		mockAPI := &plugintest.API{}
+		mockAPI.On("KVSet", "backend_test-backend_auth", mock.Anything).Return(nil).Maybe()
+		mockAPI.On("LogInfo", mock.Anything, mock.Anything, mock.Anything).Maybe()
+		mockAPI.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		mockAPI.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		return mockAPI
+	}
+
+	t.Run("valid credentials", func(t *testing.T) {
+		tokenExpiry := time.Now().Add(time.Hour)
+		authCalls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, backend.DefaultAuthPath, r.URL.Path)
+			authCalls++
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"authorizationToken": "test-token",
+				"expirationTime":     tokenExpiry.UnixMilli(),
+			})
+		}))
+		defer server.Close()
+
+		mockAPI := newMockAPI()
+		var saved backend.CredentialCheck
+		mockAPI.On("KVSet", "backend_test-backend_credentials", mock.Anything).Run(func(args mock.Arguments) {
+			require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &saved))
+		}).Return(nil).Once()
+		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+		cfg := config
+		cfg.URL = server.URL
+		b, err := New(cfg, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
+		require.NoError(t, err)
+
+		check, err := b.CheckCredentials(context.Background())
+		require.NoError(t, err)
+		assert.True(t, check.Valid)
+		assert.False(t, check.Rejected)
+		assert.Empty(t, check.Error)
+		assert.WithinDuration(t, tokenExpiry, check.TokenExpiry, time.Second)
+		assert.WithinDuration(t, time.Now(), check.CheckedAt, time.Minute)
+		assert.Equal(t, 1, authCalls, "The check authenticates even without a cached token to reuse")
+		assert.True(t, saved.Valid)
+		mockAPI.AssertExpectations(t)
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "invalid_client", "error_description": "Account suspended"}`))
+		}))
+		defer server.Close()
+
+		mockAPI := newMockAPI()
+		mockAPI.On("KVSet", "backend_test-backend_credentials", mock.Anything).Return(nil).Once()
+		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+		cfg := config
+		cfg.URL = server.URL
+		b, err := New(cfg, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
+		require.NoError(t, err)
+
+		check, err := b.CheckCredentials(context.Background())
+		require.NoError(t, err)
+		assert.False(t, check.Valid)
+		assert.True(t, check.Rejected)
+		assert.Contains(t, check.Error, "Account suspended")
+		mockAPI.AssertExpectations(t)
+	})
+
+	t.Run("unreachable source", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		mockAPI := newMockAPI()
+		mockAPI.On("KVSet", "backend_test-backend_credentials", mock.Anything).Return(nil).Once()
+		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+		cfg := config
+		cfg.URL = server.URL
+		b, err := New(cfg, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil, "")
+		require.NoError(t, err)
+
+		check, err := b.CheckCredentials(context.Background())
+		require.NoError(t, err)
+		assert.False(t, check.Valid)
+		assert.False(t, check.Rejected)
+		assert.Contains(t, check.Error, "HTTP 503")
+	})
+
+	t.Run("simulator needs no credentials", func(t *testing.T) {
+		mockAPI := &plugintest.API{}
+		client := pluginapi.NewClient(mockAPI, &plugintest.Driver{})
+
+		simulatorConfig := backend.Config{
+			ID:                  "test-backend",
+			Name:                "Demo",
+			Type:                backend.SimulatorType,
+			Enabled:             true,
+			ChannelID:           "channel123",
+			PollIntervalSeconds: 30,
+		}
+		b, err := NewSimulator(simulatorConfig, client, mockAPI, &MockPoster{}, NewMockDeduplicator(), nil)
+		require.NoError(t, err)
+
+		_, err = b.CheckCredentials(context.Background())
+		require.ErrorIs(t, err, backend.ErrCredentialCheckUnsupported)
+	})
+}
@@ -0,0 +1,123 @@
+package dataminr
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AuthError is returned when the API rejects the credentials or the authentication token.
+// The poller clears the cached token so the next poll cycle authenticates again.
+type AuthError struct {
+	// StatusCode is the HTTP status returned by the API
+	StatusCode int
+
+	// TokenRejected reports whether the alerts endpoint rejected the token,
+	// as opposed to the auth endpoint rejecting the credentials
+	TokenRejected bool
+
+	Message string
+}
+
+func (e *AuthError) Error() string {
+	return e.Message
+}
+
+// RateLimitError is returned when the API rejects a request because of its rate limit.
+// The poller backs off instead of counting it towards MaxConsecutiveFailures.
+type RateLimitError struct {
+	// RetryAfter is how long the API asked to wait before the next request (0 if not given)
+	RetryAfter time.Duration
+
+	Message string
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Message
+}
+
+// ServerError is returned when the API fails with a server-side error
+type ServerError struct {
+	// StatusCode is the HTTP status returned by the API
+	StatusCode int
+
+	Message string
+}
+
+func (e *ServerError) Error() string {
+	return e.Message
+}
+
+// NetworkError is returned when a request could not be sent or its response not received
+type NetworkError struct {
+	// Op describes the failed request (e.g., "alerts request failed")
+	Op string
+
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned when the API rejects a request as invalid, which points at a
+// configuration problem that retrying cannot fix. The poller disables the backend immediately.
+type ValidationError struct {
+	// StatusCode is the HTTP status returned by the API
+	StatusCode int
+
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// CursorError is returned when the API rejects the cursor of a request, e.g. because it
+// expired. The poller discards the cursor and falls back to fetching alerts by time window.
+type CursorError struct {
+	// StatusCode is the HTTP status returned by the API
+	StatusCode int
+
+	Message string
+}
+
+func (e *CursorError) Error() string {
+	return e.Message
+}
+
+// PayloadTooLargeError is returned when an API response exceeds the configured size limit.
+// Reading stops at the limit; the poller counts it as a regular failure without resetting auth.
+type PayloadTooLargeError struct {
+	// Limit is the largest response accepted, in bytes
+	Limit int64
+
+	Message string
+}
+
+func (e *PayloadTooLargeError) Error() string {
+	return e.Message
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+// Returns 0 if the header is missing or invalid.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
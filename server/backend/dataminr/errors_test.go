@@ -0,0 +1,47 @@
+package dataminr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected time.Duration
+	}{
+		{"missing", "", 0},
+		{"seconds", "30", 30 * time.Second},
+		{"negative seconds", "-5", 0},
+		{"HTTP date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"HTTP date in the past", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"invalid", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseRetryAfter(tt.header, now))
+		})
+	}
+}
+
+func TestNetworkError_Unwrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("failed to fetch alerts: %w", &NetworkError{Op: "alerts request failed", Err: cause})
+
+	assert.Equal(t, "failed to fetch alerts: alerts request failed: connection refused", err.Error())
+	assert.ErrorIs(t, err, cause)
+
+	var networkErr *NetworkError
+	require.ErrorAs(t, err, &networkErr)
+	assert.Equal(t, "alerts request failed", networkErr.Op)
+}
@@ -0,0 +1,89 @@
+package dataminr
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// requestLimiter spaces out requests and caps how many are sent in any one-minute window.
+// Wait blocks until the next request is allowed, so callers are delayed rather than rejected.
+// The lock is only held to check and record requests, never while waiting.
+type requestLimiter struct {
+	mu           sync.Mutex
+	maxPerMinute int
+	minInterval  time.Duration
+
+	// sent holds the times of the requests sent within the last minute, oldest first
+	sent []time.Time
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// newRequestLimiter creates a limiter. A zero maxPerMinute or minInterval disables that limit.
+func newRequestLimiter(maxPerMinute int, minInterval time.Duration) *requestLimiter {
+	return &requestLimiter{
+		maxPerMinute: maxPerMinute,
+		minInterval:  minInterval,
+		now:          time.Now,
+		sleep:        sleepContext,
+	}
+}
+
+// Wait blocks until a request may be sent and records it. Returns how long it waited, or the
+// context error if ctx is cancelled first, in which case no request is recorded.
+func (l *requestLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	var waited time.Duration
+	for {
+		// Another request may have been recorded while sleeping, so the delay is checked again
+		l.mu.Lock()
+		delay := l.delay(l.now())
+		if delay <= 0 {
+			l.sent = append(l.sent, l.now())
+			l.mu.Unlock()
+			return waited, nil
+		}
+		l.mu.Unlock()
+
+		if err := l.sleep(ctx, delay); err != nil {
+			return waited, err
+		}
+		waited += delay
+	}
+}
+
+// sleepContext waits for d, returning early with the context error if ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// delay returns how long to wait at now before the next request is allowed
+func (l *requestLimiter) delay(now time.Time) time.Duration {
+	// Forget requests that left the one-minute window
+	cutoff := now.Add(-time.Minute)
+	kept := 0
+	for kept < len(l.sent) && !l.sent[kept].After(cutoff) {
+		kept++
+	}
+	l.sent = l.sent[kept:]
+
+	var delay time.Duration
+	if l.minInterval > 0 && len(l.sent) > 0 {
+		delay = l.sent[len(l.sent)-1].Add(l.minInterval).Sub(now)
+	}
+	if l.maxPerMinute > 0 && len(l.sent) >= l.maxPerMinute {
+		if windowDelay := l.sent[len(l.sent)-l.maxPerMinute].Add(time.Minute).Sub(now); windowDelay > delay {
+			delay = windowDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,99 @@
+package dataminr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLimiter returns a limiter driven by a fake clock that advances when it sleeps
+func newTestLimiter(maxPerMinute int, minInterval time.Duration) (*requestLimiter, *time.Time) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newRequestLimiter(maxPerMinute, minInterval)
+	limiter.now = func() time.Time { return clock }
+	limiter.sleep = func(_ context.Context, d time.Duration) error {
+		clock = clock.Add(d)
+		return nil
+	}
+	return limiter, &clock
+}
+
+// wait waits for the limiter without a deadline, failing the test on error
+func wait(t *testing.T, limiter *requestLimiter) time.Duration {
+	t.Helper()
+	waited, err := limiter.Wait(context.Background())
+	require.NoError(t, err)
+	return waited
+}
+
+func TestRequestLimiter_Unlimited(t *testing.T) {
+	limiter, _ := newTestLimiter(0, 0)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, time.Duration(0), wait(t, limiter))
+	}
+}
+
+func TestRequestLimiter_MinInterval(t *testing.T) {
+	limiter, clock := newTestLimiter(0, 2*time.Second)
+
+	assert.Equal(t, time.Duration(0), wait(t, limiter))
+	assert.Equal(t, 2*time.Second, wait(t, limiter))
+
+	// Requests spaced further apart are not delayed
+	*clock = clock.Add(5 * time.Second)
+	assert.Equal(t, time.Duration(0), wait(t, limiter))
+
+	*clock = clock.Add(500 * time.Millisecond)
+	assert.Equal(t, 1500*time.Millisecond, wait(t, limiter))
+}
+
+func TestRequestLimiter_MaxPerMinute(t *testing.T) {
+	limiter, clock := newTestLimiter(3, 0)
+
+	start := *clock
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, time.Duration(0), wait(t, limiter))
+		*clock = clock.Add(10 * time.Second)
+	}
+
+	// The fourth request waits until the first one leaves the window
+	assert.Equal(t, 30*time.Second, wait(t, limiter))
+	assert.Equal(t, start.Add(time.Minute), *clock)
+
+	// The next waits for the second request to leave the window
+	assert.Equal(t, 10*time.Second, wait(t, limiter))
+}
+
+func TestRequestLimiter_Combined(t *testing.T) {
+	limiter, _ := newTestLimiter(2, 20*time.Second)
+
+	assert.Equal(t, time.Duration(0), wait(t, limiter))
+	assert.Equal(t, 20*time.Second, wait(t, limiter))
+
+	// The window limit is stricter than the spacing for the third request
+	assert.Equal(t, 40*time.Second, wait(t, limiter))
+}
+
+func TestRequestLimiter_ContextCancelled(t *testing.T) {
+	limiter := newRequestLimiter(0, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	waited, err := limiter.Wait(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), waited)
+
+	// The second request would wait an hour, but returns as soon as the context is cancelled
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = limiter.Wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// The cancelled request is not recorded
+	limiter.mu.Lock()
+	assert.Len(t, limiter.sent, 1)
+	limiter.mu.Unlock()
+}
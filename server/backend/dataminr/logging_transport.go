@@ -0,0 +1,101 @@
+package dataminr
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// maxLoggedBodyBytes is how much of each request and response body is included in debug logs
+const maxLoggedBodyBytes = 1024
+
+// secretPattern matches secret values in JSON ("token": "x") and form (api_password=x) bodies
+var secretPattern = regexp.MustCompile(`(?i)("?[a-z_]*(?:password|token|secret|api_?key)"?\s*[:=]\s*"?)([^"&,\s}]+)`)
+
+// loggingTransport is an http.RoundTripper that logs sanitized request and response metadata
+// at debug level. Headers are never logged since they carry the authorization token.
+type loggingTransport struct {
+	next      http.RoundTripper
+	logger    backend.Logger
+	backendID string
+}
+
+// newLoggingTransport wraps a transport with debug logging (nil uses the default transport)
+func newLoggingTransport(next http.RoundTripper, logger backend.Logger, backendID string) *loggingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &loggingTransport{
+		next:      next,
+		logger:    logger,
+		backendID: backendID,
+	}
+}
+
+// RoundTrip executes the request and logs its method, path, status, duration and truncated bodies
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody := ""
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			requestBody = readLoggedBody(body)
+			_ = body.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		t.logger.Debug("HTTP request failed",
+			"backendId", t.backendID,
+			"method", req.Method,
+			"path", req.URL.Path,
+			"durationMs", duration.Milliseconds(),
+			"requestBody", requestBody,
+			"error", err.Error())
+		return resp, err
+	}
+
+	// Peek at the start of the response body and put it back for the caller
+	peeked, _ := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBodyBytes+1))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peeked), resp.Body), resp.Body}
+
+	t.logger.Debug("HTTP request completed",
+		"backendId", t.backendID,
+		"method", req.Method,
+		"path", req.URL.Path,
+		"status", resp.StatusCode,
+		"durationMs", duration.Milliseconds(),
+		"requestBody", requestBody,
+		"responseBody", sanitizeBody(peeked))
+
+	return resp, nil
+}
+
+// readLoggedBody reads the loggable prefix of a body and sanitizes it
+func readLoggedBody(body io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(body, maxLoggedBodyBytes+1))
+	return sanitizeBody(data)
+}
+
+// sanitizeBody redacts secrets and truncates a body for logging
+func sanitizeBody(data []byte) string {
+	truncated := len(data) > maxLoggedBodyBytes
+	if truncated {
+		data = data[:maxLoggedBodyBytes]
+	}
+
+	sanitized := secretPattern.ReplaceAllString(string(data), "${1}[REDACTED]")
+	if truncated {
+		sanitized += "...(truncated)"
+	}
+	return sanitized
+}
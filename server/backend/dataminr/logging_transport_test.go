@@ -0,0 +1,134 @@
+package dataminr
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// captureDebugLogs registers a LogDebug expectation with the given number of key/value
+// arguments and returns the logged key/value pairs of each call
+func captureDebugLogs(api *plugintest.API, kvArgs int) *[]map[string]interface{} {
+	logs := &[]map[string]interface{}{}
+	args := make([]interface{}, kvArgs+1)
+	for i := range args {
+		args[i] = mock.Anything
+	}
+
+	api.On("LogDebug", args...).Run(func(callArgs mock.Arguments) {
+		fields := map[string]interface{}{"msg": callArgs.Get(0)}
+		for i := 1; i+1 < len(callArgs); i += 2 {
+			fields[callArgs.Get(i).(string)] = callArgs.Get(i + 1)
+		}
+		*logs = append(*logs, fields)
+	})
+	return logs
+}
+
+func TestLoggingTransport_RoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"authorizationToken":"secret-token-123","expire":1700000000}`))
+	}))
+	defer server.Close()
+
+	api := &plugintest.API{}
+	logs := captureDebugLogs(api, 14)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	transport := newLoggingTransport(nil, &client.Log, "test-id")
+	form := url.Values{"api_user_id": {"user"}, "api_password": {"hunter2"}}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/auth/1/userAuthorization?x=1", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer header-token")
+
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// The response body is still fully readable by the caller
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "secret-token-123")
+
+	require.Len(t, *logs, 1)
+	entry := (*logs)[0]
+	assert.Equal(t, "HTTP request completed", entry["msg"])
+	assert.Equal(t, "test-id", entry["backendId"])
+	assert.Equal(t, http.MethodPost, entry["method"])
+	assert.Equal(t, "/auth/1/userAuthorization", entry["path"])
+	assert.Equal(t, http.StatusOK, entry["status"])
+	assert.Contains(t, entry["requestBody"], "api_user_id=user")
+	assert.Contains(t, entry["requestBody"], "api_password=[REDACTED]")
+	assert.NotContains(t, entry["requestBody"], "hunter2")
+	assert.Contains(t, entry["responseBody"], `"authorizationToken":"[REDACTED]"`)
+	assert.NotContains(t, entry["responseBody"], "secret-token-123")
+	for _, value := range entry {
+		if text, ok := value.(string); ok {
+			assert.NotContains(t, text, "header-token")
+		}
+	}
+}
+
+func TestLoggingTransport_RoundTripError(t *testing.T) {
+	api := &plugintest.API{}
+	logs := captureDebugLogs(api, 12)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	failing := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	transport := newLoggingTransport(failing, &client.Log, "test-id")
+
+	req, err := http.NewRequest(http.MethodGet, "http://dataminr.invalid/alerts/1/alerts", nil)
+	require.NoError(t, err)
+
+	_, err = transport.RoundTrip(req)
+	require.Error(t, err)
+
+	require.Len(t, *logs, 1)
+	assert.Equal(t, "HTTP request failed", (*logs)[0]["msg"])
+	assert.Equal(t, "connection refused", (*logs)[0]["error"])
+}
+
+func TestSanitizeBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"no secrets", `{"alerts":[]}`, `{"alerts":[]}`},
+		{"json token", `{"dmaToken": "abc", "expire": 1}`, `{"dmaToken": "[REDACTED]", "expire": 1}`},
+		{"form password", "grant_type=api_key&api_password=p%40ss&scope=api", "grant_type=api_key&api_password=[REDACTED]&scope=api"},
+		{"api key", `{"apiKey":"k1"}`, `{"apiKey":"[REDACTED]"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sanitizeBody([]byte(tt.body)))
+		})
+	}
+
+	t.Run("truncates long bodies", func(t *testing.T) {
+		sanitized := sanitizeBody([]byte(strings.Repeat("a", maxLoggedBodyBytes+10)))
+		assert.Equal(t, strings.Repeat("a", maxLoggedBodyBytes)+"...(truncated)", sanitized)
+	})
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,141 @@
+package dataminr
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// pendingQueue checkpoints the alerts of a batch in the KV store until each one is posted.
+// Alerts whose post failed, or that were left unposted when the poll cycle was interrupted,
+// stay queued and are retried on the next run of the poll job instead of being dropped.
+//
+// Alerts are saved as soon as they are added, but posted and failed alerts only update the
+// in-memory copy until flush, so a batch rewrites the stored queue once rather than once per
+// alert. Alerts posted since the last flush are posted again if the node stops before it.
+type pendingQueue struct {
+	stateStore *StateStore
+
+	// mu guards alerts, a copy of the stored queue loaded at the start of each poll cycle,
+	// and dirty. Alerts are posted by several workers, so updates must be serialized.
+	mu     sync.Mutex
+	alerts []PendingAlert
+
+	// dirty marks changes to alerts that are not saved yet
+	dirty bool
+}
+
+// newPendingQueue creates a pending queue backed by the state store
+func newPendingQueue(stateStore *StateStore) *pendingQueue {
+	return &pendingQueue{stateStore: stateStore}
+}
+
+// load reads the stored queue. Must be called at the start of a poll cycle, since the
+// polling job may have run on another cluster node since the last cycle on this one.
+func (q *pendingQueue) load() ([]PendingAlert, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	alerts, err := q.stateStore.GetPendingAlerts()
+	if err != nil {
+		return nil, err
+	}
+
+	q.alerts = alerts
+	q.dirty = false
+	return append([]PendingAlert(nil), alerts...), nil
+}
+
+// add queues alerts before they are posted
+func (q *pendingQueue) add(posts []pendingPost) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, post := range posts {
+		q.alerts = append(q.alerts, PendingAlert{Alert: post.alert, ChannelID: post.channelID, Subscribed: post.subscribed})
+	}
+	if err := q.stateStore.SavePendingAlerts(q.alerts); err != nil {
+		return err
+	}
+	q.dirty = false
+	return nil
+}
+
+// remove drops an alert posted to a channel from the queue until the next flush
+func (q *pendingQueue) remove(alertID, channelID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, pending := range q.alerts {
+		if pending.Alert.AlertID == alertID && pending.ChannelID == channelID {
+			q.alerts = append(q.alerts[:i], q.alerts[i+1:]...)
+			q.dirty = true
+			return
+		}
+	}
+}
+
+// flush saves the changes made to the queue since it was last saved
+func (q *pendingQueue) flush() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.dirty {
+		return nil
+	}
+	if err := q.stateStore.SavePendingAlerts(q.alerts); err != nil {
+		return err
+	}
+	q.dirty = false
+	return nil
+}
+
+// recordFailure counts a failed attempt to post an alert to a channel and schedules the next
+// attempt after retryDelay, saved on the next flush. Alerts that reached MaxAlertPostAttempts
+// are dropped from the queue and recorded as failed deliveries. Returns whether the alert was
+// dropped.
+func (q *pendingQueue) recordFailure(alertID, channelID string, postErr error, now time.Time) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i := range q.alerts {
+		pending := &q.alerts[i]
+		if pending.Alert.AlertID != alertID || pending.ChannelID != channelID {
+			continue
+		}
+
+		pending.Attempts++
+		pending.LastError = postErr.Error()
+		pending.RetryAt = now.Add(retryDelay(pending.Attempts))
+		q.dirty = true
+		if pending.Attempts < backend.MaxAlertPostAttempts {
+			return false, nil
+		}
+
+		delivery := backend.FailedDelivery{
+			AlertID:   alertID,
+			Headline:  pending.Alert.Headline,
+			ChannelID: channelID,
+			Attempts:  pending.Attempts,
+			LastError: pending.LastError,
+			FailedAt:  now,
+		}
+		q.alerts = append(q.alerts[:i], q.alerts[i+1:]...)
+		return true, q.stateStore.RecordFailedDelivery(delivery)
+	}
+	return false, nil
+}
+
+// retryDelay returns how long to wait before posting an alert again after a number of failed
+// attempts: AlertPostRetryDelay, doubling with each attempt up to MaxAlertPostRetryDelay
+func retryDelay(attempts int) time.Duration {
+	delay := backend.AlertPostRetryDelay
+	for i := 1; i < attempts && delay < backend.MaxAlertPostRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > backend.MaxAlertPostRetryDelay {
+		return backend.MaxAlertPostRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,236 @@
+package dataminr
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// newCheckpointedProcessor creates a processor with checkpointing backed by an in-memory KV store
+func newCheckpointedProcessor(t *testing.T, poster *MockPoster, dedup *MockDeduplicator) (*AlertProcessor, *StateStore) {
+	api := plugintest.NewAPI(t)
+	kvStore := make(map[string][]byte)
+	api.On("KVSet", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		kvStore[args.String(0)] = args.Get(1).([]byte)
+	}).Return(nil).Maybe()
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return kvStore[key], nil
+	}).Maybe()
+	api.On("KVDelete", mock.Anything).Run(func(args mock.Arguments) {
+		delete(kvStore, args.String(0))
+	}).Return(nil).Maybe()
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", "Retrying alerts left unposted by an earlier poll cycle", "backendName", "Test Backend", "count", mock.Anything).Maybe()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	stateStore := NewStateStore(api, "test-backend-id")
+	processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", poster, "test-channel-id", dedup, nil)
+	processor.SetPendingStore(stateStore)
+	return processor, stateStore
+}
+
+func pendingIDs(t *testing.T, stateStore *StateStore) []string {
+	pending, err := stateStore.GetPendingAlerts()
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, item := range pending {
+		ids = append(ids, item.Alert.AlertID)
+	}
+	return ids
+}
+
+func TestAlertProcessor_Checkpointing(t *testing.T) {
+	alerts := []Alert{
+		{AlertID: "alert-1", AlertType: AlertType{Name: "Flash"}, Headline: "Test Alert 1"},
+		{AlertID: "alert-2", AlertType: AlertType{Name: "Flash"}, Headline: "Test Alert 2"},
+		{AlertID: "alert-3", AlertType: AlertType{Name: "Flash"}, Headline: "Test Alert 3"},
+	}
+
+	t.Run("posted alerts leave the pending queue", func(t *testing.T) {
+		processor, stateStore := newCheckpointedProcessor(t, &MockPoster{}, NewMockDeduplicator())
+
+		count, err := processor.ProcessAlerts(context.Background(), alerts)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.Empty(t, pendingIDs(t, stateStore))
+	})
+
+	t.Run("failed alerts are retried on the next poll cycle", func(t *testing.T) {
+		failing := true
+		var posted []string
+		poster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				if failing && alert.AlertID == "alert-2" {
+					return errors.New("post failed")
+				}
+				posted = append(posted, alert.AlertID)
+				return nil
+			},
+		}
+		processor, stateStore := newCheckpointedProcessor(t, poster, NewMockDeduplicator())
+		now := time.Now()
+		processor.now = func() time.Time { return now }
+
+		count, err := processor.ProcessAlerts(context.Background(), alerts)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []string{"alert-2"}, pendingIDs(t, stateStore))
+
+		// Poll cycles before the retry delay has passed leave the alert queued
+		failing = false
+		_, err = processor.ProcessAlerts(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alert-1", "alert-3"}, posted)
+		assert.Equal(t, []string{"alert-2"}, pendingIDs(t, stateStore))
+
+		// The next poll cycle after the delay retries the alert before processing the new batch
+		now = now.Add(backend.AlertPostRetryDelay)
+		_, err = processor.ProcessAlerts(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alert-1", "alert-3", "alert-2"}, posted)
+		assert.Empty(t, pendingIDs(t, stateStore))
+	})
+
+	t.Run("retried alerts are posted in priority order with new alerts", func(t *testing.T) {
+		failing := true
+		var posted []string
+		poster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				if failing {
+					return errors.New("post failed")
+				}
+				posted = append(posted, alert.AlertID)
+				return nil
+			},
+		}
+		processor, stateStore := newCheckpointedProcessor(t, poster, NewMockDeduplicator())
+		now := time.Now()
+		processor.now = func() time.Time { return now }
+
+		_, err := processor.ProcessAlerts(context.Background(), []Alert{
+			{AlertID: "routine-1", AlertType: AlertType{Name: "Alert"}, Headline: "Routine Alert"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"routine-1"}, pendingIDs(t, stateStore))
+
+		failing = false
+		now = now.Add(backend.AlertPostRetryDelay)
+		count, err := processor.ProcessAlerts(context.Background(), []Alert{
+			{AlertID: "flash-1", AlertType: AlertType{Name: "Flash"}, Headline: "Flash Alert"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count, "Retried alerts were counted by the cycle that fetched them")
+		assert.Equal(t, []string{"flash-1", "routine-1"}, posted)
+		assert.Empty(t, pendingIDs(t, stateStore))
+	})
+
+	t.Run("alerts are dropped after repeated failures", func(t *testing.T) {
+		poster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				return errors.New("post failed")
+			},
+		}
+		processor, stateStore := newCheckpointedProcessor(t, poster, NewMockDeduplicator())
+		now := time.Now()
+		processor.now = func() time.Time { return now }
+
+		_, err := processor.ProcessAlerts(context.Background(), alerts[:1])
+		require.NoError(t, err)
+
+		for i := 1; i < backend.MaxAlertPostAttempts; i++ {
+			assert.Equal(t, []string{"alert-1"}, pendingIDs(t, stateStore))
+			now = now.Add(backend.MaxAlertPostRetryDelay)
+			_, err = processor.ProcessAlerts(context.Background(), nil)
+			require.NoError(t, err)
+		}
+
+		assert.Empty(t, pendingIDs(t, stateStore))
+
+		// The dropped alert is reported in the backend status
+		failed, err := stateStore.GetFailedDeliveries()
+		require.NoError(t, err)
+		require.Len(t, failed, 1)
+		assert.Equal(t, "alert-1", failed[0].AlertID)
+		assert.Equal(t, "Test Alert 1", failed[0].Headline)
+		assert.Equal(t, "test-channel-id", failed[0].ChannelID)
+		assert.Equal(t, backend.MaxAlertPostAttempts, failed[0].Attempts)
+		assert.Equal(t, "post failed", failed[0].LastError)
+	})
+
+	t.Run("interrupted batches resume where they stopped", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var posted []string
+		poster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				posted = append(posted, alert.AlertID)
+				cancel()
+				return nil
+			},
+		}
+		dedup := NewMockDeduplicator()
+		processor, stateStore := newCheckpointedProcessor(t, poster, dedup)
+
+		_, err := processor.ProcessAlerts(ctx, alerts)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []string{"alert-2", "alert-3"}, pendingIDs(t, stateStore))
+
+		// The remaining alerts stay recorded, so re-fetching them doesn't post them twice
+		_, err = processor.ProcessAlerts(context.Background(), alerts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alert-1", "alert-2", "alert-3"}, posted)
+		assert.Empty(t, pendingIDs(t, stateStore))
+	})
+}
+
+func TestPendingQueue_Flush(t *testing.T) {
+	api := &plugintest.API{}
+	kvStore := mockKVStore(api)
+	stateStore := NewStateStore(api, "test-backend-id")
+	queue := newPendingQueue(stateStore)
+
+	require.NoError(t, queue.add([]pendingPost{
+		{alert: backend.Alert{AlertID: "alert-1"}, channelID: "channel-1"},
+		{alert: backend.Alert{AlertID: "alert-2"}, channelID: "channel-1"},
+		{alert: backend.Alert{AlertID: "alert-3"}, channelID: "channel-1"},
+	}))
+	assert.Equal(t, []string{"alert-1", "alert-2", "alert-3"}, pendingIDs(t, stateStore), "Added alerts are saved right away")
+
+	queue.remove("alert-1", "channel-1")
+	_, err := queue.recordFailure("alert-2", "channel-1", errors.New("post failed"), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alert-1", "alert-2", "alert-3"}, pendingIDs(t, stateStore), "Changes wait for the flush")
+
+	require.NoError(t, queue.flush())
+	pending, err := stateStore.GetPendingAlerts()
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "alert-2", pending[0].Alert.AlertID)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, "alert-3", pending[1].Alert.AlertID)
+
+	// Flushing without changes doesn't write the queue again
+	delete(kvStore, "backend_test-backend-id_pending")
+	require.NoError(t, queue.flush())
+	assert.NotContains(t, kvStore, "backend_test-backend-id_pending")
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, backend.AlertPostRetryDelay, retryDelay(1))
+	assert.Equal(t, 2*backend.AlertPostRetryDelay, retryDelay(2))
+	assert.Equal(t, 4*backend.AlertPostRetryDelay, retryDelay(3))
+	assert.Equal(t, backend.MaxAlertPostRetryDelay, retryDelay(20))
+}
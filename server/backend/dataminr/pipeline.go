@@ -0,0 +1,149 @@
+package dataminr
+
+import (
+	"context"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// StagePhase orders the stages of the alert pipeline. Stages run in phase order and, within
+// a phase, in the order they were added.
+type StagePhase int
+
+const (
+	// PhaseNormalize converts the fetched Dataminr alerts into backend alerts
+	PhaseNormalize StagePhase = iota
+
+	// PhaseDedup drops alerts that were already processed
+	PhaseDedup
+
+	// PhaseFilter drops, holds back or summarizes alerts before they are enriched
+	PhaseFilter
+
+	// PhaseEnrich adds information to each alert once, before it is copied to other channels
+	PhaseEnrich
+
+	// PhaseRoute adds a copy of the alerts for every channel they are posted in. Stages that
+	// depend on the target channel, such as channel mutes and translation, run after routing.
+	PhaseRoute
+
+	// PhasePost posts the alerts
+	PhasePost
+)
+
+// String returns the name of the phase
+func (ph StagePhase) String() string {
+	switch ph {
+	case PhaseNormalize:
+		return "normalize"
+	case PhaseDedup:
+		return "dedup"
+	case PhaseFilter:
+		return "filter"
+	case PhaseEnrich:
+		return "enrich"
+	case PhaseRoute:
+		return "route"
+	case PhasePost:
+		return "post"
+	default:
+		return "unknown"
+	}
+}
+
+// AlertBatch is a batch of alerts passing through the pipeline. Stages added from outside
+// the package change it through its methods.
+type AlertBatch struct {
+	// fetched are the alerts returned by the Dataminr API, consumed by the normalize phase
+	fetched []Alert
+
+	// posts are the alerts left to post, one per target channel
+	posts []pendingPost
+
+	// retried are the alerts of the pending queue due for another attempt, posted together
+	// with the batch in priority order
+	retried []pendingPost
+
+	// processed counts the new alerts that were posted, or that were taken out of the batch
+	// without being posted individually (buffered, summarized or muted). Copies posted to
+	// subscribed channels are not counted.
+	processed int
+
+	// unposted are the posts left unposted because the context was cancelled
+	unposted []pendingPost
+}
+
+// Update calls update with each alert left to post and the channel it is posted to, keeping
+// the changes made to the alert. The fetched alerts are converted by the normalize phase, so
+// stages of that phase see no alerts.
+func (b *AlertBatch) Update(update func(alert *backend.Alert, channelID string)) {
+	for i := range b.posts {
+		update(&b.posts[i].alert, b.posts[i].channelID)
+	}
+}
+
+// Drop removes the alerts left to post for which drop returns true. Like muted alerts,
+// dropped alerts count as processed.
+func (b *AlertBatch) Drop(drop func(alert backend.Alert, channelID string) bool) {
+	posts := b.posts[:0]
+	for _, post := range b.posts {
+		if !drop(post.alert, post.channelID) {
+			posts = append(posts, post)
+			continue
+		}
+		if !post.subscribed {
+			b.processed++
+		}
+	}
+	b.posts = posts
+}
+
+// StageFunc processes a batch in place. A stage that returns an error has its error logged
+// and the batch passed on to the next stage, so one broken stage doesn't stop alerts.
+type StageFunc func(ctx context.Context, batch *AlertBatch) error
+
+// pipelineStage is a named step of the alert pipeline
+type pipelineStage struct {
+	name    string
+	phase   StagePhase
+	process StageFunc
+}
+
+// pipeline runs alert batches through an ordered list of stages
+type pipeline struct {
+	stages []pipelineStage
+}
+
+// add registers a stage, running it after the stages already added to the same phase
+func (pl *pipeline) add(phase StagePhase, name string, process StageFunc) {
+	i := len(pl.stages)
+	for i > 0 && pl.stages[i-1].phase > phase {
+		i--
+	}
+
+	pl.stages = append(pl.stages, pipelineStage{})
+	copy(pl.stages[i+1:], pl.stages[i:])
+	pl.stages[i] = pipelineStage{name: name, phase: phase, process: process}
+}
+
+// run passes the batch through the stages of the phases from first to last, inclusive,
+// logging the errors of failed stages
+func (pl *pipeline) run(ctx context.Context, batch *AlertBatch, first, last StagePhase, logger backend.Logger) {
+	for _, stage := range pl.stages {
+		if stage.phase < first || stage.phase > last {
+			continue
+		}
+		if err := stage.process(ctx, batch); err != nil {
+			logger.Error("Alert pipeline stage failed", "phase", stage.phase.String(), "stage", stage.name, "error", err.Error())
+		}
+	}
+}
+
+// names returns the "phase/name" of each stage in the order they run
+func (pl *pipeline) names() []string {
+	names := make([]string, 0, len(pl.stages))
+	for _, stage := range pl.stages {
+		names = append(names, stage.phase.String()+"/"+stage.name)
+	}
+	return names
+}
@@ -0,0 +1,187 @@
+package dataminr
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// recordingStage returns a fake stage appending its name to calls
+func recordingStage(name string, calls *[]string) StageFunc {
+	return func(_ context.Context, _ *AlertBatch) error {
+		*calls = append(*calls, name)
+		return nil
+	}
+}
+
+func TestPipeline_Add(t *testing.T) {
+	var calls []string
+	var pl pipeline
+	pl.add(PhasePost, "post", recordingStage("post", &calls))
+	pl.add(PhaseNormalize, "normalize", recordingStage("normalize", &calls))
+	pl.add(PhaseFilter, "filter-1", recordingStage("filter-1", &calls))
+	pl.add(PhaseRoute, "route", recordingStage("route", &calls))
+	pl.add(PhaseFilter, "filter-2", recordingStage("filter-2", &calls))
+
+	assert.Equal(t, []string{"normalize/normalize", "filter/filter-1", "filter/filter-2", "route/route", "post/post"}, pl.names())
+
+	pl.run(context.Background(), &AlertBatch{}, PhaseNormalize, PhasePost, &pluginapi.LogService{})
+	assert.Equal(t, []string{"normalize", "filter-1", "filter-2", "route", "post"}, calls)
+}
+
+func TestPipeline_Run(t *testing.T) {
+	t.Run("runs only the stages of the given phases", func(t *testing.T) {
+		var calls []string
+		var pl pipeline
+		pl.add(PhaseDedup, "dedup", recordingStage("dedup", &calls))
+		pl.add(PhaseEnrich, "enrich", recordingStage("enrich", &calls))
+		pl.add(PhaseRoute, "route", recordingStage("route", &calls))
+		pl.add(PhasePost, "post", recordingStage("post", &calls))
+
+		pl.run(context.Background(), &AlertBatch{}, PhaseEnrich, PhaseRoute, &pluginapi.LogService{})
+		assert.Equal(t, []string{"enrich", "route"}, calls)
+	})
+
+	t.Run("passes the batch from stage to stage", func(t *testing.T) {
+		var pl pipeline
+		pl.add(PhaseFilter, "drop-urgent", func(_ context.Context, batch *AlertBatch) error {
+			batch.Drop(func(alert backend.Alert, _ string) bool {
+				return alert.AlertType == "Urgent"
+			})
+			return nil
+		})
+		pl.add(PhaseEnrich, "tag", func(_ context.Context, batch *AlertBatch) error {
+			batch.Update(func(alert *backend.Alert, _ string) {
+				alert.Headline = "[tagged] " + alert.Headline
+			})
+			return nil
+		})
+
+		batch := &AlertBatch{posts: []pendingPost{
+			{alert: backend.Alert{AlertID: "alert-1", AlertType: "Urgent", Headline: "One"}},
+			{alert: backend.Alert{AlertID: "alert-2", AlertType: "Alert", Headline: "Two"}},
+		}}
+		pl.run(context.Background(), batch, PhaseNormalize, PhasePost, &pluginapi.LogService{})
+
+		require.Len(t, batch.posts, 1)
+		assert.Equal(t, "[tagged] Two", batch.posts[0].alert.Headline)
+		assert.Equal(t, 1, batch.processed)
+	})
+
+	t.Run("logs failed stages and passes the batch on", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("LogError", "Alert pipeline stage failed", "phase", "enrich", "stage", "broken", "error", "enrichment unavailable").Once()
+		logger := &pluginapi.NewClient(api, &plugintest.Driver{}).Log
+
+		var calls []string
+		var pl pipeline
+		pl.add(PhaseEnrich, "broken", func(_ context.Context, _ *AlertBatch) error {
+			return errors.New("enrichment unavailable")
+		})
+		pl.add(PhasePost, "post", recordingStage("post", &calls))
+
+		pl.run(context.Background(), &AlertBatch{}, PhaseNormalize, PhasePost, logger)
+		assert.Equal(t, []string{"post"}, calls)
+	})
+}
+
+func TestAlertBatch_Drop(t *testing.T) {
+	batch := &AlertBatch{posts: []pendingPost{
+		{alert: backend.Alert{AlertID: "alert-1"}, channelID: "channel-1"},
+		{alert: backend.Alert{AlertID: "alert-1"}, channelID: "channel-2", subscribed: true},
+		{alert: backend.Alert{AlertID: "alert-2"}, channelID: "channel-1"},
+	}}
+
+	batch.Drop(func(alert backend.Alert, _ string) bool {
+		return alert.AlertID == "alert-1"
+	})
+
+	require.Len(t, batch.posts, 1)
+	assert.Equal(t, "alert-2", batch.posts[0].alert.AlertID)
+	assert.Equal(t, 1, batch.processed, "subscribed copies are not counted")
+}
+
+func TestStagePhase_String(t *testing.T) {
+	assert.Equal(t, "normalize", PhaseNormalize.String())
+	assert.Equal(t, "post", PhasePost.String())
+	assert.Equal(t, "unknown", StagePhase(42).String())
+}
+
+func TestAlertProcessor_Pipeline(t *testing.T) {
+	newProcessor := func(t *testing.T, poster *MockPoster) *AlertProcessor {
+		api := plugintest.NewAPI(t)
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+		return NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", poster, "test-channel-id", NewMockDeduplicator(), nil)
+	}
+
+	t.Run("runs the built-in stages in phase order", func(t *testing.T) {
+		processor := newProcessor(t, &MockPoster{})
+
+		assert.Equal(t, []string{
+			"normalize/normalize",
+			"dedup/deduplicate",
+			"filter/quiet-hours",
+			"filter/batch-limit",
+			"enrich/summarize",
+			"route/subscriptions",
+			"route/topic-mutes",
+			"route/alert-list-mutes",
+			"route/translate",
+			"post/checkpoint",
+			"post/post",
+		}, processor.pipeline.names())
+	})
+
+	t.Run("added stages process alerts without changes to the processor", func(t *testing.T) {
+		var posted []backend.Alert
+		processor := newProcessor(t, &MockPoster{
+			PostAlertFn: func(alert backend.Alert, _ string) error {
+				posted = append(posted, alert)
+				return nil
+			},
+		})
+
+		var filtered, enriched []string
+		processor.AddStage(PhaseFilter, "drop-alert-2", func(_ context.Context, batch *AlertBatch) error {
+			batch.Drop(func(alert backend.Alert, _ string) bool {
+				filtered = append(filtered, alert.AlertID)
+				return alert.AlertID == "alert-2"
+			})
+			return nil
+		})
+		processor.AddStage(PhaseEnrich, "tag", func(_ context.Context, batch *AlertBatch) error {
+			batch.Update(func(alert *backend.Alert, _ string) {
+				enriched = append(enriched, alert.AlertID)
+				alert.Headline += " (tagged)"
+			})
+			return nil
+		})
+
+		eventTime := time.Now().UTC()
+		alerts := []Alert{
+			{AlertID: "alert-1", AlertType: AlertType{Name: "Alert"}, EventTime: eventTime, Headline: "One"},
+			{AlertID: "alert-2", AlertType: AlertType{Name: "Alert"}, EventTime: eventTime, Headline: "Two"},
+			{AlertID: "alert-1", AlertType: AlertType{Name: "Alert"}, EventTime: eventTime, Headline: "One"},
+		}
+
+		count, err := processor.ProcessAlerts(context.Background(), alerts)
+		require.NoError(t, err)
+
+		// Duplicates are dropped before filters run
+		assert.Equal(t, []string{"alert-1", "alert-2"}, filtered)
+		assert.Equal(t, []string{"alert-1"}, enriched)
+		require.Len(t, posted, 1)
+		assert.Equal(t, "One (tagged)", posted[0].Headline)
+		assert.Equal(t, 2, count, "dropped alerts count as processed")
+	})
+}
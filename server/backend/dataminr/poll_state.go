@@ -0,0 +1,430 @@
+package dataminr
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// PollState is the state a poll cycle updates, persisted as a single KV document so a
+// cycle saves it with one write instead of one per field
+type PollState struct {
+	Cursor      string        `json:"cursor,omitempty"`
+	LastPoll    time.Time     `json:"lastPoll"`
+	LastSuccess time.Time     `json:"lastSuccess"`
+	Failures    int           `json:"failures,omitempty"`
+	LastError   string        `json:"lastError,omitempty"`
+	Phase       backend.Phase `json:"phase,omitempty"`
+
+	// WindowStart is set while alerts are fetched by time window after the API rejected the
+	// cursor; it is the start of the next window
+	WindowStart time.Time `json:"windowStart"`
+
+	// CatchUp is set while a catch-up is in progress, so it resumes from the cursor after a
+	// restart or failure instead of posting the rest of the backlog as new alerts
+	CatchUp *backend.CatchUpProgress `json:"catchUp,omitempty"`
+}
+
+// equal reports whether two poll states hold the same values
+func (s PollState) equal(other PollState) bool {
+	return s.Cursor == other.Cursor &&
+		s.LastPoll.Equal(other.LastPoll) &&
+		s.LastSuccess.Equal(other.LastSuccess) &&
+		s.Failures == other.Failures &&
+		s.LastError == other.LastError &&
+		s.Phase == other.Phase &&
+		s.WindowStart.Equal(other.WindowStart) &&
+		s.CatchUp.Equal(other.CatchUp)
+}
+
+// pollStateBatch holds the poll state while its changes are batched, with the changes not yet
+// saved so they can be applied again over changes made by other nodes
+type pollStateBatch struct {
+	state   PollState
+	changes []func(*PollState)
+}
+
+// BeginBatch starts batching poll state changes: they are kept in memory and saved with a
+// single write by Flush. Other nodes see the changes only once they are flushed.
+func (s *StateStore) BeginBatch() error {
+	s.pollStateMu.Lock()
+	defer s.pollStateMu.Unlock()
+
+	if s.batch != nil {
+		return nil
+	}
+
+	state, err := s.loadPollState()
+	if err != nil {
+		return err
+	}
+	s.batch = &pollStateBatch{state: state}
+	return nil
+}
+
+// Flush saves the poll state changed since BeginBatch, if any, and stops batching
+func (s *StateStore) Flush() error {
+	s.pollStateMu.Lock()
+	defer s.pollStateMu.Unlock()
+
+	err := s.saveBatch()
+	s.batch = nil
+	return err
+}
+
+// saveBatch saves the batched poll state changes, if any. Only the changed fields are saved,
+// so changes other nodes made to the other fields since BeginBatch are kept. Callers must hold
+// pollStateMu.
+func (s *StateStore) saveBatch() error {
+	if s.batch == nil || len(s.batch.changes) == 0 {
+		return nil
+	}
+	state, err := s.applyPollState(s.batch.changes...)
+	if err != nil {
+		return err
+	}
+	s.batch.state = state
+	s.batch.changes = nil
+	return nil
+}
+
+// Checkpoint saves the poll state changed since BeginBatch, if any, and keeps batching.
+// Long poll cycles checkpoint their progress so a restart does not lose it.
+func (s *StateStore) Checkpoint() error {
+	s.pollStateMu.Lock()
+	defer s.pollStateMu.Unlock()
+
+	return s.saveBatch()
+}
+
+// GetPollState returns the poll state, including changes not yet flushed
+func (s *StateStore) GetPollState() (PollState, error) {
+	s.pollStateMu.Lock()
+	defer s.pollStateMu.Unlock()
+
+	if s.batch != nil {
+		return s.batch.state, nil
+	}
+	return s.loadPollState()
+}
+
+// updatePollState applies a change to the poll state. While batching the change is kept until
+// Flush; otherwise it is saved right away. Unchanged state is not saved.
+func (s *StateStore) updatePollState(update func(*PollState)) error {
+	s.pollStateMu.Lock()
+	defer s.pollStateMu.Unlock()
+
+	if s.batch != nil {
+		before := s.batch.state
+		update(&s.batch.state)
+		if !s.batch.state.equal(before) {
+			s.batch.changes = append(s.batch.changes, update)
+		}
+		return nil
+	}
+
+	_, err := s.applyPollState(update)
+	return err
+}
+
+// applyPollState applies changes to the stored poll state and saves it if they changed it,
+// retrying over concurrent updates from other nodes. Returns the resulting state. Callers
+// must hold pollStateMu.
+func (s *StateStore) applyPollState(changes ...func(*PollState)) (PollState, error) {
+	key := fmt.Sprintf(kvKeyPollState, s.backendID)
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		oldData, state, err := s.readPollState()
+		if err != nil {
+			return PollState{}, err
+		}
+
+		before := state
+		for _, change := range changes {
+			change(&state)
+		}
+		if state.equal(before) {
+			return state, nil
+		}
+
+		newData, err := json.Marshal(state)
+		if err != nil {
+			return PollState{}, fmt.Errorf("failed to marshal poll state: %w", err)
+		}
+		saved, appErr := s.api.KVSetWithOptions(key, newData, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return PollState{}, fmt.Errorf("failed to save poll state: %w", appErr)
+		}
+		if saved {
+			return state, nil
+		}
+	}
+
+	return PollState{}, fmt.Errorf("failed to save poll state: too many concurrent updates")
+}
+
+// loadPollState reads the poll state document, migrating the legacy per-field keys the first
+// time it is missing. Callers must hold pollStateMu.
+func (s *StateStore) loadPollState() (PollState, error) {
+	_, state, err := s.readPollState()
+	return state, err
+}
+
+// readPollState reads the poll state document and returns it with its stored value, migrating
+// the legacy per-field keys the first time it is missing. Callers must hold pollStateMu.
+func (s *StateStore) readPollState() ([]byte, PollState, error) {
+	key := fmt.Sprintf(kvKeyPollState, s.backendID)
+	data, appErr := s.api.KVGet(key)
+	if appErr != nil {
+		return nil, PollState{}, fmt.Errorf("failed to get poll state: %w", appErr)
+	}
+
+	if data == nil && !s.migrated {
+		migrated, err := s.migrateLegacyPollState()
+		if err != nil {
+			return nil, PollState{}, err
+		}
+		if !migrated.equal(PollState{}) {
+			if data, appErr = s.api.KVGet(key); appErr != nil {
+				return nil, PollState{}, fmt.Errorf("failed to get poll state: %w", appErr)
+			}
+		}
+	}
+
+	var state PollState
+	if data != nil {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, PollState{}, fmt.Errorf("failed to unmarshal poll state: %w", err)
+		}
+	}
+	return data, state, nil
+}
+
+// savePollState writes the poll state document. Callers must hold pollStateMu.
+func (s *StateStore) savePollState(state PollState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal poll state: %w", err)
+	}
+
+	if appErr := s.api.KVSet(fmt.Sprintf(kvKeyPollState, s.backendID), data); appErr != nil {
+		return fmt.Errorf("failed to save poll state: %w", appErr)
+	}
+	return nil
+}
+
+// migrateLegacyPollState moves the poll state fields stored under their own keys into the poll
+// state document and deletes the old keys. Callers must hold pollStateMu.
+func (s *StateStore) migrateLegacyPollState() (PollState, error) {
+	var state PollState
+	found := false
+
+	legacy := func(format string, decode func([]byte) error) error {
+		data, appErr := s.api.KVGet(fmt.Sprintf(format, s.backendID))
+		if appErr != nil {
+			return fmt.Errorf("failed to get legacy poll state: %w", appErr)
+		}
+		if data == nil {
+			return nil
+		}
+		found = true
+		return decode(data)
+	}
+
+	decoders := []struct {
+		format string
+		decode func([]byte) error
+	}{
+		{kvKeyCursor, func(data []byte) error { state.Cursor = string(data); return nil }},
+		{kvKeyLastPoll, func(data []byte) error { return json.Unmarshal(data, &state.LastPoll) }},
+		{kvKeyLastSuccess, func(data []byte) error { return json.Unmarshal(data, &state.LastSuccess) }},
+		{kvKeyFailures, func(data []byte) error { return json.Unmarshal(data, &state.Failures) }},
+		{kvKeyLastError, func(data []byte) error { state.LastError = string(data); return nil }},
+		{kvKeyPhase, func(data []byte) error { state.Phase = backend.Phase(data); return nil }},
+	}
+	for _, d := range decoders {
+		if err := legacy(d.format, d.decode); err != nil {
+			return PollState{}, fmt.Errorf("failed to migrate poll state: %w", err)
+		}
+	}
+
+	if found {
+		if err := s.savePollState(state); err != nil {
+			return PollState{}, err
+		}
+		for _, d := range decoders {
+			if appErr := s.api.KVDelete(fmt.Sprintf(d.format, s.backendID)); appErr != nil {
+				return PollState{}, fmt.Errorf("failed to delete legacy poll state: %w", appErr)
+			}
+		}
+	}
+
+	s.migrated = true
+	return state, nil
+}
+
+// SaveCursor stores the pagination cursor for the next API request
+func (s *StateStore) SaveCursor(cursor string) error {
+	if err := s.updatePollState(func(state *PollState) { state.Cursor = cursor }); err != nil {
+		return fmt.Errorf("failed to save cursor: %w", err)
+	}
+	return nil
+}
+
+// GetCursor retrieves the stored pagination cursor
+// Returns empty string if no cursor is stored
+func (s *StateStore) GetCursor() (string, error) {
+	state, err := s.GetPollState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get cursor: %w", err)
+	}
+	return state.Cursor, nil
+}
+
+// SaveWindowStart stores the start of the next time window to fetch alerts from.
+// The zero time leaves time window polling.
+func (s *StateStore) SaveWindowStart(t time.Time) error {
+	if err := s.updatePollState(func(state *PollState) { state.WindowStart = t }); err != nil {
+		return fmt.Errorf("failed to save time window start: %w", err)
+	}
+	return nil
+}
+
+// SaveCatchUp stores the progress of the catch-up in progress. Nil marks the catch-up completed.
+func (s *StateStore) SaveCatchUp(progress *backend.CatchUpProgress) error {
+	if progress != nil {
+		saved := *progress
+		progress = &saved
+	}
+	if err := s.updatePollState(func(state *PollState) { state.CatchUp = progress }); err != nil {
+		return fmt.Errorf("failed to save catch-up progress: %w", err)
+	}
+	return nil
+}
+
+// SaveLastPoll stores the timestamp of the last poll attempt
+func (s *StateStore) SaveLastPoll(t time.Time) error {
+	if err := s.updatePollState(func(state *PollState) { state.LastPoll = t }); err != nil {
+		return fmt.Errorf("failed to save last poll time: %w", err)
+	}
+	return nil
+}
+
+// GetLastPoll retrieves the timestamp of the last poll attempt
+// Returns zero time if no poll time is stored
+func (s *StateStore) GetLastPoll() (time.Time, error) {
+	state, err := s.GetPollState()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last poll time: %w", err)
+	}
+	return state.LastPoll, nil
+}
+
+// IncrementFailures increments the consecutive failures counter and returns the new count
+func (s *StateStore) IncrementFailures() (int, error) {
+	count := 0
+	if err := s.updatePollState(func(state *PollState) {
+		state.Failures++
+		count = state.Failures
+	}); err != nil {
+		return 0, fmt.Errorf("failed to save failures count: %w", err)
+	}
+	return count, nil
+}
+
+// ResetFailures resets the consecutive failures counter to zero and returns the count before
+func (s *StateStore) ResetFailures() (int, error) {
+	previous := 0
+	if err := s.updatePollState(func(state *PollState) {
+		previous = state.Failures
+		state.Failures = 0
+	}); err != nil {
+		return 0, fmt.Errorf("failed to reset failures count: %w", err)
+	}
+	return previous, nil
+}
+
+// GetFailures retrieves the current consecutive failures count
+// Returns 0 if no count is stored
+func (s *StateStore) GetFailures() (int, error) {
+	state, err := s.GetPollState()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get failures count: %w", err)
+	}
+	return state.Failures, nil
+}
+
+// SaveLastSuccess stores the timestamp of the last successful poll
+func (s *StateStore) SaveLastSuccess(t time.Time) error {
+	if err := s.updatePollState(func(state *PollState) { state.LastSuccess = t }); err != nil {
+		return fmt.Errorf("failed to save last success time: %w", err)
+	}
+	return nil
+}
+
+// GetLastSuccess retrieves the timestamp of the last successful poll
+// Returns zero time if no success time is stored
+func (s *StateStore) GetLastSuccess() (time.Time, error) {
+	state, err := s.GetPollState()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last success time: %w", err)
+	}
+	return state.LastSuccess, nil
+}
+
+// SaveLastError stores the error message from the most recent failure
+func (s *StateStore) SaveLastError(errMsg string) error {
+	if err := s.updatePollState(func(state *PollState) { state.LastError = errMsg }); err != nil {
+		return fmt.Errorf("failed to save last error: %w", err)
+	}
+	return nil
+}
+
+// ClearLastError removes the error message from the most recent failure
+func (s *StateStore) ClearLastError() error {
+	if err := s.updatePollState(func(state *PollState) { state.LastError = "" }); err != nil {
+		return fmt.Errorf("failed to clear last error: %w", err)
+	}
+	return nil
+}
+
+// GetLastError retrieves the error message from the most recent failure
+// Returns empty string if no error is stored
+func (s *StateStore) GetLastError() (string, error) {
+	state, err := s.GetPollState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get last error: %w", err)
+	}
+	return state.LastError, nil
+}
+
+// SavePhase stores the current phase of the polling lifecycle. Phase changes are saved right
+// away even while batching, so every node reports the phase of a long poll cycle.
+func (s *StateStore) SavePhase(phase backend.Phase) error {
+	if err := s.updatePollState(func(state *PollState) { state.Phase = phase }); err != nil {
+		return fmt.Errorf("failed to save phase: %w", err)
+	}
+
+	s.pollStateMu.Lock()
+	defer s.pollStateMu.Unlock()
+	if err := s.saveBatch(); err != nil {
+		return fmt.Errorf("failed to save phase: %w", err)
+	}
+	return nil
+}
+
+// GetPhase retrieves the current phase of the polling lifecycle
+// Returns an empty phase if none is stored
+func (s *StateStore) GetPhase() (backend.Phase, error) {
+	state, err := s.GetPollState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get phase: %w", err)
+	}
+	return state.Phase, nil
+}
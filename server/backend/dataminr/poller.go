@@ -1,7 +1,12 @@
 package dataminr
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/mattermost/mattermost/server/public/plugin"
@@ -11,23 +16,78 @@ import (
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
 
+// maxCatchUpPages bounds the number of pages fetched during a single catch-up
+const maxCatchUpPages = 50
+
+// maxPollPages bounds the number of pages fetched during a single regular poll cycle, so a
+// large burst is drained over a few cycles instead of blocking the poller indefinitely
+const maxPollPages = 10
+
+// alertsPageSize is the number of alerts requested from the alerts endpoint per page. A page
+// with fewer alerts means the backlog is drained and no further page is requested.
+const alertsPageSize = 40
+
+// defaultRateLimitBackoff is how long polling backs off after a rate limit error without a Retry-After
+const defaultRateLimitBackoff = time.Minute
+
+// maxRateLimitBackoff caps how long polling backs off after a rate limit error
+const maxRateLimitBackoff = 15 * time.Minute
+
 // AlertFetcher is an interface for fetching alerts from the Dataminr API
 type AlertFetcher interface {
-	FetchAlerts(cursor string) (*AlertsResponse, error)
+	FetchAlerts(ctx context.Context, cursor string) (*AlertsResponse, error)
+}
+
+// authResetter is implemented by fetchers that cache an authentication token
+type authResetter interface {
+	ResetAuth() error
 }
 
 // Poller manages the cluster-aware scheduled polling job for a Dataminr backend
 type Poller struct {
 	api             *pluginapi.Client
+	logger          backend.Logger
 	backendID       string
 	backendName     string
 	interval        time.Duration
 	client          AlertFetcher
 	processor       *AlertProcessor
 	stateStore      *StateStore
+	history         backend.PollHistoryStore
 	scheduler       JobScheduler
 	job             Job
 	disableCallback backend.DisableCallback
+	startupJitter   time.Duration
+	catchUpWindow   time.Duration
+	postHistorical  bool
+	circuitBreaker  *backend.CircuitBreakerSettings
+
+	// failureThreshold is how many consecutive failed polls disable the backend or open the
+	// circuit breaker; warner is sent a warning FailureWarningMargin failures before (nil sends none)
+	failureThreshold int
+	warner           backend.AlertPoster
+
+	// tagging generates the correlation ID sent with the requests of each poll cycle (nil
+	// sends none); correlationID is the ID of the current cycle, only used by the job goroutine
+	tagging       *taggingTransport
+	correlationID string
+
+	// drainTimeout is how long Stop waits for an in-flight poll cycle before cancelling it
+	drainTimeout time.Duration
+
+	// mu guards firstRunAt, which is set on Start and cleared once the first poll runs,
+	// the next run time last computed for the job scheduler, the last saved phase, the
+	// context cancelled when Stop gives up waiting for an in-flight poll cycle, the channel
+	// closed once the stopped job has closed, and the listener called when the backend's
+	// status may have changed
+	mu             sync.Mutex
+	firstRunAt     time.Time
+	nextRunAt      time.Time
+	phase          backend.Phase
+	ctx            context.Context
+	cancel         context.CancelFunc
+	jobClosed      chan struct{}
+	statusListener func()
 }
 
 // NewPoller creates a new poller instance
@@ -42,24 +102,99 @@ func NewPoller(
 	stateStore *StateStore,
 	disableCallback backend.DisableCallback,
 ) *Poller {
+	jobClosed := make(chan struct{})
+	close(jobClosed)
+
 	return &Poller{
-		api:             api,
-		backendID:       backendID,
-		backendName:     backendName,
-		interval:        interval,
-		client:          client,
-		processor:       processor,
-		stateStore:      stateStore,
-		scheduler:       NewClusterJobScheduler(papi),
-		disableCallback: disableCallback,
+		api:              api,
+		logger:           &api.Log,
+		backendID:        backendID,
+		backendName:      backendName,
+		interval:         interval,
+		client:           client,
+		processor:        processor,
+		stateStore:       stateStore,
+		history:          stateStore,
+		scheduler:        NewClusterJobScheduler(papi),
+		disableCallback:  disableCallback,
+		failureThreshold: backend.MaxConsecutiveFailures,
+		drainTimeout:     backend.ShutdownDrainTimeout,
+		jobClosed:        jobClosed,
 	}
 }
 
+// SetLogger sets the logger for the backend's poll cycles
+func (p *Poller) SetLogger(logger backend.Logger) {
+	p.logger = logger
+}
+
 // SetScheduler sets a custom job scheduler (useful for testing)
 func (p *Poller) SetScheduler(scheduler JobScheduler) {
 	p.scheduler = scheduler
 }
 
+// SetStartupJitter sets the maximum random delay added to the first poll after Start
+func (p *Poller) SetStartupJitter(jitter time.Duration) {
+	p.startupJitter = jitter
+}
+
+// SetCatchUp configures how alerts available when starting without a cursor are handled.
+// They are skipped unless postHistorical is set, in which case those within the window are
+// posted. The window also bounds the time window polled after the API rejects the cursor.
+func (p *Poller) SetCatchUp(window time.Duration, postHistorical bool) {
+	p.catchUpWindow = window
+	p.postHistorical = postHistorical
+}
+
+// SetCircuitBreaker enables cool-down cycles for a failing backend before it is disabled.
+// A nil value keeps the default behavior of disabling at the failure threshold.
+func (p *Poller) SetCircuitBreaker(settings *backend.CircuitBreakerSettings) {
+	p.circuitBreaker = settings
+}
+
+// SetFailureThreshold sets how many consecutive failed polls disable the backend, or open
+// the circuit breaker when one is configured
+func (p *Poller) SetFailureThreshold(threshold int) {
+	p.failureThreshold = threshold
+}
+
+// SetFailureWarning posts a warning to the admin channel through the poster once the backend
+// is FailureWarningMargin failures away from its failure threshold
+func (p *Poller) SetFailureWarning(warner backend.AlertPoster) {
+	p.warner = warner
+}
+
+// SetPollHistoryStore sets where poll cycle outcomes are recorded instead of the backend's state
+func (p *Poller) SetPollHistoryStore(store backend.PollHistoryStore) {
+	p.history = store
+}
+
+// SetStatusListener sets the function called when a poll cycle changes the phase or the
+// failing state of the backend (nil calls none)
+func (p *Poller) SetStatusListener(listener func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.statusListener = listener
+}
+
+// reportStatus calls the status listener, if any
+func (p *Poller) reportStatus() {
+	p.mu.Lock()
+	listener := p.statusListener
+	p.mu.Unlock()
+
+	if listener != nil {
+		listener()
+	}
+}
+
+// SetRequestTagging sends a new correlation ID with the requests of each poll cycle through
+// the transport, and logs it with the cycle
+func (p *Poller) SetRequestTagging(tagging *taggingTransport) {
+	p.tagging = tagging
+}
+
 // Start begins the polling job using Mattermost's cluster job system
 // This ensures only one server instance polls in a multi-server cluster
 func (p *Poller) Start() error {
@@ -67,6 +202,13 @@ func (p *Poller) Start() error {
 		return fmt.Errorf("poller already running")
 	}
 
+	// Hold back the first poll so backends don't all poll at the same instant
+	p.mu.Lock()
+	p.firstRunAt = time.Now().Add(p.startDelay())
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.mu.Unlock()
+
+	p.setPhase(backend.PhaseStarting)
 	return p.startRegularJob()
 }
 
@@ -81,31 +223,192 @@ func (p *Poller) startRegularJob() error {
 	}
 
 	p.job = job
-	p.api.Log.Info("Poller started", "backendId", p.backendID, "backendName", p.backendName, "interval", p.interval)
+	p.logger.Info("Poller started", "backendId", p.backendID, "backendName", p.backendName, "interval", p.interval)
 	return nil
 }
 
-// Stop gracefully stops the polling job
-func (p *Poller) Stop() error {
+// Stop gracefully stops the polling job.
+// An in-flight poll cycle is given the drain timeout to finish, or less if the context is done
+// first; after that its context is cancelled so it stops posting, keeps the cursor and leaves
+// the remaining alerts for the next poll. Once the context is done Stop returns without
+// waiting for the cancelled cycle.
+func (p *Poller) Stop(ctx context.Context) error {
 	if p.job == nil {
 		return nil
 	}
 
-	err := p.job.Close()
+	job := p.job
 	p.job = nil
 
+	jobClosed := make(chan struct{})
+	p.mu.Lock()
+	p.jobClosed = jobClosed
+	p.mu.Unlock()
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- job.Close()
+		close(jobClosed)
+	}()
+
+	var err error
+	select {
+	case err = <-closed:
+	case <-time.After(p.drainTimeout):
+		p.logger.Warn("Poll cycle still running after drain timeout, cancelling it",
+			"backendId", p.backendID,
+			"backendName", p.backendName,
+			"drainTimeout", p.drainTimeout)
+		p.cancelRun()
+		err = p.waitClosed(ctx, closed)
+	case <-ctx.Done():
+		p.logger.Warn("Poll cycle still running when stopping was cancelled, cancelling it",
+			"backendId", p.backendID,
+			"backendName", p.backendName)
+		p.cancelRun()
+		err = p.waitClosed(ctx, closed)
+	}
+	p.cancelRun()
+
+	p.mu.Lock()
+	p.nextRunAt = time.Time{}
+	p.mu.Unlock()
+
 	if err != nil {
-		p.api.Log.Error("Failed to close cluster job", "backendId", p.backendID, "error", err.Error())
+		p.logger.Error("Failed to close cluster job", "backendId", p.backendID, "error", err.Error())
 		return fmt.Errorf("failed to close cluster job: %w", err)
 	}
 
-	p.api.Log.Info("Poller stopped", "backendId", p.backendID, "backendName", p.backendName)
+	p.logger.Info("Poller stopped", "backendId", p.backendID, "backendName", p.backendName)
 	return nil
 }
 
+// Stopped returns a channel closed once the cluster job closed by the last Stop has closed,
+// which may be after Stop returned if it gave up waiting for an in-flight poll cycle
+func (p *Poller) Stopped() <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.jobClosed
+}
+
+// Reconfigure changes the backend name and poll interval, and runs apply to change other
+// settings used by poll cycles. A running job is stopped first so no poll cycle sees a partial
+// change, then scheduled again without the startup delay; the cursor, failure count and
+// cluster job metadata are kept, so the next poll follows the previous one by the new interval.
+func (p *Poller) Reconfigure(ctx context.Context, name string, interval time.Duration, apply func()) error {
+	running := p.job != nil
+	if running {
+		if err := p.Stop(ctx); err != nil {
+			return err
+		}
+	}
+
+	p.backendName = name
+	p.interval = interval
+	if apply != nil {
+		apply()
+	}
+
+	if !running {
+		return nil
+	}
+
+	p.mu.Lock()
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.mu.Unlock()
+	return p.startRegularJob()
+}
+
+// waitClosed waits for the job of a cancelled poll cycle to close, at most until the context
+// is done
+func (p *Poller) waitClosed(ctx context.Context, closed <-chan error) error {
+	select {
+	case err := <-closed:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("poll cycle did not stop in time: %w", ctx.Err())
+	}
+}
+
+// runContext returns the context of the current poll cycle
+func (p *Poller) runContext() context.Context {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ctx == nil {
+		return context.Background()
+	}
+	return p.ctx
+}
+
+// cancelRun cancels the context of an in-flight poll cycle
+func (p *Poller) cancelRun() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// startDelay returns how long to hold back the first poll after Start.
+// The delay combines a stagger offset derived from the backend ID, which spreads backends
+// sharing an interval across it, with a random startup jitter.
+func (p *Poller) startDelay() time.Duration {
+	delay := staggerOffset(p.backendID, p.interval)
+	if p.startupJitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(p.startupJitter))) //nolint:gosec // jitter does not need a secure source
+	}
+	return delay
+}
+
+// staggerOffset maps a backend ID to a stable offset within the poll interval.
+// Hashing keeps the offset the same across restarts and cluster nodes.
+func staggerOffset(backendID string, interval time.Duration) time.Duration {
+	intervalMillis := interval.Milliseconds()
+	if intervalMillis <= 0 {
+		return 0
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(backendID))
+	return time.Duration(int64(h.Sum32())%intervalMillis) * time.Millisecond
+}
+
 // nextWaitInterval is called by the cluster job scheduler to determine how long to wait
 // until the next poll. The metadata.LastFinished is automatically set by the cluster scheduler.
+// The first poll after Start is additionally held back until its staggered start time.
+// The resulting run time is recorded for NextRunAt.
 func (p *Poller) nextWaitInterval(now time.Time, metadata cluster.JobMetadata) time.Duration {
+	wait := p.intervalWait(now, metadata)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.firstRunAt.IsZero() {
+		if untilFirstRun := p.firstRunAt.Sub(now); untilFirstRun > wait {
+			wait = untilFirstRun
+		}
+	}
+
+	p.nextRunAt = now.Add(wait)
+	return wait
+}
+
+// NextRunAt returns when the job scheduler of this server next runs a poll cycle, as last
+// computed from the cluster job metadata. The time is in the past while a cycle is running or
+// when the scheduler is stuck, and zero before the job is scheduled or after Stop.
+// Paused, backing off or cooling down polls skip the cycle when it runs.
+func (p *Poller) NextRunAt() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.nextRunAt
+}
+
+// intervalWait returns the remaining wait based on when the previous poll finished
+func (p *Poller) intervalWait(now time.Time, metadata cluster.JobMetadata) time.Duration {
 	// For the first run, execute immediately
 	if metadata.LastFinished.IsZero() {
 		return 0
@@ -124,126 +427,616 @@ func (p *Poller) nextWaitInterval(now time.Time, metadata cluster.JobMetadata) t
 
 // run is called by the cluster job scheduler to execute a poll cycle
 func (p *Poller) run() {
-	p.api.Log.Debug("Starting poll cycle", "backendId", p.backendID, "backendName", p.backendName)
+	// The staggered start only applies to the first poll
+	p.mu.Lock()
+	p.firstRunAt = time.Time{}
+	p.mu.Unlock()
 
-	// Update last poll time
-	if err := p.stateStore.SaveLastPoll(time.Now()); err != nil {
-		p.api.Log.Error("Failed to save last poll time", "backendId", p.backendID, "error", err.Error())
+	// Skip the poll while paused; the cursor is kept so polling continues where it left off
+	paused, _, err := p.stateStore.GetPause(time.Now())
+	if err != nil {
+		p.logger.Error("Failed to load pause state", "backendId", p.backendID, "error", err.Error())
+	} else if paused {
+		p.logger.Debug("Skipping poll cycle while paused", "backendId", p.backendID, "backendName", p.backendName)
+		return
 	}
 
-	// Load cursor from state
-	cursor, err := p.stateStore.GetCursor()
+	// Skip the poll while backing off after a rate limit error, which may have been returned
+	// to another node of the cluster
+	backoffUntil, err := p.stateStore.GetBackoff()
 	if err != nil {
-		p.handlePollError(fmt.Errorf("failed to load cursor: %w", err))
+		p.logger.Error("Failed to load back-off state", "backendId", p.backendID, "error", err.Error())
+	} else if time.Now().Before(backoffUntil) {
+		p.logger.Debug("Skipping poll cycle during rate limit back-off",
+			"backendId", p.backendID,
+			"backendName", p.backendName,
+			"backoffUntil", backoffUntil)
 		return
 	}
 
-	// Fetch alerts from API
-	response, err := p.client.FetchAlerts(cursor)
-	if err != nil {
-		p.handlePollError(fmt.Errorf("failed to fetch alerts: %w", err))
+	// Skip the poll while the circuit breaker is cooling down
+	if p.circuitBreaker != nil {
+		cooldown, err := p.stateStore.GetCooldown()
+		if err != nil {
+			p.logger.Error("Failed to load cool-down state", "backendId", p.backendID, "error", err.Error())
+		} else if time.Now().Before(cooldown.Until) {
+			p.logger.Debug("Skipping poll cycle during circuit breaker cool-down",
+				"backendId", p.backendID,
+				"backendName", p.backendName,
+				"cooldownUntil", cooldown.Until)
+			return
+		}
+	}
+
+	// Queue alerts held back during quiet hours, and post the queued alerts once this run
+	// ends, even if the poll fails. A poll posts them earlier, with the alerts it fetched.
+	// Paused, backing off or cooling down runs leave both alone, so nothing is posted during
+	// maintenance and retries don't use up the attempts of queued alerts.
+	if p.processor != nil {
+		p.processor.DeliverHeldAlerts(p.runContext())
+		defer p.processor.RetryPendingAlerts(p.runContext())
+	}
+
+	p.correlationID = ""
+	if p.tagging != nil {
+		p.correlationID = p.tagging.newCorrelationID()
+	}
+	p.logger.Debug("Starting poll cycle", p.cycleFields()...)
+
+	// Batch the poll state changes of the cycle into a single write
+	if err := p.stateStore.BeginBatch(); err != nil {
+		p.logger.Error("Failed to load poll state", "backendId", p.backendID, "error", err.Error())
+	}
+	defer p.flushState()
+
+	started := time.Now()
+	alerts, newAlerts, err := p.poll(p.runContext(), started)
+	if errors.Is(err, context.Canceled) {
+		// Keep the cursor so the unposted alerts are fetched again
+		p.logInterrupted()
 		return
 	}
 
-	// Process alerts
-	newCount, err := p.processor.ProcessAlerts(response.Alerts)
+	sample := backend.PollSample{
+		Time:      started,
+		Success:   err == nil,
+		Alerts:    alerts,
+		NewAlerts: newAlerts,
+		LatencyMs: time.Since(started).Milliseconds(),
+	}
 	if err != nil {
-		p.handlePollError(fmt.Errorf("failed to process alerts: %w", err))
-		return
+		sample.Error = err.Error()
+		p.handlePollError(err)
+	} else {
+		p.recordSuccess()
 	}
 
-	// Save new cursor
-	if response.To != "" {
-		if err := p.stateStore.SaveCursor(response.To); err != nil {
-			p.handlePollError(fmt.Errorf("failed to save cursor: %w", err))
-			return
+	if recordErr := p.history.RecordPollSample(sample, time.Now()); recordErr != nil {
+		p.logger.Error("Failed to record poll history", "backendId", p.backendID, "error", recordErr.Error())
+	}
+}
+
+// flushState saves the poll state changed during the cycle
+func (p *Poller) flushState() {
+	if err := p.stateStore.Flush(); err != nil {
+		p.logger.Error("Failed to save poll state", "backendId", p.backendID, "error", err.Error())
+	}
+}
+
+// poll fetches and processes the pages of new alerts (or the catch-up backlog when no cursor
+// exists yet), saving the cursor after each page. Paging stops at a short page or after
+// maxPollPages. When the API rejects the cursor, alerts are fetched by time window instead.
+// Returns the number of alerts fetched and posted.
+func (p *Poller) poll(ctx context.Context, now time.Time) (int, int, error) {
+	// Update last poll time
+	if err := p.stateStore.SaveLastPoll(now); err != nil {
+		p.logger.Error("Failed to save last poll time", "backendId", p.backendID, "error", err.Error())
+	}
+
+	// Discard the cursor when an administrator reset it, so this cycle catches up. The reset is
+	// applied here rather than when requested so a cycle in progress cannot save over it.
+	reset, err := p.stateStore.ApplyCursorReset()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to apply cursor reset: %w", err)
+	}
+	if reset {
+		p.logger.Info("Cursor was reset, catching up", "backendId", p.backendID, "backendName", p.backendName)
+	}
+
+	// Load cursor from state
+	state, err := p.stateStore.GetPollState()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load cursor: %w", err)
+	}
+	cursor := state.Cursor
+
+	// Resume a catch-up interrupted by a restart, a failure or the page limit
+	if state.CatchUp != nil {
+		return p.catchUp(ctx)
+	}
+
+	// After the API rejected the cursor, fetch alerts by time window until it returns a new one
+	if cursor == "" && !state.WindowStart.IsZero() {
+		return p.pollWindow(ctx, state.WindowStart, now)
+	}
+
+	// Without a cursor, skip (or post) the backlog instead of treating it as new alerts
+	if cursor == "" {
+		return p.catchUp(ctx)
+	}
+
+	// Page through the alerts until the backlog is drained, saving the cursor after each page
+	// so a failure on a later page keeps the progress already made
+	total := 0
+	newTotal := 0
+	for page := 1; ; page++ {
+		response, err := p.client.FetchAlerts(ctx, cursor)
+		var cursorErr *CursorError
+		if errors.As(err, &cursorErr) {
+			alerts, newAlerts, err := p.fallBackToWindow(ctx, cursorErr, state.LastSuccess, now)
+			return total + alerts, newTotal + newAlerts, err
+		}
+		if err != nil {
+			return total, newTotal, fmt.Errorf("failed to fetch alerts: %w", err)
+		}
+
+		newCount, err := p.processor.ProcessAlerts(ctx, response.Alerts)
+		total += len(response.Alerts)
+		newTotal += newCount
+		if err != nil {
+			return total, newTotal, fmt.Errorf("failed to process alerts: %w", err)
+		}
+
+		// Stop once the API has nothing newer to return
+		if response.To == "" || response.To == cursor {
+			break
+		}
+
+		cursor = response.To
+		if err := p.stateStore.SaveCursor(cursor); err != nil {
+			return total, newTotal, fmt.Errorf("failed to save cursor: %w", err)
+		}
+
+		if len(response.Alerts) < alertsPageSize || ctx.Err() != nil {
+			break
+		}
+
+		if page >= maxPollPages {
+			p.logger.Info("Poll page limit reached, remaining alerts will be fetched next cycle",
+				"backendId", p.backendID,
+				"backendName", p.backendName,
+				"pages", page,
+				"totalAlerts", total)
+			break
+		}
+	}
+
+	p.logger.Debug("Poll cycle completed", p.cycleFields(
+		"totalAlerts", total,
+		"newAlerts", newTotal,
+		"cursor", cursor)...)
+	return total, newTotal, nil
+}
+
+// cycleFields returns the log fields identifying the backend and the correlation ID of the
+// current poll cycle, if any, followed by the given fields
+func (p *Poller) cycleFields(fields ...any) []any {
+	cycle := []any{"backendId", p.backendID, "backendName", p.backendName}
+	if p.correlationID != "" {
+		cycle = append(cycle, "correlationId", p.correlationID)
+	}
+	return append(cycle, fields...)
+}
+
+// logInterrupted logs a poll cycle cancelled by Stop, which is not counted as a failure
+func (p *Poller) logInterrupted() {
+	p.logger.Info("Poll cycle interrupted by shutdown, cursor not advanced",
+		"backendId", p.backendID,
+		"backendName", p.backendName)
+}
+
+// catchUp pages through the alerts the API returns when no cursor exists yet and stores the
+// resulting cursor, so regular polling continues from the latest position. Alerts older than
+// the catch-up window are skipped; newer ones are posted only if historical posting is enabled.
+// Alerts published after the catch-up started are posted as new.
+//
+// The progress is checkpointed after each page, so a catch-up interrupted by a restart, a
+// failure or the page limit resumes from the saved cursor in the next cycle instead of posting
+// the rest of the backlog as new alerts. Returns the number of alerts fetched and posted.
+func (p *Poller) catchUp(ctx context.Context) (int, int, error) {
+	p.setPhase(backend.PhaseCatchingUp)
+
+	state, err := p.stateStore.GetPollState()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load catch-up progress: %w", err)
+	}
+
+	progress := state.CatchUp
+	cursor := state.Cursor
+	if progress == nil {
+		progress = &backend.CatchUpProgress{StartedAt: time.Now()}
+		cursor = ""
+		if err := p.stateStore.SaveCatchUp(progress); err != nil {
+			return 0, 0, fmt.Errorf("failed to save catch-up progress: %w", err)
 		}
+	} else {
+		p.logger.Info("Resuming catch-up",
+			"backendId", p.backendID,
+			"backendName", p.backendName,
+			"startedAt", progress.StartedAt,
+			"skippedAlerts", progress.Skipped,
+			"postedAlerts", progress.Posted)
 	}
 
-	// Poll succeeded - update success state
+	cutoff := progress.StartedAt.Add(-p.catchUpWindow)
+	fetched := 0
+	posted := 0
+
+	for page := 0; page < maxCatchUpPages; page++ {
+		response, err := p.client.FetchAlerts(ctx, cursor)
+		if err != nil {
+			return fetched, posted, fmt.Errorf("failed to fetch alerts during catch-up: %w", err)
+		}
+		fetched += len(response.Alerts)
+
+		var historical []Alert
+		for _, alert := range response.Alerts {
+			if alert.EventTime.After(progress.StartedAt) || (p.postHistorical && alert.EventTime.After(cutoff)) {
+				historical = append(historical, alert)
+			} else {
+				progress.Skipped++
+			}
+		}
+
+		if len(historical) > 0 {
+			count, err := p.processor.ProcessAlerts(ctx, historical)
+			progress.Posted += count
+			posted += count
+			if err != nil {
+				return fetched, posted, fmt.Errorf("failed to process alerts during catch-up: %w", err)
+			}
+		}
+
+		// Stop once the API has nothing newer to return
+		if response.To == "" || response.To == cursor {
+			return fetched, posted, p.completeCatchUp(cursor, progress)
+		}
+
+		cursor = response.To
+		if err := p.checkpointCatchUp(cursor, progress); err != nil {
+			return fetched, posted, err
+		}
+
+		if len(response.Alerts) == 0 {
+			return fetched, posted, p.completeCatchUp(cursor, progress)
+		}
+		if ctx.Err() != nil {
+			return fetched, posted, nil
+		}
+	}
+
+	p.logger.Info("Catch-up page limit reached, continuing next cycle",
+		"backendId", p.backendID,
+		"backendName", p.backendName,
+		"pages", maxCatchUpPages,
+		"skippedAlerts", progress.Skipped,
+		"postedAlerts", progress.Posted)
+	return fetched, posted, nil
+}
+
+// checkpointCatchUp saves the cursor and progress of the catch-up after a page
+func (p *Poller) checkpointCatchUp(cursor string, progress *backend.CatchUpProgress) error {
+	if err := p.stateStore.SaveCursor(cursor); err != nil {
+		return fmt.Errorf("failed to save cursor during catch-up: %w", err)
+	}
+	if err := p.stateStore.SaveCatchUp(progress); err != nil {
+		return fmt.Errorf("failed to save catch-up progress: %w", err)
+	}
+	if err := p.stateStore.Checkpoint(); err != nil {
+		return fmt.Errorf("failed to checkpoint catch-up: %w", err)
+	}
+	return nil
+}
+
+// completeCatchUp saves the final cursor and marks the catch-up completed
+func (p *Poller) completeCatchUp(cursor string, progress *backend.CatchUpProgress) error {
+	if err := p.stateStore.SaveCursor(cursor); err != nil {
+		return fmt.Errorf("failed to save cursor during catch-up: %w", err)
+	}
+	if err := p.stateStore.SaveCatchUp(nil); err != nil {
+		return fmt.Errorf("failed to complete catch-up: %w", err)
+	}
+
+	p.logger.Info("Catch-up completed",
+		"backendId", p.backendID,
+		"backendName", p.backendName,
+		"postedAlerts", progress.Posted,
+		"skippedAlerts", progress.Skipped)
+	return nil
+}
+
+// recordSuccess updates the state after a successful poll cycle
+func (p *Poller) recordSuccess() {
 	now := time.Now()
 	if err := p.stateStore.SaveLastSuccess(now); err != nil {
-		p.api.Log.Error("Failed to save last success time", "backendId", p.backendID, "error", err.Error())
+		p.logger.Error("Failed to save last success time", "backendId", p.backendID, "error", err.Error())
 	}
 
-	// Reset failure counter
-	if err := p.stateStore.ResetFailures(); err != nil {
-		p.api.Log.Error("Failed to reset failure counter", "backendId", p.backendID, "error", err.Error())
+	// Reset failure counter, reporting the recovery of a failing backend
+	if previousFailures, err := p.stateStore.ResetFailures(); err != nil {
+		p.logger.Error("Failed to reset failure counter", "backendId", p.backendID, "error", err.Error())
+	} else if previousFailures > 0 {
+		defer p.reportStatus()
 	}
 
 	// Clear last error on success
 	if err := p.stateStore.SaveLastError(""); err != nil {
-		p.api.Log.Error("Failed to clear last error", "backendId", p.backendID, "error", err.Error())
+		p.logger.Error("Failed to clear last error", "backendId", p.backendID, "error", err.Error())
+	}
+
+	if p.circuitBreaker != nil {
+		p.closeCircuitBreaker()
+	}
+
+	// A catch-up continuing next cycle keeps its phase
+	if state, err := p.stateStore.GetPollState(); err == nil && state.CatchUp != nil {
+		return
+	}
+	p.setPhase(backend.PhasePolling)
+}
+
+// closeCircuitBreaker clears the cool-down state after a successful probe poll
+func (p *Poller) closeCircuitBreaker() {
+	cooldown, err := p.stateStore.GetCooldown()
+	if err != nil {
+		p.logger.Error("Failed to load cool-down state", "backendId", p.backendID, "error", err.Error())
+		return
+	}
+
+	if cooldown.Cycles == 0 {
+		return
+	}
+
+	if err := p.stateStore.ClearCooldown(); err != nil {
+		p.logger.Error("Failed to clear cool-down state", "backendId", p.backendID, "error", err.Error())
+		return
 	}
 
-	p.api.Log.Debug("Poll cycle completed",
+	p.logger.Info("Backend recovered, resuming normal polling",
 		"backendId", p.backendID,
 		"backendName", p.backendName,
-		"totalAlerts", len(response.Alerts),
-		"newAlerts", newCount,
-		"cursor", response.To)
+		"cooldownCycles", cooldown.Cycles)
+}
+
+// openCircuitBreaker starts a cool-down cycle once the failure threshold is reached, or after
+// a failed probe poll at the end of a cool-down. Returns true when all cool-down cycles are
+// used up and the backend should be disabled.
+func (p *Poller) openCircuitBreaker(failureCount int, errMsg string) bool {
+	cooldown, err := p.stateStore.GetCooldown()
+	if err != nil {
+		p.logger.Error("Failed to load cool-down state", "backendId", p.backendID, "error", err.Error())
+		return failureCount >= p.failureThreshold
+	}
+
+	// Not tripped yet and not probing after a cool-down
+	if failureCount < p.failureThreshold && cooldown.Cycles == 0 {
+		return false
+	}
+
+	cycles := cooldown.Cycles + 1
+	if cycles > p.circuitBreaker.MaxCycles() {
+		p.logger.Error("Backend still failing after circuit breaker cool-down cycles",
+			"backendId", p.backendID,
+			"backendName", p.backendName,
+			"cooldownCycles", cooldown.Cycles,
+			"lastError", errMsg)
+		return true
+	}
+
+	until := time.Now().Add(p.circuitBreaker.Cooldown())
+	if err := p.stateStore.SaveCooldown(CooldownState{Until: until, Cycles: cycles}); err != nil {
+		p.logger.Error("Failed to save cool-down state", "backendId", p.backendID, "error", err.Error())
+		return failureCount >= p.failureThreshold
+	}
+	p.setPhase(backend.PhaseCoolingDown)
+
+	p.logger.Warn("Circuit breaker opened, backend cooling down",
+		"backendId", p.backendID,
+		"backendName", p.backendName,
+		"consecutiveFailures", failureCount,
+		"cooldownCycle", cycles,
+		"cooldownUntil", until,
+		"lastError", errMsg)
+	return false
 }
 
 // handlePollError increments failure count and disables backend if threshold exceeded
+// (or starts a cool-down cycle when a circuit breaker is configured). Rate limit errors back
+// off without counting as a failure, authentication errors clear the cached token so the next
+// cycle re-authenticates, and validation errors disable the backend immediately.
 func (p *Poller) handlePollError(err error) {
+	defer p.reportStatus()
+
 	errMsg := err.Error()
 
-	p.api.Log.Error("Poll cycle failed",
-		"backendId", p.backendID,
-		"backendName", p.backendName,
-		"error", errMsg)
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		p.recordError(errMsg)
+		p.backOff(rateLimitErr.RetryAfter, errMsg)
+		return
+	}
 
-	// Save error message
-	if saveErr := p.stateStore.SaveLastError(errMsg); saveErr != nil {
-		p.api.Log.Error("Failed to save last error",
-			"backendId", p.backendID,
-			"error", saveErr.Error())
+	p.logger.Error("Poll cycle failed", p.cycleFields("error", errMsg)...)
+	p.recordError(errMsg)
+
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		p.resetAuth()
 	}
 
 	// Increment failure counter
 	failureCount, incrementErr := p.stateStore.IncrementFailures()
 	if incrementErr != nil {
-		p.api.Log.Error("Failed to increment failure counter",
+		p.logger.Error("Failed to increment failure counter",
 			"backendId", p.backendID,
 			"error", incrementErr.Error())
 		return
 	}
 
-	// Check if backend should be disabled
-	if failureCount >= backend.MaxConsecutiveFailures {
-		p.api.Log.Error("Backend reached max consecutive failures",
+	// A rejected request will be rejected again, so retrying only delays the disable
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		p.logger.Error("Backend configuration rejected by the API",
+			"backendId", p.backendID,
+			"backendName", p.backendName,
+			"error", errMsg)
+		p.disable()
+		return
+	}
+
+	// Backends with a threshold within the margin are warned on their first failure
+	if failureCount == max(p.failureThreshold-backend.FailureWarningMargin, 1) {
+		p.warnFailures(failureCount, errMsg)
+	}
+
+	// Check if backend should be disabled; with a circuit breaker the backend first cools down
+	shouldDisable := failureCount >= p.failureThreshold
+	if p.circuitBreaker != nil {
+		shouldDisable = p.openCircuitBreaker(failureCount, errMsg)
+	}
+
+	if shouldDisable {
+		p.logger.Error("Backend reached max consecutive failures",
 			"backendId", p.backendID,
 			"backendName", p.backendName,
 			"consecutiveFailures", failureCount,
 			"lastError", errMsg)
+		p.disable()
+	}
+}
+
+// warnFailures warns operators that the backend is about to reach its failure threshold, so
+// they can intervene before it is disabled or starts cooling down
+func (p *Poller) warnFailures(failureCount int, errMsg string) {
+	p.logger.Warn("Backend approaching max consecutive failures",
+		"backendId", p.backendID,
+		"backendName", p.backendName,
+		"consecutiveFailures", failureCount,
+		"failureThreshold", p.failureThreshold,
+		"lastError", errMsg)
+
+	if p.warner == nil {
+		return
+	}
+
+	outcome := "disabled"
+	if p.circuitBreaker != nil {
+		outcome = "cooling down"
+	}
+	message := fmt.Sprintf(":warning: Backend **%s** has failed %d consecutive polls and will be %s after %d. Last error: `%s`",
+		p.backendName, failureCount, outcome, p.failureThreshold, errMsg)
+	if err := p.warner.PostAdminMessage(message); err != nil {
+		p.logger.Error("Failed to post failure warning", "backendId", p.backendID, "error", err.Error())
+	}
+}
+
+// disable persists disabling the backend through the disable callback, or stops the poller
+// locally if there is no callback or it fails
+func (p *Poller) disable() {
+	p.setPhase(backend.PhaseDisabled)
+
+	// Call disable callback to persist the configuration change
+	// This will trigger OnConfigurationChange which will stop the backend
+	// Wrap in goroutine to avoid deadlock (callback triggers Stop() on this backend)
+	if p.disableCallback != nil {
+		go func() {
+			if disableErr := p.disableCallback(p.backendID); disableErr != nil {
+				p.logger.Error("Failed to disable backend in configuration",
+					"backendId", p.backendID,
+					"error", disableErr.Error())
 
-		// Call disable callback to persist the configuration change
-		// This will trigger OnConfigurationChange which will stop the backend
-		// Wrap in goroutine to avoid deadlock (callback triggers Stop() on this backend)
-		if p.disableCallback != nil {
-			go func() {
-				if disableErr := p.disableCallback(p.backendID); disableErr != nil {
-					p.api.Log.Error("Failed to disable backend in configuration",
+				// Fallback: stop the poller locally if callback fails
+				if stopErr := p.Stop(context.Background()); stopErr != nil {
+					p.logger.Error("Failed to stop poller after callback failure",
 						"backendId", p.backendID,
-						"error", disableErr.Error())
-
-					// Fallback: stop the poller locally if callback fails
-					if stopErr := p.Stop(); stopErr != nil {
-						p.api.Log.Error("Failed to stop poller after callback failure",
-							"backendId", p.backendID,
-							"error", stopErr.Error())
-					}
+						"error", stopErr.Error())
 				}
-			}()
-		} else {
-			// Fallback: stop the poller if no callback is provided
-			p.api.Log.Warn("No disable callback provided, stopping poller locally",
-				"backendId", p.backendID)
-			if stopErr := p.Stop(); stopErr != nil {
-				p.api.Log.Error("Failed to stop poller",
-					"backendId", p.backendID,
-					"error", stopErr.Error())
 			}
+		}()
+	} else {
+		// Fallback: stop the poller if no callback is provided
+		p.logger.Warn("No disable callback provided, stopping poller locally",
+			"backendId", p.backendID)
+		if stopErr := p.Stop(context.Background()); stopErr != nil {
+			p.logger.Error("Failed to stop poller",
+				"backendId", p.backendID,
+				"error", stopErr.Error())
 		}
 	}
 }
+
+// recordError saves the error of a failed poll cycle as the last error and in the error history
+func (p *Poller) recordError(errMsg string) {
+	if saveErr := p.stateStore.SaveLastError(errMsg); saveErr != nil {
+		p.logger.Error("Failed to save last error",
+			"backendId", p.backendID,
+			"error", saveErr.Error())
+	}
+	if recordErr := p.stateStore.RecordError(errMsg, time.Now()); recordErr != nil {
+		p.logger.Error("Failed to record error history",
+			"backendId", p.backendID,
+			"error", recordErr.Error())
+	}
+}
+
+// backOff skips poll cycles for the wait the API asked for after a rate limit error,
+// using defaultRateLimitBackoff when it gave none and capping it at maxRateLimitBackoff
+func (p *Poller) backOff(retryAfter time.Duration, errMsg string) {
+	wait := retryAfter
+	if wait <= 0 {
+		wait = defaultRateLimitBackoff
+	}
+	if wait > maxRateLimitBackoff {
+		wait = maxRateLimitBackoff
+	}
+
+	until := time.Now().Add(wait)
+	if err := p.stateStore.SaveBackoff(until); err != nil {
+		p.logger.Error("Failed to save back-off state", "backendId", p.backendID, "error", err.Error())
+	}
+	p.setPhase(backend.PhaseCoolingDown)
+
+	p.logger.Warn("Rate limited by the API, backing off",
+		"backendId", p.backendID,
+		"backendName", p.backendName,
+		"backoffUntil", until,
+		"error", errMsg)
+}
+
+// setPhase saves the phase of the polling lifecycle when it changes and reports the change
+func (p *Poller) setPhase(phase backend.Phase) {
+	p.mu.Lock()
+	changed := p.phase != phase
+	p.phase = phase
+	p.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if err := p.stateStore.SavePhase(phase); err != nil {
+		p.logger.Error("Failed to save phase", "backendId", p.backendID, "error", err.Error())
+	}
+	p.reportStatus()
+}
+
+// resetAuth clears the cached authentication token so the next poll cycle authenticates again
+func (p *Poller) resetAuth() {
+	resetter, ok := p.client.(authResetter)
+	if !ok {
+		return
+	}
+	if err := resetter.ResetAuth(); err != nil {
+		p.logger.Error("Failed to clear cached auth token", "backendId", p.backendID, "error", err.Error())
+	}
+}
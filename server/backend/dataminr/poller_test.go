@@ -1,7 +1,11 @@
 package dataminr
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -10,6 +14,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
@@ -119,11 +124,105 @@ func TestPoller_nextWaitInterval(t *testing.T) {
 	})
 }
 
+func TestPoller_nextWaitInterval_StaggeredStart(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("KVGet", mock.Anything).Return(nil, nil)
+	api.On("KVSetWithOptions", "backend_test-backend-id_state", mustMarshalPollState(PollState{Phase: backend.PhaseStarting}), mock.Anything).Return(true, nil).Once()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	pollInterval := 30 * time.Second
+	poller := NewPoller(client, api, "test-backend-id", "Test Backend", pollInterval, nil, nil, NewStateStore(api, "test-backend-id"), nil)
+	poller.SetScheduler(&mockJobScheduler{})
+	poller.SetStartupJitter(5 * time.Second)
+
+	before := time.Now()
+	assert.NoError(t, poller.Start())
+
+	offset := staggerOffset("test-backend-id", pollInterval)
+	require.Greater(t, offset, time.Duration(0), "test backend ID should map to a non-zero offset")
+
+	// An overdue poll waits for the staggered start (offset plus at most the jitter)
+	wait := poller.nextWaitInterval(before, cluster.JobMetadata{})
+	assert.GreaterOrEqual(t, wait, offset)
+	assert.Less(t, wait, offset+5*time.Second+time.Second)
+
+	// A later regular wait is not shortened
+	lastFinished := before.Add(-time.Second)
+	wait = poller.nextWaitInterval(before, cluster.JobMetadata{LastFinished: lastFinished})
+	assert.GreaterOrEqual(t, wait, 29*time.Second)
+
+	// Once the first poll has started, the stagger no longer applies
+	poller.mu.Lock()
+	poller.firstRunAt = time.Time{}
+	poller.mu.Unlock()
+	assert.Equal(t, time.Duration(0), poller.nextWaitInterval(before, cluster.JobMetadata{}))
+}
+
+func TestPoller_NextRunAt(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("KVGet", mock.Anything).Return(nil, nil)
+	api.On("KVSetWithOptions", "backend_test-backend-id_state", mock.Anything, mock.Anything).Return(true, nil)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	poller := NewPoller(client, api, "test-backend-id", "Test Backend", 30*time.Second, nil, nil, NewStateStore(api, "test-backend-id"), nil)
+	poller.SetScheduler(&mockJobScheduler{})
+	assert.True(t, poller.NextRunAt().IsZero(), "Not scheduled before Start")
+
+	require.NoError(t, poller.Start())
+	poller.mu.Lock()
+	poller.firstRunAt = time.Time{}
+	poller.mu.Unlock()
+
+	// The run time follows the wait computed for the scheduler
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	poller.nextWaitInterval(now, cluster.JobMetadata{LastFinished: now.Add(-10 * time.Second)})
+	assert.Equal(t, now.Add(20*time.Second), poller.NextRunAt())
+
+	poller.nextWaitInterval(now, cluster.JobMetadata{LastFinished: now.Add(-time.Minute)})
+	assert.Equal(t, now, poller.NextRunAt(), "An overdue poll runs right away")
+
+	require.NoError(t, poller.Stop(context.Background()))
+	assert.True(t, poller.NextRunAt().IsZero(), "Not scheduled after Stop")
+}
+
+func TestStaggerOffset(t *testing.T) {
+	interval := 60 * time.Second
+
+	// Stable for the same backend and within the interval
+	offset := staggerOffset("backend-a", interval)
+	assert.Equal(t, offset, staggerOffset("backend-a", interval))
+	assert.GreaterOrEqual(t, offset, time.Duration(0))
+	assert.Less(t, offset, interval)
+
+	// Different backends are spread across the interval
+	offsets := make(map[time.Duration]bool)
+	for _, id := range []string{"backend-a", "backend-b", "backend-c", "backend-d"} {
+		offsets[staggerOffset(id, interval)] = true
+	}
+	assert.Greater(t, len(offsets), 1)
+
+	assert.Equal(t, time.Duration(0), staggerOffset("backend-a", 0))
+}
+
 func TestPoller_run_Success(t *testing.T) {
 	api := plugintest.NewAPI(t)
 	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	var history []backend.PollSample
+	api.On("KVSet", "backend_test-id_poll_history", mock.Anything).Run(func(args mock.Arguments) {
+		require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &history))
+	}).Return(nil).Once()
+	// The poll state changes of the cycle are saved with a single write
+	var state PollState
+	api.On("KVSetWithOptions", "backend_test-id_state", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &state))
+	}).Return(true, nil).Once()
 	api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
+	// Existing cursor so the regular poll runs instead of catch-up
+	api.On("KVGet", "backend_test-id_state").Return(mustMarshalPollState(PollState{Cursor: "cursor123"}), nil).Maybe()
 	api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
@@ -157,7 +256,7 @@ func TestPoller_run_Success(t *testing.T) {
 		},
 	}
 	mockDedup := NewMockDeduplicator()
-	processor := NewAlertProcessor(client, "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup)
+	processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup, nil)
 
 	poller := NewPoller(
 		client,
@@ -177,6 +276,493 @@ func TestPoller_run_Success(t *testing.T) {
 	// Verify all operations completed
 	assert.True(t, handlerCalled, "Alert handler should have been called")
 	assert.Equal(t, 1, mockClient.fetchCallCount, "FetchAlerts should have been called once")
+
+	assert.Equal(t, "cursor456", state.Cursor)
+	assert.False(t, state.LastPoll.IsZero())
+	assert.False(t, state.LastSuccess.IsZero())
+	assert.Equal(t, backend.PhasePolling, state.Phase)
+
+	// Verify the poll outcome was added to the history
+	require.Len(t, history, 1)
+	assert.True(t, history[0].Success)
+	assert.Equal(t, 1, history[0].Alerts)
+	assert.Equal(t, 1, history[0].NewAlerts)
+	assert.Empty(t, history[0].Error)
+}
+
+func TestPoller_run_Paused(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+	pause, _ := json.Marshal(PauseState{Until: time.Now().Add(time.Hour)})
+	api.On("KVGet", "backend_test-id_pause").Return(pause, nil).Once()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	mockClient := &mockAPIClient{response: &AlertsResponse{}}
+	poller := NewPoller(
+		client,
+		api,
+		"test-id",
+		"Test Backend",
+		30*time.Second,
+		mockClient,
+		nil,
+		NewStateStore(api, "test-id"),
+		nil,
+	)
+
+	poller.run()
+
+	assert.Equal(t, 0, mockClient.fetchCallCount, "FetchAlerts must not be called while paused")
+}
+
+func TestPoller_run_PausedKeepsHeldAlerts(t *testing.T) {
+	api := &plugintest.API{}
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	kvStore := mockKVStore(api)
+	kvStore["backend_test-id_pause"], _ = json.Marshal(PauseState{Until: time.Now().Add(time.Hour)})
+	kvStore["backend_test-id_quiet_buffer"], _ = json.Marshal([]backend.Alert{{AlertID: "buffered-1", AlertType: "Alert"}})
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	var posted []string
+	poster := &MockPoster{PostAlertFn: func(alert backend.Alert, channelID string) error {
+		posted = append(posted, alert.AlertID)
+		return nil
+	}}
+
+	stateStore := NewStateStore(api, "test-id")
+	gate := NewQuietHoursGate(&backend.QuietHours{Ranges: []backend.TimeRange{{Start: "22:00", End: "06:00"}}}, stateStore)
+	gate.now = func() time.Time { return time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC) }
+	processor := NewAlertProcessor(client, "test-id", "dataminr", "Test Backend", poster, "channel-id", NewMockDeduplicator(), gate)
+	processor.SetPendingStore(stateStore)
+
+	mockClient := &mockAPIClient{response: &AlertsResponse{}}
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, mockClient, processor, stateStore, nil)
+
+	poller.run()
+
+	assert.Equal(t, 0, mockClient.fetchCallCount, "FetchAlerts must not be called while paused")
+	assert.Empty(t, posted, "Alerts held during quiet hours are not posted while paused")
+
+	buffered, err := stateStore.GetBufferedAlerts()
+	require.NoError(t, err)
+	require.Len(t, buffered, 1)
+	assert.Equal(t, "buffered-1", buffered[0].AlertID)
+}
+
+// newPendingTestPoller creates a poller whose processor has a pending queue with a due and a
+// not yet due alert whose post failed once
+func newPendingTestPoller(t *testing.T, paused bool) (*Poller, *StateStore, *mockAPIClient, *[]string) {
+	api := &plugintest.API{}
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	kvStore := mockKVStore(api)
+	if paused {
+		kvStore["backend_test-id_pause"], _ = json.Marshal(PauseState{Until: time.Now().Add(time.Hour)})
+	}
+	kvStore["backend_test-id_pending"], _ = json.Marshal([]PendingAlert{
+		{Alert: backend.Alert{AlertID: "failed-1", AlertType: "Alert"}, ChannelID: "channel-id", Attempts: 1},
+		{Alert: backend.Alert{AlertID: "not-due", AlertType: "Alert"}, ChannelID: "channel-id", Attempts: 1, RetryAt: time.Now().Add(time.Hour)},
+	})
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	var posted []string
+	poster := &MockPoster{PostAlertFn: func(alert backend.Alert, channelID string) error {
+		posted = append(posted, alert.AlertID)
+		return nil
+	}}
+
+	stateStore := NewStateStore(api, "test-id")
+	processor := NewAlertProcessor(client, "test-id", "dataminr", "Test Backend", poster, "channel-id", NewMockDeduplicator(), nil)
+	processor.SetPendingStore(stateStore)
+
+	mockClient := &mockAPIClient{response: &AlertsResponse{}}
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, mockClient, processor, stateStore, nil)
+	return poller, stateStore, mockClient, &posted
+}
+
+func TestPoller_run_RetriesPendingAlerts(t *testing.T) {
+	poller, stateStore, mockClient, posted := newPendingTestPoller(t, false)
+
+	poller.run()
+
+	assert.Equal(t, 1, mockClient.fetchCallCount)
+	assert.Equal(t, []string{"failed-1"}, *posted, "Due alerts whose post failed are retried")
+
+	pending, err := stateStore.GetPendingAlerts()
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "not-due", pending[0].Alert.AlertID)
+}
+
+func TestPoller_run_PausedKeepsPendingAlerts(t *testing.T) {
+	poller, stateStore, mockClient, posted := newPendingTestPoller(t, true)
+
+	poller.run()
+
+	assert.Equal(t, 0, mockClient.fetchCallCount, "FetchAlerts must not be called while paused")
+	assert.Empty(t, *posted, "Queued alerts are not posted while paused")
+
+	// The attempts of the queued alerts are not used up while paused
+	pending, err := stateStore.GetPendingAlerts()
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	for _, alert := range pending {
+		assert.Equal(t, 1, alert.Attempts)
+	}
+}
+
+func TestPoller_run_CatchUp(t *testing.T) {
+	now := time.Now()
+	backlog := []Alert{
+		{AlertID: "old", AlertType: AlertType{Name: "Alert"}, EventTime: now.Add(-48 * time.Hour), Headline: "Old"},
+		{AlertID: "recent", AlertType: AlertType{Name: "Alert"}, EventTime: now.Add(-time.Hour), Headline: "Recent"},
+	}
+
+	tests := []struct {
+		name           string
+		window         time.Duration
+		postHistorical bool
+		expectedPosted []string
+	}{
+		{"historical alerts skipped by default", 24 * time.Hour, false, nil},
+		{"historical alerts within window posted", 24 * time.Hour, true, []string{"recent"}},
+		{"short window posts nothing older", 30 * time.Minute, true, nil},
+		{"long window posts everything", 168 * time.Hour, true, []string{"old", "recent"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := plugintest.NewAPI(t)
+			api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+			kvStore := make(map[string][]byte)
+			var phases []backend.Phase
+			api.On("KVSetWithOptions", "backend_test-id_state", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				kvStore[args.String(0)] = args.Get(1).([]byte)
+				phase := storedPollState(t, kvStore, "test-id").Phase
+				if len(phases) == 0 || phases[len(phases)-1] != phase {
+					phases = append(phases, phase)
+				}
+			}).Return(true, nil)
+			mockKVStoreFrom(api, kvStore)
+			client := pluginapi.NewClient(api, &plugintest.Driver{})
+			stateStore := NewStateStore(api, "test-id")
+
+			// First page returns the backlog, the second page is empty
+			fetcher := &pagedAPIClient{pages: []*AlertsResponse{
+				{Alerts: backlog, To: "cursor-1"},
+				{Alerts: nil, To: "cursor-2"},
+			}}
+
+			var posted []string
+			mockPoster := &MockPoster{
+				PostAlertFn: func(alert backend.Alert, channelID string) error {
+					posted = append(posted, alert.AlertID)
+					return nil
+				},
+			}
+			processor := NewAlertProcessor(client, "test-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+
+			poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, fetcher, processor, stateStore, nil)
+			poller.SetCatchUp(tt.window, tt.postHistorical)
+
+			poller.run()
+
+			assert.Equal(t, tt.expectedPosted, posted)
+			assert.Equal(t, []string{"", "cursor-1"}, fetcher.cursors)
+			assert.Equal(t, []backend.Phase{backend.PhaseCatchingUp, backend.PhasePolling}, phases)
+
+			cursor, err := stateStore.GetCursor()
+			require.NoError(t, err)
+			assert.Equal(t, "cursor-2", cursor)
+
+			failures, err := stateStore.GetFailures()
+			require.NoError(t, err)
+			assert.Equal(t, 0, failures)
+		})
+	}
+}
+
+func TestPoller_run_CursorReset(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", "Cursor was reset, catching up", "backendId", "test-id", "backendName", "Test Backend").Once()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	mockKVStore(api)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	stateStore := NewStateStore(api, "test-id")
+	require.NoError(t, stateStore.SaveCursor("cursor-1"))
+	require.NoError(t, stateStore.RequestCursorReset())
+
+	fetcher := &pagedAPIClient{pages: []*AlertsResponse{
+		{Alerts: []Alert{{AlertID: "old", AlertType: AlertType{Name: "Alert"}, EventTime: time.Now().Add(-time.Hour)}}, To: "cursor-2"},
+		{Alerts: nil, To: "cursor-3"},
+	}}
+
+	var posted []string
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			posted = append(posted, alert.AlertID)
+			return nil
+		},
+	}
+	processor := NewAlertProcessor(client, "test-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, fetcher, processor, stateStore, nil)
+	poller.run()
+
+	// The backlog is skipped by the catch-up instead of being posted
+	assert.Empty(t, posted)
+	assert.Equal(t, []string{"", "cursor-2"}, fetcher.cursors)
+
+	cursor, err := stateStore.GetCursor()
+	require.NoError(t, err)
+	assert.Equal(t, "cursor-3", cursor)
+
+	pending, err := stateStore.IsCursorResetPending()
+	require.NoError(t, err)
+	assert.False(t, pending)
+}
+
+func TestPoller_run_CatchUpResumes(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	startedAt := time.Now().Add(-10 * time.Minute)
+	api.On("LogInfo", "Resuming catch-up", "backendId", "test-id", "backendName", "Test Backend",
+		"startedAt", mock.Anything, "skippedAlerts", 40, "postedAlerts", 0).Once()
+	api.On("LogInfo", "Catch-up completed", "backendId", "test-id", "backendName", "Test Backend",
+		"postedAlerts", 1, "skippedAlerts", 41).Once()
+
+	// A restart interrupted the catch-up after some pages were skipped
+	kvStore := map[string][]byte{"backend_test-id_state": mustMarshalPollState(PollState{
+		Cursor:  "cursor-4",
+		Phase:   backend.PhaseCatchingUp,
+		CatchUp: &backend.CatchUpProgress{StartedAt: startedAt, Skipped: 40},
+	})}
+	mockKVStoreFrom(api, kvStore)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	stateStore := NewStateStore(api, "test-id")
+
+	fetcher := &pagedAPIClient{pages: []*AlertsResponse{
+		{Alerts: []Alert{
+			{AlertID: "backlog", AlertType: AlertType{Name: "Alert"}, EventTime: startedAt.Add(-time.Hour)},
+			{AlertID: "new", AlertType: AlertType{Name: "Alert"}, EventTime: startedAt.Add(time.Minute)},
+		}, To: "cursor-5"},
+		{Alerts: nil, To: "cursor-6"},
+	}}
+
+	var posted []string
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			posted = append(posted, alert.AlertID)
+			return nil
+		},
+	}
+	processor := NewAlertProcessor(client, "test-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, fetcher, processor, stateStore, nil)
+	poller.run()
+
+	// The catch-up continues from the saved cursor, and alerts published since it started are posted
+	assert.Equal(t, []string{"cursor-4", "cursor-5"}, fetcher.cursors)
+	assert.Equal(t, []string{"new"}, posted)
+
+	state := storedPollState(t, kvStore, "test-id")
+	assert.Equal(t, "cursor-6", state.Cursor)
+	assert.Nil(t, state.CatchUp)
+	assert.Equal(t, backend.PhasePolling, state.Phase)
+}
+
+func TestPoller_run_CatchUpPageLimit(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", "Catch-up page limit reached, continuing next cycle", "backendId", "test-id", "backendName", "Test Backend",
+		"pages", maxCatchUpPages, "skippedAlerts", maxCatchUpPages*alertsPageSize, "postedAlerts", 0).Once()
+
+	kvStore := mockKVStore(api)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	stateStore := NewStateStore(api, "test-id")
+
+	pages := make([]*AlertsResponse, maxCatchUpPages+1)
+	for i := range pages {
+		pages[i] = makeAlertPage(fmt.Sprintf("page%d", i+1), alertsPageSize, fmt.Sprintf("cursor-%d", i+1))
+		for j := range pages[i].Alerts {
+			pages[i].Alerts[j].EventTime = time.Now().Add(-time.Hour)
+		}
+	}
+	fetcher := &pagedAPIClient{pages: pages}
+	processor := NewAlertProcessor(client, "test-id", "dataminr", "Test Backend", &MockPoster{}, "test-channel-id", NewMockDeduplicator(), nil)
+
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, fetcher, processor, stateStore, nil)
+	poller.run()
+
+	// The catch-up stays in progress, so the next cycle continues skipping the backlog
+	state := storedPollState(t, kvStore, "test-id")
+	assert.Equal(t, fmt.Sprintf("cursor-%d", maxCatchUpPages), state.Cursor)
+	assert.Equal(t, backend.PhaseCatchingUp, state.Phase)
+	require.NotNil(t, state.CatchUp)
+	assert.Equal(t, maxCatchUpPages*alertsPageSize, state.CatchUp.Skipped)
+	assert.Zero(t, state.CatchUp.Posted)
+	assert.Len(t, fetcher.cursors, maxCatchUpPages)
+}
+
+func TestPoller_run_CatchUpKeepsProgressOnError(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	kvStore := mockKVStore(api)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	stateStore := NewStateStore(api, "test-id")
+
+	fetcher := &failingPageAPIClient{
+		pagedAPIClient: pagedAPIClient{pages: []*AlertsResponse{makeAlertPage("page1", 3, "cursor-1")}},
+		err:            errors.New("API error"),
+	}
+	for i := range fetcher.pages[0].Alerts {
+		fetcher.pages[0].Alerts[i].EventTime = time.Now().Add(-time.Hour)
+	}
+	processor := NewAlertProcessor(client, "test-id", "dataminr", "Test Backend", &MockPoster{}, "test-channel-id", NewMockDeduplicator(), nil)
+
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, fetcher, processor, stateStore, nil)
+	poller.run()
+
+	// The checkpoint of the first page survives the failure
+	state := storedPollState(t, kvStore, "test-id")
+	assert.Equal(t, "cursor-1", state.Cursor)
+	assert.Equal(t, 1, state.Failures)
+	require.NotNil(t, state.CatchUp)
+	assert.Equal(t, 3, state.CatchUp.Skipped)
+}
+
+// makeAlertPage returns a page of count unique Flash alerts with IDs starting at prefix
+func makeAlertPage(prefix string, count int, to string) *AlertsResponse {
+	alerts := make([]Alert, count)
+	for i := range alerts {
+		alerts[i] = Alert{
+			AlertID:   fmt.Sprintf("%s-%d", prefix, i),
+			AlertType: AlertType{Name: "Flash"},
+			EventTime: time.Now(),
+			Headline:  "Test Alert",
+		}
+	}
+	return &AlertsResponse{Alerts: alerts, To: to}
+}
+
+func TestPoller_run_Pagination(t *testing.T) {
+	fullPages := func(count int) []*AlertsResponse {
+		pages := make([]*AlertsResponse, count)
+		for i := range pages {
+			pages[i] = makeAlertPage(fmt.Sprintf("page%d", i+1), alertsPageSize, fmt.Sprintf("cursor-%d", i+1))
+		}
+		return pages
+	}
+
+	tests := []struct {
+		name            string
+		pages           []*AlertsResponse
+		expectedFetches int
+		expectedPosted  int
+		expectedCursor  string
+	}{
+		{
+			name:            "stops at a short page",
+			pages:           append(fullPages(2), makeAlertPage("short", 3, "cursor-3")),
+			expectedFetches: 3,
+			expectedPosted:  2*alertsPageSize + 3,
+			expectedCursor:  "cursor-3",
+		},
+		{
+			name:            "stops at an empty page without a new cursor",
+			pages:           append(fullPages(1), &AlertsResponse{To: "cursor-1"}),
+			expectedFetches: 2,
+			expectedPosted:  alertsPageSize,
+			expectedCursor:  "cursor-1",
+		},
+		{
+			name:            "stops at the page limit",
+			pages:           fullPages(maxPollPages + 2),
+			expectedFetches: maxPollPages,
+			expectedPosted:  maxPollPages * alertsPageSize,
+			expectedCursor:  fmt.Sprintf("cursor-%d", maxPollPages),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := plugintest.NewAPI(t)
+			api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			api.On("LogInfo", "Poll page limit reached, remaining alerts will be fetched next cycle",
+				"backendId", "test-id", "backendName", "Test Backend", "pages", maxPollPages, "totalAlerts", mock.Anything).Maybe()
+
+			kvStore := map[string][]byte{"backend_test-id_state": mustMarshalPollState(PollState{Cursor: "cursor-0"})}
+			mockKVStoreFrom(api, kvStore)
+			client := pluginapi.NewClient(api, &plugintest.Driver{})
+			stateStore := NewStateStore(api, "test-id")
+
+			fetcher := &pagedAPIClient{pages: tt.pages}
+			posted := 0
+			mockPoster := &MockPoster{
+				PostAlertFn: func(alert backend.Alert, channelID string) error {
+					posted++
+					return nil
+				},
+			}
+			processor := NewAlertProcessor(client, "test-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+
+			poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, fetcher, processor, stateStore, nil)
+			poller.run()
+
+			assert.Len(t, fetcher.cursors, tt.expectedFetches)
+			assert.Equal(t, "cursor-0", fetcher.cursors[0])
+			assert.Equal(t, tt.expectedPosted, posted)
+
+			cursor, err := stateStore.GetCursor()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCursor, cursor)
+
+			history, err := stateStore.GetPollHistory()
+			require.NoError(t, err)
+			require.Len(t, history, 1)
+			assert.True(t, history[0].Success)
+			assert.Equal(t, tt.expectedPosted, history[0].Alerts)
+		})
+	}
+}
+
+func TestPoller_run_PaginationKeepsProgressOnError(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	kvStore := map[string][]byte{"backend_test-id_state": mustMarshalPollState(PollState{Cursor: "cursor-0"})}
+	mockKVStoreFrom(api, kvStore)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	stateStore := NewStateStore(api, "test-id")
+
+	fetcher := &failingPageAPIClient{
+		pagedAPIClient: pagedAPIClient{pages: []*AlertsResponse{makeAlertPage("page1", alertsPageSize, "cursor-1")}},
+		err:            errors.New("API error"),
+	}
+	processor := NewAlertProcessor(client, "test-id", "dataminr", "Test Backend", &MockPoster{}, "test-channel-id", NewMockDeduplicator(), nil)
+
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, fetcher, processor, stateStore, nil)
+	poller.run()
+
+	// The first page was posted, so the cursor advanced past it before the failure
+	cursor, err := stateStore.GetCursor()
+	require.NoError(t, err)
+	assert.Equal(t, "cursor-1", cursor)
+
+	failures, err := stateStore.GetFailures()
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures)
 }
 
 func TestPoller_run_FetchError(t *testing.T) {
@@ -187,8 +773,14 @@ func TestPoller_run_FetchError(t *testing.T) {
 	// Mock KV operations - use Maybe() to allow any KV calls
 	failureCount := 0
 	api.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
-	api.On("KVSet", "backend_test-id_failures", mock.Anything).Run(func(args mock.Arguments) {
-		failureCount = 1
+	api.On("KVSetWithOptions", "backend_test-id_state", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		var state PollState
+		require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &state))
+		failureCount = state.Failures
+	}).Return(true, nil)
+	var history []backend.PollSample
+	api.On("KVSet", "backend_test-id_poll_history", mock.Anything).Run(func(args mock.Arguments) {
+		require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &history))
 	}).Return(nil).Once()
 	api.On("KVSet", mock.Anything, mock.Anything).Return(nil).Maybe()
 
@@ -202,7 +794,7 @@ func TestPoller_run_FetchError(t *testing.T) {
 	}
 
 	mockDedup := NewMockDeduplicator()
-	processor := NewAlertProcessor(client, "dataminr", "Test Backend", &MockPoster{}, "test-channel-id", mockDedup)
+	processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", &MockPoster{}, "test-channel-id", mockDedup, nil)
 
 	poller := NewPoller(
 		client,
@@ -221,21 +813,20 @@ func TestPoller_run_FetchError(t *testing.T) {
 
 	// Verify failure was incremented
 	assert.Equal(t, 1, failureCount)
+
+	// Verify the failed poll was added to the history
+	require.Len(t, history, 1)
+	assert.False(t, history[0].Success)
+	assert.Equal(t, "failed to fetch alerts during catch-up: API error", history[0].Error)
 }
 
 func TestPoller_handlePollError_MaxFailures(t *testing.T) {
 	api := plugintest.NewAPI(t)
 	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogWarn", "No disable callback provided, stopping poller locally", "backendId", "test-id").Once()
 
-	// Track failure count manually since we're mocking KV storage
-	currentFailures := backend.MaxConsecutiveFailures - 1
-
-	// Mocks for setting up initial failure count (IncrementFailures calls in the setup loop)
-	for i := 0; i < backend.MaxConsecutiveFailures-1; i++ {
-		api.On("KVGet", "backend_test-id_failures").Return(nil, nil).Once()
-		api.On("KVSet", "backend_test-id_failures", mock.Anything).Return(nil).Once()
-	}
+	kvStore := mockKVStore(api)
 
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
@@ -259,34 +850,88 @@ func TestPoller_handlePollError_MaxFailures(t *testing.T) {
 		assert.NoError(t, err)
 	}
 
-	// Now mock the final handlePollError call (SaveLastError + IncrementFailures)
-	api.On("KVSet", "backend_test-id_last_error", mock.Anything).Return(nil).Once()
-	api.On("KVGet", "backend_test-id_failures").Return(nil, nil).Once()
-	api.On("KVSet", "backend_test-id_failures", mock.Anything).Run(func(args mock.Arguments) {
-		currentFailures++
-	}).Return(nil).Once()
-
 	// Handle one more error to reach threshold
 	poller.handlePollError(errors.New("test error"))
 
 	// Verify failure count reached threshold
-	assert.Equal(t, backend.MaxConsecutiveFailures, currentFailures)
+	state := storedPollState(t, kvStore, "test-id")
+	assert.Equal(t, backend.MaxConsecutiveFailures, state.Failures)
+	assert.Equal(t, "test error", state.LastError)
 
 	// Verify poller was stopped (job should be nil)
 	assert.Nil(t, poller.job, "Poller should have been stopped after max failures")
 }
 
+func TestPoller_handlePollError_ConfiguredThreshold(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogWarn", "Backend approaching max consecutive failures",
+		"backendId", "test-id", "backendName", "Test Backend", "consecutiveFailures", 8, "failureThreshold", 10, "lastError", "test error").Once()
+	kvStore := mockKVStore(api)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	disabled := make(chan string, 1)
+	var warnings []string
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, nil, nil, NewStateStore(api, "test-id"), func(backendID string) error {
+		disabled <- backendID
+		return nil
+	})
+	poller.SetFailureThreshold(10)
+	poller.SetFailureWarning(&MockPoster{PostAdminMessageFn: func(message string) error {
+		warnings = append(warnings, message)
+		return nil
+	}})
+
+	// The warning is sent once, two failures before the threshold
+	for i := 0; i < 9; i++ {
+		poller.handlePollError(errors.New("test error"))
+	}
+	state := storedPollState(t, kvStore, "test-id")
+	assert.Equal(t, 9, state.Failures)
+	assert.NotEqual(t, backend.PhaseDisabled, state.Phase)
+	assert.Equal(t, []string{":warning: Backend **Test Backend** has failed 8 consecutive polls and will be disabled after 10. Last error: `test error`"}, warnings)
+
+	// The default threshold no longer disables the backend; the configured one does
+	poller.handlePollError(errors.New("test error"))
+	assert.Equal(t, backend.PhaseDisabled, storedPollState(t, kvStore, "test-id").Phase)
+	select {
+	case backendID := <-disabled:
+		assert.Equal(t, "test-id", backendID)
+	case <-time.After(time.Second):
+		t.Fatal("backend was not disabled")
+	}
+}
+
+func TestPoller_handlePollError_ThresholdWithinWarningMargin(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogWarn", "Backend approaching max consecutive failures",
+		"backendId", "test-id", "backendName", "Test Backend", "consecutiveFailures", 1, "failureThreshold", 2, "lastError", "test error").Once()
+	mockKVStore(api)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	var warnings []string
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, nil, nil, NewStateStore(api, "test-id"), func(string) error {
+		return nil
+	})
+	poller.SetFailureThreshold(2)
+	poller.SetFailureWarning(&MockPoster{PostAdminMessageFn: func(message string) error {
+		warnings = append(warnings, message)
+		return nil
+	}})
+
+	// The warning is sent on the first failure rather than never
+	poller.handlePollError(errors.New("test error"))
+	assert.Equal(t, []string{":warning: Backend **Test Backend** has failed 1 consecutive polls and will be disabled after 2. Last error: `test error`"}, warnings)
+}
+
 func TestPoller_handlePollError_BelowThreshold(t *testing.T) {
 	api := plugintest.NewAPI(t)
 	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 
-	// Mock single increment (also includes SaveLastError call)
-	failureCount := 0
-	api.On("KVSet", "backend_test-id_last_error", mock.Anything).Return(nil).Once()
-	api.On("KVGet", "backend_test-id_failures").Return(nil, nil).Once()
-	api.On("KVSet", "backend_test-id_failures", mock.Anything).Run(func(args mock.Arguments) {
-		failureCount = 1
-	}).Return(nil).Once()
+	kvStore := mockKVStore(api)
 
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
 
@@ -308,12 +953,148 @@ func TestPoller_handlePollError_BelowThreshold(t *testing.T) {
 	poller.handlePollError(errors.New("test error"))
 
 	// Verify failure count incremented but below threshold
+	failureCount := storedPollState(t, kvStore, "test-id").Failures
 	assert.Equal(t, 1, failureCount)
 	assert.Less(t, failureCount, backend.MaxConsecutiveFailures)
 }
 
+func TestPoller_handlePollError_RateLimit(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogWarn", "Rate limited by the API, backing off",
+		"backendId", "test-id", "backendName", "Test Backend", "backoffUntil", mock.Anything, "error", mock.Anything).Once()
+	api.On("LogDebug", "Skipping poll cycle during rate limit back-off",
+		"backendId", "test-id", "backendName", "Test Backend", "backoffUntil", mock.Anything).Twice()
+	kvStore := mockKVStore(api)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	mockClient := &mockAPIClient{response: &AlertsResponse{}}
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, mockClient, nil, NewStateStore(api, "test-id"), nil)
+
+	before := time.Now()
+	poller.handlePollError(fmt.Errorf("failed to fetch alerts: %w", &RateLimitError{RetryAfter: 2 * time.Minute, Message: "rate limit exceeded (HTTP 429): too many requests"}))
+	backoffUntil, err := poller.stateStore.GetBackoff()
+	require.NoError(t, err)
+	assert.WithinDuration(t, before.Add(2*time.Minute), backoffUntil, time.Second)
+
+	// The error is recorded but the failure counter is left alone
+	assert.Equal(t, PollState{
+		LastError: "failed to fetch alerts: rate limit exceeded (HTTP 429): too many requests",
+		Phase:     backend.PhaseCoolingDown,
+	}, storedPollState(t, kvStore, "test-id"))
+
+	// The next poll cycle is skipped while backing off, also by another node of the cluster
+	poller.run()
+	assert.Equal(t, 0, mockClient.fetchCallCount)
+
+	otherNode := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, mockClient, nil, NewStateStore(api, "test-id"), nil)
+	otherNode.run()
+	assert.Equal(t, 0, mockClient.fetchCallCount)
+}
+
+func TestPoller_backOff(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	mockKVStore(api)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, nil, nil, NewStateStore(api, "test-id"), nil)
+
+	tests := []struct {
+		name       string
+		retryAfter time.Duration
+		expected   time.Duration
+	}{
+		{"default without Retry-After", 0, defaultRateLimitBackoff},
+		{"Retry-After", 5 * time.Minute, 5 * time.Minute},
+		{"capped", 2 * time.Hour, maxRateLimitBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now()
+			poller.backOff(tt.retryAfter, "rate limited")
+			backoffUntil, err := poller.stateStore.GetBackoff()
+			require.NoError(t, err)
+			assert.WithinDuration(t, before.Add(tt.expected), backoffUntil, time.Second)
+		})
+	}
+}
+
+// resettableAPIClient is a fetcher that records when its cached token is cleared
+type resettableAPIClient struct {
+	mockAPIClient
+	resets int
+}
+
+func (m *resettableAPIClient) ResetAuth() error {
+	m.resets++
+	return nil
+}
+
+func TestPoller_handlePollError_AuthError(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	kvStore := mockKVStore(api)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	fetcher := &resettableAPIClient{}
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, fetcher, nil, NewStateStore(api, "test-id"), nil)
+
+	poller.handlePollError(fmt.Errorf("failed to fetch alerts: %w", &AuthError{StatusCode: http.StatusUnauthorized, Message: "authentication failed with HTTP 401"}))
+
+	// The cached token is cleared and the error still counts as a failure
+	assert.Equal(t, 1, fetcher.resets)
+	assert.Equal(t, 1, storedPollState(t, kvStore, "test-id").Failures)
+}
+
+func TestPoller_handlePollError_PayloadTooLarge(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	kvStore := mockKVStore(api)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	fetcher := &resettableAPIClient{}
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, fetcher, nil, NewStateStore(api, "test-id"), nil)
+
+	poller.handlePollError(fmt.Errorf("failed to parse alerts response: %w", &PayloadTooLargeError{Limit: 1024, Message: "response exceeds the size limit of 1024 bytes"}))
+
+	// An oversized response is a regular failure: the cached token is kept
+	assert.Equal(t, 0, fetcher.resets)
+	state := storedPollState(t, kvStore, "test-id")
+	assert.Equal(t, 1, state.Failures)
+	assert.Equal(t, "failed to parse alerts response: response exceeds the size limit of 1024 bytes", state.LastError)
+}
+
+func TestPoller_handlePollError_ValidationError(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	kvStore := mockKVStore(api)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	disabled := make(chan string, 1)
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, nil, nil, NewStateStore(api, "test-id"), func(backendID string) error {
+		disabled <- backendID
+		return nil
+	})
+
+	// The first rejected request disables the backend without waiting for the failure threshold
+	poller.handlePollError(fmt.Errorf("failed to fetch alerts: %w", &ValidationError{StatusCode: http.StatusBadRequest, Message: "bad request (HTTP 400): invalid request parameters"}))
+
+	select {
+	case backendID := <-disabled:
+		assert.Equal(t, "test-id", backendID)
+	case <-time.After(time.Second):
+		t.Fatal("backend was not disabled")
+	}
+
+	state := storedPollState(t, kvStore, "test-id")
+	assert.Equal(t, 1, state.Failures)
+	assert.Equal(t, backend.PhaseDisabled, state.Phase)
+}
+
 func TestPoller_Start_WithExistingCursor(t *testing.T) {
 	api := plugintest.NewAPI(t)
+	api.On("KVGet", "backend_test-id_state").Return(mustMarshalPollState(PollState{Cursor: "cursor-1"}), nil).Once()
+	api.On("KVSetWithOptions", "backend_test-id_state", mustMarshalPollState(PollState{Cursor: "cursor-1", Phase: backend.PhaseStarting}), mock.Anything).Return(true, nil).Once()
 	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 
 	client := pluginapi.NewClient(api, &plugintest.Driver{})
@@ -335,7 +1116,7 @@ type mockAPIClient struct {
 	fetchCallCount int
 }
 
-func (m *mockAPIClient) FetchAlerts(cursor string) (*AlertsResponse, error) {
+func (m *mockAPIClient) FetchAlerts(_ context.Context, cursor string) (*AlertsResponse, error) {
 	m.fetchCallCount++
 	if m.err != nil {
 		return nil, m.err
@@ -343,6 +1124,34 @@ func (m *mockAPIClient) FetchAlerts(cursor string) (*AlertsResponse, error) {
 	return m.response, nil
 }
 
+// failingPageAPIClient returns its pages and then fails every further request
+type failingPageAPIClient struct {
+	pagedAPIClient
+	err error
+}
+
+func (m *failingPageAPIClient) FetchAlerts(ctx context.Context, cursor string) (*AlertsResponse, error) {
+	if len(m.cursors) >= len(m.pages) {
+		m.cursors = append(m.cursors, cursor)
+		return nil, m.err
+	}
+	return m.pagedAPIClient.FetchAlerts(ctx, cursor)
+}
+
+// pagedAPIClient returns a sequence of responses and records the requested cursors
+type pagedAPIClient struct {
+	pages   []*AlertsResponse
+	cursors []string
+}
+
+func (m *pagedAPIClient) FetchAlerts(_ context.Context, cursor string) (*AlertsResponse, error) {
+	m.cursors = append(m.cursors, cursor)
+	if len(m.cursors) > len(m.pages) {
+		return &AlertsResponse{To: cursor}, nil
+	}
+	return m.pages[len(m.cursors)-1], nil
+}
+
 // mockJobScheduler is a mock for the JobScheduler interface
 type mockJobScheduler struct {
 	scheduleCalled bool
@@ -364,3 +1173,256 @@ func (m *mockJob) Close() error {
 	m.closed = true
 	return nil
 }
+
+func newCircuitBreakerPoller(api *plugintest.API, disableCallback backend.DisableCallback) *Poller {
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	poller := NewPoller(
+		client,
+		api,
+		"test-id",
+		"Test Backend",
+		30*time.Second,
+		nil,
+		nil,
+		NewStateStore(api, "test-id"),
+		disableCallback,
+	)
+	poller.SetCircuitBreaker(&backend.CircuitBreakerSettings{CooldownMinutes: 10, MaxCooldownCycles: 2})
+	return poller
+}
+
+func TestPoller_handlePollError_CircuitBreakerOpens(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+	api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+
+	kvStore := mockKVStore(api)
+	kvStore["backend_test-id_state"] = mustMarshalPollState(PollState{Failures: backend.MaxConsecutiveFailures - 1})
+
+	disabled := false
+	poller := newCircuitBreakerPoller(api, func(string) error {
+		disabled = true
+		return nil
+	})
+
+	before := time.Now()
+	poller.handlePollError(errors.New("test error"))
+
+	var saved CooldownState
+	require.NoError(t, json.Unmarshal(kvStore["backend_test-id_cooldown"], &saved))
+	assert.Equal(t, 1, saved.Cycles)
+	assert.Equal(t, backend.PhaseCoolingDown, storedPollState(t, kvStore, "test-id").Phase)
+	assert.WithinDuration(t, before.Add(10*time.Minute), saved.Until, 5*time.Second)
+	assert.False(t, disabled, "backend must not be disabled while cool-down cycles remain")
+}
+
+func TestPoller_handlePollError_CircuitBreakerExhausted(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Twice()
+
+	// A failed probe after the last cool-down cycle
+	cooldown, _ := json.Marshal(CooldownState{Until: time.Now().Add(-time.Minute), Cycles: 2})
+	kvStore := mockKVStore(api)
+	kvStore["backend_test-id_state"] = mustMarshalPollState(PollState{Failures: backend.MaxConsecutiveFailures})
+	kvStore["backend_test-id_cooldown"] = cooldown
+
+	disabled := make(chan string, 1)
+	poller := newCircuitBreakerPoller(api, func(id string) error {
+		disabled <- id
+		return nil
+	})
+
+	poller.handlePollError(errors.New("test error"))
+
+	select {
+	case id := <-disabled:
+		assert.Equal(t, "test-id", id)
+	case <-time.After(time.Second):
+		t.Fatal("backend should be disabled after the last cool-down cycle")
+	}
+	assert.Equal(t, backend.PhaseDisabled, storedPollState(t, kvStore, "test-id").Phase)
+}
+
+func TestPoller_run_CoolingDown(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+	cooldown, _ := json.Marshal(CooldownState{Until: time.Now().Add(time.Minute), Cycles: 1})
+	api.On("KVGet", "backend_test-id_pause").Return(nil, nil).Once()
+	api.On("KVGet", "backend_test-id_backoff").Return(nil, nil).Once()
+	api.On("KVGet", "backend_test-id_cooldown").Return(cooldown, nil).Once()
+
+	mockClient := &mockAPIClient{response: &AlertsResponse{}}
+	poller := newCircuitBreakerPoller(api, nil)
+	poller.client = mockClient
+
+	poller.run()
+
+	assert.Equal(t, 0, mockClient.fetchCallCount, "FetchAlerts must not be called during a cool-down")
+}
+
+func TestPoller_recordSuccess_ClosesCircuitBreaker(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+	cooldown, _ := json.Marshal(CooldownState{Until: time.Now().Add(-time.Minute), Cycles: 1})
+	kvStore := mockKVStore(api)
+	kvStore["backend_test-id_state"] = mustMarshalPollState(PollState{Failures: 3, LastError: "test error", Phase: backend.PhaseCoolingDown})
+	kvStore["backend_test-id_cooldown"] = cooldown
+
+	poller := newCircuitBreakerPoller(api, nil)
+	poller.recordSuccess()
+
+	state := storedPollState(t, kvStore, "test-id")
+	assert.Zero(t, state.Failures)
+	assert.Empty(t, state.LastError)
+	assert.False(t, state.LastSuccess.IsZero())
+	assert.Equal(t, backend.PhasePolling, state.Phase)
+	assert.NotContains(t, kvStore, "backend_test-id_cooldown")
+}
+
+func TestPoller_run_InterruptedByShutdown(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", "Poll cycle interrupted by shutdown, cursor not advanced",
+		"backendId", "test-id", "backendName", "Test Backend").Once()
+	kvStore := mockKVStore(api)
+	kvStore["backend_test-id_state"] = mustMarshalPollState(PollState{Cursor: "cursor123"})
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	mockClient := &mockAPIClient{response: &AlertsResponse{
+		Alerts: []Alert{
+			{AlertID: "alert-1", AlertType: AlertType{Name: "Flash"}, Headline: "Test Alert 1"},
+			{AlertID: "alert-2", AlertType: AlertType{Name: "Flash"}, Headline: "Test Alert 2"},
+		},
+		To: "cursor456",
+	}}
+
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, mockClient, nil, NewStateStore(api, "test-id"), nil)
+	poller.ctx, poller.cancel = context.WithCancel(context.Background())
+	defer poller.cancel()
+
+	// Stop gives up waiting while the first alert is posted
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			poller.cancelRun()
+			return nil
+		},
+	}
+	poller.processor = NewAlertProcessor(client, "test-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+
+	poller.run()
+
+	state := storedPollState(t, kvStore, "test-id")
+	assert.Equal(t, "cursor123", state.Cursor, "the cursor must not advance past unposted alerts")
+	assert.Zero(t, state.Failures, "an interrupted poll is not a failure")
+}
+
+// blockingJob is a Job whose Close waits for the in-flight poll cycle to notice cancellation
+type blockingJob struct {
+	ctx context.Context
+}
+
+func (j *blockingJob) Close() error {
+	<-j.ctx.Done()
+	return nil
+}
+
+func TestPoller_Stop(t *testing.T) {
+	t.Run("waits for an in-flight poll cycle to finish", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("LogInfo", "Poller stopped", "backendId", "test-id", "backendName", "Test Backend").Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, nil, nil, nil, nil)
+		poller.ctx, poller.cancel = context.WithCancel(context.Background())
+		job := &mockJob{}
+		poller.job = job
+
+		require.NoError(t, poller.Stop(context.Background()))
+		assert.True(t, job.closed)
+		assert.ErrorIs(t, poller.ctx.Err(), context.Canceled)
+	})
+
+	t.Run("cancels the poll cycle after the drain timeout", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("LogWarn", "Poll cycle still running after drain timeout, cancelling it",
+			"backendId", "test-id", "backendName", "Test Backend", "drainTimeout", 10*time.Millisecond).Once()
+		api.On("LogInfo", "Poller stopped", "backendId", "test-id", "backendName", "Test Backend").Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, nil, nil, nil, nil)
+		poller.drainTimeout = 10 * time.Millisecond
+		poller.ctx, poller.cancel = context.WithCancel(context.Background())
+		poller.job = &blockingJob{ctx: poller.ctx}
+
+		require.NoError(t, poller.Stop(context.Background()))
+		assert.Nil(t, poller.job)
+	})
+
+	t.Run("cancels the poll cycle and returns once the context is done", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("LogWarn", "Poll cycle still running when stopping was cancelled, cancelling it",
+			"backendId", "test-id", "backendName", "Test Backend").Once()
+		api.On("LogError", "Failed to close cluster job", "backendId", "test-id", "error", mock.Anything).Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, nil, nil, nil, nil)
+		poller.ctx, poller.cancel = context.WithCancel(context.Background())
+
+		// The job never closes, e.g. because the poll cycle ignores cancellation
+		stuck := &blockingJob{ctx: context.Background()}
+		poller.job = stuck
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := poller.Stop(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, poller.ctx.Err(), context.Canceled)
+		assert.Nil(t, poller.job)
+	})
+
+	t.Run("reports when a job it gave up on has closed", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogError", "Failed to close cluster job", "backendId", "test-id", "error", mock.Anything).Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, nil, nil, nil, nil)
+		poller.ctx, poller.cancel = context.WithCancel(context.Background())
+		select {
+		case <-poller.Stopped():
+		default:
+			t.Fatal("a poller that was never started is stopped")
+		}
+
+		closeJob, release := context.WithCancel(context.Background())
+		poller.job = &blockingJob{ctx: closeJob}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.Error(t, poller.Stop(ctx))
+
+		stopped := poller.Stopped()
+		select {
+		case <-stopped:
+			t.Fatal("the job has not closed yet")
+		default:
+		}
+
+		release()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the job closed but the poller was not reported stopped")
+		}
+	})
+}
+
+func TestPoller_CycleFields(t *testing.T) {
+	p := &Poller{backendID: "backend-1", backendName: "Weather Watch"}
+	assert.Equal(t, []any{"backendId", "backend-1", "backendName", "Weather Watch", "error", "timeout"}, p.cycleFields("error", "timeout"))
+
+	p.correlationID = "correlation-1"
+	assert.Equal(t, []any{"backendId", "backend-1", "backendName", "Weather Watch", "correlationId", "correlation-1"}, p.cycleFields())
+}
@@ -1,58 +1,1040 @@
 package dataminr
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/mattermost/mattermost/server/public/pluginapi"
 
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
 
-// AlertProcessor orchestrates alert normalization and deduplication
+// maxSummarizedHeadlines is how many headlines are listed in an oversized batch summary
+const maxSummarizedHeadlines = 10
+
+// summaryBudget bounds how long summarizing a batch may hold up posting it; alerts not
+// summarized by then are posted without a summary
+const summaryBudget = 20 * time.Second
+
+// translationBudget bounds how long translating a batch may hold up posting it; alerts not
+// translated by then are posted untranslated
+const translationBudget = 20 * time.Second
+
+// maxEnrichmentRequests caps the summarizer or translator requests in flight for a batch
+const maxEnrichmentRequests = 4
+
+// dryRunDedupPrefix namespaces the alerts recorded by dry-run backends apart from live ones
+const dryRunDedupPrefix = "dryrun:"
+
+// pendingPost is an alert waiting to be posted to a channel
+type pendingPost struct {
+	alert     backend.Alert
+	channelID string
+
+	// checkpointed marks alerts tracked in the pending queue
+	checkpointed bool
+
+	// subscribed marks copies posted to channels subscribed to the backend, which are
+	// not counted as new alerts
+	subscribed bool
+
+	// retry marks alerts posted from the pending queue, which were counted as new alerts
+	// by the poll cycle that first processed them
+	retry bool
+}
+
+// AlertProcessor runs each batch of fetched alerts through a pipeline of stages:
+// normalize → dedup → filter → enrich → route → post
 type AlertProcessor struct {
 	api          *pluginapi.Client
+	logger       backend.Logger
+	backendID    string
 	backendType  string
 	backendName  string
 	poster       backend.AlertPoster
 	channelID    string
 	deduplicator backend.Deduplicator
+	quietHours   *QuietHoursGate
+
+	// routes send alerts to other channels than channelID depending on the time of day
+	routes []backend.ChannelRoute
+
+	// concurrency is the number of channels posted to in parallel. Alerts to the same
+	// channel are always posted one at a time, in order.
+	concurrency int
+
+	// maxBatch caps the alerts posted individually per batch; the rest are summarized
+	maxBatch int
+
+	// attachRawPayload passes the original alert JSON to the poster
+	attachRawPayload bool
+
+	// dryRun logs the alerts and summaries that would have been posted instead of posting them
+	dryRun bool
+
+	// pending checkpoints alerts until they are posted (nil disables checkpointing)
+	pending *pendingQueue
+
+	// subscriptions lists the channels that opted in to this backend's alerts (nil disables fan-out)
+	subscriptions *backend.SubscriptionStore
+
+	// topicMutes lists the topics muted per channel (nil disables muting)
+	topicMutes *backend.TopicMuteStore
+
+	// alertListMutes lists the alert lists muted per channel and for the backend (nil disables muting)
+	alertListMutes *backend.AlertListMuteStore
+
+	// summarizer adds a summary to alerts whose text is at least summaryMinLength characters
+	summarizer       backend.Summarizer
+	summaryMinLength int
+
+	// summaryBudget and translationBudget bound the time spent summarizing and translating
+	// a batch; replaced in tests
+	summaryBudget     time.Duration
+	translationBudget time.Duration
+
+	// translator machine translates alerts into the languages set per channel in translation
+	translator  backend.Translator
+	translation backend.TranslatorSettings
+
+	// pipeline holds the stages each batch of alerts is processed by
+	pipeline pipeline
+
+	// now returns the current time; replaced in tests
+	now func() time.Time
 }
 
 // NewAlertProcessor creates a new alert processor
-func NewAlertProcessor(api *pluginapi.Client, backendType, backendName string, poster backend.AlertPoster, channelID string, deduplicator backend.Deduplicator) *AlertProcessor {
-	return &AlertProcessor{
+func NewAlertProcessor(api *pluginapi.Client, backendID, backendType, backendName string, poster backend.AlertPoster, channelID string, deduplicator backend.Deduplicator, quietHours *QuietHoursGate) *AlertProcessor {
+	p := &AlertProcessor{
 		api:          api,
+		logger:       &api.Log,
+		backendID:    backendID,
 		backendType:  backendType,
 		backendName:  backendName,
 		poster:       poster,
 		channelID:    channelID,
 		deduplicator: deduplicator,
+		quietHours:   quietHours,
+		concurrency:  backend.DefaultPostConcurrency,
+		maxBatch:     backend.DefaultMaxAlertsPerBatch,
+		summarizer:   backend.NoopSummarizer{},
+		translator:   backend.NoopTranslator{},
+		now:          time.Now,
+
+		summaryBudget:     summaryBudget,
+		translationBudget: translationBudget,
+	}
+	p.addBuiltinStages()
+	return p
+}
+
+// SetLogger sets the logger for the backend's alert processing
+func (p *AlertProcessor) SetLogger(logger backend.Logger) {
+	p.logger = logger
+}
+
+// SetTarget changes the backend name shown in alerts and the channel alerts are posted to.
+// It must not be called while a poll cycle is processing alerts.
+func (p *AlertProcessor) SetTarget(backendName, channelID string) {
+	p.backendName = backendName
+	p.channelID = channelID
+}
+
+// SetRoutes configures the time-based channel routes alerts are posted to instead of the
+// backend's channel. It must not be called while a poll cycle is processing alerts.
+func (p *AlertProcessor) SetRoutes(routes []backend.ChannelRoute) {
+	p.routes = routes
+}
+
+// SetBatchLimits configures the number of channels posted to in parallel and the per-batch
+// cap. Non-positive values use the defaults.
+func (p *AlertProcessor) SetBatchLimits(concurrency, maxBatch int) {
+	p.concurrency = backend.DefaultPostConcurrency
+	if concurrency > 0 {
+		p.concurrency = concurrency
+	}
+	p.maxBatch = backend.DefaultMaxAlertsPerBatch
+	if maxBatch > 0 {
+		p.maxBatch = maxBatch
 	}
 }
 
+// SetQuietHours replaces the quiet hours schedule, buffering alerts in the state store. Alerts
+// buffered under the old schedule are posted once the new one isn't in effect, including when
+// quiet hours are removed. It must not be called while a poll cycle is processing alerts.
+func (p *AlertProcessor) SetQuietHours(schedule *backend.QuietHours, stateStore *StateStore) {
+	if p.quietHours == nil {
+		p.quietHours = NewQuietHoursGate(schedule, stateStore)
+		return
+	}
+	p.quietHours.schedule = schedule
+}
+
+// SetAttachRawPayload configures whether the original alert JSON is passed along
+// with each alert so the poster can attach it as a thread reply.
+func (p *AlertProcessor) SetAttachRawPayload(enabled bool) {
+	p.attachRawPayload = enabled
+}
+
+// SetDryRun configures whether alerts are only logged instead of posted
+func (p *AlertProcessor) SetDryRun(enabled bool) {
+	p.dryRun = enabled
+}
+
+// SetPendingStore enables checkpointing: alerts are stored before posting and removed once
+// posted, so alerts that fail to post or are interrupted mid-batch are retried on the next
+// poll cycle, even after a restart.
+func (p *AlertProcessor) SetPendingStore(stateStore *StateStore) {
+	p.pending = newPendingQueue(stateStore)
+}
+
+// SetSubscriptions enables posting alerts to the channels subscribed to the backend,
+// in addition to the configured channel.
+func (p *AlertProcessor) SetSubscriptions(subscriptions *backend.SubscriptionStore) {
+	p.subscriptions = subscriptions
+}
+
+// SetAlertListMutes enables skipping alerts whose alert lists are muted for the backend or in
+// the channel they'd be posted to
+func (p *AlertProcessor) SetAlertListMutes(alertListMutes *backend.AlertListMuteStore) {
+	p.alertListMutes = alertListMutes
+}
+
+// SetTopicMutes enables skipping alerts whose topics are muted in the channel they'd be posted to
+func (p *AlertProcessor) SetTopicMutes(topicMutes *backend.TopicMuteStore) {
+	p.topicMutes = topicMutes
+}
+
+// SetSummarizer enables summarizing the source or translated text of alerts with at least
+// minLength characters. The summary is shown at the top of the alert post.
+func (p *AlertProcessor) SetSummarizer(summarizer backend.Summarizer, minLength int) {
+	p.summarizer = summarizer
+	p.summaryMinLength = minLength
+}
+
+// SetTranslator enables machine translating alerts without a Dataminr translation into the
+// language configured for the channel they are posted in
+func (p *AlertProcessor) SetTranslator(translator backend.Translator, settings backend.TranslatorSettings) {
+	p.translator = translator
+	p.translation = settings
+}
+
 // ProcessAlerts processes a batch of Dataminr alerts
-// Returns the number of new alerts processed (after deduplication)
-func (p *AlertProcessor) ProcessAlerts(alerts []Alert) (int, error) {
-	newCount := 0
+// Returns the number of new alerts processed (after deduplication).
+// If the context is cancelled before every alert is posted, the context error is returned.
+// The remaining alerts stay in the pending queue when checkpointing is enabled; otherwise
+// they are forgotten by the deduplicator so they are processed again when re-fetched.
+func (p *AlertProcessor) ProcessAlerts(ctx context.Context, alerts []Alert) (int, error) {
+	// Alerts left unposted by earlier poll cycles are posted with the batch
+	batch := &AlertBatch{fetched: alerts, retried: p.duePendingPosts()}
+	p.pipeline.run(ctx, batch, PhaseNormalize, PhasePost, p.logger)
+
+	if len(batch.unposted) > 0 {
+		return batch.processed, ctx.Err()
+	}
+	return batch.processed, nil
+}
+
+// AddStage adds a stage to the alert pipeline, running it after the stages already added
+// to its phase. It must not be called while a poll cycle is processing alerts.
+func (p *AlertProcessor) AddStage(phase StagePhase, name string, process StageFunc) {
+	p.pipeline.add(phase, name, process)
+}
+
+// addBuiltinStages adds the stages every backend runs alerts through
+func (p *AlertProcessor) addBuiltinStages() {
+	p.AddStage(PhaseNormalize, "normalize", p.normalizeStage)
+	p.AddStage(PhaseDedup, "deduplicate", p.dedupStage)
+	p.AddStage(PhaseFilter, "quiet-hours", p.quietHoursStage)
+	p.AddStage(PhaseFilter, "batch-limit", p.batchLimitStage)
+	p.AddStage(PhaseEnrich, "summarize", p.summarizeStage)
+	p.AddStage(PhaseRoute, "subscriptions", p.subscriptionsStage)
+	p.AddStage(PhaseRoute, "topic-mutes", p.topicMutesStage)
+	p.AddStage(PhaseRoute, "alert-list-mutes", p.alertListMutesStage)
+	p.AddStage(PhaseRoute, "translate", p.translateStage)
+	p.AddStage(PhasePost, "checkpoint", p.checkpointStage)
+	p.AddStage(PhasePost, "post", p.postStage)
+}
+
+// normalizeStage converts the fetched alerts into backend alerts for the channel routed to
+func (p *AlertProcessor) normalizeStage(_ context.Context, batch *AlertBatch) error {
+	channelID := p.targetChannel()
+	for _, alert := range batch.fetched {
+		normalized := NormalizeAlert(alert, p.backendName)
+		normalized.BackendID = p.backendID
+		if p.attachRawPayload {
+			normalized.RawPayload = string(alert.Raw)
+		}
+		batch.posts = append(batch.posts, pendingPost{alert: *normalized, channelID: channelID})
+	}
+	batch.fetched = nil
+	return nil
+}
+
+// targetChannel returns the channel alerts are posted to at this time: the channel of the
+// first active route, or the backend's channel
+func (p *AlertProcessor) targetChannel() string {
+	return backend.RouteChannel(p.routes, p.channelID, p.now())
+}
+
+// dedupStage drops alerts that were already processed, replying to the post of updated ones
+// with their changes. In dry-run mode alerts are recorded under a separate namespace, so a
+// dry-run backend doesn't claim alerts from live backends of the same type.
+func (p *AlertProcessor) dedupStage(_ context.Context, batch *AlertBatch) error {
+	posts := batch.posts[:0]
+	for _, post := range batch.posts {
+		if p.dryRun && p.deduplicator.IsSeen(p.backendType, post.alert.AlertID) {
+			p.logger.Debug("Skipping duplicate alert", "backendType", p.backendType, "alertId", post.alert.AlertID)
+			continue
+		}
 
-	for _, alert := range alerts {
 		// Atomically check and record alert (prevents race conditions)
-		isNew := p.deduplicator.RecordAlert(p.backendType, alert.AlertID)
-		if !isNew {
-			p.api.Log.Debug("Skipping duplicate alert", "backendType", p.backendType, "alertId", alert.AlertID)
+		if !p.deduplicator.RecordAlert(p.dedupNamespace(), post.alert.AlertID) {
+			p.logger.Debug("Skipping duplicate alert", "backendType", p.backendType, "alertId", post.alert.AlertID)
+			p.postAlertUpdate(post.alert)
 			continue
 		}
+		posts = append(posts, post)
+	}
+	batch.posts = posts
+	return nil
+}
 
-		// Normalize to backend.Alert
-		normalized := NormalizeAlert(alert, p.backendName)
+// dedupNamespace returns the namespace alerts are recorded under in the deduplicator
+func (p *AlertProcessor) dedupNamespace() string {
+	if p.dryRun {
+		return dryRunDedupPrefix + p.backendType
+	}
+	return p.backendType
+}
+
+// postAlertUpdate summarizes the changes of an alert fetched again under the same alert ID
+// in the thread of its post
+func (p *AlertProcessor) postAlertUpdate(alert backend.Alert) {
+	if p.dryRun {
+		return
+	}
+	if err := p.poster.PostAlertUpdate(alert); err != nil {
+		p.logger.Warn("Failed to post alert update", "alertId", alert.AlertID, "error", err.Error())
+	}
+}
+
+// quietHoursStage holds back non-Flash alerts during quiet hours. Alerts that can't be
+// buffered are posted immediately.
+func (p *AlertProcessor) quietHoursStage(_ context.Context, batch *AlertBatch) error {
+	posts := batch.posts[:0]
+	for _, post := range batch.posts {
+		if p.quietHours.ShouldBuffer(post.alert) {
+			if err := p.quietHours.Buffer(post.alert); err != nil {
+				p.logger.Error("Failed to buffer alert during quiet hours, posting immediately", "alertId", post.alert.AlertID, "error", err.Error())
+			} else {
+				p.logger.Debug("Buffered alert during quiet hours", "alertId", post.alert.AlertID)
+				batch.processed++
+				continue
+			}
+		}
+		posts = append(posts, post)
+	}
+	batch.posts = posts
+	return nil
+}
+
+// batchLimitStage orders alerts so Flash alerts are posted first, summarizing the least
+// urgent alerts of an oversized batch instead of posting each one. Alerts whose summary
+// failed to post are posted individually after the others, so they are checkpointed and
+// retried like any alert that failed to post.
+func (p *AlertProcessor) batchLimitStage(_ context.Context, batch *AlertBatch) error {
+	posts, overflow := prioritize(batch.posts, p.maxBatch)
+	if len(overflow) > 0 {
+		unsummarized := p.postSummary(overflow)
+		batch.processed += len(overflow) - len(unsummarized)
+		posts = append(posts, unsummarized...)
+	}
+	batch.posts = posts
+	return nil
+}
+
+// summarizeStage adds a summary to alerts with long text. The summaries are requested in
+// parallel within the summary budget; alerts not summarized by then are posted without one.
+func (p *AlertProcessor) summarizeStage(ctx context.Context, batch *AlertBatch) error {
+	var long []int
+	for i := range batch.posts {
+		if p.summaryText(batch.posts[i].alert) != "" {
+			long = append(long, i)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.summaryBudget)
+	defer cancel()
+
+	skipped := forEachConcurrently(ctx, len(long), maxEnrichmentRequests, func(i int) {
+		p.summarize(ctx, &batch.posts[long[i]].alert)
+	})
+	if skipped > 0 {
+		p.logger.Warn("Summary budget exhausted, posting the remaining alerts without a summary", "skipped", skipped)
+	}
+	return nil
+}
+
+// subscriptionsStage adds a copy of the alerts for the channels subscribed to the backend
+func (p *AlertProcessor) subscriptionsStage(_ context.Context, batch *AlertBatch) error {
+	batch.posts = p.withSubscribers(batch.posts)
+	return nil
+}
+
+// topicMutesStage drops the alerts whose topics are muted in the channel they'd be posted to
+func (p *AlertProcessor) topicMutesStage(_ context.Context, batch *AlertBatch) error {
+	var muted int
+	batch.posts, muted = p.withoutMutedTopics(batch.posts)
+	batch.processed += muted
+	return nil
+}
+
+// alertListMutesStage drops the alerts whose alert lists are muted for the backend or in the
+// channel they'd be posted to
+func (p *AlertProcessor) alertListMutesStage(_ context.Context, batch *AlertBatch) error {
+	var muted int
+	batch.posts, muted = p.withoutMutedAlertLists(batch.posts)
+	batch.processed += muted
+	return nil
+}
+
+// translateStage machine translates alerts into the language of their channel
+func (p *AlertProcessor) translateStage(ctx context.Context, batch *AlertBatch) error {
+	p.translate(ctx, batch.posts)
+	return nil
+}
+
+// checkpointStage stores the alerts in the pending queue before they are posted
+func (p *AlertProcessor) checkpointStage(_ context.Context, batch *AlertBatch) error {
+	p.checkpoint(batch.posts)
+	return nil
+}
+
+// postStage posts the alerts, together with the alerts retried from the pending queue in
+// priority order. Alerts left unposted because the context was cancelled are forgotten by
+// the deduplicator, unless they are checkpointed, so they are processed again.
+func (p *AlertProcessor) postStage(ctx context.Context, batch *AlertBatch) error {
+	posts := batch.posts
+	if len(batch.retried) > 0 {
+		// Order the retried alerts with the batch, so new Flash alerts don't wait behind them
+		posts = sortByPriority(append(batch.retried, batch.posts...))
+		batch.retried = nil
+	}
+
+	posted, unposted := p.postAll(ctx, posts)
+	batch.processed += posted
+	batch.posts = nil
+	batch.unposted = unposted
 
-		// Post alert to Mattermost channel
-		if err := p.poster.PostAlert(*normalized, p.channelID); err != nil {
-			p.api.Log.Error("Failed to post alert", "alertId", alert.AlertID, "channelId", p.channelID, "error", err.Error())
+	for _, item := range unposted {
+		// Forgetting an alert re-posts it everywhere, so only do it when the
+		// configured channel missed it
+		if !item.checkpointed && !item.subscribed {
+			p.deduplicator.ForgetAlert(p.dedupNamespace(), item.alert.AlertID)
+		}
+	}
+	return nil
+}
+
+// summarize adds a summary of the alert's translated text, or its source text when there is
+// no translation, if the text is long enough. Summarizer failures are logged and the alert
+// is posted without a summary.
+func (p *AlertProcessor) summarize(ctx context.Context, alert *backend.Alert) {
+	text := p.summaryText(*alert)
+	if text == "" {
+		return
+	}
+
+	summary, err := p.summarizer.Summarize(ctx, text)
+	if err != nil {
+		p.logger.Warn("Failed to summarize alert text, posting without a summary", "alertId", alert.AlertID, "error", err.Error())
+		return
+	}
+	alert.Summary = summary
+}
+
+// summaryText returns the text of an alert to summarize, or an empty string if it is too short
+func (p *AlertProcessor) summaryText(alert backend.Alert) string {
+	text := alert.TranslatedText
+	if text == "" {
+		text = alert.SourceText
+	}
+	if len([]rune(text)) < p.summaryMinLength {
+		return ""
+	}
+	return text
+}
+
+// forEachConcurrently calls fn for the indexes 0 to n-1 with at most limit calls running at a
+// time, and waits for them to return. Indexes not started when the context ends are skipped.
+// Returns the number of indexes skipped.
+func forEachConcurrently(ctx context.Context, n, limit int, fn func(i int)) int {
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for i := 0; i < n; i++ {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			return n - i
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			fn(i)
+		}()
+	}
+	return 0
+}
+
+// translate machine translates the headline and source text of alerts without a Dataminr
+// translation into the language of the channel each is posted in. Each alert is translated
+// once per language, in parallel within the translation budget. Translator failures are logged
+// and the alert is posted untranslated, as are alerts not translated within the budget.
+func (p *AlertProcessor) translate(ctx context.Context, posts []pendingPost) {
+	type translation struct {
+		alert    backend.Alert
+		language string
+	}
+
+	var keys []string
+	translations := make(map[string]*translation)
+	for i := range posts {
+		alert := posts[i].alert
+		language := p.translation.TargetLanguage(posts[i].channelID)
+		if language == "" || alert.TranslatedText != "" {
 			continue
 		}
 
-		p.api.Log.Debug("Successfully posted alert", "alertId", alert.AlertID, "channelId", p.channelID)
-		newCount++
+		key := alert.AlertID + "\n" + language
+		if _, exists := translations[key]; !exists {
+			keys = append(keys, key)
+			translations[key] = &translation{alert: alert, language: language}
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.translationBudget)
+	defer cancel()
+
+	skipped := forEachConcurrently(ctx, len(keys), maxEnrichmentRequests, func(i int) {
+		t := translations[keys[i]]
+		translated, err := p.translateAlert(ctx, t.alert, t.language)
+		if err != nil {
+			p.logger.Warn("Failed to translate alert, posting it untranslated", "alertId", t.alert.AlertID, "language", t.language, "error", err.Error())
+			return
+		}
+		t.alert = translated
+	})
+	if skipped > 0 {
+		p.logger.Warn("Translation budget exhausted, posting the remaining alerts untranslated", "skipped", skipped)
+	}
+
+	for i := range posts {
+		if t, exists := translations[posts[i].alert.AlertID+"\n"+p.translation.TargetLanguage(posts[i].channelID)]; exists {
+			posts[i].alert = t.alert
+		}
 	}
+}
 
-	return newCount, nil
+// translateAlert returns a copy of the alert with its headline and source text translated
+func (p *AlertProcessor) translateAlert(ctx context.Context, alert backend.Alert, language string) (backend.Alert, error) {
+	headline, err := p.translator.Translate(ctx, alert.Headline, language)
+	if err != nil || headline == "" {
+		return alert, err
+	}
+
+	var sourceText string
+	if alert.SourceText != "" {
+		if sourceText, err = p.translator.Translate(ctx, alert.SourceText, language); err != nil {
+			return alert, err
+		}
+	}
+
+	alert.OriginalHeadline = alert.Headline
+	alert.Headline = headline
+	alert.TranslatedText = sourceText
+	alert.TranslationLanguage = language
+	return alert, nil
+}
+
+// postAll posts alerts using a bounded pool of workers. Alerts are grouped by channel and
+// each channel's alerts are posted by a single worker in batch order, so ordering within a
+// channel is preserved while different channels are posted in parallel.
+// Returns the number of alerts posted successfully and the alerts skipped because the
+// context was cancelled.
+func (p *AlertProcessor) postAll(ctx context.Context, posts []pendingPost) (int, []pendingPost) {
+	if len(posts) == 0 {
+		return 0, nil
+	}
+
+	var channelOrder []string
+	queues := make(map[string][]pendingPost)
+	for _, post := range posts {
+		if _, exists := queues[post.channelID]; !exists {
+			channelOrder = append(channelOrder, post.channelID)
+		}
+		queues[post.channelID] = append(queues[post.channelID], post)
+	}
+
+	workers := p.concurrency
+	if workers > len(channelOrder) {
+		workers = len(channelOrder)
+	}
+
+	var posted int64
+	var unpostedMu sync.Mutex
+	var unposted []pendingPost
+	work := make(chan []pendingPost)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for queue := range work {
+				count, skipped := p.postQueue(ctx, queue)
+				atomic.AddInt64(&posted, int64(count))
+				if len(skipped) > 0 {
+					unpostedMu.Lock()
+					unposted = append(unposted, skipped...)
+					unpostedMu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, channelID := range channelOrder {
+		work <- queues[channelID]
+	}
+	close(work)
+	wg.Wait()
+
+	if p.pending != nil {
+		if err := p.pending.flush(); err != nil {
+			p.logger.Error("Failed to update the pending queue", "backendName", p.backendName, "error", err.Error())
+		}
+	}
+
+	return int(posted), unposted
+}
+
+// postQueue posts a single channel's alerts in order and returns the number posted.
+// Once the context is cancelled the remaining alerts are returned unposted.
+func (p *AlertProcessor) postQueue(ctx context.Context, queue []pendingPost) (int, []pendingPost) {
+	posted := 0
+	for i, item := range queue {
+		if ctx.Err() != nil {
+			return posted, queue[i:]
+		}
+
+		if err := p.postAlert(ctx, item); err != nil {
+			p.logger.Error("Failed to post alert", "alertId", item.alert.AlertID, "channelId", item.channelID, "error", err.Error())
+			if item.checkpointed {
+				p.recordPostFailure(item, err)
+			}
+			continue
+		}
+
+		if item.checkpointed {
+			p.pending.remove(item.alert.AlertID, item.channelID)
+		}
+
+		p.logger.Debug("Successfully posted alert", "alertId", item.alert.AlertID, "channelId", item.channelID)
+		if !item.subscribed && !item.retry {
+			posted++
+		}
+	}
+	return posted, nil
+}
+
+// postAlert posts an alert to its channel, or only logs it in dry-run mode
+func (p *AlertProcessor) postAlert(ctx context.Context, item pendingPost) error {
+	if p.dryRun {
+		p.logger.Info("Dry run: would have posted alert",
+			"alertId", item.alert.AlertID,
+			"channelId", item.channelID,
+			"alertType", item.alert.AlertType,
+			"headline", item.alert.Headline)
+		return nil
+	}
+	return p.poster.PostAlert(ctx, item.alert, item.channelID)
+}
+
+// prioritize orders alerts by priority so Flash alerts are posted before Urgent alerts and
+// routine alerts, keeping the batch order within each priority. When the batch exceeds
+// maxBatch, the lowest priority alerts (oldest first) are returned separately as overflow.
+func prioritize(posts []pendingPost, maxBatch int) ([]pendingPost, []pendingPost) {
+	var overflow []pendingPost
+	if excess := len(posts) - maxBatch; excess > 0 {
+		leastUrgent := append([]pendingPost(nil), posts...)
+		sort.SliceStable(leastUrgent, func(i, j int) bool {
+			return leastUrgent[i].alert.Priority() > leastUrgent[j].alert.Priority()
+		})
+		overflow = leastUrgent[:excess]
+		posts = leastUrgent[excess:]
+	}
+
+	return sortByPriority(posts), overflow
+}
+
+// sortByPriority returns the alerts with Flash alerts first, then Urgent alerts and routine
+// alerts, keeping their order within each priority
+func sortByPriority(posts []pendingPost) []pendingPost {
+	sorted := append([]pendingPost(nil), posts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].alert.Priority() < sorted[j].alert.Priority()
+	})
+	return sorted
+}
+
+// withSubscribers adds a copy of each alert for every subscribed channel whose filters it
+// matches. If the subscriptions can't be loaded alerts are only posted to the configured channel.
+func (p *AlertProcessor) withSubscribers(posts []pendingPost) []pendingPost {
+	if p.subscriptions == nil || len(posts) == 0 {
+		return posts
+	}
+
+	subscriptions, err := p.subscriptions.List(p.backendID)
+	if err != nil {
+		p.logger.Error("Failed to load channel subscriptions", "backendName", p.backendName, "error", err.Error())
+		return posts
+	}
+
+	if len(subscriptions) == 0 {
+		return posts
+	}
+
+	result := make([]pendingPost, 0, len(posts)*(len(subscriptions)+1))
+	for _, post := range posts {
+		result = append(result, post)
+		for _, subscription := range subscriptions {
+			if subscription.ChannelID == post.channelID || !subscription.Matches(post.alert) {
+				continue
+			}
+			result = append(result, pendingPost{alert: post.alert, channelID: subscription.ChannelID, subscribed: true})
+		}
+	}
+	return result
+}
+
+// withoutMutedTopics drops posts of alerts whose topics are muted in the target channel.
+// Returns the remaining posts and the number of alerts muted in the configured channel.
+// If a channel's mutes can't be loaded its alerts are posted.
+func (p *AlertProcessor) withoutMutedTopics(posts []pendingPost) ([]pendingPost, int) {
+	if p.topicMutes == nil || len(posts) == 0 {
+		return posts, 0
+	}
+
+	now := time.Now()
+	mutesByChannel := make(map[string][]backend.TopicMute)
+	result := make([]pendingPost, 0, len(posts))
+	muted := 0
+	for _, post := range posts {
+		mutes, loaded := mutesByChannel[post.channelID]
+		if !loaded {
+			var err error
+			if mutes, err = p.topicMutes.List(post.channelID, now); err != nil {
+				p.logger.Error("Failed to load topic mutes", "channelId", post.channelID, "error", err.Error())
+			}
+			mutesByChannel[post.channelID] = mutes
+		}
+
+		if mute := backend.MutedTopic(mutes, post.alert, now); mute != nil {
+			p.logger.Debug("Skipping alert with a muted topic", "alertId", post.alert.AlertID, "channelId", post.channelID, "topic", mute.Topic)
+			if !post.subscribed {
+				muted++
+			}
+			continue
+		}
+		result = append(result, post)
+	}
+	return result, muted
+}
+
+// withoutMutedAlertLists drops posts of alerts whose alert lists are muted for the backend or
+// in the target channel. Returns the remaining posts and the number of alerts muted in the
+// configured channel. If mutes can't be loaded the alerts are posted.
+func (p *AlertProcessor) withoutMutedAlertLists(posts []pendingPost) ([]pendingPost, int) {
+	if p.alertListMutes == nil || len(posts) == 0 {
+		return posts, 0
+	}
+
+	now := time.Now()
+	backendMutes, err := p.alertListMutes.List(backend.AlertListMuteScope{BackendID: p.backendID}, now)
+	if err != nil {
+		p.logger.Error("Failed to load alert list mutes", "backendId", p.backendID, "error", err.Error())
+	}
+
+	mutesByChannel := make(map[string][]backend.AlertListMute)
+	result := make([]pendingPost, 0, len(posts))
+	muted := 0
+	for _, post := range posts {
+		mutes, loaded := mutesByChannel[post.channelID]
+		if !loaded {
+			var err error
+			if mutes, err = p.alertListMutes.List(backend.AlertListMuteScope{ChannelID: post.channelID}, now); err != nil {
+				p.logger.Error("Failed to load alert list mutes", "channelId", post.channelID, "error", err.Error())
+			}
+			mutesByChannel[post.channelID] = mutes
+		}
+
+		mute := backend.MutedAlertList(backendMutes, post.alert, now)
+		if mute == nil {
+			mute = backend.MutedAlertList(mutes, post.alert, now)
+		}
+		if mute != nil {
+			p.logger.Debug("Skipping alert in a muted alert list", "alertId", post.alert.AlertID, "channelId", post.channelID, "alertList", mute.AlertList)
+			if !post.subscribed {
+				muted++
+			}
+			continue
+		}
+		result = append(result, post)
+	}
+	return result, muted
+}
+
+// checkpoint stores alerts in the pending queue before they are posted.
+// If the queue can't be saved the alerts are posted without a checkpoint.
+func (p *AlertProcessor) checkpoint(posts []pendingPost) {
+	if p.pending == nil || len(posts) == 0 {
+		return
+	}
+
+	if err := p.pending.add(posts); err != nil {
+		p.logger.Error("Failed to checkpoint alerts before posting", "backendName", p.backendName, "error", err.Error())
+		return
+	}
+
+	for i := range posts {
+		posts[i].checkpointed = true
+	}
+}
+
+// RetryPendingAlerts posts the alerts left in the pending queue by earlier poll cycles. Alerts
+// that failed to post are retried once their retry delay has passed. The poller calls it on
+// every run of its job that polls, including runs that fail to fetch alerts, so posting is
+// retried while the API is unavailable. Runs skipped while paused, backing off or cooling
+// down leave the queue alone.
+func (p *AlertProcessor) RetryPendingAlerts(ctx context.Context) {
+	posts := p.duePendingPosts()
+	if len(posts) == 0 {
+		return
+	}
+	p.postAll(ctx, sortByPriority(posts))
+}
+
+// duePendingPosts loads the pending queue and returns the alerts due for another attempt
+func (p *AlertProcessor) duePendingPosts() []pendingPost {
+	if p.pending == nil {
+		return nil
+	}
+
+	pending, err := p.pending.load()
+	if err != nil {
+		p.logger.Error("Failed to load pending alerts", "backendName", p.backendName, "error", err.Error())
+		return nil
+	}
+
+	now := p.now()
+	var posts []pendingPost
+	for _, item := range pending {
+		if now.Before(item.RetryAt) {
+			continue
+		}
+		posts = append(posts, pendingPost{
+			alert:        item.Alert,
+			channelID:    item.ChannelID,
+			checkpointed: true,
+			subscribed:   item.Subscribed,
+			retry:        true,
+		})
+	}
+
+	if len(posts) > 0 {
+		p.logger.Info("Retrying alerts left unposted by an earlier poll cycle", "backendName", p.backendName, "count", len(posts))
+	}
+	return posts
+}
+
+// recordPostFailure counts a failed post of a checkpointed alert, dropping it after too many attempts
+func (p *AlertProcessor) recordPostFailure(item pendingPost, postErr error) {
+	dropped, err := p.pending.recordFailure(item.alert.AlertID, item.channelID, postErr, p.now())
+	if err != nil {
+		p.logger.Error("Failed to record the failed delivery", "alertId", item.alert.AlertID, "error", err.Error())
+	}
+	if dropped {
+		p.logger.Error("Dropping alert after repeated posting failures",
+			"alertId", item.alert.AlertID,
+			"channelId", item.channelID,
+			"attempts", backend.MaxAlertPostAttempts)
+	}
+}
+
+// postSummary posts one summary message per channel for alerts that exceeded the batch cap.
+// Returns the alerts of the channels whose summary failed to post.
+func (p *AlertProcessor) postSummary(posts []pendingPost) []pendingPost {
+	var channelOrder []string
+	byChannel := make(map[string][]pendingPost)
+	for _, post := range posts {
+		if _, exists := byChannel[post.channelID]; !exists {
+			channelOrder = append(channelOrder, post.channelID)
+		}
+		byChannel[post.channelID] = append(byChannel[post.channelID], post)
+	}
+
+	var unsummarized []pendingPost
+	for _, channelID := range channelOrder {
+		channelPosts := byChannel[channelID]
+		alerts := make([]backend.Alert, 0, len(channelPosts))
+		for _, post := range channelPosts {
+			alerts = append(alerts, post.alert)
+		}
+		p.logger.Warn("Alert batch exceeded limit, summarizing oldest alerts",
+			"backendName", p.backendName,
+			"channelId", channelID,
+			"summarized", len(alerts),
+			"limit", p.maxBatch)
+
+		if p.dryRun {
+			p.logger.Info("Dry run: would have posted batch summary", "channelId", channelID, "summarized", len(alerts))
+			continue
+		}
+		if err := p.poster.PostMessage(summarizeAlerts(p.backendName, alerts, p.maxBatch), channelID); err != nil {
+			p.logger.Error("Failed to post batch summary, posting the alerts individually", "channelId", channelID, "error", err.Error())
+			unsummarized = append(unsummarized, channelPosts...)
+		}
+	}
+	return unsummarized
+}
+
+// summarizeAlerts builds the summary text for alerts that were not posted individually
+func summarizeAlerts(backendName string, alerts []backend.Alert, limit int) string {
+	var typeOrder []string
+	typeCounts := make(map[string]int)
+	for _, alert := range alerts {
+		alertType := alert.AlertType
+		if alertType == "" {
+			alertType = "Alert"
+		}
+		if typeCounts[alertType] == 0 {
+			typeOrder = append(typeOrder, alertType)
+		}
+		typeCounts[alertType]++
+	}
+
+	counts := make([]string, 0, len(typeOrder))
+	for _, alertType := range typeOrder {
+		counts = append(counts, fmt.Sprintf("%s: %d", alertType, typeCounts[alertType]))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(":warning: **%d older alerts from %s were not posted individually** (batch limit: %d alerts)\n",
+		len(alerts), backendName, limit))
+	sb.WriteString(strings.Join(counts, ", "))
+
+	for i, alert := range alerts {
+		if i == maxSummarizedHeadlines {
+			sb.WriteString(fmt.Sprintf("\n* ...and %d more", len(alerts)-maxSummarizedHeadlines))
+			break
+		}
+
+		if alert.AlertURL != "" {
+			sb.WriteString(fmt.Sprintf("\n* [%s](%s)", alert.Headline, alert.AlertURL))
+		} else {
+			sb.WriteString(fmt.Sprintf("\n* %s", alert.Headline))
+		}
+	}
+
+	return sb.String()
+}
+
+// DeliverHeldAlerts releases the alerts held back during quiet hours once they have ended. The
+// poller calls it on every run of its job that polls, including runs that fail to fetch
+// alerts, so held alerts are not delayed until the API recovers. Runs skipped while paused,
+// backing off or cooling down keep them held.
+func (p *AlertProcessor) DeliverHeldAlerts(ctx context.Context) {
+	p.flushQuietHoursBuffer(ctx)
+}
+
+// flushQuietHoursBuffer releases alerts that were buffered during quiet hours, oldest first.
+// The alerts are moved to the pending queue and posted with the next alerts posted by the
+// run, in priority order, so they don't delay Flash alerts fetched by the same run. Alerts
+// that fail to post or are left unposted by a cancelled cycle or a crash are retried instead
+// of lost. Without a pending queue, the alerts are posted right away and leave the buffer
+// once posted.
+func (p *AlertProcessor) flushQuietHoursBuffer(ctx context.Context) {
+	buffered, err := p.quietHours.Held()
+	if err != nil {
+		p.logger.Error("Failed to load quiet hours buffer", "backendName", p.backendName, "error", err.Error())
+		return
+	}
+
+	if len(buffered) == 0 {
+		return
+	}
+
+	p.logger.Info("Quiet hours ended, posting buffered alerts", "backendName", p.backendName, "count", len(buffered))
+
+	channelID := p.targetChannel()
+	batch := &AlertBatch{posts: make([]pendingPost, 0, len(buffered))}
+	for _, alert := range buffered {
+		batch.posts = append(batch.posts, pendingPost{alert: alert, channelID: channelID})
+	}
+
+	// Buffered alerts were already filtered, so they resume the pipeline at enrichment
+	p.pipeline.run(ctx, batch, PhaseEnrich, PhaseRoute, p.logger)
+
+	if p.pending == nil {
+		_, unposted := p.postAll(ctx, batch.posts)
+		p.releaseBuffered(buffered, unposted)
+		return
+	}
+
+	if _, err := p.pending.load(); err != nil {
+		p.logger.Error("Failed to load pending alerts", "backendName", p.backendName, "error", err.Error())
+		return
+	}
+	p.checkpoint(batch.posts)
+	if len(batch.posts) > 0 && !batch.posts[0].checkpointed {
+		// Keep the alerts buffered; the next run tries again
+		return
+	}
+	p.releaseBuffered(buffered, nil)
+}
+
+// releaseBuffered removes the buffered alerts from the quiet hours buffer, except those whose
+// post to the configured channel is still outstanding
+func (p *AlertProcessor) releaseBuffered(buffered []backend.Alert, unposted []pendingPost) {
+	outstanding := make(map[string]bool, len(unposted))
+	for _, item := range unposted {
+		if !item.subscribed {
+			outstanding[item.alert.AlertID] = true
+		}
+	}
+
+	released := make([]backend.Alert, 0, len(buffered))
+	for _, alert := range buffered {
+		if !outstanding[alert.AlertID] {
+			released = append(released, alert)
+		}
+	}
+
+	if err := p.quietHours.Release(released); err != nil {
+		p.logger.Error("Failed to remove posted alerts from the quiet hours buffer", "backendName", p.backendName, "error", err.Error())
+	}
 }
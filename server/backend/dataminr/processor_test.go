@@ -1,7 +1,12 @@
 package dataminr
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -9,6 +14,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/pluginapi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
@@ -30,7 +36,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 		}
 
 		mockDedup := NewMockDeduplicator()
-		processor := NewAlertProcessor(client, "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup)
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup, nil)
 
 		alerts := []Alert{
 			{
@@ -49,13 +55,14 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 			},
 		}
 
-		count, err := processor.ProcessAlerts(alerts)
+		count, err := processor.ProcessAlerts(context.Background(), alerts)
 
 		assert.NoError(t, err)
 		assert.Equal(t, 2, count)
 		assert.Len(t, postedAlerts, 2)
 		assert.Equal(t, "alert-1", postedAlerts[0].AlertID)
 		assert.Equal(t, "alert-2", postedAlerts[1].AlertID)
+		assert.Equal(t, "test-backend-id", postedAlerts[0].BackendID)
 	})
 
 	t.Run("skips duplicate alerts", func(t *testing.T) {
@@ -72,7 +79,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 		}
 
 		mockDedup := NewMockDeduplicator()
-		processor := NewAlertProcessor(client, "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup)
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup, nil)
 
 		alerts := []Alert{
 			{
@@ -91,7 +98,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 			},
 		}
 
-		count, err := processor.ProcessAlerts(alerts)
+		count, err := processor.ProcessAlerts(context.Background(), alerts)
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, count)
@@ -112,7 +119,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 		}
 
 		mockDedup := NewMockDeduplicator()
-		processor := NewAlertProcessor(client, "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup)
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup, nil)
 
 		// First batch
 		batch1 := []Alert{
@@ -125,7 +132,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 			},
 		}
 
-		count1, err := processor.ProcessAlerts(batch1)
+		count1, err := processor.ProcessAlerts(context.Background(), batch1)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, count1)
 
@@ -147,7 +154,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 			},
 		}
 
-		count2, err := processor.ProcessAlerts(batch2)
+		count2, err := processor.ProcessAlerts(context.Background(), batch2)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, count2) // Only alert-2 should be processed
 
@@ -176,7 +183,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 		}
 
 		mockDedup := NewMockDeduplicator()
-		processor := NewAlertProcessor(client, "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup)
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup, nil)
 
 		alerts := []Alert{
 			{
@@ -202,7 +209,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 			},
 		}
 
-		count, err := processor.ProcessAlerts(alerts)
+		count, err := processor.ProcessAlerts(context.Background(), alerts)
 
 		assert.NoError(t, err)
 		assert.Equal(t, 2, count) // alert-1 and alert-3 succeeded
@@ -223,9 +230,9 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 		}
 
 		mockDedup := NewMockDeduplicator()
-		processor := NewAlertProcessor(client, "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup)
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup, nil)
 
-		count, err := processor.ProcessAlerts([]Alert{})
+		count, err := processor.ProcessAlerts(context.Background(), []Alert{})
 
 		assert.NoError(t, err)
 		assert.Equal(t, 0, count)
@@ -240,7 +247,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 		mockPoster := &MockPoster{}
 
 		mockDedup := NewMockDeduplicator()
-		processor := NewAlertProcessor(client, "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup)
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup, nil)
 
 		alerts := []Alert{
 			{
@@ -252,7 +259,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 			},
 		}
 
-		count, err := processor.ProcessAlerts(alerts)
+		count, err := processor.ProcessAlerts(context.Background(), alerts)
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, count)
@@ -272,7 +279,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 		}
 
 		mockDedup := NewMockDeduplicator()
-		processor := NewAlertProcessor(client, "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup)
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup, nil)
 
 		alerts := []Alert{
 			{
@@ -290,7 +297,7 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 			},
 		}
 
-		count, err := processor.ProcessAlerts(alerts)
+		count, err := processor.ProcessAlerts(context.Background(), alerts)
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, count)
@@ -301,3 +308,1073 @@ func TestAlertProcessor_ProcessAlerts(t *testing.T) {
 		assert.InDelta(t, 1609.34, capturedAlert.Location.ConfidenceRadius, 0.01)
 	})
 }
+
+func TestAlertProcessor_AttachRawPayload(t *testing.T) {
+	raw := json.RawMessage(`{"alertId":"alert-1","extra":"field"}`)
+	alerts := []Alert{
+		{AlertID: "alert-1", AlertType: AlertType{Name: "Alert"}, EventTime: time.Now(), Headline: "Test", Raw: raw},
+	}
+
+	for _, enabled := range []bool{false, true} {
+		api := plugintest.NewAPI(t)
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		var posted []backend.Alert
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				posted = append(posted, alert)
+				return nil
+			},
+		}
+
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+		processor.SetAttachRawPayload(enabled)
+
+		_, err := processor.ProcessAlerts(context.Background(), alerts)
+		require.NoError(t, err)
+		require.Len(t, posted, 1)
+
+		if enabled {
+			assert.Equal(t, string(raw), posted[0].RawPayload)
+		} else {
+			assert.Empty(t, posted[0].RawPayload)
+		}
+	}
+}
+
+// stubSummarizer records the text it is asked to summarize. A block channel holds each
+// summary until it is closed or the context ends.
+type stubSummarizer struct {
+	mu    sync.Mutex
+	texts []string
+	err   error
+	block chan struct{}
+}
+
+func (s *stubSummarizer) Summarize(ctx context.Context, text string) (string, error) {
+	s.mu.Lock()
+	s.texts = append(s.texts, text)
+	s.mu.Unlock()
+
+	if s.block != nil {
+		select {
+		case <-s.block:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	if s.err != nil {
+		return "", s.err
+	}
+	return "summary of " + text[:4], nil
+}
+
+func TestAlertProcessor_Summarizer(t *testing.T) {
+	longText := strings.Repeat("word ", 30)
+	alerts := []Alert{
+		{AlertID: "short", AlertType: AlertType{Name: "Alert"}, EventTime: time.Now(), Headline: "Short", PublicPost: &PublicPost{Text: "short text"}},
+		{AlertID: "source", AlertType: AlertType{Name: "Alert"}, EventTime: time.Now(), Headline: "Source", PublicPost: &PublicPost{Text: "src " + longText}},
+		{AlertID: "translated", AlertType: AlertType{Name: "Alert"}, EventTime: time.Now(), Headline: "Translated", PublicPost: &PublicPost{Text: "src " + longText, TranslatedText: "tra " + longText}},
+	}
+
+	newProcessor := func(t *testing.T, posted *[]backend.Alert) *AlertProcessor {
+		api := plugintest.NewAPI(t)
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				*posted = append(*posted, alert)
+				return nil
+			},
+		}
+		return NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+	}
+
+	t.Run("no summary without a summarizer", func(t *testing.T) {
+		var posted []backend.Alert
+		processor := newProcessor(t, &posted)
+
+		_, err := processor.ProcessAlerts(context.Background(), alerts)
+		require.NoError(t, err)
+		require.Len(t, posted, 3)
+		for _, alert := range posted {
+			assert.Empty(t, alert.Summary)
+		}
+	})
+
+	t.Run("summarizes long text, preferring the translation", func(t *testing.T) {
+		var posted []backend.Alert
+		processor := newProcessor(t, &posted)
+		summarizer := &stubSummarizer{}
+		processor.SetSummarizer(summarizer, 100)
+
+		_, err := processor.ProcessAlerts(context.Background(), alerts)
+		require.NoError(t, err)
+		require.Len(t, posted, 3)
+
+		summaries := make(map[string]string)
+		for _, alert := range posted {
+			summaries[alert.AlertID] = alert.Summary
+		}
+		assert.Equal(t, map[string]string{"short": "", "source": "summary of src ", "translated": "summary of tra "}, summaries)
+		assert.Len(t, summarizer.texts, 2)
+	})
+
+	t.Run("posts without a summary when summarizing fails", func(t *testing.T) {
+		var posted []backend.Alert
+		processor := newProcessor(t, &posted)
+		processor.SetSummarizer(&stubSummarizer{err: errors.New("service unavailable")}, 100)
+
+		_, err := processor.ProcessAlerts(context.Background(), alerts)
+		require.NoError(t, err)
+		require.Len(t, posted, 3)
+		for _, alert := range posted {
+			assert.Empty(t, alert.Summary)
+		}
+	})
+
+	t.Run("summarizes in parallel within the batch budget", func(t *testing.T) {
+		var many []Alert
+		for i := 0; i < maxEnrichmentRequests+2; i++ {
+			many = append(many, Alert{AlertID: fmt.Sprintf("alert-%d", i), AlertType: AlertType{Name: "Alert"}, EventTime: time.Now(), Headline: "Long", PublicPost: &PublicPost{Text: "src " + longText}})
+		}
+
+		var posted []backend.Alert
+		processor := newProcessor(t, &posted)
+		summarizer := &stubSummarizer{block: make(chan struct{})}
+		processor.SetSummarizer(summarizer, 100)
+		processor.summaryBudget = 50 * time.Millisecond
+
+		_, err := processor.ProcessAlerts(context.Background(), many)
+		require.NoError(t, err)
+		require.Len(t, posted, len(many))
+		for _, alert := range posted {
+			assert.Empty(t, alert.Summary)
+		}
+
+		// Only the first requests started before the budget ran out; the rest were skipped
+		assert.Len(t, summarizer.texts, maxEnrichmentRequests)
+	})
+}
+
+// stubTranslator translates text by prefixing it with the target language. A block channel
+// holds each translation until it is closed or the context ends.
+type stubTranslator struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+	block chan struct{}
+}
+
+func (s *stubTranslator) Translate(ctx context.Context, text, targetLanguage string) (string, error) {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+
+	if s.block != nil {
+		select {
+		case <-s.block:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	if s.err != nil {
+		return "", s.err
+	}
+	return "[" + targetLanguage + "] " + text, nil
+}
+
+func TestAlertProcessor_Translator(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	var posted []backend.Alert
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			posted = append(posted, alert)
+			return nil
+		},
+	}
+
+	alerts := []Alert{
+		{AlertID: "untranslated", AlertType: AlertType{Name: "Alert"}, EventTime: time.Now(), Headline: "Fire downtown", PublicPost: &PublicPost{Text: "Roads closed"}},
+		{AlertID: "translated", AlertType: AlertType{Name: "Alert"}, EventTime: time.Now(), Headline: "Feu", PublicPost: &PublicPost{Text: "Routes fermées", TranslatedText: "Roads closed"}},
+	}
+
+	translator := &stubTranslator{}
+	processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+	processor.SetTranslator(translator, backend.TranslatorSettings{Language: "fr"})
+
+	_, err := processor.ProcessAlerts(context.Background(), alerts)
+	require.NoError(t, err)
+	require.Len(t, posted, 2)
+
+	assert.Equal(t, "[fr] Fire downtown", posted[0].Headline)
+	assert.Equal(t, "Fire downtown", posted[0].OriginalHeadline)
+	assert.Equal(t, "[fr] Roads closed", posted[0].TranslatedText)
+	assert.Equal(t, "fr", posted[0].TranslationLanguage)
+
+	// Alerts translated by Dataminr are left as is
+	assert.Equal(t, "Feu", posted[1].Headline)
+	assert.Empty(t, posted[1].TranslationLanguage)
+	assert.Equal(t, 2, translator.calls)
+
+	t.Run("translates once per language and channel language", func(t *testing.T) {
+		translator := &stubTranslator{}
+		processor.SetTranslator(translator, backend.TranslatorSettings{ChannelLanguages: map[string]string{"channel-de": "de", "channel-de-2": "de"}})
+
+		alert := backend.Alert{AlertID: "alert-1", Headline: "Fire downtown"}
+		posts := []pendingPost{
+			{alert: alert, channelID: "channel-de"},
+			{alert: alert, channelID: "channel-de-2", subscribed: true},
+			{alert: alert, channelID: "channel-other", subscribed: true},
+		}
+		processor.translate(context.Background(), posts)
+
+		assert.Equal(t, "[de] Fire downtown", posts[0].alert.Headline)
+		assert.Equal(t, "[de] Fire downtown", posts[1].alert.Headline)
+		assert.Equal(t, "Fire downtown", posts[2].alert.Headline)
+		assert.Equal(t, 1, translator.calls)
+	})
+
+	t.Run("posts untranslated when translating fails", func(t *testing.T) {
+		processor.SetTranslator(&stubTranslator{err: errors.New("service unavailable")}, backend.TranslatorSettings{Language: "fr"})
+
+		posts := []pendingPost{{alert: backend.Alert{AlertID: "alert-1", Headline: "Fire downtown"}, channelID: "test-channel-id"}}
+		processor.translate(context.Background(), posts)
+
+		assert.Equal(t, "Fire downtown", posts[0].alert.Headline)
+		assert.Empty(t, posts[0].alert.TranslationLanguage)
+	})
+
+	t.Run("translates in parallel within the batch budget", func(t *testing.T) {
+		translator := &stubTranslator{block: make(chan struct{})}
+		processor.SetTranslator(translator, backend.TranslatorSettings{Language: "fr"})
+		processor.translationBudget = 50 * time.Millisecond
+		defer func() { processor.translationBudget = translationBudget }()
+
+		var posts []pendingPost
+		for i := 0; i < maxEnrichmentRequests+2; i++ {
+			posts = append(posts, pendingPost{alert: backend.Alert{AlertID: fmt.Sprintf("alert-%d", i), Headline: "Fire downtown"}, channelID: "test-channel-id"})
+		}
+		processor.translate(context.Background(), posts)
+
+		for _, post := range posts {
+			assert.Equal(t, "Fire downtown", post.alert.Headline)
+		}
+
+		// Only the first requests started before the budget ran out; the rest were skipped
+		assert.Equal(t, maxEnrichmentRequests, translator.calls)
+	})
+}
+
+func TestAlertProcessor_QuietHours(t *testing.T) {
+	eventTime := time.Now().UTC()
+	schedule := &backend.QuietHours{Ranges: []backend.TimeRange{{Start: "22:00", End: "06:00"}}}
+	quietTime := time.Date(2025, 1, 13, 23, 0, 0, 0, time.UTC)
+	activeTime := time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC)
+
+	alerts := []Alert{
+		{AlertID: "alert-1", AlertType: AlertType{Name: "Flash"}, EventTime: eventTime, Headline: "Flash Alert"},
+		{AlertID: "alert-2", AlertType: AlertType{Name: "Urgent"}, EventTime: eventTime, Headline: "Urgent Alert"},
+	}
+
+	t.Run("buffers non-Flash alerts during quiet hours", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("KVGet", "backend_test-id_quiet_buffer").Return(nil, nil)
+		api.On("KVSetWithOptions", "backend_test-id_quiet_buffer", mock.Anything, mock.Anything).Return(true, nil).Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		postedAlerts := []backend.Alert{}
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				postedAlerts = append(postedAlerts, alert)
+				return nil
+			},
+		}
+
+		gate := NewQuietHoursGate(schedule, NewStateStore(api, "test-id"))
+		gate.now = func() time.Time { return quietTime }
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), gate)
+
+		count, err := processor.ProcessAlerts(context.Background(), alerts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Len(t, postedAlerts, 1)
+		assert.Equal(t, "alert-1", postedAlerts[0].AlertID, "Flash alerts should post immediately")
+		api.AssertExpectations(t)
+	})
+
+	t.Run("flushes buffered alerts after quiet hours end", func(t *testing.T) {
+		buffered, err := json.Marshal([]backend.Alert{{AlertID: "buffered-1", AlertType: "Alert"}})
+		require.NoError(t, err)
+
+		api := &plugintest.API{}
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		kvStore := mockKVStore(api)
+		kvStore["backend_test-id_quiet_buffer"] = buffered
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		postedAlerts := []backend.Alert{}
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				postedAlerts = append(postedAlerts, alert)
+				return nil
+			},
+		}
+
+		stateStore := NewStateStore(api, "test-id")
+		gate := NewQuietHoursGate(schedule, stateStore)
+		gate.now = func() time.Time { return activeTime }
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), gate)
+		processor.SetPendingStore(stateStore)
+
+		processor.DeliverHeldAlerts(context.Background())
+		count, err := processor.ProcessAlerts(context.Background(), alerts[1:])
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		require.Len(t, postedAlerts, 2)
+		assert.Equal(t, "alert-2", postedAlerts[0].AlertID, "Buffered alerts are posted with the new alerts in priority order")
+		assert.Equal(t, "buffered-1", postedAlerts[1].AlertID)
+		assert.NotContains(t, kvStore, "backend_test-id_quiet_buffer")
+		assert.NotContains(t, kvStore, "backend_test-id_pending")
+	})
+
+	t.Run("buffered alerts that fail to post are kept for a retry", func(t *testing.T) {
+		buffered, err := json.Marshal([]backend.Alert{{AlertID: "buffered-1", AlertType: "Alert"}})
+		require.NoError(t, err)
+
+		api := &plugintest.API{}
+		api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		kvStore := mockKVStore(api)
+		kvStore["backend_test-id_quiet_buffer"] = buffered
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				return errors.New("post failed")
+			},
+		}
+
+		stateStore := NewStateStore(api, "test-id")
+		gate := NewQuietHoursGate(schedule, stateStore)
+		gate.now = func() time.Time { return activeTime }
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), gate)
+		processor.SetPendingStore(stateStore)
+
+		processor.DeliverHeldAlerts(context.Background())
+		processor.RetryPendingAlerts(context.Background())
+
+		// The alert moved from the buffer to the pending queue
+		assert.NotContains(t, kvStore, "backend_test-id_quiet_buffer")
+		pending, err := stateStore.GetPendingAlerts()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, "buffered-1", pending[0].Alert.AlertID)
+		assert.Equal(t, 1, pending[0].Attempts)
+	})
+
+	t.Run("buffered alerts stay buffered while the flush is cancelled", func(t *testing.T) {
+		buffered, err := json.Marshal([]backend.Alert{{AlertID: "buffered-1", AlertType: "Alert"}})
+		require.NoError(t, err)
+
+		api := &plugintest.API{}
+		api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		kvStore := mockKVStore(api)
+		kvStore["backend_test-id_quiet_buffer"] = buffered
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		gate := NewQuietHoursGate(schedule, NewStateStore(api, "test-id"))
+		gate.now = func() time.Time { return activeTime }
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", &MockPoster{}, "test-channel-id", NewMockDeduplicator(), gate)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		processor.DeliverHeldAlerts(ctx)
+
+		assert.Equal(t, buffered, kvStore["backend_test-id_quiet_buffer"], "Without a pending queue alerts leave the buffer once posted")
+	})
+
+	t.Run("alerts buffered by another node while flushing are kept", func(t *testing.T) {
+		api := &plugintest.API{}
+		kvStore := mockKVStore(api)
+		stateStore := NewStateStore(api, "test-id")
+
+		require.NoError(t, stateStore.BufferAlert(backend.Alert{AlertID: "buffered-1"}))
+		held, err := stateStore.GetBufferedAlerts()
+		require.NoError(t, err)
+
+		require.NoError(t, stateStore.BufferAlert(backend.Alert{AlertID: "buffered-2"}))
+		require.NoError(t, stateStore.RemoveBufferedAlerts([]string{held[0].AlertID}))
+
+		remaining, err := stateStore.GetBufferedAlerts()
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+		assert.Equal(t, "buffered-2", remaining[0].AlertID)
+
+		require.NoError(t, stateStore.RemoveBufferedAlerts([]string{"buffered-2"}))
+		assert.NotContains(t, kvStore, "backend_test-id_quiet_buffer")
+	})
+}
+
+func TestAlertProcessor_AlertUpdates(t *testing.T) {
+	eventTime := time.Now().UTC()
+
+	t.Run("duplicates are passed to the poster as updates", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		var posted, updated []backend.Alert
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				posted = append(posted, alert)
+				return nil
+			},
+			PostAlertUpdateFn: func(alert backend.Alert) error {
+				updated = append(updated, alert)
+				return nil
+			},
+		}
+
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+
+		_, err := processor.ProcessAlerts(context.Background(), []Alert{
+			{AlertID: "alert-1", AlertType: AlertType{Name: "Urgent"}, EventTime: eventTime, Headline: "Flooding reported downtown"},
+		})
+		require.NoError(t, err)
+
+		count, err := processor.ProcessAlerts(context.Background(), []Alert{
+			{AlertID: "alert-1", AlertType: AlertType{Name: "Urgent"}, EventTime: eventTime, Headline: "Flooding reported across the city"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		require.Len(t, posted, 1)
+		require.Len(t, updated, 1)
+		assert.Equal(t, "alert-1", updated[0].AlertID)
+		assert.Equal(t, "test-backend-id", updated[0].BackendID)
+		assert.Equal(t, "Flooding reported across the city", updated[0].Headline)
+	})
+
+	t.Run("update failures are logged", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogWarn", "Failed to post alert update", "alertId", "alert-1", "error", "post not found").Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		mockPoster := &MockPoster{
+			PostAlertUpdateFn: func(alert backend.Alert) error {
+				return errors.New("post not found")
+			},
+		}
+
+		deduplicator := NewMockDeduplicator()
+		deduplicator.RecordAlert("dataminr", "alert-1")
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", deduplicator, nil)
+
+		_, err := processor.ProcessAlerts(context.Background(), []Alert{
+			{AlertID: "alert-1", AlertType: AlertType{Name: "Urgent"}, EventTime: eventTime, Headline: "Flooding reported downtown"},
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestAlertProcessor_DryRun(t *testing.T) {
+	eventTime := time.Now().UTC()
+	alerts := []Alert{
+		{AlertID: "alert-1", AlertType: AlertType{Name: "Urgent"}, EventTime: eventTime, Headline: "New alert"},
+		{AlertID: "alert-2", AlertType: AlertType{Name: "Alert"}, EventTime: eventTime, Headline: "Duplicate alert"},
+		{AlertID: "alert-3", AlertType: AlertType{Name: "Alert"}, EventTime: eventTime, Headline: "Summarized alert"},
+	}
+
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", "Dry run: would have posted alert",
+		"alertId", "alert-1",
+		"channelId", "test-channel-id",
+		"alertType", "Urgent",
+		"headline", "New alert").Once()
+	api.On("LogInfo", "Dry run: would have posted batch summary", "channelId", "test-channel-id", "summarized", 1).Once()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			t.Errorf("alert %s should not be posted in dry-run mode", alert.AlertID)
+			return nil
+		},
+		PostMessageFn: func(message, channelID string) error {
+			t.Error("batch summary should not be posted in dry-run mode")
+			return nil
+		},
+		PostAlertUpdateFn: func(alert backend.Alert) error {
+			t.Errorf("update of alert %s should not be posted in dry-run mode", alert.AlertID)
+			return nil
+		},
+	}
+
+	deduplicator := NewMockDeduplicator()
+	deduplicator.RecordAlert("dataminr", "alert-2")
+
+	processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", deduplicator, nil)
+	processor.SetBatchLimits(0, 1)
+	processor.SetDryRun(true)
+
+	// The duplicate is dropped; the new and summarized alerts are processed
+	count, err := processor.ProcessAlerts(context.Background(), alerts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestAlertProcessor_DryRunAlongsideLiveBackend(t *testing.T) {
+	eventTime := time.Now().UTC()
+	alerts := []Alert{
+		{AlertID: "alert-1", AlertType: AlertType{Name: "Alert"}, EventTime: eventTime, Headline: "Flooding reported downtown"},
+	}
+
+	api := &plugintest.API{}
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	var posted []string
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			posted = append(posted, alert.AlertID)
+			return nil
+		},
+	}
+
+	// Both backends are of the same type and share the deduplicator
+	deduplicator := NewMockDeduplicator()
+	dryRun := NewAlertProcessor(client, "dry-run-backend-id", "dataminr", "Dry Run Backend", mockPoster, "dry-run-channel-id", deduplicator, nil)
+	dryRun.SetDryRun(true)
+	live := NewAlertProcessor(client, "live-backend-id", "dataminr", "Live Backend", mockPoster, "live-channel-id", deduplicator, nil)
+
+	// The dry-run backend fetching the alert first doesn't keep the live backend from posting it
+	count, err := dryRun.ProcessAlerts(context.Background(), alerts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = live.ProcessAlerts(context.Background(), alerts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"alert-1"}, posted)
+
+	// Alerts already processed by either backend are duplicates for the dry-run backend
+	count, err = dryRun.ProcessAlerts(context.Background(), alerts)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestAlertProcessor_Routes(t *testing.T) {
+	eventTime := time.Now().UTC()
+	routes := []backend.ChannelRoute{{
+		ChannelID: "after-hours-channel",
+		Timezone:  "America/New_York",
+		Days:      []string{"mon", "tue", "wed", "thu", "fri"},
+		Ranges:    []backend.TimeRange{{Start: "09:00", End: "17:00"}},
+		Outside:   true,
+	}}
+
+	// 2024-03-11 is the first Monday of daylight saving time: business hours start at 13:00 UTC
+	beforeOpening := time.Date(2024, 3, 11, 12, 30, 0, 0, time.UTC)
+	afterOpening := time.Date(2024, 3, 11, 13, 30, 0, 0, time.UTC)
+
+	t.Run("posts to the channel routed at the time of the batch", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		channels := map[string]string{}
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				channels[alert.AlertID] = channelID
+				return nil
+			},
+		}
+
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+		processor.SetRoutes(routes)
+
+		processor.now = func() time.Time { return beforeOpening }
+		_, err := processor.ProcessAlerts(context.Background(), []Alert{{AlertID: "alert-1", AlertType: AlertType{Name: "Alert"}, EventTime: eventTime, Headline: "Early"}})
+		require.NoError(t, err)
+
+		processor.now = func() time.Time { return afterOpening }
+		_, err = processor.ProcessAlerts(context.Background(), []Alert{{AlertID: "alert-2", AlertType: AlertType{Name: "Alert"}, EventTime: eventTime, Headline: "Daytime"}})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"alert-1": "after-hours-channel", "alert-2": "test-channel-id"}, channels)
+	})
+
+	t.Run("flushes buffered alerts to the channel routed when quiet hours end", func(t *testing.T) {
+		buffered, err := json.Marshal([]backend.Alert{{AlertID: "buffered-1", AlertType: "Alert"}})
+		require.NoError(t, err)
+
+		api := &plugintest.API{}
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		kvStore := mockKVStore(api)
+		kvStore["backend_test-id_quiet_buffer"] = buffered
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		channels := map[string]string{}
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				channels[alert.AlertID] = channelID
+				return nil
+			},
+		}
+
+		schedule := &backend.QuietHours{Timezone: "America/New_York", Ranges: []backend.TimeRange{{Start: "22:00", End: "06:00"}}}
+		gate := NewQuietHoursGate(schedule, NewStateStore(api, "test-id"))
+		gate.now = func() time.Time { return beforeOpening }
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), gate)
+		processor.SetRoutes(routes)
+		processor.now = func() time.Time { return beforeOpening }
+
+		processor.DeliverHeldAlerts(context.Background())
+
+		assert.Equal(t, map[string]string{"buffered-1": "after-hours-channel"}, channels)
+		assert.NotContains(t, kvStore, "backend_test-id_quiet_buffer")
+	})
+}
+
+func TestAlertProcessor_BatchLimits(t *testing.T) {
+	eventTime := time.Now().UTC()
+	newAlerts := func(count int) []Alert {
+		alerts := make([]Alert, 0, count)
+		for i := 0; i < count; i++ {
+			alerts = append(alerts, Alert{
+				AlertID:       fmt.Sprintf("alert-%d", i),
+				AlertType:     AlertType{Name: "Alert"},
+				EventTime:     eventTime,
+				Headline:      fmt.Sprintf("Headline %d", i),
+				FirstAlertURL: fmt.Sprintf("https://example.com/alert/%d", i),
+			})
+		}
+		return alerts
+	}
+
+	t.Run("summarizes the oldest alerts of an oversized batch", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		var posted []string
+		var summaries []string
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				posted = append(posted, alert.AlertID)
+				return nil
+			},
+			PostMessageFn: func(message, channelID string) error {
+				assert.Equal(t, "test-channel-id", channelID)
+				summaries = append(summaries, message)
+				return nil
+			},
+		}
+
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+		processor.SetBatchLimits(0, 3)
+
+		count, err := processor.ProcessAlerts(context.Background(), newAlerts(5))
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, count)
+		assert.Equal(t, []string{"alert-2", "alert-3", "alert-4"}, posted)
+		require.Len(t, summaries, 1)
+		assert.Contains(t, summaries[0], "**2 older alerts from Test Backend were not posted individually** (batch limit: 3 alerts)")
+		assert.Contains(t, summaries[0], "[Headline 0](https://example.com/alert/0)")
+		assert.Contains(t, summaries[0], "[Headline 1](https://example.com/alert/1)")
+	})
+
+	t.Run("posts the alerts individually when the summary fails", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+		api.On("LogError", "Failed to post batch summary, posting the alerts individually", "channelId", "test-channel-id", "error", "channel archived").Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		var posted []string
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				posted = append(posted, alert.AlertID)
+				return nil
+			},
+			PostMessageFn: func(string, string) error {
+				return errors.New("channel archived")
+			},
+		}
+
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+		processor.SetBatchLimits(0, 3)
+
+		count, err := processor.ProcessAlerts(context.Background(), newAlerts(5))
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, count)
+		assert.Equal(t, []string{"alert-2", "alert-3", "alert-4", "alert-0", "alert-1"}, posted)
+	})
+
+	t.Run("preserves per-channel ordering when posting in parallel", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		var mu sync.Mutex
+		posted := make(map[string][]string)
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				posted[channelID] = append(posted[channelID], alert.AlertID)
+				return nil
+			},
+		}
+
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+		processor.SetBatchLimits(3, 0)
+
+		var posts []pendingPost
+		expected := make(map[string][]string)
+		for i := 0; i < 30; i++ {
+			channelID := fmt.Sprintf("channel-%d", i%4)
+			alertID := fmt.Sprintf("alert-%d", i)
+			posts = append(posts, pendingPost{alert: backend.Alert{AlertID: alertID}, channelID: channelID})
+			expected[channelID] = append(expected[channelID], alertID)
+		}
+
+		count, unposted := processor.postAll(context.Background(), posts)
+		assert.Equal(t, 30, count)
+		assert.Empty(t, unposted)
+		assert.Equal(t, expected, posted)
+	})
+}
+
+func TestSummarizeAlerts(t *testing.T) {
+	alerts := []backend.Alert{
+		{AlertType: "Flash", Headline: "Explosion", AlertURL: "https://example.com/1"},
+		{AlertType: "Alert", Headline: "Protest"},
+		{AlertType: "Flash", Headline: "Fire"},
+	}
+
+	summary := summarizeAlerts("Primary", alerts, 100)
+
+	assert.Equal(t, ":warning: **3 older alerts from Primary were not posted individually** (batch limit: 100 alerts)\n"+
+		"Flash: 2, Alert: 1\n"+
+		"* [Explosion](https://example.com/1)\n"+
+		"* Protest\n"+
+		"* Fire", summary)
+
+	var many []backend.Alert
+	for i := 0; i < maxSummarizedHeadlines+5; i++ {
+		many = append(many, backend.Alert{AlertType: "Alert", Headline: fmt.Sprintf("Headline %d", i)})
+	}
+	summary = summarizeAlerts("Primary", many, 100)
+	assert.Contains(t, summary, "* ...and 5 more")
+	assert.NotContains(t, summary, fmt.Sprintf("Headline %d", maxSummarizedHeadlines))
+}
+
+func TestAlertProcessor_ProcessAlerts_Cancelled(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Shutting down while the first alert is posted
+	var posted []string
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			posted = append(posted, alert.AlertID)
+			cancel()
+			return nil
+		},
+	}
+
+	mockDedup := NewMockDeduplicator()
+	processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", mockDedup, nil)
+
+	count, err := processor.ProcessAlerts(ctx, []Alert{
+		{AlertID: "alert-1", AlertType: AlertType{Name: "Flash"}, Headline: "Test Alert 1"},
+		{AlertID: "alert-2", AlertType: AlertType{Name: "Flash"}, Headline: "Test Alert 2"},
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"alert-1"}, posted)
+
+	// The unposted alert is processed again when it is fetched next
+	assert.False(t, mockDedup.RecordAlert("dataminr", "alert-1"))
+	assert.True(t, mockDedup.RecordAlert("dataminr", "alert-2"))
+}
+
+func TestAlertProcessor_Subscriptions(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	subscriptions := []backend.ChannelSubscription{
+		{ChannelID: "subscribed-all"},
+		{ChannelID: "subscribed-flash", AlertTypes: []string{"flash"}},
+		{ChannelID: "test-channel-id"},
+	}
+	data, err := json.Marshal(subscriptions)
+	require.NoError(t, err)
+	api.On("KVGet", "backend_test-backend-id_subscriptions").Return(data, nil).Once()
+
+	var mu sync.Mutex
+	posted := make(map[string][]string)
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			posted[channelID] = append(posted[channelID], alert.AlertID)
+			return nil
+		},
+	}
+
+	processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+	processor.SetSubscriptions(backend.NewSubscriptionStore(api))
+
+	count, err := processor.ProcessAlerts(context.Background(), []Alert{
+		{AlertID: "alert-1", AlertType: AlertType{Name: "Flash"}, Headline: "Test Alert 1"},
+		{AlertID: "alert-2", AlertType: AlertType{Name: "Urgent"}, Headline: "Test Alert 2"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "copies for subscribed channels aren't counted as new alerts")
+	assert.Equal(t, map[string][]string{
+		"test-channel-id":  {"alert-1", "alert-2"},
+		"subscribed-all":   {"alert-1", "alert-2"},
+		"subscribed-flash": {"alert-1"},
+	}, posted)
+}
+
+func TestAlertProcessor_RetrySubscribedCopies(t *testing.T) {
+	api := &plugintest.API{}
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	kvStore := mockKVStore(api)
+	subscriptions, err := json.Marshal([]backend.ChannelSubscription{{ChannelID: "subscribed-channel"}})
+	require.NoError(t, err)
+	kvStore["backend_test-backend-id_subscriptions"] = subscriptions
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	failing := true
+	posted := make(map[string][]string)
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			if failing && channelID == "subscribed-channel" {
+				return errors.New("post failed")
+			}
+			posted[channelID] = append(posted[channelID], alert.AlertID)
+			return nil
+		},
+	}
+
+	stateStore := NewStateStore(api, "test-id")
+	processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+	processor.SetPendingStore(stateStore)
+	processor.SetSubscriptions(backend.NewSubscriptionStore(api))
+
+	count, err := processor.ProcessAlerts(context.Background(), []Alert{
+		{AlertID: "alert-1", AlertType: AlertType{Name: "Alert"}, Headline: "Test Alert 1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// The failed copy stays in the pending queue as a subscribed copy
+	pending, err := stateStore.GetPendingAlerts()
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "subscribed-channel", pending[0].ChannelID)
+	assert.True(t, pending[0].Subscribed)
+
+	failing = false
+	processor.now = func() time.Time { return pending[0].RetryAt }
+	processor.RetryPendingAlerts(context.Background())
+
+	assert.Equal(t, map[string][]string{
+		"test-channel-id":    {"alert-1"},
+		"subscribed-channel": {"alert-1"},
+	}, posted)
+	assert.NotContains(t, kvStore, "backend_test-id_pending")
+}
+
+func TestAlertProcessor_TopicMutes(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	subscriptions, err := json.Marshal([]backend.ChannelSubscription{{ChannelID: "subscribed-channel"}})
+	require.NoError(t, err)
+	api.On("KVGet", "backend_test-backend-id_subscriptions").Return(subscriptions, nil).Once()
+
+	mutes, err := json.Marshal([]backend.TopicMute{{Topic: "flooding", Until: time.Now().Add(time.Hour)}})
+	require.NoError(t, err)
+	api.On("KVGet", "channel_test-channel-id_topic_mutes").Return(nil, nil).Once()
+	api.On("KVGet", "channel_subscribed-channel_topic_mutes").Return(mutes, nil).Once()
+
+	var mu sync.Mutex
+	posted := make(map[string][]string)
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			posted[channelID] = append(posted[channelID], alert.AlertID)
+			return nil
+		},
+	}
+
+	processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+	processor.SetSubscriptions(backend.NewSubscriptionStore(api))
+	processor.SetTopicMutes(backend.NewTopicMuteStore(api))
+
+	count, err := processor.ProcessAlerts(context.Background(), []Alert{
+		{AlertID: "alert-1", AlertType: AlertType{Name: "Flash"}, Headline: "Test Alert 1", AlertTopics: []AlertTopic{{Name: "Flooding"}}},
+		{AlertID: "alert-2", AlertType: AlertType{Name: "Urgent"}, Headline: "Test Alert 2"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, map[string][]string{
+		"test-channel-id":    {"alert-1", "alert-2"},
+		"subscribed-channel": {"alert-2"},
+	}, posted)
+}
+
+func TestAlertProcessor_AlertListMutes(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	subscriptions, err := json.Marshal([]backend.ChannelSubscription{{ChannelID: "subscribed-channel"}})
+	require.NoError(t, err)
+	api.On("KVGet", "backend_test-backend-id_subscriptions").Return(subscriptions, nil).Once()
+
+	backendMutes, err := json.Marshal([]backend.AlertListMute{{AlertList: "wildfires", Until: time.Now().Add(time.Hour)}})
+	require.NoError(t, err)
+	channelMutes, err := json.Marshal([]backend.AlertListMute{{AlertList: "gulf coast weather", Until: time.Now().Add(time.Hour)}})
+	require.NoError(t, err)
+	api.On("KVGet", "backend_test-backend-id_alert_list_mutes").Return(backendMutes, nil).Once()
+	api.On("KVGet", "channel_test-channel-id_alert_list_mutes").Return(nil, nil).Once()
+	api.On("KVGet", "channel_subscribed-channel_alert_list_mutes").Return(channelMutes, nil).Once()
+
+	var mu sync.Mutex
+	posted := make(map[string][]string)
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			posted[channelID] = append(posted[channelID], alert.AlertID)
+			return nil
+		},
+	}
+
+	processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+	processor.SetSubscriptions(backend.NewSubscriptionStore(api))
+	processor.SetAlertListMutes(backend.NewAlertListMuteStore(api))
+
+	count, err := processor.ProcessAlerts(context.Background(), []Alert{
+		{AlertID: "alert-1", AlertType: AlertType{Name: "Flash"}, Headline: "Test Alert 1", AlertLists: []AlertList{{Name: "Gulf Coast Weather"}}},
+		{AlertID: "alert-2", AlertType: AlertType{Name: "Urgent"}, Headline: "Test Alert 2", AlertLists: []AlertList{{Name: "Wildfires"}}},
+		{AlertID: "alert-3", AlertType: AlertType{Name: "Urgent"}, Headline: "Test Alert 3"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, map[string][]string{
+		"test-channel-id":    {"alert-1", "alert-3"},
+		"subscribed-channel": {"alert-3"},
+	}, posted)
+}
+
+func TestAlertProcessor_Priority(t *testing.T) {
+	newAlert := func(id, alertType string) Alert {
+		return Alert{AlertID: id, AlertType: AlertType{Name: alertType}, Headline: "Headline " + id}
+	}
+	alerts := []Alert{
+		newAlert("alert-1", "Alert"),
+		newAlert("alert-2", "Urgent"),
+		newAlert("alert-3", "Alert"),
+		newAlert("alert-4", "Flash"),
+		newAlert("alert-5", "Alert"),
+		newAlert("alert-6", "Flash"),
+	}
+
+	t.Run("posts Flash alerts first", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		var posted []string
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				posted = append(posted, alert.AlertID)
+				return nil
+			},
+		}
+
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+
+		count, err := processor.ProcessAlerts(context.Background(), alerts)
+		require.NoError(t, err)
+		assert.Equal(t, 6, count)
+		assert.Equal(t, []string{"alert-4", "alert-6", "alert-2", "alert-1", "alert-3", "alert-5"}, posted)
+	})
+
+	t.Run("summarizes the least urgent alerts of an oversized batch", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		var posted []string
+		var summaries []string
+		mockPoster := &MockPoster{
+			PostAlertFn: func(alert backend.Alert, channelID string) error {
+				posted = append(posted, alert.AlertID)
+				return nil
+			},
+			PostMessageFn: func(message, channelID string) error {
+				summaries = append(summaries, message)
+				return nil
+			},
+		}
+
+		processor := NewAlertProcessor(client, "test-backend-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+		processor.SetBatchLimits(0, 4)
+
+		count, err := processor.ProcessAlerts(context.Background(), alerts)
+		require.NoError(t, err)
+		assert.Equal(t, 6, count)
+		assert.Equal(t, []string{"alert-4", "alert-6", "alert-2", "alert-5"}, posted)
+		require.Len(t, summaries, 1)
+		assert.Contains(t, summaries[0], "Headline alert-1")
+		assert.Contains(t, summaries[0], "Headline alert-3")
+	})
+}
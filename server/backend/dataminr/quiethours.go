@@ -0,0 +1,81 @@
+package dataminr
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// QuietHoursGate decides whether alerts should be held back during quiet hours
+// and persists held alerts in the KV store so they survive plugin restarts
+type QuietHoursGate struct {
+	schedule   *backend.QuietHours
+	stateStore *StateStore
+	now        func() time.Time
+}
+
+// NewQuietHoursGate creates a new quiet hours gate for a backend.
+// Returns nil if no quiet hours are configured.
+func NewQuietHoursGate(schedule *backend.QuietHours, stateStore *StateStore) *QuietHoursGate {
+	if schedule == nil {
+		return nil
+	}
+
+	return &QuietHoursGate{
+		schedule:   schedule,
+		stateStore: stateStore,
+		now:        time.Now,
+	}
+}
+
+// IsActive reports whether quiet hours are currently in effect
+func (g *QuietHoursGate) IsActive() bool {
+	if g == nil {
+		return false
+	}
+	return g.schedule.IsActive(g.now())
+}
+
+// ShouldBuffer reports whether the alert must be held back instead of posted.
+// Flash alerts always post immediately.
+func (g *QuietHoursGate) ShouldBuffer(alert backend.Alert) bool {
+	if strings.EqualFold(alert.AlertType, "flash") {
+		return false
+	}
+	return g.IsActive()
+}
+
+// Buffer stores an alert until quiet hours end
+func (g *QuietHoursGate) Buffer(alert backend.Alert) error {
+	return g.stateStore.BufferAlert(alert)
+}
+
+// Held returns the buffered alerts once quiet hours have ended, oldest first. The alerts stay
+// buffered until released, so they are not lost if posting them is interrupted.
+// Returns nil while quiet hours are still active.
+func (g *QuietHoursGate) Held() ([]backend.Alert, error) {
+	if g == nil || g.IsActive() {
+		return nil, nil
+	}
+
+	alerts, err := g.stateStore.GetBufferedAlerts()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+	return alerts, nil
+}
+
+// Release removes held alerts from the buffer once they are posted or queued for posting.
+// Alerts buffered since they were read stay buffered.
+func (g *QuietHoursGate) Release(alerts []backend.Alert) error {
+	alertIDs := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		alertIDs = append(alertIDs, alert.AlertID)
+	}
+	return g.stateStore.RemoveBufferedAlerts(alertIDs)
+}
@@ -0,0 +1,49 @@
+package dataminr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// limitedBody reads a response body, failing with a PayloadTooLargeError as soon as more
+// than limit bytes are read instead of buffering the rest of the response
+type limitedBody struct {
+	r         io.Reader
+	limit     int64
+	remaining int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, l.tooLarge()
+	}
+	// Read one byte past the limit so a response of exactly limit bytes is accepted
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), l.tooLarge()
+	}
+	return n, err
+}
+
+func (l *limitedBody) tooLarge() error {
+	return &PayloadTooLargeError{
+		Limit:   l.limit,
+		Message: fmt.Sprintf("response exceeds the size limit of %d bytes", l.limit),
+	}
+}
+
+// decodeResponse streams the JSON body of a response into v, reading at most limit bytes.
+// A response that declares a larger Content-Length is rejected without reading it.
+func decodeResponse(resp *http.Response, limit int64, v any) error {
+	body := &limitedBody{r: resp.Body, limit: limit, remaining: limit}
+	if resp.ContentLength > limit {
+		return body.tooLarge()
+	}
+	return json.NewDecoder(body).Decode(v)
+}
@@ -0,0 +1,67 @@
+package dataminr
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingReader records how many bytes were read from it
+type countingReader struct {
+	r    io.Reader
+	read int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += n
+	return n, err
+}
+
+func TestDecodeResponse(t *testing.T) {
+	response := func(body string, contentLength int64) *http.Response {
+		return &http.Response{Body: io.NopCloser(strings.NewReader(body)), ContentLength: contentLength}
+	}
+
+	t.Run("responses within the limit are decoded", func(t *testing.T) {
+		body := `{"to":"cursor"}`
+		var decoded AlertsResponse
+		require.NoError(t, decodeResponse(response(body, -1), int64(len(body)), &decoded))
+		assert.Equal(t, "cursor", decoded.To)
+	})
+
+	t.Run("reading stops past the limit", func(t *testing.T) {
+		reader := &countingReader{r: strings.NewReader(`{"to":"` + strings.Repeat("x", 1<<20) + `"}`)}
+		var decoded AlertsResponse
+		err := decodeResponse(&http.Response{Body: io.NopCloser(reader), ContentLength: -1}, 100, &decoded)
+
+		var tooLarge *PayloadTooLargeError
+		require.True(t, errors.As(err, &tooLarge))
+		assert.Equal(t, int64(100), tooLarge.Limit)
+		assert.Equal(t, "response exceeds the size limit of 100 bytes", err.Error())
+		assert.Equal(t, 101, reader.read, "Only one byte past the limit should be read")
+	})
+
+	t.Run("a declared length past the limit is rejected without reading", func(t *testing.T) {
+		reader := &countingReader{r: strings.NewReader(`{"to":"cursor"}`)}
+		var decoded AlertsResponse
+		err := decodeResponse(&http.Response{Body: io.NopCloser(reader), ContentLength: 2048}, 1024, &decoded)
+
+		var tooLarge *PayloadTooLargeError
+		require.True(t, errors.As(err, &tooLarge))
+		assert.Zero(t, reader.read)
+	})
+
+	t.Run("malformed responses fail to decode", func(t *testing.T) {
+		var decoded AlertsResponse
+		err := decodeResponse(response(`{"to":`, -1), 1024, &decoded)
+		require.Error(t, err)
+		var tooLarge *PayloadTooLargeError
+		assert.False(t, errors.As(err, &tooLarge))
+	})
+}
@@ -0,0 +1,30 @@
+package dataminr
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// selfTestHeadlinePrefix marks the headline of self-test alerts so they aren't mistaken for real events
+const selfTestHeadlinePrefix = "[Self-test] "
+
+// SelfTestAlert returns a synthetic alert for a backend, normalized from the first alert of the
+// bundled simulator fixture, so alert posts can be checked end to end without calling the
+// Dataminr API. The alert gets a unique ID and the current event time.
+func SelfTestAlert(backendID, backendName string, now time.Time) (*backend.Alert, error) {
+	alerts, err := ParseSimulatorFixture(bundledFixture)
+	if err != nil {
+		return nil, err
+	}
+
+	canned := alerts[0]
+	canned.AlertID = fmt.Sprintf("selftest-%d", now.UnixNano())
+	canned.Headline = selfTestHeadlinePrefix + canned.Headline
+	canned.EventTime = now.UTC()
+
+	alert := NormalizeAlert(canned, backendName)
+	alert.BackendID = backendID
+	return alert, nil
+}
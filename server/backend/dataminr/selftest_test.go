@@ -0,0 +1,26 @@
+package dataminr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTestAlert(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	alert, err := SelfTestAlert("backend-1", "Weather Watch", now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "backend-1", alert.BackendID)
+	assert.Equal(t, "Weather Watch", alert.BackendName)
+	assert.Equal(t, "selftest-1792141200000000000", alert.AlertID)
+	assert.Equal(t, "[Self-test] Large fire reported at industrial warehouse, multiple fire crews responding", alert.Headline)
+	assert.Equal(t, "Flash", alert.AlertType)
+	assert.True(t, now.Equal(alert.EventTime))
+	assert.NotEmpty(t, alert.Topics)
+	require.NotNil(t, alert.Location)
+	assert.Equal(t, "Port of Oakland, Oakland, CA 94607, USA", alert.Location.Address)
+}
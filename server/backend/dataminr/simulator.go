@@ -0,0 +1,149 @@
+package dataminr
+
+import (
+	"context"
+	_ "embed" // Embed the bundled simulator fixture
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// kvKeySimulatorFixture stores an uploaded simulator fixture by name
+const kvKeySimulatorFixture = "simulator_fixture_%s"
+
+// MaxSimulatorFixtureBytes is the largest simulator fixture that can be uploaded
+const MaxSimulatorFixtureBytes = 1 << 20
+
+// bundledFixture holds the alerts replayed by simulator backends without an uploaded fixture
+//
+//go:embed fixtures/simulator_alerts.json
+var bundledFixture []byte
+
+// init registers the simulator backend factory
+func init() {
+	backend.RegisterBackendFactory(backend.SimulatorType, func(config backend.Config, api *pluginapi.Client, papi plugin.API, poster backend.AlertPoster, deduplicator backend.Deduplicator, disableCallback backend.DisableCallback, _ string) (backend.Backend, error) {
+		return NewSimulator(config, api, papi, poster, deduplicator, disableCallback)
+	})
+}
+
+// NewSimulator creates a backend that replays alerts from a fixture instead of polling the
+// Dataminr API. The alerts go through the same processor and poster as real alerts.
+func NewSimulator(config backend.Config, api *pluginapi.Client, papi plugin.API, poster backend.AlertPoster, deduplicator backend.Deduplicator, disableCallback backend.DisableCallback) (*Backend, error) {
+	if config.Type != backend.SimulatorType {
+		return nil, fmt.Errorf("invalid backend type: %s (expected: %s)", config.Type, backend.SimulatorType)
+	}
+	if config.ID == "" {
+		return nil, fmt.Errorf("backend ID is required")
+	}
+	if config.ChannelID == "" {
+		return nil, fmt.Errorf("channel ID is required")
+	}
+
+	fetcher := NewFixtureFetcher(papi, config.Simulator.FixtureName(), config.Simulator.BatchSize())
+	logger := backend.NewCapturingLogger(&api.Log, config.LogLevel, backend.LogCaptureLines)
+	return newBackend(config, api, papi, poster, deduplicator, disableCallback, NewStateStore(papi, config.ID), logger, fetcher), nil
+}
+
+// SimulatorFixtureKey returns the KV store key of an uploaded simulator fixture
+func SimulatorFixtureKey(name string) string {
+	return fmt.Sprintf(kvKeySimulatorFixture, name)
+}
+
+// ParseSimulatorFixture parses a simulator fixture, either a JSON array of alerts or an
+// alerts response object in the Dataminr API format
+func ParseSimulatorFixture(data []byte) ([]Alert, error) {
+	var alerts []Alert
+	if err := json.Unmarshal(data, &alerts); err != nil {
+		var response AlertsResponse
+		if responseErr := json.Unmarshal(data, &response); responseErr != nil {
+			return nil, fmt.Errorf("invalid simulator fixture: %w", err)
+		}
+		alerts = response.Alerts
+	}
+
+	if len(alerts) == 0 {
+		return nil, fmt.Errorf("simulator fixture contains no alerts")
+	}
+	for i, alert := range alerts {
+		if alert.AlertID == "" {
+			return nil, fmt.Errorf("simulator fixture alert %d is missing 'alertId'", i+1)
+		}
+	}
+
+	return alerts, nil
+}
+
+// FixtureFetcher replays the alerts of a simulator fixture in a loop, a batch per request.
+// The cursor is the number of alerts replayed so far. Replayed alerts get a unique ID and
+// the current event time, so they are neither deduplicated nor skipped as historical.
+type FixtureFetcher struct {
+	api       plugin.API
+	fixture   string
+	batchSize int
+	now       func() time.Time
+}
+
+// NewFixtureFetcher creates a fetcher replaying the named uploaded fixture, or the bundled
+// fixture if the name is empty
+func NewFixtureFetcher(api plugin.API, fixture string, batchSize int) *FixtureFetcher {
+	return &FixtureFetcher{
+		api:       api,
+		fixture:   fixture,
+		batchSize: batchSize,
+		now:       time.Now,
+	}
+}
+
+// FetchAlerts returns the next batch of fixture alerts after the cursor. Without a cursor
+// it returns no alerts, so catch-up starts the replay at the beginning of the fixture.
+// The fixture is loaded on every request, so an uploaded replacement is used right away.
+func (f *FixtureFetcher) FetchAlerts(_ context.Context, cursor string) (*AlertsResponse, error) {
+	alerts, err := f.loadFixture()
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor == "" {
+		return &AlertsResponse{To: "0"}, nil
+	}
+
+	// An unknown cursor (e.g., left over from a Dataminr backend) restarts the replay
+	position, err := strconv.Atoi(cursor)
+	if err != nil || position < 0 {
+		position = 0
+	}
+
+	now := f.now().UTC()
+	batch := make([]Alert, 0, f.batchSize)
+	for i := 0; i < f.batchSize; i++ {
+		replayed := alerts[(position+i)%len(alerts)]
+		replayed.AlertID = fmt.Sprintf("%s-%d", replayed.AlertID, position+i)
+		replayed.EventTime = now
+		batch = append(batch, replayed)
+	}
+
+	return &AlertsResponse{Alerts: batch, To: strconv.Itoa(position + f.batchSize)}, nil
+}
+
+// loadFixture returns the alerts of the uploaded fixture, or of the bundled fixture if none is configured
+func (f *FixtureFetcher) loadFixture() ([]Alert, error) {
+	if f.fixture == "" {
+		return ParseSimulatorFixture(bundledFixture)
+	}
+
+	data, appErr := f.api.KVGet(SimulatorFixtureKey(f.fixture))
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to load simulator fixture '%s': %w", f.fixture, appErr)
+	}
+	if data == nil {
+		return nil, fmt.Errorf("simulator fixture '%s' not found", f.fixture)
+	}
+
+	return ParseSimulatorFixture(data)
+}
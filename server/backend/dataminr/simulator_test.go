@@ -0,0 +1,203 @@
+package dataminr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestParseSimulatorFixture(t *testing.T) {
+	t.Run("bundled fixture", func(t *testing.T) {
+		alerts, err := ParseSimulatorFixture(bundledFixture)
+		require.NoError(t, err)
+		assert.NotEmpty(t, alerts)
+		for _, alert := range alerts {
+			assert.NotEmpty(t, alert.AlertID)
+			assert.NotEmpty(t, alert.Headline)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		data        string
+		wantIDs     []string
+		errContains string
+	}{
+		{
+			name:    "array of alerts",
+			data:    `[{"alertId":"a"},{"alertId":"b"}]`,
+			wantIDs: []string{"a", "b"},
+		},
+		{
+			name:    "alerts response",
+			data:    `{"alerts":[{"alertId":"a"}],"to":"cursor"}`,
+			wantIDs: []string{"a"},
+		},
+		{
+			name:        "invalid JSON",
+			data:        `not json`,
+			errContains: "invalid simulator fixture",
+		},
+		{
+			name:        "no alerts",
+			data:        `[]`,
+			errContains: "contains no alerts",
+		},
+		{
+			name:        "alert without ID",
+			data:        `[{"alertId":"a"},{"headline":"No ID"}]`,
+			errContains: "alert 2 is missing 'alertId'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts, err := ParseSimulatorFixture([]byte(tt.data))
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			ids := make([]string, 0, len(alerts))
+			for _, alert := range alerts {
+				ids = append(ids, alert.AlertID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestFixtureFetcher_FetchAlerts(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	fixture := []byte(`[{"alertId":"a","eventTime":1000},{"alertId":"b"},{"alertId":"c"}]`)
+
+	newFetcher := func(batchSize int) *FixtureFetcher {
+		api := &plugintest.API{}
+		api.On("KVGet", SimulatorFixtureKey("demo")).Return(fixture, nil)
+		fetcher := NewFixtureFetcher(api, "demo", batchSize)
+		fetcher.now = func() time.Time { return now }
+		return fetcher
+	}
+
+	t.Run("empty cursor ends catch-up without alerts", func(t *testing.T) {
+		response, err := newFetcher(2).FetchAlerts(context.Background(), "")
+		require.NoError(t, err)
+		assert.Empty(t, response.Alerts)
+		assert.Equal(t, "0", response.To)
+	})
+
+	tests := []struct {
+		name    string
+		cursor  string
+		batch   int
+		wantIDs []string
+		wantTo  string
+	}{
+		{name: "first batch", cursor: "0", batch: 2, wantIDs: []string{"a-0", "b-1"}, wantTo: "2"},
+		{name: "wraps around the fixture", cursor: "2", batch: 2, wantIDs: []string{"c-2", "a-3"}, wantTo: "4"},
+		{name: "batch larger than the fixture", cursor: "0", batch: 4, wantIDs: []string{"a-0", "b-1", "c-2", "a-3"}, wantTo: "4"},
+		{name: "unknown cursor restarts the replay", cursor: "dataminr-cursor", batch: 1, wantIDs: []string{"a-0"}, wantTo: "1"},
+		{name: "negative cursor restarts the replay", cursor: "-5", batch: 1, wantIDs: []string{"a-0"}, wantTo: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := newFetcher(tt.batch).FetchAlerts(context.Background(), tt.cursor)
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(response.Alerts))
+			for _, alert := range response.Alerts {
+				ids = append(ids, alert.AlertID)
+				assert.Equal(t, now, alert.EventTime)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantTo, response.To)
+		})
+	}
+
+	t.Run("bundled fixture", func(t *testing.T) {
+		fetcher := NewFixtureFetcher(&plugintest.API{}, "", 3)
+		response, err := fetcher.FetchAlerts(context.Background(), "0")
+		require.NoError(t, err)
+		assert.Len(t, response.Alerts, 3)
+		assert.Equal(t, "sim-flash-fire-0", response.Alerts[0].AlertID)
+	})
+
+	t.Run("missing uploaded fixture", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", SimulatorFixtureKey("missing")).Return(nil, nil)
+		_, err := NewFixtureFetcher(api, "missing", 3).FetchAlerts(context.Background(), "0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "simulator fixture 'missing' not found")
+	})
+
+	t.Run("KV error", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", SimulatorFixtureKey("demo")).Return(nil, model.NewAppError("KVGet", "error", nil, "", 500))
+		_, err := NewFixtureFetcher(api, "demo", 3).FetchAlerts(context.Background(), "0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load simulator fixture 'demo'")
+	})
+}
+
+func TestNewSimulator(t *testing.T) {
+	api := &plugintest.API{}
+	client := pluginapi.NewClient(api, nil)
+	validConfig := backend.Config{
+		ID:                  "test-id-123",
+		Name:                "Demo",
+		Type:                backend.SimulatorType,
+		Enabled:             true,
+		ChannelID:           "channel123",
+		PollIntervalSeconds: 30,
+		Simulator:           &backend.SimulatorSettings{Fixture: "demo", AlertsPerPoll: 5},
+	}
+
+	t.Run("valid config", func(t *testing.T) {
+		b, err := NewSimulator(validConfig, client, api, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Nil(t, b.authManager)
+		assert.Nil(t, b.apiClient)
+
+		fetcher, ok := b.poller.client.(*FixtureFetcher)
+		require.True(t, ok)
+		assert.Equal(t, "demo", fetcher.fixture)
+		assert.Equal(t, 5, fetcher.batchSize)
+	})
+
+	t.Run("registered factory", func(t *testing.T) {
+		b, err := backend.Create(validConfig, client, api, nil, nil, nil, "")
+		require.NoError(t, err)
+		assert.Equal(t, backend.SimulatorType, b.GetType())
+	})
+
+	tests := []struct {
+		name        string
+		modify      func(c *backend.Config)
+		errContains string
+	}{
+		{name: "wrong type", modify: func(c *backend.Config) { c.Type = "dataminr" }, errContains: "invalid backend type"},
+		{name: "missing ID", modify: func(c *backend.Config) { c.ID = "" }, errContains: "backend ID is required"},
+		{name: "missing channel", modify: func(c *backend.Config) { c.ChannelID = "" }, errContains: "channel ID is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validConfig
+			tt.modify(&config)
+			_, err := NewSimulator(config, client, api, nil, nil, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
@@ -3,19 +3,43 @@ package dataminr
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
 
 // KV store key format strings
 const (
 	kvKeyAuthToken   = "backend_%s_auth"         //nolint:gosec // False positive: this is a key name format, not a credential
+	kvKeyPollState   = "backend_%s_state"        //nolint:gosec
+	kvKeyQuietBuffer = "backend_%s_quiet_buffer" //nolint:gosec
+	kvKeyPause       = "backend_%s_pause"        //nolint:gosec
+	kvKeyCooldown    = "backend_%s_cooldown"     //nolint:gosec
+	kvKeyBackoff     = "backend_%s_backoff"      //nolint:gosec
+	kvKeyErrors      = "backend_%s_errors"       //nolint:gosec
+	kvKeyPending     = "backend_%s_pending"      //nolint:gosec
+	kvKeyPollHistory = "backend_%s_poll_history" //nolint:gosec
+	kvKeyCursorReset = "backend_%s_cursor_reset" //nolint:gosec
+	kvKeyFailed      = "backend_%s_failed"       //nolint:gosec
+	kvKeyCredentials = "backend_%s_credentials"  //nolint:gosec
+)
+
+// maxUpdateAttempts bounds the compare-and-set retries of a state update
+const maxUpdateAttempts = 5
+
+// Legacy KV key format strings of the poll state fields, each stored on its own before the
+// poll state was kept in a single document. Read once to migrate, then deleted.
+const (
 	kvKeyCursor      = "backend_%s_cursor"       //nolint:gosec
 	kvKeyLastPoll    = "backend_%s_last_poll"    //nolint:gosec
 	kvKeyLastSuccess = "backend_%s_last_success" //nolint:gosec
 	kvKeyFailures    = "backend_%s_failures"     //nolint:gosec
 	kvKeyLastError   = "backend_%s_last_error"   //nolint:gosec
+	kvKeyPhase       = "backend_%s_phase"        //nolint:gosec
 )
 
 // StateStore manages backend state persistence in the Mattermost KV store
@@ -23,6 +47,15 @@ const (
 type StateStore struct {
 	api       plugin.API
 	backendID string // UUID of the backend this state store manages
+
+	// pollStateMu guards the poll state batch and migration check
+	pollStateMu sync.Mutex
+
+	// batch holds the poll state changed since BeginBatch, saved by Flush; nil when not batching
+	batch *pollStateBatch
+
+	// migrated is set once the legacy poll state keys were checked
+	migrated bool
 }
 
 // NewStateStore creates a new state store for a specific backend
@@ -81,201 +114,566 @@ func (s *StateStore) GetAuthToken() (string, time.Time, error) {
 	return state.Token, state.Expiry, nil
 }
 
-// SaveCursor stores the pagination cursor for the next API request
-func (s *StateStore) SaveCursor(cursor string) error {
-	key := fmt.Sprintf(kvKeyCursor, s.backendID)
-	if err := s.api.KVSet(key, []byte(cursor)); err != nil {
-		return fmt.Errorf("failed to save cursor: %w", err)
+// RequestCursorReset marks the cursor to be discarded at the start of the next poll cycle.
+// Deleting the cursor directly could be undone by a poll cycle in progress saving its cursor.
+func (s *StateStore) RequestCursorReset() error {
+	key := fmt.Sprintf(kvKeyCursorReset, s.backendID)
+	if err := s.api.KVSet(key, []byte("1")); err != nil {
+		return fmt.Errorf("failed to request cursor reset: %w", err)
 	}
 	return nil
 }
 
-// GetCursor retrieves the stored pagination cursor
-// Returns empty string if no cursor is stored
-func (s *StateStore) GetCursor() (string, error) {
-	key := fmt.Sprintf(kvKeyCursor, s.backendID)
+// IsCursorResetPending reports whether the cursor is discarded at the start of the next poll cycle
+func (s *StateStore) IsCursorResetPending() (bool, error) {
+	key := fmt.Sprintf(kvKeyCursorReset, s.backendID)
 	data, err := s.api.KVGet(key)
 	if err != nil {
-		return "", fmt.Errorf("failed to get cursor: %w", err)
+		return false, fmt.Errorf("failed to get cursor reset request: %w", err)
+	}
+	return data != nil, nil
+}
+
+// ApplyCursorReset discards the cursor if a reset was requested and reports whether it did
+func (s *StateStore) ApplyCursorReset() (bool, error) {
+	pending, err := s.IsCursorResetPending()
+	if err != nil || !pending {
+		return false, err
+	}
+
+	if err := s.updatePollState(func(state *PollState) {
+		state.Cursor = ""
+		state.WindowStart = time.Time{}
+		state.CatchUp = nil
+	}); err != nil {
+		return false, fmt.Errorf("failed to reset cursor: %w", err)
+	}
+	if err := s.api.KVDelete(fmt.Sprintf(kvKeyCursorReset, s.backendID)); err != nil {
+		return false, fmt.Errorf("failed to clear cursor reset request: %w", err)
+	}
+
+	return true, nil
+}
+
+// ClearAuthToken removes the cached authentication token
+func (s *StateStore) ClearAuthToken() error {
+	key := fmt.Sprintf(kvKeyAuthToken, s.backendID)
+	if err := s.api.KVDelete(key); err != nil {
+		return fmt.Errorf("failed to clear auth token: %w", err)
+	}
+	return nil
+}
+
+// RecordError adds a polling error to the recent error history, keeping the newest MaxRecentErrors
+func (s *StateStore) RecordError(errMsg string, at time.Time) error {
+	records, err := s.GetRecentErrors()
+	if err != nil {
+		return err
+	}
+
+	records = append([]backend.ErrorRecord{{Time: at, Message: errMsg}}, records...)
+	if len(records) > backend.MaxRecentErrors {
+		records = records[:backend.MaxRecentErrors]
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recent errors: %w", err)
+	}
+
+	key := fmt.Sprintf(kvKeyErrors, s.backendID)
+	if err := s.api.KVSet(key, data); err != nil {
+		return fmt.Errorf("failed to save recent errors: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecentErrors retrieves the recent polling errors, newest first
+func (s *StateStore) GetRecentErrors() ([]backend.ErrorRecord, error) {
+	key := fmt.Sprintf(kvKeyErrors, s.backendID)
+	data, err := s.api.KVGet(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent errors: %w", err)
 	}
 
 	if data == nil {
-		return "", nil
+		return nil, nil
 	}
 
-	return string(data), nil
+	var records []backend.ErrorRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal recent errors: %w", err)
+	}
+
+	return records, nil
 }
 
-// SaveLastPoll stores the timestamp of the last poll attempt
-func (s *StateStore) SaveLastPoll(t time.Time) error {
-	key := fmt.Sprintf(kvKeyLastPoll, s.backendID)
-	data, err := json.Marshal(t)
+// RecordPollSample appends a poll cycle outcome to the poll history, dropping samples
+// beyond the retention period and sample limit
+func (s *StateStore) RecordPollSample(sample backend.PollSample, now time.Time) error {
+	samples, err := s.GetPollHistory()
 	if err != nil {
-		return fmt.Errorf("failed to marshal last poll time: %w", err)
+		return err
 	}
 
+	samples = backend.TrimPollHistory(append(samples, sample), now)
+
+	data, err := json.Marshal(samples)
+	if err != nil {
+		return fmt.Errorf("failed to marshal poll history: %w", err)
+	}
+
+	key := fmt.Sprintf(kvKeyPollHistory, s.backendID)
 	if err := s.api.KVSet(key, data); err != nil {
-		return fmt.Errorf("failed to save last poll time: %w", err)
+		return fmt.Errorf("failed to save poll history: %w", err)
 	}
 
 	return nil
 }
 
-// GetLastPoll retrieves the timestamp of the last poll attempt
-// Returns zero time if no poll time is stored
-func (s *StateStore) GetLastPoll() (time.Time, error) {
-	key := fmt.Sprintf(kvKeyLastPoll, s.backendID)
+// GetPollHistory retrieves the recorded poll cycle outcomes, oldest first
+func (s *StateStore) GetPollHistory() ([]backend.PollSample, error) {
+	key := fmt.Sprintf(kvKeyPollHistory, s.backendID)
 	data, err := s.api.KVGet(key)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to get last poll time: %w", err)
+		return nil, fmt.Errorf("failed to get poll history: %w", err)
 	}
 
 	if data == nil {
-		return time.Time{}, nil
+		return nil, nil
+	}
+
+	var samples []backend.PollSample
+	if err := json.Unmarshal(data, &samples); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal poll history: %w", err)
+	}
+
+	return samples, nil
+}
+
+// BufferAlert appends a normalized alert to the quiet hours buffer
+func (s *StateStore) BufferAlert(alert backend.Alert) error {
+	return s.updateBufferedAlerts(func(alerts []backend.Alert) []backend.Alert {
+		return append(alerts, alert)
+	})
+}
+
+// GetBufferedAlerts retrieves the alerts held in the quiet hours buffer, oldest first
+// Returns an empty slice if nothing is buffered
+func (s *StateStore) GetBufferedAlerts() ([]backend.Alert, error) {
+	key := fmt.Sprintf(kvKeyQuietBuffer, s.backendID)
+	data, err := s.api.KVGet(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get buffered alerts: %w", err)
+	}
+
+	return unmarshalBufferedAlerts(data)
+}
+
+// RemoveBufferedAlerts removes alerts from the quiet hours buffer, keeping alerts buffered
+// since they were read
+func (s *StateStore) RemoveBufferedAlerts(alertIDs []string) error {
+	removed := make(map[string]bool, len(alertIDs))
+	for _, alertID := range alertIDs {
+		removed[alertID] = true
+	}
+
+	return s.updateBufferedAlerts(func(alerts []backend.Alert) []backend.Alert {
+		remaining := alerts[:0]
+		for _, alert := range alerts {
+			if !removed[alert.AlertID] {
+				remaining = append(remaining, alert)
+			}
+		}
+		return remaining
+	})
+}
+
+// updateBufferedAlerts applies a change to the quiet hours buffer with compare-and-set, so
+// alerts buffered by a poll cycle on another node are not lost
+func (s *StateStore) updateBufferedAlerts(update func([]backend.Alert) []backend.Alert) error {
+	return s.compareAndSet(fmt.Sprintf(kvKeyQuietBuffer, s.backendID), func(oldData []byte) ([]byte, error) {
+		alerts, err := unmarshalBufferedAlerts(oldData)
+		if err != nil {
+			return nil, err
+		}
+
+		alerts = update(alerts)
+		if len(alerts) == 0 {
+			return nil, nil
+		}
+
+		data, err := json.Marshal(alerts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal buffered alerts: %w", err)
+		}
+		return data, nil
+	})
+}
+
+// unmarshalBufferedAlerts decodes the stored quiet hours buffer
+func unmarshalBufferedAlerts(data []byte) ([]backend.Alert, error) {
+	if data == nil {
+		return []backend.Alert{}, nil
+	}
+
+	var alerts []backend.Alert
+	if err := json.Unmarshal(data, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal buffered alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// compareAndSet replaces the value of a key with the result of update, retrying when another
+// node changed the value in between. A nil result deletes the key.
+func (s *StateStore) compareAndSet(key string, update func(oldData []byte) ([]byte, error)) error {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		oldData, appErr := s.api.KVGet(key)
+		if appErr != nil {
+			return fmt.Errorf("failed to get %s: %w", key, appErr)
+		}
+
+		newData, err := update(oldData)
+		if err != nil {
+			return err
+		}
+
+		saved, appErr := s.api.KVSetWithOptions(key, newData, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to save %s: %w", key, appErr)
+		}
+		if saved {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to save %s: too many concurrent updates", key)
+}
+
+// PendingAlert is an alert accepted for posting that has not been posted yet
+type PendingAlert struct {
+	Alert     backend.Alert `json:"alert"`
+	ChannelID string        `json:"channelId"`
+	Attempts  int           `json:"attempts,omitempty"`
+
+	// Subscribed marks the copy of an alert for a channel subscribed to the backend,
+	// checkpointed with the alert for the configured channel
+	Subscribed bool `json:"subscribed,omitempty"`
+
+	// LastError is the error of the last failed attempt to post the alert
+	LastError string `json:"lastError,omitempty"`
+
+	// RetryAt is when posting the alert is attempted again after a failure (zero retries
+	// at the next poll cycle)
+	RetryAt time.Time `json:"retryAt,omitempty"`
+}
+
+// SavePendingAlerts replaces the alerts waiting to be posted (an empty list deletes the key)
+func (s *StateStore) SavePendingAlerts(alerts []PendingAlert) error {
+	key := fmt.Sprintf(kvKeyPending, s.backendID)
+	if len(alerts) == 0 {
+		if err := s.api.KVDelete(key); err != nil {
+			return fmt.Errorf("failed to clear pending alerts: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending alerts: %w", err)
+	}
+
+	if err := s.api.KVSet(key, data); err != nil {
+		return fmt.Errorf("failed to save pending alerts: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingAlerts retrieves the alerts waiting to be posted, oldest first
+// Returns an empty slice if nothing is pending
+func (s *StateStore) GetPendingAlerts() ([]PendingAlert, error) {
+	key := fmt.Sprintf(kvKeyPending, s.backendID)
+	data, err := s.api.KVGet(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending alerts: %w", err)
+	}
+
+	if data == nil {
+		return []PendingAlert{}, nil
 	}
 
-	var t time.Time
-	if err := json.Unmarshal(data, &t); err != nil {
-		return time.Time{}, fmt.Errorf("failed to unmarshal last poll time: %w", err)
+	var alerts []PendingAlert
+	if err := json.Unmarshal(data, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pending alerts: %w", err)
 	}
 
-	return t, nil
+	return alerts, nil
 }
 
-// IncrementFailures increments the consecutive failures counter and returns the new count
-func (s *StateStore) IncrementFailures() (int, error) {
-	count, err := s.GetFailures()
+// RecordFailedDelivery adds an alert dropped after failing to post to the failed deliveries,
+// keeping the newest MaxFailedDeliveries
+func (s *StateStore) RecordFailedDelivery(delivery backend.FailedDelivery) error {
+	deliveries, err := s.GetFailedDeliveries()
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	count++
+	deliveries = append([]backend.FailedDelivery{delivery}, deliveries...)
+	if len(deliveries) > backend.MaxFailedDeliveries {
+		deliveries = deliveries[:backend.MaxFailedDeliveries]
+	}
 
-	key := fmt.Sprintf(kvKeyFailures, s.backendID)
-	data, err := json.Marshal(count)
+	data, err := json.Marshal(deliveries)
 	if err != nil {
-		return 0, fmt.Errorf("failed to marshal failures count: %w", err)
+		return fmt.Errorf("failed to marshal failed deliveries: %w", err)
 	}
 
+	key := fmt.Sprintf(kvKeyFailed, s.backendID)
 	if err := s.api.KVSet(key, data); err != nil {
-		return 0, fmt.Errorf("failed to save failures count: %w", err)
+		return fmt.Errorf("failed to save failed deliveries: %w", err)
 	}
 
-	return count, nil
+	return nil
 }
 
-// ResetFailures resets the consecutive failures counter to zero
-func (s *StateStore) ResetFailures() error {
-	key := fmt.Sprintf(kvKeyFailures, s.backendID)
-	data, err := json.Marshal(0)
+// GetFailedDeliveries retrieves the alerts dropped after failing to post, newest first
+func (s *StateStore) GetFailedDeliveries() ([]backend.FailedDelivery, error) {
+	key := fmt.Sprintf(kvKeyFailed, s.backendID)
+	data, err := s.api.KVGet(key)
 	if err != nil {
-		return fmt.Errorf("failed to marshal failures count: %w", err)
+		return nil, fmt.Errorf("failed to get failed deliveries: %w", err)
+	}
+
+	if data == nil {
+		return nil, nil
 	}
 
+	var deliveries []backend.FailedDelivery
+	if err := json.Unmarshal(data, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal failed deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// ClearFailedDeliveries removes the failed deliveries
+func (s *StateStore) ClearFailedDeliveries() error {
+	key := fmt.Sprintf(kvKeyFailed, s.backendID)
+	if err := s.api.KVDelete(key); err != nil {
+		return fmt.Errorf("failed to clear failed deliveries: %w", err)
+	}
+	return nil
+}
+
+// SaveCredentialCheck stores the outcome of the last credential check
+func (s *StateStore) SaveCredentialCheck(check backend.CredentialCheck) error {
+	data, err := json.Marshal(check)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credential check: %w", err)
+	}
+
+	key := fmt.Sprintf(kvKeyCredentials, s.backendID)
 	if err := s.api.KVSet(key, data); err != nil {
-		return fmt.Errorf("failed to reset failures count: %w", err)
+		return fmt.Errorf("failed to save credential check: %w", err)
 	}
 
 	return nil
 }
 
-// GetFailures retrieves the current consecutive failures count
-// Returns 0 if no count is stored
-func (s *StateStore) GetFailures() (int, error) {
-	key := fmt.Sprintf(kvKeyFailures, s.backendID)
+// GetCredentialCheck retrieves the outcome of the last credential check
+// Returns nil if the credentials were never checked
+func (s *StateStore) GetCredentialCheck() (*backend.CredentialCheck, error) {
+	key := fmt.Sprintf(kvKeyCredentials, s.backendID)
 	data, err := s.api.KVGet(key)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get failures count: %w", err)
+		return nil, fmt.Errorf("failed to get credential check: %w", err)
 	}
 
 	if data == nil {
-		return 0, nil
+		return nil, nil
 	}
 
-	var count int
-	if err := json.Unmarshal(data, &count); err != nil {
-		return 0, fmt.Errorf("failed to unmarshal failures count: %w", err)
+	var check backend.CredentialCheck
+	if err := json.Unmarshal(data, &check); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal credential check: %w", err)
 	}
 
-	return count, nil
+	return &check, nil
+}
+
+// PauseState represents a stored polling pause
+type PauseState struct {
+	Until time.Time `json:"until"`
 }
 
-// SaveLastSuccess stores the timestamp of the last successful poll
-func (s *StateStore) SaveLastSuccess(t time.Time) error {
-	key := fmt.Sprintf(kvKeyLastSuccess, s.backendID)
-	data, err := json.Marshal(t)
+// SavePause pauses polling until the given time (zero pauses until cleared)
+func (s *StateStore) SavePause(until time.Time) error {
+	data, err := json.Marshal(PauseState{Until: until})
 	if err != nil {
-		return fmt.Errorf("failed to marshal last success time: %w", err)
+		return fmt.Errorf("failed to marshal pause state: %w", err)
 	}
 
+	key := fmt.Sprintf(kvKeyPause, s.backendID)
 	if err := s.api.KVSet(key, data); err != nil {
-		return fmt.Errorf("failed to save last success time: %w", err)
+		return fmt.Errorf("failed to save pause state: %w", err)
 	}
 
 	return nil
 }
 
-// GetLastSuccess retrieves the timestamp of the last successful poll
-// Returns zero time if no success time is stored
-func (s *StateStore) GetLastSuccess() (time.Time, error) {
-	key := fmt.Sprintf(kvKeyLastSuccess, s.backendID)
+// GetPause reports whether polling is paused at the given time and when the pause ends.
+// An expired pause is reported as not paused.
+func (s *StateStore) GetPause(now time.Time) (bool, time.Time, error) {
+	key := fmt.Sprintf(kvKeyPause, s.backendID)
 	data, err := s.api.KVGet(key)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to get last success time: %w", err)
+		return false, time.Time{}, fmt.Errorf("failed to get pause state: %w", err)
 	}
 
 	if data == nil {
-		return time.Time{}, nil
+		return false, time.Time{}, nil
+	}
+
+	var state PauseState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return false, time.Time{}, fmt.Errorf("failed to unmarshal pause state: %w", err)
+	}
+
+	if !state.Until.IsZero() && !now.Before(state.Until) {
+		return false, time.Time{}, nil
+	}
+
+	return true, state.Until, nil
+}
+
+// ClearPause removes the polling pause
+func (s *StateStore) ClearPause() error {
+	key := fmt.Sprintf(kvKeyPause, s.backendID)
+	if err := s.api.KVDelete(key); err != nil {
+		return fmt.Errorf("failed to clear pause state: %w", err)
+	}
+	return nil
+}
+
+// CooldownState tracks the circuit breaker cool-down of a failing backend
+type CooldownState struct {
+	// Until is when the current cool-down ends and the next probe poll runs
+	Until time.Time `json:"until"`
+
+	// Cycles is the number of consecutive cool-down cycles
+	Cycles int `json:"cycles"`
+}
+
+// SaveCooldown stores the circuit breaker cool-down state
+func (s *StateStore) SaveCooldown(state CooldownState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cool-down state: %w", err)
+	}
+
+	key := fmt.Sprintf(kvKeyCooldown, s.backendID)
+	if err := s.api.KVSet(key, data); err != nil {
+		return fmt.Errorf("failed to save cool-down state: %w", err)
+	}
+
+	return nil
+}
+
+// GetCooldown retrieves the circuit breaker cool-down state
+// Returns the zero state if the backend is not cooling down
+func (s *StateStore) GetCooldown() (CooldownState, error) {
+	key := fmt.Sprintf(kvKeyCooldown, s.backendID)
+	data, err := s.api.KVGet(key)
+	if err != nil {
+		return CooldownState{}, fmt.Errorf("failed to get cool-down state: %w", err)
 	}
 
-	var t time.Time
-	if err := json.Unmarshal(data, &t); err != nil {
-		return time.Time{}, fmt.Errorf("failed to unmarshal last success time: %w", err)
+	if data == nil {
+		return CooldownState{}, nil
+	}
+
+	var state CooldownState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return CooldownState{}, fmt.Errorf("failed to unmarshal cool-down state: %w", err)
 	}
 
-	return t, nil
+	return state, nil
 }
 
-// SaveLastError stores the error message from the most recent failure
-func (s *StateStore) SaveLastError(errMsg string) error {
-	key := fmt.Sprintf(kvKeyLastError, s.backendID)
-	if err := s.api.KVSet(key, []byte(errMsg)); err != nil {
-		return fmt.Errorf("failed to save last error: %w", err)
+// ClearCooldown removes the circuit breaker cool-down state
+func (s *StateStore) ClearCooldown() error {
+	key := fmt.Sprintf(kvKeyCooldown, s.backendID)
+	if err := s.api.KVDelete(key); err != nil {
+		return fmt.Errorf("failed to clear cool-down state: %w", err)
 	}
 	return nil
 }
 
-// GetLastError retrieves the error message from the most recent failure
-// Returns empty string if no error is stored
-func (s *StateStore) GetLastError() (string, error) {
-	key := fmt.Sprintf(kvKeyLastError, s.backendID)
+// SaveBackoff stores when the rate limit back-off of the backend ends, so the node running the
+// next poll cycle honors it
+func (s *StateStore) SaveBackoff(until time.Time) error {
+	data, err := json.Marshal(until)
+	if err != nil {
+		return fmt.Errorf("failed to marshal back-off state: %w", err)
+	}
+
+	key := fmt.Sprintf(kvKeyBackoff, s.backendID)
+	if err := s.api.KVSet(key, data); err != nil {
+		return fmt.Errorf("failed to save back-off state: %w", err)
+	}
+
+	return nil
+}
+
+// GetBackoff retrieves when the rate limit back-off of the backend ends
+// Returns the zero time if the backend never backed off
+func (s *StateStore) GetBackoff() (time.Time, error) {
+	key := fmt.Sprintf(kvKeyBackoff, s.backendID)
 	data, err := s.api.KVGet(key)
 	if err != nil {
-		return "", fmt.Errorf("failed to get last error: %w", err)
+		return time.Time{}, fmt.Errorf("failed to get back-off state: %w", err)
 	}
 
 	if data == nil {
-		return "", nil
+		return time.Time{}, nil
+	}
+
+	var until time.Time
+	if err := json.Unmarshal(data, &until); err != nil {
+		return time.Time{}, fmt.Errorf("failed to unmarshal back-off state: %w", err)
 	}
 
-	return string(data), nil
+	return until, nil
+}
+
+// ClearBackoff removes the rate limit back-off state
+func (s *StateStore) ClearBackoff() error {
+	key := fmt.Sprintf(kvKeyBackoff, s.backendID)
+	if err := s.api.KVDelete(key); err != nil {
+		return fmt.Errorf("failed to clear back-off state: %w", err)
+	}
+	return nil
 }
 
 // ClearOperationalState removes cursor and auth token from the KV store
 // This preserves failure tracking state for status display while ensuring
 // a fresh start when a disabled backend is eventually re-enabled
 func (s *StateStore) ClearOperationalState() error {
-	keys := []string{
-		fmt.Sprintf(kvKeyAuthToken, s.backendID),
-		fmt.Sprintf(kvKeyCursor, s.backendID),
+	if err := s.ClearAuthToken(); err != nil {
+		return err
 	}
-
-	for _, key := range keys {
-		if err := s.api.KVDelete(key); err != nil {
-			return fmt.Errorf("failed to delete key %s: %w", key, err)
-		}
+	if err := s.updatePollState(func(state *PollState) {
+		state.Cursor = ""
+		state.WindowStart = time.Time{}
+		state.CatchUp = nil
+	}); err != nil {
+		return fmt.Errorf("failed to clear cursor: %w", err)
 	}
-
 	return nil
 }
 
@@ -284,11 +682,23 @@ func (s *StateStore) ClearOperationalState() error {
 func (s *StateStore) ClearAll() error {
 	keys := []string{
 		fmt.Sprintf(kvKeyAuthToken, s.backendID),
+		fmt.Sprintf(kvKeyPollState, s.backendID),
 		fmt.Sprintf(kvKeyCursor, s.backendID),
 		fmt.Sprintf(kvKeyLastPoll, s.backendID),
 		fmt.Sprintf(kvKeyLastSuccess, s.backendID),
 		fmt.Sprintf(kvKeyFailures, s.backendID),
 		fmt.Sprintf(kvKeyLastError, s.backendID),
+		fmt.Sprintf(kvKeyQuietBuffer, s.backendID),
+		fmt.Sprintf(kvKeyPause, s.backendID),
+		fmt.Sprintf(kvKeyCooldown, s.backendID),
+		fmt.Sprintf(kvKeyBackoff, s.backendID),
+		fmt.Sprintf(kvKeyErrors, s.backendID),
+		fmt.Sprintf(kvKeyPending, s.backendID),
+		fmt.Sprintf(kvKeyPollHistory, s.backendID),
+		fmt.Sprintf(kvKeyPhase, s.backendID),
+		fmt.Sprintf(kvKeyCursorReset, s.backendID),
+		fmt.Sprintf(kvKeyFailed, s.backendID),
+		fmt.Sprintf(kvKeyCredentials, s.backendID),
 	}
 
 	for _, key := range keys {
@@ -1,13 +1,19 @@
 package dataminr
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
 
 func TestStateStore_AuthToken(t *testing.T) {
@@ -74,172 +80,390 @@ func TestStateStore_AuthToken(t *testing.T) {
 	})
 }
 
-func TestStateStore_Cursor(t *testing.T) {
-	t.Run("save and retrieve cursor", func(t *testing.T) {
-		api := &plugintest.API{}
-		backendID := "test-backend-456"
-		store := NewStateStore(api, backendID)
+// mockKVStore backs the KV methods of a mock API with a map and returns the map
+func mockKVStore(api *plugintest.API) map[string][]byte {
+	return mockKVStoreFrom(api, make(map[string][]byte))
+}
 
-		cursor := "cursor_abc123xyz"
-		expectedKey := "backend_test-backend-456_cursor"
+// mockKVStoreFrom backs the KV operations of the mock API with the given map, which holds the
+// initially stored values
+func mockKVStoreFrom(api *plugintest.API, kvStore map[string][]byte) map[string][]byte {
+	api.On("KVSet", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		kvStore[args.String(0)] = args.Get(1).([]byte)
+	}).Return(nil).Maybe()
+	api.On("KVGet", mock.Anything).Return(func(key string) []byte {
+		return kvStore[key]
+	}, nil).Maybe()
+	api.On("KVDelete", mock.Anything).Run(func(args mock.Arguments) {
+		delete(kvStore, args.String(0))
+	}).Return(nil).Maybe()
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+			if options.Atomic && !bytes.Equal(kvStore[key], options.OldValue) {
+				return false, nil
+			}
+			if value == nil {
+				delete(kvStore, key)
+			} else {
+				kvStore[key] = value
+			}
+			return true, nil
+		}).Maybe()
+	return kvStore
+}
 
-		api.On("KVSet", expectedKey, []byte(cursor)).Return(nil)
+// storedPollState decodes the poll state document of a backend from a map backed KV store
+func storedPollState(t *testing.T, kvStore map[string][]byte, backendID string) PollState {
+	t.Helper()
+	data, exists := kvStore["backend_"+backendID+"_state"]
+	require.True(t, exists, "poll state should be stored")
 
-		// Save
-		err := store.SaveCursor(cursor)
-		require.NoError(t, err)
-		api.AssertExpectations(t)
+	var state PollState
+	require.NoError(t, json.Unmarshal(data, &state))
+	return state
+}
 
-		// Mock KVGet
-		api.On("KVGet", expectedKey).Return([]byte(cursor), nil)
+func TestStateStore_PollState(t *testing.T) {
+	t.Run("fields are saved in a single document", func(t *testing.T) {
+		api := &plugintest.API{}
+		kvStore := mockKVStore(api)
+		store := NewStateStore(api, "test-backend-456")
 
-		// Retrieve
-		gotCursor, err := store.GetCursor()
+		pollTime := time.Date(2025, 1, 15, 14, 30, 45, 0, time.UTC)
+		require.NoError(t, store.SaveCursor("cursor_abc123xyz"))
+		require.NoError(t, store.SaveLastPoll(pollTime))
+		require.NoError(t, store.SaveLastSuccess(pollTime))
+		require.NoError(t, store.SaveLastError("timeout"))
+		require.NoError(t, store.SavePhase(backend.PhaseCatchingUp))
+
+		assert.Equal(t, PollState{
+			Cursor:      "cursor_abc123xyz",
+			LastPoll:    pollTime,
+			LastSuccess: pollTime,
+			LastError:   "timeout",
+			Phase:       backend.PhaseCatchingUp,
+		}, storedPollState(t, kvStore, "test-backend-456"))
+		assert.Len(t, kvStore, 1)
+
+		cursor, err := store.GetCursor()
 		require.NoError(t, err)
-		assert.Equal(t, cursor, gotCursor)
-		api.AssertExpectations(t)
+		assert.Equal(t, "cursor_abc123xyz", cursor)
+		lastPoll, err := store.GetLastPoll()
+		require.NoError(t, err)
+		assert.Equal(t, pollTime, lastPoll)
+		lastSuccess, err := store.GetLastSuccess()
+		require.NoError(t, err)
+		assert.Equal(t, pollTime, lastSuccess)
+		lastError, err := store.GetLastError()
+		require.NoError(t, err)
+		assert.Equal(t, "timeout", lastError)
+		phase, err := store.GetPhase()
+		require.NoError(t, err)
+		assert.Equal(t, backend.PhaseCatchingUp, phase)
+
+		require.NoError(t, store.ClearLastError())
+		assert.Empty(t, storedPollState(t, kvStore, "test-backend-456").LastError)
 	})
 
-	t.Run("get cursor when none stored", func(t *testing.T) {
+	t.Run("empty when none stored", func(t *testing.T) {
 		api := &plugintest.API{}
-		backendID := "test-backend-456"
-		store := NewStateStore(api, backendID)
-
-		expectedKey := "backend_test-backend-456_cursor"
-		api.On("KVGet", expectedKey).Return(nil, nil)
+		mockKVStore(api)
+		store := NewStateStore(api, "test-backend-456")
 
-		cursor, err := store.GetCursor()
+		state, err := store.GetPollState()
 		require.NoError(t, err)
-		assert.Empty(t, cursor)
-		api.AssertExpectations(t)
+		assert.Equal(t, PollState{}, state)
 	})
-}
 
-func TestStateStore_LastPoll(t *testing.T) {
-	t.Run("save and retrieve last poll time", func(t *testing.T) {
+	t.Run("increment and reset failures", func(t *testing.T) {
 		api := &plugintest.API{}
-		backendID := "test-backend-789"
-		store := NewStateStore(api, backendID)
+		kvStore := mockKVStore(api)
+		store := NewStateStore(api, "test-backend-abc")
 
-		pollTime := time.Date(2025, 1, 15, 14, 30, 45, 0, time.UTC)
-		expectedKey := "backend_test-backend-789_last_poll"
-		expectedData, _ := json.Marshal(pollTime)
+		count, err := store.IncrementFailures()
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
 
-		api.On("KVSet", expectedKey, expectedData).Return(nil)
+		count, err = store.IncrementFailures()
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
 
-		// Save
-		err := store.SaveLastPoll(pollTime)
+		count, err = store.GetFailures()
 		require.NoError(t, err)
-		api.AssertExpectations(t)
+		assert.Equal(t, 2, count)
 
-		// Mock KVGet
-		api.On("KVGet", expectedKey).Return(expectedData, nil)
+		count, err = store.ResetFailures()
+		require.NoError(t, err)
+		assert.Equal(t, 2, count, "the count before the reset is returned")
+		assert.Zero(t, storedPollState(t, kvStore, "test-backend-abc").Failures)
+	})
 
-		// Retrieve
-		gotTime, err := store.GetLastPoll()
+	t.Run("unchanged state is not saved", func(t *testing.T) {
+		api := &plugintest.API{}
+		data, err := json.Marshal(PollState{Cursor: "cursor-1"})
 		require.NoError(t, err)
-		assert.Equal(t, pollTime, gotTime)
-		api.AssertExpectations(t)
+		api.On("KVGet", "backend_test-backend-abc_state").Return(data, nil)
+		store := NewStateStore(api, "test-backend-abc")
+
+		_, err = store.ResetFailures()
+		require.NoError(t, err)
+		require.NoError(t, store.SaveCursor("cursor-1"))
+		api.AssertNotCalled(t, "KVSet", mock.Anything, mock.Anything)
 	})
 
-	t.Run("get last poll when none stored", func(t *testing.T) {
+	t.Run("load error", func(t *testing.T) {
 		api := &plugintest.API{}
-		backendID := "test-backend-789"
-		store := NewStateStore(api, backendID)
+		api.On("KVGet", "backend_test-backend-abc_state").Return(nil, model.NewAppError("KVGet", "error", nil, "", 500))
+		store := NewStateStore(api, "test-backend-abc")
 
-		expectedKey := "backend_test-backend-789_last_poll"
-		api.On("KVGet", expectedKey).Return(nil, nil)
+		_, err := store.IncrementFailures()
+		assert.Error(t, err)
+	})
 
-		pollTime, err := store.GetLastPoll()
-		require.NoError(t, err)
-		assert.True(t, pollTime.IsZero())
-		api.AssertExpectations(t)
+	t.Run("invalid document", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", "backend_test-backend-abc_state").Return([]byte("invalid json"), nil)
+		store := NewStateStore(api, "test-backend-abc")
+
+		_, err := store.GetCursor()
+		assert.Error(t, err)
 	})
 }
 
-func TestStateStore_Failures(t *testing.T) {
-	t.Run("increment failures from zero", func(t *testing.T) {
+func TestStateStore_Batch(t *testing.T) {
+	t.Run("changes are saved with one write on flush", func(t *testing.T) {
 		api := &plugintest.API{}
-		backendID := "test-backend-abc"
-		store := NewStateStore(api, backendID)
+		kvStore := mockKVStore(api)
+		store := NewStateStore(api, "test-backend-abc")
+		require.NoError(t, store.SaveCursor("cursor-1"))
+		api.Calls = nil
+
+		now := time.Date(2025, 1, 15, 14, 30, 45, 0, time.UTC)
+		require.NoError(t, store.BeginBatch())
+		require.NoError(t, store.SaveLastPoll(now))
+		require.NoError(t, store.SaveCursor("cursor-2"))
+		require.NoError(t, store.SaveCursor("cursor-3"))
+		require.NoError(t, store.SaveLastSuccess(now))
+		_, err := store.ResetFailures()
+		require.NoError(t, err)
+		require.NoError(t, store.SaveLastError(""))
 
-		expectedKey := "backend_test-backend-abc_failures"
+		// Unflushed changes are visible to this store but not yet saved
+		cursor, err := store.GetCursor()
+		require.NoError(t, err)
+		assert.Equal(t, "cursor-3", cursor)
+		assert.Equal(t, "cursor-1", storedPollState(t, kvStore, "test-backend-abc").Cursor)
+		api.AssertNotCalled(t, "KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything)
+
+		require.NoError(t, store.Flush())
+		api.AssertNumberOfCalls(t, "KVSetWithOptions", 1)
+		assert.Equal(t, PollState{
+			Cursor:      "cursor-3",
+			LastPoll:    now,
+			LastSuccess: now,
+		}, storedPollState(t, kvStore, "test-backend-abc"))
+
+		// After the flush changes are saved right away again
+		require.NoError(t, store.SaveCursor("cursor-4"))
+		api.AssertNumberOfCalls(t, "KVSetWithOptions", 2)
+	})
 
-		// First call to GetFailures (inside IncrementFailures)
-		api.On("KVGet", expectedKey).Return(nil, nil).Once()
+	t.Run("phase changes are saved right away", func(t *testing.T) {
+		api := &plugintest.API{}
+		kvStore := mockKVStore(api)
+		store := NewStateStore(api, "test-backend-abc")
+
+		require.NoError(t, store.BeginBatch())
+		require.NoError(t, store.SaveCursor("cursor-1"))
+		require.NoError(t, store.SavePhase(backend.PhaseCatchingUp))
+		assert.Equal(t, PollState{Cursor: "cursor-1", Phase: backend.PhaseCatchingUp}, storedPollState(t, kvStore, "test-backend-abc"))
+
+		// The batch continues after the phase was saved
+		require.NoError(t, store.SaveCursor("cursor-2"))
+		api.AssertNumberOfCalls(t, "KVSetWithOptions", 1)
+		require.NoError(t, store.Flush())
+		api.AssertNumberOfCalls(t, "KVSetWithOptions", 2)
+		assert.Equal(t, "cursor-2", storedPollState(t, kvStore, "test-backend-abc").Cursor)
+	})
 
-		// Save incremented count
-		expectedData, _ := json.Marshal(1)
-		api.On("KVSet", expectedKey, expectedData).Return(nil)
+	t.Run("checkpoint saves and keeps batching", func(t *testing.T) {
+		api := &plugintest.API{}
+		kvStore := mockKVStore(api)
+		store := NewStateStore(api, "test-backend-abc")
+
+		startedAt := time.Date(2025, 1, 15, 14, 30, 45, 0, time.UTC)
+		progress := &backend.CatchUpProgress{StartedAt: startedAt, Skipped: 10}
+		require.NoError(t, store.BeginBatch())
+		require.NoError(t, store.SaveCursor("cursor-1"))
+		require.NoError(t, store.SaveCatchUp(progress))
+		require.NoError(t, store.Checkpoint())
+		assert.Equal(t, PollState{Cursor: "cursor-1", CatchUp: &backend.CatchUpProgress{StartedAt: startedAt, Skipped: 10}},
+			storedPollState(t, kvStore, "test-backend-abc"))
+
+		// The saved progress is a copy, so later changes need another save
+		progress.Skipped = 20
+		require.NoError(t, store.Checkpoint())
+		api.AssertNumberOfCalls(t, "KVSetWithOptions", 1)
+
+		require.NoError(t, store.SaveCatchUp(nil))
+		api.AssertNumberOfCalls(t, "KVSetWithOptions", 1)
+		require.NoError(t, store.Flush())
+		assert.Nil(t, storedPollState(t, kvStore, "test-backend-abc").CatchUp)
+	})
 
-		count, err := store.IncrementFailures()
+	t.Run("nothing is saved without changes", func(t *testing.T) {
+		api := &plugintest.API{}
+		mockKVStore(api)
+		store := NewStateStore(api, "test-backend-abc")
+
+		require.NoError(t, store.BeginBatch())
+		_, err := store.ResetFailures()
 		require.NoError(t, err)
-		assert.Equal(t, 1, count)
-		api.AssertExpectations(t)
+		require.NoError(t, store.Flush())
+		api.AssertNotCalled(t, "KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("increment failures from existing count", func(t *testing.T) {
+	t.Run("flush keeps changes other nodes made during the batch", func(t *testing.T) {
 		api := &plugintest.API{}
-		backendID := "test-backend-abc"
-		store := NewStateStore(api, backendID)
-
-		expectedKey := "backend_test-backend-abc_failures"
+		kvStore := mockKVStore(api)
+		store := NewStateStore(api, "test-backend-abc")
+		require.NoError(t, store.SaveCursor("cursor-1"))
+
+		now := time.Date(2025, 1, 15, 14, 30, 45, 0, time.UTC)
+		require.NoError(t, store.BeginBatch())
+		require.NoError(t, store.SaveCursor("cursor-2"))
+		require.NoError(t, store.SaveLastPoll(now))
+
+		// Another node records a failure meanwhile
+		kvStore["backend_test-backend-abc_state"] = mustMarshalPollState(PollState{Cursor: "cursor-1", Failures: 3, LastError: "timeout"})
+
+		require.NoError(t, store.Flush())
+		assert.Equal(t, PollState{
+			Cursor:    "cursor-2",
+			LastPoll:  now,
+			Failures:  3,
+			LastError: "timeout",
+		}, storedPollState(t, kvStore, "test-backend-abc"))
+	})
 
-		// Mock existing count of 3
-		existingData, _ := json.Marshal(3)
-		api.On("KVGet", expectedKey).Return(existingData, nil).Once()
+	t.Run("flush without a batch", func(t *testing.T) {
+		api := &plugintest.API{}
+		store := NewStateStore(api, "test-backend-abc")
+		assert.NoError(t, store.Flush())
+	})
+}
 
-		// Save incremented count (4)
-		newData, _ := json.Marshal(4)
-		api.On("KVSet", expectedKey, newData).Return(nil)
+func TestStateStore_MigrateLegacyPollState(t *testing.T) {
+	t.Run("moves the legacy keys into the document", func(t *testing.T) {
+		api := &plugintest.API{}
+		kvStore := mockKVStore(api)
+		lastPoll := time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC)
+		lastSuccess := time.Date(2025, 1, 15, 14, 0, 0, 0, time.UTC)
+		kvStore["backend_test-backend-abc_cursor"] = []byte("cursor-1")
+		kvStore["backend_test-backend-abc_last_poll"] = mustMarshalTime(lastPoll)
+		kvStore["backend_test-backend-abc_last_success"] = mustMarshalTime(lastSuccess)
+		kvStore["backend_test-backend-abc_failures"] = []byte("2")
+		kvStore["backend_test-backend-abc_last_error"] = []byte("timeout")
+		kvStore["backend_test-backend-abc_phase"] = []byte("cooling_down")
+		kvStore["backend_test-backend-abc_pause"] = []byte(`{}`)
+		store := NewStateStore(api, "test-backend-abc")
 
 		count, err := store.IncrementFailures()
 		require.NoError(t, err)
-		assert.Equal(t, 4, count)
-		api.AssertExpectations(t)
+		assert.Equal(t, 3, count)
+
+		assert.Equal(t, PollState{
+			Cursor:      "cursor-1",
+			LastPoll:    lastPoll,
+			LastSuccess: lastSuccess,
+			Failures:    3,
+			LastError:   "timeout",
+			Phase:       backend.PhaseCoolingDown,
+		}, storedPollState(t, kvStore, "test-backend-abc"))
+
+		// Only the poll state document and unrelated keys remain
+		assert.Len(t, kvStore, 2)
+		assert.Contains(t, kvStore, "backend_test-backend-abc_pause")
 	})
 
-	t.Run("reset failures", func(t *testing.T) {
+	t.Run("legacy keys are checked once", func(t *testing.T) {
 		api := &plugintest.API{}
-		backendID := "test-backend-abc"
-		store := NewStateStore(api, backendID)
-
-		expectedKey := "backend_test-backend-abc_failures"
-		expectedData, _ := json.Marshal(0)
+		mockKVStore(api)
+		store := NewStateStore(api, "test-backend-abc")
 
-		api.On("KVSet", expectedKey, expectedData).Return(nil)
+		_, err := store.GetPollState()
+		require.NoError(t, err)
+		api.AssertNumberOfCalls(t, "KVGet", 7)
 
-		err := store.ResetFailures()
+		_, err = store.GetPollState()
 		require.NoError(t, err)
+		api.AssertNumberOfCalls(t, "KVGet", 8)
+	})
+}
+
+func TestStateStore_Pause(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	until := now.Add(time.Hour)
+	key := "backend_test-backend-123_pause"
+
+	t.Run("save pause", func(t *testing.T) {
+		api := &plugintest.API{}
+		store := NewStateStore(api, "test-backend-123")
+
+		expectedData, _ := json.Marshal(PauseState{Until: until})
+		api.On("KVSet", key, expectedData).Return(nil)
+
+		require.NoError(t, store.SavePause(until))
 		api.AssertExpectations(t)
 	})
 
-	t.Run("get failures when none stored", func(t *testing.T) {
+	t.Run("active pause", func(t *testing.T) {
 		api := &plugintest.API{}
-		backendID := "test-backend-abc"
-		store := NewStateStore(api, backendID)
+		store := NewStateStore(api, "test-backend-123")
 
-		expectedKey := "backend_test-backend-abc_failures"
-		api.On("KVGet", expectedKey).Return(nil, nil)
+		data, _ := json.Marshal(PauseState{Until: until})
+		api.On("KVGet", key).Return(data, nil)
 
-		count, err := store.GetFailures()
+		paused, pausedUntil, err := store.GetPause(now)
 		require.NoError(t, err)
-		assert.Equal(t, 0, count)
-		api.AssertExpectations(t)
+		assert.True(t, paused)
+		assert.Equal(t, until, pausedUntil)
+
+		// The pause ends at the until time
+		paused, _, err = store.GetPause(until)
+		require.NoError(t, err)
+		assert.False(t, paused)
 	})
 
-	t.Run("get failures with existing count", func(t *testing.T) {
+	t.Run("indefinite pause", func(t *testing.T) {
 		api := &plugintest.API{}
-		backendID := "test-backend-abc"
-		store := NewStateStore(api, backendID)
+		store := NewStateStore(api, "test-backend-123")
+
+		data, _ := json.Marshal(PauseState{})
+		api.On("KVGet", key).Return(data, nil)
+
+		paused, pausedUntil, err := store.GetPause(now.Add(365 * 24 * time.Hour))
+		require.NoError(t, err)
+		assert.True(t, paused)
+		assert.True(t, pausedUntil.IsZero())
+	})
 
-		expectedKey := "backend_test-backend-abc_failures"
-		existingData, _ := json.Marshal(5)
-		api.On("KVGet", expectedKey).Return(existingData, nil)
+	t.Run("not paused", func(t *testing.T) {
+		api := &plugintest.API{}
+		store := NewStateStore(api, "test-backend-123")
+		api.On("KVGet", key).Return(nil, nil)
 
-		count, err := store.GetFailures()
+		paused, _, err := store.GetPause(now)
 		require.NoError(t, err)
-		assert.Equal(t, 5, count)
+		assert.False(t, paused)
+	})
+
+	t.Run("clear pause", func(t *testing.T) {
+		api := &plugintest.API{}
+		store := NewStateStore(api, "test-backend-123")
+		api.On("KVDelete", key).Return(nil)
+
+		require.NoError(t, store.ClearPause())
 		api.AssertExpectations(t)
 	})
 }
@@ -247,25 +471,53 @@ func TestStateStore_Failures(t *testing.T) {
 func TestStateStore_ClearOperationalState(t *testing.T) {
 	t.Run("clears only cursor and auth token", func(t *testing.T) {
 		api := &plugintest.API{}
-		backendID := "test-backend-xyz"
-		store := NewStateStore(api, backendID)
+		kvStore := mockKVStore(api)
+		store := NewStateStore(api, "test-backend-xyz")
 
-		// Should only delete cursor and auth, not failure tracking state
-		expectedKeys := []string{
-			"backend_test-backend-xyz_auth",
-			"backend_test-backend-xyz_cursor",
-		}
+		require.NoError(t, store.SaveAuthToken("token", time.Now().Add(time.Hour)))
+		require.NoError(t, store.SaveCursor("cursor-1"))
+		_, err := store.IncrementFailures()
+		require.NoError(t, err)
 
-		for _, key := range expectedKeys {
-			api.On("KVDelete", key).Return(nil)
-		}
+		require.NoError(t, store.ClearOperationalState())
 
-		err := store.ClearOperationalState()
-		require.NoError(t, err)
-		api.AssertExpectations(t)
+		// Failure tracking is kept
+		assert.NotContains(t, kvStore, "backend_test-backend-xyz_auth")
+		assert.Equal(t, PollState{Failures: 1}, storedPollState(t, kvStore, "test-backend-xyz"))
 	})
 }
 
+func TestStateStore_CursorReset(t *testing.T) {
+	api := &plugintest.API{}
+	mockKVStore(api)
+	store := NewStateStore(api, "test-backend-xyz")
+
+	// Without a request the cursor is kept
+	require.NoError(t, store.SaveCursor("cursor-1"))
+	reset, err := store.ApplyCursorReset()
+	require.NoError(t, err)
+	assert.False(t, reset)
+
+	require.NoError(t, store.RequestCursorReset())
+	pending, err := store.IsCursorResetPending()
+	require.NoError(t, err)
+	assert.True(t, pending)
+
+	// A cursor saved after the request is still discarded
+	require.NoError(t, store.SaveCursor("cursor-2"))
+	reset, err = store.ApplyCursorReset()
+	require.NoError(t, err)
+	assert.True(t, reset)
+
+	cursor, err := store.GetCursor()
+	require.NoError(t, err)
+	assert.Empty(t, cursor)
+
+	pending, err = store.IsCursorResetPending()
+	require.NoError(t, err)
+	assert.False(t, pending)
+}
+
 func TestStateStore_ClearAll(t *testing.T) {
 	t.Run("clears all state keys", func(t *testing.T) {
 		api := &plugintest.API{}
@@ -274,11 +526,23 @@ func TestStateStore_ClearAll(t *testing.T) {
 
 		expectedKeys := []string{
 			"backend_test-backend-xyz_auth",
+			"backend_test-backend-xyz_state",
 			"backend_test-backend-xyz_cursor",
 			"backend_test-backend-xyz_last_poll",
 			"backend_test-backend-xyz_last_success",
 			"backend_test-backend-xyz_failures",
 			"backend_test-backend-xyz_last_error",
+			"backend_test-backend-xyz_quiet_buffer",
+			"backend_test-backend-xyz_pause",
+			"backend_test-backend-xyz_cooldown",
+			"backend_test-backend-xyz_backoff",
+			"backend_test-backend-xyz_errors",
+			"backend_test-backend-xyz_pending",
+			"backend_test-backend-xyz_poll_history",
+			"backend_test-backend-xyz_phase",
+			"backend_test-backend-xyz_cursor_reset",
+			"backend_test-backend-xyz_failed",
+			"backend_test-backend-xyz_credentials",
 		}
 
 		for _, key := range expectedKeys {
@@ -294,6 +558,7 @@ func TestStateStore_ClearAll(t *testing.T) {
 func TestStateStore_KeyIsolation(t *testing.T) {
 	t.Run("different backend IDs use different keys", func(t *testing.T) {
 		api := &plugintest.API{}
+		kvStore := mockKVStore(api)
 
 		store1 := NewStateStore(api, "backend-1")
 		store2 := NewStateStore(api, "backend-2")
@@ -301,30 +566,259 @@ func TestStateStore_KeyIsolation(t *testing.T) {
 		cursor1 := "cursor_for_backend_1"
 		cursor2 := "cursor_for_backend_2"
 
-		// Each should use a different key
-		api.On("KVSet", "backend_backend-1_cursor", []byte(cursor1)).Return(nil)
-		api.On("KVSet", "backend_backend-2_cursor", []byte(cursor2)).Return(nil)
+		require.NoError(t, store1.SaveCursor(cursor1))
+		require.NoError(t, store2.SaveCursor(cursor2))
+
+		// Each uses a different key
+		assert.Equal(t, cursor1, storedPollState(t, kvStore, "backend-1").Cursor)
+		assert.Equal(t, cursor2, storedPollState(t, kvStore, "backend-2").Cursor)
 
-		err := store1.SaveCursor(cursor1)
+		got1, err := store1.GetCursor()
 		require.NoError(t, err)
+		assert.Equal(t, cursor1, got1)
 
-		err = store2.SaveCursor(cursor2)
+		got2, err := store2.GetCursor()
 		require.NoError(t, err)
+		assert.Equal(t, cursor2, got2)
+	})
+}
 
-		api.AssertExpectations(t)
+func TestStateStore_Cooldown(t *testing.T) {
+	key := "backend_test-backend-123_cooldown"
+	state := CooldownState{Until: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), Cycles: 2}
 
-		// Verify retrievals use correct keys
-		api.On("KVGet", "backend_backend-1_cursor").Return([]byte(cursor1), nil)
-		api.On("KVGet", "backend_backend-2_cursor").Return([]byte(cursor2), nil)
+	t.Run("save and get", func(t *testing.T) {
+		api := &plugintest.API{}
+		store := NewStateStore(api, "test-backend-123")
 
-		got1, err := store1.GetCursor()
+		data, _ := json.Marshal(state)
+		api.On("KVSet", key, data).Return(nil)
+		api.On("KVGet", key).Return(data, nil)
+
+		require.NoError(t, store.SaveCooldown(state))
+		got, err := store.GetCooldown()
 		require.NoError(t, err)
-		assert.Equal(t, cursor1, got1)
+		assert.Equal(t, state, got)
+		api.AssertExpectations(t)
+	})
 
-		got2, err := store2.GetCursor()
+	t.Run("no cool-down", func(t *testing.T) {
+		api := &plugintest.API{}
+		store := NewStateStore(api, "test-backend-123")
+		api.On("KVGet", key).Return(nil, nil)
+
+		got, err := store.GetCooldown()
 		require.NoError(t, err)
-		assert.Equal(t, cursor2, got2)
+		assert.Equal(t, CooldownState{}, got)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		api := &plugintest.API{}
+		store := NewStateStore(api, "test-backend-123")
+		api.On("KVDelete", key).Return(nil)
+
+		require.NoError(t, store.ClearCooldown())
+		api.AssertExpectations(t)
+	})
+}
+
+func TestStateStore_Backoff(t *testing.T) {
+	api := &plugintest.API{}
+	mockKVStore(api)
+	store := NewStateStore(api, "test-backend-123")
+
+	until, err := store.GetBackoff()
+	require.NoError(t, err)
+	assert.True(t, until.IsZero(), "no back-off by default")
+
+	backoffUntil := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, store.SaveBackoff(backoffUntil))
+	until, err = store.GetBackoff()
+	require.NoError(t, err)
+	assert.True(t, backoffUntil.Equal(until))
+
+	require.NoError(t, store.ClearBackoff())
+	until, err = store.GetBackoff()
+	require.NoError(t, err)
+	assert.True(t, until.IsZero())
+}
+
+func TestStateStore_CredentialCheck(t *testing.T) {
+	key := "backend_test-backend-123_credentials"
+	checkedAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	check := backend.CredentialCheck{CheckedAt: checkedAt, Valid: true, TokenExpiry: checkedAt.Add(time.Hour)}
+
+	t.Run("save and get", func(t *testing.T) {
+		api := &plugintest.API{}
+		store := NewStateStore(api, "test-backend-123")
+
+		data, _ := json.Marshal(check)
+		api.On("KVSet", key, data).Return(nil)
+		api.On("KVGet", key).Return(data, nil)
 
+		require.NoError(t, store.SaveCredentialCheck(check))
+		got, err := store.GetCredentialCheck()
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, check, *got)
 		api.AssertExpectations(t)
 	})
+
+	t.Run("never checked", func(t *testing.T) {
+		api := &plugintest.API{}
+		store := NewStateStore(api, "test-backend-123")
+		api.On("KVGet", key).Return(nil, nil)
+
+		got, err := store.GetCredentialCheck()
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestStateStore_RecordError(t *testing.T) {
+	key := "backend_test-backend-123_errors"
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	api := &plugintest.API{}
+	store := NewStateStore(api, "test-backend-123")
+
+	var stored []byte
+	api.On("KVGet", key).Return(func(string) ([]byte, *model.AppError) { return stored, nil })
+	api.On("KVSet", key, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).([]byte)
+	}).Return(nil)
+
+	records, err := store.GetRecentErrors()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	for i := 0; i < backend.MaxRecentErrors+2; i++ {
+		require.NoError(t, store.RecordError(fmt.Sprintf("error %d", i), start.Add(time.Duration(i)*time.Minute)))
+	}
+
+	records, err = store.GetRecentErrors()
+	require.NoError(t, err)
+	require.Len(t, records, backend.MaxRecentErrors)
+	assert.Equal(t, "error 6", records[0].Message, "newest error should be first")
+	assert.Equal(t, start.Add(6*time.Minute), records[0].Time)
+	assert.Equal(t, "error 2", records[len(records)-1].Message)
+}
+
+func TestStateStore_RecordPollSample(t *testing.T) {
+	key := "backend_test-backend-123_poll_history"
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	api := &plugintest.API{}
+	store := NewStateStore(api, "test-backend-123")
+
+	var stored []byte
+	api.On("KVGet", key).Return(func(string) ([]byte, *model.AppError) { return stored, nil })
+	api.On("KVSet", key, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).([]byte)
+	}).Return(nil)
+
+	samples, err := store.GetPollHistory()
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+
+	for i := 0; i < 3; i++ {
+		sample := backend.PollSample{Time: start.Add(time.Duration(i) * time.Hour), Success: true, Alerts: i}
+		require.NoError(t, store.RecordPollSample(sample, sample.Time))
+	}
+
+	samples, err = store.GetPollHistory()
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+	assert.Equal(t, 0, samples[0].Alerts, "oldest sample should be first")
+
+	// Samples older than the retention period are dropped
+	later := start.Add(backend.PollHistoryRetention + 90*time.Minute)
+	require.NoError(t, store.RecordPollSample(backend.PollSample{Time: later, Alerts: 3}, later))
+
+	samples, err = store.GetPollHistory()
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, 2, samples[0].Alerts)
+	assert.Equal(t, 3, samples[1].Alerts)
 }
+
+func TestStateStore_PendingAlerts(t *testing.T) {
+	key := "backend_test-backend-123_pending"
+
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	store := NewStateStore(api, "test-backend-123")
+
+	var stored []byte
+	api.On("KVGet", key).Return(func(string) ([]byte, *model.AppError) { return stored, nil })
+	api.On("KVSet", key, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).([]byte)
+	}).Return(nil).Once()
+	api.On("KVDelete", key).Run(func(mock.Arguments) {
+		stored = nil
+	}).Return(nil).Once()
+
+	pending, err := store.GetPendingAlerts()
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	alerts := []PendingAlert{
+		{Alert: backend.Alert{AlertID: "alert-1", Headline: "First"}, ChannelID: "channel-1"},
+		{Alert: backend.Alert{AlertID: "alert-2", Headline: "Second"}, ChannelID: "channel-1", Attempts: 2},
+	}
+	require.NoError(t, store.SavePendingAlerts(alerts))
+
+	pending, err = store.GetPendingAlerts()
+	require.NoError(t, err)
+	assert.Equal(t, alerts, pending)
+
+	// Saving an empty queue deletes the key
+	require.NoError(t, store.SavePendingAlerts(nil))
+	pending, err = store.GetPendingAlerts()
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestStateStore_FailedDeliveries(t *testing.T) {
+	key := "backend_test-backend-123_failed"
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	api := &plugintest.API{}
+	store := NewStateStore(api, "test-backend-123")
+
+	var stored []byte
+	api.On("KVGet", key).Return(func(string) ([]byte, *model.AppError) { return stored, nil })
+	api.On("KVSet", key, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).([]byte)
+	}).Return(nil)
+	api.On("KVDelete", key).Run(func(mock.Arguments) {
+		stored = nil
+	}).Return(nil)
+
+	deliveries, err := store.GetFailedDeliveries()
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+
+	for i := 0; i < backend.MaxFailedDeliveries+2; i++ {
+		require.NoError(t, store.RecordFailedDelivery(backend.FailedDelivery{
+			AlertID:   fmt.Sprintf("alert-%d", i),
+			Attempts:  backend.MaxAlertPostAttempts,
+			LastError: "post failed",
+			FailedAt:  start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	deliveries, err = store.GetFailedDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, backend.MaxFailedDeliveries)
+	assert.Equal(t, "alert-11", deliveries[0].AlertID, "newest delivery should be first")
+	assert.Equal(t, "alert-2", deliveries[len(deliveries)-1].AlertID)
+
+	require.NoError(t, store.ClearFailedDeliveries())
+	deliveries, err = store.GetFailedDeliveries()
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
+
+// The backend state records the poll history in the KV store by default
+var _ backend.PollHistoryStore = (*StateStore)(nil)
@@ -0,0 +1,62 @@
+package dataminr
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// taggingTransport is an http.RoundTripper that identifies the backend's requests: each one
+// carries the plugin's User-Agent and, when a header is configured, the correlation ID of the
+// current poll cycle, so the API provider's support can trace them.
+type taggingTransport struct {
+	next              http.RoundTripper
+	userAgent         string
+	correlationHeader string
+
+	// mu guards correlationID, the ID of the current poll cycle
+	mu            sync.Mutex
+	correlationID string
+}
+
+// newTaggingTransport wraps a transport to tag requests (nil uses the default transport).
+// An empty correlation header sends no correlation ID.
+func newTaggingTransport(next http.RoundTripper, userAgent, correlationHeader string) *taggingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &taggingTransport{
+		next:              next,
+		userAgent:         userAgent,
+		correlationHeader: correlationHeader,
+	}
+}
+
+// newCorrelationID generates the correlation ID sent with the requests that follow, until the
+// next call. Returns an empty string when no correlation header is configured.
+func (t *taggingTransport) newCorrelationID() string {
+	if t.correlationHeader == "" {
+		return ""
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.correlationID = model.NewId()
+	return t.correlationID
+}
+
+// RoundTrip sets the User-Agent and correlation ID headers on a copy of the request and executes it
+func (t *taggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	correlationID := t.correlationID
+	t.mu.Unlock()
+
+	// A RoundTripper must not modify the caller's request
+	tagged := req.Clone(req.Context())
+	tagged.Header.Set("User-Agent", t.userAgent)
+	if correlationID != "" {
+		tagged.Header.Set(t.correlationHeader, correlationID)
+	}
+	return t.next.RoundTrip(tagged)
+}
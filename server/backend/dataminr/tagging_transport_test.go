@@ -0,0 +1,58 @@
+package dataminr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaggingTransport_RoundTrip(t *testing.T) {
+	var received []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Clone())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	send := func(t *testing.T, transport *taggingTransport) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := (&http.Client{Transport: transport}).Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return req
+	}
+
+	t.Run("user agent without correlation ID", func(t *testing.T) {
+		received = nil
+		transport := newTaggingTransport(nil, "mattermost-plugin-dataminr/1.0.0", "")
+		assert.Empty(t, transport.newCorrelationID())
+
+		req := send(t, transport)
+		require.Len(t, received, 1)
+		assert.Equal(t, "mattermost-plugin-dataminr/1.0.0", received[0].Get("User-Agent"))
+		assert.Empty(t, req.Header.Get("User-Agent"), "the caller's request is not modified")
+	})
+
+	t.Run("correlation ID of the current cycle", func(t *testing.T) {
+		received = nil
+		transport := newTaggingTransport(nil, "mattermost-plugin-dataminr/1.0.0", "X-Correlation-Id")
+
+		first := transport.newCorrelationID()
+		require.NotEmpty(t, first)
+		send(t, transport)
+		send(t, transport)
+
+		second := transport.newCorrelationID()
+		assert.NotEqual(t, first, second)
+		send(t, transport)
+
+		require.Len(t, received, 3)
+		assert.Equal(t, first, received[0].Get("X-Correlation-Id"))
+		assert.Equal(t, first, received[1].Get("X-Correlation-Id"))
+		assert.Equal(t, second, received[2].Get("X-Correlation-Id"))
+	})
+}
@@ -1,22 +1,51 @@
 package dataminr
 
 import (
+	"context"
+
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
 
 // MockPoster is a mock poster implementation for testing
 type MockPoster struct {
-	PostAlertFn func(alert backend.Alert, channelID string) error
+	PostAlertFn        func(alert backend.Alert, channelID string) error
+	PostMessageFn      func(message, channelID string) error
+	PostAlertUpdateFn  func(alert backend.Alert) error
+	PostAdminMessageFn func(message string) error
 }
 
 // PostAlert calls the mock function
-func (m *MockPoster) PostAlert(alert backend.Alert, channelID string) error {
+func (m *MockPoster) PostAlert(_ context.Context, alert backend.Alert, channelID string) error {
 	if m.PostAlertFn != nil {
 		return m.PostAlertFn(alert, channelID)
 	}
 	return nil
 }
 
+// PostMessage calls the mock function
+func (m *MockPoster) PostMessage(message, channelID string) error {
+	if m.PostMessageFn != nil {
+		return m.PostMessageFn(message, channelID)
+	}
+	return nil
+}
+
+// PostAlertUpdate calls the mock function
+func (m *MockPoster) PostAlertUpdate(alert backend.Alert) error {
+	if m.PostAlertUpdateFn != nil {
+		return m.PostAlertUpdateFn(alert)
+	}
+	return nil
+}
+
+// PostAdminMessage calls the mock function
+func (m *MockPoster) PostAdminMessage(message string) error {
+	if m.PostAdminMessageFn != nil {
+		return m.PostAdminMessageFn(message)
+	}
+	return nil
+}
+
 // MockDeduplicator is a mock implementation of backend.Deduplicator for testing
 type MockDeduplicator struct {
 	RecordAlertFn func(backendType, alertID string) bool
@@ -43,3 +72,13 @@ func (m *MockDeduplicator) RecordAlert(backendType, alertID string) bool {
 	m.seenAlerts[key] = true
 	return true
 }
+
+// IsSeen reports whether the alert is tracked in memory
+func (m *MockDeduplicator) IsSeen(backendType, alertID string) bool {
+	return m.seenAlerts[backendType+":"+alertID]
+}
+
+// ForgetAlert removes an alert from the in-memory tracking
+func (m *MockDeduplicator) ForgetAlert(backendType, alertID string) {
+	delete(m.seenAlerts, backendType+":"+alertID)
+}
@@ -0,0 +1,32 @@
+package dataminr
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// newTransport builds the HTTP transport for a backend's custom TLS, proxy and debug logging
+// settings. Returns nil when none is configured so the default transport is used.
+func newTransport(config backend.Config, logger backend.Logger) (http.RoundTripper, error) {
+	transport, err := newBaseTransport(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.DebugHTTPLogging {
+		return newLoggingTransport(transport, logger, config.ID), nil
+	}
+
+	return transport, nil
+}
+
+// newBaseTransport builds the transport for custom TLS and proxy settings,
+// or returns nil when neither is configured.
+func newBaseTransport(config backend.Config) (http.RoundTripper, error) {
+	transport, err := backend.NewHTTPTransport(config)
+	if err != nil || transport == nil {
+		return nil, err
+	}
+	return transport, nil
+}
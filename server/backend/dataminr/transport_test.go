@@ -0,0 +1,91 @@
+package dataminr
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestNewTransport(t *testing.T) {
+	t.Run("default transport without custom settings", func(t *testing.T) {
+		transport, err := newTransport(backend.Config{}, &pluginapi.LogService{})
+		require.NoError(t, err)
+		assert.Nil(t, transport)
+	})
+
+	t.Run("debug logging wraps the transport", func(t *testing.T) {
+		transport, err := newTransport(backend.Config{ID: "test-id", DebugHTTPLogging: true}, &pluginapi.LogService{})
+		require.NoError(t, err)
+
+		logging, ok := transport.(*loggingTransport)
+		require.True(t, ok)
+		assert.Equal(t, "test-id", logging.backendID)
+		assert.Equal(t, http.DefaultTransport, logging.next)
+	})
+
+	t.Run("custom CA trusts the server certificate", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+		// Without the custom CA the self-signed certificate is rejected
+		_, err := (&http.Client{}).Get(server.URL)
+		require.Error(t, err)
+
+		transport, err := newTransport(backend.Config{TLS: &backend.TLSSettings{CACertificates: string(caPEM)}}, &pluginapi.LogService{})
+		require.NoError(t, err)
+
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("proxy URL is applied", func(t *testing.T) {
+		transport, err := newTransport(backend.Config{ProxyURL: "http://proxy.internal:3128"}, &pluginapi.LogService{})
+		require.NoError(t, err)
+
+		httpTransport, ok := transport.(*http.Transport)
+		require.True(t, ok)
+
+		req := &http.Request{URL: &url.URL{Scheme: "https", Host: "api.dataminr.com"}}
+		proxyURL, err := httpTransport.Proxy(req)
+		require.NoError(t, err)
+		assert.Equal(t, "http://proxy.internal:3128", proxyURL.String())
+	})
+
+	t.Run("proxy credentials are applied", func(t *testing.T) {
+		transport, err := newTransport(backend.Config{
+			ProxyURL:      "http://proxy.internal:3128",
+			ProxyUsername: "proxy-user",
+			ProxyPassword: "p@ss",
+		}, &pluginapi.LogService{})
+		require.NoError(t, err)
+
+		httpTransport, ok := transport.(*http.Transport)
+		require.True(t, ok)
+
+		req := &http.Request{URL: &url.URL{Scheme: "https", Host: "api.dataminr.com"}}
+		proxyURL, err := httpTransport.Proxy(req)
+		require.NoError(t, err)
+		assert.Equal(t, "proxy-user", proxyURL.User.Username())
+		password, _ := proxyURL.User.Password()
+		assert.Equal(t, "p@ss", password)
+	})
+
+	t.Run("invalid TLS settings", func(t *testing.T) {
+		_, err := newTransport(backend.Config{TLS: &backend.TLSSettings{CACertificates: "invalid"}}, &pluginapi.LogService{})
+		assert.ErrorContains(t, err, "invalid CA certificates")
+	})
+}
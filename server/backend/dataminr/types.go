@@ -44,9 +44,9 @@ func (a *AuthResponse) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// AuthError represents authentication error response
+// AuthErrorResponse represents authentication error response
 // Format: {"Error": "unauthorized_client", "error_description": "Invalid API User ID/Password"}
-type AuthError struct {
+type AuthErrorResponse struct {
 	Error            string `json:"Error"`
 	ErrorDescription string `json:"error_description"`
 }
@@ -92,6 +92,9 @@ type Alert struct {
 	LinkedAlerts  []LinkedAlert `json:"linkedAlerts,omitempty"`
 	SubHeadline   *SubHeadline  `json:"subHeadline,omitempty"`
 	TermsOfUse    string        `json:"termsOfUse,omitempty"`
+
+	// Raw is the original JSON of the alert as returned by the API
+	Raw json.RawMessage `json:"-"`
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for Alert
@@ -112,6 +115,9 @@ func (r *Alert) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	// Keep a copy of the original payload since the decoder may reuse its buffer
+	r.Raw = append(json.RawMessage(nil), data...)
+
 	// Parse event time from milliseconds to UTC
 	if aux.EventTimeMs > 0 {
 		r.EventTime = time.Unix(0, aux.EventTimeMs*int64(time.Millisecond)).UTC()
@@ -178,6 +184,23 @@ type AlertList struct {
 	Name string `json:"name"`
 }
 
+// ListsResponse represents the response from the alert lists endpoint, with the alert lists
+// available to the account grouped by list type
+// Format: {"watchlists": {"TOPIC": [{"id": 101, "name": "Severe Weather", "type": "TOPIC"}]}}
+type ListsResponse struct {
+	Watchlists map[string][]Watchlist `json:"watchlists"`
+}
+
+// topicListType is the type of alert lists following a topic rather than a company or place
+const topicListType = "TOPIC"
+
+// Watchlist represents an alert list available to the account
+type Watchlist struct {
+	ID   json.Number `json:"id"`
+	Name string      `json:"name"`
+	Type string      `json:"type"`
+}
+
 // LinkedAlert represents linked/related alerts
 type LinkedAlert struct {
 	Count    int    `json:"count"`
@@ -116,6 +116,17 @@ func TestAlert_TimeConversion(t *testing.T) {
 	})
 }
 
+func TestAlert_KeepsRawPayload(t *testing.T) {
+	data := []byte(`{"alerts":[{"alertId":"alert-1","unknownField":{"nested":true}},{"alertId":"alert-2"}],"to":"cursor"}`)
+
+	var response AlertsResponse
+	require.NoError(t, json.Unmarshal(data, &response))
+	require.Len(t, response.Alerts, 2)
+
+	assert.JSONEq(t, `{"alertId":"alert-1","unknownField":{"nested":true}}`, string(response.Alerts[0].Raw))
+	assert.JSONEq(t, `{"alertId":"alert-2"}`, string(response.Alerts[1].Raw))
+}
+
 func TestAlert_LocationParsing(t *testing.T) {
 	t.Run("parses complete location array", func(t *testing.T) {
 		jsonData := `{
@@ -0,0 +1,138 @@
+package dataminr
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// windowOverlap is how far each time window reaches back before the end of the previous one,
+// so alerts published late are not missed. The deduplicator drops the repeated alerts.
+const windowOverlap = time.Minute
+
+// windowFetcher is implemented by fetchers that can fetch alerts by time window, which the
+// poller falls back to when the API rejects the cursor
+type windowFetcher interface {
+	FetchAlertsWindow(ctx context.Context, start, end time.Time) (*AlertsResponse, error)
+}
+
+// fallBackToWindow discards a cursor rejected by the API and fetches the alerts published since
+// the last successful poll by time window. Fetchers without time windows, and backends that
+// never polled successfully, catch up as on a first start instead. The window start is saved
+// with the cursor cleared, so a failed fetch retries the same window next cycle.
+func (p *Poller) fallBackToWindow(ctx context.Context, cursorErr error, lastSuccess, now time.Time) (int, int, error) {
+	p.logger.Warn("Cursor rejected by the API, falling back to polling by time window",
+		"backendId", p.backendID,
+		"backendName", p.backendName,
+		"error", cursorErr.Error())
+
+	if err := p.stateStore.SaveCursor(""); err != nil {
+		return 0, 0, fmt.Errorf("failed to clear rejected cursor: %w", err)
+	}
+
+	if _, ok := p.client.(windowFetcher); !ok || lastSuccess.IsZero() {
+		return p.catchUp(ctx)
+	}
+
+	start := lastSuccess.Add(-windowOverlap)
+	if p.catchUpWindow > 0 && start.Before(now.Add(-p.catchUpWindow)) {
+		start = now.Add(-p.catchUpWindow)
+	}
+	if err := p.stateStore.SaveWindowStart(start); err != nil {
+		return 0, 0, fmt.Errorf("failed to save time window start: %w", err)
+	}
+	return p.pollWindow(ctx, start, now)
+}
+
+// pollWindow fetches and processes the alerts published between start and now. A full page
+// is followed by the window starting at its latest alert, until the API returns a short page.
+// Time window polling continues each cycle until the API returns a cursor, then regular
+// polling resumes. Returns the number of alerts fetched and posted.
+func (p *Poller) pollWindow(ctx context.Context, start, now time.Time) (int, int, error) {
+	fetcher, ok := p.client.(windowFetcher)
+	if !ok {
+		if err := p.stateStore.SaveWindowStart(time.Time{}); err != nil {
+			return 0, 0, fmt.Errorf("failed to leave time window polling: %w", err)
+		}
+		return p.catchUp(ctx)
+	}
+
+	total := 0
+	newTotal := 0
+	for page := 1; ; page++ {
+		response, err := fetcher.FetchAlertsWindow(ctx, start, now)
+		if err != nil {
+			return total, newTotal, fmt.Errorf("failed to fetch alerts by time window: %w", err)
+		}
+
+		newCount, err := p.processor.ProcessAlerts(ctx, response.Alerts)
+		total += len(response.Alerts)
+		newTotal += newCount
+		if err != nil {
+			return total, newTotal, fmt.Errorf("failed to process alerts: %w", err)
+		}
+
+		if len(response.Alerts) < alertsPageSize {
+			return total, newTotal, p.finishWindow(response.To, now)
+		}
+
+		// Alerts published at the latest instant of the page are fetched again, and dropped
+		// by the deduplicator
+		next := latestEventTime(response.Alerts)
+		if !next.After(start) {
+			p.logger.Warn("Time window page holds alerts of a single instant, some alerts may have been missed",
+				"backendId", p.backendID,
+				"backendName", p.backendName,
+				"windowStart", start,
+				"windowEnd", now)
+			return total, newTotal, p.finishWindow(response.To, now)
+		}
+
+		start = next
+		if err := p.stateStore.SaveWindowStart(start); err != nil {
+			return total, newTotal, fmt.Errorf("failed to save time window start: %w", err)
+		}
+
+		if ctx.Err() != nil || page >= maxPollPages {
+			p.logger.Info("Poll page limit reached, remaining alerts of the time window will be fetched next cycle",
+				"backendId", p.backendID,
+				"backendName", p.backendName,
+				"pages", page,
+				"windowStart", start)
+			return total, newTotal, nil
+		}
+	}
+}
+
+// finishWindow records the end of a time window fetched in full: with a cursor from the API
+// regular polling resumes, otherwise the next cycle fetches the window starting at now.
+func (p *Poller) finishWindow(cursor string, now time.Time) error {
+	if cursor == "" {
+		if err := p.stateStore.SaveWindowStart(now.Add(-windowOverlap)); err != nil {
+			return fmt.Errorf("failed to save time window start: %w", err)
+		}
+		return nil
+	}
+
+	if err := p.stateStore.SaveCursor(cursor); err != nil {
+		return fmt.Errorf("failed to save cursor: %w", err)
+	}
+	if err := p.stateStore.SaveWindowStart(time.Time{}); err != nil {
+		return fmt.Errorf("failed to leave time window polling: %w", err)
+	}
+	p.logger.Info("API returned a new cursor, resuming cursor polling",
+		"backendId", p.backendID,
+		"backendName", p.backendName)
+	return nil
+}
+
+// latestEventTime returns the latest event time of the alerts
+func latestEventTime(alerts []Alert) time.Time {
+	var latest time.Time
+	for _, alert := range alerts {
+		if alert.EventTime.After(latest) {
+			latest = alert.EventTime
+		}
+	}
+	return latest
+}
@@ -0,0 +1,254 @@
+package dataminr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// rejectingAPIClient rejects the cursor "expired-cursor" and returns its pages for other requests
+type rejectingAPIClient struct {
+	pagedAPIClient
+	rejected int
+}
+
+func (m *rejectingAPIClient) FetchAlerts(ctx context.Context, cursor string) (*AlertsResponse, error) {
+	if cursor == "expired-cursor" {
+		m.rejected++
+		return nil, &CursorError{StatusCode: 410, Message: "cursor rejected (HTTP 410): cursor expired"}
+	}
+	return m.pagedAPIClient.FetchAlerts(ctx, cursor)
+}
+
+// windowAPIClient rejects the cursor and returns a response for each time window requested
+type windowAPIClient struct {
+	rejectingAPIClient
+	windows  []*AlertsResponse
+	requests [][2]time.Time
+	err      error
+}
+
+func (m *windowAPIClient) FetchAlertsWindow(_ context.Context, start, end time.Time) (*AlertsResponse, error) {
+	m.requests = append(m.requests, [2]time.Time{start, end})
+	if m.err != nil {
+		return nil, m.err
+	}
+	if len(m.requests) > len(m.windows) {
+		return &AlertsResponse{}, nil
+	}
+	return m.windows[len(m.requests)-1], nil
+}
+
+// makeWindowPage returns a page of alerts with event times a second apart, starting at start
+func makeWindowPage(prefix string, count int, start time.Time, to string) *AlertsResponse {
+	page := makeAlertPage(prefix, count, to)
+	for i := range page.Alerts {
+		page.Alerts[i].EventTime = start.Add(time.Duration(i) * time.Second)
+	}
+	return page
+}
+
+// newWindowTestPoller creates a poller with a map-backed state store holding the given state,
+// and returns it with the IDs of the alerts it posts
+func newWindowTestPoller(t *testing.T, fetcher AlertFetcher, state PollState) (*Poller, *StateStore, *[]string) {
+	api := plugintest.NewAPI(t)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogWarn", "Cursor rejected by the API, falling back to polling by time window",
+		"backendId", "test-id", "backendName", "Test Backend", "error", "cursor rejected (HTTP 410): cursor expired").Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	kvStore := mockKVStore(api)
+	kvStore["backend_test-id_state"] = mustMarshalPollState(state)
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+	stateStore := NewStateStore(api, "test-id")
+
+	var posted []string
+	mockPoster := &MockPoster{
+		PostAlertFn: func(alert backend.Alert, channelID string) error {
+			posted = append(posted, alert.AlertID)
+			return nil
+		},
+	}
+	processor := NewAlertProcessor(client, "test-id", "dataminr", "Test Backend", mockPoster, "test-channel-id", NewMockDeduplicator(), nil)
+
+	poller := NewPoller(client, api, "test-id", "Test Backend", 30*time.Second, fetcher, processor, stateStore, nil)
+	poller.SetCatchUp(time.Hour, false)
+	return poller, stateStore, &posted
+}
+
+func TestPoller_poll_CursorRejectedFallsBackToWindow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		lastSuccess   time.Time
+		expectedStart time.Time
+	}{
+		{
+			name:          "window starts before the last successful poll",
+			lastSuccess:   now.Add(-10 * time.Minute),
+			expectedStart: now.Add(-11 * time.Minute),
+		},
+		{
+			name:          "window is limited to the catch-up window",
+			lastSuccess:   now.Add(-24 * time.Hour),
+			expectedStart: now.Add(-time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := &windowAPIClient{windows: []*AlertsResponse{makeAlertPage("window", 2, "")}}
+			poller, stateStore, posted := newWindowTestPoller(t, fetcher, PollState{Cursor: "expired-cursor", LastSuccess: tt.lastSuccess})
+
+			alerts, newAlerts, err := poller.poll(context.Background(), now)
+			require.NoError(t, err)
+			assert.Equal(t, 2, alerts)
+			assert.Equal(t, 2, newAlerts)
+			assert.Equal(t, []string{"window-0", "window-1"}, *posted)
+
+			require.Len(t, fetcher.requests, 1)
+			assert.Equal(t, tt.expectedStart, fetcher.requests[0][0])
+			assert.Equal(t, now, fetcher.requests[0][1])
+
+			// Without a new cursor, the next cycle continues with the next window
+			state, err := stateStore.GetPollState()
+			require.NoError(t, err)
+			assert.Empty(t, state.Cursor)
+			assert.Equal(t, now.Add(-windowOverlap), state.WindowStart)
+		})
+	}
+}
+
+func TestPoller_poll_WindowResumesCursor(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	windowStart := now.Add(-2 * time.Minute)
+
+	fetcher := &windowAPIClient{windows: []*AlertsResponse{makeAlertPage("window", 1, "new-cursor")}}
+	poller, stateStore, posted := newWindowTestPoller(t, fetcher, PollState{WindowStart: windowStart, LastSuccess: now.Add(-time.Minute)})
+
+	alerts, _, err := poller.poll(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, alerts)
+	assert.Equal(t, []string{"window-0"}, *posted)
+
+	// Polling by time window continues from the saved start instead of catching up
+	assert.Zero(t, fetcher.rejected)
+	assert.Empty(t, fetcher.cursors)
+	require.Len(t, fetcher.requests, 1)
+	assert.Equal(t, windowStart, fetcher.requests[0][0])
+
+	state, err := stateStore.GetPollState()
+	require.NoError(t, err)
+	assert.Equal(t, "new-cursor", state.Cursor)
+	assert.True(t, state.WindowStart.IsZero())
+}
+
+func TestPoller_poll_CursorRejectedCatchesUp(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("fetcher without time windows", func(t *testing.T) {
+		fetcher := &rejectingAPIClient{pagedAPIClient: pagedAPIClient{pages: []*AlertsResponse{{To: "catch-up-cursor"}}}}
+		poller, stateStore, _ := newWindowTestPoller(t, fetcher, PollState{Cursor: "expired-cursor", LastSuccess: now.Add(-time.Minute)})
+
+		_, _, err := poller.poll(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, fetcher.rejected)
+		assert.Equal(t, []string{""}, fetcher.cursors)
+
+		state, err := stateStore.GetPollState()
+		require.NoError(t, err)
+		assert.Equal(t, "catch-up-cursor", state.Cursor)
+		assert.True(t, state.WindowStart.IsZero())
+	})
+
+	t.Run("no successful poll yet", func(t *testing.T) {
+		fetcher := &windowAPIClient{rejectingAPIClient: rejectingAPIClient{pagedAPIClient: pagedAPIClient{pages: []*AlertsResponse{{To: "catch-up-cursor"}}}}}
+		poller, stateStore, _ := newWindowTestPoller(t, fetcher, PollState{Cursor: "expired-cursor"})
+
+		_, _, err := poller.poll(context.Background(), now)
+		require.NoError(t, err)
+		assert.Empty(t, fetcher.requests)
+		assert.Equal(t, 1, fetcher.rejected)
+		assert.Equal(t, []string{""}, fetcher.cursors)
+
+		state, err := stateStore.GetPollState()
+		require.NoError(t, err)
+		assert.Equal(t, "catch-up-cursor", state.Cursor)
+	})
+}
+
+func TestPoller_poll_WindowFetchFailureKeepsWindow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	fetcher := &windowAPIClient{err: errors.New("connection reset")}
+	poller, stateStore, _ := newWindowTestPoller(t, fetcher, PollState{Cursor: "expired-cursor", LastSuccess: now.Add(-10 * time.Minute)})
+
+	_, _, err := poller.poll(context.Background(), now)
+	require.Error(t, err)
+
+	// The next cycle fetches the same window instead of catching up
+	state, err := stateStore.GetPollState()
+	require.NoError(t, err)
+	assert.Empty(t, state.Cursor)
+	assert.Equal(t, now.Add(-11*time.Minute), state.WindowStart)
+}
+
+func TestPoller_poll_WindowPagesUntilShortPage(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	windowStart := now.Add(-30 * time.Minute)
+
+	t.Run("full pages continue from their latest alert", func(t *testing.T) {
+		firstLatest := windowStart.Add(time.Duration(alertsPageSize-1) * time.Second)
+		fetcher := &windowAPIClient{windows: []*AlertsResponse{
+			makeWindowPage("page1", alertsPageSize, windowStart, "cursor-1"),
+			makeWindowPage("page2", 2, firstLatest.Add(time.Second), "cursor-2"),
+		}}
+		poller, stateStore, posted := newWindowTestPoller(t, fetcher, PollState{WindowStart: windowStart, LastSuccess: now.Add(-time.Minute)})
+
+		alerts, _, err := poller.poll(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, alertsPageSize+2, alerts)
+		assert.Len(t, *posted, alertsPageSize+2)
+
+		require.Len(t, fetcher.requests, 2)
+		assert.Equal(t, windowStart, fetcher.requests[0][0])
+		assert.Equal(t, firstLatest, fetcher.requests[1][0])
+		assert.Equal(t, now, fetcher.requests[1][1])
+
+		// The cursor of the short page resumes cursor polling
+		state, err := stateStore.GetPollState()
+		require.NoError(t, err)
+		assert.Equal(t, "cursor-2", state.Cursor)
+		assert.True(t, state.WindowStart.IsZero())
+	})
+
+	t.Run("page limit saves the window progress", func(t *testing.T) {
+		pages := make([]*AlertsResponse, maxPollPages)
+		for i := range pages {
+			pages[i] = makeWindowPage(fmt.Sprintf("page%d", i+1), alertsPageSize, windowStart.Add(time.Duration(i)*time.Minute), "")
+		}
+		fetcher := &windowAPIClient{windows: pages}
+		poller, stateStore, _ := newWindowTestPoller(t, fetcher, PollState{WindowStart: windowStart, LastSuccess: now.Add(-time.Minute)})
+
+		alerts, _, err := poller.poll(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, maxPollPages*alertsPageSize, alerts)
+		require.Len(t, fetcher.requests, maxPollPages)
+
+		state, err := stateStore.GetPollState()
+		require.NoError(t, err)
+		assert.Equal(t, latestEventTime(pages[maxPollPages-1].Alerts), state.WindowStart)
+	})
+}
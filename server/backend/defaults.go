@@ -0,0 +1,95 @@
+package backend
+
+import "fmt"
+
+// Defaults are backend settings inherited by every backend that doesn't set them itself.
+// They reduce repetition when many backends share the same polling, filter, quiet hours,
+// hashtag and topic style settings.
+type Defaults struct {
+	// PollIntervalSeconds is used by backends without a poll interval
+	PollIntervalSeconds int `json:"pollIntervalSeconds,omitempty"`
+
+	// AlertListIDs is used by backends without alert lists
+	AlertListIDs []string `json:"alertListIds,omitempty"`
+
+	// QuietHours is used by backends without quiet hours
+	QuietHours *QuietHours `json:"quietHours,omitempty"`
+
+	// HashtagLocale is used by backends without a hashtag locale
+	HashtagLocale string `json:"hashtagLocale,omitempty"`
+
+	// Hashtags is used by backends without hashtag settings
+	Hashtags *HashtagSettings `json:"hashtags,omitempty"`
+
+	// TopicStyles is used by backends without topic styles
+	TopicStyles []TopicStyle `json:"topicStyles,omitempty"`
+}
+
+// Validate checks the default settings. Defaults are validated before they are applied,
+// so errors point at the defaults block rather than every backend inheriting them.
+func (d *Defaults) Validate() error {
+	if d.PollIntervalSeconds != 0 && d.PollIntervalSeconds < MinPollIntervalSeconds {
+		return fmt.Errorf("defaults: poll interval must be at least %d seconds (got %d)", MinPollIntervalSeconds, d.PollIntervalSeconds)
+	}
+
+	if err := validateAlertListIDs(d.AlertListIDs); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+
+	if d.QuietHours != nil {
+		if err := d.QuietHours.Validate(); err != nil {
+			return fmt.Errorf("defaults: %w", err)
+		}
+	}
+
+	if d.HashtagLocale != "" && !SupportedHashtagLocales[d.HashtagLocale] {
+		return fmt.Errorf("defaults: unsupported hashtag locale '%s'", d.HashtagLocale)
+	}
+
+	if d.Hashtags != nil {
+		if err := d.Hashtags.Validate(); err != nil {
+			return fmt.Errorf("defaults: %w", err)
+		}
+	}
+
+	if err := validateTopicStyles(d.TopicStyles); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+
+	return nil
+}
+
+// Apply returns copies of the backend configurations with unset settings filled in from
+// the defaults. Settings a backend sets itself always take precedence. A nil Defaults
+// returns the configurations unchanged.
+func (d *Defaults) Apply(configs []Config) []Config {
+	if d == nil || configs == nil {
+		return configs
+	}
+
+	applied := make([]Config, len(configs))
+	for i, cfg := range configs {
+		if cfg.PollIntervalSeconds == 0 {
+			cfg.PollIntervalSeconds = d.PollIntervalSeconds
+		}
+		if len(cfg.AlertListIDs) == 0 && len(d.AlertListIDs) > 0 {
+			cfg.AlertListIDs = append([]string(nil), d.AlertListIDs...)
+		}
+		if cfg.QuietHours == nil && d.QuietHours != nil {
+			quietHours := *d.QuietHours
+			cfg.QuietHours = &quietHours
+		}
+		if cfg.HashtagLocale == "" {
+			cfg.HashtagLocale = d.HashtagLocale
+		}
+		if cfg.Hashtags == nil && d.Hashtags != nil {
+			hashtags := *d.Hashtags
+			cfg.Hashtags = &hashtags
+		}
+		if len(cfg.TopicStyles) == 0 && len(d.TopicStyles) > 0 {
+			cfg.TopicStyles = append([]TopicStyle(nil), d.TopicStyles...)
+		}
+		applied[i] = cfg
+	}
+	return applied
+}
@@ -0,0 +1,108 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaults_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaults    Defaults
+		errContains string
+	}{
+		{name: "empty defaults"},
+		{
+			name: "valid defaults",
+			defaults: Defaults{
+				PollIntervalSeconds: 60,
+				AlertListIDs:        []string{"list-1"},
+				QuietHours:          &QuietHours{Ranges: []TimeRange{{Start: "22:00", End: "06:00"}}},
+				HashtagLocale:       "fr",
+				Hashtags:            &HashtagSettings{MaxHashtags: 5},
+				TopicStyles:         []TopicStyle{{Match: "Cyber", Emoji: ":computer:", Color: "#7B3FE4"}},
+			},
+		},
+		{name: "poll interval too low", defaults: Defaults{PollIntervalSeconds: 5}, errContains: "defaults: poll interval must be at least"},
+		{name: "invalid alert list", defaults: Defaults{AlertListIDs: []string{"a,b"}}, errContains: "defaults: invalid alert list ID"},
+		{name: "invalid quiet hours", defaults: Defaults{QuietHours: &QuietHours{}}, errContains: "defaults:"},
+		{name: "unsupported locale", defaults: Defaults{HashtagLocale: "xx"}, errContains: "defaults: unsupported hashtag locale 'xx'"},
+		{name: "invalid topic style", defaults: Defaults{TopicStyles: []TopicStyle{{Match: "Cyber"}}}, errContains: "defaults: topic style 'Cyber' must set an emoji or a color"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.defaults.Validate()
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestDefaults_Apply(t *testing.T) {
+	defaults := &Defaults{
+		PollIntervalSeconds: 60,
+		AlertListIDs:        []string{"list-1"},
+		QuietHours:          &QuietHours{Ranges: []TimeRange{{Start: "22:00", End: "06:00"}}},
+		HashtagLocale:       "fr",
+		Hashtags:            &HashtagSettings{DisableTopics: true},
+		TopicStyles:         []TopicStyle{{Match: "Cyber", Color: "#7B3FE4"}},
+	}
+
+	configs := []Config{
+		{ID: "inherits"},
+		{
+			ID:                  "overrides",
+			PollIntervalSeconds: 30,
+			AlertListIDs:        []string{"list-2"},
+			QuietHours:          &QuietHours{Ranges: []TimeRange{{Start: "01:00", End: "02:00"}}},
+			HashtagLocale:       "de",
+			Hashtags:            &HashtagSettings{MaxHashtags: 3},
+			TopicStyles:         []TopicStyle{{Match: "Weather", Emoji: ":cloud:"}},
+		},
+	}
+
+	applied := defaults.Apply(configs)
+	require.Len(t, applied, 2)
+
+	assert.Equal(t, 60, applied[0].PollIntervalSeconds)
+	assert.Equal(t, []string{"list-1"}, applied[0].AlertListIDs)
+	assert.Equal(t, *defaults.QuietHours, *applied[0].QuietHours)
+	assert.Equal(t, "fr", applied[0].HashtagLocale)
+	assert.True(t, applied[0].Hashtags.DisableTopics)
+	assert.Equal(t, defaults.TopicStyles, applied[0].TopicStyles)
+
+	assert.Equal(t, configs[1], applied[1], "settings of the backend take precedence")
+
+	// The inputs are left untouched and nothing is shared with the defaults
+	assert.Zero(t, configs[0].PollIntervalSeconds)
+	applied[0].AlertListIDs[0] = "changed"
+	applied[0].QuietHours.Timezone = "UTC"
+	applied[0].TopicStyles[0].Color = "#000000"
+	assert.Equal(t, "list-1", defaults.AlertListIDs[0])
+	assert.Equal(t, "#7B3FE4", defaults.TopicStyles[0].Color)
+	assert.Empty(t, defaults.QuietHours.Timezone)
+
+	var none *Defaults
+	assert.Equal(t, configs, none.Apply(configs))
+}
+
+func TestValidateBackendsJSON_Defaults(t *testing.T) {
+	data := []byte(`[{"id": "550e8400-e29b-41d4-a716-446655440000", "name": "Inherits", "type": "dataminr",
+		"url": "https://api.example.com", "apiId": "id", "apiKey": "key", "channelId": "channel"}]`)
+
+	_, results, err := ValidateBackendsJSON(data, nil, ValidationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backend configuration at position 1: missing required field 'pollIntervalSeconds'"}, results[0].Errors)
+
+	configs, results, err := ValidateBackendsJSON(data, &Defaults{PollIntervalSeconds: 60}, ValidationOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, results[0].Errors)
+	assert.Zero(t, configs[0].PollIntervalSeconds, "configurations are returned without the defaults applied")
+}
@@ -0,0 +1,24 @@
+package backend
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// validateServiceEndpoint checks the URL and header names of a text service endpoint such as
+// the summarizer or translator. The service name is used in the error messages.
+func validateServiceEndpoint(service, rawURL string, headers map[string]string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%s URL must be an http or https URL (got '%s')", service, rawURL)
+	}
+
+	for name := range headers {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " :\r\n") {
+			return fmt.Errorf("invalid %s header name '%s'", service, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateServiceEndpoint(t *testing.T) {
+	assert.NoError(t, validateServiceEndpoint("summarizer", "https://ai.example.com/v1/chat/completions", map[string]string{"Authorization": "Bearer token"}))
+	assert.EqualError(t, validateServiceEndpoint("summarizer", "ftp://ai.example.com", nil), "summarizer URL must be an http or https URL (got 'ftp://ai.example.com')")
+	assert.EqualError(t, validateServiceEndpoint("translator", "https://translate.example.com", map[string]string{"X Key": "value"}), "invalid translator header name 'X Key'")
+}
@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/mattermost/mattermost/server/public/plugin"
@@ -10,7 +11,21 @@ import (
 // AlertPoster is an interface for posting alerts to Mattermost channels.
 // This abstraction allows backends to post alerts without directly depending on the poster package.
 type AlertPoster interface {
-	PostAlert(alert Alert, channelID string) error
+	// PostAlert posts an alert. Cancelling the context abandons work that can be skipped,
+	// such as downloading media.
+	PostAlert(ctx context.Context, alert Alert, channelID string) error
+
+	// PostMessage posts a plain text message from the bot, e.g. a summary of skipped alerts
+	PostMessage(message, channelID string) error
+
+	// PostAlertUpdate replies in the thread of an alert's post with a summary of the changes
+	// when the alert is fetched again with new content, e.g. a changed headline or new topics.
+	// Does nothing if the alert wasn't posted or its content didn't change.
+	PostAlertUpdate(alert Alert) error
+
+	// PostAdminMessage posts a plain text message from the bot to the admin channel, e.g. a
+	// warning that a backend is about to be disabled. Does nothing without an admin channel.
+	PostAdminMessage(message string) error
 }
 
 // Deduplicator is an interface for tracking seen alert IDs across all backends.
@@ -19,14 +34,22 @@ type Deduplicator interface {
 	// RecordAlert atomically checks if an alert is new and marks it as seen if so.
 	// Returns true if this is a new alert (successfully recorded), false if it's a duplicate.
 	RecordAlert(backendType, alertID string) bool
+
+	// IsSeen reports whether an alert was recorded by RecordAlert, without recording it.
+	IsSeen(backendType, alertID string) bool
+
+	// ForgetAlert removes an alert recorded by RecordAlert that was never posted,
+	// so it is processed again when it is fetched next.
+	ForgetAlert(backendType, alertID string)
 }
 
-// DisableCallback is a function type for disabling a backend when it reaches MaxConsecutiveFailures.
+// DisableCallback is a function type for disabling a backend when it reaches its failure threshold.
 // The callback receives the backend ID and should persist the configuration change.
 type DisableCallback func(backendID string) error
 
-// Factory is a function type that creates a backend instance
-type Factory func(config Config, api *pluginapi.Client, papi plugin.API, poster AlertPoster, deduplicator Deduplicator, disableCallback DisableCallback) (Backend, error)
+// Factory is a function type that creates a backend instance. The user agent identifies the
+// plugin and Mattermost server versions in requests to the backend's API.
+type Factory func(config Config, api *pluginapi.Client, papi plugin.API, poster AlertPoster, deduplicator Deduplicator, disableCallback DisableCallback, userAgent string) (Backend, error)
 
 // factoryRegistry maps backend types to their factory functions
 var factoryRegistry = make(map[string]Factory)
@@ -39,7 +62,7 @@ func RegisterBackendFactory(backendType string, factory Factory) {
 
 // Create creates a new backend instance based on the provided configuration.
 // Returns an error if the backend type is unknown or if creation fails.
-func Create(config Config, api *pluginapi.Client, papi plugin.API, poster AlertPoster, deduplicator Deduplicator, disableCallback DisableCallback) (Backend, error) {
+func Create(config Config, api *pluginapi.Client, papi plugin.API, poster AlertPoster, deduplicator Deduplicator, disableCallback DisableCallback, userAgent string) (Backend, error) {
 	if config.Type == "" {
 		return nil, fmt.Errorf("backend type is required")
 	}
@@ -49,5 +72,5 @@ func Create(config Config, api *pluginapi.Client, papi plugin.API, poster AlertP
 		return nil, fmt.Errorf("unknown backend type: %s", config.Type)
 	}
 
-	return factory(config, api, papi, poster, deduplicator, disableCallback)
+	return factory(config, api, papi, poster, deduplicator, disableCallback, userAgent)
 }
@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"context"
 	"testing"
 
 	"github.com/mattermost/mattermost/server/public/plugin"
@@ -13,7 +14,19 @@ import (
 // mockPoster is a simple poster implementation for testing
 type mockPoster struct{}
 
-func (m *mockPoster) PostAlert(alert Alert, channelID string) error {
+func (m *mockPoster) PostAlert(ctx context.Context, alert Alert, channelID string) error {
+	return nil
+}
+
+func (m *mockPoster) PostMessage(message, channelID string) error {
+	return nil
+}
+
+func (m *mockPoster) PostAlertUpdate(alert Alert) error {
+	return nil
+}
+
+func (m *mockPoster) PostAdminMessage(message string) error {
 	return nil
 }
 
@@ -37,8 +50,16 @@ func (m *mockDeduplicator) RecordAlert(backendType, alertID string) bool {
 	return true
 }
 
+func (m *mockDeduplicator) IsSeen(backendType, alertID string) bool {
+	return m.seen[backendType+":"+alertID]
+}
+
+func (m *mockDeduplicator) ForgetAlert(backendType, alertID string) {
+	delete(m.seen, backendType+":"+alertID)
+}
+
 // mockFactory creates a mock backend using the existing mockBackend from registry_test.go
-func mockFactory(config Config, api *pluginapi.Client, papi plugin.API, poster AlertPoster, deduplicator Deduplicator, disableCallback DisableCallback) (Backend, error) {
+func mockFactory(config Config, api *pluginapi.Client, papi plugin.API, poster AlertPoster, deduplicator Deduplicator, disableCallback DisableCallback, userAgent string) (Backend, error) {
 	return newMockBackend(config.ID, config.Name, config.Type), nil
 }
 
@@ -98,7 +119,7 @@ func TestCreate(t *testing.T) {
 			Type: "mock",
 		}
 
-		backend, err := Create(config, client, api, &mockPoster{}, newMockDeduplicator(), nil)
+		backend, err := Create(config, client, api, &mockPoster{}, newMockDeduplicator(), nil, "")
 		require.NoError(t, err)
 		require.NotNil(t, backend)
 
@@ -116,7 +137,7 @@ func TestCreate(t *testing.T) {
 			Type: "unknown",
 		}
 
-		backend, err := Create(config, client, api, &mockPoster{}, newMockDeduplicator(), nil)
+		backend, err := Create(config, client, api, &mockPoster{}, newMockDeduplicator(), nil, "")
 		assert.Error(t, err)
 		assert.Nil(t, backend)
 		assert.Contains(t, err.Error(), "unknown backend type: unknown")
@@ -131,7 +152,7 @@ func TestCreate(t *testing.T) {
 			Type: "",
 		}
 
-		backend, err := Create(config, client, api, &mockPoster{}, newMockDeduplicator(), nil)
+		backend, err := Create(config, client, api, &mockPoster{}, newMockDeduplicator(), nil, "")
 		assert.Error(t, err)
 		assert.Nil(t, backend)
 		assert.Contains(t, err.Error(), "backend type is required")
@@ -0,0 +1,86 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StandbyIDSuffix is appended to a backend ID to form the ID of its warm standby
+const StandbyIDSuffix = "-standby"
+
+// FailoverSettings configures a warm standby for a backend: a second set of Dataminr
+// credentials polled with the backend's channel and filters while the backend is
+// auto-disabled or keeps failing to authenticate
+type FailoverSettings struct {
+	// APIId is the API user ID of the standby credentials
+	APIId string `json:"apiId"`
+
+	// APIKey is the API password of the standby credentials (or an "env:" reference)
+	APIKey string `json:"apiKey"`
+
+	// URL is the API URL of the standby (default: the backend URL)
+	URL string `json:"url,omitempty"`
+
+	// AuthFailureThreshold is how many consecutive polls failing without a valid token
+	// start the standby (default: DefaultFailoverAuthFailures). It can't exceed the failure
+	// threshold of the backend, which is disabled once reached.
+	AuthFailureThreshold int `json:"authFailureThreshold,omitempty"`
+}
+
+// Validate checks that the standby credentials are set and the threshold is within range of
+// the failure threshold of the backend. The standby URL is checked with the backend URL.
+func (f *FailoverSettings) Validate(failureThreshold int) error {
+	if strings.TrimSpace(f.APIId) == "" {
+		return fmt.Errorf("failover apiId must not be empty")
+	}
+	if strings.TrimSpace(f.APIKey) == "" {
+		return fmt.Errorf("failover apiKey must not be empty")
+	}
+	if f.AuthFailureThreshold < 0 || f.AuthFailureThreshold > failureThreshold {
+		return fmt.Errorf("failover auth failure threshold must be between 0 and the backend failure threshold %d (got %d)", failureThreshold, f.AuthFailureThreshold)
+	}
+	return nil
+}
+
+// AuthFailures returns the auth failure threshold, applying the default when unset. The
+// default is lowered to the failure threshold of the backend so the standby starts before
+// the backend is disabled.
+func (f FailoverSettings) AuthFailures(failureThreshold int) int {
+	if f.AuthFailureThreshold <= 0 {
+		return min(DefaultFailoverAuthFailures, failureThreshold)
+	}
+	return f.AuthFailureThreshold
+}
+
+// StandbyID returns the ID of the warm standby of a backend
+func StandbyID(backendID string) string {
+	return backendID + StandbyIDSuffix
+}
+
+// PrimaryID returns the ID of the backend a warm standby stands in for, or the ID itself for
+// a backend that is not a standby
+func PrimaryID(backendID string) string {
+	return strings.TrimSuffix(backendID, StandbyIDSuffix)
+}
+
+// StandbyConfig returns the configuration of the warm standby of a backend with failover
+// settings: the backend's channel, filters and posting settings with the standby credentials.
+// Returns false if the backend has no failover settings.
+func StandbyConfig(primary Config) (Config, bool) {
+	if primary.Failover == nil {
+		return Config{}, false
+	}
+
+	standby := primary
+	standby.ID = StandbyID(primary.ID)
+	standby.Name = primary.Name + " (standby)"
+	standby.Enabled = true
+	standby.APIId = primary.Failover.APIId
+	standby.APIKey = primary.Failover.APIKey
+	standby.APIKeyStored = false
+	if primary.Failover.URL != "" {
+		standby.URL = primary.Failover.URL
+	}
+	standby.Failover = nil
+	return standby, true
+}
@@ -0,0 +1,68 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailoverSettings_Validate(t *testing.T) {
+	require.NoError(t, (&FailoverSettings{APIId: "standby-id", APIKey: "standby-key"}).Validate(MaxConsecutiveFailures))
+	require.NoError(t, (&FailoverSettings{APIId: "standby-id", APIKey: "env:STANDBY_KEY", AuthFailureThreshold: 2}).Validate(MaxConsecutiveFailures))
+	require.NoError(t, (&FailoverSettings{APIId: "standby-id", APIKey: "standby-key", AuthFailureThreshold: 20}).Validate(50))
+
+	err := (&FailoverSettings{APIKey: "standby-key"}).Validate(MaxConsecutiveFailures)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failover apiId must not be empty")
+
+	err = (&FailoverSettings{APIId: "standby-id", APIKey: " "}).Validate(MaxConsecutiveFailures)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failover apiKey must not be empty")
+
+	err = (&FailoverSettings{APIId: "standby-id", APIKey: "standby-key", AuthFailureThreshold: 3}).Validate(2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failover auth failure threshold must be between 0 and the backend failure threshold 2 (got 3)")
+}
+
+func TestFailoverSettings_AuthFailures(t *testing.T) {
+	assert.Equal(t, DefaultFailoverAuthFailures, FailoverSettings{}.AuthFailures(MaxConsecutiveFailures))
+	assert.Equal(t, 2, FailoverSettings{AuthFailureThreshold: 2}.AuthFailures(MaxConsecutiveFailures))
+	assert.Equal(t, 2, FailoverSettings{}.AuthFailures(2), "the default is lowered to the backend failure threshold")
+}
+
+func TestStandbyConfig(t *testing.T) {
+	primary := Config{
+		ID:           "backend-1",
+		Name:         "Weather Watch",
+		Type:         "dataminr",
+		Enabled:      false,
+		URL:          "https://api.example.com",
+		APIId:        "primary-id",
+		APIKeyStored: true,
+		ChannelID:    "channel-1",
+		AlertListIDs: []string{"list-1"},
+		Failover:     &FailoverSettings{APIId: "standby-id", APIKey: "standby-key"},
+	}
+
+	standby, ok := StandbyConfig(primary)
+	require.True(t, ok)
+	assert.Equal(t, "backend-1-standby", standby.ID)
+	assert.Equal(t, "Weather Watch (standby)", standby.Name)
+	assert.True(t, standby.Enabled)
+	assert.Equal(t, "https://api.example.com", standby.URL)
+	assert.Equal(t, "standby-id", standby.APIId)
+	assert.Equal(t, "standby-key", standby.APIKey)
+	assert.False(t, standby.APIKeyStored)
+	assert.Equal(t, "channel-1", standby.ChannelID)
+	assert.Equal(t, []string{"list-1"}, standby.AlertListIDs)
+	assert.Nil(t, standby.Failover)
+
+	primary.Failover = &FailoverSettings{APIId: "standby-id", APIKey: "standby-key", URL: "https://standby.example.com"}
+	standby, _ = StandbyConfig(primary)
+	assert.Equal(t, "https://standby.example.com", standby.URL)
+
+	primary.Failover = nil
+	_, ok = StandbyConfig(primary)
+	assert.False(t, ok)
+}
@@ -0,0 +1,35 @@
+package backend
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Fingerprint returns a hash of the alert's content: its headline, event time and location.
+// Alerts reissued for the same event under a new alert ID share a fingerprint.
+// Returns an empty string for alerts without a headline.
+func (a Alert) Fingerprint() string {
+	headline := strings.ToLower(strings.Join(strings.Fields(a.Headline), " "))
+	if headline == "" {
+		return ""
+	}
+
+	location := ""
+	if a.Location != nil {
+		location = fmt.Sprintf("%s|%.3f|%.3f",
+			strings.ToLower(strings.Join(strings.Fields(a.Location.Address), " ")),
+			a.Location.Latitude,
+			a.Location.Longitude)
+	}
+
+	eventTime := ""
+	if !a.EventTime.IsZero() {
+		eventTime = a.EventTime.UTC().Format(time.RFC3339)
+	}
+
+	sum := sha256.Sum256([]byte(headline + "\n" + eventTime + "\n" + location))
+	return hex.EncodeToString(sum[:16])
+}
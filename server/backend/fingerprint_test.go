@@ -0,0 +1,44 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlert_Fingerprint(t *testing.T) {
+	eventTime := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	alert := Alert{
+		AlertID:   "alert-1",
+		Headline:  "Flooding reported downtown",
+		EventTime: eventTime,
+		Location:  &Location{Address: "Austin, TX, USA", Latitude: 30.2672, Longitude: -97.7431},
+	}
+
+	fingerprint := alert.Fingerprint()
+	assert.Len(t, fingerprint, 32)
+
+	// Reissued under a new ID with cosmetic differences
+	reissued := alert
+	reissued.AlertID = "alert-2"
+	reissued.AlertType = "Urgent"
+	reissued.Headline = "  Flooding  reported DOWNTOWN "
+	reissued.EventTime = eventTime.In(time.FixedZone("CDT", -5*3600))
+	reissued.Location = &Location{Address: "austin, tx, usa", Latitude: 30.26721, Longitude: -97.74312}
+	assert.Equal(t, fingerprint, reissued.Fingerprint())
+
+	changed := alert
+	changed.Headline = "Flooding reported uptown"
+	assert.NotEqual(t, fingerprint, changed.Fingerprint())
+
+	changed = alert
+	changed.EventTime = eventTime.Add(time.Minute)
+	assert.NotEqual(t, fingerprint, changed.Fingerprint())
+
+	changed = alert
+	changed.Location = nil
+	assert.NotEqual(t, fingerprint, changed.Fingerprint())
+
+	assert.Empty(t, Alert{AlertID: "alert-3"}.Fingerprint())
+}
@@ -0,0 +1,82 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// HashtagPlacementMessage shows the hashtags in the alert post message, where they are
+	// searchable and clickable
+	HashtagPlacementMessage = "message"
+
+	// HashtagPlacementFooter shows the hashtags in the alert attachment footer. They are less
+	// prominent there but not indexed by hashtag search.
+	HashtagPlacementFooter = "footer"
+
+	// HashtagPlacementReply posts the hashtags as a reply in the alert's thread, keeping them
+	// searchable without cluttering the channel
+	HashtagPlacementReply = "reply"
+)
+
+// HashtagSettings customizes the hashtags added to alert posts.
+// The zero value keeps every hashtag category with no limit.
+type HashtagSettings struct {
+	// DisableAlertLevel omits the alert level hashtag (e.g., #Flash)
+	DisableAlertLevel bool `json:"disableAlertLevel,omitempty"`
+
+	// DisableLocation omits the city, state and country hashtags
+	DisableLocation bool `json:"disableLocation,omitempty"`
+
+	// DisableTopics omits the topic hashtags
+	DisableTopics bool `json:"disableTopics,omitempty"`
+
+	// MaxHashtags caps the number of generated hashtags (0 means no limit).
+	// Custom hashtags are always included and do not count toward the cap.
+	MaxHashtags int `json:"maxHashtags,omitempty"`
+
+	// CustomHashtags is a static list of hashtags appended to every alert
+	CustomHashtags []string `json:"customHashtags,omitempty"`
+
+	// Placement is where the hashtags are shown: "message", "footer" or "reply"
+	// (default: HashtagPlacementMessage)
+	Placement string `json:"placement,omitempty"`
+}
+
+// Validate checks that the limit is non-negative, the custom hashtags are well formed and the
+// placement is supported.
+func (h *HashtagSettings) Validate() error {
+	if h.MaxHashtags < 0 {
+		return fmt.Errorf("max hashtags must not be negative (got %d)", h.MaxHashtags)
+	}
+
+	switch h.Placement {
+	case "", HashtagPlacementMessage, HashtagPlacementFooter, HashtagPlacementReply:
+	default:
+		return fmt.Errorf("invalid hashtag placement '%s' (must be %s, %s or %s)", h.Placement, HashtagPlacementMessage, HashtagPlacementFooter, HashtagPlacementReply)
+	}
+
+	for _, tag := range h.CustomHashtags {
+		name := strings.TrimPrefix(tag, "#")
+		if name == "" {
+			return fmt.Errorf("custom hashtag must not be empty")
+		}
+
+		for _, r := range name {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+				return fmt.Errorf("invalid custom hashtag '%s' (only letters, digits, '_' and '-' are allowed)", tag)
+			}
+		}
+	}
+
+	return nil
+}
+
+// HashtagPlacement returns where the hashtags are shown, applying the default when unset
+func (h HashtagSettings) HashtagPlacement() string {
+	if h.Placement == "" {
+		return HashtagPlacementMessage
+	}
+	return h.Placement
+}
@@ -0,0 +1,70 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashtagSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    HashtagSettings
+		expectedErr string
+	}{
+		{
+			name:     "zero value",
+			settings: HashtagSettings{},
+		},
+		{
+			name: "valid settings",
+			settings: HashtagSettings{
+				DisableTopics:  true,
+				MaxHashtags:    5,
+				CustomHashtags: []string{"#SOC", "night_shift", "team-a", "Überwachung"},
+				Placement:      HashtagPlacementReply,
+			},
+		},
+		{
+			name:        "unknown placement",
+			settings:    HashtagSettings{Placement: "header"},
+			expectedErr: "invalid hashtag placement 'header' (must be message, footer or reply)",
+		},
+		{
+			name:        "negative limit",
+			settings:    HashtagSettings{MaxHashtags: -1},
+			expectedErr: "max hashtags must not be negative",
+		},
+		{
+			name:        "empty custom hashtag",
+			settings:    HashtagSettings{CustomHashtags: []string{"#"}},
+			expectedErr: "custom hashtag must not be empty",
+		},
+		{
+			name:        "custom hashtag with space",
+			settings:    HashtagSettings{CustomHashtags: []string{"two words"}},
+			expectedErr: "invalid custom hashtag 'two words'",
+		},
+		{
+			name:        "custom hashtag with comma",
+			settings:    HashtagSettings{CustomHashtags: []string{"a,b"}},
+			expectedErr: "invalid custom hashtag 'a,b'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestHashtagSettings_HashtagPlacement(t *testing.T) {
+	assert.Equal(t, HashtagPlacementMessage, HashtagSettings{}.HashtagPlacement())
+	assert.Equal(t, HashtagPlacementFooter, HashtagSettings{Placement: HashtagPlacementFooter}.HashtagPlacement())
+}
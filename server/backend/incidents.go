@@ -0,0 +1,47 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+)
+
+// IncidentSettings group related alerts into one incident thread per channel: the first alert
+// is the root post and later related alerts are posted as replies. Alerts are related when they
+// share a Dataminr linked alert chain, or a topic and location within the incident window.
+type IncidentSettings struct {
+	// WindowMinutes is how long after its latest alert an incident takes alerts matched by
+	// location and topic (default: DefaultIncidentWindowMinutes)
+	WindowMinutes int `json:"windowMinutes,omitempty"`
+
+	// RadiusKm is how far apart the locations of matched alerts may be (default: DefaultIncidentRadiusKm)
+	RadiusKm float64 `json:"radiusKm,omitempty"`
+}
+
+// Validate checks that the window and radius are within range.
+func (s *IncidentSettings) Validate() error {
+	if s.WindowMinutes < 0 || s.WindowMinutes > MaxIncidentWindowMinutes {
+		return fmt.Errorf("incident window must be between 0 and %d minutes (got %d)", MaxIncidentWindowMinutes, s.WindowMinutes)
+	}
+	if s.RadiusKm < 0 || s.RadiusKm > MaxIncidentRadiusKm {
+		return fmt.Errorf("incident radius must be between 0 and %g km (got %g)", MaxIncidentRadiusKm, s.RadiusKm)
+	}
+	return nil
+}
+
+// Window returns how long an incident takes alerts matched by location and topic, applying
+// the default when unset
+func (s IncidentSettings) Window() time.Duration {
+	if s.WindowMinutes <= 0 {
+		return DefaultIncidentWindowMinutes * time.Minute
+	}
+	return time.Duration(s.WindowMinutes) * time.Minute
+}
+
+// Radius returns how far apart the locations of matched alerts may be in kilometers,
+// applying the default when unset
+func (s IncidentSettings) Radius() float64 {
+	if s.RadiusKm <= 0 {
+		return DefaultIncidentRadiusKm
+	}
+	return s.RadiusKm
+}
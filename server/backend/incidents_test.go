@@ -0,0 +1,32 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentSettings_Validate(t *testing.T) {
+	require.NoError(t, (&IncidentSettings{}).Validate())
+	require.NoError(t, (&IncidentSettings{WindowMinutes: 120, RadiusKm: 2.5}).Validate())
+
+	err := (&IncidentSettings{WindowMinutes: MaxIncidentWindowMinutes + 1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "incident window must be between 0 and 1440 minutes (got 1441)")
+
+	err = (&IncidentSettings{RadiusKm: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "incident radius must be between 0 and 500 km (got -1)")
+}
+
+func TestIncidentSettings_Defaults(t *testing.T) {
+	settings := IncidentSettings{}
+	assert.Equal(t, time.Hour, settings.Window())
+	assert.Equal(t, 10.0, settings.Radius())
+
+	settings = IncidentSettings{WindowMinutes: 30, RadiusKm: 2.5}
+	assert.Equal(t, 30*time.Minute, settings.Window())
+	assert.Equal(t, 2.5, settings.Radius())
+}
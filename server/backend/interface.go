@@ -1,19 +1,63 @@
 package backend
 
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrRestartRequired is returned by backends that can't apply a configuration change in place
+var ErrRestartRequired = errors.New("backend must be restarted to apply the configuration")
+
 // Backend defines the interface that all backend implementations must satisfy.
 // Each backend type (e.g., Dataminr) implements this interface to provide
 // standardized alert polling and management capabilities.
 type Backend interface {
 	// Start begins the backend's polling lifecycle.
 	// This should initialize any necessary resources and start the polling job.
+	// The context bounds starting only; polling continues after it is cancelled.
 	// Returns an error if the backend cannot be started.
-	Start() error
+	Start(ctx context.Context) error
 
 	// Stop gracefully shuts down the backend.
-	// This should cancel any running polling jobs and clean up resources.
-	// Returns an error if the shutdown encounters issues.
-	Stop() error
+	// This should cancel any running polling jobs and clean up resources. Once the context
+	// is done, in-flight work is cancelled and Stop returns without waiting for it.
+	// Returns an error if the shutdown encounters issues or the context is done first.
+	Stop(ctx context.Context) error
+
+	// UpdateConfig applies the settings of a configuration that can change while the backend
+	// runs (see UpdatableInPlace), keeping its cursor and authentication token. A running
+	// backend finishes its in-flight poll cycle first, at most until the context is done.
+	// Other settings of the configuration are ignored.
+	// Returns ErrRestartRequired if the backend can't be updated in place.
+	UpdateConfig(ctx context.Context, config Config) error
+
+	Operations
+}
 
+// StopWaiter is implemented by backends whose in-flight work can outlive a Stop that gave up
+// waiting for it. Stopped returns a channel closed once that work has finished, so a backend
+// with the same ID is not started alongside it.
+type StopWaiter interface {
+	Stopped() <-chan struct{}
+}
+
+// StatusReporter is implemented by backends that change status on their own, e.g. as their
+// polls fail or a cool-down starts. The registry sets a listener when the backend is registered,
+// which the backend calls whenever its status may have changed so observers are notified.
+type StatusReporter interface {
+	SetStatusListener(listener func())
+}
+
+// PollHistoryRecorder is implemented by backends that can record their poll history in a
+// store other than their own state. The store is set before the backend is started.
+type PollHistoryRecorder interface {
+	SetPollHistoryStore(store PollHistoryStore)
+}
+
+// Operations are the methods of a backend besides its lifecycle, shared by Backend and
+// LegacyBackend.
+type Operations interface {
 	// GetID returns the unique identifier for this backend (UUID v4).
 	// This ID is immutable and used for internal operations.
 	GetID() string
@@ -34,4 +78,37 @@ type Backend interface {
 	// start when eventually re-enabled, while preserving failure tracking for display.
 	// Returns an error if state cannot be cleared.
 	ClearOperationalState() error
+
+	// Pause temporarily stops polling without changing the saved configuration.
+	// Polling resumes automatically at until; a zero until pauses until Resume is called.
+	// The pause is shared by all cluster nodes and survives configuration changes.
+	Pause(until time.Time) error
+
+	// Resume ends a pause so polling continues from the saved cursor.
+	Resume() error
+
+	// GetState returns the backend's persisted polling state for inspection.
+	// The authentication token is never included.
+	GetState() (StateSnapshot, error)
+
+	// ResetState resets part of the backend's persisted polling state.
+	// A reset cursor makes the next poll cycle run the catch-up routine.
+	ResetState(scope StateResetScope) error
+
+	// GetPollHistory returns the recorded poll cycle outcomes, oldest first.
+	// At most MaxPollHistorySamples from the last PollHistoryRetention are kept.
+	GetPollHistory() ([]PollSample, error)
+
+	// GetRecentLogs returns the backend's most recent log lines captured on this server node,
+	// oldest first. At most LogCaptureLines are kept.
+	GetRecentLogs() []LogEntry
+
+	// FetchTaxonomy fetches the alert lists and topics available to the backend's account.
+	// Returns ErrTaxonomyUnsupported if the backend's source has no taxonomy.
+	FetchTaxonomy(ctx context.Context) (Taxonomy, error)
+
+	// CheckCredentials validates the backend's credentials with an authentication-only call
+	// and records the outcome, reported by GetStatus. Returns ErrCredentialCheckUnsupported if
+	// the backend's source needs no credentials.
+	CheckCredentials(ctx context.Context) (CredentialCheck, error)
 }
@@ -0,0 +1,98 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// LegacyBackend is the Backend interface before Start and Stop took a context. Backend types
+// still implementing it are adapted with FromLegacy.
+type LegacyBackend interface {
+	// Start begins the backend's polling lifecycle
+	Start() error
+
+	// Stop gracefully shuts down the backend, blocking until it has stopped
+	Stop() error
+
+	Operations
+}
+
+// legacyBackend adapts a LegacyBackend to the Backend interface
+type legacyBackend struct {
+	Operations
+	legacy LegacyBackend
+
+	// mu guards stopped, closed once the last legacy Stop returned (nil if never stopped)
+	mu      sync.Mutex
+	stopped chan struct{}
+}
+
+// FromLegacy adapts a backend implementing the LegacyBackend interface. The adapted backend
+// doesn't start once the context is done, and stopping it returns when the context is done
+// even if the legacy Stop is still running.
+func FromLegacy(legacy LegacyBackend) Backend {
+	return &legacyBackend{Operations: legacy, legacy: legacy}
+}
+
+// Start starts the legacy backend unless the context is already done
+func (b *legacyBackend) Start(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("backend not started: %w", err)
+	}
+	return b.legacy.Start()
+}
+
+// Stop stops the legacy backend, giving up waiting for it once the context is done
+func (b *legacyBackend) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	b.mu.Lock()
+	b.stopped = done
+	b.mu.Unlock()
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- b.legacy.Stop()
+		close(done)
+	}()
+
+	select {
+	case err := <-stopped:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("backend did not stop in time: %w", ctx.Err())
+	}
+}
+
+// Stopped returns a channel closed once the legacy Stop returned, which may be after Stop
+// gave up waiting for it
+func (b *legacyBackend) Stopped() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stopped == nil {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	return b.stopped
+}
+
+// UpdateConfig reports that legacy backends must be restarted to change their configuration
+func (b *legacyBackend) UpdateConfig(_ context.Context, _ Config) error {
+	return ErrRestartRequired
+}
+
+// SetStatusListener passes the status listener on to legacy backends reporting their status changes
+func (b *legacyBackend) SetStatusListener(listener func()) {
+	if reporter, ok := b.legacy.(StatusReporter); ok {
+		reporter.SetStatusListener(listener)
+	}
+}
+
+// SetPollHistoryStore passes the poll history store on to legacy backends recording their poll history
+func (b *legacyBackend) SetPollHistoryStore(store PollHistoryStore) {
+	if recorder, ok := b.legacy.(PollHistoryRecorder); ok {
+		recorder.SetPollHistoryStore(store)
+	}
+}
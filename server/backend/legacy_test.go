@@ -0,0 +1,80 @@
+package backend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyTestBackend implements LegacyBackend; Stop blocks until release is closed
+type legacyTestBackend struct {
+	*mockBackend
+	started bool
+	release chan struct{}
+}
+
+func (l *legacyTestBackend) Start() error {
+	l.started = true
+	return nil
+}
+
+func (l *legacyTestBackend) Stop() error {
+	<-l.release
+	return nil
+}
+
+func TestFromLegacy(t *testing.T) {
+	newLegacy := func() *legacyTestBackend {
+		return &legacyTestBackend{mockBackend: newMockBackend("legacy-1", "Legacy", "dataminr"), release: make(chan struct{})}
+	}
+
+	t.Run("delegates the lifecycle and operations", func(t *testing.T) {
+		legacy := newLegacy()
+		b := FromLegacy(legacy)
+		assert.Equal(t, "legacy-1", b.GetID())
+
+		require.NoError(t, b.Start(context.Background()))
+		assert.True(t, legacy.started)
+
+		close(legacy.release)
+		require.NoError(t, b.Stop(context.Background()))
+	})
+
+	t.Run("must be restarted to update", func(t *testing.T) {
+		err := FromLegacy(newLegacy()).UpdateConfig(context.Background(), Config{ID: "legacy-1", Name: "Renamed"})
+		require.ErrorIs(t, err, ErrRestartRequired)
+	})
+
+	t.Run("does not start once the context is done", func(t *testing.T) {
+		legacy := newLegacy()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := FromLegacy(legacy).Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.False(t, legacy.started)
+	})
+
+	t.Run("stops waiting once the context is done", func(t *testing.T) {
+		legacy := newLegacy()
+		defer close(legacy.release)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := FromLegacy(legacy).Stop(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "backend did not stop in time")
+	})
+
+	t.Run("registers with the registry", func(t *testing.T) {
+		legacy := newLegacy()
+		close(legacy.release)
+		registry := NewRegistry()
+
+		require.NoError(t, registry.Register(FromLegacy(legacy)))
+		require.NoError(t, registry.Unregister(context.Background(), "legacy-1"))
+	})
+}
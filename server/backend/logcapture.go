@@ -0,0 +1,165 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log levels of backend log lines, from the most to the least verbose
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// logLevelRanks orders the supported log levels by severity
+var logLevelRanks = map[string]int{
+	LogLevelDebug: 0,
+	LogLevelInfo:  1,
+	LogLevelWarn:  2,
+	LogLevelError: 3,
+}
+
+// ValidateLogLevel checks that a backend log level is empty or supported
+func ValidateLogLevel(level string) error {
+	if _, ok := logLevelRanks[level]; level != "" && !ok {
+		return fmt.Errorf("unsupported log level '%s' (supported levels are 'debug', 'info', 'warn' and 'error')", level)
+	}
+	return nil
+}
+
+// Logger writes log lines with alternating key and value pairs. It is satisfied by the
+// plugin API's log service.
+type Logger interface {
+	Debug(message string, keyValuePairs ...any)
+	Info(message string, keyValuePairs ...any)
+	Warn(message string, keyValuePairs ...any)
+	Error(message string, keyValuePairs ...any)
+}
+
+// LogEntry is a captured backend log line
+type LogEntry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	Fields  string    `json:"fields,omitempty"`
+
+	// ChannelID is the channel the line is about, from its "channelId" field (empty if none)
+	ChannelID string `json:"channelId,omitempty"`
+}
+
+// CapturingLogger forwards the log lines of a backend at or above its log level and keeps
+// the most recent ones in memory, so a single backend can be debugged without raising the
+// verbosity of the whole server. The capture only holds lines logged on this server node.
+type CapturingLogger struct {
+	next         Logger
+	forwardLevel int
+	captureLevel int
+	now          func() time.Time
+
+	// mu guards the ring buffer of captured lines, where start is the oldest line
+	mu      sync.Mutex
+	entries []LogEntry
+	start   int
+	count   int
+}
+
+// NewCapturingLogger creates a logger forwarding to next and capturing up to capacity lines.
+// An empty level forwards every line, leaving filtering to the server log, and captures info and above.
+func NewCapturingLogger(next Logger, level string, capacity int) *CapturingLogger {
+	l := &CapturingLogger{
+		next:         next,
+		forwardLevel: logLevelRanks[LogLevelDebug],
+		captureLevel: logLevelRanks[LogLevelInfo],
+		now:          time.Now,
+		entries:      make([]LogEntry, capacity),
+	}
+	if rank, ok := logLevelRanks[level]; ok {
+		l.forwardLevel = rank
+		l.captureLevel = rank
+	}
+	return l
+}
+
+// Debug logs a debug line
+func (l *CapturingLogger) Debug(message string, keyValuePairs ...any) {
+	l.log(LogLevelDebug, l.next.Debug, message, keyValuePairs)
+}
+
+// Info logs an info line
+func (l *CapturingLogger) Info(message string, keyValuePairs ...any) {
+	l.log(LogLevelInfo, l.next.Info, message, keyValuePairs)
+}
+
+// Warn logs a warning line
+func (l *CapturingLogger) Warn(message string, keyValuePairs ...any) {
+	l.log(LogLevelWarn, l.next.Warn, message, keyValuePairs)
+}
+
+// Error logs an error line
+func (l *CapturingLogger) Error(message string, keyValuePairs ...any) {
+	l.log(LogLevelError, l.next.Error, message, keyValuePairs)
+}
+
+// Recent returns the captured log lines, oldest first
+func (l *CapturingLogger) Recent() []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := make([]LogEntry, 0, l.count)
+	for i := 0; i < l.count; i++ {
+		entries = append(entries, l.entries[(l.start+i)%len(l.entries)])
+	}
+	return entries
+}
+
+// log forwards and captures a line according to the configured levels
+func (l *CapturingLogger) log(level string, forward func(string, ...any), message string, keyValuePairs []any) {
+	rank := logLevelRanks[level]
+	if rank >= l.forwardLevel {
+		forward(message, keyValuePairs...)
+	}
+	if rank < l.captureLevel || len(l.entries) == 0 {
+		return
+	}
+
+	entry := LogEntry{Time: l.now(), Level: level, Message: message, Fields: formatLogFields(keyValuePairs), ChannelID: logChannelID(keyValuePairs)}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.count < len(l.entries) {
+		l.entries[(l.start+l.count)%len(l.entries)] = entry
+		l.count++
+		return
+	}
+	l.entries[l.start] = entry
+	l.start = (l.start + 1) % len(l.entries)
+}
+
+// logChannelID returns the value of the "channelId" field of a log line, if any
+func logChannelID(keyValuePairs []any) string {
+	for i := 0; i+1 < len(keyValuePairs); i += 2 {
+		if keyValuePairs[i] == "channelId" {
+			channelID, _ := keyValuePairs[i+1].(string)
+			return channelID
+		}
+	}
+	return ""
+}
+
+// formatLogFields formats alternating key and value pairs as "key=value" separated by spaces
+func formatLogFields(keyValuePairs []any) string {
+	fields := make([]string, 0, (len(keyValuePairs)+1)/2)
+	for i := 0; i < len(keyValuePairs); i += 2 {
+		if i+1 == len(keyValuePairs) {
+			fields = append(fields, fmt.Sprintf("%v", keyValuePairs[i]))
+			break
+		}
+		fields = append(fields, fmt.Sprintf("%v=%v", keyValuePairs[i], keyValuePairs[i+1]))
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,116 @@
+package backend
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLogger records the levels and messages of forwarded log lines
+type recordingLogger struct {
+	lines []string
+}
+
+func (r *recordingLogger) Debug(message string, _ ...any) {
+	r.lines = append(r.lines, "debug "+message)
+}
+
+func (r *recordingLogger) Info(message string, _ ...any) {
+	r.lines = append(r.lines, "info "+message)
+}
+
+func (r *recordingLogger) Warn(message string, _ ...any) {
+	r.lines = append(r.lines, "warn "+message)
+}
+
+func (r *recordingLogger) Error(message string, _ ...any) {
+	r.lines = append(r.lines, "error "+message)
+}
+
+// logAllLevels logs one line at every level
+func logAllLevels(logger *CapturingLogger) {
+	logger.Debug("debug line")
+	logger.Info("info line")
+	logger.Warn("warn line")
+	logger.Error("error line")
+}
+
+// capturedMessages returns the messages of the captured lines
+func capturedMessages(logger *CapturingLogger) []string {
+	var messages []string
+	for _, entry := range logger.Recent() {
+		messages = append(messages, entry.Message)
+	}
+	return messages
+}
+
+func TestCapturingLogger_Levels(t *testing.T) {
+	t.Run("default level", func(t *testing.T) {
+		next := &recordingLogger{}
+		logger := NewCapturingLogger(next, "", 10)
+		logAllLevels(logger)
+
+		assert.Equal(t, []string{"debug debug line", "info info line", "warn warn line", "error error line"}, next.lines)
+		assert.Equal(t, []string{"info line", "warn line", "error line"}, capturedMessages(logger))
+	})
+
+	t.Run("debug level", func(t *testing.T) {
+		next := &recordingLogger{}
+		logger := NewCapturingLogger(next, LogLevelDebug, 10)
+		logAllLevels(logger)
+
+		assert.Len(t, next.lines, 4)
+		assert.Equal(t, []string{"debug line", "info line", "warn line", "error line"}, capturedMessages(logger))
+	})
+
+	t.Run("warn level", func(t *testing.T) {
+		next := &recordingLogger{}
+		logger := NewCapturingLogger(next, LogLevelWarn, 10)
+		logAllLevels(logger)
+
+		assert.Equal(t, []string{"warn warn line", "error error line"}, next.lines)
+		assert.Equal(t, []string{"warn line", "error line"}, capturedMessages(logger))
+	})
+}
+
+func TestCapturingLogger_RingBuffer(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	logger := NewCapturingLogger(&recordingLogger{}, LogLevelInfo, 3)
+	logger.now = func() time.Time { return now }
+
+	assert.Empty(t, logger.Recent())
+
+	for i := 1; i <= 5; i++ {
+		logger.Info(fmt.Sprintf("line %d", i), "alertId", i, "dangling")
+	}
+
+	entries := logger.Recent()
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{"line 3", "line 4", "line 5"}, capturedMessages(logger))
+	assert.Equal(t, LogEntry{Time: now, Level: LogLevelInfo, Message: "line 5", Fields: "alertId=5 dangling"}, entries[2])
+}
+
+func TestCapturingLogger_ChannelID(t *testing.T) {
+	logger := NewCapturingLogger(&recordingLogger{}, LogLevelInfo, 3)
+
+	logger.Info("Dry run: would have posted alert", "alertId", "alert-1", "channelId", "channel-1")
+	logger.Info("Poller started", "backendId", "backend-1")
+
+	entries := logger.Recent()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "channel-1", entries[0].ChannelID)
+	assert.Empty(t, entries[1].ChannelID)
+}
+
+func TestValidateLogLevel(t *testing.T) {
+	for _, level := range []string{"", LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError} {
+		assert.NoError(t, ValidateLogLevel(level))
+	}
+
+	err := ValidateLogLevel("verbose")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported log level 'verbose'")
+}
@@ -0,0 +1,67 @@
+package backend
+
+import "fmt"
+
+const (
+	// MediaBundleModeAttachments shows each media item as a separate image attachment
+	MediaBundleModeAttachments = "attachments"
+
+	// MediaBundleModeFiles downloads the media items and uploads them as files of the post
+	MediaBundleModeFiles = "files"
+)
+
+// MediaBundleSettings show several media items of an alert as images instead of embedding the
+// first one and linking the rest. Media items beyond MaxItems are still linked. In files mode
+// media that can't be downloaded, is too large or isn't an image is shown as an image
+// attachment linking the original URL.
+type MediaBundleSettings struct {
+	// Mode is how the media items are shown: MediaBundleModeAttachments (default) or
+	// MediaBundleModeFiles
+	Mode string `json:"mode,omitempty"`
+
+	// MaxItems is the number of media items shown as images (default: DefaultMediaBundleItems)
+	MaxItems int `json:"maxItems,omitempty"`
+
+	// MaxTotalSizeMB caps the total size of the files uploaded for an alert in files mode
+	// (default: DefaultMediaBundleSizeMB)
+	MaxTotalSizeMB int `json:"maxTotalSizeMb,omitempty"`
+}
+
+// Validate checks that the mode is supported and the limits are within range.
+func (m *MediaBundleSettings) Validate() error {
+	if m.Mode != "" && m.Mode != MediaBundleModeAttachments && m.Mode != MediaBundleModeFiles {
+		return fmt.Errorf("invalid media bundle mode '%s' (must be %s or %s)", m.Mode, MediaBundleModeAttachments, MediaBundleModeFiles)
+	}
+	if m.MaxItems < 0 || m.MaxItems > MaxMediaBundleItems {
+		return fmt.Errorf("media bundle items must be between 0 and %d (got %d)", MaxMediaBundleItems, m.MaxItems)
+	}
+	if m.MaxTotalSizeMB < 0 || m.MaxTotalSizeMB > MaxMediaBundleSizeMB {
+		return fmt.Errorf("media bundle size limit must be between 0 and %d MB (got %d)", MaxMediaBundleSizeMB, m.MaxTotalSizeMB)
+	}
+	return nil
+}
+
+// ModeOrDefault returns how the media items are shown, applying the default when unset
+func (m MediaBundleSettings) ModeOrDefault() string {
+	if m.Mode == "" {
+		return MediaBundleModeAttachments
+	}
+	return m.Mode
+}
+
+// Items returns the number of media items shown as images, applying the default when unset
+func (m MediaBundleSettings) Items() int {
+	if m.MaxItems <= 0 {
+		return DefaultMediaBundleItems
+	}
+	return m.MaxItems
+}
+
+// MaxTotalSizeBytes returns the total size limit in bytes, applying the default when unset
+func (m MediaBundleSettings) MaxTotalSizeBytes() int64 {
+	sizeMB := m.MaxTotalSizeMB
+	if sizeMB <= 0 {
+		sizeMB = DefaultMediaBundleSizeMB
+	}
+	return int64(sizeMB) * 1024 * 1024
+}
@@ -0,0 +1,36 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaBundleSettings_Validate(t *testing.T) {
+	require.NoError(t, (&MediaBundleSettings{}).Validate())
+	require.NoError(t, (&MediaBundleSettings{Mode: MediaBundleModeFiles, MaxItems: MaxMediaBundleItems, MaxTotalSizeMB: MaxMediaBundleSizeMB}).Validate())
+
+	err := (&MediaBundleSettings{Mode: "gallery"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid media bundle mode 'gallery'")
+
+	err = (&MediaBundleSettings{MaxItems: MaxMediaBundleItems + 1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "media bundle items must be between 0 and 10 (got 11)")
+
+	err = (&MediaBundleSettings{MaxTotalSizeMB: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "media bundle size limit must be between 0 and 50 MB (got -1)")
+}
+
+func TestMediaBundleSettings_Defaults(t *testing.T) {
+	assert.Equal(t, MediaBundleModeAttachments, MediaBundleSettings{}.ModeOrDefault())
+	assert.Equal(t, MediaBundleModeFiles, MediaBundleSettings{Mode: MediaBundleModeFiles}.ModeOrDefault())
+
+	assert.Equal(t, DefaultMediaBundleItems, MediaBundleSettings{}.Items())
+	assert.Equal(t, 6, MediaBundleSettings{MaxItems: 6}.Items())
+
+	assert.Equal(t, int64(20*1024*1024), MediaBundleSettings{}.MaxTotalSizeBytes())
+	assert.Equal(t, int64(5*1024*1024), MediaBundleSettings{MaxTotalSizeMB: 5}.MaxTotalSizeBytes())
+}
@@ -0,0 +1,29 @@
+package backend
+
+import "fmt"
+
+// MediaUploadSettings enables uploading the first media item of each alert to Mattermost.
+// The media is downloaded by the server and attached to the post as a file, so clients that
+// can't reach the original URL (or open it after it expires) can still view it. Media that
+// can't be downloaded, is too large or isn't an image is linked by URL as before.
+type MediaUploadSettings struct {
+	// MaxSizeMB is the largest media file uploaded (default: DefaultMediaUploadSizeMB)
+	MaxSizeMB int `json:"maxSizeMb,omitempty"`
+}
+
+// Validate checks that the size limit is within range.
+func (m *MediaUploadSettings) Validate() error {
+	if m.MaxSizeMB < 0 || m.MaxSizeMB > MaxMediaUploadSizeMB {
+		return fmt.Errorf("media upload size limit must be between 0 and %d MB (got %d)", MaxMediaUploadSizeMB, m.MaxSizeMB)
+	}
+	return nil
+}
+
+// MaxSizeBytes returns the size limit in bytes, applying the default when unset
+func (m MediaUploadSettings) MaxSizeBytes() int64 {
+	sizeMB := m.MaxSizeMB
+	if sizeMB <= 0 {
+		sizeMB = DefaultMediaUploadSizeMB
+	}
+	return int64(sizeMB) * 1024 * 1024
+}
@@ -0,0 +1,25 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaUploadSettings_Validate(t *testing.T) {
+	require.NoError(t, (&MediaUploadSettings{}).Validate())
+	require.NoError(t, (&MediaUploadSettings{MaxSizeMB: MaxMediaUploadSizeMB}).Validate())
+
+	err := (&MediaUploadSettings{MaxSizeMB: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "media upload size limit must be between 0 and 50 MB (got -1)")
+
+	err = (&MediaUploadSettings{MaxSizeMB: MaxMediaUploadSizeMB + 1}).Validate()
+	require.Error(t, err)
+}
+
+func TestMediaUploadSettings_MaxSizeBytes(t *testing.T) {
+	assert.Equal(t, int64(10*1024*1024), MediaUploadSettings{}.MaxSizeBytes())
+	assert.Equal(t, int64(2*1024*1024), MediaUploadSettings{MaxSizeMB: 2}.MaxSizeBytes())
+}
@@ -0,0 +1,262 @@
+package backend
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// UnavailableMentionDefer mentions unavailable users in a reply to the alert post once the
+	// deferral delay has passed
+	UnavailableMentionDefer = "defer"
+
+	// UnavailableMentionOnCall mentions the on-call user instead of unavailable users
+	UnavailableMentionOnCall = "onCall"
+)
+
+// specialMentions are the channel-wide mentions that do not refer to a user or group
+var specialMentions = map[string]bool{
+	"channel": true,
+	"here":    true,
+	"all":     true,
+}
+
+// MentionRules lists the mentions prepended to alert posts for each alert type.
+// Targets are usernames, user group names or one of @channel, @here and @all,
+// with or without the leading '@'.
+type MentionRules struct {
+	// Flash lists the mentions added to Flash alerts
+	Flash []string `json:"flash,omitempty"`
+
+	// Urgent lists the mentions added to Urgent alerts
+	Urgent []string `json:"urgent,omitempty"`
+
+	// Alert lists the mentions added to Alert alerts
+	Alert []string `json:"alert,omitempty"`
+
+	// OnCall optionally adds the current on-call user from an external on-call service
+	OnCall *OnCallSettings `json:"onCall,omitempty"`
+
+	// Unavailable optionally holds back the mentions of users in Do Not Disturb or out of office
+	Unavailable *UnavailableMentionSettings `json:"unavailable,omitempty"`
+}
+
+// Validate checks that every mention target is a well formed name.
+// Whether user and group targets exist is checked by the plugin since it requires the server API.
+func (m *MentionRules) Validate() error {
+	for _, targets := range [][]string{m.Flash, m.Urgent, m.Alert} {
+		for _, target := range targets {
+			name := normalizeMention(target)
+			if name == "" {
+				return fmt.Errorf("mention target must not be empty")
+			}
+
+			if !ValidMentionName(name) {
+				return fmt.Errorf("invalid mention target '%s' (only letters, digits, '.', '_' and '-' are allowed)", target)
+			}
+		}
+	}
+
+	if m.OnCall != nil {
+		if err := m.OnCall.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if m.Unavailable != nil {
+		if err := m.Unavailable.Validate(); err != nil {
+			return err
+		}
+		if m.Unavailable.Action == UnavailableMentionOnCall && m.OnCall == nil {
+			return fmt.Errorf("mentioning the on-call user instead of unavailable users requires an on-call service")
+		}
+	}
+
+	return nil
+}
+
+// Mentions returns the mention text for an alert type (e.g., "@channel @security-team"),
+// or empty if the alert type has no mentions configured.
+func (m *MentionRules) Mentions(alertType string) string {
+	if m == nil {
+		return ""
+	}
+
+	var targets []string
+	switch strings.ToLower(alertType) {
+	case "flash":
+		targets = m.Flash
+	case "urgent":
+		targets = m.Urgent
+	case "alert":
+		targets = m.Alert
+	}
+
+	mentions := make([]string, 0, len(targets))
+	for _, target := range targets {
+		mentions = append(mentions, "@"+normalizeMention(target))
+	}
+	return strings.Join(mentions, " ")
+}
+
+// NamedTargets returns the user and group names mentioned by any rule or the on-call fallback
+// (without the '@'), excluding @channel, @here and @all. Each name is returned once.
+func (m *MentionRules) NamedTargets() []string {
+	lists := [][]string{m.Flash, m.Urgent, m.Alert}
+	if m.OnCall != nil && m.OnCall.Fallback != "" {
+		lists = append(lists, []string{m.OnCall.Fallback})
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, targets := range lists {
+		for _, target := range targets {
+			name := normalizeMention(target)
+			if name == "" || specialMentions[name] || seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// normalizeMention strips whitespace and the leading '@' and lowercases a mention target
+func normalizeMention(target string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(target), "@"))
+}
+
+// OnCallSettings resolves the on-call user mentioned on critical alerts from an external
+// on-call service (e.g., a PagerDuty or Opsgenie webhook, or any HTTP endpoint returning a username)
+type OnCallSettings struct {
+	// URL is the HTTP(S) endpoint queried with GET for the current on-call username
+	URL string `json:"url"`
+
+	// Headers are added to the request (e.g., an Authorization header)
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// UsernameField is the dot-separated path of the username in a JSON response
+	// (e.g., "data.0.user.name"). Empty accepts a plain text username, a JSON string or
+	// a JSON object with a "username" field.
+	UsernameField string `json:"usernameField,omitempty"`
+
+	// AlertTypes lists the alert types the on-call user is mentioned on (default: Flash)
+	AlertTypes []string `json:"alertTypes,omitempty"`
+
+	// CacheMinutes is how long a resolved username is reused (default: DefaultOnCallCacheMinutes)
+	CacheMinutes int `json:"cacheMinutes,omitempty"`
+
+	// Fallback is the static mention used when the on-call service cannot be reached
+	// or returns no username (optional)
+	Fallback string `json:"fallback,omitempty"`
+}
+
+// Validate checks the endpoint URL, alert types, cache duration and fallback mention.
+func (o *OnCallSettings) Validate() error {
+	parsed, err := url.Parse(o.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("on-call URL must be an http or https URL (got '%s')", o.URL)
+	}
+
+	for _, alertType := range o.AlertTypes {
+		switch strings.ToLower(alertType) {
+		case "flash", "urgent", "alert":
+		default:
+			return fmt.Errorf("invalid on-call alert type '%s' (must be Flash, Urgent or Alert)", alertType)
+		}
+	}
+
+	if o.CacheMinutes < 0 || o.CacheMinutes > MaxOnCallCacheMinutes {
+		return fmt.Errorf("on-call cache duration must be between 0 and %d minutes (got %d)", MaxOnCallCacheMinutes, o.CacheMinutes)
+	}
+
+	if o.Fallback != "" && !ValidMentionName(normalizeMention(o.Fallback)) {
+		return fmt.Errorf("invalid on-call fallback mention '%s' (only letters, digits, '.', '_' and '-' are allowed)", o.Fallback)
+	}
+
+	return nil
+}
+
+// AppliesTo reports whether the on-call user is mentioned on an alert type
+func (o OnCallSettings) AppliesTo(alertType string) bool {
+	if len(o.AlertTypes) == 0 {
+		return strings.EqualFold(alertType, "flash")
+	}
+	for _, t := range o.AlertTypes {
+		if strings.EqualFold(t, alertType) {
+			return true
+		}
+	}
+	return false
+}
+
+// CacheDuration returns how long a resolved username is reused, applying the default when unset
+func (o OnCallSettings) CacheDuration() time.Duration {
+	if o.CacheMinutes <= 0 {
+		return DefaultOnCallCacheMinutes * time.Minute
+	}
+	return time.Duration(o.CacheMinutes) * time.Minute
+}
+
+// FallbackMention returns the fallback mention with a leading '@' (empty if unset)
+func (o OnCallSettings) FallbackMention() string {
+	if name := normalizeMention(o.Fallback); name != "" {
+		return "@" + name
+	}
+	return ""
+}
+
+// UnavailableMentionSettings decide what happens to the mentions of users who are in
+// Do Not Disturb or out of office when an alert is posted, instead of pinging them right away
+type UnavailableMentionSettings struct {
+	// Action is UnavailableMentionDefer (default), or UnavailableMentionOnCall to mention the
+	// on-call user instead. Mentions are deferred if the on-call user is unavailable too.
+	Action string `json:"action,omitempty"`
+
+	// DeferMinutes is how long deferred mentions are held back (default: DefaultMentionDeferMinutes)
+	DeferMinutes int `json:"deferMinutes,omitempty"`
+}
+
+// Validate checks that the action is supported and the deferral is within range.
+func (u *UnavailableMentionSettings) Validate() error {
+	if u.Action != "" && u.Action != UnavailableMentionDefer && u.Action != UnavailableMentionOnCall {
+		return fmt.Errorf("invalid unavailable mention action '%s' (must be %s or %s)", u.Action, UnavailableMentionDefer, UnavailableMentionOnCall)
+	}
+	if u.DeferMinutes < 0 || u.DeferMinutes > MaxMentionDeferMinutes {
+		return fmt.Errorf("mention deferral must be between 0 and %d minutes (got %d)", MaxMentionDeferMinutes, u.DeferMinutes)
+	}
+	return nil
+}
+
+// ActionOrDefault returns the action for mentions of unavailable users, applying the default when unset
+func (u UnavailableMentionSettings) ActionOrDefault() string {
+	if u.Action == "" {
+		return UnavailableMentionDefer
+	}
+	return u.Action
+}
+
+// DeferDelay returns how long deferred mentions are held back, applying the default when unset
+func (u UnavailableMentionSettings) DeferDelay() time.Duration {
+	if u.DeferMinutes <= 0 {
+		return DefaultMentionDeferMinutes * time.Minute
+	}
+	return time.Duration(u.DeferMinutes) * time.Minute
+}
+
+// ValidMentionName reports whether a normalized name (lowercase, without '@') only contains
+// the characters allowed in usernames and group names
+func ValidMentionName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '.' && r != '_' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionRules_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       MentionRules
+		expectedErr string
+	}{
+		{"empty", MentionRules{}, ""},
+		{"special and named targets", MentionRules{Flash: []string{"@channel", "security-team"}, Urgent: []string{"@john.doe"}}, ""},
+		{"empty target", MentionRules{Urgent: []string{"@"}}, "mention target must not be empty"},
+		{"invalid target", MentionRules{Alert: []string{"@john doe"}}, "invalid mention target '@john doe'"},
+		{"on-call service", MentionRules{OnCall: &OnCallSettings{URL: "https://oncall.example.com/now", AlertTypes: []string{"Flash", "urgent"}, Fallback: "@sre"}}, ""},
+		{"on-call URL", MentionRules{OnCall: &OnCallSettings{URL: "ftp://oncall.example.com"}}, "on-call URL must be an http or https URL (got 'ftp://oncall.example.com')"},
+		{"on-call alert type", MentionRules{OnCall: &OnCallSettings{URL: "https://oncall.example.com", AlertTypes: []string{"Critical"}}}, "invalid on-call alert type 'Critical'"},
+		{"on-call cache", MentionRules{OnCall: &OnCallSettings{URL: "https://oncall.example.com", CacheMinutes: -1}}, "on-call cache duration must be between 0 and 1440 minutes (got -1)"},
+		{"on-call fallback", MentionRules{OnCall: &OnCallSettings{URL: "https://oncall.example.com", Fallback: "@sre team"}}, "invalid on-call fallback mention '@sre team'"},
+		{"defer unavailable", MentionRules{Unavailable: &UnavailableMentionSettings{DeferMinutes: 30}}, ""},
+		{"route unavailable to on-call", MentionRules{OnCall: &OnCallSettings{URL: "https://oncall.example.com"}, Unavailable: &UnavailableMentionSettings{Action: UnavailableMentionOnCall}}, ""},
+		{"unavailable action", MentionRules{Unavailable: &UnavailableMentionSettings{Action: "drop"}}, "invalid unavailable mention action 'drop'"},
+		{"unavailable deferral", MentionRules{Unavailable: &UnavailableMentionSettings{DeferMinutes: 1441}}, "mention deferral must be between 0 and 1440 minutes (got 1441)"},
+		{"unavailable on-call without service", MentionRules{Unavailable: &UnavailableMentionSettings{Action: UnavailableMentionOnCall}}, "requires an on-call service"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rules.Validate()
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestMentionRules_Mentions(t *testing.T) {
+	rules := &MentionRules{
+		Flash:  []string{"@channel", "Security-Team"},
+		Urgent: []string{"john.doe"},
+	}
+
+	assert.Equal(t, "@channel @security-team", rules.Mentions("Flash"))
+	assert.Equal(t, "@john.doe", rules.Mentions("urgent"))
+	assert.Equal(t, "", rules.Mentions("Alert"))
+	assert.Equal(t, "", rules.Mentions("Unknown"))
+
+	var noRules *MentionRules
+	assert.Equal(t, "", noRules.Mentions("Flash"))
+}
+
+func TestMentionRules_NamedTargets(t *testing.T) {
+	rules := &MentionRules{
+		Flash:  []string{"@channel", "@security-team", "@here"},
+		Urgent: []string{"@john.doe", "@Security-Team"},
+		Alert:  []string{"@all"},
+	}
+
+	assert.Equal(t, []string{"security-team", "john.doe"}, rules.NamedTargets())
+	assert.Empty(t, (&MentionRules{Flash: []string{"@channel"}}).NamedTargets())
+
+	// The on-call fallback must exist as well
+	rules = &MentionRules{Flash: []string{"@sre"}, OnCall: &OnCallSettings{Fallback: "@duty-officer"}}
+	assert.Equal(t, []string{"sre", "duty-officer"}, rules.NamedTargets())
+}
+
+func TestOnCallSettings_Defaults(t *testing.T) {
+	settings := OnCallSettings{}
+	assert.True(t, settings.AppliesTo("Flash"))
+	assert.False(t, settings.AppliesTo("Urgent"))
+	assert.Equal(t, 5*time.Minute, settings.CacheDuration())
+	assert.Empty(t, settings.FallbackMention())
+
+	settings = OnCallSettings{AlertTypes: []string{"urgent"}, CacheMinutes: 30, Fallback: "SRE"}
+	assert.False(t, settings.AppliesTo("Flash"))
+	assert.True(t, settings.AppliesTo("Urgent"))
+	assert.Equal(t, 30*time.Minute, settings.CacheDuration())
+	assert.Equal(t, "@sre", settings.FallbackMention())
+}
+
+func TestUnavailableMentionSettings_Defaults(t *testing.T) {
+	settings := UnavailableMentionSettings{}
+	assert.Equal(t, UnavailableMentionDefer, settings.ActionOrDefault())
+	assert.Equal(t, time.Hour, settings.DeferDelay())
+
+	settings = UnavailableMentionSettings{Action: UnavailableMentionOnCall, DeferMinutes: 15}
+	assert.Equal(t, UnavailableMentionOnCall, settings.ActionOrDefault())
+	assert.Equal(t, 15*time.Minute, settings.DeferDelay())
+}
@@ -0,0 +1,108 @@
+package backend
+
+import "time"
+
+// PollSample is the outcome of a single poll cycle
+type PollSample struct {
+	// Time is when the poll cycle started
+	Time time.Time `json:"time"`
+
+	// Success indicates whether the poll cycle completed without an error
+	Success bool `json:"success"`
+
+	// Alerts is the number of alerts returned by the API
+	Alerts int `json:"alerts"`
+
+	// NewAlerts is the number of alerts posted (alerts not seen before)
+	NewAlerts int `json:"newAlerts"`
+
+	// LatencyMs is how long the poll cycle took in milliseconds
+	LatencyMs int64 `json:"latencyMs"`
+
+	// Error is the error message of a failed poll cycle
+	Error string `json:"error,omitempty"`
+}
+
+// PollHistoryStore records the poll cycle outcomes of a backend. Backends keep them with their
+// state in the KV store by default; the "sql" storage driver keeps them in the database.
+type PollHistoryStore interface {
+	// RecordPollSample appends a poll cycle outcome, dropping samples beyond the retention
+	// period and sample limit
+	RecordPollSample(sample PollSample, now time.Time) error
+
+	// GetPollHistory returns the recorded poll cycle outcomes, oldest first
+	GetPollHistory() ([]PollSample, error)
+}
+
+// PollHistorySummary aggregates the poll samples recorded since a point in time
+type PollHistorySummary struct {
+	// Since is the start of the summarized period
+	Since time.Time `json:"since"`
+
+	// Polls is the number of poll cycles in the period
+	Polls int `json:"polls"`
+
+	// Failures is the number of failed poll cycles in the period
+	Failures int `json:"failures"`
+
+	// SuccessRate is the fraction of successful poll cycles (0 to 1, 0 if there were no polls)
+	SuccessRate float64 `json:"successRate"`
+
+	// Alerts is the number of alerts returned by the API in the period
+	Alerts int `json:"alerts"`
+
+	// NewAlerts is the number of alerts posted in the period
+	NewAlerts int `json:"newAlerts"`
+
+	// AverageLatencyMs is the mean poll cycle duration in milliseconds
+	AverageLatencyMs int64 `json:"averageLatencyMs"`
+
+	// MaxLatencyMs is the longest poll cycle duration in milliseconds
+	MaxLatencyMs int64 `json:"maxLatencyMs"`
+}
+
+// SummarizePollHistory aggregates the samples recorded at or after since
+func SummarizePollHistory(samples []PollSample, since time.Time) PollHistorySummary {
+	summary := PollHistorySummary{Since: since}
+
+	var totalLatency int64
+	for _, sample := range samples {
+		if sample.Time.Before(since) {
+			continue
+		}
+
+		summary.Polls++
+		if !sample.Success {
+			summary.Failures++
+		}
+		summary.Alerts += sample.Alerts
+		summary.NewAlerts += sample.NewAlerts
+		totalLatency += sample.LatencyMs
+		if sample.LatencyMs > summary.MaxLatencyMs {
+			summary.MaxLatencyMs = sample.LatencyMs
+		}
+	}
+
+	if summary.Polls > 0 {
+		summary.SuccessRate = float64(summary.Polls-summary.Failures) / float64(summary.Polls)
+		summary.AverageLatencyMs = totalLatency / int64(summary.Polls)
+	}
+
+	return summary
+}
+
+// TrimPollHistory drops samples older than PollHistoryRetention and keeps at most
+// MaxPollHistorySamples of the newest. Samples are ordered oldest first.
+func TrimPollHistory(samples []PollSample, now time.Time) []PollSample {
+	cutoff := now.Add(-PollHistoryRetention)
+
+	start := 0
+	for start < len(samples) && samples[start].Time.Before(cutoff) {
+		start++
+	}
+	if len(samples)-start > MaxPollHistorySamples {
+		start = len(samples) - MaxPollHistorySamples
+	}
+
+	return samples[start:]
+}
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizePollHistory(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-24 * time.Hour)
+
+	summary := SummarizePollHistory(nil, since)
+	assert.Equal(t, PollHistorySummary{Since: since}, summary)
+
+	samples := []PollSample{
+		{Time: now.Add(-30 * time.Hour), Success: false, LatencyMs: 5000},
+		{Time: now.Add(-3 * time.Hour), Success: true, Alerts: 10, NewAlerts: 4, LatencyMs: 200},
+		{Time: now.Add(-2 * time.Hour), Success: true, Alerts: 2, NewAlerts: 1, LatencyMs: 100},
+		{Time: now.Add(-1 * time.Hour), Success: true, LatencyMs: 120},
+		{Time: now.Add(-1 * time.Minute), Success: false, LatencyMs: 780, Error: "timeout"},
+	}
+
+	summary = SummarizePollHistory(samples, since)
+	assert.Equal(t, 4, summary.Polls)
+	assert.Equal(t, 1, summary.Failures)
+	assert.Equal(t, 0.75, summary.SuccessRate)
+	assert.Equal(t, 12, summary.Alerts)
+	assert.Equal(t, 5, summary.NewAlerts)
+	assert.Equal(t, int64(300), summary.AverageLatencyMs)
+	assert.Equal(t, int64(780), summary.MaxLatencyMs)
+}
+
+func TestTrimPollHistory(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	t.Run("drops samples older than the retention period", func(t *testing.T) {
+		samples := []PollSample{
+			{Time: now.Add(-PollHistoryRetention - time.Minute)},
+			{Time: now.Add(-PollHistoryRetention + time.Minute)},
+			{Time: now},
+		}
+
+		trimmed := TrimPollHistory(samples, now)
+		require.Len(t, trimmed, 2)
+		assert.Equal(t, samples[1:], trimmed)
+	})
+
+	t.Run("keeps the newest samples up to the limit", func(t *testing.T) {
+		samples := make([]PollSample, MaxPollHistorySamples+10)
+		for i := range samples {
+			samples[i] = PollSample{Time: now.Add(time.Duration(i-len(samples)) * time.Second)}
+		}
+
+		trimmed := TrimPollHistory(samples, now)
+		require.Len(t, trimmed, MaxPollHistorySamples)
+		assert.Equal(t, samples[len(samples)-1], trimmed[len(trimmed)-1])
+		assert.Equal(t, samples[10], trimmed[0])
+	})
+}
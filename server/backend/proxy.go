@@ -0,0 +1,67 @@
+package backend
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// validateProxy checks the proxy URL and that proxy credentials are set only with a proxy URL,
+// and in either the URL or the username and password settings
+func (c Config) validateProxy() error {
+	if c.ProxyURL == "" {
+		if c.ProxyUsername != "" || c.ProxyPassword != "" {
+			return fmt.Errorf("proxy username and password require a proxy URL")
+		}
+		return nil
+	}
+
+	if err := validateProxyURL(c.ProxyURL); err != nil {
+		return err
+	}
+
+	if (c.ProxyPassword != "" || c.ProxyPasswordStored) && c.ProxyUsername == "" {
+		return fmt.Errorf("proxy password requires a proxy username")
+	}
+
+	if c.ProxyUsername != "" {
+		parsed, _ := url.Parse(c.ProxyURL)
+		if parsed.User != nil {
+			return fmt.Errorf("proxy credentials must be set either in the proxy URL or in the proxy username and password, not both")
+		}
+	}
+
+	return nil
+}
+
+// ProxyEndpoint returns the proxy URL including the proxy username and password,
+// or nil when no proxy is configured. The password must already be resolved.
+func (c Config) ProxyEndpoint() (*url.URL, error) {
+	if c.ProxyURL == "" {
+		return nil, nil
+	}
+
+	parsed, err := url.Parse(c.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	if c.ProxyUsername != "" {
+		if c.ProxyPassword != "" {
+			parsed.User = url.UserPassword(c.ProxyUsername, c.ProxyPassword)
+		} else {
+			parsed.User = url.User(c.ProxyUsername)
+		}
+	}
+
+	return parsed, nil
+}
+
+// RedactedProxyURL returns the proxy URL with its password masked, for logs and diagnostics.
+// Returns an empty string when no proxy is configured or the URL doesn't parse.
+func (c Config) RedactedProxyURL() string {
+	endpoint, err := c.ProxyEndpoint()
+	if err != nil || endpoint == nil {
+		return ""
+	}
+	return endpoint.Redacted()
+}
@@ -0,0 +1,175 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours defines recurring time windows during which non-Flash alerts are
+// held back and posted once the window ends.
+type QuietHours struct {
+	// Timezone is the IANA timezone name used to evaluate the ranges (default: UTC)
+	Timezone string `json:"timezone,omitempty"`
+
+	// Days limits quiet hours to specific days of the week ("mon" through "sun").
+	// An empty list means every day. For ranges that cross midnight, the day is
+	// the one on which the range starts.
+	Days []string `json:"days,omitempty"`
+
+	// Ranges is the list of quiet time ranges within a day
+	Ranges []TimeRange `json:"ranges"`
+}
+
+// TimeRange is a time-of-day range in 24-hour "HH:MM" format.
+// A range whose end is before its start wraps past midnight (e.g. 22:00-06:00).
+type TimeRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// weekdays maps the accepted day abbreviations to time.Weekday values
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Validate checks that the timezone, days and ranges are well formed.
+func (q *QuietHours) Validate() error {
+	return validateSchedule("quiet hours", q.Timezone, q.Days, q.Ranges)
+}
+
+// IsActive reports whether the given instant falls inside one of the quiet hours ranges.
+// Invalid configurations are never active.
+func (q *QuietHours) IsActive(now time.Time) bool {
+	if q == nil {
+		return false
+	}
+	return scheduleActive(q.Timezone, q.Days, q.Ranges, now)
+}
+
+// validateSchedule checks that the timezone, days and ranges of a weekly schedule are well
+// formed. The name of the setting starts the error messages.
+func validateSchedule(name, timezone string, days []string, ranges []TimeRange) error {
+	if _, err := scheduleLocation(name, timezone); err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid %s day '%s' (expected mon, tue, wed, thu, fri, sat or sun)", name, day)
+		}
+	}
+
+	if len(ranges) == 0 {
+		return fmt.Errorf("%s must define at least one time range", name)
+	}
+
+	for _, r := range ranges {
+		start, err := parseTimeOfDay(r.Start)
+		if err != nil {
+			return fmt.Errorf("invalid %s start time: %w", name, err)
+		}
+		end, err := parseTimeOfDay(r.End)
+		if err != nil {
+			return fmt.Errorf("invalid %s end time: %w", name, err)
+		}
+		if start == end {
+			return fmt.Errorf("%s range %s-%s is empty", name, r.Start, r.End)
+		}
+	}
+
+	return nil
+}
+
+// scheduleActive reports whether the given instant falls inside one of the ranges of a weekly
+// schedule, evaluated on the wall clock of its timezone so ranges follow daylight saving time.
+// Invalid schedules are never active.
+func scheduleActive(timezone string, days []string, ranges []TimeRange, now time.Time) bool {
+	if len(ranges) == 0 {
+		return false
+	}
+
+	loc, err := scheduleLocation("", timezone)
+	if err != nil {
+		return false
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	for _, r := range ranges {
+		start, err := parseTimeOfDay(r.Start)
+		if err != nil {
+			continue
+		}
+		end, err := parseTimeOfDay(r.End)
+		if err != nil {
+			continue
+		}
+
+		if start < end {
+			// Same-day range
+			if minute >= start && minute < end && appliesOn(days, local.Weekday()) {
+				return true
+			}
+			continue
+		}
+
+		// Range wraps past midnight: the evening part belongs to today,
+		// the morning part belongs to the range that started yesterday
+		if minute >= start && appliesOn(days, local.Weekday()) {
+			return true
+		}
+		if minute < end && appliesOn(days, local.AddDate(0, 0, -1).Weekday()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// appliesOn reports whether a schedule with the given days applies on a weekday.
+// No days means every day.
+func appliesOn(days []string, day time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+
+	for _, d := range days {
+		if wd, ok := weekdays[strings.ToLower(d)]; ok && wd == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scheduleLocation resolves the timezone of a schedule, defaulting to UTC
+func scheduleLocation(name, timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s timezone '%s': %w", name, timezone, err)
+	}
+
+	return loc, nil
+}
+
+// parseTimeOfDay parses an "HH:MM" string into minutes since midnight
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not in HH:MM format", value)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,139 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuietHours_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		quietHours  QuietHours
+		errContains string
+	}{
+		{
+			name:       "valid same-day range",
+			quietHours: QuietHours{Ranges: []TimeRange{{Start: "09:00", End: "17:00"}}},
+		},
+		{
+			name: "valid overnight range with timezone and days",
+			quietHours: QuietHours{
+				Timezone: "America/New_York",
+				Days:     []string{"mon", "Fri"},
+				Ranges:   []TimeRange{{Start: "22:00", End: "06:00"}},
+			},
+		},
+		{
+			name:        "no ranges",
+			quietHours:  QuietHours{},
+			errContains: "at least one time range",
+		},
+		{
+			name:        "invalid timezone",
+			quietHours:  QuietHours{Timezone: "Mars/Olympus", Ranges: []TimeRange{{Start: "09:00", End: "17:00"}}},
+			errContains: "invalid quiet hours timezone",
+		},
+		{
+			name:        "invalid day",
+			quietHours:  QuietHours{Days: []string{"funday"}, Ranges: []TimeRange{{Start: "09:00", End: "17:00"}}},
+			errContains: "invalid quiet hours day",
+		},
+		{
+			name:        "invalid start time",
+			quietHours:  QuietHours{Ranges: []TimeRange{{Start: "9am", End: "17:00"}}},
+			errContains: "invalid quiet hours start time",
+		},
+		{
+			name:        "invalid end time",
+			quietHours:  QuietHours{Ranges: []TimeRange{{Start: "09:00", End: "25:00"}}},
+			errContains: "invalid quiet hours end time",
+		},
+		{
+			name:        "empty range",
+			quietHours:  QuietHours{Ranges: []TimeRange{{Start: "09:00", End: "09:00"}}},
+			errContains: "is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.quietHours.Validate()
+			if tt.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestQuietHours_IsActive(t *testing.T) {
+	// 2025-01-13 is a Monday
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2025, 1, 13, hour, minute, 0, 0, time.UTC)
+	}
+
+	t.Run("nil quiet hours are never active", func(t *testing.T) {
+		var q *QuietHours
+		assert.False(t, q.IsActive(monday(12, 0)))
+	})
+
+	t.Run("same-day range", func(t *testing.T) {
+		q := &QuietHours{Ranges: []TimeRange{{Start: "09:00", End: "17:00"}}}
+
+		assert.False(t, q.IsActive(monday(8, 59)))
+		assert.True(t, q.IsActive(monday(9, 0)))
+		assert.True(t, q.IsActive(monday(16, 59)))
+		assert.False(t, q.IsActive(monday(17, 0)))
+	})
+
+	t.Run("overnight range", func(t *testing.T) {
+		q := &QuietHours{Ranges: []TimeRange{{Start: "22:00", End: "06:00"}}}
+
+		assert.True(t, q.IsActive(monday(23, 0)))
+		assert.True(t, q.IsActive(monday(5, 59)))
+		assert.False(t, q.IsActive(monday(6, 0)))
+		assert.False(t, q.IsActive(monday(21, 59)))
+	})
+
+	t.Run("overnight range uses the start day", func(t *testing.T) {
+		q := &QuietHours{
+			Days:   []string{"fri"},
+			Ranges: []TimeRange{{Start: "22:00", End: "06:00"}},
+		}
+
+		friday := time.Date(2025, 1, 17, 23, 0, 0, 0, time.UTC)
+		saturdayMorning := time.Date(2025, 1, 18, 3, 0, 0, 0, time.UTC)
+		fridayMorning := time.Date(2025, 1, 17, 3, 0, 0, 0, time.UTC)
+
+		assert.True(t, q.IsActive(friday))
+		assert.True(t, q.IsActive(saturdayMorning))
+		assert.False(t, q.IsActive(fridayMorning), "Friday morning belongs to Thursday's range")
+	})
+
+	t.Run("days filter", func(t *testing.T) {
+		q := &QuietHours{
+			Days:   []string{"sat", "sun"},
+			Ranges: []TimeRange{{Start: "00:00", End: "23:59"}},
+		}
+
+		assert.False(t, q.IsActive(monday(12, 0)))
+		assert.True(t, q.IsActive(time.Date(2025, 1, 18, 12, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("timezone is applied", func(t *testing.T) {
+		q := &QuietHours{
+			Timezone: "America/New_York",
+			Ranges:   []TimeRange{{Start: "09:00", End: "17:00"}},
+		}
+
+		// 14:00 UTC is 09:00 in New York (EST)
+		assert.True(t, q.IsActive(monday(14, 0)))
+		// 09:00 UTC is 04:00 in New York
+		assert.False(t, q.IsActive(monday(9, 0)))
+	})
+}
@@ -1,26 +1,48 @@
 package backend
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Registry manages all active backend instances.
-// It provides thread-safe operations for registering, retrieving, and managing backends.
+// It provides thread-safe operations for registering, retrieving, and managing backends,
+// and notifies observers of backend lifecycle changes.
 type Registry struct {
 	mu       sync.RWMutex
 	backends map[string]Backend
+
+	// stopping holds, keyed by backend ID, channels closed once the backend last unregistered
+	// under the ID has finished stopping; stopWait bounds how long Register waits for it
+	stopping map[string]chan struct{}
+	stopWait time.Duration
+
+	// statusMu guards statuses, the last observed status of each backend
+	statusMu sync.Mutex
+	statuses map[string]Status
+
+	// observersMu guards observers
+	observersMu sync.Mutex
+	observers   []*observer
 }
 
 // NewRegistry creates a new backend registry.
 func NewRegistry() *Registry {
 	return &Registry{
 		backends: make(map[string]Backend),
+		stopping: make(map[string]chan struct{}),
+		stopWait: StopWaitTimeout,
+		statuses: make(map[string]Status),
 	}
 }
 
-// Register adds a backend to the registry.
-// Returns an error if a backend with the same ID already exists.
+// Register adds a backend to the registry. A backend unregistered under the same ID that is
+// still stopping is waited for first, so two poll jobs of a backend never run at once.
+// Returns an error if a backend with the same ID already exists or is still stopping.
 func (r *Registry) Register(backend Backend) error {
 	if backend == nil {
 		return fmt.Errorf("cannot register nil backend")
@@ -31,21 +53,32 @@ func (r *Registry) Register(backend Backend) error {
 		return fmt.Errorf("backend ID cannot be empty")
 	}
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	if err := r.waitStopped(id); err != nil {
+		return err
+	}
+
+	status := backend.GetStatus()
 
+	r.mu.Lock()
 	if _, exists := r.backends[id]; exists {
+		r.mu.Unlock()
 		return fmt.Errorf("backend with ID %s already registered", id)
 	}
-
 	r.backends[id] = backend
+	r.mu.Unlock()
+
+	r.trackStatus(id, status)
+	if reporter, ok := backend.(StatusReporter); ok {
+		reporter.SetStatusListener(func() { r.reportStatus(backend) })
+	}
+	r.publishRegistered(backend)
 	return nil
 }
 
-// Unregister removes a backend from the registry and stops it.
-// Returns an error if the backend doesn't exist or cannot be stopped.
+// Unregister removes a backend from the registry and stops it, giving up waiting once the
+// context is done. Returns an error if the backend doesn't exist or cannot be stopped.
 // The backend is always removed from the registry, even if Stop fails.
-func (r *Registry) Unregister(id string) error {
+func (r *Registry) Unregister(ctx context.Context, id string) error {
 	r.mu.Lock()
 	backend, exists := r.backends[id]
 	if !exists {
@@ -55,16 +88,62 @@ func (r *Registry) Unregister(id string) error {
 
 	// Remove the backend from the registry first
 	delete(r.backends, id)
+	stopped := r.trackStopping(id)
 	r.mu.Unlock()
+	r.untrackStatus(id)
 
 	// Stop the backend after releasing the lock to avoid blocking other registry operations
-	if err := backend.Stop(); err != nil {
+	err := backend.Stop(ctx)
+	go waitForStop(backend, stopped)
+	r.publishUnregistered(backend)
+	if err != nil {
 		return fmt.Errorf("failed to stop backend %s: %w", id, err)
 	}
 
 	return nil
 }
 
+// trackStopping records that the backend unregistered under an ID is stopping and returns the
+// channel to close once it stopped. Callers must hold mu.
+func (r *Registry) trackStopping(id string) chan struct{} {
+	stopped := make(chan struct{})
+	r.stopping[id] = stopped
+	return stopped
+}
+
+// waitStopped waits, at most for the stop wait timeout, until the backend last unregistered
+// under an ID has finished stopping
+func (r *Registry) waitStopped(id string) error {
+	r.mu.RLock()
+	stopped, exists := r.stopping[id]
+	r.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(r.stopWait):
+		return fmt.Errorf("backend with ID %s is still stopping", id)
+	}
+
+	r.mu.Lock()
+	if r.stopping[id] == stopped {
+		delete(r.stopping, id)
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// waitForStop closes stopped once a backend whose Stop returned has finished its in-flight
+// work, which backends implementing StopWaiter may still be doing
+func waitForStop(backend Backend, stopped chan struct{}) {
+	if waiter, ok := backend.(StopWaiter); ok {
+		<-waiter.Stopped()
+	}
+	close(stopped)
+}
+
 // Get retrieves a backend by its ID.
 // Returns nil if the backend doesn't exist.
 func (r *Registry) Get(id string) Backend {
@@ -88,23 +167,88 @@ func (r *Registry) List() []Backend {
 	return backends
 }
 
-// UnregisterAll unregisters and stops all registered backends.
-// Returns the first error encountered, but continues unregistering remaining backends.
-func (r *Registry) UnregisterAll() error {
+// Pause pauses polling for a registered backend until the given time (zero pauses until resumed).
+// Returns an error if the backend doesn't exist or the pause cannot be saved.
+func (r *Registry) Pause(id string, until time.Time) error {
+	backend := r.Get(id)
+	if backend == nil {
+		return fmt.Errorf("backend with ID %s not found", id)
+	}
+
+	if err := backend.Pause(until); err != nil {
+		return fmt.Errorf("failed to pause backend %s: %w", id, err)
+	}
+	r.CheckStatus(id)
+	return nil
+}
+
+// Resume resumes polling for a paused backend.
+// Returns an error if the backend doesn't exist or the pause cannot be cleared.
+func (r *Registry) Resume(id string) error {
+	backend := r.Get(id)
+	if backend == nil {
+		return fmt.Errorf("backend with ID %s not found", id)
+	}
+
+	if err := backend.Resume(); err != nil {
+		return fmt.Errorf("failed to resume backend %s: %w", id, err)
+	}
+	r.CheckStatus(id)
+	return nil
+}
+
+// UnregisterAll unregisters all registered backends and stops them in parallel, giving up
+// waiting once the context is done. Backends still stopping then are reported unregistered
+// anyway and their Stop keeps running in the background, so a backend ignoring the context
+// can't block shutdown. Returns an error naming the backends that did not stop in time, or
+// else the first error encountered.
+func (r *Registry) UnregisterAll(ctx context.Context) error {
 	r.mu.Lock()
 	// Get all backends and clear the registry
 	backends := make([]Backend, 0, len(r.backends))
+	stopped := make(map[string]chan struct{}, len(r.backends))
 	for id, backend := range r.backends {
 		backends = append(backends, backend)
 		delete(r.backends, id)
+		stopped[id] = r.trackStopping(id)
 	}
 	r.mu.Unlock()
 
+	type stopResult struct {
+		backend Backend
+		err     error
+	}
+
 	// Stop all backends after releasing the lock
-	var firstError error
+	results := make(chan stopResult, len(backends))
+	pending := make(map[string]Backend, len(backends))
 	for _, backend := range backends {
-		if err := backend.Stop(); err != nil && firstError == nil {
-			firstError = fmt.Errorf("failed to stop backend %s: %w", backend.GetID(), err)
+		r.untrackStatus(backend.GetID())
+		pending[backend.GetID()] = backend
+		go func(backend Backend) {
+			err := backend.Stop(ctx)
+			waitForStop(backend, stopped[backend.GetID()])
+			results <- stopResult{backend: backend, err: err}
+		}(backend)
+	}
+
+	var firstError error
+	for len(pending) > 0 {
+		select {
+		case result := <-results:
+			delete(pending, result.backend.GetID())
+			if result.err != nil && firstError == nil {
+				firstError = fmt.Errorf("failed to stop backend %s: %w", result.backend.GetID(), result.err)
+			}
+			r.publishUnregistered(result.backend)
+		case <-ctx.Done():
+			ids := make([]string, 0, len(pending))
+			for id, backend := range pending {
+				ids = append(ids, id)
+				r.publishUnregistered(backend)
+			}
+			sort.Strings(ids)
+			return fmt.Errorf("backends %s did not stop in time: %w", strings.Join(ids, ", "), ctx.Err())
 		}
 	}
 
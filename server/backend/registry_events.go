@@ -0,0 +1,212 @@
+package backend
+
+import "sync"
+
+// RegistryObserver reacts to backend lifecycle changes in a Registry. Nil callbacks are skipped.
+// Each observer receives its events in order on its own goroutine, so a slow observer
+// delays neither the registry nor other observers.
+type RegistryObserver struct {
+	// OnRegistered is called after a backend is added to the registry
+	OnRegistered func(backend Backend)
+
+	// OnUnregistered is called after a backend is removed from the registry and stopped
+	OnUnregistered func(backend Backend)
+
+	// OnStatusChanged is called when a backend is enabled or disabled, paused or resumed,
+	// starts or stops failing, or enters or leaves a circuit breaker cool-down. Changes are
+	// detected when the backend is paused or resumed through the registry, or reports a change
+	// itself as a StatusReporter.
+	OnStatusChanged func(backend Backend, previous, current Status)
+}
+
+// statusSummary holds the parts of a status whose changes are reported to observers.
+// Changes to poll times and counters are not lifecycle changes.
+type statusSummary struct {
+	enabled     bool
+	paused      bool
+	failing     bool
+	coolingDown bool
+}
+
+// summarizeStatus returns the summary of a status compared to detect status changes
+func summarizeStatus(status Status) statusSummary {
+	return statusSummary{
+		enabled:     status.Enabled,
+		paused:      status.Paused,
+		failing:     status.ConsecutiveFailures > 0,
+		coolingDown: !status.CooldownUntil.IsZero(),
+	}
+}
+
+// observer delivers events to a RegistryObserver from a dedicated goroutine
+type observer struct {
+	RegistryObserver
+
+	// mu guards queue, the events waiting to be delivered
+	mu    sync.Mutex
+	queue []func()
+
+	// wake signals that events were queued; done stops delivery
+	wake chan struct{}
+	done chan struct{}
+}
+
+// newObserver creates an observer and starts delivering its events
+func newObserver(callbacks RegistryObserver) *observer {
+	o := &observer{
+		RegistryObserver: callbacks,
+		wake:             make(chan struct{}, 1),
+		done:             make(chan struct{}),
+	}
+	go o.run()
+	return o
+}
+
+// enqueue queues an event for delivery without blocking
+func (o *observer) enqueue(deliver func()) {
+	o.mu.Lock()
+	o.queue = append(o.queue, deliver)
+	o.mu.Unlock()
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers queued events in order until the observer is removed
+func (o *observer) run() {
+	for {
+		select {
+		case <-o.done:
+			return
+		case <-o.wake:
+		}
+
+		for {
+			o.mu.Lock()
+			if len(o.queue) == 0 {
+				o.mu.Unlock()
+				break
+			}
+			deliver := o.queue[0]
+			o.queue = o.queue[1:]
+			o.mu.Unlock()
+
+			deliver()
+		}
+	}
+}
+
+// Observe adds an observer notified of backend lifecycle changes from now on.
+// The returned function removes the observer; events not yet delivered are dropped.
+func (r *Registry) Observe(callbacks RegistryObserver) func() {
+	o := newObserver(callbacks)
+
+	r.observersMu.Lock()
+	r.observers = append(r.observers, o)
+	r.observersMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.observersMu.Lock()
+			for i, existing := range r.observers {
+				if existing == o {
+					r.observers = append(r.observers[:i:i], r.observers[i+1:]...)
+					break
+				}
+			}
+			r.observersMu.Unlock()
+			close(o.done)
+		})
+	}
+}
+
+// publishRegistered queues an OnRegistered event for every observer
+func (r *Registry) publishRegistered(backend Backend) {
+	r.observersMu.Lock()
+	defer r.observersMu.Unlock()
+
+	for _, o := range r.observers {
+		if o.OnRegistered != nil {
+			onRegistered := o.OnRegistered
+			o.enqueue(func() { onRegistered(backend) })
+		}
+	}
+}
+
+// publishUnregistered queues an OnUnregistered event for every observer
+func (r *Registry) publishUnregistered(backend Backend) {
+	r.observersMu.Lock()
+	defer r.observersMu.Unlock()
+
+	for _, o := range r.observers {
+		if o.OnUnregistered != nil {
+			onUnregistered := o.OnUnregistered
+			o.enqueue(func() { onUnregistered(backend) })
+		}
+	}
+}
+
+// CheckStatus compares the status of a registered backend with the last observed one and
+// notifies observers if it changed
+func (r *Registry) CheckStatus(id string) {
+	backend := r.Get(id)
+	if backend == nil {
+		return
+	}
+
+	// The status is taken while holding statusMu so concurrent checks compare and record
+	// statuses in the order they were taken
+	r.statusMu.Lock()
+	current := backend.GetStatus()
+	previous, tracked := r.statuses[id]
+	changed := tracked && summarizeStatus(previous) != summarizeStatus(current)
+	if tracked {
+		r.statuses[id] = current
+	}
+	r.statusMu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	r.observersMu.Lock()
+	defer r.observersMu.Unlock()
+
+	for _, o := range r.observers {
+		if o.OnStatusChanged != nil {
+			onStatusChanged := o.OnStatusChanged
+			o.enqueue(func() { onStatusChanged(backend, previous, current) })
+		}
+	}
+}
+
+// reportStatus checks the status of a backend that reported it may have changed. The check
+// runs in the background so the backend isn't blocked, and is skipped once the backend was
+// unregistered, including when another backend was registered under its ID since.
+func (r *Registry) reportStatus(backend Backend) {
+	go func() {
+		if r.Get(backend.GetID()) != backend {
+			return
+		}
+		r.CheckStatus(backend.GetID())
+	}()
+}
+
+// trackStatus records the status a backend was registered with, the baseline of later checks
+func (r *Registry) trackStatus(id string, status Status) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+
+	r.statuses[id] = status
+}
+
+// untrackStatus forgets the last observed status of a removed backend
+func (r *Registry) untrackStatus(id string) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+
+	delete(r.statuses, id)
+}
@@ -0,0 +1,214 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventRecorder records the events delivered to a registry observer
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (e *eventRecorder) observer() RegistryObserver {
+	return RegistryObserver{
+		OnRegistered: func(backend Backend) {
+			e.record("registered " + backend.GetID())
+		},
+		OnUnregistered: func(backend Backend) {
+			e.record("unregistered " + backend.GetID())
+		},
+		OnStatusChanged: func(backend Backend, previous, current Status) {
+			e.record(fmt.Sprintf("status %s paused=%t->%t failures=%d->%d",
+				backend.GetID(), previous.Paused, current.Paused, previous.ConsecutiveFailures, current.ConsecutiveFailures))
+		},
+	}
+}
+
+func (e *eventRecorder) record(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, event)
+}
+
+// waitFor waits until the given events were delivered, in order
+func (e *eventRecorder) waitFor(t *testing.T, expected ...string) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		return len(e.events) >= len(expected)
+	}, time.Second, time.Millisecond)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	assert.Equal(t, expected, e.events)
+}
+
+func TestRegistry_Observe(t *testing.T) {
+	registry := NewRegistry()
+	recorder := &eventRecorder{}
+	stop := registry.Observe(recorder.observer())
+	defer stop()
+
+	backend := newMockBackend("id1", "Backend 1", "dataminr")
+	require.NoError(t, registry.Register(backend))
+	require.Error(t, registry.Register(backend), "failed registrations are not reported")
+
+	require.NoError(t, registry.Pause("id1", time.Time{}))
+	require.NoError(t, registry.Resume("id1"))
+
+	require.NoError(t, registry.Register(newMockBackend("id2", "Backend 2", "dataminr")))
+	require.NoError(t, registry.Unregister(context.Background(), "id1"))
+	require.NoError(t, registry.UnregisterAll(context.Background()))
+
+	recorder.waitFor(t,
+		"registered id1",
+		"status id1 paused=false->true failures=0->0",
+		"status id1 paused=true->false failures=0->0",
+		"registered id2",
+		"unregistered id1",
+		"unregistered id2",
+	)
+}
+
+func TestRegistry_ObserveUnregisterStopError(t *testing.T) {
+	registry := NewRegistry()
+	recorder := &eventRecorder{}
+	defer registry.Observe(recorder.observer())()
+
+	backend := newMockBackend("id1", "Backend 1", "dataminr")
+	backend.stopErr = fmt.Errorf("stop failed")
+	require.NoError(t, registry.Register(backend))
+	require.Error(t, registry.Unregister(context.Background(), "id1"))
+
+	recorder.waitFor(t, "registered id1", "unregistered id1")
+}
+
+func TestRegistry_CheckStatus(t *testing.T) {
+	registry := NewRegistry()
+	recorder := &eventRecorder{}
+	defer registry.Observe(recorder.observer())()
+
+	backend := newMockBackend("id1", "Backend 1", "dataminr")
+	require.NoError(t, registry.Register(backend))
+
+	// Unchanged statuses are not reported
+	registry.CheckStatus("id1")
+
+	backend.setFailures(1)
+	registry.CheckStatus("id1")
+
+	// More failures don't change whether the backend is failing
+	backend.setFailures(2)
+	registry.CheckStatus("id1")
+
+	backend.setFailures(0)
+	registry.CheckStatus("id1")
+	registry.CheckStatus("missing")
+
+	recorder.waitFor(t,
+		"registered id1",
+		"status id1 paused=false->false failures=0->1",
+		"status id1 paused=false->false failures=2->0",
+	)
+}
+
+// reportingBackend is a mock backend reporting its status changes to the registry
+type reportingBackend struct {
+	*mockBackend
+	listener func()
+}
+
+func (r *reportingBackend) SetStatusListener(listener func()) {
+	r.listener = listener
+}
+
+func TestRegistry_StatusReporter(t *testing.T) {
+	registry := NewRegistry()
+	recorder := &eventRecorder{}
+	defer registry.Observe(recorder.observer())()
+
+	backend := &reportingBackend{mockBackend: newMockBackend("id1", "Backend 1", "dataminr")}
+	require.NoError(t, registry.Register(backend))
+	require.NotNil(t, backend.listener)
+
+	backend.setFailures(3)
+	backend.listener()
+	recorder.waitFor(t, "registered id1", "status id1 paused=false->false failures=0->3")
+
+	// A replaced backend's reports are ignored
+	require.NoError(t, registry.Unregister(context.Background(), "id1"))
+	replacement := &reportingBackend{mockBackend: newMockBackend("id1", "Backend 1", "dataminr")}
+	require.NoError(t, registry.Register(replacement))
+	replacement.setFailures(1)
+	backend.listener()
+	replacement.listener()
+
+	recorder.waitFor(t,
+		"registered id1",
+		"status id1 paused=false->false failures=0->3",
+		"unregistered id1",
+		"registered id1",
+		"status id1 paused=false->false failures=0->1",
+	)
+}
+
+func TestRegistry_ObserveStop(t *testing.T) {
+	registry := NewRegistry()
+	stopped := &eventRecorder{}
+	active := &eventRecorder{}
+	stop := registry.Observe(stopped.observer())
+	defer registry.Observe(active.observer())()
+
+	stop()
+	stop()
+
+	require.NoError(t, registry.Register(newMockBackend("id1", "Backend 1", "dataminr")))
+	active.waitFor(t, "registered id1")
+
+	stopped.mu.Lock()
+	defer stopped.mu.Unlock()
+	assert.Empty(t, stopped.events)
+}
+
+func TestRegistry_ObserveSlowObserver(t *testing.T) {
+	registry := NewRegistry()
+
+	release := make(chan struct{})
+	defer registry.Observe(RegistryObserver{
+		OnRegistered: func(Backend) { <-release },
+	})()
+
+	fast := &eventRecorder{}
+	defer registry.Observe(fast.observer())()
+
+	// A blocked observer delays neither the registry nor other observers
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, registry.Register(newMockBackend(fmt.Sprintf("id%d", i), "Backend", "dataminr")))
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("registry blocked on a slow observer")
+	}
+
+	expected := make([]string, 0, 10)
+	for i := 0; i < 10; i++ {
+		expected = append(expected, fmt.Sprintf("registered id%d", i))
+	}
+	fast.waitFor(t, expected...)
+	close(release)
+}
@@ -1,9 +1,11 @@
 package backend
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,11 +17,15 @@ type mockBackend struct {
 	name    string
 	typ     string
 	stopped bool
+	paused  bool
+	until   time.Time
+	fails   int
 	mu      sync.Mutex
 
 	// Errors to return
 	stopErr  error
 	startErr error
+	pauseErr error
 }
 
 func newMockBackend(id, name, typ string) *mockBackend {
@@ -30,19 +36,26 @@ func newMockBackend(id, name, typ string) *mockBackend {
 	}
 }
 
-func (m *mockBackend) Start() error {
+func (m *mockBackend) Start(_ context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	return m.startErr
 }
 
-func (m *mockBackend) Stop() error {
+func (m *mockBackend) Stop(_ context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.stopped = true
 	return m.stopErr
 }
 
+func (m *mockBackend) UpdateConfig(_ context.Context, config Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.name = config.Name
+	return nil
+}
+
 func (m *mockBackend) GetID() string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -64,7 +77,13 @@ func (m *mockBackend) GetType() string {
 func (m *mockBackend) GetStatus() Status {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return Status{}
+	return Status{Paused: m.paused, ConsecutiveFailures: m.fails}
+}
+
+func (m *mockBackend) setFailures(fails int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fails = fails
 }
 
 func (m *mockBackend) ClearOperationalState() error {
@@ -73,6 +92,55 @@ func (m *mockBackend) ClearOperationalState() error {
 	return nil
 }
 
+func (m *mockBackend) Pause(until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pauseErr != nil {
+		return m.pauseErr
+	}
+	m.paused = true
+	m.until = until
+	return nil
+}
+
+func (m *mockBackend) Resume() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paused = false
+	m.until = time.Time{}
+	return nil
+}
+
+func (m *mockBackend) GetPollHistory() ([]PollSample, error) {
+	return nil, nil
+}
+
+func (m *mockBackend) GetRecentLogs() []LogEntry {
+	return nil
+}
+
+func (m *mockBackend) FetchTaxonomy(context.Context) (Taxonomy, error) {
+	return Taxonomy{}, ErrTaxonomyUnsupported
+}
+
+func (m *mockBackend) CheckCredentials(context.Context) (CredentialCheck, error) {
+	return CredentialCheck{}, ErrCredentialCheckUnsupported
+}
+
+func (m *mockBackend) GetState() (StateSnapshot, error) {
+	return StateSnapshot{}, nil
+}
+
+func (m *mockBackend) ResetState(_ StateResetScope) error {
+	return nil
+}
+
+func (m *mockBackend) isPaused() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.paused
+}
+
 func (m *mockBackend) isStopped() bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -141,7 +209,7 @@ func TestRegistry_Unregister(t *testing.T) {
 	err := registry.Register(backend)
 	require.NoError(t, err)
 
-	err = registry.Unregister("backend1")
+	err = registry.Unregister(context.Background(), "backend1")
 	assert.NoError(t, err)
 	assert.Equal(t, 0, registry.Count())
 	assert.True(t, backend.isStopped())
@@ -150,10 +218,43 @@ func TestRegistry_Unregister(t *testing.T) {
 	assert.Nil(t, retrieved)
 }
 
+// lingeringBackend is a mock backend whose in-flight work outlives Stop until done is closed
+type lingeringBackend struct {
+	*mockBackend
+	done chan struct{}
+}
+
+func (b *lingeringBackend) Stopped() <-chan struct{} {
+	return b.done
+}
+
+func TestRegistry_RegisterWaitsForStoppingBackend(t *testing.T) {
+	registry := NewRegistry()
+	registry.stopWait = 10 * time.Millisecond
+
+	old := &lingeringBackend{mockBackend: newMockBackend("backend1", "Old Backend", "dataminr"), done: make(chan struct{})}
+	require.NoError(t, registry.Register(old))
+	require.NoError(t, registry.Unregister(context.Background(), "backend1"))
+
+	// The old backend's poll cycle is still running, so the ID can't be registered again
+	err := registry.Register(newMockBackend("backend1", "New Backend", "dataminr"))
+	require.Error(t, err)
+	assert.Equal(t, "backend with ID backend1 is still stopping", err.Error())
+	assert.Equal(t, 0, registry.Count())
+
+	// Other IDs are not held up
+	require.NoError(t, registry.Register(newMockBackend("backend2", "Other Backend", "dataminr")))
+
+	close(old.done)
+	registry.stopWait = 5 * time.Second
+	require.NoError(t, registry.Register(newMockBackend("backend1", "New Backend", "dataminr")))
+	assert.Equal(t, "New Backend", registry.Get("backend1").GetName())
+}
+
 func TestRegistry_UnregisterNotFound(t *testing.T) {
 	registry := NewRegistry()
 
-	err := registry.Unregister("nonexistent")
+	err := registry.Unregister(context.Background(), "nonexistent")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
 }
@@ -166,7 +267,7 @@ func TestRegistry_UnregisterStopError(t *testing.T) {
 	err := registry.Register(backend)
 	require.NoError(t, err)
 
-	err = registry.Unregister("backend1")
+	err = registry.Unregister(context.Background(), "backend1")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to stop backend")
 	assert.Contains(t, err.Error(), "stop failed")
@@ -236,7 +337,7 @@ func TestRegistry_UnregisterAll(t *testing.T) {
 	require.NoError(t, registry.Register(backend2))
 	require.NoError(t, registry.Register(backend3))
 
-	err := registry.UnregisterAll()
+	err := registry.UnregisterAll(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, 0, registry.Count())
 
@@ -258,7 +359,7 @@ func TestRegistry_UnregisterAllWithErrors(t *testing.T) {
 	require.NoError(t, registry.Register(backend2))
 	require.NoError(t, registry.Register(backend3))
 
-	err := registry.UnregisterAll()
+	err := registry.UnregisterAll(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to stop backend")
 
@@ -266,6 +367,85 @@ func TestRegistry_UnregisterAllWithErrors(t *testing.T) {
 	assert.Equal(t, 0, registry.Count())
 }
 
+// blockingStopBackend is a mock backend whose Stop blocks, ignoring its context, until
+// release is closed
+type blockingStopBackend struct {
+	*mockBackend
+	stopping chan struct{}
+	release  chan struct{}
+}
+
+func newBlockingStopBackend(id string) *blockingStopBackend {
+	return &blockingStopBackend{
+		mockBackend: newMockBackend(id, "Blocking "+id, "dataminr"),
+		stopping:    make(chan struct{}),
+		release:     make(chan struct{}),
+	}
+}
+
+func (b *blockingStopBackend) Stop(ctx context.Context) error {
+	close(b.stopping)
+	<-b.release
+	return b.mockBackend.Stop(ctx)
+}
+
+func TestRegistry_UnregisterAllInParallel(t *testing.T) {
+	registry := NewRegistry()
+
+	backend1 := newBlockingStopBackend("backend1")
+	backend2 := newBlockingStopBackend("backend2")
+	require.NoError(t, registry.Register(backend1))
+	require.NoError(t, registry.Register(backend2))
+
+	// Each backend finishes stopping only once the other started, which deadlocks serial stops
+	go func() {
+		<-backend1.stopping
+		<-backend2.stopping
+		close(backend1.release)
+		close(backend2.release)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, registry.UnregisterAll(ctx))
+	assert.True(t, backend1.isStopped())
+	assert.True(t, backend2.isStopped())
+}
+
+func TestRegistry_UnregisterAllDeadline(t *testing.T) {
+	registry := NewRegistry()
+	recorder := &eventRecorder{}
+	defer registry.Observe(recorder.observer())()
+
+	stuck := newBlockingStopBackend("stuck")
+	defer close(stuck.release)
+	healthy := newMockBackend("healthy", "Healthy", "dataminr")
+	require.NoError(t, registry.Register(stuck))
+	require.NoError(t, registry.Register(healthy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := registry.UnregisterAll(ctx)
+	assert.Less(t, time.Since(start), 5*time.Second, "A backend ignoring the deadline must not block unregistering")
+
+	require.Error(t, err)
+	assert.Equal(t, "backends stuck did not stop in time: context deadline exceeded", err.Error())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, healthy.isStopped())
+	assert.False(t, stuck.isStopped())
+	assert.Equal(t, 0, registry.Count())
+
+	// The stuck backend is reported unregistered anyway
+	recorder.waitFor(t,
+		"registered stuck",
+		"registered healthy",
+		"unregistered healthy",
+		"unregistered stuck",
+	)
+}
+
 func TestRegistry_Count(t *testing.T) {
 	registry := NewRegistry()
 	assert.Equal(t, 0, registry.Count())
@@ -278,9 +458,44 @@ func TestRegistry_Count(t *testing.T) {
 	require.NoError(t, registry.Register(backend2))
 	assert.Equal(t, 2, registry.Count())
 
-	require.NoError(t, registry.Unregister("backend1"))
+	require.NoError(t, registry.Unregister(context.Background(), "backend1"))
 	assert.Equal(t, 1, registry.Count())
 
-	require.NoError(t, registry.Unregister("backend2"))
+	require.NoError(t, registry.Unregister(context.Background(), "backend2"))
 	assert.Equal(t, 0, registry.Count())
 }
+
+func TestRegistry_PauseResume(t *testing.T) {
+	registry := NewRegistry()
+	backend := newMockBackend("id1", "Backend 1", "dataminr")
+	require.NoError(t, registry.Register(backend))
+
+	until := time.Now().Add(time.Hour)
+	require.NoError(t, registry.Pause("id1", until))
+	assert.True(t, backend.isPaused())
+	assert.Equal(t, until, backend.until)
+	assert.False(t, backend.isStopped(), "pausing must not stop the backend")
+
+	require.NoError(t, registry.Resume("id1"))
+	assert.False(t, backend.isPaused())
+}
+
+func TestRegistry_PauseResumeErrors(t *testing.T) {
+	registry := NewRegistry()
+
+	err := registry.Pause("missing", time.Time{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "backend with ID missing not found")
+
+	err = registry.Resume("missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "backend with ID missing not found")
+
+	backend := newMockBackend("id1", "Backend 1", "dataminr")
+	backend.pauseErr = fmt.Errorf("kv unavailable")
+	require.NoError(t, registry.Register(backend))
+
+	err = registry.Pause("id1", time.Time{})
+	require.Error(t, err)
+	assert.Equal(t, "failed to pause backend id1: kv unavailable", err.Error())
+}
@@ -0,0 +1,72 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+)
+
+// RequestLimitSettings tunes the HTTP requests a backend sends to its API, for tenancies
+// with rate limits different from the defaults
+type RequestLimitSettings struct {
+	// TimeoutSeconds is how long a single request may take (default: DefaultRequestTimeoutSeconds)
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+
+	// MaxRequestsPerMinute caps the alert requests sent in any one-minute window (0 means unlimited)
+	MaxRequestsPerMinute int `json:"maxRequestsPerMinute,omitempty"`
+
+	// MinIntervalMs is the minimum spacing between two alert requests in milliseconds (0 means none)
+	MinIntervalMs int `json:"minIntervalMs,omitempty"`
+
+	// MaxResponseSizeMB caps the size of a single API response (default: DefaultMaxResponseSizeMB)
+	MaxResponseSizeMB int `json:"maxResponseSizeMb,omitempty"`
+}
+
+// Validate checks that the timeout, request rate, spacing and response size are within range.
+func (r *RequestLimitSettings) Validate() error {
+	if r.TimeoutSeconds < 0 || r.TimeoutSeconds > MaxRequestTimeoutSeconds {
+		return fmt.Errorf("request timeout must be between 0 and %d seconds (got %d)", MaxRequestTimeoutSeconds, r.TimeoutSeconds)
+	}
+	if r.MaxRequestsPerMinute < 0 {
+		return fmt.Errorf("max requests per minute must not be negative (got %d)", r.MaxRequestsPerMinute)
+	}
+	if r.MinIntervalMs < 0 || r.MinIntervalMs > MaxRequestIntervalMs {
+		return fmt.Errorf("minimum request interval must be between 0 and %d milliseconds (got %d)", MaxRequestIntervalMs, r.MinIntervalMs)
+	}
+	if r.MaxResponseSizeMB < 0 || r.MaxResponseSizeMB > MaxResponseSizeMB {
+		return fmt.Errorf("response size limit must be between 0 and %d MB (got %d)", MaxResponseSizeMB, r.MaxResponseSizeMB)
+	}
+	return nil
+}
+
+// Timeout returns the request timeout, applying the default when unset or nil
+func (r *RequestLimitSettings) Timeout() time.Duration {
+	if r == nil || r.TimeoutSeconds <= 0 {
+		return DefaultRequestTimeoutSeconds * time.Second
+	}
+	return time.Duration(r.TimeoutSeconds) * time.Second
+}
+
+// RequestsPerMinute returns the maximum number of requests per minute (0 when unlimited or nil)
+func (r *RequestLimitSettings) RequestsPerMinute() int {
+	if r == nil {
+		return 0
+	}
+	return r.MaxRequestsPerMinute
+}
+
+// MinInterval returns the minimum spacing between requests (0 when unset or nil)
+func (r *RequestLimitSettings) MinInterval() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return time.Duration(r.MinIntervalMs) * time.Millisecond
+}
+
+// MaxResponseBytes returns the response size limit in bytes, applying the default when unset or nil
+func (r *RequestLimitSettings) MaxResponseBytes() int64 {
+	sizeMB := DefaultMaxResponseSizeMB
+	if r != nil && r.MaxResponseSizeMB > 0 {
+		sizeMB = r.MaxResponseSizeMB
+	}
+	return int64(sizeMB) * 1024 * 1024
+}
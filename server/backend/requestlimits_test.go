@@ -0,0 +1,54 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLimitSettings_Validate(t *testing.T) {
+	require.NoError(t, (&RequestLimitSettings{}).Validate())
+	require.NoError(t, (&RequestLimitSettings{TimeoutSeconds: 10, MaxRequestsPerMinute: 6, MinIntervalMs: 2000}).Validate())
+
+	err := (&RequestLimitSettings{TimeoutSeconds: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "request timeout must be between 0 and 300 seconds (got -1)")
+
+	err = (&RequestLimitSettings{TimeoutSeconds: MaxRequestTimeoutSeconds + 1}).Validate()
+	require.Error(t, err)
+
+	err = (&RequestLimitSettings{MaxRequestsPerMinute: -5}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max requests per minute must not be negative (got -5)")
+
+	err = (&RequestLimitSettings{MinIntervalMs: MaxRequestIntervalMs + 1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "minimum request interval must be between 0 and 60000 milliseconds")
+
+	require.NoError(t, (&RequestLimitSettings{MaxResponseSizeMB: MaxResponseSizeMB}).Validate())
+	err = (&RequestLimitSettings{MaxResponseSizeMB: MaxResponseSizeMB + 1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "response size limit must be between 0 and 100 MB (got 101)")
+}
+
+func TestRequestLimitSettings_Defaults(t *testing.T) {
+	var settings *RequestLimitSettings
+	assert.Equal(t, 30*time.Second, settings.Timeout())
+	assert.Equal(t, 0, settings.RequestsPerMinute())
+	assert.Equal(t, time.Duration(0), settings.MinInterval())
+	assert.Equal(t, int64(10*1024*1024), settings.MaxResponseBytes())
+
+	settings = &RequestLimitSettings{}
+	assert.Equal(t, 30*time.Second, settings.Timeout())
+	assert.Equal(t, int64(10*1024*1024), settings.MaxResponseBytes())
+
+	settings = &RequestLimitSettings{TimeoutSeconds: 5, MaxRequestsPerMinute: 12, MinIntervalMs: 1500}
+	assert.Equal(t, 5*time.Second, settings.Timeout())
+	assert.Equal(t, 12, settings.RequestsPerMinute())
+	assert.Equal(t, 1500*time.Millisecond, settings.MinInterval())
+
+	settings = &RequestLimitSettings{MaxResponseSizeMB: 2}
+	assert.Equal(t, int64(2*1024*1024), settings.MaxResponseBytes())
+}
@@ -0,0 +1,81 @@
+package backend
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	// UserAgentProduct is the product token starting the User-Agent of requests to backend APIs
+	UserAgentProduct = "mattermost-plugin-dataminr"
+
+	// maxUserAgentLength caps the text an administrator adds to the User-Agent
+	maxUserAgentLength = 128
+)
+
+// headerNamePattern matches valid HTTP header names
+var headerNamePattern = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+
+// reservedHeaders are set by the backend itself and can't carry the correlation ID
+var reservedHeaders = []string{"Authorization", "Content-Type", "Content-Length", "Host", "User-Agent"}
+
+// RequestTaggingSettings identify a backend's requests to its API, so the API provider's
+// support can trace them when a ticket is opened
+type RequestTaggingSettings struct {
+	// UserAgent is appended to the User-Agent of every request, after the plugin and
+	// Mattermost server versions (e.g., the name of the organization)
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// CorrelationIDHeader is the header carrying an ID generated for each poll cycle and
+	// sent with all of its requests, e.g. "X-Correlation-ID" (empty sends none). The ID is
+	// logged with the poll cycle.
+	CorrelationIDHeader string `json:"correlationIdHeader,omitempty"`
+}
+
+// Validate checks that the User-Agent text is printable and the header name is valid.
+func (s *RequestTaggingSettings) Validate() error {
+	if len(s.UserAgent) > maxUserAgentLength {
+		return fmt.Errorf("user agent must be at most %d characters (got %d)", maxUserAgentLength, len(s.UserAgent))
+	}
+	for _, r := range s.UserAgent {
+		if r < ' ' || r > '~' {
+			return fmt.Errorf("user agent must only contain printable ASCII characters")
+		}
+	}
+
+	if s.CorrelationIDHeader == "" {
+		return nil
+	}
+	if !headerNamePattern.MatchString(s.CorrelationIDHeader) {
+		return fmt.Errorf("invalid correlation ID header name '%s'", s.CorrelationIDHeader)
+	}
+	for _, reserved := range reservedHeaders {
+		if strings.EqualFold(s.CorrelationIDHeader, reserved) {
+			return fmt.Errorf("correlation ID header must not be %s", reserved)
+		}
+	}
+	return nil
+}
+
+// UserAgentHeader returns the User-Agent of requests: the plugin and server versions, followed
+// by the configured text. An empty base uses UserAgentProduct alone.
+func (s *RequestTaggingSettings) UserAgentHeader(base string) string {
+	if base == "" {
+		base = UserAgentProduct
+	}
+	if s == nil || strings.TrimSpace(s.UserAgent) == "" {
+		return base
+	}
+	return base + " " + strings.TrimSpace(s.UserAgent)
+}
+
+// CorrelationHeader returns the canonical name of the correlation ID header, or an empty
+// string when none is configured or the settings are nil
+func (s *RequestTaggingSettings) CorrelationHeader() string {
+	if s == nil || s.CorrelationIDHeader == "" {
+		return ""
+	}
+	return http.CanonicalHeaderKey(s.CorrelationIDHeader)
+}
@@ -0,0 +1,41 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTaggingSettings_Validate(t *testing.T) {
+	require.NoError(t, (&RequestTaggingSettings{}).Validate())
+	require.NoError(t, (&RequestTaggingSettings{UserAgent: "Acme SOC (ops@example.com)", CorrelationIDHeader: "X-Correlation-ID"}).Validate())
+
+	err := (&RequestTaggingSettings{UserAgent: strings.Repeat("a", maxUserAgentLength+1)}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "user agent must be at most 128 characters")
+
+	err = (&RequestTaggingSettings{UserAgent: "Acme\r\nX-Injected: 1"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "printable ASCII")
+
+	err = (&RequestTaggingSettings{CorrelationIDHeader: "X Correlation"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid correlation ID header name 'X Correlation'")
+
+	err = (&RequestTaggingSettings{CorrelationIDHeader: "authorization"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "correlation ID header must not be Authorization")
+}
+
+func TestRequestTaggingSettings_Headers(t *testing.T) {
+	var settings *RequestTaggingSettings
+	assert.Equal(t, UserAgentProduct, settings.UserAgentHeader(""))
+	assert.Equal(t, "mattermost-plugin-dataminr/1.0.0 Mattermost/10.5.0", settings.UserAgentHeader("mattermost-plugin-dataminr/1.0.0 Mattermost/10.5.0"))
+	assert.Empty(t, settings.CorrelationHeader())
+
+	settings = &RequestTaggingSettings{UserAgent: " Acme SOC ", CorrelationIDHeader: "x-correlation-id"}
+	assert.Equal(t, "mattermost-plugin-dataminr/1.0.0 Acme SOC", settings.UserAgentHeader("mattermost-plugin-dataminr/1.0.0"))
+	assert.Equal(t, "X-Correlation-Id", settings.CorrelationHeader())
+}
@@ -0,0 +1,57 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChannelRoute posts alerts to another channel than the backend's during a recurring weekly
+// schedule, e.g. to an after-hours channel outside business hours. The schedule is evaluated
+// on the wall clock of its timezone, so ranges follow daylight saving time.
+type ChannelRoute struct {
+	// ChannelID is the channel alerts are posted to while the route is active
+	ChannelID string `json:"channelId"`
+
+	// Timezone is the IANA timezone name used to evaluate the ranges (default: UTC)
+	Timezone string `json:"timezone,omitempty"`
+
+	// Days limits the schedule to specific days of the week ("mon" through "sun").
+	// An empty list means every day. For ranges that cross midnight, the day is
+	// the one on which the range starts.
+	Days []string `json:"days,omitempty"`
+
+	// Ranges is the list of time ranges within a day
+	Ranges []TimeRange `json:"ranges"`
+
+	// Outside makes the route active outside the schedule instead of during it, so business
+	// hours can be configured as is to route the remaining time
+	Outside bool `json:"outside,omitempty"`
+}
+
+// Validate checks that the route has a channel and a well formed schedule.
+func (r *ChannelRoute) Validate() error {
+	if r.ChannelID == "" {
+		return fmt.Errorf("missing required field 'channelId'")
+	}
+	return validateSchedule("route", r.Timezone, r.Days, r.Ranges)
+}
+
+// IsActive reports whether alerts are routed to the route's channel at the given instant.
+// Invalid schedules are never active.
+func (r *ChannelRoute) IsActive(now time.Time) bool {
+	if r.Validate() != nil {
+		return false
+	}
+	return scheduleActive(r.Timezone, r.Days, r.Ranges, now) != r.Outside
+}
+
+// RouteChannel returns the channel of the first route active at the given instant, or the
+// default channel if no route is active.
+func RouteChannel(routes []ChannelRoute, defaultChannelID string, now time.Time) string {
+	for i := range routes {
+		if routes[i].IsActive(now) {
+			return routes[i].ChannelID
+		}
+	}
+	return defaultChannelID
+}
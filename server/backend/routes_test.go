@@ -0,0 +1,164 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelRoute_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		route       ChannelRoute
+		errContains string
+	}{
+		{
+			name: "valid route outside business hours",
+			route: ChannelRoute{
+				ChannelID: "after-hours",
+				Timezone:  "America/New_York",
+				Days:      []string{"mon", "tue", "wed", "thu", "fri"},
+				Ranges:    []TimeRange{{Start: "09:00", End: "17:00"}},
+				Outside:   true,
+			},
+		},
+		{
+			name:        "missing channel",
+			route:       ChannelRoute{Ranges: []TimeRange{{Start: "09:00", End: "17:00"}}},
+			errContains: "missing required field 'channelId'",
+		},
+		{
+			name:        "no ranges",
+			route:       ChannelRoute{ChannelID: "after-hours"},
+			errContains: "route must define at least one time range",
+		},
+		{
+			name:        "invalid timezone",
+			route:       ChannelRoute{ChannelID: "after-hours", Timezone: "Mars/Olympus", Ranges: []TimeRange{{Start: "09:00", End: "17:00"}}},
+			errContains: "invalid route timezone",
+		},
+		{
+			name:        "invalid day",
+			route:       ChannelRoute{ChannelID: "after-hours", Days: []string{"funday"}, Ranges: []TimeRange{{Start: "09:00", End: "17:00"}}},
+			errContains: "invalid route day",
+		},
+		{
+			name:        "empty range",
+			route:       ChannelRoute{ChannelID: "after-hours", Ranges: []TimeRange{{Start: "09:00", End: "09:00"}}},
+			errContains: "is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.route.Validate()
+			if tt.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestRouteChannel_BusinessHours(t *testing.T) {
+	// Alerts go to the ops channel during New York business hours and to the after-hours
+	// channel the rest of the time
+	routes := []ChannelRoute{{
+		ChannelID: "after-hours",
+		Timezone:  "America/New_York",
+		Days:      []string{"mon", "tue", "wed", "thu", "fri"},
+		Ranges:    []TimeRange{{Start: "09:00", End: "17:00"}},
+		Outside:   true,
+	}}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		channel string
+	}{
+		// 2024-03-08 is the Friday before daylight saving time starts (EST, UTC-5)
+		{"friday before opening (EST)", time.Date(2024, 3, 8, 13, 59, 0, 0, time.UTC), "after-hours"},
+		{"friday opening (EST)", time.Date(2024, 3, 8, 14, 0, 0, 0, time.UTC), "ops"},
+		{"friday before closing (EST)", time.Date(2024, 3, 8, 21, 59, 0, 0, time.UTC), "ops"},
+		{"friday closing (EST)", time.Date(2024, 3, 8, 22, 0, 0, 0, time.UTC), "after-hours"},
+		{"saturday midday", time.Date(2024, 3, 9, 17, 0, 0, 0, time.UTC), "after-hours"},
+		{"sunday clocks spring forward", time.Date(2024, 3, 10, 14, 0, 0, 0, time.UTC), "after-hours"},
+
+		// 2024-03-11 is the first Monday of daylight saving time (EDT, UTC-4), so business
+		// hours start an hour earlier in UTC
+		{"monday before opening (EDT)", time.Date(2024, 3, 11, 12, 59, 0, 0, time.UTC), "after-hours"},
+		{"monday opening (EDT)", time.Date(2024, 3, 11, 13, 0, 0, 0, time.UTC), "ops"},
+		{"monday before closing (EDT)", time.Date(2024, 3, 11, 20, 59, 0, 0, time.UTC), "ops"},
+		{"monday closing (EDT)", time.Date(2024, 3, 11, 21, 0, 0, 0, time.UTC), "after-hours"},
+
+		// Daylight saving time ends on 2024-11-03, moving business hours an hour later in UTC
+		{"friday opening before fall back (EDT)", time.Date(2024, 11, 1, 13, 30, 0, 0, time.UTC), "ops"},
+		{"monday same UTC time after fall back (EST)", time.Date(2024, 11, 4, 13, 30, 0, 0, time.UTC), "after-hours"},
+		{"monday opening after fall back (EST)", time.Date(2024, 11, 4, 14, 0, 0, 0, time.UTC), "ops"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.channel, RouteChannel(routes, "ops", tt.now))
+		})
+	}
+}
+
+func TestRouteChannel_OvernightAcrossDST(t *testing.T) {
+	routes := []ChannelRoute{{
+		ChannelID: "night",
+		Timezone:  "America/New_York",
+		Ranges:    []TimeRange{{Start: "22:00", End: "06:00"}},
+	}}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		channel string
+	}{
+		// Clocks jump from 02:00 EST to 03:00 EDT at 07:00 UTC on 2024-03-10
+		{"before spring forward", time.Date(2024, 3, 10, 6, 59, 0, 0, time.UTC), "night"},
+		{"after spring forward", time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC), "night"},
+		{"last minute of the night (EDT)", time.Date(2024, 3, 10, 9, 59, 0, 0, time.UTC), "night"},
+		{"morning (EDT)", time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC), "ops"},
+
+		// Clocks fall back from 02:00 EDT to 01:00 EST at 06:00 UTC on 2024-11-03, so 01:30
+		// happens twice
+		{"first 01:30 (EDT)", time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC), "night"},
+		{"second 01:30 (EST)", time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC), "night"},
+		{"last minute of the night (EST)", time.Date(2024, 11, 3, 10, 59, 0, 0, time.UTC), "night"},
+		{"morning (EST)", time.Date(2024, 11, 3, 11, 0, 0, 0, time.UTC), "ops"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.channel, RouteChannel(routes, "ops", tt.now))
+		})
+	}
+}
+
+func TestRouteChannel_FirstActiveRouteWins(t *testing.T) {
+	routes := []ChannelRoute{
+		{ChannelID: "night", Ranges: []TimeRange{{Start: "22:00", End: "06:00"}}},
+		{ChannelID: "after-hours", Ranges: []TimeRange{{Start: "09:00", End: "17:00"}}, Outside: true},
+	}
+
+	// 2024-03-11 is a Monday
+	assert.Equal(t, "night", RouteChannel(routes, "ops", time.Date(2024, 3, 11, 3, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "after-hours", RouteChannel(routes, "ops", time.Date(2024, 3, 11, 18, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "ops", RouteChannel(routes, "ops", time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "ops", RouteChannel(nil, "ops", time.Date(2024, 3, 11, 3, 0, 0, 0, time.UTC)))
+}
+
+func TestChannelRoute_IsActive_InvalidSchedule(t *testing.T) {
+	// Invalid routes are never active, even when routing outside their schedule
+	route := ChannelRoute{ChannelID: "after-hours", Timezone: "Mars/Olympus", Ranges: []TimeRange{{Start: "09:00", End: "17:00"}}, Outside: true}
+	assert.False(t, route.IsActive(time.Date(2024, 3, 11, 3, 0, 0, 0, time.UTC)))
+
+	route = ChannelRoute{ChannelID: "after-hours", Outside: true}
+	assert.False(t, route.IsActive(time.Date(2024, 3, 11, 3, 0, 0, 0, time.UTC)))
+}
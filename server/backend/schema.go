@@ -0,0 +1,245 @@
+package backend
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaDialect is the JSON schema draft the backend configuration schema follows
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of a JSON schema used to describe backend configurations
+type Schema struct {
+	Dialect              string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+}
+
+// schemaConstraint applies a validation rule to the schema of a configuration field
+type schemaConstraint struct {
+	// path is the dotted JSON path of the field; constraints on array fields apply to their items
+	path string
+
+	apply func(*Schema)
+}
+
+// configConstraints mirrors the validation rules of backend configurations. Optional strings
+// accept the empty string, which the validator treats as unset. Rules that depend on other
+// fields or the plugin environment, such as unique names, are only checked by the validator.
+// The tests check every range against the validator, so the two can't drift apart.
+var configConstraints = []schemaConstraint{
+	{"id", withFormat("uuid")},
+	{"type", withEnum(sortedKeys(SupportedBackendTypes)...)},
+	{"url", withFormat("uri")},
+	{"alertListIds", withPattern(`^[^,\s]+$`)},
+	{"logLevel", withEnum("", LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError)},
+	{"pollIntervalSeconds", withRange(MinPollIntervalSeconds, -1)},
+	{"startupJitterSeconds", withRange(0, -1)},
+	{"catchUpHours", withRange(0, MaxCatchUpHours)},
+	{"dedupTTLHours", withRange(0, MaxDedupTTLHours)},
+	{"failureThreshold", withRange(0, MaxFailureThreshold)},
+	{"postConcurrency", withRange(0, MaxPostConcurrency)},
+	{"maxAlertsPerBatch", withRange(0, -1)},
+	{"locale", withEnum(append([]string{""}, sortedKeys(SupportedLocales)...)...)},
+	{"hashtagLocale", withEnum(append([]string{""}, sortedKeys(SupportedHashtagLocales)...)...)},
+	{"mediaUpload.maxSizeMb", withRange(0, MaxMediaUploadSizeMB)},
+	{"mediaBundle.mode", withEnum("", MediaBundleModeAttachments, MediaBundleModeFiles)},
+	{"mediaBundle.maxItems", withRange(0, MaxMediaBundleItems)},
+	{"mediaBundle.maxTotalSizeMb", withRange(0, MaxMediaBundleSizeMB)},
+	{"contentLimits.maxSourceTextChars", withRange(0, -1)},
+	{"contentLimits.maxMediaLinks", withRange(0, -1)},
+	{"contentLimits.maxTopics", withRange(0, -1)},
+	{"circuitBreaker.cooldownMinutes", withRange(0, MaxCooldownMinutes)},
+	{"circuitBreaker.maxCooldownCycles", withRange(0, -1)},
+	{"requestLimits.timeoutSeconds", withRange(0, MaxRequestTimeoutSeconds)},
+	{"requestLimits.maxRequestsPerMinute", withRange(0, -1)},
+	{"requestLimits.minIntervalMs", withRange(0, MaxRequestIntervalMs)},
+	{"requestLimits.maxResponseSizeMb", withRange(0, MaxResponseSizeMB)},
+	{"requestTagging.userAgent", withMaxLength(maxUserAgentLength)},
+	{"requestTagging.correlationIdHeader", withPattern(orEmpty(headerNamePattern.String()))},
+	{"failover", withRequired("apiId", "apiKey")},
+	{"failover.url", withFormat("uri")},
+	{"failover.authFailureThreshold", withRange(0, MaxFailureThreshold)},
+	{"apiEndpoints.alertVersion", withRange(0, -1)},
+	{"apiEndpoints.authPath", withPattern(orEmpty("^/"))},
+	{"apiEndpoints.alertsPath", withPattern(orEmpty("^/"))},
+	{"apiEndpoints.listsPath", withPattern(orEmpty("^/"))},
+	{"quietHours", withRequired("ranges")},
+	{"quietHours.ranges", withRequired("start", "end")},
+	{"routes", withRequired("channelId", "ranges")},
+	{"routes.ranges", withRequired("start", "end")},
+	{"hashtags.maxHashtags", withRange(0, -1)},
+	{"hashtags.customHashtags", withPattern(`^#?[\p{L}\p{N}_-]+$`)},
+	{"hashtags.placement", withEnum("", HashtagPlacementMessage, HashtagPlacementFooter, HashtagPlacementReply)},
+	{"topicStyles", withRequired("match")},
+	{"topicStyles.emoji", withMaxLength(maxTopicStyleEmojiLength)},
+	{"topicStyles.color", withPattern(orEmpty(colorPattern.String()))},
+	{"botIdentity.displayName", withMaxLength(maxBotDisplayNameLength)},
+	{"botIdentity.iconUrl", withFormat("uri")},
+	{"mentions.onCall", withRequired("url")},
+	{"mentions.onCall.url", withFormat("uri")},
+	{"mentions.onCall.alertTypes", withEnum("Flash", "Urgent", "Alert")},
+	{"mentions.onCall.cacheMinutes", withRange(0, MaxOnCallCacheMinutes)},
+	{"mentions.unavailable.action", withEnum("", UnavailableMentionDefer, UnavailableMentionOnCall)},
+	{"mentions.unavailable.deferMinutes", withRange(0, MaxMentionDeferMinutes)},
+	{"ackSla.windowMinutes", withRange(0, MaxAckWindowMinutes)},
+	{"alertTtl.action", withEnum("", AlertTTLActionStrikethrough, AlertTTLActionDelete)},
+	{"alertTtl.flashHours", withRange(0, MaxAlertTTLHours)},
+	{"alertTtl.urgentHours", withRange(0, MaxAlertTTLHours)},
+	{"alertTtl.alertHours", withRange(0, MaxAlertTTLHours)},
+	{"incidents.windowMinutes", withRange(0, MaxIncidentWindowMinutes)},
+	{"incidents.radiusKm", withRange(0, MaxIncidentRadiusKm)},
+	{"workflow.nameTemplate", withMaxLength(MaxWorkflowNameLength)},
+	{"workflow.alertTypes", withEnum("Flash", "Urgent", "Alert")},
+	{"summarizer", withRequired("url")},
+	{"summarizer.url", withFormat("uri")},
+	{"summarizer.minTextLength", withRange(0, -1)},
+	{"translator", withRequired("url")},
+	{"translator.url", withFormat("uri")},
+	{"translator.language", withPattern(orEmpty(languageCodePattern.String()))},
+	{"translator.channelLanguages", func(s *Schema) { s.AdditionalProperties.Pattern = languageCodePattern.String() }},
+	{"simulator.fixture", withPattern(orEmpty(fixtureNamePattern.String()))},
+	{"simulator.alertsPerPoll", withRange(0, MaxSimulatorAlertsPerPoll)},
+}
+
+// ConfigSchema returns a JSON schema describing a backend configuration, with the field types
+// of Config and the constraints its validation enforces, so configurations can be checked
+// before they are saved. Credentials are only required for backend types that poll an API,
+// so the schema lists just the fields every backend requires.
+func ConfigSchema() *Schema {
+	schema := schemaOf(reflect.TypeOf(Config{}))
+	schema.Dialect = SchemaDialect
+	schema.Title = "Backend configuration"
+	schema.Required = []string{"id", "name", "type", "channelId", "pollIntervalSeconds"}
+
+	for _, constraint := range configConstraints {
+		if field := schema.property(constraint.path); field != nil {
+			constraint.apply(field)
+		}
+	}
+	return schema
+}
+
+// property returns the schema of the field at a dotted JSON path, or nil if there is none.
+// Array fields resolve to the schema of their items.
+func (s *Schema) property(path string) *Schema {
+	current := s
+	for _, name := range strings.Split(path, ".") {
+		if current.Items != nil {
+			current = current.Items
+		}
+		current = current.Properties[name]
+		if current == nil {
+			return nil
+		}
+	}
+	if current.Items != nil {
+		return current.Items
+	}
+	return current
+}
+
+// schemaOf describes a Go type as a JSON schema, using the JSON names of struct fields
+func schemaOf(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaOf(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(t.Elem())}
+	case reflect.Struct:
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" || !field.IsExported() {
+				continue
+			}
+			schema.Properties[name] = schemaOf(field.Type)
+		}
+		return schema
+	default:
+		return &Schema{}
+	}
+}
+
+// withRange sets the minimum and maximum of a number; a negative maximum leaves it unbounded
+func withRange(minimum, maximum float64) func(*Schema) {
+	return func(s *Schema) {
+		s.Minimum = &minimum
+		if maximum >= 0 {
+			s.Maximum = &maximum
+		}
+	}
+}
+
+// withEnum restricts a string to the given values
+func withEnum(values ...string) func(*Schema) {
+	return func(s *Schema) {
+		s.Enum = values
+	}
+}
+
+// withFormat sets the format of a string
+func withFormat(format string) func(*Schema) {
+	return func(s *Schema) {
+		s.Format = format
+	}
+}
+
+// withPattern restricts a string to a regular expression
+func withPattern(pattern string) func(*Schema) {
+	return func(s *Schema) {
+		s.Pattern = pattern
+	}
+}
+
+// orEmpty extends a pattern to also match the empty string
+func orEmpty(pattern string) string {
+	return "^$|" + pattern
+}
+
+// withMaxLength caps the length of a string
+func withMaxLength(length int) func(*Schema) {
+	return func(s *Schema) {
+		s.MaxLength = &length
+	}
+}
+
+// withRequired lists the required fields of an object
+func withRequired(fields ...string) func(*Schema) {
+	return func(s *Schema) {
+		s.Required = fields
+	}
+}
+
+// sortedKeys returns the keys of a set in alphabetical order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
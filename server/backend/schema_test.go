@@ -0,0 +1,141 @@
+package backend
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSchema(t *testing.T) {
+	schema := ConfigSchema()
+
+	assert.Equal(t, SchemaDialect, schema.Dialect)
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"id", "name", "type", "channelId", "pollIntervalSeconds"}, schema.Required)
+
+	// Field types follow the Config struct
+	assert.Equal(t, "string", schema.Properties["name"].Type)
+	assert.Equal(t, "boolean", schema.Properties["enabled"].Type)
+	assert.Equal(t, "integer", schema.Properties["pollIntervalSeconds"].Type)
+	assert.Equal(t, "array", schema.Properties["alertListIds"].Type)
+	assert.Equal(t, "string", schema.Properties["alertListIds"].Items.Type)
+	assert.Equal(t, "number", schema.property("incidents.radiusKm").Type)
+	assert.Equal(t, "object", schema.Properties["translator"].Properties["channelLanguages"].Type)
+
+	// Constraints follow the validation rules
+	assert.Equal(t, float64(MinPollIntervalSeconds), *schema.Properties["pollIntervalSeconds"].Minimum)
+	assert.Nil(t, schema.Properties["pollIntervalSeconds"].Maximum)
+	assert.Equal(t, float64(MaxCatchUpHours), *schema.Properties["catchUpHours"].Maximum)
+	assert.Equal(t, float64(MaxDedupTTLHours), *schema.Properties["dedupTTLHours"].Maximum)
+	assert.Equal(t, float64(MaxFailureThreshold), *schema.Properties["failureThreshold"].Maximum)
+	assert.Equal(t, []string{"dataminr", SimulatorType}, schema.Properties["type"].Enum)
+	assert.Equal(t, []string{"", "message", "footer", "reply"}, schema.property("hashtags.placement").Enum)
+	assert.Equal(t, []string{"match"}, schema.Properties["topicStyles"].Items.Required)
+	assert.Equal(t, []string{"channelId", "ranges"}, schema.Properties["routes"].Items.Required)
+	assert.Equal(t, []string{"start", "end"}, schema.property("routes.ranges").Required)
+	assert.Equal(t, "^$|"+colorPattern.String(), schema.property("topicStyles.color").Pattern)
+	assert.Equal(t, []string{"Flash", "Urgent", "Alert"}, schema.Properties["workflow"].Properties["alertTypes"].Items.Enum)
+	assert.Equal(t, MaxWorkflowNameLength, *schema.property("workflow.nameTemplate").MaxLength)
+}
+
+func TestConfigSchema_ConstraintPaths(t *testing.T) {
+	schema := ConfigSchema()
+
+	// Every constraint must name an existing field, so renamed fields don't silently drop it
+	for _, constraint := range configConstraints {
+		assert.NotNil(t, schema.property(constraint.path), "constraint path %s", constraint.path)
+	}
+}
+
+func TestConfigSchema_RangesMatchValidation(t *testing.T) {
+	base := Config{
+		ID:                  "550e8400-e29b-41d4-a716-446655440000",
+		Name:                "Test Backend",
+		Type:                "dataminr",
+		URL:                 "https://api.example.com",
+		APIId:               "test-id",
+		APIKey:              "test-key",
+		ChannelID:           "channel123",
+		PollIntervalSeconds: 30,
+
+		// Fields other ranges depend on, set so those ranges are checked in full
+		PostHistoricalAlerts: true,
+		FailureThreshold:     MaxFailureThreshold,
+	}
+
+	// Objects whose other fields are required, so only the field under test can fail
+	required := map[string]map[string]any{
+		"failover":        {"apiId": "standby-id", "apiKey": "standby-key"},
+		"mentions.onCall": {"url": "https://oncall.example.com"},
+		"summarizer":      {"url": "https://llm.example.com"},
+		"translator":      {"url": "https://translate.example.com"},
+	}
+
+	// validate checks the base configuration with the field at path set to value
+	validate := func(path string, value float64) error {
+		data, err := json.Marshal(base)
+		require.NoError(t, err)
+		var fields map[string]any
+		require.NoError(t, json.Unmarshal(data, &fields))
+
+		names := strings.Split(path, ".")
+		object := fields
+		for i, name := range names[:len(names)-1] {
+			child, ok := object[name].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				for key, value := range required[strings.Join(names[:i+1], ".")] {
+					child[key] = value
+				}
+				object[name] = child
+			}
+			object = child
+		}
+		object[names[len(names)-1]] = value
+
+		data, err = json.Marshal(fields)
+		require.NoError(t, err)
+		var config Config
+		require.NoError(t, json.Unmarshal(data, &config))
+		return ValidateBackends([]Config{config})
+	}
+
+	// Every range of the schema must accept the values the validator accepts and no others
+	schema := ConfigSchema()
+	ranges := 0
+	for _, constraint := range configConstraints {
+		field := schema.property(constraint.path)
+		if field.Minimum == nil && field.Maximum == nil {
+			continue
+		}
+		ranges++
+
+		if field.Minimum != nil {
+			assert.NoError(t, validate(constraint.path, *field.Minimum), "minimum of %s", constraint.path)
+			assert.Error(t, validate(constraint.path, *field.Minimum-1), "below the minimum of %s", constraint.path)
+		}
+		if field.Maximum != nil {
+			assert.NoError(t, validate(constraint.path, *field.Maximum), "maximum of %s", constraint.path)
+			assert.Error(t, validate(constraint.path, *field.Maximum+1), "above the maximum of %s", constraint.path)
+		}
+	}
+	assert.NotZero(t, ranges)
+}
+
+func TestConfigSchema_JSON(t *testing.T) {
+	data, err := json.Marshal(ConfigSchema())
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, SchemaDialect, decoded["$schema"])
+
+	properties := decoded["properties"].(map[string]interface{})
+	pollInterval := properties["pollIntervalSeconds"].(map[string]interface{})
+	assert.Equal(t, "integer", pollInterval["type"])
+	assert.Equal(t, float64(MinPollIntervalSeconds), pollInterval["minimum"])
+	assert.NotContains(t, pollInterval, "maximum")
+}
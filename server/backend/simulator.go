@@ -0,0 +1,66 @@
+package backend
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// SimulatorType is the type of backends that replay alerts from a fixture instead of polling
+// an API. Simulator backends need no URL or credentials, so demos and QA get realistic alert
+// traffic through the regular posting pipeline.
+const SimulatorType = "simulator"
+
+// fixtureNamePattern matches the names of uploaded simulator fixtures
+var fixtureNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+// SimulatorSettings configures which alerts a simulator backend replays and how fast
+type SimulatorSettings struct {
+	// Fixture is the name of an uploaded fixture to replay (empty replays the bundled fixture)
+	Fixture string `json:"fixture,omitempty"`
+
+	// AlertsPerPoll is how many alerts are replayed per poll cycle (default: DefaultSimulatorAlertsPerPoll)
+	AlertsPerPoll int `json:"alertsPerPoll,omitempty"`
+}
+
+// Validate checks the fixture name and the number of alerts per poll.
+func (s *SimulatorSettings) Validate() error {
+	if s.Fixture != "" {
+		if err := ValidateFixtureName(s.Fixture); err != nil {
+			return err
+		}
+	}
+	if s.AlertsPerPoll < 0 || s.AlertsPerPoll > MaxSimulatorAlertsPerPoll {
+		return fmt.Errorf("simulator alerts per poll must be between 0 and %d (got %d)", MaxSimulatorAlertsPerPoll, s.AlertsPerPoll)
+	}
+	return nil
+}
+
+// BatchSize returns how many alerts are replayed per poll cycle, applying the default when unset or nil
+func (s *SimulatorSettings) BatchSize() int {
+	if s == nil || s.AlertsPerPoll <= 0 {
+		return DefaultSimulatorAlertsPerPoll
+	}
+	return s.AlertsPerPoll
+}
+
+// FixtureName returns the name of the uploaded fixture to replay (empty for the bundled fixture)
+func (s *SimulatorSettings) FixtureName() string {
+	if s == nil {
+		return ""
+	}
+	return s.Fixture
+}
+
+// ValidateFixtureName checks that a simulator fixture name uses only lowercase letters,
+// digits, dashes and underscores
+func ValidateFixtureName(name string) error {
+	if !fixtureNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid simulator fixture name '%s' (use up to 64 lowercase letters, digits, dashes or underscores)", name)
+	}
+	return nil
+}
+
+// RequiresCredentials reports whether the backend type polls an API and needs a URL and credentials
+func (c Config) RequiresCredentials() bool {
+	return c.Type != SimulatorType
+}
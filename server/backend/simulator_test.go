@@ -0,0 +1,48 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatorSettings_Validate(t *testing.T) {
+	require.NoError(t, (&SimulatorSettings{}).Validate())
+	require.NoError(t, (&SimulatorSettings{Fixture: "qa_flash-alerts", AlertsPerPoll: MaxSimulatorAlertsPerPoll}).Validate())
+
+	err := (&SimulatorSettings{Fixture: "Bad Name"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid simulator fixture name 'Bad Name'")
+
+	err = (&SimulatorSettings{AlertsPerPoll: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "simulator alerts per poll must be between 0 and 40 (got -1)")
+
+	err = (&SimulatorSettings{AlertsPerPoll: MaxSimulatorAlertsPerPoll + 1}).Validate()
+	require.Error(t, err)
+}
+
+func TestSimulatorSettings_Defaults(t *testing.T) {
+	var settings *SimulatorSettings
+	assert.Equal(t, DefaultSimulatorAlertsPerPoll, settings.BatchSize())
+	assert.Empty(t, settings.FixtureName())
+
+	settings = &SimulatorSettings{Fixture: "demo", AlertsPerPoll: 7}
+	assert.Equal(t, 7, settings.BatchSize())
+	assert.Equal(t, "demo", settings.FixtureName())
+}
+
+func TestValidateFixtureName(t *testing.T) {
+	assert.NoError(t, ValidateFixtureName("demo"))
+	assert.NoError(t, ValidateFixtureName("qa_2-flash"))
+	assert.Error(t, ValidateFixtureName(""))
+	assert.Error(t, ValidateFixtureName("Demo"))
+	assert.Error(t, ValidateFixtureName("../demo"))
+	assert.Error(t, ValidateFixtureName(string(make([]byte, 65))))
+}
+
+func TestConfig_RequiresCredentials(t *testing.T) {
+	assert.True(t, Config{Type: "dataminr"}.RequiresCredentials())
+	assert.False(t, Config{Type: SimulatorType}.RequiresCredentials())
+}
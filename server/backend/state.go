@@ -0,0 +1,100 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StateSnapshot is the persisted polling state of a backend, for inspection by administrators.
+// The authentication token itself is never included.
+type StateSnapshot struct {
+	// Cursor is the pagination cursor the next poll continues from (empty starts a catch-up)
+	Cursor string `json:"cursor"`
+
+	// CursorResetPending indicates the cursor is discarded at the start of the next poll
+	CursorResetPending bool `json:"cursorResetPending"`
+
+	// WindowStart is set while alerts are fetched by time window after the API rejected the
+	// cursor; it is the start of the next window
+	WindowStart time.Time `json:"windowStart"`
+
+	// CatchUp is the progress of the catch-up in progress (nil when not catching up)
+	CatchUp *CatchUpProgress `json:"catchUp,omitempty"`
+
+	// HasAuthToken indicates an authentication token is cached
+	HasAuthToken bool `json:"hasAuthToken"`
+
+	// AuthTokenExpiry is when the cached authentication token expires (zero without a token)
+	AuthTokenExpiry time.Time `json:"authTokenExpiry"`
+
+	// ConsecutiveFailures is the count of consecutive polling failures
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+
+	// LastPollTime is the timestamp of the last poll attempt
+	LastPollTime time.Time `json:"lastPollTime"`
+
+	// LastSuccessTime is the timestamp of the last successful poll
+	LastSuccessTime time.Time `json:"lastSuccessTime"`
+
+	// NextPollTime is when the poll job of this server node next runs (zero if not scheduled).
+	// Unlike the rest of the snapshot it is not persisted.
+	NextPollTime time.Time `json:"nextPollTime"`
+
+	// LastError is the error message from the most recent failure
+	LastError string `json:"lastError"`
+
+	// Phase is the stored stage of the polling lifecycle
+	Phase Phase `json:"phase"`
+
+	// Paused indicates whether polling is paused, until PausedUntil (zero until resumed)
+	Paused      bool      `json:"paused"`
+	PausedUntil time.Time `json:"pausedUntil"`
+
+	// CooldownUntil is when the circuit breaker cool-down ends (zero if not cooling down)
+	CooldownUntil time.Time `json:"cooldownUntil"`
+
+	// CooldownCycles is the number of consecutive circuit breaker cool-down cycles
+	CooldownCycles int `json:"cooldownCycles"`
+
+	// BufferedAlerts is the number of alerts held back by quiet hours
+	BufferedAlerts int `json:"bufferedAlerts"`
+
+	// PendingAlerts is the number of alerts left unposted by an interrupted poll or waiting to
+	// be retried after failing to post
+	PendingAlerts int `json:"pendingAlerts"`
+
+	// FailedDeliveries is the number of recent alerts dropped after failing to post
+	FailedDeliveries int `json:"failedDeliveries"`
+}
+
+// StateResetScope selects the part of a backend's persisted state to reset
+type StateResetScope string
+
+const (
+	// StateResetCursor discards the cursor so the next poll runs the catch-up routine
+	StateResetCursor StateResetScope = "cursor"
+
+	// StateResetAuth discards the cached authentication token so the next poll authenticates again
+	StateResetAuth StateResetScope = "auth"
+
+	// StateResetFailures clears the failure counter, last error, circuit breaker cool-down and
+	// failed alert deliveries
+	StateResetFailures StateResetScope = "failures"
+
+	// StateResetAll resets the cursor, authentication token and failure tracking
+	StateResetAll StateResetScope = "all"
+)
+
+// StateResetScopes lists the supported reset scopes
+var StateResetScopes = []StateResetScope{StateResetCursor, StateResetAuth, StateResetFailures, StateResetAll}
+
+// ParseStateResetScope parses a case-insensitive reset scope
+func ParseStateResetScope(value string) (StateResetScope, error) {
+	for _, scope := range StateResetScopes {
+		if strings.EqualFold(value, string(scope)) {
+			return scope, nil
+		}
+	}
+	return "", fmt.Errorf("unknown state reset scope %q", value)
+}
@@ -0,0 +1,23 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStateResetScope(t *testing.T) {
+	for _, scope := range StateResetScopes {
+		parsed, err := ParseStateResetScope(string(scope))
+		require.NoError(t, err)
+		assert.Equal(t, scope, parsed)
+	}
+
+	parsed, err := ParseStateResetScope("Cursor")
+	require.NoError(t, err)
+	assert.Equal(t, StateResetCursor, parsed)
+
+	_, err = ParseStateResetScope("pause")
+	assert.Error(t, err)
+}
@@ -0,0 +1,185 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+)
+
+const (
+	// kvKeySubscriptions stores the channels subscribed to a backend, keyed by backend ID
+	kvKeySubscriptions = "backend_%s_subscriptions"
+
+	// maxUpdateAttempts bounds the retries of a subscription update that lost a race
+	// with another node
+	maxUpdateAttempts = 5
+)
+
+// ChannelSubscription is a channel that opted in to a backend's alerts in addition to
+// the backend's configured channel.
+type ChannelSubscription struct {
+	// ChannelID is the subscribed channel
+	ChannelID string `json:"channelId"`
+
+	// AlertTypes optionally restricts the subscription to these alert types (e.g., "Flash")
+	AlertTypes []string `json:"alertTypes,omitempty"`
+
+	// Keywords optionally restricts the subscription to alerts mentioning at least one
+	// keyword (case-insensitive) in the headline or a topic
+	Keywords []string `json:"keywords,omitempty"`
+
+	// CreatedBy is the user who subscribed the channel
+	CreatedBy string `json:"createdBy"`
+
+	// CreatedAt is when the channel was subscribed
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Matches reports whether an alert passes the subscription filters
+func (s ChannelSubscription) Matches(alert Alert) bool {
+	if len(s.AlertTypes) > 0 {
+		matched := false
+		for _, alertType := range s.AlertTypes {
+			if strings.EqualFold(alertType, alert.AlertType) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(s.Keywords) == 0 {
+		return true
+	}
+
+	text := strings.ToLower(alert.Headline + "\n" + strings.Join(alert.Topics, "\n"))
+	for _, keyword := range s.Keywords {
+		if strings.Contains(text, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SubscriptionStore keeps channel subscriptions in the plugin KV store.
+// Subscriptions are managed with slash commands rather than the plugin configuration,
+// so channel admins can opt in without a system admin.
+type SubscriptionStore struct {
+	api plugin.API
+}
+
+// NewSubscriptionStore creates a subscription store
+func NewSubscriptionStore(api plugin.API) *SubscriptionStore {
+	return &SubscriptionStore{api: api}
+}
+
+// List returns the channels subscribed to a backend
+func (s *SubscriptionStore) List(backendID string) ([]ChannelSubscription, error) {
+	data, appErr := s.api.KVGet(fmt.Sprintf(kvKeySubscriptions, backendID))
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get channel subscriptions: %w", appErr)
+	}
+
+	if data == nil {
+		return []ChannelSubscription{}, nil
+	}
+
+	var subscriptions []ChannelSubscription
+	if err := json.Unmarshal(data, &subscriptions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal channel subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// Add subscribes a channel to a backend, replacing the filters of an existing subscription.
+// Returns true if the channel was newly subscribed.
+func (s *SubscriptionStore) Add(backendID string, subscription ChannelSubscription) (bool, error) {
+	var added bool
+	err := s.update(backendID, func(subscriptions []ChannelSubscription) ([]ChannelSubscription, bool) {
+		for i, existing := range subscriptions {
+			if existing.ChannelID == subscription.ChannelID {
+				added = false
+				subscriptions[i] = subscription
+				return subscriptions, true
+			}
+		}
+
+		added = true
+		return append(subscriptions, subscription), true
+	})
+	return added, err
+}
+
+// Remove unsubscribes a channel from a backend.
+// Returns true if the channel was subscribed.
+func (s *SubscriptionStore) Remove(backendID, channelID string) (bool, error) {
+	var removed bool
+	err := s.update(backendID, func(subscriptions []ChannelSubscription) ([]ChannelSubscription, bool) {
+		remaining := make([]ChannelSubscription, 0, len(subscriptions))
+		for _, existing := range subscriptions {
+			if existing.ChannelID != channelID {
+				remaining = append(remaining, existing)
+			}
+		}
+
+		removed = len(remaining) < len(subscriptions)
+		return remaining, removed
+	})
+	return removed, err
+}
+
+// DeleteAll removes every subscription to a backend
+func (s *SubscriptionStore) DeleteAll(backendID string) error {
+	if appErr := s.api.KVDelete(fmt.Sprintf(kvKeySubscriptions, backendID)); appErr != nil {
+		return fmt.Errorf("failed to delete channel subscriptions: %w", appErr)
+	}
+	return nil
+}
+
+// update applies a change to the channels subscribed to a backend, retrying when another
+// node changed them concurrently. The change reports whether the subscriptions need saving.
+func (s *SubscriptionStore) update(backendID string, change func([]ChannelSubscription) ([]ChannelSubscription, bool)) error {
+	key := fmt.Sprintf(kvKeySubscriptions, backendID)
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		oldData, appErr := s.api.KVGet(key)
+		if appErr != nil {
+			return fmt.Errorf("failed to get channel subscriptions: %w", appErr)
+		}
+
+		subscriptions := []ChannelSubscription{}
+		if oldData != nil {
+			if err := json.Unmarshal(oldData, &subscriptions); err != nil {
+				return fmt.Errorf("failed to unmarshal channel subscriptions: %w", err)
+			}
+		}
+
+		subscriptions, changed := change(subscriptions)
+		if !changed {
+			return nil
+		}
+
+		data, err := json.Marshal(subscriptions)
+		if err != nil {
+			return fmt.Errorf("failed to marshal channel subscriptions: %w", err)
+		}
+
+		saved, appErr := s.api.KVSetWithOptions(key, data, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to save channel subscriptions: %w", appErr)
+		}
+		if saved {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to save channel subscriptions: too many concurrent updates")
+}
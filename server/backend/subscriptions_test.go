@@ -0,0 +1,131 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelSubscription_Matches(t *testing.T) {
+	alert := Alert{AlertType: "Flash", Headline: "Flooding reported downtown", Topics: []string{"Severe Weather"}}
+
+	tests := []struct {
+		name         string
+		subscription ChannelSubscription
+		expected     bool
+	}{
+		{name: "no filters", expected: true},
+		{name: "matching type", subscription: ChannelSubscription{AlertTypes: []string{"urgent", "flash"}}, expected: true},
+		{name: "other type", subscription: ChannelSubscription{AlertTypes: []string{"Urgent"}}, expected: false},
+		{name: "keyword in headline", subscription: ChannelSubscription{Keywords: []string{"FLOOD"}}, expected: true},
+		{name: "keyword in topic", subscription: ChannelSubscription{Keywords: []string{"weather"}}, expected: true},
+		{name: "no matching keyword", subscription: ChannelSubscription{Keywords: []string{"earthquake"}}, expected: false},
+		{
+			name:         "type and keyword must both match",
+			subscription: ChannelSubscription{AlertTypes: []string{"Urgent"}, Keywords: []string{"flood"}},
+			expected:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.subscription.Matches(alert))
+		})
+	}
+}
+
+func TestSubscriptionStore(t *testing.T) {
+	const key = "backend_backend-1_subscriptions"
+
+	t.Run("list with nothing stored", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, nil)
+
+		subscriptions, err := NewSubscriptionStore(api).List("backend-1")
+		require.NoError(t, err)
+		assert.Empty(t, subscriptions)
+	})
+
+	t.Run("add, update and remove", func(t *testing.T) {
+		api := &plugintest.API{}
+		var stored []byte
+		api.On("KVGet", key).Return(func(string) []byte { return stored }, nil)
+		api.On("KVSetWithOptions", key, mock.Anything, mock.Anything).Return(
+			func(_ string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+				if !bytes.Equal(stored, options.OldValue) {
+					return false, nil
+				}
+				stored = value
+				return true, nil
+			})
+
+		store := NewSubscriptionStore(api)
+
+		added, err := store.Add("backend-1", ChannelSubscription{ChannelID: "channel-1"})
+		require.NoError(t, err)
+		assert.True(t, added)
+
+		added, err = store.Add("backend-1", ChannelSubscription{ChannelID: "channel-1", Keywords: []string{"flood"}})
+		require.NoError(t, err)
+		assert.False(t, added)
+
+		_, err = store.Add("backend-1", ChannelSubscription{ChannelID: "channel-2"})
+		require.NoError(t, err)
+
+		var subscriptions []ChannelSubscription
+		require.NoError(t, json.Unmarshal(stored, &subscriptions))
+		require.Len(t, subscriptions, 2)
+		assert.Equal(t, []string{"flood"}, subscriptions[0].Keywords)
+
+		removed, err := store.Remove("backend-1", "channel-1")
+		require.NoError(t, err)
+		assert.True(t, removed)
+
+		removed, err = store.Remove("backend-1", "channel-1")
+		require.NoError(t, err)
+		assert.False(t, removed)
+
+		subscriptions, err = store.List("backend-1")
+		require.NoError(t, err)
+		require.Len(t, subscriptions, 1)
+		assert.Equal(t, "channel-2", subscriptions[0].ChannelID)
+	})
+
+	t.Run("add retries after a concurrent update", func(t *testing.T) {
+		api := &plugintest.API{}
+		concurrent, err := json.Marshal([]ChannelSubscription{{ChannelID: "channel-1"}})
+		require.NoError(t, err)
+		api.On("KVGet", key).Return(nil, nil).Once()
+		api.On("KVSetWithOptions", key, mock.Anything, mock.Anything).Return(false, nil).Once()
+		api.On("KVGet", key).Return(concurrent, nil).Once()
+
+		var stored []byte
+		api.On("KVSetWithOptions", key, mock.Anything, model.PluginKVSetOptions{Atomic: true, OldValue: concurrent}).Run(func(args mock.Arguments) {
+			stored = args.Get(1).([]byte)
+		}).Return(true, nil).Once()
+
+		added, err := NewSubscriptionStore(api).Add("backend-1", ChannelSubscription{ChannelID: "channel-2"})
+		require.NoError(t, err)
+		assert.True(t, added)
+
+		var subscriptions []ChannelSubscription
+		require.NoError(t, json.Unmarshal(stored, &subscriptions))
+		require.Len(t, subscriptions, 2, "The subscription added by the other node is kept")
+		assert.Equal(t, "channel-1", subscriptions[0].ChannelID)
+		api.AssertExpectations(t)
+	})
+
+	t.Run("delete all", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVDelete", key).Return(nil).Once()
+
+		require.NoError(t, NewSubscriptionStore(api).DeleteAll("backend-1"))
+		api.AssertExpectations(t)
+	})
+}
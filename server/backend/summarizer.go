@@ -0,0 +1,73 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Summarizer condenses long alert text into a short summary shown at the top of the alert post
+type Summarizer interface {
+	// Summarize returns a summary of the text (empty if there is nothing to add)
+	Summarize(ctx context.Context, text string) (string, error)
+}
+
+// NoopSummarizer is the Summarizer of backends without summarizer settings: it never summarizes
+type NoopSummarizer struct{}
+
+// Summarize returns an empty summary
+func (NoopSummarizer) Summarize(context.Context, string) (string, error) {
+	return "", nil
+}
+
+// SummarizerSettings configures an OpenAI-compatible chat completions endpoint used to
+// summarize long alert text, e.g. the Mattermost AI plugin or a self-hosted model server
+type SummarizerSettings struct {
+	// URL is the HTTP(S) chat completions endpoint the text is sent to with POST
+	URL string `json:"url"`
+
+	// Headers are added to the request (e.g., an Authorization header)
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Model is the model name sent with the request (optional, some endpoints require it)
+	Model string `json:"model,omitempty"`
+
+	// Prompt is the instruction sent before the alert text (default: a one to two sentence summary)
+	Prompt string `json:"prompt,omitempty"`
+
+	// MinTextLength is the shortest source or translated text, in characters, that is
+	// summarized (default: DefaultSummaryMinTextLength)
+	MinTextLength int `json:"minTextLength,omitempty"`
+}
+
+// defaultSummaryPrompt asks for a summary that fits at the top of an alert post
+const defaultSummaryPrompt = "Summarize the following alert text in one or two short sentences. Reply with the summary only."
+
+// Validate checks the endpoint URL and the minimum text length.
+func (s *SummarizerSettings) Validate() error {
+	if err := validateServiceEndpoint("summarizer", s.URL, s.Headers); err != nil {
+		return err
+	}
+
+	if s.MinTextLength < 0 {
+		return fmt.Errorf("summarizer minimum text length must not be negative (got %d)", s.MinTextLength)
+	}
+
+	return nil
+}
+
+// MinLength returns the shortest text summarized, applying the default when unset
+func (s SummarizerSettings) MinLength() int {
+	if s.MinTextLength <= 0 {
+		return DefaultSummaryMinTextLength
+	}
+	return s.MinTextLength
+}
+
+// Instruction returns the prompt sent before the alert text, applying the default when unset
+func (s SummarizerSettings) Instruction() string {
+	if strings.TrimSpace(s.Prompt) == "" {
+		return defaultSummaryPrompt
+	}
+	return s.Prompt
+}
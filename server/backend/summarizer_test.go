@@ -0,0 +1,50 @@
+package backend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizerSettings_Validate(t *testing.T) {
+	require.NoError(t, (&SummarizerSettings{URL: "https://llm.example.com/v1/chat/completions"}).Validate())
+	require.NoError(t, (&SummarizerSettings{
+		URL:           "http://localhost:8065/plugins/mattermost-ai/v1/chat/completions",
+		Headers:       map[string]string{"Authorization": "Bearer token"},
+		MinTextLength: 200,
+	}).Validate())
+
+	err := (&SummarizerSettings{}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "summarizer URL must be an http or https URL (got '')")
+
+	err = (&SummarizerSettings{URL: "ftp://llm.example.com"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "summarizer URL must be an http or https URL (got 'ftp://llm.example.com')")
+
+	err = (&SummarizerSettings{URL: "https://llm.example.com", Headers: map[string]string{"Bad Header": "x"}}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid summarizer header name 'Bad Header'")
+
+	err = (&SummarizerSettings{URL: "https://llm.example.com", MinTextLength: -1}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "summarizer minimum text length must not be negative (got -1)")
+}
+
+func TestSummarizerSettings_Defaults(t *testing.T) {
+	settings := SummarizerSettings{}
+	assert.Equal(t, DefaultSummaryMinTextLength, settings.MinLength())
+	assert.Equal(t, defaultSummaryPrompt, settings.Instruction())
+
+	settings = SummarizerSettings{MinTextLength: 100, Prompt: "Summarize in French."}
+	assert.Equal(t, 100, settings.MinLength())
+	assert.Equal(t, "Summarize in French.", settings.Instruction())
+}
+
+func TestNoopSummarizer(t *testing.T) {
+	summary, err := NoopSummarizer{}.Summarize(context.Background(), "some long text")
+	require.NoError(t, err)
+	assert.Empty(t, summary)
+}
@@ -0,0 +1,78 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrTaxonomyUnsupported is returned by backends whose source has no taxonomy to fetch
+var ErrTaxonomyUnsupported = errors.New("backend does not provide a taxonomy")
+
+// TaxonomyList is an alert list available to a backend's account
+type TaxonomyList struct {
+	// ID is the alert list ID used in AlertListIDs
+	ID string `json:"id"`
+
+	// Name is the display name of the alert list, as shown in alerts
+	Name string `json:"name"`
+
+	// Type is the kind of alert list, e.g. "TOPIC" or "COMPANY"
+	Type string `json:"type,omitempty"`
+}
+
+// Taxonomy is the alert lists and topics available to a backend's account, used to check
+// that configured filters and routing rules refer to names alerts can actually carry
+type Taxonomy struct {
+	// AlertLists are the alert lists available to the account
+	AlertLists []TaxonomyList `json:"alertLists"`
+
+	// Topics are the topic names alerts can be tagged with
+	Topics []string `json:"topics"`
+
+	// FetchedAt is when the taxonomy was fetched from the backend's source
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// Check returns a warning for each alert list ID and topic style of a backend configuration
+// that the taxonomy doesn't know. Unknown names are only warnings: the taxonomy may be
+// outdated, and alerts without a match are still posted.
+func (t Taxonomy) Check(cfg Config) []string {
+	var warnings []string
+
+	listIDs := make(map[string]bool, len(t.AlertLists))
+	for _, list := range t.AlertLists {
+		listIDs[list.ID] = true
+	}
+	for _, id := range cfg.AlertListIDs {
+		if !listIDs[id] {
+			warnings = append(warnings, fmt.Sprintf("alert list ID '%s' is not available to the account", id))
+		}
+	}
+
+	for _, style := range cfg.TopicStyles {
+		if !t.matchesName(style.Match) {
+			warnings = append(warnings, fmt.Sprintf("topic style '%s' matches no known topic or alert list", style.Match))
+		}
+	}
+
+	return warnings
+}
+
+// matchesName reports whether a topic or alert list name contains match, case-insensitively,
+// the way topic styles are matched against alerts
+func (t Taxonomy) matchesName(match string) bool {
+	match = strings.ToLower(match)
+	for _, topic := range t.Topics {
+		if strings.Contains(strings.ToLower(topic), match) {
+			return true
+		}
+	}
+	for _, list := range t.AlertLists {
+		if strings.Contains(strings.ToLower(list.Name), match) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaxonomy_Check(t *testing.T) {
+	taxonomy := Taxonomy{
+		AlertLists: []TaxonomyList{
+			{ID: "101", Name: "Severe Weather", Type: "TOPIC"},
+			{ID: "102", Name: "Acme Corp", Type: "COMPANY"},
+		},
+		Topics: []string{"Cybersecurity", "Fires"},
+	}
+
+	t.Run("known names", func(t *testing.T) {
+		cfg := Config{
+			AlertListIDs: []string{"101", "102"},
+			TopicStyles: []TopicStyle{
+				{Match: "cyber", Color: "#6A0DAD"},
+				{Match: "Weather", Emoji: ":cloud:"},
+			},
+		}
+		assert.Empty(t, taxonomy.Check(cfg))
+	})
+
+	t.Run("unknown names", func(t *testing.T) {
+		cfg := Config{
+			AlertListIDs: []string{"101", "999"},
+			TopicStyles:  []TopicStyle{{Match: "Earthquakes", Emoji: ":warning:"}},
+		}
+		assert.Equal(t, []string{
+			"alert list ID '999' is not available to the account",
+			"topic style 'Earthquakes' matches no known topic or alert list",
+		}, taxonomy.Check(cfg))
+	})
+}
@@ -0,0 +1,35 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeDisplaySettings controls how the event time of alerts is shown in posts
+type TimeDisplaySettings struct {
+	// Timezone is the IANA timezone name event times are shown in
+	// (empty keeps the UTC offset reported with the alert)
+	Timezone string `json:"timezone,omitempty"`
+
+	// ShowRelative appends how long ago the event occurred (e.g., "6 min ago")
+	ShowRelative bool `json:"showRelative,omitempty"`
+}
+
+// Validate checks that the timezone is known.
+func (t *TimeDisplaySettings) Validate() error {
+	_, err := t.Location()
+	return err
+}
+
+// Location resolves the display timezone. Returns nil if no timezone is configured.
+func (t TimeDisplaySettings) Location() (*time.Location, error) {
+	if t.Timezone == "" {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid display timezone '%s': %w", t.Timezone, err)
+	}
+	return loc, nil
+}
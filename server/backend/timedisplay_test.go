@@ -0,0 +1,28 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeDisplaySettings_Validate(t *testing.T) {
+	require.NoError(t, (&TimeDisplaySettings{}).Validate())
+	require.NoError(t, (&TimeDisplaySettings{Timezone: "Europe/Paris", ShowRelative: true}).Validate())
+
+	err := (&TimeDisplaySettings{Timezone: "Mars/Olympus_Mons"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid display timezone 'Mars/Olympus_Mons'")
+}
+
+func TestTimeDisplaySettings_Location(t *testing.T) {
+	loc, err := TimeDisplaySettings{}.Location()
+	require.NoError(t, err)
+	assert.Nil(t, loc)
+
+	loc, err = TimeDisplaySettings{Timezone: "America/New_York"}.Location()
+	require.NoError(t, err)
+	require.NotNil(t, loc)
+	assert.Equal(t, "America/New_York", loc.String())
+}
@@ -0,0 +1,80 @@
+package backend
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+)
+
+// TLSSettings customizes the TLS connection to a backend API, e.g. when traffic is
+// routed through a TLS-intercepting proxy or the API requires client certificates.
+type TLSSettings struct {
+	// CACertificates is a PEM bundle of additional trusted CA certificates
+	CACertificates string `json:"caCertificates,omitempty"`
+
+	// ClientCertificate is the PEM client certificate used for mutual TLS
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+
+	// ClientKey is the PEM private key for ClientCertificate
+	ClientKey string `json:"clientKey,omitempty"`
+}
+
+// Validate checks that the PEM inputs parse and the client certificate and key are set together.
+func (t *TLSSettings) Validate() error {
+	_, err := t.TLSConfig()
+	return err
+}
+
+// TLSConfig builds the TLS client configuration.
+// The custom CAs are trusted in addition to the system roots.
+func (t *TLSSettings) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if t.CACertificates != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(t.CACertificates)) {
+			return nil, fmt.Errorf("invalid CA certificates: no PEM certificates found")
+		}
+		config.RootCAs = pool
+	}
+
+	if (t.ClientCertificate == "") != (t.ClientKey == "") {
+		return nil, fmt.Errorf("client certificate and client key must be configured together")
+	}
+
+	if t.ClientCertificate != "" {
+		cert, err := tls.X509KeyPair([]byte(t.ClientCertificate), []byte(t.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// validateProxyURL checks that a proxy URL is an absolute http, https or socks5 URL
+func validateProxyURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("invalid proxy URL: scheme must be http, https or socks5 (got '%s')", parsed.Scheme)
+	}
+
+	if parsed.Host == "" {
+		return fmt.Errorf("invalid proxy URL: missing host")
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+)
+
+// kvKeyTopicMutes stores the topics muted in a channel, keyed by channel ID
+const kvKeyTopicMutes = "channel_%s_topic_mutes"
+
+// TopicMute suppresses alerts containing a topic in a channel until it expires
+type TopicMute struct {
+	// Topic is the muted topic, matched case-insensitively
+	Topic string `json:"topic"`
+
+	// Until is when the mute expires
+	Until time.Time `json:"until"`
+
+	// CreatedBy is the user who muted the topic
+	CreatedBy string `json:"createdBy"`
+}
+
+// MutedTopic returns the first active mute matching one of the alert topics, or nil if none match
+func MutedTopic(mutes []TopicMute, alert Alert, now time.Time) *TopicMute {
+	for i, mute := range mutes {
+		if !now.Before(mute.Until) {
+			continue
+		}
+		for _, topic := range alert.Topics {
+			if strings.EqualFold(topic, mute.Topic) {
+				return &mutes[i]
+			}
+		}
+	}
+	return nil
+}
+
+// TopicMuteStore keeps per-channel topic mutes in the plugin KV store
+type TopicMuteStore struct {
+	api plugin.API
+}
+
+// NewTopicMuteStore creates a topic mute store
+func NewTopicMuteStore(api plugin.API) *TopicMuteStore {
+	return &TopicMuteStore{api: api}
+}
+
+// List returns the active topic mutes of a channel. Expired mutes are left out.
+func (s *TopicMuteStore) List(channelID string, now time.Time) ([]TopicMute, error) {
+	data, appErr := s.api.KVGet(fmt.Sprintf(kvKeyTopicMutes, channelID))
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get topic mutes: %w", appErr)
+	}
+
+	if data == nil {
+		return []TopicMute{}, nil
+	}
+
+	var mutes []TopicMute
+	if err := json.Unmarshal(data, &mutes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal topic mutes: %w", err)
+	}
+
+	active := make([]TopicMute, 0, len(mutes))
+	for _, mute := range mutes {
+		if now.Before(mute.Until) {
+			active = append(active, mute)
+		}
+	}
+	return active, nil
+}
+
+// Mute mutes a topic in a channel, replacing the expiry of an existing mute of the same topic.
+// Expired mutes are cleaned up.
+func (s *TopicMuteStore) Mute(channelID string, mute TopicMute, now time.Time) error {
+	mutes, err := s.List(channelID, now)
+	if err != nil {
+		return err
+	}
+
+	for i, existing := range mutes {
+		if strings.EqualFold(existing.Topic, mute.Topic) {
+			mutes[i] = mute
+			return s.save(channelID, mutes)
+		}
+	}
+
+	return s.save(channelID, append(mutes, mute))
+}
+
+// Unmute removes a topic mute from a channel.
+// Returns true if the topic was muted.
+func (s *TopicMuteStore) Unmute(channelID, topic string, now time.Time) (bool, error) {
+	mutes, err := s.List(channelID, now)
+	if err != nil {
+		return false, err
+	}
+
+	remaining := make([]TopicMute, 0, len(mutes))
+	for _, existing := range mutes {
+		if !strings.EqualFold(existing.Topic, topic) {
+			remaining = append(remaining, existing)
+		}
+	}
+
+	if len(remaining) == len(mutes) {
+		return false, nil
+	}
+	return true, s.save(channelID, remaining)
+}
+
+// save stores the topic mutes of a channel (an empty list deletes the key)
+func (s *TopicMuteStore) save(channelID string, mutes []TopicMute) error {
+	key := fmt.Sprintf(kvKeyTopicMutes, channelID)
+	if len(mutes) == 0 {
+		if appErr := s.api.KVDelete(key); appErr != nil {
+			return fmt.Errorf("failed to clear topic mutes: %w", appErr)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(mutes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal topic mutes: %w", err)
+	}
+
+	if appErr := s.api.KVSet(key, data); appErr != nil {
+		return fmt.Errorf("failed to save topic mutes: %w", appErr)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutedTopic(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	alert := Alert{Topics: []string{"Severe Weather", "Flooding"}}
+
+	mutes := []TopicMute{
+		{Topic: "flooding", Until: now.Add(-time.Minute)},
+		{Topic: "severe weather", Until: now.Add(time.Hour)},
+	}
+
+	mute := MutedTopic(mutes, alert, now)
+	require.NotNil(t, mute)
+	assert.Equal(t, "severe weather", mute.Topic)
+
+	assert.Nil(t, MutedTopic(mutes[:1], alert, now), "expired mutes are ignored")
+	assert.Nil(t, MutedTopic(mutes, Alert{Topics: []string{"Fire"}}, now))
+}
+
+func TestTopicMuteStore(t *testing.T) {
+	const key = "channel_channel-1_topic_mutes"
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("lists active mutes only", func(t *testing.T) {
+		api := &plugintest.API{}
+		data, err := json.Marshal([]TopicMute{
+			{Topic: "Fire", Until: now.Add(-time.Hour)},
+			{Topic: "Flooding", Until: now.Add(time.Hour)},
+		})
+		require.NoError(t, err)
+		api.On("KVGet", key).Return(data, nil)
+
+		mutes, err := NewTopicMuteStore(api).List("channel-1", now)
+		require.NoError(t, err)
+		require.Len(t, mutes, 1)
+		assert.Equal(t, "Flooding", mutes[0].Topic)
+	})
+
+	t.Run("mute, extend and unmute", func(t *testing.T) {
+		api := &plugintest.API{}
+		var stored []byte
+		api.On("KVGet", key).Return(func(string) []byte { return stored }, nil)
+		api.On("KVSet", key, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).([]byte)
+		}).Return(nil)
+		api.On("KVDelete", key).Run(func(mock.Arguments) { stored = nil }).Return(nil)
+
+		store := NewTopicMuteStore(api)
+		require.NoError(t, store.Mute("channel-1", TopicMute{Topic: "Flooding", Until: now.Add(time.Hour)}, now))
+		require.NoError(t, store.Mute("channel-1", TopicMute{Topic: "flooding", Until: now.Add(2 * time.Hour)}, now))
+
+		mutes, err := store.List("channel-1", now)
+		require.NoError(t, err)
+		require.Len(t, mutes, 1)
+		assert.Equal(t, now.Add(2*time.Hour), mutes[0].Until)
+
+		removed, err := store.Unmute("channel-1", "FLOODING", now)
+		require.NoError(t, err)
+		assert.True(t, removed)
+		assert.Nil(t, stored)
+
+		removed, err = store.Unmute("channel-1", "Flooding", now)
+		require.NoError(t, err)
+		assert.False(t, removed)
+	})
+}
@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// colorPattern matches a hex color such as "#7B3FE4"
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// maxTopicStyleEmojiLength caps the length of a topic style emoji (a character or :shortcode:)
+const maxTopicStyleEmojiLength = 64
+
+// TopicStyle replaces the alert type emoji and attachment color of alerts with a matching
+// topic or alert list. A backend's styles are checked in order and the first match wins.
+// Flash alerts keep their alert type emoji and color unless the style applies to Flash.
+type TopicStyle struct {
+	// Match is matched case-insensitively against every topic and alert list name of an
+	// alert; a name containing it matches (e.g., "Cyber" matches "Cybersecurity")
+	Match string `json:"match"`
+
+	// Emoji replaces the alert type emoji in the post message, as a character or :shortcode: (optional)
+	Emoji string `json:"emoji,omitempty"`
+
+	// Color replaces the alert type attachment color, as a hex color such as "#7B3FE4" (optional)
+	Color string `json:"color,omitempty"`
+
+	// ApplyToFlash applies the style to Flash alerts too
+	ApplyToFlash bool `json:"applyToFlash,omitempty"`
+}
+
+// Validate checks that the style has a match and a well-formed emoji or color.
+func (s *TopicStyle) Validate() error {
+	if strings.TrimSpace(s.Match) == "" {
+		return fmt.Errorf("topic style match must not be empty")
+	}
+	if s.Emoji == "" && s.Color == "" {
+		return fmt.Errorf("topic style '%s' must set an emoji or a color", s.Match)
+	}
+	if len(s.Emoji) > maxTopicStyleEmojiLength || strings.IndexFunc(s.Emoji, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("invalid emoji '%s' in topic style '%s'", s.Emoji, s.Match)
+	}
+	if s.Color != "" && !colorPattern.MatchString(s.Color) {
+		return fmt.Errorf("invalid color '%s' in topic style '%s' (must be a hex color such as #7B3FE4)", s.Color, s.Match)
+	}
+	return nil
+}
+
+// validateTopicStyles checks every topic style of a list
+func validateTopicStyles(styles []TopicStyle) error {
+	for i := range styles {
+		if err := styles[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchTopicStyle returns the first style matching a topic or alert list of the alert
+func MatchTopicStyle(styles []TopicStyle, alert Alert) (TopicStyle, bool) {
+	isFlash := alert.Priority() == 0
+	for _, style := range styles {
+		if isFlash && !style.ApplyToFlash {
+			continue
+		}
+
+		match := strings.ToLower(style.Match)
+		for _, name := range append(append([]string(nil), alert.Topics...), alert.AlertLists...) {
+			if strings.Contains(strings.ToLower(name), match) {
+				return style, true
+			}
+		}
+	}
+	return TopicStyle{}, false
+}
@@ -0,0 +1,70 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicStyle_Validate(t *testing.T) {
+	require.NoError(t, (&TopicStyle{Match: "Cyber", Emoji: "💻", Color: "#7B3FE4"}).Validate())
+	require.NoError(t, (&TopicStyle{Match: "Weather", Emoji: ":cloud:"}).Validate())
+	require.NoError(t, (&TopicStyle{Match: "Fire", Color: "#ff0000"}).Validate())
+
+	tests := []struct {
+		name     string
+		style    TopicStyle
+		expected string
+	}{
+		{"empty match", TopicStyle{Match: " ", Color: "#7B3FE4"}, "topic style match must not be empty"},
+		{"no emoji or color", TopicStyle{Match: "Cyber"}, "topic style 'Cyber' must set an emoji or a color"},
+		{"emoji with spaces", TopicStyle{Match: "Cyber", Emoji: ":a b:"}, "invalid emoji ':a b:' in topic style 'Cyber'"},
+		{"named color", TopicStyle{Match: "Cyber", Color: "purple"}, "invalid color 'purple' in topic style 'Cyber'"},
+		{"short color", TopicStyle{Match: "Cyber", Color: "#fff"}, "invalid color '#fff' in topic style 'Cyber'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.style.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestMatchTopicStyle(t *testing.T) {
+	styles := []TopicStyle{
+		{Match: "cyber", Emoji: "💻", Color: "#7B3FE4"},
+		{Match: "Critical Infrastructure", Color: "#000000", ApplyToFlash: true},
+		{Match: "Security", Emoji: ":lock:"},
+	}
+
+	tests := []struct {
+		name     string
+		alert    Alert
+		expected string
+	}{
+		{"topic contains the match", Alert{AlertType: "Urgent", Topics: []string{"Cybersecurity"}}, "cyber"},
+		{"alert list matches", Alert{AlertType: "Alert", AlertLists: []string{"Critical Infrastructure"}}, "Critical Infrastructure"},
+		{"first match wins", Alert{AlertType: "Alert", Topics: []string{"Security", "Cyber Attack"}}, "cyber"},
+		{"Flash skips styles not applying to Flash", Alert{AlertType: "Flash", Topics: []string{"Cyber Attack", "Critical Infrastructure"}}, "Critical Infrastructure"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style, matched := MatchTopicStyle(styles, tt.alert)
+			require.True(t, matched)
+			assert.Equal(t, tt.expected, style.Match)
+		})
+	}
+
+	_, matched := MatchTopicStyle(styles, Alert{AlertType: "Alert", Topics: []string{"Weather"}})
+	assert.False(t, matched)
+
+	_, matched = MatchTopicStyle(styles, Alert{AlertType: "Flash", Topics: []string{"Cyber Attack"}})
+	assert.False(t, matched)
+
+	_, matched = MatchTopicStyle(nil, Alert{AlertType: "Alert", Topics: []string{"Cyber Attack"}})
+	assert.False(t, matched)
+}
@@ -0,0 +1,73 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// languageCodePattern matches ISO 639 language codes with an optional region or script (e.g., "fr", "zh-Hans")
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z]{2,4})?$`)
+
+// Translator machine translates alert text into a channel's language
+type Translator interface {
+	// Translate returns the text translated into the target language
+	Translate(ctx context.Context, text, targetLanguage string) (string, error)
+}
+
+// NoopTranslator is the Translator of backends without translator settings: it never translates
+type NoopTranslator struct{}
+
+// Translate returns an empty translation
+func (NoopTranslator) Translate(context.Context, string, string) (string, error) {
+	return "", nil
+}
+
+// TranslatorSettings configures a LibreTranslate-compatible endpoint used to translate the
+// headline and source text of alerts that Dataminr did not translate
+type TranslatorSettings struct {
+	// URL is the HTTP(S) translate endpoint the text is sent to with POST
+	URL string `json:"url"`
+
+	// APIKey is sent with each request for endpoints that require one (optional)
+	APIKey string `json:"apiKey,omitempty"`
+
+	// Headers are added to the request (e.g., an Authorization header)
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Language is the language alerts are translated to in every channel without an
+	// entry in ChannelLanguages (empty leaves them untranslated)
+	Language string `json:"language,omitempty"`
+
+	// ChannelLanguages sets the language alerts are translated to, keyed by channel ID
+	ChannelLanguages map[string]string `json:"channelLanguages,omitempty"`
+}
+
+// Validate checks the endpoint URL and the language codes.
+func (t *TranslatorSettings) Validate() error {
+	if err := validateServiceEndpoint("translator", t.URL, t.Headers); err != nil {
+		return err
+	}
+
+	if t.Language == "" && len(t.ChannelLanguages) == 0 {
+		return fmt.Errorf("translator must set a language or channel languages")
+	}
+	if t.Language != "" && !languageCodePattern.MatchString(t.Language) {
+		return fmt.Errorf("invalid translator language '%s' (must be a language code such as 'fr')", t.Language)
+	}
+	for channelID, language := range t.ChannelLanguages {
+		if !languageCodePattern.MatchString(language) {
+			return fmt.Errorf("invalid translator language '%s' for channel %s (must be a language code such as 'fr')", language, channelID)
+		}
+	}
+
+	return nil
+}
+
+// TargetLanguage returns the language alerts posted in a channel are translated to (empty for none)
+func (t TranslatorSettings) TargetLanguage(channelID string) string {
+	if language, exists := t.ChannelLanguages[channelID]; exists {
+		return language
+	}
+	return t.Language
+}
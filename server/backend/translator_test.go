@@ -0,0 +1,53 @@
+package backend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslatorSettings_Validate(t *testing.T) {
+	require.NoError(t, (&TranslatorSettings{URL: "https://translate.example.com/translate", Language: "fr"}).Validate())
+	require.NoError(t, (&TranslatorSettings{
+		URL:              "http://libretranslate:5000/translate",
+		APIKey:           "key",
+		ChannelLanguages: map[string]string{"channel-1": "de", "channel-2": "zh-Hans"},
+	}).Validate())
+
+	tests := []struct {
+		name     string
+		settings TranslatorSettings
+		expected string
+	}{
+		{"missing URL", TranslatorSettings{Language: "fr"}, "translator URL must be an http or https URL (got '')"},
+		{"invalid header", TranslatorSettings{URL: "https://t.example.com", Language: "fr", Headers: map[string]string{"X Key": "v"}}, "invalid translator header name 'X Key'"},
+		{"no language", TranslatorSettings{URL: "https://t.example.com"}, "translator must set a language or channel languages"},
+		{"invalid language", TranslatorSettings{URL: "https://t.example.com", Language: "French"}, "invalid translator language 'French'"},
+		{"invalid channel language", TranslatorSettings{URL: "https://t.example.com", ChannelLanguages: map[string]string{"channel-1": ""}}, "invalid translator language '' for channel channel-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestTranslatorSettings_TargetLanguage(t *testing.T) {
+	settings := TranslatorSettings{Language: "fr", ChannelLanguages: map[string]string{"channel-de": "de"}}
+	assert.Equal(t, "de", settings.TargetLanguage("channel-de"))
+	assert.Equal(t, "fr", settings.TargetLanguage("channel-other"))
+
+	settings = TranslatorSettings{ChannelLanguages: map[string]string{"channel-de": "de"}}
+	assert.Empty(t, settings.TargetLanguage("channel-other"))
+}
+
+func TestNoopTranslator(t *testing.T) {
+	translated, err := NoopTranslator{}.Translate(context.Background(), "Bonjour", "en")
+	require.NoError(t, err)
+	assert.Empty(t, translated)
+}
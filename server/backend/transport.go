@@ -0,0 +1,33 @@
+package backend
+
+import (
+	"net/http"
+)
+
+// NewHTTPTransport builds the HTTP transport for a backend's custom TLS and proxy settings,
+// or returns nil when neither is configured. The proxy password must already be resolved.
+func NewHTTPTransport(config Config) (*http.Transport, error) {
+	if config.TLS == nil && config.ProxyURL == "" {
+		return nil, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	proxyURL, err := config.ProxyEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return transport, nil
+}
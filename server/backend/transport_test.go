@@ -0,0 +1,40 @@
+package backend
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPTransport(t *testing.T) {
+	t.Run("nil without custom settings", func(t *testing.T) {
+		transport, err := NewHTTPTransport(Config{})
+		require.NoError(t, err)
+		assert.Nil(t, transport)
+	})
+
+	t.Run("applies the proxy", func(t *testing.T) {
+		transport, err := NewHTTPTransport(Config{ProxyURL: "http://proxy.internal:3128", ProxyUsername: "proxy-user"})
+		require.NoError(t, err)
+		require.NotNil(t, transport)
+
+		proxyURL, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "media.example.com"}})
+		require.NoError(t, err)
+		assert.Equal(t, "http://proxy-user@proxy.internal:3128", proxyURL.String())
+	})
+
+	t.Run("applies the TLS settings", func(t *testing.T) {
+		transport, err := NewHTTPTransport(Config{TLS: &TLSSettings{}})
+		require.NoError(t, err)
+		require.NotNil(t, transport)
+		assert.NotNil(t, transport.TLSClientConfig)
+	})
+
+	t.Run("invalid TLS settings", func(t *testing.T) {
+		_, err := NewHTTPTransport(Config{TLS: &TLSSettings{CACertificates: "invalid"}})
+		assert.ErrorContains(t, err, "invalid CA certificates")
+	})
+}
@@ -1,70 +1,363 @@
 package backend
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"reflect"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
 // SupportedBackendTypes lists all backend types this plugin supports
 var SupportedBackendTypes = map[string]bool{
-	"dataminr": true,
+	"dataminr":    true,
+	SimulatorType: true,
+}
+
+// SupportedHashtagLocales lists the locales available for localized location hashtags
+var SupportedHashtagLocales = map[string]bool{
+	"en": true,
+	"fr": true,
+	"de": true,
+	"es": true,
+}
+
+// SupportedLocales lists the locales available for alert post text
+var SupportedLocales = map[string]bool{
+	"en": true,
+	"fr": true,
+	"de": true,
+	"es": true,
+}
+
+// ValidationOptions relaxes backend validation for test and development setups
+type ValidationOptions struct {
+	// AllowInsecureURLs accepts plain HTTP backend URLs, e.g. to poll a mock server
+	AllowInsecureURLs bool
 }
 
 // ValidateBackends validates backend configurations.
 // This performs all validation steps defined in the specification.
 func ValidateBackends(configs []Config) error {
+	return ValidateBackendsWithOptions(configs, ValidationOptions{})
+}
+
+// ValidateBackendsWithOptions validates backend configurations like ValidateBackends,
+// relaxing the checks selected by the options.
+func ValidateBackendsWithOptions(configs []Config, opts ValidationOptions) error {
 	if len(configs) == 0 {
 		// Empty configuration is valid - no backends configured
 		return nil
 	}
 
-	// Step 2-8: Validate each backend and check for duplicates
+	// Step 2-16: Validate each backend and check for duplicates
 	seenIDs := make(map[string]bool)
 	seenNames := make(map[string]bool)
 
 	for i, config := range configs {
-		// Step 2: Required fields
-		if err := validateRequiredFields(config); err != nil {
-			return fmt.Errorf("backend configuration at position %d: %w", i+1, err)
+		if errs := validateBackend(config, i+1, opts, seenIDs, seenNames); len(errs) > 0 {
+			return errs[0]
 		}
+	}
 
-		// Step 3: UUID format
-		if err := validateUUID(config.ID); err != nil {
-			return fmt.Errorf("backend '%s': %w", config.Name, err)
-		}
+	return nil
+}
+
+// BackendValidationResult lists the validation errors of one backend in a configuration document
+type BackendValidationResult struct {
+	// Position is the 1-based position of the backend in the document
+	Position int `json:"position"`
+
+	// ID is the backend ID as given in the document
+	ID string `json:"id"`
+
+	// Name is the backend name as given in the document
+	Name string `json:"name"`
+
+	// Errors lists every validation error of the backend (empty if valid)
+	Errors []string `json:"errors"`
+
+	// Warnings lists issues that don't prevent saving the backend, such as alert lists
+	// missing from its cached taxonomy
+	Warnings []string `json:"warnings,omitempty"`
+}
 
-		// Step 4: Duplicate IDs
-		if seenIDs[config.ID] {
-			return fmt.Errorf("duplicate backend ID found: %s", config.ID)
+// ValidateBackendsJSON parses a JSON array of backend configurations and validates every backend
+// with the defaults applied, collecting all errors of each backend instead of stopping at the
+// first one. The defaults themselves are not validated. The parsed configurations are returned
+// without the defaults applied.
+// Returns an error only if the document is not a valid JSON array of backends.
+func ValidateBackendsJSON(data []byte, defaults *Defaults, opts ValidationOptions) ([]Config, []BackendValidationResult, error) {
+	var configs []Config
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, nil, fmt.Errorf("invalid backends JSON: %w", err)
+	}
+
+	seenIDs := make(map[string]bool)
+	seenNames := make(map[string]bool)
+
+	results := make([]BackendValidationResult, 0, len(configs))
+	for i, config := range defaults.Apply(configs) {
+		result := BackendValidationResult{Position: i + 1, ID: config.ID, Name: config.Name, Errors: []string{}}
+		for _, err := range validateBackend(config, i+1, opts, seenIDs, seenNames) {
+			result.Errors = append(result.Errors, err.Error())
 		}
+		results = append(results, result)
+	}
+
+	return configs, results, nil
+}
+
+// validateBackend runs the validation steps for a single backend and returns every error found,
+// in step order. Missing required fields end validation of the backend early since the
+// remaining steps depend on them. seenIDs and seenNames track the backends validated so far
+// to detect duplicates.
+func validateBackend(config Config, position int, opts ValidationOptions, seenIDs, seenNames map[string]bool) []error {
+	var errs []error
+	fail := func(err error) {
+		errs = append(errs, fmt.Errorf("backend '%s': %w", config.Name, err))
+	}
+
+	// Step 2: Required fields
+	if err := validateRequiredFields(config); err != nil {
 		seenIDs[config.ID] = true
+		seenNames[config.Name] = true
+		return []error{fmt.Errorf("backend configuration at position %d: %w", position, err)}
+	}
 
-		// Step 5: Duplicate names
-		if seenNames[config.Name] {
-			return fmt.Errorf("duplicate backend name found: '%s'", config.Name)
+	// Step 3: UUID format
+	if err := validateUUID(config.ID); err != nil {
+		fail(err)
+	}
+
+	// Step 4: Duplicate IDs
+	if seenIDs[config.ID] {
+		errs = append(errs, fmt.Errorf("duplicate backend ID found: %s", config.ID))
+	}
+	seenIDs[config.ID] = true
+
+	// Step 5: Duplicate names
+	if seenNames[config.Name] {
+		errs = append(errs, fmt.Errorf("duplicate backend name found: '%s'", config.Name))
+	}
+	seenNames[config.Name] = true
+
+	// Step 6: Type support and simulator settings
+	if !SupportedBackendTypes[config.Type] {
+		fail(fmt.Errorf("unsupported type '%s' (supported types are 'dataminr' and '%s')", config.Type, SimulatorType))
+	}
+
+	if config.Simulator != nil {
+		if err := config.Simulator.Validate(); err != nil {
+			fail(err)
 		}
-		seenNames[config.Name] = true
+	}
+
+	// Step 7: URL format, connection settings, alert list selection, request limits, API endpoints and failover
+	if config.RequiresCredentials() || config.URL != "" {
+		if err := validateURL(config.URL, opts.AllowInsecureURLs); err != nil {
+			fail(err)
+		}
+	}
+
+	if config.TLS != nil {
+		if err := config.TLS.Validate(); err != nil {
+			fail(err)
+		}
+	}
 
-		// Step 6: Type support
-		if !SupportedBackendTypes[config.Type] {
-			return fmt.Errorf("backend '%s': unsupported type '%s' (only 'dataminr' is currently supported)", config.Name, config.Type)
+	if err := config.validateProxy(); err != nil {
+		fail(err)
+	}
+
+	if err := validateAlertListIDs(config.AlertListIDs); err != nil {
+		fail(err)
+	}
+
+	if config.RequestLimits != nil {
+		if err := config.RequestLimits.Validate(); err != nil {
+			fail(err)
 		}
+	}
 
-		// Step 7: URL format
-		if err := validateURL(config.URL); err != nil {
-			return fmt.Errorf("backend '%s': %w", config.Name, err)
+	if config.RequestTagging != nil {
+		if err := config.RequestTagging.Validate(); err != nil {
+			fail(err)
 		}
+	}
 
-		// Step 8: Poll interval minimum
-		if config.PollIntervalSeconds < MinPollIntervalSeconds {
-			return fmt.Errorf("backend '%s': poll interval must be at least %d seconds (got %d)",
-				config.Name, MinPollIntervalSeconds, config.PollIntervalSeconds)
+	if config.APIEndpoints != nil {
+		if err := config.APIEndpoints.Validate(); err != nil {
+			fail(err)
 		}
 	}
 
-	return nil
+	if config.Failover != nil {
+		if !config.RequiresCredentials() {
+			fail(fmt.Errorf("failover is not supported by %s backends", config.Type))
+		} else if err := config.Failover.Validate(config.MaxFailures()); err != nil {
+			fail(err)
+		} else if config.Failover.URL != "" {
+			if err := validateURL(config.Failover.URL, opts.AllowInsecureURLs); err != nil {
+				fail(fmt.Errorf("failover %w", err))
+			}
+		}
+	}
+
+	// Step 8: Poll interval minimum and polling/posting/content limits
+	if config.PollIntervalSeconds < MinPollIntervalSeconds {
+		fail(fmt.Errorf("poll interval must be at least %d seconds (got %d)", MinPollIntervalSeconds, config.PollIntervalSeconds))
+	}
+
+	if config.StartupJitterSeconds < 0 {
+		fail(fmt.Errorf("startup jitter must not be negative (got %d)", config.StartupJitterSeconds))
+	}
+
+	if config.CatchUpHours < 0 || config.CatchUpHours > MaxCatchUpHours {
+		fail(fmt.Errorf("catch-up hours must be between 0 and %d (got %d)", MaxCatchUpHours, config.CatchUpHours))
+	} else if config.CatchUpHours > 0 && !config.PostHistoricalAlerts {
+		fail(fmt.Errorf("catch-up hours require postHistoricalAlerts, otherwise alerts published before the first poll are skipped"))
+	}
+
+	if config.DedupTTLHours < 0 || config.DedupTTLHours > MaxDedupTTLHours {
+		fail(fmt.Errorf("dedup TTL hours must be between 0 and %d (got %d)", MaxDedupTTLHours, config.DedupTTLHours))
+	}
+
+	if config.FailureThreshold < 0 || config.FailureThreshold > MaxFailureThreshold {
+		fail(fmt.Errorf("failure threshold must be between 0 and %d (got %d)", MaxFailureThreshold, config.FailureThreshold))
+	}
+
+	if config.PostConcurrency < 0 || config.PostConcurrency > MaxPostConcurrency {
+		fail(fmt.Errorf("post concurrency must be between 0 and %d (got %d)", MaxPostConcurrency, config.PostConcurrency))
+	}
+
+	if config.MaxAlertsPerBatch < 0 {
+		fail(fmt.Errorf("max alerts per batch must not be negative (got %d)", config.MaxAlertsPerBatch))
+	}
+
+	if config.MediaUpload != nil {
+		if err := config.MediaUpload.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	if config.MediaBundle != nil {
+		if err := config.MediaBundle.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	if config.ContentLimits != nil {
+		if err := config.ContentLimits.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	if config.CircuitBreaker != nil {
+		if err := config.CircuitBreaker.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	// Step 9: Quiet hours and channel routes
+	if config.QuietHours != nil {
+		if err := config.QuietHours.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	for i := range config.Routes {
+		if err := config.Routes[i].Validate(); err != nil {
+			fail(fmt.Errorf("route %d: %w", i+1, err))
+		}
+	}
+
+	// Step 10: Post locale, time display and hashtag locale
+	if config.TimeDisplay != nil {
+		if err := config.TimeDisplay.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	if config.Locale != "" && !SupportedLocales[config.Locale] {
+		fail(fmt.Errorf("unsupported locale '%s'", config.Locale))
+	}
+
+	if config.HashtagLocale != "" && !SupportedHashtagLocales[config.HashtagLocale] {
+		fail(fmt.Errorf("unsupported hashtag locale '%s'", config.HashtagLocale))
+	}
+
+	// Step 11: Hashtag settings and topic styles
+	if config.Hashtags != nil {
+		if err := config.Hashtags.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	if err := validateTopicStyles(config.TopicStyles); err != nil {
+		fail(err)
+	}
+
+	// Step 12: Bot identity override
+	if config.BotIdentity != nil {
+		if err := config.BotIdentity.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	// Step 13: Mention rules
+	if config.Mentions != nil {
+		if err := config.Mentions.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	// Step 14: Acknowledgment SLA, alert TTL, incident grouping and workflow
+	if config.AckSLA != nil {
+		if err := config.AckSLA.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	if config.AlertTTL != nil {
+		if err := config.AlertTTL.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	if config.Incidents != nil {
+		if err := config.Incidents.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	if config.Workflow != nil {
+		if err := config.Workflow.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	// Step 15: Log level
+	if err := ValidateLogLevel(config.LogLevel); err != nil {
+		fail(err)
+	}
+
+	// Step 16: Summarizer and translator
+	if config.Summarizer != nil {
+		if err := config.Summarizer.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	if config.Translator != nil {
+		if err := config.Translator.Validate(); err != nil {
+			fail(err)
+		}
+	}
+
+	return errs
 }
 
 // validateRequiredFields checks that all required fields are present and non-empty
@@ -78,14 +371,16 @@ func validateRequiredFields(config Config) error {
 	if config.Type == "" {
 		return fmt.Errorf("missing required field 'type'")
 	}
-	if config.URL == "" {
-		return fmt.Errorf("missing required field 'url'")
-	}
-	if config.APIId == "" {
-		return fmt.Errorf("missing required field 'apiId'")
-	}
-	if config.APIKey == "" {
-		return fmt.Errorf("missing required field 'apiKey'")
+	if config.RequiresCredentials() {
+		if config.URL == "" {
+			return fmt.Errorf("missing required field 'url'")
+		}
+		if config.APIId == "" {
+			return fmt.Errorf("missing required field 'apiId'")
+		}
+		if config.APIKey == "" && !config.APIKeyStored {
+			return fmt.Errorf("missing required field 'apiKey'")
+		}
 	}
 	if config.ChannelID == "" {
 		return fmt.Errorf("missing required field 'channelId'")
@@ -111,8 +406,8 @@ func validateUUID(id string) error {
 	return nil
 }
 
-// validateURL checks that the URL is valid and uses HTTPS
-func validateURL(rawURL string) error {
+// validateURL checks that the URL is valid and uses HTTPS, or plain HTTP if allowInsecure is set
+func validateURL(rawURL string, allowInsecure bool) error {
 	if rawURL == "" {
 		return fmt.Errorf("url cannot be empty")
 	}
@@ -122,7 +417,7 @@ func validateURL(rawURL string) error {
 		return fmt.Errorf("invalid url format: %w", err)
 	}
 
-	if parsed.Scheme != "https" {
+	if parsed.Scheme != "https" && (!allowInsecure || parsed.Scheme != "http") {
 		return fmt.Errorf("url must use HTTPS (got %s)", parsed.Scheme)
 	}
 
@@ -151,8 +446,8 @@ func DiffBackendConfigs(oldConfigs, newConfigs []Config) (toAdd, toUpdate, toRem
 	for id, newCfg := range newMap {
 		if oldCfg, exists := oldMap[id]; !exists {
 			toAdd = append(toAdd, id)
-		} else if oldCfg != newCfg {
-			// Direct struct comparison works since all fields are primitive types
+		} else if !reflect.DeepEqual(oldCfg, newCfg) {
+			// Deep comparison is required since optional settings contain slices and pointers
 			toUpdate = append(toUpdate, id)
 		}
 	}
@@ -166,3 +461,36 @@ func DiffBackendConfigs(oldConfigs, newConfigs []Config) (toAdd, toUpdate, toRem
 
 	return toAdd, toUpdate, toRemove
 }
+
+// UpdatableInPlace reports whether a backend configuration change only touches settings a
+// running backend can apply without restarting: the name, channel, channel routes, the alert
+// filters (alert lists, quiet hours and batch limit) and poll interval. Any other change,
+// including enabling or disabling the backend, requires a restart.
+func UpdatableInPlace(oldCfg, newCfg Config) bool {
+	newCfg.Name = oldCfg.Name
+	newCfg.ChannelID = oldCfg.ChannelID
+	newCfg.Routes = oldCfg.Routes
+	newCfg.AlertListIDs = oldCfg.AlertListIDs
+	newCfg.QuietHours = oldCfg.QuietHours
+	newCfg.MaxAlertsPerBatch = oldCfg.MaxAlertsPerBatch
+	newCfg.PollIntervalSeconds = oldCfg.PollIntervalSeconds
+	return reflect.DeepEqual(oldCfg, newCfg)
+}
+
+// validateAlertListIDs checks that alert list IDs are non-empty, unique and contain no separators
+func validateAlertListIDs(ids []string) error {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("alert list ID must not be empty")
+		}
+		if strings.ContainsAny(id, ", \t") {
+			return fmt.Errorf("invalid alert list ID '%s' (must not contain commas or whitespace)", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicate alert list ID '%s'", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
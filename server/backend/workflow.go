@@ -0,0 +1,129 @@
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// workflowPlaceholder matches a placeholder in a workflow name template
+var workflowPlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// WorkflowSettings start a Playbooks run and/or create a Boards card for critical alerts,
+// linking back to the alert post. Both are created through the plugins' APIs on this server.
+type WorkflowSettings struct {
+	// PlaybookID is the playbook run for each matching alert (optional)
+	PlaybookID string `json:"playbookId,omitempty"`
+
+	// BoardID is the board a card is added to for each matching alert (optional)
+	BoardID string `json:"boardId,omitempty"`
+
+	// NameTemplate is the run name and card title, with {alertType}, {headline}, {backendName},
+	// {alertId}, {topics} and {location} placeholders (default: DefaultWorkflowNameTemplate)
+	NameTemplate string `json:"nameTemplate,omitempty"`
+
+	// OwnerUsername is the user owning the playbook runs and creating the cards
+	// (default: the plugin bot, which must then be a member of the playbook and board)
+	OwnerUsername string `json:"ownerUsername,omitempty"`
+
+	// AlertTypes lists the alert types that trigger the workflow (default: Flash)
+	AlertTypes []string `json:"alertTypes,omitempty"`
+}
+
+// Validate checks that a playbook or board is set, and the IDs, name template, owner and alert types.
+func (w *WorkflowSettings) Validate() error {
+	if w.PlaybookID == "" && w.BoardID == "" {
+		return fmt.Errorf("workflow requires a playbook ID or a board ID")
+	}
+	if w.PlaybookID != "" && !validWorkflowID(w.PlaybookID) {
+		return fmt.Errorf("invalid workflow playbook ID '%s'", w.PlaybookID)
+	}
+	if w.BoardID != "" && !validWorkflowID(w.BoardID) {
+		return fmt.Errorf("invalid workflow board ID '%s'", w.BoardID)
+	}
+
+	for _, placeholder := range workflowPlaceholder.FindAllString(w.NameTemplate, -1) {
+		if _, known := workflowFields[placeholder]; !known {
+			return fmt.Errorf("unknown workflow name placeholder '%s'", placeholder)
+		}
+	}
+
+	if w.OwnerUsername != "" && !ValidMentionName(normalizeMention(w.OwnerUsername)) {
+		return fmt.Errorf("invalid workflow owner username '%s'", w.OwnerUsername)
+	}
+
+	for _, alertType := range w.AlertTypes {
+		switch strings.ToLower(alertType) {
+		case "flash", "urgent", "alert":
+		default:
+			return fmt.Errorf("invalid workflow alert type '%s' (must be Flash, Urgent or Alert)", alertType)
+		}
+	}
+
+	return nil
+}
+
+// AppliesTo reports whether an alert type triggers the workflow
+func (w WorkflowSettings) AppliesTo(alertType string) bool {
+	if len(w.AlertTypes) == 0 {
+		return strings.EqualFold(alertType, "flash")
+	}
+	for _, t := range w.AlertTypes {
+		if strings.EqualFold(t, alertType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Owner returns the owner username without a leading @ (empty for the plugin bot)
+func (w WorkflowSettings) Owner() string {
+	return normalizeMention(w.OwnerUsername)
+}
+
+// Name returns the run name and card title for an alert, capped at MaxWorkflowNameLength characters
+func (w WorkflowSettings) Name(alert Alert) string {
+	template := w.NameTemplate
+	if strings.TrimSpace(template) == "" {
+		template = DefaultWorkflowNameTemplate
+	}
+
+	name := workflowPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		field, known := workflowFields[placeholder]
+		if !known {
+			return placeholder
+		}
+		return field(alert)
+	})
+	name = strings.Join(strings.Fields(name), " ")
+
+	if runes := []rune(name); len(runes) > MaxWorkflowNameLength {
+		name = string(runes[:MaxWorkflowNameLength-1]) + "…"
+	}
+	return name
+}
+
+// workflowFields maps workflow name placeholders to the alert fields they show
+var workflowFields = map[string]func(Alert) string{
+	"{alertType}":   func(a Alert) string { return a.AlertType },
+	"{headline}":    func(a Alert) string { return a.Headline },
+	"{backendName}": func(a Alert) string { return a.BackendName },
+	"{alertId}":     func(a Alert) string { return a.AlertID },
+	"{topics}":      func(a Alert) string { return strings.Join(a.Topics, ", ") },
+	"{location}": func(a Alert) string {
+		if a.Location == nil {
+			return ""
+		}
+		return a.Location.Address
+	},
+}
+
+// validWorkflowID reports whether a playbook or board ID only contains letters and digits
+func validWorkflowID(id string) bool {
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return id != ""
+}
@@ -0,0 +1,59 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowSettings_Validate(t *testing.T) {
+	assert.NoError(t, (&WorkflowSettings{PlaybookID: "abc123"}).Validate())
+	assert.NoError(t, (&WorkflowSettings{BoardID: "bxyz789"}).Validate())
+	assert.NoError(t, (&WorkflowSettings{
+		PlaybookID:    "abc123",
+		BoardID:       "bxyz789",
+		NameTemplate:  "[{alertType}] {headline} ({location})",
+		OwnerUsername: "@duty.officer",
+		AlertTypes:    []string{"Flash", "urgent"},
+	}).Validate())
+
+	assert.ErrorContains(t, (&WorkflowSettings{}).Validate(), "requires a playbook ID or a board ID")
+	assert.ErrorContains(t, (&WorkflowSettings{PlaybookID: "../runs"}).Validate(), "invalid workflow playbook ID")
+	assert.ErrorContains(t, (&WorkflowSettings{BoardID: "board id"}).Validate(), "invalid workflow board ID")
+	assert.ErrorContains(t, (&WorkflowSettings{PlaybookID: "abc123", NameTemplate: "{title}"}).Validate(), "unknown workflow name placeholder '{title}'")
+	assert.ErrorContains(t, (&WorkflowSettings{PlaybookID: "abc123", OwnerUsername: "duty officer"}).Validate(), "invalid workflow owner username")
+	assert.ErrorContains(t, (&WorkflowSettings{PlaybookID: "abc123", AlertTypes: []string{"critical"}}).Validate(), "invalid workflow alert type 'critical'")
+}
+
+func TestWorkflowSettings_AppliesTo(t *testing.T) {
+	assert.True(t, WorkflowSettings{}.AppliesTo("Flash"))
+	assert.False(t, WorkflowSettings{}.AppliesTo("Urgent"))
+	assert.True(t, WorkflowSettings{AlertTypes: []string{"urgent"}}.AppliesTo("Urgent"))
+	assert.False(t, WorkflowSettings{AlertTypes: []string{"urgent"}}.AppliesTo("Flash"))
+}
+
+func TestWorkflowSettings_Name(t *testing.T) {
+	alert := Alert{
+		BackendName: "Weather Watch",
+		AlertID:     "alert-1",
+		AlertType:   "Flash",
+		Headline:    "Explosion reported\nnear the port",
+		Topics:      []string{"Explosions", "Fires"},
+		Location:    &Location{Address: "Port of Oakland"},
+	}
+
+	assert.Equal(t, "Flash: Explosion reported near the port", WorkflowSettings{}.Name(alert))
+	assert.Equal(t, "[Weather Watch] Explosions, Fires at Port of Oakland (alert-1)",
+		WorkflowSettings{NameTemplate: "[{backendName}] {topics} at {location} ({alertId})"}.Name(alert))
+	assert.Equal(t, "Flash at", WorkflowSettings{NameTemplate: "{alertType} at {location}"}.Name(Alert{AlertType: "Flash"}))
+
+	long := WorkflowSettings{}.Name(Alert{AlertType: "Flash", Headline: strings.Repeat("a", 200)})
+	assert.Len(t, []rune(long), MaxWorkflowNameLength)
+	assert.True(t, strings.HasSuffix(long, "…"))
+}
+
+func TestWorkflowSettings_Owner(t *testing.T) {
+	assert.Empty(t, WorkflowSettings{}.Owner())
+	assert.Equal(t, "duty.officer", WorkflowSettings{OwnerUsername: " @Duty.Officer"}.Owner())
+}
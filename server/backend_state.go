@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// stateUsage describes the /dataminr state subcommands
+var stateUsage = fmt.Sprintf("Please use `/%[1]s state show <backend name>` or `/%[1]s state reset <backend name> [cursor|auth|failures|all]`.", commandTrigger)
+
+// executeState handles /dataminr state show <backend> and /dataminr state reset <backend> [scope]
+func (p *Plugin) executeState(args *model.CommandArgs, params []string) string {
+	if len(params) < 2 {
+		return stateUsage
+	}
+
+	switch params[0] {
+	case "show":
+		return p.executeStateShow(strings.Join(params[1:], " "))
+	case "reset":
+		return p.executeStateReset(args, params[1:])
+	default:
+		return stateUsage
+	}
+}
+
+// executeStateShow lists the persisted polling state of a backend
+func (p *Plugin) executeStateShow(name string) string {
+	b := p.findBackend(name)
+	if b == nil {
+		return fmt.Sprintf("Backend `%s` not found.", name)
+	}
+
+	state, err := b.GetState()
+	if err != nil {
+		p.API.LogError("Failed to load backend state", "id", b.GetID(), "error", err.Error())
+		return fmt.Sprintf("Failed to load the state of backend **%s**.", b.GetName())
+	}
+
+	return formatBackendState(b.GetName(), state)
+}
+
+// executeStateReset resets part of a backend's persisted polling state.
+// The last word is treated as the scope when it names one; the default scope is all.
+func (p *Plugin) executeStateReset(args *model.CommandArgs, params []string) string {
+	scope := backend.StateResetAll
+	if len(params) > 1 {
+		if parsed, err := backend.ParseStateResetScope(params[len(params)-1]); err == nil {
+			scope = parsed
+			params = params[:len(params)-1]
+		}
+	}
+
+	name := strings.Join(params, " ")
+	b := p.findBackend(name)
+	if b == nil {
+		return fmt.Sprintf("Backend `%s` not found.", name)
+	}
+
+	if err := b.ResetState(scope); err != nil {
+		p.API.LogError("Failed to reset backend state", "id", b.GetID(), "scope", string(scope), "userId", args.UserId, "error", err.Error())
+		return fmt.Sprintf("Failed to reset the state of backend **%s**.", b.GetName())
+	}
+
+	p.API.LogInfo("Backend state reset", "id", b.GetID(), "scope", string(scope), "userId", args.UserId)
+	p.registry.CheckStatus(b.GetID())
+	return describeStateReset(b.GetName(), scope)
+}
+
+// describeStateReset builds the confirmation text for a state reset
+func describeStateReset(name string, scope backend.StateResetScope) string {
+	switch scope {
+	case backend.StateResetCursor:
+		return fmt.Sprintf("The cursor of backend **%s** was reset. The next poll catches up on recent alerts.", name)
+	case backend.StateResetAuth:
+		return fmt.Sprintf("The cached token of backend **%s** was cleared. The next poll authenticates again.", name)
+	case backend.StateResetFailures:
+		return fmt.Sprintf("The failure tracking of backend **%s** was reset.", name)
+	default:
+		return fmt.Sprintf("The cursor, cached token and failure tracking of backend **%s** were reset. The next poll authenticates again and catches up on recent alerts.", name)
+	}
+}
+
+// formatBackendState lists a backend's persisted polling state
+func formatBackendState(name string, state backend.StateSnapshot) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("###### State of %s\n", name))
+
+	cursor := "none (the next poll catches up)"
+	switch {
+	case state.Cursor != "":
+		cursor = fmt.Sprintf("`%s`", state.Cursor)
+	case !state.WindowStart.IsZero():
+		cursor = fmt.Sprintf("none (rejected by the API, polling by time window from %s)", formatStateTime(state.WindowStart))
+	}
+	if state.CursorResetPending {
+		cursor += " (reset at the next poll)"
+	}
+	sb.WriteString(fmt.Sprintf("- **Cursor:** %s\n", cursor))
+
+	token := "none"
+	if state.HasAuthToken {
+		token = fmt.Sprintf("cached (redacted), expires %s", formatStateTime(state.AuthTokenExpiry))
+	}
+	sb.WriteString(fmt.Sprintf("- **Auth token:** %s\n", token))
+
+	phase := string(state.Phase)
+	if phase == "" {
+		phase = "unknown"
+	}
+	sb.WriteString(fmt.Sprintf("- **Phase:** %s\n", phase))
+	if state.CatchUp != nil {
+		sb.WriteString(fmt.Sprintf("- **Catch-up:** started %s, %d alerts posted, %d skipped\n",
+			formatStateTime(state.CatchUp.StartedAt), state.CatchUp.Posted, state.CatchUp.Skipped))
+	}
+	sb.WriteString(fmt.Sprintf("- **Last poll:** %s\n", formatStateTime(state.LastPollTime)))
+	sb.WriteString(fmt.Sprintf("- **Last success:** %s\n", formatStateTime(state.LastSuccessTime)))
+	sb.WriteString(fmt.Sprintf("- **Next poll:** %s\n", formatNextPoll(state.NextPollTime, time.Now())))
+	sb.WriteString(fmt.Sprintf("- **Consecutive failures:** %d\n", state.ConsecutiveFailures))
+	if state.LastError != "" {
+		sb.WriteString(fmt.Sprintf("- **Last error:** `%s`\n", state.LastError))
+	}
+
+	switch {
+	case state.Paused && state.PausedUntil.IsZero():
+		sb.WriteString("- **Paused:** until resumed\n")
+	case state.Paused:
+		sb.WriteString(fmt.Sprintf("- **Paused:** until %s\n", formatStateTime(state.PausedUntil)))
+	}
+	if state.CooldownUntil.After(time.Now()) {
+		sb.WriteString(fmt.Sprintf("- **Cool-down:** until %s (cycle %d)\n", formatStateTime(state.CooldownUntil), state.CooldownCycles))
+	}
+
+	sb.WriteString(fmt.Sprintf("- **Alerts held by quiet hours:** %d\n", state.BufferedAlerts))
+	sb.WriteString(fmt.Sprintf("- **Alerts waiting to be posted:** %d\n", state.PendingAlerts))
+	sb.WriteString(fmt.Sprintf("- **Alerts that failed to post:** %d", state.FailedDeliveries))
+
+	return sb.String()
+}
+
+// formatNextPoll describes when the poll job of this server runs next, telling a job waiting
+// for its interval apart from one whose run is overdue
+func formatNextPoll(next, now time.Time) string {
+	switch {
+	case next.IsZero():
+		return "not scheduled on this server"
+	case next.After(now):
+		return fmt.Sprintf("%s (in %s)", formatStateTime(next), next.Sub(now).Round(time.Second))
+	default:
+		return fmt.Sprintf("%s (overdue by %s: a poll cycle is running or the poll job is stuck)", formatStateTime(next), now.Sub(next).Round(time.Second))
+	}
+}
+
+// formatStateTime formats a state timestamp in UTC, or "never" for the zero time
+func formatStateTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.UTC().Format("2006-01-02 15:04 MST")
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestExecuteStateShow(t *testing.T) {
+	p, b := newPauseTestPlugin(t)
+	b.state = backend.StateSnapshot{
+		Cursor:              "cursor-123",
+		HasAuthToken:        true,
+		AuthTokenExpiry:     time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		ConsecutiveFailures: 2,
+		LastPollTime:        time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		LastError:           "timeout",
+		Phase:               backend.PhasePolling,
+		PendingAlerts:       3,
+		FailedDeliveries:    1,
+	}
+
+	text := p.executeState(&model.CommandArgs{}, []string{"show", "weather", "watch"})
+	assert.Contains(t, text, "###### State of Weather Watch")
+	assert.Contains(t, text, "- **Cursor:** `cursor-123`")
+	assert.Contains(t, text, "- **Auth token:** cached (redacted), expires 2026-03-01 12:30 UTC")
+	assert.Contains(t, text, "- **Last poll:** 2026-03-01 12:00 UTC")
+	assert.Contains(t, text, "- **Last success:** never")
+	assert.Contains(t, text, "- **Next poll:** not scheduled on this server")
+	assert.Contains(t, text, "- **Consecutive failures:** 2")
+	assert.Contains(t, text, "- **Last error:** `timeout`")
+	assert.Contains(t, text, "- **Alerts waiting to be posted:** 3")
+	assert.Contains(t, text, "- **Alerts that failed to post:** 1")
+	assert.NotContains(t, text, "Paused")
+
+	b.state = backend.StateSnapshot{CursorResetPending: true}
+	text = p.executeState(&model.CommandArgs{}, []string{"show", "backend-1"})
+	assert.Contains(t, text, "- **Cursor:** none (the next poll catches up) (reset at the next poll)")
+	assert.Contains(t, text, "- **Auth token:** none")
+
+	b.state = backend.StateSnapshot{WindowStart: time.Date(2026, 3, 1, 11, 59, 0, 0, time.UTC)}
+	text = p.executeState(&model.CommandArgs{}, []string{"show", "backend-1"})
+	assert.Contains(t, text, "- **Cursor:** none (rejected by the API, polling by time window from 2026-03-01 11:59 UTC)")
+
+	b.state = backend.StateSnapshot{
+		Phase:   backend.PhaseCatchingUp,
+		CatchUp: &backend.CatchUpProgress{StartedAt: time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC), Posted: 2, Skipped: 120},
+	}
+	text = p.executeState(&model.CommandArgs{}, []string{"show", "backend-1"})
+	assert.Contains(t, text, "- **Catch-up:** started 2026-03-01 11:00 UTC, 2 alerts posted, 120 skipped")
+
+	assert.Equal(t, "Backend `Other` not found.", p.executeState(&model.CommandArgs{}, []string{"show", "Other"}))
+}
+
+func TestFormatNextPoll(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "not scheduled on this server", formatNextPoll(time.Time{}, now))
+	assert.Equal(t, "2026-03-01 12:00 UTC (in 25s)", formatNextPoll(now.Add(25*time.Second), now))
+	assert.Equal(t, "2026-03-01 11:55 UTC (overdue by 5m0s: a poll cycle is running or the poll job is stuck)",
+		formatNextPoll(now.Add(-5*time.Minute), now))
+}
+
+func TestExecuteStateReset(t *testing.T) {
+	newPlugin := func(t *testing.T) (*Plugin, *fakeBackend) {
+		api := &plugintest.API{}
+		api.On("LogInfo", "Backend state reset", "id", "backend-1", "scope", mock.Anything, "userId", "user-id").Maybe()
+		p := newCommandTestPlugin(api)
+		p.registry = backend.NewRegistry()
+
+		b := &fakeBackend{id: "backend-1", name: "Weather Watch", status: backend.Status{Enabled: true}}
+		require.NoError(t, p.registry.Register(b))
+		return p, b
+	}
+
+	t.Run("resets the given scope", func(t *testing.T) {
+		p, b := newPlugin(t)
+
+		text := p.executeState(&model.CommandArgs{UserId: "user-id"}, []string{"reset", "Weather", "Watch", "cursor"})
+		assert.Equal(t, "The cursor of backend **Weather Watch** was reset. The next poll catches up on recent alerts.", text)
+		assert.Equal(t, []backend.StateResetScope{backend.StateResetCursor}, b.resets)
+
+		p.executeState(&model.CommandArgs{UserId: "user-id"}, []string{"reset", "Weather", "Watch", "AUTH"})
+		assert.Equal(t, []backend.StateResetScope{backend.StateResetCursor, backend.StateResetAuth}, b.resets)
+	})
+
+	t.Run("resets everything by default", func(t *testing.T) {
+		p, b := newPlugin(t)
+
+		text := p.executeState(&model.CommandArgs{UserId: "user-id"}, []string{"reset", "Weather", "Watch"})
+		assert.Contains(t, text, "The cursor, cached token and failure tracking of backend **Weather Watch** were reset.")
+		assert.Equal(t, []backend.StateResetScope{backend.StateResetAll}, b.resets)
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		p, b := newPlugin(t)
+
+		assert.Equal(t, "Backend `Other` not found.", p.executeState(&model.CommandArgs{}, []string{"reset", "Other", "failures"}))
+		assert.Empty(t, b.resets)
+	})
+}
+
+func TestExecuteState_Usage(t *testing.T) {
+	p, _ := newPauseTestPlugin(t)
+
+	assert.Equal(t, stateUsage, p.executeState(&model.CommandArgs{}, nil))
+	assert.Equal(t, stateUsage, p.executeState(&model.CommandArgs{}, []string{"show"}))
+	assert.Equal(t, stateUsage, p.executeState(&model.CommandArgs{}, []string{"clear", "Weather"}))
+}
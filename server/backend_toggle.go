@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// executeEnable handles /dataminr enable <backend|all>
+func (p *Plugin) executeEnable(args *model.CommandArgs, params []string) string {
+	return p.executeSetEnabled(args, params, true)
+}
+
+// executeDisable handles /dataminr disable <backend|all>
+func (p *Plugin) executeDisable(args *model.CommandArgs, params []string) string {
+	return p.executeSetEnabled(args, params, false)
+}
+
+// executeSetEnabled sets the enabled flag of one backend, matched by ID or case-insensitive
+// name, or of all backends with "all", and persists the configuration like disableBackend.
+// The saved configuration triggers OnConfigurationChange, which starts or stops the backends.
+func (p *Plugin) executeSetEnabled(args *model.CommandArgs, params []string, enabled bool) string {
+	action := "disable"
+	if enabled {
+		action = "enable"
+	}
+	if len(params) == 0 {
+		return fmt.Sprintf("Please specify a backend or `all`, e.g. `/%s %s Weather Watch`.", commandTrigger, action)
+	}
+
+	name := strings.Join(params, " ")
+	all := strings.EqualFold(name, "all")
+
+	configClone := p.getConfiguration().Clone()
+
+	var matched, changed, changedIDs []string
+	for i := range configClone.Backends {
+		cfg := &configClone.Backends[i]
+		if !all && cfg.ID != name && !strings.EqualFold(cfg.Name, name) {
+			continue
+		}
+		matched = append(matched, cfg.Name)
+		if cfg.Enabled != enabled {
+			cfg.Enabled = enabled
+			changed = append(changed, cfg.Name)
+			changedIDs = append(changedIDs, cfg.ID)
+		}
+	}
+
+	switch {
+	case len(matched) == 0 && all:
+		return "No backends are configured."
+	case len(matched) == 0:
+		return fmt.Sprintf("Backend `%s` not found.", name)
+	case len(changed) == 0 && all:
+		return fmt.Sprintf("All backends are already %sd.", action)
+	case len(changed) == 0:
+		return fmt.Sprintf("Backend **%s** is already %sd.", matched[0], action)
+	}
+
+	if err := p.savePluginConfig(configClone); err != nil {
+		p.API.LogError("Failed to save configuration", "action", action, "backends", strings.Join(changed, ", "), "userId", args.UserId, "error", err.Error())
+		return fmt.Sprintf("Failed to %s %s.", action, formatBackendNames(changed))
+	}
+
+	p.API.LogInfo("Backends "+action+"d with slash command", "backends", strings.Join(changed, ", "), "userId", args.UserId)
+	if enabled {
+		return fmt.Sprintf("Enabled %s.", formatBackendNames(changed))
+	}
+	for i, id := range changedIDs {
+		p.publishBackendDisabled(id, changed[i], "disabled with /"+commandTrigger+" disable")
+	}
+	return fmt.Sprintf("Disabled %s. Polling stops until re-enabled with `/%s enable`.", formatBackendNames(changed), commandTrigger)
+}
+
+// formatBackendNames describes a list of backends, e.g. "backends **A** and **B**"
+func formatBackendNames(names []string) string {
+	bold := make([]string, len(names))
+	for i, name := range names {
+		bold[i] = "**" + name + "**"
+	}
+
+	if len(bold) == 1 {
+		return "backend " + bold[0]
+	}
+	return "backends " + strings.Join(bold[:len(bold)-1], ", ") + " and " + bold[len(bold)-1]
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func newToggleTestPlugin(t *testing.T) (*Plugin, *plugintest.API) {
+	api := &plugintest.API{}
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	p := newCommandTestPlugin(api)
+	p.setConfiguration(&configuration{Backends: []backend.Config{
+		{ID: "backend-1", Name: "Weather Watch", Enabled: true},
+		{ID: "backend-2", Name: "Cyber Watch", Enabled: false},
+		{ID: "backend-3", Name: "Travel Watch", Enabled: false},
+	}})
+	return p, api
+}
+
+// savedEnabledFlags returns the enabled flag of each backend in a saved configuration, keyed by name
+func savedEnabledFlags(t *testing.T, saved map[string]any) map[string]bool {
+	require.NotNil(t, saved)
+	flags := make(map[string]bool)
+	for _, item := range saved["backends"].([]any) {
+		cfg := item.(map[string]any)
+		flags[cfg["name"].(string)] = cfg["enabled"] == true
+	}
+	return flags
+}
+
+func TestExecuteSetEnabled(t *testing.T) {
+	t.Run("enables a backend by name", func(t *testing.T) {
+		p, api := newToggleTestPlugin(t)
+		var saved map[string]any
+		api.On("SavePluginConfig", mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(0).(map[string]any)
+		}).Return(nil).Once()
+
+		text := p.executeEnable(&model.CommandArgs{UserId: "admin"}, []string{"cyber", "watch"})
+		assert.Equal(t, "Enabled backend **Cyber Watch**.", text)
+		assert.Equal(t, map[string]bool{"Weather Watch": true, "Cyber Watch": true, "Travel Watch": false}, savedEnabledFlags(t, saved))
+
+		// The active configuration is left to OnConfigurationChange
+		assert.False(t, p.getConfiguration().Backends[1].Enabled)
+	})
+
+	t.Run("disables all backends", func(t *testing.T) {
+		p, api := newToggleTestPlugin(t)
+		var saved map[string]any
+		api.On("SavePluginConfig", mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(0).(map[string]any)
+		}).Return(nil).Once()
+		api.On("PublishWebSocketEvent", wsEventBackendDisabled, map[string]any{
+			"backend_id":   "backend-1",
+			"backend_name": "Weather Watch",
+			"reason":       "disabled with /dataminr disable",
+		}, adminBroadcast()).Once()
+
+		text := p.executeDisable(&model.CommandArgs{UserId: "admin"}, []string{"all"})
+		assert.Equal(t, "Disabled backend **Weather Watch**. Polling stops until re-enabled with `/dataminr enable`.", text)
+		assert.Equal(t, map[string]bool{"Weather Watch": false, "Cyber Watch": false, "Travel Watch": false}, savedEnabledFlags(t, saved))
+		api.AssertExpectations(t)
+	})
+
+	t.Run("enables all backends", func(t *testing.T) {
+		p, api := newToggleTestPlugin(t)
+		api.On("SavePluginConfig", mock.Anything).Return(nil).Once()
+
+		text := p.executeEnable(&model.CommandArgs{UserId: "admin"}, []string{"ALL"})
+		assert.Equal(t, "Enabled backends **Cyber Watch** and **Travel Watch**.", text)
+	})
+
+	t.Run("nothing to change", func(t *testing.T) {
+		p, api := newToggleTestPlugin(t)
+
+		assert.Equal(t, "Backend **Weather Watch** is already enabled.", p.executeEnable(&model.CommandArgs{}, []string{"backend-1"}))
+		assert.Equal(t, "Backend `Unknown` not found.", p.executeDisable(&model.CommandArgs{}, []string{"Unknown"}))
+		assert.Contains(t, p.executeDisable(&model.CommandArgs{}, nil), "Please specify a backend or `all`")
+
+		p.setConfiguration(&configuration{Backends: []backend.Config{{ID: "backend-1", Name: "Weather Watch"}}})
+		assert.Equal(t, "All backends are already disabled.", p.executeDisable(&model.CommandArgs{}, []string{"all"}))
+
+		p.setConfiguration(&configuration{})
+		assert.Equal(t, "No backends are configured.", p.executeEnable(&model.CommandArgs{}, []string{"all"}))
+
+		api.AssertNotCalled(t, "SavePluginConfig", mock.Anything)
+	})
+
+	t.Run("save failure", func(t *testing.T) {
+		p, api := newToggleTestPlugin(t)
+		api.On("SavePluginConfig", mock.Anything).Return(model.NewAppError("SavePluginConfig", "save_failed", nil, "", 500)).Once()
+		api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+
+		text := p.executeDisable(&model.CommandArgs{UserId: "admin"}, []string{"Weather Watch"})
+		assert.Equal(t, "Failed to disable backend **Weather Watch**.", text)
+	})
+}
+
+func TestFormatBackendNames(t *testing.T) {
+	assert.Equal(t, "backend **A**", formatBackendNames([]string{"A"}))
+	assert.Equal(t, "backends **A** and **B**", formatBackendNames([]string{"A", "B"}))
+	assert.Equal(t, "backends **A**, **B** and **C**", formatBackendNames([]string{"A", "B", "C"}))
+}
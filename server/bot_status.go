@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// botStatusInterval is how often the bot's custom status is refreshed
+	botStatusInterval = time.Minute
+
+	// botStatusJobID is the cluster job ID for refreshing the bot's custom status
+	botStatusJobID = "dataminr_bot_status"
+)
+
+// aggregateBotStatus summarizes the states of all backends into the bot's custom status:
+// disabled backends take precedence over failing ones. Returns nil when there are no backends.
+func aggregateBotStatus(statuses []backend.Status) *model.CustomStatus {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	failing, disabled := 0, 0
+	for _, status := range statuses {
+		switch classifyStatus(status) {
+		case stateFailing:
+			failing++
+		case stateDisabled:
+			disabled++
+		}
+	}
+
+	switch {
+	case disabled > 0:
+		return &model.CustomStatus{Emoji: "no_entry", Text: fmt.Sprintf("%d of %d backends disabled", disabled, len(statuses))}
+	case failing > 0:
+		return &model.CustomStatus{Emoji: "warning", Text: fmt.Sprintf("%d of %d backends failing", failing, len(statuses))}
+	default:
+		return &model.CustomStatus{Emoji: "white_check_mark", Text: "All backends healthy"}
+	}
+}
+
+// BotStatus keeps the bot's custom status in line with the overall health of the backends,
+// so it is visible at a glance from any channel the bot posts in. The status is only updated
+// when it changes, and is cleared when there are no backends or the watcher stops.
+type BotStatus struct {
+	api      plugin.API
+	botID    string
+	registry *backend.Registry
+	job      *cluster.Job
+
+	mu sync.Mutex
+	// applied is the custom status last set on the bot (nil when cleared)
+	applied *model.CustomStatus
+	// known is false until the bot's custom status was first set or cleared
+	known bool
+}
+
+// NewBotStatus creates a watcher for the bot's custom status
+func NewBotStatus(api plugin.API, botID string, registry *backend.Registry) *BotStatus {
+	return &BotStatus{
+		api:      api,
+		botID:    botID,
+		registry: registry,
+	}
+}
+
+// Start schedules the periodic cluster-aware refresh of the bot's custom status
+func (s *BotStatus) Start() error {
+	job, err := cluster.Schedule(s.api, botStatusJobID, cluster.MakeWaitForInterval(botStatusInterval), s.refresh)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule bot status job")
+	}
+
+	s.job = job
+	return nil
+}
+
+// Stop cancels the refresh and clears the bot's custom status, which would go stale
+func (s *BotStatus) Stop() {
+	if s.job == nil {
+		return
+	}
+
+	if err := s.job.Close(); err != nil {
+		s.api.LogWarn("Failed to close bot status job", "error", err.Error())
+	}
+	s.job = nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apply(nil)
+}
+
+// refresh sets the bot's custom status from the current backend states
+func (s *BotStatus) refresh() {
+	var statuses []backend.Status
+	for _, b := range s.registry.List() {
+		statuses = append(statuses, b.GetStatus())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apply(aggregateBotStatus(statuses))
+}
+
+// apply sets or clears the bot's custom status unless it is unchanged
+func (s *BotStatus) apply(status *model.CustomStatus) {
+	if s.known && sameCustomStatus(s.applied, status) {
+		return
+	}
+
+	if status == nil {
+		if appErr := s.api.RemoveUserCustomStatus(s.botID); appErr != nil {
+			s.api.LogWarn("Failed to clear bot custom status", "error", appErr.Error())
+			return
+		}
+	} else if appErr := s.api.UpdateUserCustomStatus(s.botID, status); appErr != nil {
+		s.api.LogWarn("Failed to update bot custom status", "error", appErr.Error())
+		return
+	}
+
+	s.applied = status
+	s.known = true
+}
+
+// sameCustomStatus reports whether two custom statuses show the same emoji and text
+func sameCustomStatus(a, b *model.CustomStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Emoji == b.Emoji && a.Text == b.Text
+}
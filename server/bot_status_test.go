@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestAggregateBotStatus(t *testing.T) {
+	healthy := backend.Status{Enabled: true}
+	failing := backend.Status{Enabled: true, ConsecutiveFailures: 2}
+	disabled := backend.Status{Enabled: false}
+
+	tests := []struct {
+		name     string
+		statuses []backend.Status
+		expected *model.CustomStatus
+	}{
+		{"no backends", nil, nil},
+		{"all healthy", []backend.Status{healthy, healthy}, &model.CustomStatus{Emoji: "white_check_mark", Text: "All backends healthy"}},
+		{"some failing", []backend.Status{healthy, failing, failing}, &model.CustomStatus{Emoji: "warning", Text: "2 of 3 backends failing"}},
+		{"disabled takes precedence", []backend.Status{failing, disabled}, &model.CustomStatus{Emoji: "no_entry", Text: "1 of 2 backends disabled"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, aggregateBotStatus(tt.statuses))
+		})
+	}
+}
+
+func TestBotStatus_refresh(t *testing.T) {
+	t.Run("updates the custom status only when it changes", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		healthy := &model.CustomStatus{Emoji: "white_check_mark", Text: "All backends healthy"}
+		failing := &model.CustomStatus{Emoji: "warning", Text: "1 of 1 backends failing"}
+		api.On("UpdateUserCustomStatus", "bot-id", healthy).Return(nil).Once()
+		api.On("UpdateUserCustomStatus", "bot-id", failing).Return(nil).Once()
+
+		registry := backend.NewRegistry()
+		b := &fakeBackend{id: "b1", name: "Prod", status: backend.Status{Enabled: true}}
+		require.NoError(t, registry.Register(b))
+
+		status := NewBotStatus(api, "bot-id", registry)
+		status.refresh()
+		status.refresh()
+
+		b.status.ConsecutiveFailures = 3
+		status.refresh()
+		status.refresh()
+	})
+
+	t.Run("clears the custom status without backends", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("RemoveUserCustomStatus", "bot-id").Return(nil).Once()
+
+		status := NewBotStatus(api, "bot-id", backend.NewRegistry())
+		status.refresh()
+		status.refresh()
+	})
+
+	t.Run("retries after a failed update", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("UpdateUserCustomStatus", "bot-id", mock.Anything).Return(model.NewAppError("UpdateUserCustomStatus", "app.error", nil, "", 500)).Once()
+		api.On("LogWarn", "Failed to update bot custom status", "error", mock.Anything).Once()
+		api.On("UpdateUserCustomStatus", "bot-id", mock.Anything).Return(nil).Once()
+
+		registry := backend.NewRegistry()
+		require.NoError(t, registry.Register(&fakeBackend{id: "b1", name: "Prod", status: backend.Status{Enabled: true}}))
+
+		status := NewBotStatus(api, "bot-id", registry)
+		status.refresh()
+		status.refresh()
+	})
+}
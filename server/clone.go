@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/secrets"
+)
+
+// cloneNameSuffix is appended to the name of a cloned backend
+const cloneNameSuffix = " (copy)"
+
+// errBackendNotFound is returned when no backend is configured with the requested ID or name
+var errBackendNotFound = errors.New("backend not found")
+
+// cloneBackend adds a disabled copy of a configured backend, matched by ID or case-insensitive
+// name, to the configuration and saves it. The copy gets a new ID and a unique name, and uses
+// the same API key and proxy password as the original: secrets in the secret store are stored
+// again for the copy.
+// Returns the copy as saved, to be edited before it is enabled.
+func (p *Plugin) cloneBackend(nameOrID string) (backend.Config, error) {
+	newConfig := p.getConfiguration().Clone()
+
+	var source backend.Config
+	found := false
+	for _, cfg := range newConfig.Backends {
+		if cfg.ID == nameOrID || strings.EqualFold(cfg.Name, nameOrID) {
+			source, found = cfg, true
+			break
+		}
+	}
+	if !found {
+		return backend.Config{}, errBackendNotFound
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return backend.Config{}, errors.Wrap(err, "failed to generate backend ID")
+	}
+
+	clone := source
+	clone.ID = id.String()
+	clone.Name = uniqueCloneName(source.Name, newConfig.Backends)
+	clone.Enabled = false
+
+	if err := p.copySecrets(newConfig, source, clone.ID); err != nil {
+		p.deleteSecrets(newConfig, clone.ID)
+		return backend.Config{}, err
+	}
+
+	newConfig.Backends = append(newConfig.Backends, clone)
+	if err := backend.ValidateBackendsWithOptions(newConfig.backends(), newConfig.validationOptions()); err != nil {
+		p.deleteSecrets(newConfig, clone.ID)
+		return backend.Config{}, errors.Wrap(err, "invalid backend copy")
+	}
+	if err := p.savePluginConfig(newConfig); err != nil {
+		p.deleteSecrets(newConfig, clone.ID)
+		return backend.Config{}, err
+	}
+
+	return clone, nil
+}
+
+// copySecrets stores the API key and proxy password a backend keeps in the secret store for
+// its copy
+func (p *Plugin) copySecrets(config *configuration, source backend.Config, cloneID string) error {
+	apiKeyStored := source.APIKeyStored && !secrets.IsEnvReference(source.APIKey)
+	proxyPasswordStored := source.ProxyPasswordStored && !secrets.IsEnvReference(source.ProxyPassword)
+	if !apiKeyStored && !proxyPasswordStored {
+		return nil
+	}
+
+	store, err := secrets.NewStore(p.API, config.EncryptionKey)
+	if err != nil {
+		return err
+	}
+
+	if apiKeyStored {
+		apiKey, err := p.resolveAPIKey(source)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the API key of the backend")
+		}
+		if err := store.SaveAPIKey(cloneID, apiKey); err != nil {
+			return errors.Wrap(err, "failed to store the API key of the copy")
+		}
+	}
+
+	if proxyPasswordStored {
+		password, err := p.resolveProxyPassword(source)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the proxy password of the backend")
+		}
+		if err := store.SaveProxyPassword(cloneID, password); err != nil {
+			return errors.Wrap(err, "failed to store the proxy password of the copy")
+		}
+	}
+	return nil
+}
+
+// uniqueCloneName returns the name of a copy of a backend that no configured backend uses,
+// e.g. "Weather (copy)" or "Weather (copy 2)"
+func uniqueCloneName(name string, configs []backend.Config) string {
+	taken := func(candidate string) bool {
+		for _, cfg := range configs {
+			if strings.EqualFold(cfg.Name, candidate) {
+				return true
+			}
+		}
+		return false
+	}
+
+	candidate := name + cloneNameSuffix
+	for i := 2; taken(candidate); i++ {
+		candidate = fmt.Sprintf("%s (copy %d)", name, i)
+	}
+	return candidate
+}
+
+// postCloneBackend handles POST /api/v1/backends/{id}/clone, returning the disabled copy with
+// its secrets masked
+func (p *Plugin) postCloneBackend(w http.ResponseWriter, r *http.Request) {
+	clone, err := p.cloneBackend(mux.Vars(r)["id"])
+	if errors.Is(err, errBackendNotFound) {
+		http.Error(w, "Backend not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		p.API.LogError("Failed to clone backend", "id", mux.Vars(r)["id"], "error", err.Error())
+		http.Error(w, "Failed to clone backend: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	p.API.LogInfo("Cloned backend", "id", mux.Vars(r)["id"], "cloneId", clone.ID, "userId", r.Header.Get("Mattermost-User-ID"))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(maskSecrets(clone)); err != nil {
+		p.API.LogError("Failed to encode cloned backend response", "error", err.Error())
+	}
+}
+
+// executeClone handles /dataminr clone <backend>
+func (p *Plugin) executeClone(args *model.CommandArgs, params []string) string {
+	if len(params) == 0 {
+		return fmt.Sprintf("Please specify a backend, e.g. `/%s clone Weather Watch`.", commandTrigger)
+	}
+
+	name := strings.Join(params, " ")
+	clone, err := p.cloneBackend(name)
+	if errors.Is(err, errBackendNotFound) {
+		return fmt.Sprintf("Backend `%s` not found.", name)
+	}
+	if err != nil {
+		p.API.LogError("Failed to clone backend", "backend", name, "userId", args.UserId, "error", err.Error())
+		return fmt.Sprintf("Failed to clone backend `%s`: %s", name, err.Error())
+	}
+
+	p.API.LogInfo("Cloned backend with slash command", "backend", name, "cloneId", clone.ID, "userId", args.UserId)
+	return fmt.Sprintf("Added the disabled backend **%s** (`%s`). Edit it in the plugin settings, then enable it with `/%s enable %s`.",
+		clone.Name, clone.ID, commandTrigger, clone.Name)
+}
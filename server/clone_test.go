@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestCloneBackend(t *testing.T) {
+	t.Run("adds a disabled copy with the stored API key", func(t *testing.T) {
+		p, api := newConfigTransferTestPlugin(t)
+		var saved map[string]any
+		api.On("SavePluginConfig", mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(0).(map[string]any)
+		}).Return(nil).Once()
+
+		clone, err := p.cloneBackend("stored")
+		require.NoError(t, err)
+
+		parsed, err := uuid.Parse(clone.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uuid.Version(4), parsed.Version())
+		assert.Equal(t, "Stored (copy)", clone.Name)
+		assert.False(t, clone.Enabled)
+		assert.Equal(t, "channel-1", clone.ChannelID)
+		assert.True(t, clone.APIKeyStored)
+
+		apiKey, err := p.resolveAPIKey(clone)
+		require.NoError(t, err)
+		assert.Equal(t, "stored-key", apiKey)
+
+		backends := saved["backends"].([]any)
+		require.Len(t, backends, 3)
+		assert.Equal(t, "Stored (copy)", backends[2].(map[string]any)["name"])
+		assert.Empty(t, backends[2].(map[string]any)["apiKey"])
+	})
+
+	t.Run("keeps environment variable references", func(t *testing.T) {
+		p, api := newConfigTransferTestPlugin(t)
+		api.On("SavePluginConfig", mock.Anything).Return(nil).Once()
+
+		clone, err := p.cloneBackend("6ba7b810-9dad-41d1-80b4-00c04fd430c8")
+		require.NoError(t, err)
+		assert.Equal(t, "Environment (copy)", clone.Name)
+		assert.Equal(t, "env:DATAMINR_TEST_API_KEY", clone.APIKey)
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		p, api := newConfigTransferTestPlugin(t)
+
+		_, err := p.cloneBackend("Other")
+		require.ErrorIs(t, err, errBackendNotFound)
+		api.AssertNotCalled(t, "SavePluginConfig", mock.Anything)
+	})
+}
+
+func TestUniqueCloneName(t *testing.T) {
+	configs := []backend.Config{{Name: "Weather"}, {Name: "Weather (copy)"}, {Name: "weather (COPY 2)"}}
+	assert.Equal(t, "Weather (copy 3)", uniqueCloneName("Weather", configs))
+	assert.Equal(t, "Security (copy)", uniqueCloneName("Security", configs))
+}
+
+func TestPostCloneBackend(t *testing.T) {
+	p, api := newConfigTransferTestPlugin(t)
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("SavePluginConfig", mock.Anything).Return(nil).Once()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/backends/{id}/clone", p.postCloneBackend).Methods(http.MethodPost)
+	post := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, url, nil))
+		return w
+	}
+
+	w := post("/api/v1/backends/550e8400-e29b-41d4-a716-446655440000/clone")
+	require.Equal(t, http.StatusCreated, w.Code)
+	var clone backend.Config
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&clone))
+	assert.Equal(t, "Stored (copy)", clone.Name)
+	assert.False(t, clone.Enabled)
+	assert.NotContains(t, clone.ProxyURL, "pass", "secrets are masked")
+
+	assert.Equal(t, http.StatusNotFound, post("/api/v1/backends/unknown/clone").Code)
+}
+
+func TestExecuteClone(t *testing.T) {
+	p, api := newConfigTransferTestPlugin(t)
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("SavePluginConfig", mock.Anything).Return(nil).Once()
+
+	text := p.executeClone(&model.CommandArgs{UserId: "user-id"}, []string{"Stored"})
+	assert.Contains(t, text, "Added the disabled backend **Stored (copy)**")
+	assert.Contains(t, text, "`/dataminr enable Stored (copy)`")
+
+	assert.Equal(t, "Backend `Other` not found.", p.executeClone(&model.CommandArgs{}, []string{"Other"}))
+	assert.Contains(t, p.executeClone(&model.CommandArgs{}, nil), "Please specify a backend")
+}
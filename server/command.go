@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+)
+
+// commandTrigger is the slash command trigger word for the plugin
+const commandTrigger = "dataminr"
+
+// commandHandler handles a single /dataminr subcommand.
+// The parameters are the words following the subcommand name.
+type commandHandler struct {
+	// description is shown in the help output and autocomplete
+	description string
+
+	// hint describes the subcommand arguments for autocomplete
+	hint string
+
+	// access restricts who may run the subcommand (default: every user)
+	access accessLevel
+
+	// execute runs the subcommand and returns the ephemeral response text
+	execute func(args *model.CommandArgs, params []string) string
+}
+
+// commandHandlers returns all supported /dataminr subcommands keyed by name
+func (p *Plugin) commandHandlers() map[string]commandHandler {
+	return map[string]commandHandler{
+		"subscribe": {
+			description: "Post a backend's alerts to this channel too, optionally filtered by alert type or keyword",
+			hint:        "<backend name> [type:flash|urgent|alert] [keyword:<word>]",
+			execute:     p.executeSubscribe,
+		},
+		"subscribe-status": {
+			description: "Receive a direct message whenever a backend changes state",
+			access:      accessControl,
+			execute:     p.executeSubscribeStatus,
+		},
+		"clone": {
+			description: "Add a disabled copy of a backend under a new ID, to edit in the plugin settings",
+			hint:        "<backend name>",
+			access:      accessSystemAdmin,
+			execute:     p.executeClone,
+		},
+		"dedup": {
+			description: "Show the size, evictions and per-backend hit rates of the deduplication cache on this server",
+			access:      accessSystemAdmin,
+			execute:     p.executeDedup,
+		},
+		"disable": {
+			description: "Disable a backend, or all backends, in the plugin configuration",
+			hint:        "<backend name> | all",
+			access:      accessControl,
+			execute:     p.executeDisable,
+		},
+		"enable": {
+			description: "Enable a backend, or all backends, in the plugin configuration",
+			hint:        "<backend name> | all",
+			access:      accessControl,
+			execute:     p.executeEnable,
+		},
+		"logs": {
+			description: "Show a backend's most recent log lines captured on this server",
+			hint:        "<backend name>",
+			access:      accessControl,
+			execute:     p.executeLogs,
+		},
+		"mute": {
+			description: "Temporarily hide alerts with a topic or in an alert list, in this channel or for a backend, or list the mutes",
+			hint:        "topic <name> [duration, e.g. 4h, 2d] | list \"<alert list>\" [duration] [backend:<name>] | list",
+			execute:     p.executeMute,
+		},
+		"pause": {
+			description: "Temporarily stop polling a backend without changing its configuration",
+			hint:        "<backend name> [duration, e.g. 30m, 1h, 2d]",
+			access:      accessControl,
+			execute:     p.executePause,
+		},
+		"resume": {
+			description: "Resume polling a paused backend",
+			hint:        "<backend name>",
+			access:      accessControl,
+			execute:     p.executeResume,
+		},
+		"search": {
+			description: "Search recently posted alerts by keyword, with optional type:<alert type> and since:<duration> filters",
+			hint:        "<keywords> [type:flash|urgent|alert] [since:6h|2d]",
+			execute:     p.executeSearch,
+		},
+		"selftest": {
+			description: "Post a synthetic alert through a backend's formatting to this channel, or preview it, without calling the Dataminr API",
+			hint:        "<backend name> [preview]",
+			access:      accessControl,
+			execute:     p.executeSelfTest,
+		},
+		"state": {
+			description: "Show a backend's stored polling state, or reset its cursor, auth token or failure tracking",
+			hint:        "show <backend name> | reset <backend name> [cursor|auth|failures|all]",
+			access:      accessControl,
+			execute:     p.executeState,
+		},
+		"stats": {
+			description: "Show alert counts by type, top topics, top locations and busiest hours",
+			hint:        "[24h|7d]",
+			execute:     p.executeStats,
+		},
+		"subscriptions": {
+			description: "List the backends this channel is subscribed to",
+			execute:     p.executeSubscriptions,
+		},
+		"unmute": {
+			description: "Show alerts with a muted topic or alert list again",
+			hint:        "topic <name> | list \"<alert list>\" [backend:<name>]",
+			execute:     p.executeUnmute,
+		},
+		"unsubscribe": {
+			description: "Stop posting a backend's alerts to this channel",
+			hint:        "<backend name>",
+			execute:     p.executeUnsubscribe,
+		},
+		"unsubscribe-status": {
+			description: "Stop receiving backend state change messages",
+			access:      accessControl,
+			execute:     p.executeUnsubscribeStatus,
+		},
+	}
+}
+
+// registerCommands registers the /dataminr slash command with autocomplete for all subcommands
+func (p *Plugin) registerCommands() error {
+	handlers := p.commandHandlers()
+
+	autocomplete := model.NewAutocompleteData(commandTrigger, "[command]", "Manage Dataminr alerting")
+	for _, name := range sortedCommandNames(handlers) {
+		handler := handlers[name]
+		autocomplete.AddCommand(model.NewAutocompleteData(name, handler.hint, handler.description))
+	}
+
+	if err := p.API.RegisterCommand(&model.Command{
+		Trigger:          commandTrigger,
+		DisplayName:      "Dataminr",
+		Description:      "Manage Dataminr alerting",
+		AutoComplete:     true,
+		AutoCompleteDesc: "Available commands: " + strings.Join(sortedCommandNames(handlers), ", "),
+		AutoCompleteHint: "[command]",
+		AutocompleteData: autocomplete,
+	}); err != nil {
+		return errors.Wrap(err, "failed to register slash command")
+	}
+
+	return nil
+}
+
+// ExecuteCommand dispatches /dataminr subcommands.
+func (p *Plugin) ExecuteCommand(_ *plugin.Context, args *model.CommandArgs) (*model.CommandResponse, *model.AppError) {
+	fields := strings.Fields(args.Command)
+	if len(fields) == 0 || fields[0] != "/"+commandTrigger {
+		return respondEphemeral(fmt.Sprintf("Unknown command: %s", args.Command)), nil
+	}
+
+	handlers := p.commandHandlers()
+	if len(fields) < 2 || fields[1] == "help" {
+		return respondEphemeral(p.commandHelp(handlers)), nil
+	}
+
+	handler, exists := handlers[fields[1]]
+	if !exists {
+		return respondEphemeral(fmt.Sprintf("Unknown command `%s`.\n\n%s", fields[1], p.commandHelp(handlers))), nil
+	}
+
+	if !p.isAuthorized(args.UserId, handler.access) {
+		return respondEphemeral("You don't have permission to run this command. A system administrator can grant access in the plugin settings."), nil
+	}
+
+	return respondEphemeral(handler.execute(args, fields[2:])), nil
+}
+
+// commandHelp builds the help text listing all subcommands
+func (p *Plugin) commandHelp(handlers map[string]commandHandler) string {
+	var sb strings.Builder
+	sb.WriteString("###### Dataminr commands\n")
+	for _, name := range sortedCommandNames(handlers) {
+		handler := handlers[name]
+		usage := strings.TrimSpace(fmt.Sprintf("/%s %s %s", commandTrigger, name, handler.hint))
+		sb.WriteString(fmt.Sprintf("* `%s` - %s\n", usage, handler.description))
+	}
+	return sb.String()
+}
+
+// sortedCommandNames returns subcommand names in alphabetical order
+func sortedCommandNames(handlers map[string]commandHandler) []string {
+	names := make([]string, 0, len(handlers))
+	for name := range handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// respondEphemeral creates an ephemeral command response visible only to the caller
+func respondEphemeral(text string) *model.CommandResponse {
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func newCommandTestPlugin(api *plugintest.API) *Plugin {
+	p := &Plugin{}
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(api, &plugintest.Driver{})
+	p.subscriptions = backend.NewSubscriptionStore(api)
+	p.topicMutes = backend.NewTopicMuteStore(api)
+	p.alertListMutes = backend.NewAlertListMuteStore(api)
+	return p
+}
+
+func TestExecuteCommand_Help(t *testing.T) {
+	api := &plugintest.API{}
+	p := newCommandTestPlugin(api)
+
+	resp, appErr := p.ExecuteCommand(nil, &model.CommandArgs{Command: "/dataminr help", UserId: "user-id"})
+	require.Nil(t, appErr)
+	assert.Equal(t, model.CommandResponseTypeEphemeral, resp.ResponseType)
+	assert.Contains(t, resp.Text, "/dataminr subscribe-status")
+}
+
+func TestExecuteCommand_Unknown(t *testing.T) {
+	api := &plugintest.API{}
+	p := newCommandTestPlugin(api)
+
+	resp, appErr := p.ExecuteCommand(nil, &model.CommandArgs{Command: "/dataminr bogus", UserId: "user-id"})
+	require.Nil(t, appErr)
+	assert.Contains(t, resp.Text, "Unknown command `bogus`")
+}
+
+func TestExecuteCommand_RequiresAdmin(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	api.On("HasPermissionTo", "user-id", model.PermissionManageSystem).Return(false)
+
+	p := newCommandTestPlugin(api)
+
+	resp, appErr := p.ExecuteCommand(nil, &model.CommandArgs{Command: "/dataminr subscribe-status", UserId: "user-id"})
+	require.Nil(t, appErr)
+	assert.Contains(t, resp.Text, "You don't have permission to run this command")
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/secrets"
+)
+
+// maxConfigDocumentBytes caps the size of an imported configuration document
+const maxConfigDocumentBytes = 5 * 1024 * 1024
+
+// configDocument is the exported and imported form of the backends configuration
+type configDocument struct {
+	Defaults *backend.Defaults `json:"defaults,omitempty"`
+	Backends []backend.Config  `json:"backends"`
+}
+
+// configValidationResponse reports the validation results of an imported configuration document
+type configValidationResponse struct {
+	// Valid indicates the document can be imported
+	Valid bool `json:"valid"`
+
+	// Errors lists problems that don't belong to a single backend
+	Errors []string `json:"errors"`
+
+	// Backends lists the validation errors of each backend in document order
+	Backends []backend.BackendValidationResult `json:"backends"`
+}
+
+// exportBackends returns the backends configuration for export. Secrets are masked unless
+// includeSecrets is set, in which case API keys and proxy passwords held in the secret store are
+// included in plaintext.
+// Environment variable references are exported as is since they hold no secret.
+func (p *Plugin) exportBackends(includeSecrets bool) (configDocument, error) {
+	config := p.getConfiguration()
+	configs := config.Backends
+	document := configDocument{Defaults: config.Defaults, Backends: make([]backend.Config, 0, len(configs))}
+
+	for _, cfg := range configs {
+		if !includeSecrets {
+			document.Backends = append(document.Backends, maskSecrets(cfg))
+			continue
+		}
+
+		if cfg.APIKeyStored && !secrets.IsEnvReference(cfg.APIKey) {
+			apiKey, err := p.resolveAPIKey(cfg)
+			if err != nil {
+				return configDocument{}, errors.Wrapf(err, "failed to read the API key of backend '%s'", cfg.Name)
+			}
+			cfg.APIKey = apiKey
+			cfg.APIKeyStored = false
+		}
+		if cfg.ProxyPasswordStored && !secrets.IsEnvReference(cfg.ProxyPassword) {
+			password, err := p.resolveProxyPassword(cfg)
+			if err != nil {
+				return configDocument{}, errors.Wrapf(err, "failed to read the proxy password of backend '%s'", cfg.Name)
+			}
+			cfg.ProxyPassword = password
+			cfg.ProxyPasswordStored = false
+		}
+		document.Backends = append(document.Backends, cfg)
+	}
+
+	return document, nil
+}
+
+// maskSecrets redacts the secrets of a backend configuration, keeping environment variable
+// references since they hold no secret
+func maskSecrets(cfg backend.Config) backend.Config {
+	redacted := redactConfig(cfg)
+	if secrets.IsEnvReference(cfg.APIKey) {
+		redacted.APIKey = cfg.APIKey
+	}
+	if secrets.IsEnvReference(cfg.ProxyPassword) {
+		redacted.ProxyPassword = cfg.ProxyPassword
+	}
+	return redacted
+}
+
+// validateConfigDocument parses and validates an imported configuration document.
+// Backends without an API key must already keep their key in this server's secret store.
+// Returns the parsed document, with the backends as given rather than with the defaults applied.
+func (p *Plugin) validateConfigDocument(data []byte) (configDocument, configValidationResponse, error) {
+	var raw struct {
+		Defaults *backend.Defaults `json:"defaults"`
+		Backends json.RawMessage   `json:"backends"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return configDocument{}, configValidationResponse{}, errors.Wrap(err, "invalid configuration document")
+	}
+	if len(raw.Backends) == 0 {
+		return configDocument{}, configValidationResponse{}, errors.New("configuration document has no 'backends' field")
+	}
+
+	configs, results, err := backend.ValidateBackendsJSON(raw.Backends, raw.Defaults, p.getConfiguration().validationOptions())
+	if err != nil {
+		return configDocument{}, configValidationResponse{}, err
+	}
+
+	current := p.getConfiguration().Backends
+	for i, cfg := range configs {
+		existing, found := findBackendConfigByID(current, cfg.ID)
+		if cfg.APIKey == "" && cfg.APIKeyStored && (!found || !existing.APIKeyStored) {
+			results[i].Errors = append(results[i].Errors, "API key is not included in the document and not stored on this server; set 'apiKey'")
+		}
+		if cfg.ProxyPassword == "" && cfg.ProxyPasswordStored && (!found || !existing.ProxyPasswordStored) {
+			results[i].Errors = append(results[i].Errors, "proxy password is not included in the document and not stored on this server; set 'proxyPassword'")
+		}
+	}
+
+	// Check alert lists and topic styles against the taxonomy cached for existing backends,
+	// and the mentioned users and groups
+	for i, cfg := range raw.Defaults.Apply(configs) {
+		taxonomy, err := loadTaxonomy(p.API, cfg.ID)
+		if err != nil {
+			p.API.LogWarn("Failed to load backend taxonomy", "backendId", cfg.ID, "error", err.Error())
+		} else if taxonomy != nil {
+			results[i].Warnings = taxonomy.Check(cfg)
+		}
+		results[i].Warnings = append(results[i].Warnings, p.checkMentionTargets(cfg)...)
+	}
+
+	response := configValidationResponse{Valid: true, Errors: []string{}, Backends: results}
+	if raw.Defaults != nil {
+		if err := raw.Defaults.Validate(); err != nil {
+			response.Valid = false
+			response.Errors = append(response.Errors, err.Error())
+		}
+	}
+	for _, result := range results {
+		if len(result.Errors) > 0 {
+			response.Valid = false
+		}
+	}
+
+	return configDocument{Defaults: raw.Defaults, Backends: configs}, response, nil
+}
+
+// exportConfig handles GET /api/v1/config/export.
+// Secrets are masked unless the includeSecrets query parameter is true.
+func (p *Plugin) exportConfig(w http.ResponseWriter, r *http.Request) {
+	includeSecrets := r.URL.Query().Get("includeSecrets") == "true"
+
+	document, err := p.exportBackends(includeSecrets)
+	if err != nil {
+		p.API.LogError("Failed to export configuration", "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if includeSecrets {
+		p.API.LogInfo("Exported backend configuration with secrets", "userId", r.Header.Get("Mattermost-User-ID"))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="dataminr-backends.json"`)
+	if err := json.NewEncoder(w).Encode(document); err != nil {
+		p.API.LogError("Failed to encode configuration export", "error", err.Error())
+	}
+}
+
+// validateConfig handles POST /api/v1/config/validate, reporting the validation results of a
+// configuration document without importing it
+func (p *Plugin) validateConfig(w http.ResponseWriter, r *http.Request) {
+	_, response, ok := p.readConfigDocument(w, r)
+	if !ok {
+		return
+	}
+
+	writeConfigValidation(w, http.StatusOK, response)
+}
+
+// getConfigSchema handles GET /api/v1/schema, returning the JSON schema of a backend
+// configuration so clients can validate backends before saving them
+func (p *Plugin) getConfigSchema(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	if err := json.NewEncoder(w).Encode(backend.ConfigSchema()); err != nil {
+		p.API.LogError("Failed to encode configuration schema", "error", err.Error())
+	}
+}
+
+// importConfig handles POST /api/v1/config/import, replacing the backends configuration
+// with a valid configuration document. Invalid documents are rejected with the validation results.
+func (p *Plugin) importConfig(w http.ResponseWriter, r *http.Request) {
+	document, response, ok := p.readConfigDocument(w, r)
+	if !ok {
+		return
+	}
+
+	if !response.Valid {
+		writeConfigValidation(w, http.StatusBadRequest, response)
+		return
+	}
+
+	newConfig := p.getConfiguration().Clone()
+	newConfig.Defaults = document.Defaults
+	newConfig.Backends = document.Backends
+	if err := p.savePluginConfig(newConfig); err != nil {
+		p.API.LogError("Failed to import configuration", "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	p.API.LogInfo("Imported backend configuration", "userId", r.Header.Get("Mattermost-User-ID"), "backends", len(document.Backends))
+	writeConfigValidation(w, http.StatusOK, response)
+}
+
+// readConfigDocument reads and validates the configuration document in the request body.
+// Writes an error response and returns false if the document can't be parsed.
+func (p *Plugin) readConfigDocument(w http.ResponseWriter, r *http.Request) (configDocument, configValidationResponse, bool) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxConfigDocumentBytes+1))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return configDocument{}, configValidationResponse{}, false
+	}
+	if len(data) > maxConfigDocumentBytes {
+		http.Error(w, "Configuration document is too large", http.StatusRequestEntityTooLarge)
+		return configDocument{}, configValidationResponse{}, false
+	}
+
+	configs, response, err := p.validateConfigDocument(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return configDocument{}, configValidationResponse{}, false
+	}
+
+	return configs, response, true
+}
+
+// writeConfigValidation writes configuration validation results as JSON
+func writeConfigValidation(w http.ResponseWriter, status int, response configValidationResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
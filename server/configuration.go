@@ -1,12 +1,19 @@
 package main
 
 import (
+	"context"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/plugin"
 	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/formatter"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/hashtag"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/triage"
 )
 
 // configuration captures the plugin's external configuration as exposed in the Mattermost server
@@ -27,8 +34,71 @@ type configuration struct {
 	// BotDisplayName is the display name for the alert notification bot.
 	BotDisplayName string `json:"botDisplayName"`
 
-	// Backends is an array of backend configurations.
-	// Each backend defines a separate alert source to poll and monitor.
+	// RateLimitMaxAlerts is the maximum number of alerts posted to a single channel within
+	// RateLimitWindowMinutes before further alerts are collapsed into a thread (0 disables)
+	RateLimitMaxAlerts int `json:"rateLimitMaxAlerts"`
+
+	// RateLimitWindowMinutes is the length of the per-channel rate limit window
+	RateLimitWindowMinutes int `json:"rateLimitWindowMinutes"`
+
+	// MapProvider selects the interactive map link added to located alerts
+	// ("openstreetmap", "google", or empty to disable)
+	MapProvider string `json:"mapProvider"`
+
+	// StaticMapURLTemplate is a static map image URL template with {lat}, {lon} and {zoom}
+	// placeholders, embedded in located alerts (empty disables)
+	StaticMapURLTemplate string `json:"staticMapUrlTemplate"`
+
+	// DailySummaryEnabled posts a daily alert statistics summary to each backend channel
+	DailySummaryEnabled bool `json:"dailySummaryEnabled"`
+
+	// DailySummaryHourUTC is the hour of the day (0-23, UTC) the daily summary is posted
+	DailySummaryHourUTC int `json:"dailySummaryHourUtc"`
+
+	// TriageReactionsEnabled records the triage state of alert posts when users react to them
+	// with the triage emojis
+	TriageReactionsEnabled bool `json:"triageReactionsEnabled"`
+
+	// TriageInvestigatingEmoji, TriageResolvedEmoji and TriageFalsePositiveEmoji are the names of
+	// the reaction emojis setting each triage state (empty uses the defaults)
+	TriageInvestigatingEmoji string `json:"triageInvestigatingEmoji"`
+	TriageResolvedEmoji      string `json:"triageResolvedEmoji"`
+	TriageFalsePositiveEmoji string `json:"triageFalsePositiveEmoji"`
+
+	// TriageUpdatePosts shows the triage state in the color and footer of triaged alert posts
+	TriageUpdatePosts bool `json:"triageUpdatePosts"`
+
+	// ControlRoles and ControlUsers grant the roles and user IDs listed (comma-separated) the
+	// backend control commands and REST endpoints, in addition to system admins
+	ControlRoles string `json:"controlRoles"`
+	ControlUsers string `json:"controlUsers"`
+
+	// GlobalPause pauses polling of every backend without unregistering them or clearing their
+	// state, e.g. during Mattermost maintenance. Turning it off resumes the paused backends.
+	GlobalPause bool `json:"globalPause"`
+
+	// AdminChannelID is the channel warned when a backend is about to reach its failure
+	// threshold (empty sends no warnings)
+	AdminChannelID string `json:"adminChannelId"`
+
+	// StorageDriver selects where the alert index, audit log and poll history are stored: "kv"
+	// (the default when empty) for the plugin KV store, or "sql" for tables of the Mattermost
+	// database. Read on activation.
+	StorageDriver string `json:"storageDriver"`
+
+	// AllowInsecureBackendURLs accepts plain HTTP backend URLs. This is a developer flag for
+	// pointing test servers at a mock API; it exposes credentials and alerts on the network.
+	AllowInsecureBackendURLs bool `json:"allowInsecureBackendURLs"`
+
+	// EncryptionKey is the generated key used to encrypt backend API keys in the KV store
+	EncryptionKey string `json:"encryptionKey"`
+
+	// Defaults are backend settings inherited by backends that don't set them.
+	Defaults *backend.Defaults `json:"defaults"`
+
+	// Backends is an array of backend configurations as saved, without the inherited defaults.
+	// Each backend defines a separate alert source to poll and monitor. Use backends() for
+	// the effective configurations.
 	Backends []backend.Config `json:"backends"`
 }
 
@@ -46,6 +116,11 @@ func (c *configuration) Clone() *configuration {
 	return &clone
 }
 
+// backends returns the effective backend configurations, with unset settings inherited from the defaults
+func (c *configuration) backends() []backend.Config {
+	return c.Defaults.Apply(c.Backends)
+}
+
 // getConfiguration retrieves the active configuration under lock, making it safe to use
 // concurrently. The active configuration may change underneath the client of this method, but
 // the struct returned by this API call is considered immutable.
@@ -87,6 +162,205 @@ func (p *Plugin) setConfiguration(configuration *configuration) {
 	p.configuration = configuration
 }
 
+// rateLimit returns the per-channel alert rate limit derived from the configuration
+func (c *configuration) rateLimit() poster.RateLimit {
+	return poster.RateLimit{
+		MaxAlerts: c.RateLimitMaxAlerts,
+		Window:    time.Duration(c.RateLimitWindowMinutes) * time.Minute,
+	}
+}
+
+// validationOptions returns the backend validation options derived from the configuration
+func (c *configuration) validationOptions() backend.ValidationOptions {
+	return backend.ValidationOptions{
+		AllowInsecureURLs: c.AllowInsecureBackendURLs,
+	}
+}
+
+// formatOptions returns the alert formatting options derived from the configuration
+func (c *configuration) formatOptions() formatter.Options {
+	return formatter.Options{
+		Map: formatter.NewMapLinkBuilder(c.MapProvider, c.StaticMapURLTemplate),
+	}
+}
+
+// botIdentities returns the bot identity overrides for backends that configure one, keyed by backend ID
+func (c *configuration) botIdentities() map[string]backend.BotIdentity {
+	identities := make(map[string]backend.BotIdentity)
+	for _, cfg := range c.backends() {
+		if cfg.BotIdentity != nil {
+			identities[cfg.ID] = *cfg.BotIdentity
+		}
+	}
+	return identities
+}
+
+// mentionRules returns the mention rules for backends that configure them, keyed by backend ID
+func (c *configuration) mentionRules() map[string]backend.MentionRules {
+	rules := make(map[string]backend.MentionRules)
+	for _, cfg := range c.backends() {
+		if cfg.Mentions != nil {
+			rules[cfg.ID] = *cfg.Mentions
+		}
+	}
+	return rules
+}
+
+// contentLimits returns the content limits for backends that configure them, keyed by backend ID
+func (c *configuration) contentLimits() map[string]backend.ContentLimits {
+	limits := make(map[string]backend.ContentLimits)
+	for _, cfg := range c.backends() {
+		if cfg.ContentLimits != nil {
+			limits[cfg.ID] = *cfg.ContentLimits
+		}
+	}
+	return limits
+}
+
+// mediaUploads returns the media upload settings for backends that enable them, keyed by backend ID
+func (c *configuration) mediaUploads() map[string]backend.MediaUploadSettings {
+	settings := make(map[string]backend.MediaUploadSettings)
+	for _, cfg := range c.backends() {
+		if cfg.MediaUpload != nil {
+			settings[cfg.ID] = *cfg.MediaUpload
+		}
+	}
+	return settings
+}
+
+// mediaBundles returns the media bundle settings for backends that enable them, keyed by backend ID
+func (c *configuration) mediaBundles() map[string]backend.MediaBundleSettings {
+	settings := make(map[string]backend.MediaBundleSettings)
+	for _, cfg := range c.backends() {
+		if cfg.MediaBundle != nil {
+			settings[cfg.ID] = *cfg.MediaBundle
+		}
+	}
+	return settings
+}
+
+// timeDisplays returns the event time display settings for backends that configure them, keyed by backend ID
+func (c *configuration) timeDisplays() map[string]backend.TimeDisplaySettings {
+	settings := make(map[string]backend.TimeDisplaySettings)
+	for _, cfg := range c.backends() {
+		if cfg.TimeDisplay != nil {
+			settings[cfg.ID] = *cfg.TimeDisplay
+		}
+	}
+	return settings
+}
+
+// topicStyles returns the topic styles for backends that configure them, keyed by backend ID
+func (c *configuration) topicStyles() map[string][]backend.TopicStyle {
+	styles := make(map[string][]backend.TopicStyle)
+	for _, cfg := range c.backends() {
+		if len(cfg.TopicStyles) > 0 {
+			styles[cfg.ID] = cfg.TopicStyles
+		}
+	}
+	return styles
+}
+
+// ackSLAs returns the acknowledgment SLA settings for backends that configure them, keyed by backend ID
+func (c *configuration) ackSLAs() map[string]backend.AckSLASettings {
+	settings := make(map[string]backend.AckSLASettings)
+	for _, cfg := range c.backends() {
+		if cfg.AckSLA != nil {
+			settings[cfg.ID] = *cfg.AckSLA
+		}
+	}
+	return settings
+}
+
+// incidentSettings returns the incident grouping settings for backends that configure them, keyed by backend ID
+func (c *configuration) incidentSettings() map[string]backend.IncidentSettings {
+	settings := make(map[string]backend.IncidentSettings)
+	for _, cfg := range c.backends() {
+		if cfg.Incidents != nil {
+			settings[cfg.ID] = *cfg.Incidents
+		}
+	}
+	return settings
+}
+
+// workflows returns the workflow settings for backends that configure them, keyed by backend ID
+func (c *configuration) workflows() map[string]backend.WorkflowSettings {
+	settings := make(map[string]backend.WorkflowSettings)
+	for _, cfg := range c.backends() {
+		if cfg.Workflow != nil {
+			settings[cfg.ID] = *cfg.Workflow
+		}
+	}
+	return settings
+}
+
+// triageReactions returns the triage state set by each triage reaction emoji, keyed by emoji name
+func (c *configuration) triageReactions() map[string]triage.State {
+	emojiName := func(name, defaultName string) string {
+		name = strings.Trim(strings.TrimSpace(name), ":")
+		if name == "" {
+			return defaultName
+		}
+		return name
+	}
+
+	return map[string]triage.State{
+		emojiName(c.TriageInvestigatingEmoji, defaultTriageInvestigatingEmoji): triage.StateInvestigating,
+		emojiName(c.TriageResolvedEmoji, defaultTriageResolvedEmoji):           triage.StateResolved,
+		emojiName(c.TriageFalsePositiveEmoji, defaultTriageFalsePositiveEmoji): triage.StateFalsePositive,
+	}
+}
+
+// validateTriageReactions checks that each triage state has its own reaction emoji
+func (c *configuration) validateTriageReactions() error {
+	if len(c.triageReactions()) != 3 {
+		return errors.New("triage reaction emojis must be different for each triage state")
+	}
+	return nil
+}
+
+// locales returns the resolved alert post locale for each backend keyed by backend ID.
+// Backends without a locale use the server's default locale when it is supported.
+func (c *configuration) locales(serverLocale string) map[string]string {
+	locales := make(map[string]string, len(c.Backends))
+	for _, cfg := range c.backends() {
+		locales[cfg.ID] = formatter.ResolveLocale(cfg.Locale, serverLocale)
+	}
+	return locales
+}
+
+// hashtagOptions returns the hashtag generation options for each backend keyed by backend ID
+func (c *configuration) hashtagOptions() map[string]hashtag.Options {
+	options := make(map[string]hashtag.Options, len(c.Backends))
+	for _, cfg := range c.backends() {
+		opts := hashtag.Options{
+			Locale: cfg.HashtagLocale,
+		}
+		if cfg.Hashtags != nil {
+			opts.SkipAlertLevel = cfg.Hashtags.DisableAlertLevel
+			opts.SkipLocation = cfg.Hashtags.DisableLocation
+			opts.SkipTopics = cfg.Hashtags.DisableTopics
+			opts.MaxHashtags = cfg.Hashtags.MaxHashtags
+			opts.CustomHashtags = cfg.Hashtags.CustomHashtags
+		}
+		options[cfg.ID] = opts
+	}
+	return options
+}
+
+// hashtagPlacements returns where hashtags are shown for each backend keyed by backend ID
+func (c *configuration) hashtagPlacements() map[string]string {
+	placements := make(map[string]string, len(c.Backends))
+	for _, cfg := range c.backends() {
+		var settings backend.HashtagSettings
+		if cfg.Hashtags != nil {
+			settings = *cfg.Hashtags
+		}
+		placements[cfg.ID] = settings.HashtagPlacement()
+	}
+	return placements
+}
+
 // findBackendConfigByID finds a backend configuration by ID in a slice of configs.
 // Returns the config and true if found, or an empty config and false if not found.
 func findBackendConfigByID(configs []backend.Config, id string) (backend.Config, bool) {
@@ -98,15 +372,50 @@ func findBackendConfigByID(configs []backend.Config, id string) (backend.Config,
 	return backend.Config{}, false
 }
 
+// backendStopTimeout bounds how long stopping a backend may take: the drain timeout of its
+// in-flight poll cycle, plus time to cancel the cycle once the drain timeout passed
+const backendStopTimeout = backend.ShutdownDrainTimeout + 5*time.Second
+
 // unregisterBackend unregisters a backend from the registry and logs the result.
 func unregisterBackend(registry *backend.Registry, api plugin.API, id string, reason string) {
-	if err := registry.Unregister(id); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), backendStopTimeout)
+	defer cancel()
+
+	if err := registry.Unregister(ctx, id); err != nil {
 		api.LogWarn("Failed to unregister backend", "id", id, "reason", reason, "error", err.Error())
 	} else {
 		api.LogInfo("Unregistered backend", "id", id, "reason", reason)
 	}
 }
 
+// updateBackendInPlace applies a changed backend configuration to the registered backend
+// without restarting it, keeping its cursor and authentication token. Returns false if the
+// change requires a restart or the backend couldn't be updated.
+func (p *Plugin) updateBackendInPlace(oldConfigs []backend.Config, cfg backend.Config) bool {
+	oldCfg, found := findBackendConfigByID(oldConfigs, cfg.ID)
+	if !found || !backend.UpdatableInPlace(oldCfg, cfg) {
+		return false
+	}
+
+	b := p.registry.Get(cfg.ID)
+	if b == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backendStopTimeout)
+	defer cancel()
+
+	if err := b.UpdateConfig(ctx, cfg); err != nil {
+		if !errors.Is(err, backend.ErrRestartRequired) {
+			p.API.LogWarn("Failed to update backend in place, restarting it", "id", cfg.ID, "name", cfg.Name, "error", err.Error())
+		}
+		return false
+	}
+
+	p.API.LogInfo("Updated backend in place", "id", cfg.ID, "name", cfg.Name)
+	return true
+}
+
 // OnConfigurationChange is invoked when configuration changes may have been made.
 func (p *Plugin) OnConfigurationChange() error {
 	var newConfig = new(configuration)
@@ -116,42 +425,118 @@ func (p *Plugin) OnConfigurationChange() error {
 		return errors.Wrap(err, "failed to load plugin configuration")
 	}
 
+	if newConfig.DailySummaryHourUTC < 0 || newConfig.DailySummaryHourUTC > 23 {
+		return errors.Errorf("daily summary hour must be between 0 and 23 (got %d)", newConfig.DailySummaryHourUTC)
+	}
+
+	if err := newConfig.validateTriageReactions(); err != nil {
+		return err
+	}
+
+	if err := newConfig.validateControlUsers(); err != nil {
+		return err
+	}
+
+	if err := validateStorageDriver(newConfig.StorageDriver); err != nil {
+		return err
+	}
+
+	// Validate the defaults before the backends inheriting them
+	if newConfig.Defaults != nil {
+		if err := newConfig.Defaults.Validate(); err != nil {
+			return errors.Wrap(err, "invalid backend configuration")
+		}
+	}
+
 	// Validate backend configurations
-	if err := backend.ValidateBackends(newConfig.Backends); err != nil {
+	if err := backend.ValidateBackendsWithOptions(newConfig.backends(), newConfig.validationOptions()); err != nil {
 		return errors.Wrap(err, "invalid backend configuration")
 	}
 
+	// Report mentioned users and groups that don't exist
+	p.warnMissingMentionTargets(newConfig.backends())
+
+	// Move plaintext API keys and proxy passwords into the encrypted secret store
+	migrated, err := p.storeSecrets(newConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to store backend secrets")
+	}
+
 	// Get old configuration for comparison
 	oldConfig := p.getConfiguration()
 
-	// Determine which backends need to be added, updated, or removed
-	toAdd, toUpdate, toRemove := backend.DiffBackendConfigs(oldConfig.Backends, newConfig.Backends)
+	// Determine which backends need to be added, updated, or removed.
+	// This runs before the stored secrets are blanked so a changed secret still restarts its backend.
+	toAdd, toUpdate, toRemove := backend.DiffBackendConfigs(oldConfig.backends(), newConfig.backends())
+
+	if migrated {
+		blankStoredSecrets(newConfig)
+		go p.saveMigratedConfig(newConfig.Clone())
+	}
 
 	// Update the configuration before managing backends
 	p.setConfiguration(newConfig)
 
+	// Apply the per-channel rate limit and formatting options (the poster is created in OnActivate)
+	if p.poster != nil {
+		p.poster.SetRateLimit(newConfig.rateLimit())
+		p.poster.SetFormatOptions(newConfig.formatOptions())
+		p.poster.SetHashtagOptions(newConfig.hashtagOptions())
+		p.poster.SetHashtagPlacements(newConfig.hashtagPlacements())
+		p.poster.SetBotIdentities(newConfig.botIdentities())
+		p.poster.SetMentionRules(newConfig.mentionRules())
+		p.poster.SetContentLimits(newConfig.contentLimits())
+		p.poster.SetMediaUploads(newConfig.mediaUploads())
+		p.poster.SetMediaBundles(newConfig.mediaBundles())
+		p.poster.SetLocales(newConfig.locales(p.serverLocale()))
+		p.poster.SetTimeDisplays(newConfig.timeDisplays())
+		p.poster.SetTopicStyles(newConfig.topicStyles())
+		p.poster.SetAckSLAs(newConfig.ackSLAs())
+		p.poster.SetIncidentSettings(newConfig.incidentSettings())
+		p.poster.SetWorkflows(newConfig.workflows())
+		p.poster.SetAdminChannel(newConfig.AdminChannelID)
+	}
+
 	// Handle backend lifecycle changes
 	if p.registry != nil {
 		// Remove deleted backends
 		for _, id := range toRemove {
 			unregisterBackend(p.registry, p.API, id, "backend removed from configuration")
+			p.deleteSecrets(newConfig, id)
+			p.deleteSubscriptions(id)
+			p.pruneAlertPosts(id)
 		}
 
-		// Update modified backends (stop old, start new)
+		// Update modified backends in place when possible, otherwise stop the old and start a new one
 		for _, id := range toUpdate {
+			cfg, found := findBackendConfigByID(newConfig.backends(), id)
+			if found && p.updateBackendInPlace(oldConfig.backends(), cfg) {
+				continue
+			}
+
 			unregisterBackend(p.registry, p.API, id, "backend configuration changed")
-			if cfg, found := findBackendConfigByID(newConfig.Backends, id); found {
+			if found {
 				p.createAndStartBackend(cfg)
 			}
 		}
 
 		// Add new backends
 		for _, id := range toAdd {
-			if cfg, found := findBackendConfigByID(newConfig.Backends, id); found {
+			if cfg, found := findBackendConfigByID(newConfig.backends(), id); found {
 				p.createAndStartBackend(cfg)
 			}
 		}
 	}
 
+	// Pause or resume every backend when the global pause changed, and pause added backends
+	if p.registry != nil && (oldConfig.GlobalPause || newConfig.GlobalPause) {
+		p.applyGlobalPause(newConfig.GlobalPause)
+	}
+
+	// Start, restart or stop standbys whose backend or failover settings changed
+	if p.failover != nil {
+		p.failover.Check()
+	}
+
 	return nil
 }
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/triage"
+)
+
+func TestConfiguration_BackendDefaults(t *testing.T) {
+	config := &configuration{
+		Defaults: &backend.Defaults{PollIntervalSeconds: 60, HashtagLocale: "fr"},
+		Backends: []backend.Config{
+			{ID: "backend-1"},
+			{ID: "backend-2", PollIntervalSeconds: 30, HashtagLocale: "de"},
+		},
+	}
+
+	backends := config.backends()
+	require.Len(t, backends, 2)
+	assert.Equal(t, 60, backends[0].PollIntervalSeconds)
+	assert.Equal(t, 30, backends[1].PollIntervalSeconds)
+
+	// Derived per-backend settings use the inherited values
+	options := config.hashtagOptions()
+	assert.Equal(t, "fr", options["backend-1"].Locale)
+	assert.Equal(t, "de", options["backend-2"].Locale)
+
+	// The saved configurations don't include the inherited values
+	assert.Zero(t, config.Backends[0].PollIntervalSeconds)
+}
+
+func TestConfiguration_HashtagPlacements(t *testing.T) {
+	config := &configuration{Backends: []backend.Config{
+		{ID: "backend-1"},
+		{ID: "backend-2", Hashtags: &backend.HashtagSettings{Placement: backend.HashtagPlacementReply}},
+	}}
+
+	assert.Equal(t, map[string]string{
+		"backend-1": backend.HashtagPlacementMessage,
+		"backend-2": backend.HashtagPlacementReply,
+	}, config.hashtagPlacements())
+}
+
+func TestConfiguration_Locales(t *testing.T) {
+	config := &configuration{Backends: []backend.Config{
+		{ID: "backend-1"},
+		{ID: "backend-2", Locale: "de"},
+	}}
+
+	assert.Equal(t, map[string]string{"backend-1": "fr", "backend-2": "de"}, config.locales("fr"))
+	assert.Equal(t, map[string]string{"backend-1": "en", "backend-2": "de"}, config.locales("ja"))
+}
+
+func TestConfiguration_TimeDisplays(t *testing.T) {
+	config := &configuration{Backends: []backend.Config{
+		{ID: "backend-1"},
+		{ID: "backend-2", TimeDisplay: &backend.TimeDisplaySettings{Timezone: "Europe/Berlin", ShowRelative: true}},
+	}}
+
+	assert.Equal(t, map[string]backend.TimeDisplaySettings{
+		"backend-2": {Timezone: "Europe/Berlin", ShowRelative: true},
+	}, config.timeDisplays())
+}
+
+func TestConfiguration_MediaBundles(t *testing.T) {
+	config := &configuration{Backends: []backend.Config{
+		{ID: "backend-1"},
+		{ID: "backend-2", MediaBundle: &backend.MediaBundleSettings{Mode: backend.MediaBundleModeFiles, MaxItems: 6}},
+	}}
+
+	assert.Equal(t, map[string]backend.MediaBundleSettings{
+		"backend-2": {Mode: backend.MediaBundleModeFiles, MaxItems: 6},
+	}, config.mediaBundles())
+}
+
+func TestConfiguration_TopicStyles(t *testing.T) {
+	config := &configuration{
+		Defaults: &backend.Defaults{TopicStyles: []backend.TopicStyle{{Match: "Cyber", Color: "#6A0DAD"}}},
+		Backends: []backend.Config{
+			{ID: "backend-1"},
+			{ID: "backend-2", TopicStyles: []backend.TopicStyle{{Match: "Weather", Emoji: ":cloud:"}}},
+		},
+	}
+
+	assert.Equal(t, map[string][]backend.TopicStyle{
+		"backend-1": {{Match: "Cyber", Color: "#6A0DAD"}},
+		"backend-2": {{Match: "Weather", Emoji: ":cloud:"}},
+	}, config.topicStyles())
+}
+
+func TestConfiguration_AckSLAs(t *testing.T) {
+	config := &configuration{Backends: []backend.Config{
+		{ID: "backend-1"},
+		{ID: "backend-2", AckSLA: &backend.AckSLASettings{WindowMinutes: 5}},
+	}}
+
+	assert.Equal(t, map[string]backend.AckSLASettings{"backend-2": {WindowMinutes: 5}}, config.ackSLAs())
+}
+
+func TestConfiguration_IncidentSettings(t *testing.T) {
+	config := &configuration{Backends: []backend.Config{
+		{ID: "backend-1"},
+		{ID: "backend-2", Incidents: &backend.IncidentSettings{WindowMinutes: 30}},
+	}}
+
+	assert.Equal(t, map[string]backend.IncidentSettings{"backend-2": {WindowMinutes: 30}}, config.incidentSettings())
+}
+
+func TestConfiguration_Workflows(t *testing.T) {
+	config := &configuration{Backends: []backend.Config{
+		{ID: "backend-1"},
+		{ID: "backend-2", Workflow: &backend.WorkflowSettings{PlaybookID: "playbook1"}},
+	}}
+
+	assert.Equal(t, map[string]backend.WorkflowSettings{"backend-2": {PlaybookID: "playbook1"}}, config.workflows())
+}
+
+func TestConfiguration_TriageReactions(t *testing.T) {
+	config := &configuration{}
+	assert.Equal(t, map[string]triage.State{
+		"eyes":             triage.StateInvestigating,
+		"white_check_mark": triage.StateResolved,
+		"no_entry_sign":    triage.StateFalsePositive,
+	}, config.triageReactions())
+	assert.NoError(t, config.validateTriageReactions())
+
+	config = &configuration{TriageResolvedEmoji: ":heavy_check_mark:", TriageFalsePositiveEmoji: " x "}
+	assert.Equal(t, triage.StateResolved, config.triageReactions()["heavy_check_mark"])
+	assert.Equal(t, triage.StateFalsePositive, config.triageReactions()["x"])
+
+	config = &configuration{TriageResolvedEmoji: "eyes"}
+	assert.Error(t, config.validateTriageReactions())
+}
+
+func TestConfiguration_ValidationOptions(t *testing.T) {
+	assert.Equal(t, backend.ValidationOptions{}, (&configuration{}).validationOptions())
+	assert.Equal(t, backend.ValidationOptions{AllowInsecureURLs: true}, (&configuration{AllowInsecureBackendURLs: true}).validationOptions())
+}
+
+func TestUpdateBackendInPlace(t *testing.T) {
+	oldCfg := backend.Config{ID: "backend-1", Name: "Weather Watch", Type: "dataminr", Enabled: true, ChannelID: "channel-1", PollIntervalSeconds: 30}
+
+	newPlugin := func(b *fakeBackend) *Plugin {
+		api := &plugintest.API{}
+		api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		p := newCommandTestPlugin(api)
+		p.registry = backend.NewRegistry()
+		require.NoError(t, p.registry.Register(b))
+		return p
+	}
+
+	t.Run("safe change", func(t *testing.T) {
+		b := &fakeBackend{id: "backend-1", name: "Weather Watch"}
+		p := newPlugin(b)
+
+		cfg := oldCfg
+		cfg.Name = "Storm Watch"
+		cfg.ChannelID = "channel-2"
+		cfg.PollIntervalSeconds = 60
+		assert.True(t, p.updateBackendInPlace([]backend.Config{oldCfg}, cfg))
+		require.Len(t, b.updates, 1)
+		assert.Equal(t, cfg, b.updates[0])
+		assert.Equal(t, "Storm Watch", b.GetName())
+	})
+
+	t.Run("change requiring a restart", func(t *testing.T) {
+		b := &fakeBackend{id: "backend-1", name: "Weather Watch"}
+		p := newPlugin(b)
+
+		cfg := oldCfg
+		cfg.APIId = "other-id"
+		assert.False(t, p.updateBackendInPlace([]backend.Config{oldCfg}, cfg))
+		assert.Empty(t, b.updates)
+	})
+
+	t.Run("backend not registered", func(t *testing.T) {
+		p := newPlugin(&fakeBackend{id: "backend-2"})
+
+		cfg := oldCfg
+		cfg.Name = "Storm Watch"
+		assert.False(t, p.updateBackendInPlace([]backend.Config{oldCfg}, cfg))
+	})
+
+	t.Run("backend fails to update", func(t *testing.T) {
+		b := &fakeBackend{id: "backend-1", name: "Weather Watch", updateErr: errors.New("stop timed out")}
+		p := newPlugin(b)
+
+		cfg := oldCfg
+		cfg.Name = "Storm Watch"
+		assert.False(t, p.updateBackendInPlace([]backend.Config{oldCfg}, cfg))
+		assert.Equal(t, "Weather Watch", b.GetName())
+	})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// credentialCheckJobID is the cluster job ID for validating the credentials of each backend
+	credentialCheckJobID = "dataminr_credential_check"
+
+	// credentialCheckInterval is how often the credentials of each backend are validated
+	credentialCheckInterval = 24 * time.Hour
+
+	// credentialCheckTimeout bounds how long validating the credentials of a backend may take
+	credentialCheckTimeout = time.Minute
+)
+
+// CredentialCheck validates the credentials of each enabled backend once a day with an
+// authentication-only call, so expired or revoked credentials are reported to the admin
+// channel before a poll fails. Each backend records the outcome in its status. It runs as a
+// cluster job so each backend is checked by a single node.
+type CredentialCheck struct {
+	api      plugin.API
+	registry *backend.Registry
+	config   func() *configuration
+	warn     func(message string) error
+	job      *cluster.Job
+}
+
+// NewCredentialCheck creates a credential check job posting problems with warn
+func NewCredentialCheck(api plugin.API, registry *backend.Registry, config func() *configuration, warn func(message string) error) *CredentialCheck {
+	return &CredentialCheck{
+		api:      api,
+		registry: registry,
+		config:   config,
+		warn:     warn,
+	}
+}
+
+// Start schedules the periodic cluster-aware credential check
+func (c *CredentialCheck) Start() error {
+	job, err := cluster.Schedule(c.api, credentialCheckJobID, cluster.MakeWaitForInterval(credentialCheckInterval), c.run)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule credential check job")
+	}
+
+	c.job = job
+	return nil
+}
+
+// Stop cancels the credential check job
+func (c *CredentialCheck) Stop() {
+	if c.job == nil {
+		return
+	}
+
+	if err := c.job.Close(); err != nil {
+		c.api.LogWarn("Failed to close credential check job", "error", err.Error())
+	}
+	c.job = nil
+}
+
+// run validates the credentials of each enabled backend and warns the admin channel about
+// each credential problem. Disabled backends are skipped so they aren't issued tokens, as
+// are backends whose source needs no credentials.
+func (c *CredentialCheck) run() {
+	configs := c.config().backends()
+	for _, b := range c.registry.List() {
+		if cfg, found := findBackendConfigByID(configs, b.GetID()); !found || !cfg.Enabled {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), credentialCheckTimeout)
+		check, err := b.CheckCredentials(ctx)
+		cancel()
+		if errors.Is(err, backend.ErrCredentialCheckUnsupported) {
+			continue
+		}
+		if err != nil {
+			c.api.LogWarn("Failed to record backend credential check", "backendId", b.GetID(), "error", err.Error())
+		}
+
+		problem := check.Problem()
+		if problem == "" {
+			continue
+		}
+
+		message := fmt.Sprintf(":warning: The daily credential check of backend **%s** found a problem: %s", b.GetName(), problem)
+		if err := c.warn(message); err != nil {
+			c.api.LogError("Failed to post credential check warning", "backendId", b.GetID(), "error", err.Error())
+		}
+	}
+}
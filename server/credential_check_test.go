@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestCredentialCheck_run(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	api.On("LogWarn", "Failed to record backend credential check", "backendId", "unsaved", "error", "failed to save credential check").Once()
+
+	checkedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	registry := backend.NewRegistry()
+	require.NoError(t, registry.Register(&fakeBackend{id: "healthy", name: "Healthy", credentials: backend.CredentialCheck{
+		CheckedAt: checkedAt, Valid: true, TokenExpiry: checkedAt.Add(time.Hour),
+	}}))
+	require.NoError(t, registry.Register(&fakeBackend{id: "revoked", name: "Revoked", credentials: backend.CredentialCheck{
+		CheckedAt: checkedAt, Rejected: true, Error: "authentication failed with HTTP 401",
+	}}))
+	require.NoError(t, registry.Register(&fakeBackend{id: "unreachable", name: "Unreachable", credentials: backend.CredentialCheck{
+		CheckedAt: checkedAt, Error: "network error: connection refused",
+	}}))
+	require.NoError(t, registry.Register(&fakeBackend{id: "unsaved", name: "Unsaved", credentials: backend.CredentialCheck{
+		CheckedAt: checkedAt, Valid: true, TokenExpiry: checkedAt.Add(time.Minute),
+	}, credentialsErr: errors.New("failed to save credential check")}))
+	require.NoError(t, registry.Register(&fakeBackend{id: "simulator", name: "Demo", credentialsErr: backend.ErrCredentialCheckUnsupported}))
+	require.NoError(t, registry.Register(&fakeBackend{id: "disabled", name: "Disabled", credentials: backend.CredentialCheck{
+		CheckedAt: checkedAt, Rejected: true, Error: "authentication failed with HTTP 401",
+	}}))
+
+	config := &configuration{
+		Defaults: &backend.Defaults{PollIntervalSeconds: 60},
+		Backends: []backend.Config{
+			{ID: "healthy", Enabled: true},
+			{ID: "revoked", Enabled: true},
+			{ID: "unreachable", Enabled: true},
+			{ID: "unsaved", Enabled: true},
+			{ID: "simulator", Enabled: true},
+			{ID: "disabled", Enabled: false},
+		},
+	}
+
+	var warnings []string
+	warn := func(message string) error {
+		warnings = append(warnings, message)
+		return nil
+	}
+	NewCredentialCheck(api, registry, func() *configuration { return config }, warn).run()
+
+	assert.ElementsMatch(t, []string{
+		":warning: The daily credential check of backend **Revoked** found a problem: the credentials were rejected: authentication failed with HTTP 401",
+		":warning: The daily credential check of backend **Unsaved** found a problem: the issued token expires after 1m0s, so every poll has to authenticate again",
+	}, warnings)
+}
+
+func TestCredentialCheck_runWarningFails(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	api.On("LogError", "Failed to post credential check warning", "backendId", "revoked", "error", "channel not found").Once()
+
+	registry := backend.NewRegistry()
+	require.NoError(t, registry.Register(&fakeBackend{id: "revoked", name: "Revoked", credentials: backend.CredentialCheck{
+		Rejected: true, Error: "authentication failed with HTTP 403",
+	}}))
+
+	config := &configuration{Backends: []backend.Config{{ID: "revoked", Enabled: true}}}
+	warn := func(string) error { return errors.New("channel not found") }
+	NewCredentialCheck(api, registry, func() *configuration { return config }, warn).run()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+)
+
+const (
+	// dailySummaryJobID is the cluster job ID for the daily summary post
+	dailySummaryJobID = "dataminr_daily_summary"
+
+	// dailySummaryWindow is the period covered by the daily summary
+	dailySummaryWindow = 24 * time.Hour
+
+	// dailySummaryRecheckInterval is how often the job re-reads the configuration while disabled
+	dailySummaryRecheckInterval = time.Hour
+)
+
+// DailySummary posts alert statistics for the last day to each backend channel once a day.
+// It runs as a cluster job so the summary is posted by a single node.
+type DailySummary struct {
+	api    plugin.API
+	index  alertindex.Store
+	config func() *configuration
+	post   func(message, channelID string) error
+	job    *cluster.Job
+	now    func() time.Time
+}
+
+// NewDailySummary creates a daily summary job reading the active configuration on each run
+func NewDailySummary(api plugin.API, index alertindex.Store, config func() *configuration, post func(message, channelID string) error) *DailySummary {
+	return &DailySummary{
+		api:    api,
+		index:  index,
+		config: config,
+		post:   post,
+		now:    time.Now,
+	}
+}
+
+// Start schedules the daily cluster job
+func (d *DailySummary) Start() error {
+	job, err := cluster.Schedule(d.api, dailySummaryJobID, d.nextWaitInterval, d.run)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule daily summary job")
+	}
+
+	d.job = job
+	return nil
+}
+
+// Stop cancels the daily summary job
+func (d *DailySummary) Stop() {
+	if d.job == nil {
+		return
+	}
+
+	if err := d.job.Close(); err != nil {
+		d.api.LogWarn("Failed to close daily summary job", "error", err.Error())
+	}
+	d.job = nil
+}
+
+// nextWaitInterval returns the wait until the configured hour following the previous run.
+// While the summary is disabled, the configuration is re-checked periodically.
+func (d *DailySummary) nextWaitInterval(now time.Time, metadata cluster.JobMetadata) time.Duration {
+	config := d.config()
+	if !config.DailySummaryEnabled {
+		return dailySummaryRecheckInterval
+	}
+
+	last := metadata.LastFinished
+	if last.IsZero() {
+		last = now
+	}
+
+	wait := nextSummaryTime(last, config.DailySummaryHourUTC).Sub(now)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// nextSummaryTime returns the first occurrence of the hour (UTC) strictly after a time
+func nextSummaryTime(after time.Time, hourUTC int) time.Time {
+	after = after.UTC()
+	next := time.Date(after.Year(), after.Month(), after.Day(), hourUTC, 0, 0, 0, time.UTC)
+	if !next.After(after) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// run posts the summary of the last day to every channel backends post to
+func (d *DailySummary) run() {
+	config := d.config()
+	if !config.DailySummaryEnabled {
+		return
+	}
+
+	entries, err := d.index.Search(alertindex.Query{Since: d.now().Add(-dailySummaryWindow)})
+	if err != nil {
+		d.api.LogError("Failed to load alert history for daily summary", "error", err.Error())
+		return
+	}
+
+	byChannel := make(map[string][]alertindex.Entry)
+	for _, entry := range entries {
+		byChannel[entry.ChannelID] = append(byChannel[entry.ChannelID], entry)
+	}
+
+	posted := make(map[string]bool)
+	for _, cfg := range config.Backends {
+		if cfg.ChannelID == "" || posted[cfg.ChannelID] {
+			continue
+		}
+		posted[cfg.ChannelID] = true
+
+		message := formatStats("Daily alert summary for the last 24h", computeStats(byChannel[cfg.ChannelID]))
+		if err := d.post(message, cfg.ChannelID); err != nil {
+			d.api.LogError("Failed to post daily summary", "channelId", cfg.ChannelID, "error", err.Error())
+		}
+	}
+}
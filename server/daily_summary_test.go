@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestNextSummaryTime(t *testing.T) {
+	morning := time.Date(2026, 10, 16, 6, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC), nextSummaryTime(morning, 8))
+	assert.Equal(t, time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC), nextSummaryTime(morning, 6))
+	assert.Equal(t, time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC), nextSummaryTime(time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC), 8))
+}
+
+func TestDailySummary_nextWaitInterval(t *testing.T) {
+	now := time.Date(2026, 10, 16, 6, 30, 0, 0, time.UTC)
+	config := &configuration{DailySummaryEnabled: true, DailySummaryHourUTC: 8}
+	summary := NewDailySummary(nil, nil, func() *configuration { return config }, nil)
+
+	t.Run("waits for the configured hour", func(t *testing.T) {
+		assert.Equal(t, 90*time.Minute, summary.nextWaitInterval(now, cluster.JobMetadata{}))
+	})
+
+	t.Run("runs immediately when the hour passed since the last run", func(t *testing.T) {
+		lastFinished := now.Add(-48 * time.Hour)
+		assert.Equal(t, time.Duration(0), summary.nextWaitInterval(now, cluster.JobMetadata{LastFinished: lastFinished}))
+	})
+
+	t.Run("rechecks the configuration while disabled", func(t *testing.T) {
+		disabled := NewDailySummary(nil, nil, func() *configuration { return &configuration{} }, nil)
+		assert.Equal(t, dailySummaryRecheckInterval, disabled.nextWaitInterval(now, cluster.JobMetadata{}))
+	})
+}
+
+func TestDailySummary_run(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	postedAt := time.Now().Add(-time.Hour)
+	data, err := json.Marshal([]alertindex.Entry{
+		{AlertType: "Flash", Topics: []string{"Fire"}, ChannelID: "channel-1", PostedAt: postedAt},
+	})
+	require.NoError(t, err)
+
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		if key == "alert_index_"+postedAt.UTC().Format("2006010215") {
+			return data, nil
+		}
+		return nil, nil
+	})
+
+	config := &configuration{
+		DailySummaryEnabled: true,
+		Backends: []backend.Config{
+			{ID: "a", ChannelID: "channel-1"},
+			{ID: "b", ChannelID: "channel-1"},
+			{ID: "c", ChannelID: "channel-2"},
+		},
+	}
+
+	posts := make(map[string]string)
+	summary := NewDailySummary(api, alertindex.New(api), func() *configuration { return config }, func(message, channelID string) error {
+		posts[channelID] = message
+		return nil
+	})
+
+	summary.run()
+
+	require.Len(t, posts, 2, "each channel should receive a single summary")
+	assert.Contains(t, posts["channel-1"], "**Total alerts:** 1 (Flash: 1)")
+	assert.Contains(t, posts["channel-2"], "No alerts were posted in this period.")
+}
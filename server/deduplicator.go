@@ -1,28 +1,122 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/mattermost/mattermost/server/public/pluginapi"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
 )
 
 const (
-	// DeduplicationCacheTTL is how long to keep alert IDs in the deduplication cache
-	DeduplicationCacheTTL = 24 * time.Hour
+	// DeduplicationCacheTTL is how long to keep alert IDs recorded without a backend dedup TTL
+	// in the deduplication cache
+	DeduplicationCacheTTL = backend.DefaultDedupTTLHours * time.Hour
 
 	// DeduplicationCleanupInterval is how often to clean up expired entries
 	DeduplicationCleanupInterval = 10 * time.Minute
+
+	// dedupClaimKeyPrefix prefixes the KV keys of alert claims shared by cluster nodes
+	dedupClaimKeyPrefix = "dedup_claim_"
+
+	// SimilarAlertWindow is how far apart the event times of similar alerts may be
+	SimilarAlertWindow = 30 * time.Minute
+
+	// SimilarAlertDistanceKm is how far apart the locations of similar alerts may be
+	SimilarAlertDistanceKm = 25.0
+
+	// SimilarHeadlineThreshold is the minimum share of words two headlines must have in common
+	// (the Jaccard index of their normalized words) for the alerts to be similar
+	SimilarHeadlineThreshold = 0.8
+
+	// SimilarAlertRetention is how long posted alerts are kept for later similar alerts to be
+	// collapsed into. Alerts from another backend arriving after it are posted separately.
+	SimilarAlertRetention = 2 * time.Hour
+
+	// dedupContentKeyPrefix prefixes the KV keys of the alerts recently posted to a channel,
+	// shared by cluster nodes
+	dedupContentKeyPrefix = "dedup_content_"
+
+	// maxUpdateAttempts bounds the retries of a content update or claim extension that lost a
+	// race with another node
+	maxUpdateAttempts = 5
+
+	// minClaimExtension is how much later an alert claim must expire to be extended, so
+	// backends with the same TTL recording an alert moments apart don't rewrite its claim
+	minClaimExtension = time.Minute
+
+	// earthRadiusKm is the mean Earth radius used for distances between alert locations
+	earthRadiusKm = 6371.0
 )
 
+// contentEntry is a posted alert that later similar alerts can be collapsed into
+type contentEntry struct {
+	AlertID     string            `json:"alertId"`
+	BackendID   string            `json:"backendId"`
+	BackendName string            `json:"backendName"`
+	Headline    string            `json:"headline"`
+	EventTime   time.Time         `json:"eventTime"`
+	Location    *backend.Location `json:"location,omitempty"`
+	PostID      string            `json:"postId,omitempty"`
+	Matched     []string          `json:"matched,omitempty"`
+	SeenAt      time.Time         `json:"seenAt"`
+}
+
 // Deduplicator tracks seen alert IDs to prevent duplicate processing across all backends
+// and cluster nodes.
+// It also tracks the content of the alerts recently posted to each channel in the KV store,
+// so near-identical alerts from different backends (e.g. overlapping watchlists) are
+// collapsed into one post per channel whichever node posts them.
 type Deduplicator struct {
-	api         *pluginapi.Client
-	seenAlerts  map[string]time.Time
-	mu          sync.RWMutex
-	stopCleanup chan struct{}
-	cleanupDone chan struct{}
+	api *pluginapi.Client
+	// seenAlerts maps namespaced alert IDs to when they expire from the cache
+	seenAlerts map[string]time.Time
+	mu         sync.RWMutex
+	// lookups counts the alerts recorded by each backend on this node, keyed by backend ID
+	lookups map[string]*DedupLookups
+	// lastCleanup, lastEvictions and totalEvictions describe the cleanups run on this node
+	lastCleanup    time.Time
+	lastEvictions  int
+	totalEvictions int
+	stopCleanup    chan struct{}
+	cleanupDone    chan struct{}
+}
+
+// DedupLookups counts how often a backend's alerts were already seen (hits) or new (misses)
+type DedupLookups struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+}
+
+// HitRate returns the share of lookups that found a duplicate, or 0 without lookups
+func (l DedupLookups) HitRate() float64 {
+	if l.Hits+l.Misses == 0 {
+		return 0
+	}
+	return float64(l.Hits) / float64(l.Hits+l.Misses)
+}
+
+// DedupMetrics describes the deduplication cache of this node, to watch its memory use
+type DedupMetrics struct {
+	// SeenAlerts is the number of alert IDs cached
+	SeenAlerts int `json:"seenAlerts"`
+
+	// LastCleanup is when expired entries were last removed, and LastEvictions how many were
+	LastCleanup    time.Time `json:"lastCleanup"`
+	LastEvictions  int       `json:"lastEvictions"`
+	TotalEvictions int       `json:"totalEvictions"`
+
+	// Backends holds the lookups of each backend since the plugin started, keyed by backend ID
+	Backends map[string]DedupLookups `json:"backends"`
 }
 
 // NewDeduplicator creates a new deduplicator and starts the cleanup loop
@@ -30,6 +124,7 @@ func NewDeduplicator(api *pluginapi.Client) *Deduplicator {
 	d := &Deduplicator{
 		api:         api,
 		seenAlerts:  make(map[string]time.Time),
+		lookups:     make(map[string]*DedupLookups),
 		stopCleanup: make(chan struct{}),
 		cleanupDone: make(chan struct{}),
 	}
@@ -41,21 +136,218 @@ func NewDeduplicator(api *pluginapi.Client) *Deduplicator {
 
 // RecordAlert atomically checks if an alert is new and marks it as seen if so.
 // Returns true if this is a new alert (successfully recorded), false if it's a duplicate.
-// This operation is atomic to prevent race conditions between checking and marking.
+// Alerts not seen by this node are additionally claimed in the KV store, so in HA deployments
+// an alert is only processed by the first node to record it. If the claim can't be made the
+// alert is treated as new, since posting it twice is preferable to dropping it.
 func (d *Deduplicator) RecordAlert(backendType, alertID string) bool {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	return d.recordAlert("", backendType, alertID, DeduplicationCacheTTL)
+}
 
+// recordAlert records an alert as RecordAlert does, keeping it for the TTL and counting the
+// lookup for the backend unless the backend ID is empty
+func (d *Deduplicator) recordAlert(backendID, backendType, alertID string, ttl time.Duration) bool {
+	isNew := d.claimNewAlert(backendType, alertID, ttl)
+	if backendID != "" {
+		d.countLookup(backendID, isNew)
+	}
+	return isNew
+}
+
+// claimNewAlert marks an alert as seen on this node for the TTL and claims it across the
+// cluster. Returns false if either already had it. Backends of the same type share the alert
+// namespace, so a duplicate is kept, on this node and in its cluster claim, for the longest
+// TTL of the backends that recorded it.
+func (d *Deduplicator) claimNewAlert(backendType, alertID string, ttl time.Duration) bool {
 	namespacedID := d.namespaceAlertID(backendType, alertID)
+	expiresAt := time.Now().Add(ttl)
 
+	d.mu.Lock()
 	// Check if already seen
-	if _, exists := d.seenAlerts[namespacedID]; exists {
+	if existing, exists := d.seenAlerts[namespacedID]; exists {
+		extend := expiresAt.Sub(existing) > minClaimExtension
+		if expiresAt.After(existing) {
+			d.seenAlerts[namespacedID] = expiresAt
+		}
+		d.mu.Unlock()
+
+		if extend {
+			d.extendClaim(namespacedID, alertID, ttl)
+		}
 		return false // Duplicate
 	}
 
-	// Mark as seen
-	d.seenAlerts[namespacedID] = time.Now()
-	return true // New alert
+	// Mark as seen before claiming so concurrent callers on this node don't claim it too
+	d.seenAlerts[namespacedID] = expiresAt
+	d.mu.Unlock()
+
+	claimed, err := d.claimAlert(namespacedID, ttl)
+	if err != nil {
+		d.api.Log.Warn("Failed to claim alert across the cluster, treating it as new", "alertId", alertID, "error", err.Error())
+		return true
+	}
+	if !claimed {
+		// Another node recorded the alert first, possibly for a shorter TTL
+		d.extendClaim(namespacedID, alertID, ttl)
+	}
+	return claimed
+}
+
+// extendClaim extends the cluster claim of an alert to the TTL, unless it already expires
+// about as late. Failures are logged, leaving the claim to expire early.
+func (d *Deduplicator) extendClaim(namespacedID, alertID string, ttl time.Duration) {
+	if err := d.updateClaimExpiry(namespacedID, ttl); err != nil {
+		d.api.Log.Warn("Failed to extend cluster claim for alert", "alertId", alertID, "error", err.Error())
+	}
+}
+
+// updateClaimExpiry replaces the claim of an alert with one expiring after the TTL, if the
+// claim expires more than minClaimExtension earlier. An expired claim is recreated.
+func (d *Deduplicator) updateClaimExpiry(namespacedID string, ttl time.Duration) error {
+	key := claimKey(namespacedID)
+	expiresAt := time.Now().Add(ttl)
+	newData, err := expiresAt.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var oldData []byte
+		if err := d.api.KV.Get(key, &oldData); err != nil {
+			return fmt.Errorf("failed to get alert claim: %w", err)
+		}
+
+		var claimExpiresAt time.Time
+		if len(oldData) > 0 && claimExpiresAt.UnmarshalText(oldData) == nil && expiresAt.Sub(claimExpiresAt) <= minClaimExtension {
+			return nil
+		}
+
+		saved, err := d.api.KV.Set(key, newData, pluginapi.SetAtomic(oldData), pluginapi.SetExpiry(ttl))
+		if err != nil {
+			return fmt.Errorf("failed to save alert claim: %w", err)
+		}
+		if saved {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to save alert claim: too many concurrent updates")
+}
+
+// IsSeen reports whether an alert was recorded on this node or claimed by another node,
+// without recording it. Lookup failures are treated as unseen.
+func (d *Deduplicator) IsSeen(backendType, alertID string) bool {
+	namespacedID := d.namespaceAlertID(backendType, alertID)
+
+	d.mu.RLock()
+	expiresAt, exists := d.seenAlerts[namespacedID]
+	d.mu.RUnlock()
+	if exists && time.Now().Before(expiresAt) {
+		return true
+	}
+
+	var claimedAt []byte
+	if err := d.api.KV.Get(claimKey(namespacedID), &claimedAt); err != nil {
+		d.api.Log.Warn("Failed to look up cluster claim for alert", "alertId", alertID, "error", err.Error())
+		return false
+	}
+	return len(claimedAt) > 0
+}
+
+// countLookup counts a backend's alert as a hit if it was a duplicate, or a miss if it was new
+func (d *Deduplicator) countLookup(backendID string, isNew bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	lookups, ok := d.lookups[backendID]
+	if !ok {
+		lookups = &DedupLookups{}
+		d.lookups[backendID] = lookups
+	}
+	if isNew {
+		lookups.Misses++
+	} else {
+		lookups.Hits++
+	}
+}
+
+// ForBackend returns the deduplicator to pass to a backend, which keeps its alerts for the
+// backend's dedup TTL and counts its lookups in the metrics under the backend ID
+func (d *Deduplicator) ForBackend(backendID string, ttl time.Duration) backend.Deduplicator {
+	return &backendDeduplicator{deduplicator: d, backendID: backendID, ttl: ttl}
+}
+
+// Metrics returns the size of the cache, its evictions and the lookups of each backend
+func (d *Deduplicator) Metrics() DedupMetrics {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	metrics := DedupMetrics{
+		SeenAlerts:     len(d.seenAlerts),
+		LastCleanup:    d.lastCleanup,
+		LastEvictions:  d.lastEvictions,
+		TotalEvictions: d.totalEvictions,
+		Backends:       make(map[string]DedupLookups, len(d.lookups)),
+	}
+	for backendID, lookups := range d.lookups {
+		metrics.Backends[backendID] = *lookups
+	}
+	return metrics
+}
+
+// backendDeduplicator is the view of the shared deduplicator given to a single backend
+type backendDeduplicator struct {
+	deduplicator *Deduplicator
+	backendID    string
+	ttl          time.Duration
+}
+
+// RecordAlert records an alert in the shared deduplicator for the backend's dedup TTL,
+// counting the lookup for the backend
+func (b *backendDeduplicator) RecordAlert(backendType, alertID string) bool {
+	return b.deduplicator.recordAlert(b.backendID, backendType, alertID, b.ttl)
+}
+
+// IsSeen reports whether the alert was recorded in the shared deduplicator
+func (b *backendDeduplicator) IsSeen(backendType, alertID string) bool {
+	return b.deduplicator.IsSeen(backendType, alertID)
+}
+
+// ForgetAlert removes an alert from the shared deduplicator
+func (b *backendDeduplicator) ForgetAlert(backendType, alertID string) {
+	b.deduplicator.ForgetAlert(backendType, alertID)
+}
+
+// ForgetAlert removes an alert that was recorded but never posted, releasing its cluster claim
+// so the alert is processed again when it is fetched next.
+func (d *Deduplicator) ForgetAlert(backendType, alertID string) {
+	namespacedID := d.namespaceAlertID(backendType, alertID)
+
+	d.mu.Lock()
+	delete(d.seenAlerts, namespacedID)
+	d.mu.Unlock()
+
+	if err := d.api.KV.Delete(claimKey(namespacedID)); err != nil {
+		d.api.Log.Warn("Failed to release cluster claim for alert", "alertId", alertID, "error", err.Error())
+	}
+}
+
+// claimAlert records an alert in the KV store unless another node already has.
+// Claims expire with the TTL the alert was recorded for and hold their expiry time, so a
+// backend recording the alert for a longer TTL can extend them.
+func (d *Deduplicator) claimAlert(namespacedID string, ttl time.Duration) (bool, error) {
+	expiresAt, err := time.Now().Add(ttl).MarshalText()
+	if err != nil {
+		return false, err
+	}
+
+	return d.api.KV.Set(claimKey(namespacedID), expiresAt,
+		pluginapi.SetAtomic(nil),
+		pluginapi.SetExpiry(ttl))
+}
+
+// claimKey returns the KV key of an alert claim. Alert IDs are hashed to stay within the KV key length limit.
+func claimKey(namespacedID string) string {
+	sum := sha256.Sum256([]byte(namespacedID))
+	return dedupClaimKeyPrefix + hex.EncodeToString(sum[:])
 }
 
 // namespaceAlertID creates a namespaced alert ID to prevent collisions between backend types
@@ -63,6 +355,220 @@ func (d *Deduplicator) namespaceAlertID(backendType, alertID string) string {
 	return fmt.Sprintf("%s:%s", backendType, alertID)
 }
 
+// ClaimContent atomically checks for a similar alert from another backend already posted to
+// the channel. Returns the match if one exists, recording this backend on it. Otherwise the
+// alert's content is reserved and nil is returned. If the channel's alerts can't be loaded or
+// saved the alert is treated as new, since posting it twice is preferable to dropping it.
+func (d *Deduplicator) ClaimContent(alert backend.Alert, channelID string) *poster.ContentMatch {
+	headline := normalizeHeadline(alert.Headline)
+	if headline == "" {
+		return nil
+	}
+
+	var match *poster.ContentMatch
+	err := d.updateContent(channelID, func(entries []contentEntry) []contentEntry {
+		match = nil
+		for i := range entries {
+			entry := &entries[i]
+			if entry.BackendID == alert.BackendID || !entry.isSimilar(headline, alert) {
+				continue
+			}
+
+			if !containsString(entry.Matched, alert.BackendName) {
+				entry.Matched = append(entry.Matched, alert.BackendName)
+			}
+			match = &poster.ContentMatch{
+				PostID:          entry.PostID,
+				BackendID:       entry.BackendID,
+				BackendName:     entry.BackendName,
+				MatchedBackends: append([]string(nil), entry.Matched...),
+			}
+			return entries
+		}
+
+		return append(entries, contentEntry{
+			AlertID:     alert.AlertID,
+			BackendID:   alert.BackendID,
+			BackendName: alert.BackendName,
+			Headline:    headline,
+			EventTime:   alert.EventTime,
+			Location:    alert.Location,
+			SeenAt:      time.Now(),
+		})
+	})
+	if err != nil {
+		d.api.Log.Warn("Failed to check for similar alerts, posting the alert", "alertId", alert.AlertID, "channelId", channelID, "error", err.Error())
+		return nil
+	}
+	return match
+}
+
+// SetContentPost records the post created for a claimed alert.
+// Returns the backends whose similar alerts were collapsed into it in the meantime.
+func (d *Deduplicator) SetContentPost(alert backend.Alert, channelID, postID string) []string {
+	var matched []string
+	err := d.updateContent(channelID, func(entries []contentEntry) []contentEntry {
+		matched = nil
+		for i := range entries {
+			if entries[i].AlertID == alert.AlertID && entries[i].BackendID == alert.BackendID {
+				entries[i].PostID = postID
+				matched = append([]string(nil), entries[i].Matched...)
+				break
+			}
+		}
+		return entries
+	})
+	if err != nil {
+		d.api.Log.Warn("Failed to record the post of an alert for similar alerts", "alertId", alert.AlertID, "channelId", channelID, "error", err.Error())
+		return nil
+	}
+	return matched
+}
+
+// ReleaseContent removes the reservation of an alert that could not be posted
+func (d *Deduplicator) ReleaseContent(alert backend.Alert, channelID string) {
+	err := d.updateContent(channelID, func(entries []contentEntry) []contentEntry {
+		for i, entry := range entries {
+			if entry.AlertID == alert.AlertID && entry.BackendID == alert.BackendID {
+				return append(entries[:i], entries[i+1:]...)
+			}
+		}
+		return entries
+	})
+	if err != nil {
+		d.api.Log.Warn("Failed to release the content of an unposted alert", "alertId", alert.AlertID, "channelId", channelID, "error", err.Error())
+	}
+}
+
+// updateContent applies a change to the alerts recently posted to a channel with
+// compare-and-set, retrying when another node changed them concurrently. Alerts older than
+// SimilarAlertRetention are dropped before the change is applied.
+func (d *Deduplicator) updateContent(channelID string, change func([]contentEntry) []contentEntry) error {
+	key := dedupContentKeyPrefix + channelID
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var oldData []byte
+		if err := d.api.KV.Get(key, &oldData); err != nil {
+			return fmt.Errorf("failed to get channel alert contents: %w", err)
+		}
+
+		var entries []contentEntry
+		if len(oldData) > 0 {
+			if err := json.Unmarshal(oldData, &entries); err != nil {
+				return fmt.Errorf("failed to unmarshal channel alert contents: %w", err)
+			}
+		}
+
+		now := time.Now()
+		kept := entries[:0]
+		for _, entry := range entries {
+			if now.Sub(entry.SeenAt) <= SimilarAlertRetention {
+				kept = append(kept, entry)
+			}
+		}
+		entries = change(kept)
+
+		var newData []byte
+		if len(entries) > 0 {
+			var err error
+			if newData, err = json.Marshal(entries); err != nil {
+				return fmt.Errorf("failed to marshal channel alert contents: %w", err)
+			}
+		}
+
+		saved, err := d.api.KV.Set(key, newData, pluginapi.SetAtomic(oldData), pluginapi.SetExpiry(SimilarAlertRetention))
+		if err != nil {
+			return fmt.Errorf("failed to save channel alert contents: %w", err)
+		}
+		if saved {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to save channel alert contents: too many concurrent updates")
+}
+
+// isSimilar reports whether an alert with a similar normalized headline happened close enough
+// in time and place. Location is only compared when both alerts have coordinates.
+func (e *contentEntry) isSimilar(headline string, alert backend.Alert) bool {
+	if headlineSimilarity(e.Headline, headline) < SimilarHeadlineThreshold {
+		return false
+	}
+
+	delta := e.EventTime.Sub(alert.EventTime)
+	if delta < 0 {
+		delta = -delta
+	}
+	if delta > SimilarAlertWindow {
+		return false
+	}
+
+	if !hasCoordinates(e.Location) || !hasCoordinates(alert.Location) {
+		return true
+	}
+
+	return distanceKm(e.Location, alert.Location) <= SimilarAlertDistanceKm
+}
+
+// headlineSimilarity returns the share of distinct words two normalized headlines have in
+// common, from 0 for no common word to 1 for the same words
+func headlineSimilarity(a, b string) float64 {
+	wordsA := make(map[string]bool)
+	for _, word := range strings.Fields(a) {
+		wordsA[word] = true
+	}
+	wordsB := make(map[string]bool)
+	for _, word := range strings.Fields(b) {
+		wordsB[word] = true
+	}
+
+	common := 0
+	for word := range wordsB {
+		if wordsA[word] {
+			common++
+		}
+	}
+
+	union := len(wordsA) + len(wordsB) - common
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
+
+// normalizeHeadline lowercases a headline and reduces punctuation and whitespace to single spaces
+func normalizeHeadline(headline string) string {
+	fields := strings.FieldsFunc(strings.ToLower(headline), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// hasCoordinates reports whether a location includes coordinates
+func hasCoordinates(loc *backend.Location) bool {
+	return loc != nil && (loc.Latitude != 0 || loc.Longitude != 0)
+}
+
+// distanceKm returns the great-circle distance between two locations
+func distanceKm(a, b *backend.Location) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// containsString reports whether a slice contains a string
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // cleanupLoop periodically removes expired entries from the cache
 func (d *Deduplicator) cleanupLoop() {
 	ticker := time.NewTicker(DeduplicationCleanupInterval)
@@ -79,7 +585,7 @@ func (d *Deduplicator) cleanupLoop() {
 	}
 }
 
-// cleanup removes entries older than DeduplicationCacheTTL
+// cleanup removes alert IDs past their expiry
 func (d *Deduplicator) cleanup() {
 	d.mu.Lock()
 	defer d.mu.Unlock()
@@ -87,13 +593,17 @@ func (d *Deduplicator) cleanup() {
 	now := time.Now()
 	expired := 0
 
-	for alertID, seenTime := range d.seenAlerts {
-		if now.Sub(seenTime) > DeduplicationCacheTTL {
+	for alertID, expiresAt := range d.seenAlerts {
+		if now.After(expiresAt) {
 			delete(d.seenAlerts, alertID)
 			expired++
 		}
 	}
 
+	d.lastCleanup = now
+	d.lastEvictions = expired
+	d.totalEvictions += d.lastEvictions
+
 	if expired > 0 {
 		d.api.Log.Debug("Cleaned up expired deduplication cache entries",
 			"expired", expired,
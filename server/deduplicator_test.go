@@ -1,18 +1,31 @@
 package main
 
 import (
+	"bytes"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
 
+// mockAlertClaims makes every cluster alert claim succeed
+func mockAlertClaims(api *plugintest.API) {
+	api.On("KVSetWithOptions", mock.AnythingOfType("string"), mock.Anything, mock.Anything).Return(true, nil).Maybe()
+}
+
 func TestDeduplicator(t *testing.T) {
 	t.Run("new alert is recorded successfully", func(t *testing.T) {
 		api := plugintest.NewAPI(t)
+		mockAlertClaims(api)
 		client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 		dedup := NewDeduplicator(client)
@@ -24,6 +37,7 @@ func TestDeduplicator(t *testing.T) {
 
 	t.Run("duplicate alert is rejected", func(t *testing.T) {
 		api := plugintest.NewAPI(t)
+		mockAlertClaims(api)
 		client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 		dedup := NewDeduplicator(client)
@@ -40,6 +54,7 @@ func TestDeduplicator(t *testing.T) {
 
 	t.Run("backend type namespacing prevents collisions", func(t *testing.T) {
 		api := plugintest.NewAPI(t)
+		mockAlertClaims(api)
 		client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 		dedup := NewDeduplicator(client)
@@ -64,6 +79,7 @@ func TestDeduplicator(t *testing.T) {
 
 	t.Run("multiple different alerts", func(t *testing.T) {
 		api := plugintest.NewAPI(t)
+		mockAlertClaims(api)
 		client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 		dedup := NewDeduplicator(client)
@@ -85,6 +101,7 @@ func TestDeduplicator(t *testing.T) {
 
 	t.Run("cleanup removes expired entries", func(t *testing.T) {
 		api := plugintest.NewAPI(t)
+		mockAlertClaims(api)
 		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 		client := pluginapi.NewClient(api, &plugintest.Driver{})
 
@@ -95,9 +112,9 @@ func TestDeduplicator(t *testing.T) {
 		isNew := dedup.RecordAlert("dataminr", "alert-1")
 		assert.True(t, isNew)
 
-		// Manually set the seen time to be older than TTL (25 hours, TTL is 24 hours)
+		// Manually expire the alert
 		dedup.mu.Lock()
-		dedup.seenAlerts["dataminr:alert-1"] = time.Now().Add(-25 * time.Hour)
+		dedup.seenAlerts["dataminr:alert-1"] = time.Now().Add(-time.Hour)
 		dedup.mu.Unlock()
 
 		// Run cleanup
@@ -110,6 +127,7 @@ func TestDeduplicator(t *testing.T) {
 
 	t.Run("cleanup keeps recent entries", func(t *testing.T) {
 		api := plugintest.NewAPI(t)
+		mockAlertClaims(api)
 		client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 		dedup := NewDeduplicator(client)
@@ -129,6 +147,7 @@ func TestDeduplicator(t *testing.T) {
 
 	t.Run("cleanup with mixed expiration", func(t *testing.T) {
 		api := plugintest.NewAPI(t)
+		mockAlertClaims(api)
 		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 		client := pluginapi.NewClient(api, &plugintest.Driver{})
 
@@ -139,10 +158,10 @@ func TestDeduplicator(t *testing.T) {
 		assert.True(t, dedup.RecordAlert("dataminr", "alert-recent-1"))
 		assert.True(t, dedup.RecordAlert("dataminr", "alert-recent-2"))
 
-		// Add old alerts
+		// Add expired alerts
 		dedup.mu.Lock()
-		dedup.seenAlerts["dataminr:alert-old-1"] = time.Now().Add(-25 * time.Hour)
-		dedup.seenAlerts["dataminr:alert-old-2"] = time.Now().Add(-26 * time.Hour)
+		dedup.seenAlerts["dataminr:alert-old-1"] = time.Now().Add(-time.Hour)
+		dedup.seenAlerts["dataminr:alert-old-2"] = time.Now().Add(-2 * time.Hour)
 		dedup.mu.Unlock()
 
 		// Run cleanup
@@ -159,6 +178,7 @@ func TestDeduplicator(t *testing.T) {
 
 	t.Run("stop waits for cleanup goroutine", func(t *testing.T) {
 		api := plugintest.NewAPI(t)
+		mockAlertClaims(api)
 		client := pluginapi.NewClient(api, &plugintest.Driver{})
 
 		dedup := NewDeduplicator(client)
@@ -180,6 +200,7 @@ func TestDeduplicator(t *testing.T) {
 
 	t.Run("concurrent access is safe", func(t *testing.T) {
 		api := plugintest.NewAPI(t)
+		mockAlertClaims(api)
 		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 		client := pluginapi.NewClient(api, &plugintest.Driver{})
 
@@ -211,3 +232,360 @@ func TestDeduplicator(t *testing.T) {
 		// If we get here without a panic, concurrent access is safe
 	})
 }
+
+func TestDeduplicator_ClusterClaims(t *testing.T) {
+	t.Run("claims new alerts atomically with an expiry", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("KVSetWithOptions", claimKey("dataminr:alert-1"), mock.Anything, mock.MatchedBy(func(opts model.PluginKVSetOptions) bool {
+			return opts.Atomic && opts.OldValue == nil && opts.ExpireInSeconds == int64(DeduplicationCacheTTL/time.Second)
+		})).Return(true, nil).Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		dedup := NewDeduplicator(client)
+		defer dedup.Stop()
+
+		assert.True(t, dedup.RecordAlert("dataminr", "alert-1"))
+
+		// Alerts already seen by this node aren't claimed again
+		assert.False(t, dedup.RecordAlert("dataminr", "alert-1"))
+	})
+
+	t.Run("alert claimed by another node is a duplicate", func(t *testing.T) {
+		otherClaim, err := time.Now().Add(DeduplicationCacheTTL).MarshalText()
+		require.NoError(t, err)
+
+		api := plugintest.NewAPI(t)
+		api.On("KVSetWithOptions", claimKey("dataminr:alert-1"), mock.Anything, mock.Anything).Return(false, nil).Once()
+		api.On("KVGet", claimKey("dataminr:alert-1")).Return(otherClaim, nil).Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		dedup := NewDeduplicator(client)
+		defer dedup.Stop()
+
+		assert.False(t, dedup.RecordAlert("dataminr", "alert-1"))
+		assert.False(t, dedup.RecordAlert("dataminr", "alert-1"))
+	})
+
+	t.Run("a longer TTL extends the claim of another node", func(t *testing.T) {
+		otherClaim, err := time.Now().Add(2 * time.Hour).MarshalText()
+		require.NoError(t, err)
+
+		api := plugintest.NewAPI(t)
+		api.On("KVSetWithOptions", claimKey("dataminr:alert-1"), mock.Anything, mock.MatchedBy(func(opts model.PluginKVSetOptions) bool {
+			return opts.OldValue == nil
+		})).Return(false, nil).Once()
+		api.On("KVGet", claimKey("dataminr:alert-1")).Return(otherClaim, nil).Once()
+		api.On("KVSetWithOptions", claimKey("dataminr:alert-1"), mock.Anything, mock.MatchedBy(func(opts model.PluginKVSetOptions) bool {
+			return opts.Atomic && string(opts.OldValue) == string(otherClaim) && opts.ExpireInSeconds == int64((48*time.Hour)/time.Second)
+		})).Return(true, nil).Once()
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		dedup := NewDeduplicator(client)
+		defer dedup.Stop()
+
+		assert.False(t, dedup.ForBackend("long", 48*time.Hour).RecordAlert("dataminr", "alert-1"))
+	})
+
+	t.Run("failed claim treats the alert as new", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).
+			Return(false, model.NewAppError("KVSetWithOptions", "kv_failed", nil, "", http.StatusInternalServerError)).Once()
+		api.On("LogWarn", "Failed to claim alert across the cluster, treating it as new", "alertId", "alert-1", "error", mock.Anything).Once()
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		dedup := NewDeduplicator(client)
+		defer dedup.Stop()
+
+		assert.True(t, dedup.RecordAlert("dataminr", "alert-1"))
+	})
+
+	t.Run("forgotten alerts are released for another attempt", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("KVSetWithOptions", claimKey("dataminr:alert-1"), []byte(nil), model.PluginKVSetOptions{}).Return(true, nil).Once()
+		mockAlertClaims(api)
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		dedup := NewDeduplicator(client)
+		defer dedup.Stop()
+
+		assert.True(t, dedup.RecordAlert("dataminr", "alert-1"))
+		dedup.ForgetAlert("dataminr", "alert-1")
+		assert.True(t, dedup.RecordAlert("dataminr", "alert-1"))
+	})
+
+	t.Run("seen alerts are looked up without being claimed", func(t *testing.T) {
+		api := plugintest.NewAPI(t)
+		api.On("KVGet", claimKey("dataminr:alert-1")).Return(nil, nil).Once()
+		api.On("KVGet", claimKey("dataminr:alert-2")).Return([]byte("2024-01-01T00:00:00Z"), nil).Once()
+		mockAlertClaims(api)
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+		dedup := NewDeduplicator(client)
+		defer dedup.Stop()
+
+		assert.False(t, dedup.IsSeen("dataminr", "alert-1"))
+		assert.True(t, dedup.IsSeen("dataminr", "alert-2"), "alerts claimed by another node are seen")
+
+		// Alerts recorded by this node are seen without a KV lookup
+		assert.True(t, dedup.RecordAlert("dataminr", "alert-1"))
+		assert.True(t, dedup.IsSeen("dataminr", "alert-1"))
+	})
+
+	t.Run("claim keys stay within the KV key length limit", func(t *testing.T) {
+		key := claimKey("dataminr:" + strings.Repeat("a", 500))
+		assert.LessOrEqual(t, len(key), model.KeyValueKeyMaxRunes)
+		assert.NotEqual(t, key, claimKey("dataminr:alert-2"))
+	})
+}
+
+func similarAlert(backendID, backendName, alertID string, eventTime time.Time, location *backend.Location) backend.Alert {
+	return backend.Alert{
+		BackendID:   backendID,
+		BackendName: backendName,
+		AlertID:     alertID,
+		Headline:    "Explosion reported near Central Station",
+		EventTime:   eventTime,
+		Location:    location,
+	}
+}
+
+func TestDeduplicator_ClaimContent(t *testing.T) {
+	eventTime := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	station := &backend.Location{Latitude: 40.7527, Longitude: -73.9772}
+
+	newDedup := func(t *testing.T) *Deduplicator {
+		api := &plugintest.API{}
+		mockMemoryKV(api)
+		dedup := NewDeduplicator(pluginapi.NewClient(api, &plugintest.Driver{}))
+		t.Cleanup(dedup.Stop)
+		return dedup
+	}
+
+	t.Run("similar alert from another backend is collapsed", func(t *testing.T) {
+		dedup := newDedup(t)
+
+		first := similarAlert("backend-a", "Backend A", "alert-a", eventTime, station)
+		require.Nil(t, dedup.ClaimContent(first, "channel-1"))
+		assert.Empty(t, dedup.SetContentPost(first, "channel-1", "post-a"))
+
+		second := similarAlert("backend-b", "Backend B", "alert-b", eventTime.Add(10*time.Minute), &backend.Location{Latitude: 40.7580, Longitude: -73.9855})
+		second.Headline = "EXPLOSION reported near Central-Station!"
+		match := dedup.ClaimContent(second, "channel-1")
+		require.NotNil(t, match)
+		assert.Equal(t, "post-a", match.PostID)
+		assert.Equal(t, "Backend A", match.BackendName)
+		assert.Equal(t, []string{"Backend B"}, match.MatchedBackends)
+	})
+
+	t.Run("headlines sharing most words are collapsed", func(t *testing.T) {
+		dedup := newDedup(t)
+
+		first := similarAlert("backend-a", "Backend A", "alert-a", eventTime, nil)
+		first.Headline = "Explosion reported near Central Station in Manhattan"
+		require.Nil(t, dedup.ClaimContent(first, "channel-1"))
+
+		second := similarAlert("backend-b", "Backend B", "alert-b", eventTime, nil)
+		second.Headline = "Explosion reported near Central Station, Manhattan"
+		assert.NotNil(t, dedup.ClaimContent(second, "channel-1"))
+	})
+
+	t.Run("different headlines are not collapsed", func(t *testing.T) {
+		dedup := newDedup(t)
+
+		require.Nil(t, dedup.ClaimContent(similarAlert("backend-a", "Backend A", "alert-a", eventTime, nil), "channel-1"))
+		other := similarAlert("backend-b", "Backend B", "alert-b", eventTime, nil)
+		other.Headline = "Fire reported near Central Station"
+		assert.Nil(t, dedup.ClaimContent(other, "channel-1"))
+	})
+
+	t.Run("alerts posted by another node are collapsed", func(t *testing.T) {
+		api := &plugintest.API{}
+		mockMemoryKV(api)
+		client := pluginapi.NewClient(api, &plugintest.Driver{})
+		node1 := NewDeduplicator(client)
+		defer node1.Stop()
+		node2 := NewDeduplicator(client)
+		defer node2.Stop()
+
+		first := similarAlert("backend-a", "Backend A", "alert-a", eventTime, nil)
+		require.Nil(t, node1.ClaimContent(first, "channel-1"))
+		node1.SetContentPost(first, "channel-1", "post-a")
+
+		match := node2.ClaimContent(similarAlert("backend-b", "Backend B", "alert-b", eventTime, nil), "channel-1")
+		require.NotNil(t, match)
+		assert.Equal(t, "post-a", match.PostID)
+	})
+
+	t.Run("alerts are posted when the contents can't be loaded", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", "dedup_content_channel-1").Return(nil, model.NewAppError("KVGet", "kv_failed", nil, "", http.StatusInternalServerError))
+		api.On("LogWarn", "Failed to check for similar alerts, posting the alert", "alertId", "alert-a", "channelId", "channel-1", "error", mock.Anything).Once()
+		dedup := NewDeduplicator(pluginapi.NewClient(api, &plugintest.Driver{}))
+		defer dedup.Stop()
+
+		assert.Nil(t, dedup.ClaimContent(similarAlert("backend-a", "Backend A", "alert-a", eventTime, nil), "channel-1"))
+		api.AssertExpectations(t)
+	})
+
+	t.Run("alerts from the same backend are not collapsed", func(t *testing.T) {
+		dedup := newDedup(t)
+
+		require.Nil(t, dedup.ClaimContent(similarAlert("backend-a", "Backend A", "alert-1", eventTime, nil), "channel-1"))
+		assert.Nil(t, dedup.ClaimContent(similarAlert("backend-a", "Backend A", "alert-2", eventTime, nil), "channel-1"))
+	})
+
+	t.Run("different channels are not collapsed", func(t *testing.T) {
+		dedup := newDedup(t)
+
+		require.Nil(t, dedup.ClaimContent(similarAlert("backend-a", "Backend A", "alert-a", eventTime, nil), "channel-1"))
+		assert.Nil(t, dedup.ClaimContent(similarAlert("backend-b", "Backend B", "alert-b", eventTime, nil), "channel-2"))
+	})
+
+	t.Run("event times outside the window are not collapsed", func(t *testing.T) {
+		dedup := newDedup(t)
+
+		require.Nil(t, dedup.ClaimContent(similarAlert("backend-a", "Backend A", "alert-a", eventTime, nil), "channel-1"))
+		late := similarAlert("backend-b", "Backend B", "alert-b", eventTime.Add(SimilarAlertWindow+time.Minute), nil)
+		assert.Nil(t, dedup.ClaimContent(late, "channel-1"))
+	})
+
+	t.Run("distant locations are not collapsed", func(t *testing.T) {
+		dedup := newDedup(t)
+
+		require.Nil(t, dedup.ClaimContent(similarAlert("backend-a", "Backend A", "alert-a", eventTime, station), "channel-1"))
+		boston := &backend.Location{Latitude: 42.3601, Longitude: -71.0589}
+		assert.Nil(t, dedup.ClaimContent(similarAlert("backend-b", "Backend B", "alert-b", eventTime, boston), "channel-1"))
+	})
+
+	t.Run("matches while posting are returned when the post is recorded", func(t *testing.T) {
+		dedup := newDedup(t)
+
+		first := similarAlert("backend-a", "Backend A", "alert-a", eventTime, nil)
+		require.Nil(t, dedup.ClaimContent(first, "channel-1"))
+
+		match := dedup.ClaimContent(similarAlert("backend-b", "Backend B", "alert-b", eventTime, nil), "channel-1")
+		require.NotNil(t, match)
+		assert.Empty(t, match.PostID)
+
+		assert.Equal(t, []string{"Backend B"}, dedup.SetContentPost(first, "channel-1", "post-a"))
+	})
+
+	t.Run("released content can be claimed again", func(t *testing.T) {
+		dedup := newDedup(t)
+
+		first := similarAlert("backend-a", "Backend A", "alert-a", eventTime, nil)
+		require.Nil(t, dedup.ClaimContent(first, "channel-1"))
+		dedup.ReleaseContent(first, "channel-1")
+
+		assert.Nil(t, dedup.ClaimContent(similarAlert("backend-b", "Backend B", "alert-b", eventTime, nil), "channel-1"))
+	})
+}
+
+func TestNormalizeHeadline(t *testing.T) {
+	assert.Equal(t, "explosion reported near central station", normalizeHeadline("  Explosion reported near Central-Station!! "))
+	assert.Equal(t, "", normalizeHeadline("--- ..."))
+}
+
+func TestHeadlineSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, headlineSimilarity("flooding in houston", "houston flooding in"))
+	assert.InDelta(t, 0.75, headlineSimilarity("flooding in downtown houston", "flooding in houston"), 0.001)
+	assert.Zero(t, headlineSimilarity("flooding in houston", "wildfire near austin"))
+	assert.Zero(t, headlineSimilarity("", ""))
+}
+
+func TestDistanceKm(t *testing.T) {
+	newYork := &backend.Location{Latitude: 40.7128, Longitude: -74.0060}
+	boston := &backend.Location{Latitude: 42.3601, Longitude: -71.0589}
+
+	assert.InDelta(t, 306, distanceKm(newYork, boston), 5)
+	assert.InDelta(t, 0, distanceKm(newYork, newYork), 0.001)
+}
+
+func TestDeduplicator_BackendTTL(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	claims := make(map[string][]byte)
+	claimExpiries := make(map[string]time.Duration)
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return claims[key], nil
+	})
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+			if options.Atomic && !bytes.Equal(claims[key], options.OldValue) {
+				return false, nil
+			}
+			claims[key] = value
+			claimExpiries[key] = time.Duration(options.ExpireInSeconds) * time.Second
+			return true, nil
+		})
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	dedup := NewDeduplicator(client)
+	defer dedup.Stop()
+
+	shortTTL := dedup.ForBackend("short", 2*time.Hour)
+	longTTL := dedup.ForBackend("long", 48*time.Hour)
+
+	// Alerts are kept for the TTL of the backend that recorded them
+	start := time.Now()
+	assert.True(t, shortTTL.RecordAlert("dataminr", "alert-1"))
+	assert.True(t, shortTTL.RecordAlert("dataminr", "alert-2"))
+	assert.WithinDuration(t, start.Add(2*time.Hour), dedup.seenAlerts["dataminr:alert-1"], time.Minute)
+	assert.Equal(t, 2*time.Hour, claimExpiries[claimKey("dataminr:alert-1")])
+
+	// A duplicate from a backend with a longer TTL extends the expiry, on this node and
+	// in the cluster claim
+	assert.False(t, longTTL.RecordAlert("dataminr", "alert-2"))
+	assert.WithinDuration(t, start.Add(48*time.Hour), dedup.seenAlerts["dataminr:alert-2"], time.Minute)
+	assert.Equal(t, 48*time.Hour, claimExpiries[claimKey("dataminr:alert-2")])
+	assert.Equal(t, 2*time.Hour, claimExpiries[claimKey("dataminr:alert-1")])
+
+	// ... and a shorter TTL never shortens it
+	assert.False(t, shortTTL.RecordAlert("dataminr", "alert-2"))
+	assert.WithinDuration(t, start.Add(48*time.Hour), dedup.seenAlerts["dataminr:alert-2"], time.Minute)
+	assert.Equal(t, 48*time.Hour, claimExpiries[claimKey("dataminr:alert-2")])
+}
+
+func TestDeduplicator_Metrics(t *testing.T) {
+	api := plugintest.NewAPI(t)
+	mockAlertClaims(api)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	client := pluginapi.NewClient(api, &plugintest.Driver{})
+
+	dedup := NewDeduplicator(client)
+	defer dedup.Stop()
+
+	weather := dedup.ForBackend("weather", DeduplicationCacheTTL)
+	security := dedup.ForBackend("security", DeduplicationCacheTTL)
+
+	assert.True(t, weather.RecordAlert("dataminr", "alert-1"))
+	assert.True(t, weather.RecordAlert("dataminr", "alert-2"))
+	assert.False(t, weather.RecordAlert("dataminr", "alert-1"))
+	assert.False(t, security.RecordAlert("dataminr", "alert-2"))
+	assert.True(t, dedup.RecordAlert("dataminr", "alert-3"), "lookups without a backend are not counted")
+
+	metrics := dedup.Metrics()
+	assert.Equal(t, 3, metrics.SeenAlerts)
+	assert.True(t, metrics.LastCleanup.IsZero())
+	assert.Equal(t, map[string]DedupLookups{
+		"weather":  {Hits: 1, Misses: 2},
+		"security": {Hits: 1},
+	}, metrics.Backends)
+	assert.InDelta(t, 1.0/3, metrics.Backends["weather"].HitRate(), 0.001)
+	assert.Zero(t, DedupLookups{}.HitRate())
+
+	// Evictions count the expired alert IDs of each cleanup
+	dedup.mu.Lock()
+	dedup.seenAlerts["dataminr:alert-1"] = time.Now().Add(-time.Hour)
+	dedup.mu.Unlock()
+
+	dedup.cleanup()
+	dedup.cleanup()
+
+	metrics = dedup.Metrics()
+	assert.Equal(t, 2, metrics.SeenAlerts)
+	assert.WithinDuration(t, time.Now(), metrics.LastCleanup, time.Minute)
+	assert.Zero(t, metrics.LastEvictions)
+	assert.Equal(t, 1, metrics.TotalEvictions)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/followup"
+)
+
+const (
+	// deferredMentionsJobID is the cluster job ID for posting deferred mentions
+	deferredMentionsJobID = "dataminr_deferred_mentions"
+
+	// deferredMentionsCheckInterval is how often deferred mentions are checked
+	deferredMentionsCheckInterval = time.Minute
+)
+
+// DeferredMentions replies to alert posts with the mentions of users who were in Do Not
+// Disturb or out of office when the alert was posted, once their deferral delay has passed.
+// It runs as a cluster job so each reply is posted by a single node.
+type DeferredMentions struct {
+	api       plugin.API
+	scheduler *followup.Scheduler
+	post      func(mention followup.Mention) error
+	job       *cluster.Job
+}
+
+// NewDeferredMentions creates a new deferred mention job
+func NewDeferredMentions(api plugin.API, scheduler *followup.Scheduler, post func(mention followup.Mention) error) *DeferredMentions {
+	return &DeferredMentions{
+		api:       api,
+		scheduler: scheduler,
+		post:      post,
+	}
+}
+
+// Start schedules the periodic cluster-aware check for due mentions
+func (d *DeferredMentions) Start() error {
+	job, err := cluster.Schedule(d.api, deferredMentionsJobID, cluster.MakeWaitForInterval(deferredMentionsCheckInterval), d.check)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule deferred mentions job")
+	}
+
+	d.job = job
+	return nil
+}
+
+// Stop cancels the check for due mentions
+func (d *DeferredMentions) Stop() {
+	if d.job == nil {
+		return
+	}
+
+	if err := d.job.Close(); err != nil {
+		d.api.LogWarn("Failed to close deferred mentions job", "error", err.Error())
+	}
+	d.job = nil
+}
+
+// check posts the mentions whose deferral delay has passed. Each mention is removed once its
+// reply is posted; a reply that fails is retried later, up to followup.MaxAttempts times.
+func (d *DeferredMentions) check() {
+	due, err := d.scheduler.Due()
+	if err != nil {
+		d.api.LogError("Failed to check deferred mentions", "error", err.Error())
+		return
+	}
+
+	for _, mention := range due {
+		if err := d.post(mention); err != nil {
+			d.retry(mention, err)
+			continue
+		}
+
+		if err := d.scheduler.Complete(mention); err != nil {
+			d.api.LogError("Failed to remove posted deferred mentions", "postId", mention.PostID, "alertId", mention.AlertID, "error", err.Error())
+		}
+	}
+}
+
+// retry schedules a mention whose reply failed to post again, or drops it after too many attempts
+func (d *DeferredMentions) retry(mention followup.Mention, postErr error) {
+	retried, err := d.scheduler.Retry(mention)
+	if err != nil {
+		d.api.LogError("Failed to reschedule deferred mentions", "postId", mention.PostID, "alertId", mention.AlertID, "error", err.Error())
+		return
+	}
+
+	if retried {
+		d.api.LogWarn("Failed to post deferred mentions, retrying later", "postId", mention.PostID, "alertId", mention.AlertID, "error", postErr.Error())
+		return
+	}
+	d.api.LogError("Failed to post deferred mentions", "postId", mention.PostID, "alertId", mention.AlertID, "attempts", followup.MaxAttempts, "error", postErr.Error())
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/followup"
+)
+
+func TestDeferredMentions_Check(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	mockMemoryKV(api)
+
+	scheduler := followup.NewScheduler(api)
+	require.NoError(t, scheduler.Schedule(followup.Mention{PostID: "post-due", AlertID: "alert-1", Usernames: []string{"alice"}, DueAt: time.Now().Add(-time.Minute)}))
+	require.NoError(t, scheduler.Schedule(followup.Mention{PostID: "post-failed", AlertID: "alert-2", Usernames: []string{"bob"}, DueAt: time.Now().Add(-time.Minute)}))
+	require.NoError(t, scheduler.Schedule(followup.Mention{PostID: "post-later", AlertID: "alert-3", Usernames: []string{"carol"}, DueAt: time.Now().Add(time.Hour)}))
+
+	api.On("LogWarn", "Failed to post deferred mentions, retrying later", "postId", "post-failed", "alertId", "alert-2", "error", "post failed").Once()
+
+	var posted []string
+	jobs := NewDeferredMentions(api, scheduler, func(mention followup.Mention) error {
+		if mention.PostID == "post-failed" {
+			return errors.New("post failed")
+		}
+		posted = append(posted, mention.PostID)
+		return nil
+	})
+	jobs.check()
+
+	assert.Equal(t, []string{"post-due"}, posted)
+
+	// Posted mentions are removed, and the failed one waits for its retry
+	jobs.check()
+	assert.Equal(t, []string{"post-due"}, posted)
+
+	due, err := scheduler.Due()
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
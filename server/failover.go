@@ -0,0 +1,301 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// kvKeyActiveFailovers stores the backends whose warm standby is running, keyed by backend ID
+	kvKeyActiveFailovers = "failover_active"
+
+	// failoverCheckInterval is how often backends with failover settings are checked
+	failoverCheckInterval = 30 * time.Second
+
+	// maxFailoverUpdateAttempts bounds the compare-and-set retries of an active failovers update
+	maxFailoverUpdateAttempts = 5
+)
+
+// failoverState records why and when a backend failed over to its warm standby
+type failoverState struct {
+	StartedAt time.Time `json:"startedAt"`
+	Reason    string    `json:"reason"`
+}
+
+// FailoverMonitor runs the warm standby of backends with failover settings. A backend fails
+// over when it is auto-disabled or keeps failing to authenticate, and fails back once it
+// polls successfully again. The standby posts to the same channels with the same filters,
+// and shares the deduplicator, so alerts seen by both are posted once.
+// Active failovers are kept in the KV store so every cluster node runs the standby and
+// failovers survive restarts; the standby's polling job runs on a single node. Every node
+// checks the backends, and updates the active failovers with compare-and-set so each
+// failover and recovery is announced once.
+type FailoverMonitor struct {
+	api      plugin.API
+	registry *backend.Registry
+
+	// backends returns the configured backends
+	backends func() []backend.Config
+
+	// start creates, registers and starts a standby backend
+	start func(config backend.Config) StartupResult
+
+	// notify sends a message to the admins subscribed to backend state changes
+	notify func(message string)
+
+	// mu serializes checks; started holds the configuration of each standby started on
+	// this node, keyed by primary backend ID
+	mu      sync.Mutex
+	started map[string]backend.Config
+
+	done chan struct{}
+}
+
+// NewFailoverMonitor creates a new failover monitor
+func NewFailoverMonitor(api plugin.API, registry *backend.Registry, backends func() []backend.Config, start func(backend.Config) StartupResult, notify func(string)) *FailoverMonitor {
+	return &FailoverMonitor{
+		api:      api,
+		registry: registry,
+		backends: backends,
+		start:    start,
+		notify:   notify,
+		started:  make(map[string]backend.Config),
+	}
+}
+
+// Start checks backends now, starting the standbys of active failovers, and then
+// periodically. Every node checks since every node registers the standby backends.
+func (m *FailoverMonitor) Start() {
+	m.done = make(chan struct{})
+	m.Check()
+
+	go func(done chan struct{}) {
+		ticker := time.NewTicker(failoverCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				m.Check()
+			}
+		}
+	}(m.done)
+}
+
+// Stop ends the periodic checks. Running standbys are stopped with the other backends.
+func (m *FailoverMonitor) Stop() {
+	if m.done == nil {
+		return
+	}
+	close(m.done)
+	m.done = nil
+}
+
+// Check fails backends over to their standby and back according to their status, and
+// keeps the standbys of active failovers running with their current configuration
+func (m *FailoverMonitor) Check() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	configs := m.backends()
+	var failedOver, recoveredConfigs []backend.Config
+	active, err := m.updateActive(func(active map[string]failoverState) bool {
+		failedOver, recoveredConfigs = nil, nil
+		changed := false
+		configured := make(map[string]bool)
+		for _, cfg := range configs {
+			if cfg.Failover == nil {
+				continue
+			}
+			configured[cfg.ID] = true
+
+			primary := m.registry.Get(cfg.ID)
+			if primary == nil {
+				continue
+			}
+			state, isActive := active[cfg.ID]
+			status := primary.GetStatus()
+			switch {
+			case !isActive && authFailing(status, cfg.Failover.AuthFailures(cfg.MaxFailures())):
+				active[cfg.ID] = failoverState{StartedAt: time.Now(), Reason: fmt.Sprintf("authentication failed in %d consecutive polls", status.ConsecutiveFailures)}
+				failedOver = append(failedOver, cfg)
+				changed = true
+			case isActive && recovered(status, state.StartedAt):
+				delete(active, cfg.ID)
+				recoveredConfigs = append(recoveredConfigs, cfg)
+				changed = true
+			}
+		}
+
+		// Forget failovers of backends removed or without failover settings
+		for id := range active {
+			if !configured[id] {
+				delete(active, id)
+				changed = true
+			}
+		}
+		return changed
+	})
+	if err != nil {
+		m.api.LogError("Failed to update active failovers", "error", err.Error())
+		return
+	}
+
+	// Only the node whose update was saved announces the changes
+	for _, cfg := range failedOver {
+		m.announceFailover(cfg, active[cfg.ID])
+	}
+	for _, cfg := range recoveredConfigs {
+		m.api.LogInfo("Backend recovered, stopping its standby", "id", cfg.ID, "name", cfg.Name)
+		m.notify(fmt.Sprintf(":white_check_mark: **%s** is polling successfully again. Its standby was stopped.", cfg.Name))
+	}
+
+	configured := make(map[string]bool)
+	for _, cfg := range configs {
+		if cfg.Failover == nil {
+			continue
+		}
+		configured[cfg.ID] = true
+
+		if _, isActive := active[cfg.ID]; isActive {
+			m.ensureStandby(cfg)
+		} else {
+			m.stopStandby(cfg.ID, "backend has not failed over")
+		}
+	}
+	for id := range m.started {
+		if !configured[id] {
+			m.stopStandby(id, "failover removed from configuration")
+		}
+	}
+}
+
+// FailOver starts the standby of an auto-disabled backend. Backends without failover
+// settings or already failed over are left as is.
+func (m *FailoverMonitor) FailOver(backendID, reason string) {
+	cfg, found := findBackendConfigByID(m.backends(), backendID)
+	if !found || cfg.Failover == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	announce := false
+	active, err := m.updateActive(func(active map[string]failoverState) bool {
+		_, isActive := active[backendID]
+		announce = !isActive
+		if isActive {
+			return false
+		}
+		active[backendID] = failoverState{StartedAt: time.Now(), Reason: reason}
+		return true
+	})
+	if err != nil {
+		m.api.LogError("Failed to update active failovers", "error", err.Error())
+		return
+	}
+
+	if announce {
+		m.announceFailover(cfg, active[backendID])
+	}
+	m.ensureStandby(cfg)
+}
+
+// announceFailover logs a failover and notifies the admins
+func (m *FailoverMonitor) announceFailover(cfg backend.Config, state failoverState) {
+	m.api.LogWarn("Backend failed over to its standby", "id", cfg.ID, "name", cfg.Name, "reason", state.Reason)
+	m.notify(fmt.Sprintf(":rotating_light: **%s** failed over to its standby credentials: %s. Alerts are polled by **%s (standby)** until it polls successfully again.",
+		cfg.Name, state.Reason, cfg.Name))
+}
+
+// ensureStandby starts the standby of a backend, restarting it if the backend's
+// configuration changed since it was started
+func (m *FailoverMonitor) ensureStandby(primary backend.Config) {
+	standby, _ := backend.StandbyConfig(primary)
+
+	if running := m.registry.Get(standby.ID); running != nil {
+		if previous, started := m.started[primary.ID]; started && reflect.DeepEqual(previous, standby) {
+			return
+		}
+		unregisterBackend(m.registry, m.api, standby.ID, "standby configuration changed")
+	}
+
+	delete(m.started, primary.ID)
+	if result := m.start(standby); result.Outcome != startupStarted {
+		m.api.LogError("Failed to start standby backend", "id", standby.ID, "name", standby.Name, "error", result.Error)
+		return
+	}
+	m.started[primary.ID] = standby
+}
+
+// stopStandby stops the standby of a backend if it runs
+func (m *FailoverMonitor) stopStandby(primaryID, reason string) {
+	delete(m.started, primaryID)
+	if m.registry.Get(backend.StandbyID(primaryID)) != nil {
+		unregisterBackend(m.registry, m.api, backend.StandbyID(primaryID), reason)
+	}
+}
+
+// authFailing reports whether an enabled backend failed to authenticate in at least
+// threshold consecutive polls
+func authFailing(status backend.Status, threshold int) bool {
+	return status.Enabled && !status.IsAuthenticated && status.ConsecutiveFailures >= threshold
+}
+
+// recovered reports whether a backend polled successfully since it failed over
+func recovered(status backend.Status, failedOverAt time.Time) bool {
+	return status.Enabled && status.ConsecutiveFailures == 0 && status.LastSuccessTime.After(failedOverAt)
+}
+
+// updateActive applies a change to the active failovers with compare-and-set, so that when
+// several cluster nodes check the same backends only one of them saves, and announces, each
+// failover and recovery. The change is applied again to the failovers saved by another node
+// meanwhile; it returns whether it changed them. Returns the active failovers after the change.
+func (m *FailoverMonitor) updateActive(change func(active map[string]failoverState) bool) (map[string]failoverState, error) {
+	for attempt := 0; attempt < maxFailoverUpdateAttempts; attempt++ {
+		data, appErr := m.api.KVGet(kvKeyActiveFailovers)
+		if appErr != nil {
+			return nil, errors.Wrap(appErr, "failed to get active failovers")
+		}
+
+		active := make(map[string]failoverState)
+		if data != nil {
+			if err := json.Unmarshal(data, &active); err != nil {
+				return nil, errors.Wrap(err, "failed to parse active failovers")
+			}
+		}
+		if !change(active) {
+			return active, nil
+		}
+
+		newData, err := json.Marshal(active)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode active failovers")
+		}
+		saved, appErr := m.api.KVSetWithOptions(kvKeyActiveFailovers, newData, model.PluginKVSetOptions{Atomic: true, OldValue: data})
+		if appErr != nil {
+			return nil, errors.Wrap(appErr, "failed to save active failovers")
+		}
+		if saved {
+			return active, nil
+		}
+	}
+	return nil, errors.New("failed to save active failovers: too many concurrent updates")
+}
+
+// getActive returns the active failovers, keyed by backend ID
+func (m *FailoverMonitor) getActive() (map[string]failoverState, error) {
+	return m.updateActive(func(map[string]failoverState) bool { return false })
+}
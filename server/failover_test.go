@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// newFailoverTestMonitor creates a failover monitor for a backend with failover settings whose
+// standbys are fake backends. Returns the monitor, the primary backend, the started standby
+// configurations and the notifications sent.
+func newFailoverTestMonitor(t *testing.T) (*FailoverMonitor, *fakeBackend, *[]backend.Config, *[]string) {
+	api := &plugintest.API{}
+	mockMemoryKV(api)
+	api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	registry := backend.NewRegistry()
+	primary := &fakeBackend{id: "backend-1", name: "Weather Watch", status: backend.Status{Enabled: true, IsAuthenticated: true}}
+	require.NoError(t, registry.Register(primary))
+
+	configs := []backend.Config{
+		{
+			ID:        "backend-1",
+			Name:      "Weather Watch",
+			Type:      "dataminr",
+			URL:       "https://api.example.com",
+			APIId:     "primary-id",
+			ChannelID: "channel-1",
+			Failover:  &backend.FailoverSettings{APIId: "standby-id", APIKey: "standby-key", AuthFailureThreshold: 2},
+		},
+		{ID: "backend-2", Name: "No Failover", Type: "dataminr", ChannelID: "channel-2"},
+	}
+
+	var started []backend.Config
+	var notifications []string
+	monitor := NewFailoverMonitor(api, registry,
+		func() []backend.Config { return configs },
+		func(cfg backend.Config) StartupResult {
+			started = append(started, cfg)
+			if err := registry.Register(&fakeBackend{id: cfg.ID, name: cfg.Name}); err != nil {
+				return StartupResult{ID: cfg.ID, Outcome: startupFailed, Error: err.Error()}
+			}
+			return StartupResult{ID: cfg.ID, Outcome: startupStarted}
+		},
+		func(message string) { notifications = append(notifications, message) },
+	)
+	return monitor, primary, &started, &notifications
+}
+
+func TestFailoverMonitor_AuthFailures(t *testing.T) {
+	monitor, primary, started, notifications := newFailoverTestMonitor(t)
+
+	// A single failure is below the threshold
+	primary.status = backend.Status{Enabled: true, ConsecutiveFailures: 1}
+	monitor.Check()
+	assert.Empty(t, *started)
+	assert.Nil(t, monitor.registry.Get("backend-1-standby"))
+
+	// Failures with a valid token are not authentication failures
+	primary.status = backend.Status{Enabled: true, IsAuthenticated: true, ConsecutiveFailures: 4}
+	monitor.Check()
+	assert.Empty(t, *started)
+
+	primary.status = backend.Status{Enabled: true, ConsecutiveFailures: 2}
+	monitor.Check()
+	require.Len(t, *started, 1)
+	standby := (*started)[0]
+	assert.Equal(t, "backend-1-standby", standby.ID)
+	assert.Equal(t, "standby-id", standby.APIId)
+	assert.Equal(t, "channel-1", standby.ChannelID)
+	assert.NotNil(t, monitor.registry.Get("backend-1-standby"))
+	require.Len(t, *notifications, 1)
+	assert.Contains(t, (*notifications)[0], "**Weather Watch** failed over to its standby credentials: authentication failed in 2 consecutive polls")
+
+	// The running standby is kept
+	monitor.Check()
+	assert.Len(t, *started, 1)
+
+	// Fail back once the primary polls successfully
+	primary.status = backend.Status{Enabled: true, IsAuthenticated: true, LastSuccessTime: time.Now().Add(time.Second)}
+	monitor.Check()
+	assert.Nil(t, monitor.registry.Get("backend-1-standby"))
+	require.Len(t, *notifications, 2)
+	assert.Equal(t, ":white_check_mark: **Weather Watch** is polling successfully again. Its standby was stopped.", (*notifications)[1])
+
+	active, err := monitor.getActive()
+	require.NoError(t, err)
+	assert.Empty(t, active)
+}
+
+func TestFailoverMonitor_FailOver(t *testing.T) {
+	monitor, primary, started, notifications := newFailoverTestMonitor(t)
+
+	monitor.FailOver("backend-2", "it was disabled")
+	monitor.FailOver("unknown", "it was disabled")
+	assert.Empty(t, *started, "backends without failover settings are left as is")
+
+	monitor.FailOver("backend-1", "it was disabled after 5 consecutive failed polls")
+	require.Len(t, *started, 1)
+	require.Len(t, *notifications, 1)
+	assert.Contains(t, (*notifications)[0], "it was disabled after 5 consecutive failed polls")
+
+	monitor.FailOver("backend-1", "it was disabled after 5 consecutive failed polls")
+	assert.Len(t, *notifications, 1, "failing over again is a no-op")
+
+	// The standby keeps running while the primary is disabled
+	primary.status = backend.Status{Enabled: false, ConsecutiveFailures: 5}
+	monitor.Check()
+	assert.NotNil(t, monitor.registry.Get("backend-1-standby"))
+	assert.Len(t, *started, 1)
+
+	// A success from before the failover is not a recovery
+	primary.status = backend.Status{Enabled: true, LastSuccessTime: time.Now().Add(-time.Hour)}
+	monitor.Check()
+	assert.NotNil(t, monitor.registry.Get("backend-1-standby"))
+}
+
+func TestFailoverMonitor_ConcurrentNodes(t *testing.T) {
+	api := &plugintest.API{}
+	api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	// Another node saves the same failover between this node's read and its write
+	otherNode, err := json.Marshal(map[string]failoverState{"backend-1": {StartedAt: time.Now(), Reason: "authentication failed in 2 consecutive polls"}})
+	require.NoError(t, err)
+	store := map[string][]byte{}
+	api.On("KVGet", kvKeyActiveFailovers).Return(func(key string) ([]byte, *model.AppError) {
+		return store[key], nil
+	})
+	api.On("KVSetWithOptions", kvKeyActiveFailovers, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		store[kvKeyActiveFailovers] = otherNode
+	}).Return(false, nil).Once()
+
+	registry := backend.NewRegistry()
+	require.NoError(t, registry.Register(&fakeBackend{id: "backend-1", name: "Weather Watch", status: backend.Status{Enabled: true, ConsecutiveFailures: 2}}))
+	configs := []backend.Config{{
+		ID:       "backend-1",
+		Name:     "Weather Watch",
+		Type:     "dataminr",
+		Failover: &backend.FailoverSettings{APIId: "standby-id", APIKey: "standby-key", AuthFailureThreshold: 2},
+	}}
+
+	var started []string
+	var notifications []string
+	monitor := NewFailoverMonitor(api, registry, func() []backend.Config { return configs }, func(cfg backend.Config) StartupResult {
+		started = append(started, cfg.ID)
+		require.NoError(t, registry.Register(&fakeBackend{id: cfg.ID, name: cfg.Name}))
+		return StartupResult{ID: cfg.ID, Outcome: startupStarted}
+	}, func(message string) { notifications = append(notifications, message) })
+
+	// The node runs the standby too, but only the node that saved the failover announces it
+	monitor.Check()
+	assert.Equal(t, []string{"backend-1-standby"}, started)
+	assert.Empty(t, notifications)
+	api.AssertNumberOfCalls(t, "KVSetWithOptions", 1)
+}
+
+func TestFailoverMonitor_ConfigurationChanges(t *testing.T) {
+	monitor, _, started, _ := newFailoverTestMonitor(t)
+	configs := monitor.backends()
+
+	monitor.FailOver("backend-1", "it was disabled")
+	require.Len(t, *started, 1)
+
+	// Changed backend settings restart the standby
+	configs[0].ChannelID = "channel-3"
+	monitor.Check()
+	require.Len(t, *started, 2)
+	assert.Equal(t, "channel-3", (*started)[1].ChannelID)
+
+	// Removing the failover settings stops the standby and forgets the failover
+	configs[0].Failover = nil
+	monitor.Check()
+	assert.Nil(t, monitor.registry.Get("backend-1-standby"))
+
+	active, err := monitor.getActive()
+	require.NoError(t, err)
+	assert.Empty(t, active)
+}
+
+func TestFailoverMonitor_ResumesAfterRestart(t *testing.T) {
+	monitor, _, _, _ := newFailoverTestMonitor(t)
+	monitor.FailOver("backend-1", "it was disabled")
+
+	// A new monitor on the same KV store restarts the standby
+	registry := backend.NewRegistry()
+	require.NoError(t, registry.Register(&fakeBackend{id: "backend-1", name: "Weather Watch", status: backend.Status{Enabled: false}}))
+
+	var started []string
+	restarted := NewFailoverMonitor(monitor.api, registry, monitor.backends, func(cfg backend.Config) StartupResult {
+		started = append(started, cfg.ID)
+		require.NoError(t, registry.Register(&fakeBackend{id: cfg.ID, name: cfg.Name}))
+		return StartupResult{ID: cfg.ID, Outcome: startupStarted}
+	}, monitor.notify)
+
+	restarted.Start()
+	defer restarted.Stop()
+	assert.Equal(t, []string{"backend-1-standby"}, started)
+}
@@ -0,0 +1,169 @@
+// Package followup schedules the replies that mention users who were unavailable when an
+// alert was posted.
+package followup
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+)
+
+const (
+	// pendingKey holds the deferred mentions that have not been posted yet
+	pendingKey = "followup_pending"
+
+	// maxUpdateAttempts is how often a pending list update is retried when another node changed it concurrently
+	maxUpdateAttempts = 5
+
+	// RetryDelay is how long a mention whose reply failed to post waits before the next attempt
+	RetryDelay = 5 * time.Minute
+
+	// MaxAttempts is how often posting the reply of a mention is attempted before it is dropped
+	MaxAttempts = 3
+)
+
+// Mention is a reply mentioning users in the thread of an alert post once it is due
+type Mention struct {
+	PostID    string    `json:"postId"`
+	ChannelID string    `json:"channelId"`
+	BackendID string    `json:"backendId"`
+	AlertID   string    `json:"alertId"`
+	Usernames []string  `json:"usernames"`
+	DueAt     time.Time `json:"dueAt"`
+
+	// Attempts counts the failed attempts to post the reply
+	Attempts int `json:"attempts,omitempty"`
+}
+
+// same reports whether two mentions are the same pending entry
+func (m Mention) same(other Mention) bool {
+	return m.PostID == other.PostID &&
+		m.AlertID == other.AlertID &&
+		m.DueAt.Equal(other.DueAt) &&
+		m.Attempts == other.Attempts
+}
+
+// Scheduler stores deferred mentions in the KV store until they are due.
+// Pending list updates use compare-and-set so multiple cluster nodes can schedule mentions concurrently.
+type Scheduler struct {
+	api plugin.API
+	now func() time.Time
+}
+
+// NewScheduler creates a new deferred mention scheduler
+func NewScheduler(api plugin.API) *Scheduler {
+	return &Scheduler{
+		api: api,
+		now: time.Now,
+	}
+}
+
+// Schedule holds back a mention until it is due
+func (s *Scheduler) Schedule(mention Mention) error {
+	return s.updatePending(func(pending []Mention) []Mention {
+		return append(pending, mention)
+	})
+}
+
+// Due returns the mentions that are due. They stay pending until Complete removes them once
+// their reply is posted, or Retry schedules them again, so a reply that fails isn't lost.
+func (s *Scheduler) Due() ([]Mention, error) {
+	pending, _, err := s.loadPending()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	var due []Mention
+	for _, mention := range pending {
+		if !now.Before(mention.DueAt) {
+			due = append(due, mention)
+		}
+	}
+	return due, nil
+}
+
+// Complete removes a mention whose reply was posted from the pending list
+func (s *Scheduler) Complete(mention Mention) error {
+	return s.updatePending(func(pending []Mention) []Mention {
+		return removeMention(pending, mention)
+	})
+}
+
+// Retry schedules a mention whose reply failed to post again after the retry delay. A mention
+// that failed MaxAttempts times is removed instead. Returns whether it was scheduled again.
+func (s *Scheduler) Retry(mention Mention) (bool, error) {
+	retried := mention
+	retried.Attempts++
+	retried.DueAt = s.now().Add(RetryDelay)
+	again := retried.Attempts < MaxAttempts
+
+	if err := s.updatePending(func(pending []Mention) []Mention {
+		remaining := removeMention(pending, mention)
+		if again && len(remaining) < len(pending) {
+			remaining = append(remaining, retried)
+		}
+		return remaining
+	}); err != nil {
+		return false, err
+	}
+	return again, nil
+}
+
+// removeMention returns the pending mentions without the given one
+func removeMention(pending []Mention, mention Mention) []Mention {
+	remaining := make([]Mention, 0, len(pending))
+	for _, entry := range pending {
+		if !entry.same(mention) {
+			remaining = append(remaining, entry)
+		}
+	}
+	return remaining
+}
+
+// loadPending returns the pending mention list and its stored value
+func (s *Scheduler) loadPending() ([]Mention, []byte, error) {
+	data, appErr := s.api.KVGet(pendingKey)
+	if appErr != nil {
+		return nil, nil, fmt.Errorf("failed to get deferred mentions: %w", appErr)
+	}
+
+	var pending []Mention
+	if data != nil {
+		if err := json.Unmarshal(data, &pending); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal deferred mentions: %w", err)
+		}
+	}
+	return pending, data, nil
+}
+
+// updatePending applies a change to the pending mention list with compare-and-set
+func (s *Scheduler) updatePending(update func([]Mention) []Mention) error {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		pending, oldData, err := s.loadPending()
+		if err != nil {
+			return err
+		}
+
+		newData, err := json.Marshal(update(pending))
+		if err != nil {
+			return fmt.Errorf("failed to marshal deferred mentions: %w", err)
+		}
+
+		saved, appErr := s.api.KVSetWithOptions(pendingKey, newData, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to save deferred mentions: %w", appErr)
+		}
+		if saved {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to save deferred mentions: too many concurrent updates")
+}
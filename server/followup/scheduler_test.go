@@ -0,0 +1,120 @@
+package followup
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newMemoryKVAPI returns a mock API backed by an in-memory KV store supporting atomic sets
+func newMemoryKVAPI() (*plugintest.API, map[string][]byte) {
+	store := make(map[string][]byte)
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return store[key], nil
+	})
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+			if options.Atomic && !bytes.Equal(store[key], options.OldValue) {
+				return false, nil
+			}
+			store[key] = value
+			return true, nil
+		})
+	return api, store
+}
+
+func TestScheduler_Due(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	api, _ := newMemoryKVAPI()
+	scheduler := NewScheduler(api)
+	scheduler.now = func() time.Time { return now }
+
+	require.NoError(t, scheduler.Schedule(Mention{PostID: "post-1", Usernames: []string{"alice"}, DueAt: now.Add(30 * time.Minute)}))
+	require.NoError(t, scheduler.Schedule(Mention{PostID: "post-2", Usernames: []string{"bob"}, DueAt: now.Add(time.Hour)}))
+
+	due, err := scheduler.Due()
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	now = now.Add(45 * time.Minute)
+	due, err = scheduler.Due()
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "post-1", due[0].PostID)
+	assert.Equal(t, []string{"alice"}, due[0].Usernames)
+
+	// Due mentions stay pending until their reply is posted
+	due, err = scheduler.Due()
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+
+	require.NoError(t, scheduler.Complete(due[0]))
+	due, err = scheduler.Due()
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	now = now.Add(time.Hour)
+	due, err = scheduler.Due()
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "post-2", due[0].PostID)
+}
+
+func TestScheduler_Retry(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	api, _ := newMemoryKVAPI()
+	scheduler := NewScheduler(api)
+	scheduler.now = func() time.Time { return now }
+
+	require.NoError(t, scheduler.Schedule(Mention{PostID: "post-1", Usernames: []string{"alice"}, DueAt: now}))
+
+	for attempt := 1; attempt < MaxAttempts; attempt++ {
+		due, err := scheduler.Due()
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+
+		retried, err := scheduler.Retry(due[0])
+		require.NoError(t, err)
+		assert.True(t, retried)
+
+		// The mention waits for the retry delay before it is due again
+		due, err = scheduler.Due()
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		now = now.Add(RetryDelay)
+	}
+
+	due, err := scheduler.Due()
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, MaxAttempts-1, due[0].Attempts)
+
+	// The last failed attempt drops the mention
+	retried, err := scheduler.Retry(due[0])
+	require.NoError(t, err)
+	assert.False(t, retried)
+
+	now = now.Add(RetryDelay)
+	due, err = scheduler.Due()
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestScheduler_RetriesConcurrentUpdates(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("KVGet", pendingKey).Return(nil, nil).Times(2)
+	api.On("KVSetWithOptions", pendingKey, mock.Anything, mock.Anything).Return(false, nil).Once()
+	api.On("KVSetWithOptions", pendingKey, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	require.NoError(t, NewScheduler(api).Schedule(Mention{PostID: "post-1"}))
+}
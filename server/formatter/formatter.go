@@ -26,37 +26,116 @@ const (
 	EmojiUnknown = "⚪"
 )
 
-// GetAlertTypeText returns the formatted alert type text with emoji
-func GetAlertTypeText(alertType string) string {
-	return fmt.Sprintf("%s **%s**", getAlertEmoji(alertType), strings.ToUpper(alertType))
+// trimOrder lists the fields of an alert attachment that may be trimmed when a post exceeds
+// the size limit, from the lowest priority to the highest. The headline, links, event time
+// and location are never trimmed.
+var trimOrder = []string{
+	"Additional Media",
+	"Alert Lists",
+	"Topics",
+	"Original Source Text",
+	"Translated Text",
+	"Machine Translated",
+	"Additional Context",
+	"Summary",
+}
+
+// TrimOrder returns the titles, in a locale, of the alert attachment fields that may be
+// trimmed to fit a post within the size limit, lowest priority first
+func TrimOrder(locale string) []string {
+	titles := make([]string, 0, len(trimOrder))
+	for _, title := range trimOrder {
+		titles = append(titles, translate(locale, title))
+	}
+	return titles
+}
+
+// Options controls optional formatting behavior.
+// The zero value produces the default formatting.
+type Options struct {
+	// Map adds map links and static map images for alerts with coordinates (nil disables)
+	Map *MapLinkBuilder
+
+	// Limits caps the source text length, media links and topics (the zero value applies the defaults)
+	Limits backend.ContentLimits
+
+	// Locale is the language of field titles and the event time format (empty means English)
+	Locale string
+
+	// Timezone shows event times in this timezone (nil keeps the alert's UTC offset)
+	Timezone *time.Location
+
+	// RelativeTime appends how long before Now the event occurred
+	RelativeTime bool
+
+	// Now is the time relative durations are measured from (zero uses the current time)
+	Now time.Time
+
+	// TopicStyles replace the alert type emoji and color of alerts with a matching topic or alert list
+	TopicStyles []backend.TopicStyle
+
+	// MediaItems is the number of media items shown as images; items after the first are
+	// shown by FormatMediaAttachments (0 or 1 embeds only the first item)
+	MediaItems int
+
+	// PostLink is the deep link to the alert's post, shown after the alert and public source
+	// links (empty omits it)
+	PostLink string
+}
+
+// FormatAlertTypeText returns the formatted alert type text of an alert, with the emoji of
+// its topic style if one matches
+func FormatAlertTypeText(alert backend.Alert, opts Options) string {
+	emoji := getAlertEmoji(alert.AlertType)
+	if style, matched := backend.MatchTopicStyle(opts.TopicStyles, alert); matched && style.Emoji != "" {
+		emoji = style.Emoji
+	}
+	return fmt.Sprintf("%s **%s**", emoji, strings.ToUpper(alert.AlertType))
 }
 
 // FormatAlert creates a single alert post attachment with all alert information.
-func FormatAlert(alert backend.Alert) *model.SlackAttachment {
+func FormatAlert(alert backend.Alert, opts Options) *model.SlackAttachment {
 	attachment := &model.SlackAttachment{}
 
 	// Set text with title - use markdown H3 header for emphasis
 	attachment.Text = fmt.Sprintf("### %s", alert.Headline)
 
-	// Set color based on alert type
-	attachment.Color = getAlertColor(alert.AlertType)
+	// Set color based on the topic style or alert type
+	attachment.Color = alertColor(alert, opts)
 
 	// Build all fields
 	var fields []*model.SlackAttachmentField
 
-	// Alert Link and Public Source (side by side at top)
+	// Summary of long source text (full width, first)
+	if alert.Summary != "" {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: translate(opts.Locale, "Summary"),
+			Value: alert.Summary,
+			Short: false,
+		})
+	}
+
+	// Alert Link and Public Source (side by side)
 	if alert.AlertURL != "" {
 		fields = append(fields, &model.SlackAttachmentField{
-			Title: "Alert Link",
-			Value: fmt.Sprintf("**[Open in Dataminr](%s)**", alert.AlertURL),
+			Title: translate(opts.Locale, "Alert Link"),
+			Value: fmt.Sprintf("**[%s](%s)**", translate(opts.Locale, "Open in Dataminr"), alert.AlertURL),
 			Short: true,
 		})
 	}
 
 	if alert.PublicSourceURL != "" {
 		fields = append(fields, &model.SlackAttachmentField{
-			Title: "Public Source",
-			Value: fmt.Sprintf("**[Open Public Link](%s)**", alert.PublicSourceURL),
+			Title: translate(opts.Locale, "Public Source"),
+			Value: fmt.Sprintf("**[%s](%s)**", translate(opts.Locale, "Open Public Link"), alert.PublicSourceURL),
+			Short: true,
+		})
+	}
+
+	if opts.PostLink != "" {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: translate(opts.Locale, "Post Link"),
+			Value: fmt.Sprintf("**[%s](%s)**", translate(opts.Locale, "Link to this post"), opts.PostLink),
 			Short: true,
 		})
 	}
@@ -64,43 +143,67 @@ func FormatAlert(alert backend.Alert) *model.SlackAttachment {
 	// Event Time and Location (side by side)
 	fields = append(fields,
 		&model.SlackAttachmentField{
-			Title: "Event Time",
-			Value: formatTime(alert.EventTime),
+			Title: translate(opts.Locale, "Event Time"),
+			Value: formatEventTime(alert.EventTime, opts),
 			Short: true,
 		},
 	)
 
 	if alert.Location != nil && alert.Location.Address != "" {
 		fields = append(fields, &model.SlackAttachmentField{
-			Title: "Location",
+			Title: translate(opts.Locale, "Location"),
 			Value: formatLocation(alert.Location),
 			Short: true,
 		})
 	}
 
+	// Map link (when coordinates are available and a provider is configured)
+	if hasCoordinates(alert.Location) {
+		if link := opts.Map.LinkMarkdown(alert.Location.Latitude, alert.Location.Longitude, opts.Locale); link != "" {
+			fields = append(fields, &model.SlackAttachmentField{
+				Title: translate(opts.Locale, "Map"),
+				Value: link,
+				Short: true,
+			})
+		}
+	}
+
 	// Additional Context (sub-headline if available)
 	if alert.SubHeadline != "" {
 		fields = append(fields, &model.SlackAttachmentField{
-			Title: "Additional Context",
+			Title: translate(opts.Locale, "Additional Context"),
 			Value: alert.SubHeadline,
 			Short: false,
 		})
 	}
 
-	// Original Source Text (truncate at 500 chars)
+	// Original Source Text (truncated at the configured length)
 	if alert.SourceText != "" {
 		fields = append(fields, &model.SlackAttachmentField{
-			Title: "Original Source Text",
-			Value: truncateText(alert.SourceText, 500),
+			Title: translate(opts.Locale, "Original Source Text"),
+			Value: truncateText(alert.SourceText, opts.Limits.SourceTextChars()),
 			Short: false,
 		})
 	}
 
-	// Translated Text (truncate at 500 chars)
+	// Translated Text (truncated at the configured length)
 	if alert.TranslatedText != "" {
 		fields = append(fields, &model.SlackAttachmentField{
-			Title: "Translated Text",
-			Value: truncateText(alert.TranslatedText, 500),
+			Title: translate(opts.Locale, "Translated Text"),
+			Value: truncateText(alert.TranslatedText, opts.Limits.SourceTextChars()),
+			Short: false,
+		})
+	}
+
+	// Machine translation disclaimer with the original headline
+	if alert.TranslationLanguage != "" {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: translate(opts.Locale, "Machine Translated"),
+			Value: fmt.Sprintf("`%s`, %s\n%s: %s",
+				alert.TranslationLanguage,
+				translate(opts.Locale, "may be inaccurate"),
+				translate(opts.Locale, "Original headline"),
+				alert.OriginalHeadline),
 			Short: false,
 		})
 	}
@@ -108,8 +211,8 @@ func FormatAlert(alert backend.Alert) *model.SlackAttachment {
 	// Topics (bulleted list, full width)
 	if len(alert.Topics) > 0 {
 		fields = append(fields, &model.SlackAttachmentField{
-			Title: "Topics",
-			Value: formatBulletList(alert.Topics),
+			Title: translate(opts.Locale, "Topics"),
+			Value: formatTopics(alert.Topics, opts.Limits.MaxTopics, opts.Locale),
 			Short: false,
 		})
 	}
@@ -117,30 +220,32 @@ func FormatAlert(alert backend.Alert) *model.SlackAttachment {
 	// Alert Lists (bulleted list, full width)
 	if len(alert.AlertLists) > 0 {
 		fields = append(fields, &model.SlackAttachmentField{
-			Title: "Alert Lists",
+			Title: translate(opts.Locale, "Alert Lists"),
 			Value: formatBulletList(alert.AlertLists),
 			Short: false,
 		})
 	}
 
-	// Additional Media (links to media 2-4)
-	if len(alert.MediaURLs) > 1 {
-		additionalMedia := alert.MediaURLs[1:]
-		if len(additionalMedia) > 3 {
-			additionalMedia = additionalMedia[:3] // Limit to 3 additional media
+	// Additional Media (links to the media after the ones shown as images)
+	if shown := opts.mediaItems(); len(alert.MediaURLs) > shown {
+		additionalMedia := alert.MediaURLs[shown:]
+		if maxLinks := opts.Limits.MediaLinks(); len(additionalMedia) > maxLinks {
+			additionalMedia = additionalMedia[:maxLinks]
 		}
 		fields = append(fields, &model.SlackAttachmentField{
-			Title: "Additional Media",
-			Value: formatMediaLinks(additionalMedia),
+			Title: translate(opts.Locale, "Additional Media"),
+			Value: formatMediaLinks(additionalMedia, shown+1, opts.Locale),
 			Short: false,
 		})
 	}
 
 	attachment.Fields = fields
 
-	// Set image URL: First media item embedded
+	// Set image URL: First media item embedded, otherwise the static map if available
 	if len(alert.MediaURLs) > 0 {
 		attachment.ImageURL = alert.MediaURLs[0]
+	} else if hasCoordinates(alert.Location) {
+		attachment.ImageURL = opts.Map.StaticMapURL(alert.Location.Latitude, alert.Location.Longitude)
 	}
 
 	// Set footer: Backend name
@@ -149,6 +254,64 @@ func FormatAlert(alert backend.Alert) *model.SlackAttachment {
 	return attachment
 }
 
+// FormatMediaAttachments creates an image attachment for each media item shown as an image
+// after the first one, which FormatAlert embeds. Returns nil unless opts.MediaItems bundles
+// several media items.
+func FormatMediaAttachments(alert backend.Alert, opts Options) []*model.SlackAttachment {
+	shown := min(opts.mediaItems(), len(alert.MediaURLs))
+	if shown <= 1 {
+		return nil
+	}
+
+	attachments := make([]*model.SlackAttachment, 0, shown-1)
+	for _, mediaURL := range alert.MediaURLs[1:shown] {
+		attachments = append(attachments, &model.SlackAttachment{
+			Color:    alertColor(alert, opts),
+			ImageURL: mediaURL,
+		})
+	}
+	return attachments
+}
+
+// mediaItems returns the number of media items shown as images
+func (o Options) mediaItems() int {
+	return max(o.MediaItems, 1)
+}
+
+// FormatMapAttachment creates a secondary attachment holding the static map image.
+// This is only needed when the primary attachment already embeds alert media;
+// otherwise the map is used as the primary image and nil is returned.
+func FormatMapAttachment(alert backend.Alert, opts Options) *model.SlackAttachment {
+	if len(alert.MediaURLs) == 0 || !hasCoordinates(alert.Location) {
+		return nil
+	}
+
+	imageURL := opts.Map.StaticMapURL(alert.Location.Latitude, alert.Location.Longitude)
+	if imageURL == "" {
+		return nil
+	}
+
+	return &model.SlackAttachment{
+		Color:    alertColor(alert, opts),
+		Title:    translate(opts.Locale, "Map"),
+		ImageURL: imageURL,
+	}
+}
+
+// hasCoordinates reports whether a location includes coordinates
+func hasCoordinates(loc *backend.Location) bool {
+	return loc != nil && (loc.Latitude != 0 || loc.Longitude != 0)
+}
+
+// alertColor returns the attachment color of an alert: the color of its topic style if one
+// matches, otherwise the alert type color
+func alertColor(alert backend.Alert, opts Options) string {
+	if style, matched := backend.MatchTopicStyle(opts.TopicStyles, alert); matched && style.Color != "" {
+		return style.Color
+	}
+	return getAlertColor(alert.AlertType)
+}
+
 // getAlertColor returns the color code for an alert type
 func getAlertColor(alertType string) string {
 	switch strings.ToLower(alertType) {
@@ -177,9 +340,43 @@ func getAlertEmoji(alertType string) string {
 	}
 }
 
-// formatTime formats a time.Time to a readable string
-func formatTime(t time.Time) string {
-	return t.Format("2006-01-02 15:04:05 MST")
+// formatTime formats a time.Time to a readable string in the locale's date order
+func formatTime(t time.Time, locale string) string {
+	return t.Format(timeLayout(locale))
+}
+
+// formatEventTime formats the event time in the display timezone, appending the relative
+// duration when enabled (e.g., "2024-03-07 14:30:00 UTC · 6 min ago")
+func formatEventTime(t time.Time, opts Options) string {
+	if opts.Timezone != nil {
+		t = t.In(opts.Timezone)
+	}
+
+	formatted := formatTime(t, opts.Locale)
+	if !opts.RelativeTime {
+		return formatted
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return formatted + " · " + formatRelativeTime(now.Sub(t), opts.Locale)
+}
+
+// formatRelativeTime describes how long ago something happened. Events less than a minute
+// ago, including event times slightly in the future due to clock skew, are "just now".
+func formatRelativeTime(elapsed time.Duration, locale string) string {
+	switch {
+	case elapsed < time.Minute:
+		return translate(locale, "just now")
+	case elapsed < time.Hour:
+		return translatef(locale, "%d min ago", int(elapsed/time.Minute))
+	case elapsed < 48*time.Hour:
+		return translatef(locale, "%d h ago", int(elapsed/time.Hour))
+	default:
+		return translatef(locale, "%d days ago", int(elapsed/(24*time.Hour)))
+	}
 }
 
 // formatLocation formats a Location struct to a readable string
@@ -198,7 +395,17 @@ func formatLocation(loc *backend.Location) string {
 		parts = append(parts, fmt.Sprintf("±%.0fm", loc.ConfidenceRadius))
 	}
 
-	return strings.Join(parts, " ")
+	formatted := strings.Join(parts, " ")
+
+	// MGRS grid reference on its own line for users who navigate by grid
+	if loc.MGRS != "" {
+		if formatted != "" {
+			formatted += "\n"
+		}
+		formatted += fmt.Sprintf("MGRS: `%s`", loc.MGRS)
+	}
+
+	return formatted
 }
 
 // formatBulletList formats a slice of strings as a bulleted list
@@ -210,19 +417,44 @@ func formatBulletList(items []string) string {
 	return strings.Join(bullets, "\n")
 }
 
-// truncateText truncates text to maxLen characters, adding "..." if truncated
+// formatTopics formats topics as a bulleted list, listing at most maxTopics (0 means no limit)
+func formatTopics(topics []string, maxTopics int, locale string) string {
+	if maxTopics <= 0 || len(topics) <= maxTopics {
+		return formatBulletList(topics)
+	}
+	return formatBulletList(topics[:maxTopics]) + "\n_" + translatef(locale, "+%d more", len(topics)-maxTopics) + "_"
+}
+
+// truncateText truncates text to maxLen characters, adding "..." if truncated.
+// Multi-byte characters are never split.
 func truncateText(text string, maxLen int) string {
-	if len(text) <= maxLen {
+	runes := []rune(text)
+	if len(runes) <= maxLen {
 		return text
 	}
-	return text[:maxLen] + "..."
+	return string(runes[:maxLen]) + "..."
 }
 
-// formatMediaLinks formats media URLs as markdown links
-func formatMediaLinks(urls []string) string {
+// formatMediaLinks formats media URLs as markdown links, numbered from first
+func formatMediaLinks(urls []string, first int, locale string) string {
 	links := make([]string, len(urls))
 	for i, url := range urls {
-		links[i] = fmt.Sprintf("[Media %d](%s)", i+2, url) // Start at 2 since first media is embedded
+		links[i] = fmt.Sprintf("[%s](%s)", translatef(locale, "Media %d", first+i), url)
 	}
 	return strings.Join(links, " | ")
 }
+
+// FormatMatchedFooter builds the footer of an alert post that similar alerts from other
+// backends were collapsed into
+func FormatMatchedFooter(backendName string, matched []string, locale string) string {
+	if len(matched) == 0 {
+		return backendName
+	}
+	return backendName + " | " + translatef(locale, "also matched: %s", strings.Join(matched, ", "))
+}
+
+// FormatRepostNote builds the footer note of an alert post whose content matches an earlier
+// alert posted under another alert ID
+func FormatRepostNote(originalURL, locale string) string {
+	return translatef(locale, "possible repost of %s", originalURL)
+}
@@ -36,7 +36,7 @@ func TestFormatAlert_FullAlert(t *testing.T) {
 		},
 	}
 
-	attachment := FormatAlert(alert)
+	attachment := FormatAlert(alert, Options{})
 
 	// Verify basic structure
 	assert.Contains(t, attachment.Text, "Breaking News")
@@ -115,7 +115,7 @@ func TestFormatAlert_MinimalAlert(t *testing.T) {
 		EventTime:   time.Date(2025, 10, 30, 14, 30, 0, 0, time.UTC),
 	}
 
-	attachment := FormatAlert(alert)
+	attachment := FormatAlert(alert, Options{})
 
 	// Verify basic structure
 	assert.Contains(t, attachment.Text, "Simple Alert")
@@ -188,14 +188,14 @@ func TestGetAlertEmoji(t *testing.T) {
 func TestFormatTime(t *testing.T) {
 	// Test with UTC time
 	utcTime := time.Date(2025, 10, 30, 14, 30, 45, 0, time.UTC)
-	result := formatTime(utcTime)
+	result := formatTime(utcTime, "")
 	assert.Equal(t, "2025-10-30 14:30:45 UTC", result)
 
 	// Test with different timezone
 	loc, err := time.LoadLocation("America/New_York")
 	require.NoError(t, err)
 	nyTime := time.Date(2025, 10, 30, 14, 30, 45, 0, loc)
-	result = formatTime(nyTime)
+	result = formatTime(nyTime, "")
 	assert.Contains(t, result, "2025-10-30 14:30:45")
 	assert.Contains(t, result, "E") // EDT or EST
 }
@@ -249,6 +249,23 @@ func TestFormatLocation(t *testing.T) {
 			},
 			expected: "(40.712800, -74.006000) ±251m",
 		},
+		{
+			name: "With MGRS",
+			location: &backend.Location{
+				Address:   "123 Main St",
+				Latitude:  40.7128,
+				Longitude: -74.0060,
+				MGRS:      "18TWL8395907350",
+			},
+			expected: "123 Main St (40.712800, -74.006000)\nMGRS: `18TWL8395907350`",
+		},
+		{
+			name: "MGRS only",
+			location: &backend.Location{
+				MGRS: "18TWL8395907350",
+			},
+			expected: "MGRS: `18TWL8395907350`",
+		},
 		{
 			name:     "Empty location",
 			location: &backend.Location{},
@@ -326,6 +343,12 @@ func TestTruncateText(t *testing.T) {
 			maxLen:   500,
 			expected: strings.Repeat("a", 500) + "...",
 		},
+		{
+			name:     "Multi-byte characters are not split",
+			text:     "Überschwemmung",
+			maxLen:   3,
+			expected: "Übe...",
+		},
 		{
 			name:     "Empty text",
 			text:     "",
@@ -372,7 +395,7 @@ func TestFormatMediaLinks(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatMediaLinks(tt.urls)
+			result := formatMediaLinks(tt.urls, 2, "")
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -432,7 +455,7 @@ func TestFormatAlert_MultipleMediaURLs(t *testing.T) {
 				MediaURLs:   tt.mediaURLs,
 			}
 
-			attachment := FormatAlert(alert)
+			attachment := FormatAlert(alert, Options{})
 
 			assert.Equal(t, tt.expectedImageURL, attachment.ImageURL)
 
@@ -459,6 +482,51 @@ func TestFormatAlert_MultipleMediaURLs(t *testing.T) {
 	}
 }
 
+func TestFormatMediaAttachments(t *testing.T) {
+	alert := backend.Alert{
+		AlertType: "Flash",
+		Headline:  "Flooding",
+		MediaURLs: []string{
+			"https://example.com/1.jpg",
+			"https://example.com/2.jpg",
+			"https://example.com/3.jpg",
+			"https://example.com/4.jpg",
+		},
+	}
+
+	t.Run("bundles media items as image attachments", func(t *testing.T) {
+		opts := Options{MediaItems: 3}
+
+		attachments := FormatMediaAttachments(alert, opts)
+		require.Len(t, attachments, 2)
+		assert.Equal(t, "https://example.com/2.jpg", attachments[0].ImageURL)
+		assert.Equal(t, "https://example.com/3.jpg", attachments[1].ImageURL)
+		assert.Equal(t, ColorFlash, attachments[0].Color)
+
+		// Only the media beyond the bundled items are linked
+		attachment := FormatAlert(alert, opts)
+		assert.Equal(t, "https://example.com/1.jpg", attachment.ImageURL)
+		require.NotEmpty(t, attachment.Fields)
+		mediaField := attachment.Fields[len(attachment.Fields)-1]
+		assert.Equal(t, "Additional Media", mediaField.Title)
+		assert.Equal(t, "[Media 4](https://example.com/4.jpg)", mediaField.Value)
+	})
+
+	t.Run("bundles no more media than the alert has", func(t *testing.T) {
+		opts := Options{MediaItems: 10}
+
+		assert.Len(t, FormatMediaAttachments(alert, opts), 3)
+		for _, field := range FormatAlert(alert, opts).Fields {
+			assert.NotEqual(t, "Additional Media", field.Title)
+		}
+	})
+
+	t.Run("no bundle by default", func(t *testing.T) {
+		assert.Nil(t, FormatMediaAttachments(alert, Options{}))
+		assert.Nil(t, FormatMediaAttachments(alert, Options{MediaItems: 1}))
+	})
+}
+
 func TestFormatAlert_AlertTypeVariations(t *testing.T) {
 	tests := []struct {
 		alertType     string
@@ -488,7 +556,7 @@ func TestFormatAlert_AlertTypeVariations(t *testing.T) {
 				EventTime:   time.Now(),
 			}
 
-			attachment := FormatAlert(alert)
+			attachment := FormatAlert(alert, Options{})
 
 			assert.Equal(t, tt.expectedColor, attachment.Color)
 			assert.Contains(t, attachment.Text, "Test") // Text contains headline
@@ -509,7 +577,7 @@ func TestFormatAlert_SourceTextTruncation(t *testing.T) {
 		TranslatedText: longText,
 	}
 
-	attachment := FormatAlert(alert)
+	attachment := FormatAlert(alert, Options{})
 
 	// Find source text and translated text fields
 	var sourceTextField, translatedTextField string
@@ -532,3 +600,203 @@ func TestFormatAlert_SourceTextTruncation(t *testing.T) {
 	assert.Equal(t, 503, len(translatedTextField))
 	assert.True(t, strings.HasSuffix(translatedTextField, "..."))
 }
+
+func TestFormatAlert_ContentLimits(t *testing.T) {
+	alert := backend.Alert{
+		BackendName: "Test",
+		AlertID:     "123",
+		Headline:    "Test",
+		AlertType:   "Alert",
+		EventTime:   time.Now(),
+		SourceText:  strings.Repeat("a", 600),
+		Topics:      []string{"Fire", "Flood", "Storm"},
+		MediaURLs: []string{
+			"https://example.com/1.jpg",
+			"https://example.com/2.jpg",
+			"https://example.com/3.jpg",
+		},
+	}
+
+	attachment := FormatAlert(alert, Options{
+		Limits: backend.ContentLimits{MaxSourceTextChars: 100, MaxMediaLinks: 1, MaxTopics: 2},
+	})
+
+	values := make(map[string]string)
+	for _, field := range attachment.Fields {
+		values[field.Title] = field.Value.(string)
+	}
+
+	assert.Equal(t, strings.Repeat("a", 100)+"...", values["Original Source Text"])
+	assert.Equal(t, "[Media 2](https://example.com/2.jpg)", values["Additional Media"])
+	assert.Equal(t, "• Fire\n• Flood\n_+1 more_", values["Topics"])
+}
+
+func TestFormatAlert_TopicStyles(t *testing.T) {
+	alert := backend.Alert{
+		BackendName: "Test",
+		AlertID:     "123",
+		Headline:    "Test",
+		AlertType:   "Urgent",
+		EventTime:   time.Now(),
+		Topics:      []string{"Cyber Security"},
+	}
+	opts := Options{TopicStyles: []backend.TopicStyle{
+		{Match: "weather", Emoji: ":cloud:"},
+		{Match: "cyber", Emoji: ":computer:", Color: "#6A0DAD"},
+	}}
+
+	assert.Equal(t, "#6A0DAD", FormatAlert(alert, opts).Color)
+	assert.Equal(t, ":computer: **URGENT**", FormatAlertTypeText(alert, opts))
+
+	// A style without a color keeps the alert type color
+	opts.TopicStyles[1].Color = ""
+	assert.Equal(t, ColorUrgent, FormatAlert(alert, opts).Color)
+
+	alert.Topics = nil
+	assert.Equal(t, ColorUrgent, FormatAlert(alert, opts).Color)
+	assert.Equal(t, EmojiUrgent+" **URGENT**", FormatAlertTypeText(alert, opts))
+}
+
+func TestFormatAlert_Summary(t *testing.T) {
+	alert := backend.Alert{
+		BackendName: "Test",
+		AlertID:     "123",
+		Headline:    "Test",
+		AlertType:   "Alert",
+		EventTime:   time.Now(),
+		AlertURL:    "https://example.com/alert",
+		SourceText:  strings.Repeat("a", 600),
+		Summary:     "A short summary.",
+	}
+
+	attachment := FormatAlert(alert, Options{})
+	require.NotEmpty(t, attachment.Fields)
+	assert.Equal(t, "Summary", attachment.Fields[0].Title)
+	assert.Equal(t, "A short summary.", attachment.Fields[0].Value)
+	assert.False(t, bool(attachment.Fields[0].Short))
+
+	attachment = FormatAlert(alert, Options{Locale: "de"})
+	assert.Equal(t, "Zusammenfassung", attachment.Fields[0].Title)
+
+	alert.Summary = ""
+	attachment = FormatAlert(alert, Options{})
+	assert.Equal(t, "Alert Link", attachment.Fields[0].Title)
+}
+
+func TestFormatAlert_MachineTranslated(t *testing.T) {
+	alert := backend.Alert{
+		BackendName:         "Test",
+		AlertID:             "123",
+		Headline:            "Incendie au centre-ville",
+		AlertType:           "Alert",
+		EventTime:           time.Now(),
+		SourceText:          "Fire downtown, roads closed",
+		TranslatedText:      "Incendie au centre-ville, routes fermées",
+		TranslationLanguage: "fr",
+		OriginalHeadline:    "Fire downtown",
+	}
+
+	values := make(map[string]string)
+	for _, field := range FormatAlert(alert, Options{}).Fields {
+		values[field.Title] = field.Value.(string)
+	}
+	assert.Equal(t, "Incendie au centre-ville, routes fermées", values["Translated Text"])
+	assert.Equal(t, "`fr`, may be inaccurate\nOriginal headline: Fire downtown", values["Machine Translated"])
+
+	values = make(map[string]string)
+	for _, field := range FormatAlert(alert, Options{Locale: "fr"}).Fields {
+		values[field.Title] = field.Value.(string)
+	}
+	assert.Equal(t, "`fr`, peut être inexacte\nTitre original: Fire downtown", values["Traduction automatique"])
+
+	alert.TranslationLanguage = ""
+	for _, field := range FormatAlert(alert, Options{}).Fields {
+		assert.NotEqual(t, "Machine Translated", field.Title)
+	}
+}
+
+func TestFormatMatchedFooter(t *testing.T) {
+	assert.Equal(t, "Backend A", FormatMatchedFooter("Backend A", nil, ""))
+	assert.Equal(t, "Backend A | also matched: Backend B", FormatMatchedFooter("Backend A", []string{"Backend B"}, ""))
+	assert.Equal(t, "Backend A | also matched: Backend B, Backend C", FormatMatchedFooter("Backend A", []string{"Backend B", "Backend C"}, ""))
+}
+
+func TestFormatRepostNote(t *testing.T) {
+	assert.Equal(t, "possible repost of https://chat.example.com/_redirect/pl/post-1", FormatRepostNote("https://chat.example.com/_redirect/pl/post-1", ""))
+	assert.Equal(t, "mögliche Wiederholung von https://chat.example.com/_redirect/pl/post-1", FormatRepostNote("https://chat.example.com/_redirect/pl/post-1", "de"))
+}
+
+func TestFormatEventTime(t *testing.T) {
+	eventTime := time.Date(2024, 3, 7, 14, 30, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	t.Run("keeps the alert offset by default", func(t *testing.T) {
+		assert.Equal(t, "2024-03-07 14:30:00 UTC", formatEventTime(eventTime, Options{}))
+	})
+
+	t.Run("converts to the display timezone", func(t *testing.T) {
+		assert.Equal(t, "2024-03-07 09:30:00 EST", formatEventTime(eventTime, Options{Timezone: newYork}))
+	})
+
+	t.Run("appends the relative duration", func(t *testing.T) {
+		opts := Options{RelativeTime: true, Now: eventTime.Add(6 * time.Minute)}
+		assert.Equal(t, "2024-03-07 14:30:00 UTC · 6 min ago", formatEventTime(eventTime, opts))
+
+		opts.Locale = "de"
+		assert.Equal(t, "07.03.2024 14:30:00 UTC · vor 6 Min.", formatEventTime(eventTime, opts))
+	})
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	tests := []struct {
+		elapsed  time.Duration
+		expected string
+	}{
+		{-2 * time.Minute, "just now"},
+		{30 * time.Second, "just now"},
+		{time.Minute, "1 min ago"},
+		{59 * time.Minute, "59 min ago"},
+		{3*time.Hour + 20*time.Minute, "3 h ago"},
+		{47 * time.Hour, "47 h ago"},
+		{72 * time.Hour, "3 days ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.elapsed.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatRelativeTime(tt.elapsed, ""))
+		})
+	}
+
+	assert.Equal(t, "il y a 2 h", formatRelativeTime(2*time.Hour, "fr"))
+}
+
+func TestTrimOrder(t *testing.T) {
+	order := TrimOrder("")
+	require.Len(t, order, len(trimOrder))
+	assert.Equal(t, "Additional Media", order[0])
+	assert.Equal(t, "Summary", order[len(order)-1])
+
+	assert.Equal(t, "Listes d'alertes", TrimOrder("fr")[1])
+}
+
+func TestFormatAlert_PostLink(t *testing.T) {
+	alert := backend.Alert{
+		AlertID:         "alert-123",
+		AlertType:       "Alert",
+		Headline:        "Road closed",
+		AlertURL:        "https://example.com/alert/123",
+		PublicSourceURL: "https://example.com/source",
+	}
+
+	attachment := FormatAlert(alert, Options{PostLink: "https://chat.example.com/plugins/dataminr/alert/backend-1/alert-123", Locale: "fr"})
+
+	// Shown after the alert and public source links
+	require.GreaterOrEqual(t, len(attachment.Fields), 3)
+	assert.Equal(t, "Lien de la publication", attachment.Fields[2].Title)
+	assert.Equal(t, "**[Lien vers cette publication](https://chat.example.com/plugins/dataminr/alert/backend-1/alert-123)**", attachment.Fields[2].Value)
+
+	for _, field := range FormatAlert(alert, Options{}).Fields {
+		assert.NotEqual(t, "Post Link", field.Title)
+	}
+}
@@ -0,0 +1,149 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultLocale is the locale used when no supported locale is configured
+const DefaultLocale = "en"
+
+// messages is the catalog of translated post text keyed by locale, then by the English text.
+// English text is used for missing locales and messages.
+var messages = map[string]map[string]string{
+	"fr": {
+		"Summary":               "Résumé",
+		"Alert Link":            "Lien de l'alerte",
+		"Open in Dataminr":      "Ouvrir dans Dataminr",
+		"Public Source":         "Source publique",
+		"Open Public Link":      "Ouvrir le lien public",
+		"Post Link":             "Lien de la publication",
+		"Link to this post":     "Lien vers cette publication",
+		"Event Time":            "Heure de l'événement",
+		"Location":              "Lieu",
+		"Map":                   "Carte",
+		"Open in %s":            "Ouvrir dans %s",
+		"Additional Context":    "Contexte supplémentaire",
+		"Original Source Text":  "Texte source original",
+		"Translated Text":       "Texte traduit",
+		"Machine Translated":    "Traduction automatique",
+		"may be inaccurate":     "peut être inexacte",
+		"Original headline":     "Titre original",
+		"Topics":                "Sujets",
+		"Alert Lists":           "Listes d'alertes",
+		"Additional Media":      "Médias supplémentaires",
+		"Media %d":              "Média %d",
+		"+%d more":              "+%d de plus",
+		"also matched: %s":      "également signalé par : %s",
+		"possible repost of %s": "possible republication de %s",
+		"just now":              "à l'instant",
+		"%d min ago":            "il y a %d min",
+		"%d h ago":              "il y a %d h",
+		"%d days ago":           "il y a %d jours",
+	},
+	"de": {
+		"Summary":               "Zusammenfassung",
+		"Alert Link":            "Alarm-Link",
+		"Open in Dataminr":      "In Dataminr öffnen",
+		"Public Source":         "Öffentliche Quelle",
+		"Open Public Link":      "Öffentlichen Link öffnen",
+		"Post Link":             "Beitrags-Link",
+		"Link to this post":     "Link zu diesem Beitrag",
+		"Event Time":            "Ereigniszeit",
+		"Location":              "Ort",
+		"Map":                   "Karte",
+		"Open in %s":            "In %s öffnen",
+		"Additional Context":    "Zusätzlicher Kontext",
+		"Original Source Text":  "Originaler Quelltext",
+		"Translated Text":       "Übersetzter Text",
+		"Machine Translated":    "Maschinell übersetzt",
+		"may be inaccurate":     "möglicherweise ungenau",
+		"Original headline":     "Originale Überschrift",
+		"Topics":                "Themen",
+		"Alert Lists":           "Alarmlisten",
+		"Additional Media":      "Weitere Medien",
+		"Media %d":              "Medium %d",
+		"+%d more":              "+%d weitere",
+		"also matched: %s":      "auch gemeldet von: %s",
+		"possible repost of %s": "mögliche Wiederholung von %s",
+		"just now":              "gerade eben",
+		"%d min ago":            "vor %d Min.",
+		"%d h ago":              "vor %d Std.",
+		"%d days ago":           "vor %d Tagen",
+	},
+	"es": {
+		"Summary":               "Resumen",
+		"Alert Link":            "Enlace de la alerta",
+		"Open in Dataminr":      "Abrir en Dataminr",
+		"Public Source":         "Fuente pública",
+		"Open Public Link":      "Abrir enlace público",
+		"Post Link":             "Enlace de la publicación",
+		"Link to this post":     "Enlace a esta publicación",
+		"Event Time":            "Hora del evento",
+		"Location":              "Ubicación",
+		"Map":                   "Mapa",
+		"Open in %s":            "Abrir en %s",
+		"Additional Context":    "Contexto adicional",
+		"Original Source Text":  "Texto original",
+		"Translated Text":       "Texto traducido",
+		"Machine Translated":    "Traducción automática",
+		"may be inaccurate":     "puede ser inexacta",
+		"Original headline":     "Titular original",
+		"Topics":                "Temas",
+		"Alert Lists":           "Listas de alertas",
+		"Additional Media":      "Medios adicionales",
+		"Media %d":              "Medio %d",
+		"+%d more":              "+%d más",
+		"also matched: %s":      "también reportado por: %s",
+		"possible repost of %s": "posible republicación de %s",
+		"just now":              "justo ahora",
+		"%d min ago":            "hace %d min",
+		"%d h ago":              "hace %d h",
+		"%d days ago":           "hace %d días",
+	},
+}
+
+// timeLayouts are the event time layouts keyed by locale
+var timeLayouts = map[string]string{
+	"en": "2006-01-02 15:04:05 MST",
+	"fr": "02/01/2006 15:04:05 MST",
+	"de": "02.01.2006 15:04:05 MST",
+	"es": "02/01/2006 15:04:05 MST",
+}
+
+// ResolveLocale returns the first of the given locales with a message catalog, matching
+// regional variants by language (e.g., "fr-CA" resolves to "fr"). Empty and unsupported
+// locales are skipped. Returns DefaultLocale if none is supported.
+func ResolveLocale(locales ...string) string {
+	for _, locale := range locales {
+		language := strings.ToLower(locale)
+		if i := strings.IndexAny(language, "-_"); i >= 0 {
+			language = language[:i]
+		}
+		if _, supported := timeLayouts[language]; supported {
+			return language
+		}
+	}
+	return DefaultLocale
+}
+
+// translate returns the text in the given locale, falling back to English
+func translate(locale, text string) string {
+	if translated, exists := messages[locale][text]; exists {
+		return translated
+	}
+	return text
+}
+
+// translatef translates a format string and formats it with the arguments
+func translatef(locale, format string, args ...any) string {
+	return fmt.Sprintf(translate(locale, format), args...)
+}
+
+// timeLayout returns the event time layout for a locale, falling back to English
+func timeLayout(locale string) string {
+	if layout, exists := timeLayouts[locale]; exists {
+		return layout
+	}
+	return timeLayouts[DefaultLocale]
+}
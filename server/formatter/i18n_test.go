@@ -0,0 +1,110 @@
+package formatter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestMessageCatalog_Complete(t *testing.T) {
+	// Every supported locale translates the same messages
+	reference := messages["fr"]
+	for locale := range backend.SupportedLocales {
+		assert.Contains(t, timeLayouts, locale)
+		if locale == DefaultLocale {
+			continue
+		}
+
+		catalog, exists := messages[locale]
+		require.True(t, exists, "missing catalog for %s", locale)
+		assert.Len(t, catalog, len(reference), "catalog for %s", locale)
+		for text := range reference {
+			assert.Contains(t, catalog, text, "catalog for %s", locale)
+		}
+	}
+}
+
+func TestResolveLocale(t *testing.T) {
+	assert.Equal(t, "fr", ResolveLocale("fr"))
+	assert.Equal(t, "de", ResolveLocale("", "de"), "empty locales are skipped")
+	assert.Equal(t, "es", ResolveLocale("es_ES"))
+	assert.Equal(t, "fr", ResolveLocale("fr-CA", "de"))
+	assert.Equal(t, "de", ResolveLocale("ja", "DE"), "unsupported locales fall through")
+	assert.Equal(t, DefaultLocale, ResolveLocale("ja"))
+	assert.Equal(t, DefaultLocale, ResolveLocale())
+}
+
+func TestFormatAlert_Locales(t *testing.T) {
+	alert := backend.Alert{
+		AlertID:     "alert-1",
+		Headline:    "Headline",
+		AlertType:   "Flash",
+		EventTime:   time.Date(2024, 3, 7, 14, 5, 9, 0, time.UTC),
+		AlertURL:    "https://example.com/alert",
+		BackendName: "Backend A",
+		Location:    &backend.Location{Address: "Paris", Latitude: 48.85, Longitude: 2.35},
+		Topics:      []string{"One", "Two", "Three"},
+		MediaURLs:   []string{"https://example.com/1.jpg", "https://example.com/2.jpg"},
+	}
+
+	tests := []struct {
+		locale   string
+		titles   []string
+		values   []string
+		fallback bool
+	}{
+		{
+			locale: "",
+			titles: []string{"Alert Link", "Event Time", "Location", "Map", "Topics", "Additional Media"},
+			values: []string{"**[Open in Dataminr](https://example.com/alert)**", "2024-03-07 14:05:09 UTC", "[Open in OpenStreetMap]", "_+1 more_", "[Media 2]"},
+		},
+		{
+			locale: "fr",
+			titles: []string{"Lien de l'alerte", "Heure de l'événement", "Lieu", "Carte", "Sujets", "Médias supplémentaires"},
+			values: []string{"**[Ouvrir dans Dataminr](https://example.com/alert)**", "07/03/2024 14:05:09 UTC", "[Ouvrir dans OpenStreetMap]", "_+1 de plus_", "[Média 2]"},
+		},
+		{
+			locale: "de",
+			titles: []string{"Alarm-Link", "Ereigniszeit", "Ort", "Karte", "Themen", "Weitere Medien"},
+			values: []string{"**[In Dataminr öffnen](https://example.com/alert)**", "07.03.2024 14:05:09 UTC", "[In OpenStreetMap öffnen]", "_+1 weitere_", "[Medium 2]"},
+		},
+		{
+			locale: "es",
+			titles: []string{"Enlace de la alerta", "Hora del evento", "Ubicación", "Mapa", "Temas", "Medios adicionales"},
+			values: []string{"**[Abrir en Dataminr](https://example.com/alert)**", "07/03/2024 14:05:09 UTC", "[Abrir en OpenStreetMap]", "_+1 más_", "[Medio 2]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("locale "+tt.locale, func(t *testing.T) {
+			opts := Options{
+				Map:    NewMapLinkBuilder(MapProviderOpenStreetMap, ""),
+				Limits: backend.ContentLimits{MaxTopics: 2},
+				Locale: tt.locale,
+			}
+			attachment := FormatAlert(alert, opts)
+
+			var titles []string
+			var values string
+			for _, field := range attachment.Fields {
+				titles = append(titles, field.Title)
+				values += field.Value.(string) + "\n"
+			}
+
+			assert.Equal(t, tt.titles, titles)
+			for _, value := range tt.values {
+				assert.Contains(t, values, value)
+			}
+			assert.Equal(t, "Backend A", attachment.Footer)
+		})
+	}
+}
+
+func TestFormatMatchedFooter_Locale(t *testing.T) {
+	assert.Equal(t, "Backend A | également signalé par : Backend B", FormatMatchedFooter("Backend A", []string{"Backend B"}, "fr"))
+	assert.Equal(t, "Backend A | auch gemeldet von: Backend B", FormatMatchedFooter("Backend A", []string{"Backend B"}, "de"))
+}
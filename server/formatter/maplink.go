@@ -0,0 +1,114 @@
+package formatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Supported map provider names
+const (
+	MapProviderNone          = ""
+	MapProviderOpenStreetMap = "openstreetmap"
+	MapProviderGoogle        = "google"
+)
+
+// defaultMapZoom is the zoom level used for map links and static map images
+const defaultMapZoom = 12
+
+// MapProvider builds interactive map links for a coordinate.
+type MapProvider interface {
+	// Name returns the display name of the provider (e.g., "OpenStreetMap")
+	Name() string
+
+	// LinkURL returns a link to an interactive map centered on the coordinate
+	LinkURL(lat, lon float64) string
+}
+
+// openStreetMapProvider links to openstreetmap.org
+type openStreetMapProvider struct{}
+
+func (openStreetMapProvider) Name() string {
+	return "OpenStreetMap"
+}
+
+func (openStreetMapProvider) LinkURL(lat, lon float64) string {
+	return fmt.Sprintf("https://www.openstreetmap.org/?mlat=%s&mlon=%s#map=%d/%s/%s",
+		formatCoordinate(lat), formatCoordinate(lon), defaultMapZoom, formatCoordinate(lat), formatCoordinate(lon))
+}
+
+// googleMapsProvider links to Google Maps
+type googleMapsProvider struct{}
+
+func (googleMapsProvider) Name() string {
+	return "Google Maps"
+}
+
+func (googleMapsProvider) LinkURL(lat, lon float64) string {
+	return fmt.Sprintf("https://www.google.com/maps/search/?api=1&query=%s,%s", formatCoordinate(lat), formatCoordinate(lon))
+}
+
+// GetMapProvider returns the map provider for a configured name.
+// Returns nil if the name is empty or unknown.
+func GetMapProvider(name string) MapProvider {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case MapProviderOpenStreetMap:
+		return openStreetMapProvider{}
+	case MapProviderGoogle:
+		return googleMapsProvider{}
+	default:
+		return nil
+	}
+}
+
+// MapLinkBuilder builds map links and static map image URLs for located alerts.
+type MapLinkBuilder struct {
+	// Provider builds the interactive map link (nil disables the link field)
+	Provider MapProvider
+
+	// StaticMapURLTemplate is the URL template for a static map image.
+	// Supported placeholders: {lat}, {lon} and {zoom}. Empty disables the image.
+	StaticMapURLTemplate string
+}
+
+// NewMapLinkBuilder creates a map link builder from a provider name and static map template.
+// Returns nil if neither a provider nor a template is configured.
+func NewMapLinkBuilder(providerName, staticMapURLTemplate string) *MapLinkBuilder {
+	provider := GetMapProvider(providerName)
+	if provider == nil && staticMapURLTemplate == "" {
+		return nil
+	}
+
+	return &MapLinkBuilder{
+		Provider:             provider,
+		StaticMapURLTemplate: staticMapURLTemplate,
+	}
+}
+
+// LinkMarkdown returns a markdown link to the interactive map in the given locale,
+// or empty if no provider is configured
+func (b *MapLinkBuilder) LinkMarkdown(lat, lon float64, locale string) string {
+	if b == nil || b.Provider == nil {
+		return ""
+	}
+	return fmt.Sprintf("[%s](%s)", translatef(locale, "Open in %s", b.Provider.Name()), b.Provider.LinkURL(lat, lon))
+}
+
+// StaticMapURL returns the static map image URL, or empty if no template is configured
+func (b *MapLinkBuilder) StaticMapURL(lat, lon float64) string {
+	if b == nil || b.StaticMapURLTemplate == "" {
+		return ""
+	}
+
+	replacer := strings.NewReplacer(
+		"{lat}", formatCoordinate(lat),
+		"{lon}", formatCoordinate(lon),
+		"{zoom}", strconv.Itoa(defaultMapZoom),
+	)
+	return replacer.Replace(b.StaticMapURLTemplate)
+}
+
+// formatCoordinate formats a coordinate with six decimal places (~0.1m precision)
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
@@ -0,0 +1,99 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestGetMapProvider(t *testing.T) {
+	assert.Nil(t, GetMapProvider(""))
+	assert.Nil(t, GetMapProvider("bing"))
+	assert.Equal(t, "OpenStreetMap", GetMapProvider("OpenStreetMap").Name())
+	assert.Equal(t, "Google Maps", GetMapProvider("google").Name())
+}
+
+func TestMapProvider_LinkURL(t *testing.T) {
+	assert.Equal(t,
+		"https://www.openstreetmap.org/?mlat=40.712800&mlon=-74.006000#map=12/40.712800/-74.006000",
+		GetMapProvider(MapProviderOpenStreetMap).LinkURL(40.7128, -74.006))
+	assert.Equal(t,
+		"https://www.google.com/maps/search/?api=1&query=40.712800,-74.006000",
+		GetMapProvider(MapProviderGoogle).LinkURL(40.7128, -74.006))
+}
+
+func TestNewMapLinkBuilder(t *testing.T) {
+	assert.Nil(t, NewMapLinkBuilder("", ""), "Nothing configured should disable maps")
+
+	builder := NewMapLinkBuilder("", "https://maps.example.com/static?c={lat},{lon}&z={zoom}")
+	require.NotNil(t, builder)
+	assert.Empty(t, builder.LinkMarkdown(1, 2, ""), "No provider means no link")
+	assert.Equal(t, "https://maps.example.com/static?c=1.000000,2.000000&z=12", builder.StaticMapURL(1, 2))
+
+	builder = NewMapLinkBuilder("google", "")
+	require.NotNil(t, builder)
+	assert.Equal(t, "[Open in Google Maps](https://www.google.com/maps/search/?api=1&query=1.000000,2.000000)", builder.LinkMarkdown(1, 2, ""))
+	assert.Empty(t, builder.StaticMapURL(1, 2), "No template means no image")
+}
+
+func TestMapLinkBuilder_Nil(t *testing.T) {
+	var builder *MapLinkBuilder
+	assert.Empty(t, builder.LinkMarkdown(1, 2, ""))
+	assert.Empty(t, builder.StaticMapURL(1, 2))
+}
+
+func TestFormatAlert_Map(t *testing.T) {
+	opts := Options{Map: NewMapLinkBuilder("openstreetmap", "https://maps.example.com/{lat}/{lon}.png")}
+	located := backend.Alert{
+		AlertType: "Alert",
+		Headline:  "Located alert",
+		Location:  &backend.Location{Address: "New York, NY", Latitude: 40.7128, Longitude: -74.006},
+	}
+
+	t.Run("adds map link field and static map image", func(t *testing.T) {
+		attachment := FormatAlert(located, opts)
+
+		var mapField string
+		for _, field := range attachment.Fields {
+			if field.Title == "Map" {
+				mapField = field.Value.(string)
+			}
+		}
+		assert.Contains(t, mapField, "Open in OpenStreetMap")
+		assert.Equal(t, "https://maps.example.com/40.712800/-74.006000.png", attachment.ImageURL)
+		assert.Nil(t, FormatMapAttachment(located, opts), "Map is the primary image when there is no media")
+	})
+
+	t.Run("uses secondary attachment when alert has media", func(t *testing.T) {
+		withMedia := located
+		withMedia.MediaURLs = []string{"https://example.com/image.jpg"}
+
+		attachment := FormatAlert(withMedia, opts)
+		assert.Equal(t, "https://example.com/image.jpg", attachment.ImageURL)
+
+		mapAttachment := FormatMapAttachment(withMedia, opts)
+		require.NotNil(t, mapAttachment)
+		assert.Equal(t, "https://maps.example.com/40.712800/-74.006000.png", mapAttachment.ImageURL)
+	})
+
+	t.Run("no map without coordinates", func(t *testing.T) {
+		unlocated := backend.Alert{AlertType: "Alert", Headline: "No coordinates", Location: &backend.Location{Address: "Somewhere"}}
+
+		attachment := FormatAlert(unlocated, opts)
+		for _, field := range attachment.Fields {
+			assert.NotEqual(t, "Map", field.Title)
+		}
+		assert.Empty(t, attachment.ImageURL)
+	})
+
+	t.Run("no map when disabled", func(t *testing.T) {
+		attachment := FormatAlert(located, Options{})
+		for _, field := range attachment.Fields {
+			assert.NotEqual(t, "Map", field.Title)
+		}
+		assert.Empty(t, attachment.ImageURL)
+	})
+}
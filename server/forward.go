@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
+)
+
+const (
+	// forwardActionPath is the plugin HTTP path called by the Forward button on alert posts
+	forwardActionPath = "/actions/forward"
+
+	// forwardDialogPath is the plugin HTTP path the forward dialog is submitted to
+	forwardDialogPath = "/actions/forward/submit"
+
+	// forwardChannelField is the name of the channel selector in the forward dialog
+	forwardChannelField = "channel_id"
+)
+
+// forwardActionURL is the integration URL of the Forward button
+func forwardActionURL() string {
+	return "/plugins/" + pluginID + forwardActionPath
+}
+
+// forwardAlert handles the Forward button on alert posts, opening a dialog in which any
+// member of the alert's channel picks another channel of theirs to forward the alert to.
+func (p *Plugin) forwardAlert(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request model.PostActionIntegrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.PostId == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	post, appErr := p.API.GetPost(request.PostId)
+	if appErr != nil {
+		http.Error(w, "Alert post not found", http.StatusNotFound)
+		return
+	}
+	if !p.API.HasPermissionToChannel(userID, post.ChannelId, model.PermissionReadChannel) {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       "/plugins/" + pluginID + forwardDialogPath,
+		Dialog: model.Dialog{
+			CallbackId:       post.Id,
+			Title:            "Forward alert",
+			IntroductionText: "Post a copy of this alert to another channel you belong to. Its mentions and buttons are left out.",
+			Elements: []model.DialogElement{{
+				DisplayName: "Channel",
+				Name:        forwardChannelField,
+				Type:        "select",
+				DataSource:  "channels",
+			}},
+			SubmitLabel: "Forward",
+			State:       post.Id,
+		},
+	}); appErr != nil {
+		p.API.LogError("Failed to open forward dialog", "postId", post.Id, "error", appErr.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(model.PostActionIntegrationResponse{}); err != nil {
+		p.API.LogError("Failed to encode forward action response", "error", err.Error())
+	}
+}
+
+// submitForwardAlert handles the forward dialog, posting a copy of the alert to the chosen
+// channel if the user belongs to it, and recording the forward in the audit log
+func (p *Plugin) submitForwardAlert(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.State == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	post, appErr := p.API.GetPost(request.State)
+	if appErr != nil {
+		http.Error(w, "Alert post not found", http.StatusNotFound)
+		return
+	}
+	if !p.API.HasPermissionToChannel(userID, post.ChannelId, model.PermissionReadChannel) {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	channelID, _ := request.Submission[forwardChannelField].(string)
+	if fieldErr := p.checkForwardChannel(userID, post.ChannelId, channelID); fieldErr != "" {
+		p.writeDialogResponse(w, model.SubmitDialogResponse{Errors: map[string]string{forwardChannelField: fieldErr}})
+		return
+	}
+
+	fromChannel, appErr := p.API.GetChannel(post.ChannelId)
+	if appErr != nil {
+		p.API.LogError("Failed to get channel of forwarded alert", "channelId", post.ChannelId, "error", appErr.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	username := p.username(userID)
+	forwarded, err := p.poster.ForwardAlertPost(post, channelID, fromChannel.Name, username)
+	if err != nil {
+		p.API.LogError("Failed to forward alert", "postId", post.Id, "channelId", channelID, "error", err.Error())
+		p.writeDialogResponse(w, model.SubmitDialogResponse{Error: "The alert could not be forwarded. Please try again."})
+		return
+	}
+
+	alertID, _ := post.GetProp(poster.AlertIDProp).(string)
+	p.logAudit("alert forwarded", userID,
+		"alertId", alertID,
+		"postId", post.Id,
+		"fromChannelId", post.ChannelId,
+		"toChannelId", channelID,
+		"forwardedPostId", forwarded.Id)
+
+	confirmation := "Forwarded the alert."
+	if toChannel, appErr := p.API.GetChannel(channelID); appErr == nil {
+		confirmation = fmt.Sprintf("Forwarded the alert to ~%s.", toChannel.Name)
+	}
+	p.API.SendEphemeralPost(userID, &model.Post{
+		UserId:    p.botID,
+		ChannelId: post.ChannelId,
+		RootId:    post.RootId,
+		Message:   confirmation,
+	})
+	w.WriteHeader(http.StatusOK)
+}
+
+// checkForwardChannel returns why an alert can't be forwarded to a channel, or an empty string
+// if the user may forward it there
+func (p *Plugin) checkForwardChannel(userID, fromChannelID, channelID string) string {
+	if channelID == "" {
+		return "Please select a channel."
+	}
+	if channelID == fromChannelID {
+		return "The alert is already posted in this channel."
+	}
+	if _, appErr := p.API.GetChannelMember(channelID, userID); appErr != nil {
+		return "You can only forward alerts to channels you belong to."
+	}
+	if !p.API.HasPermissionToChannel(userID, channelID, model.PermissionCreatePost) {
+		return "You don't have permission to post in this channel."
+	}
+	return ""
+}
+
+// writeDialogResponse reports errors of a submitted dialog back to the dialog
+func (p *Plugin) writeDialogResponse(w http.ResponseWriter, response model.SubmitDialogResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("Failed to encode dialog response", "error", err.Error())
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
+)
+
+// serveAction sends a JSON request to a message action path on behalf of a user
+func serveAction(t *testing.T, p *Plugin, path, userID string, request any) *httptest.ResponseRecorder {
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(string(body)))
+	r.Header.Set("Mattermost-User-ID", userID)
+	w := httptest.NewRecorder()
+	p.ServeHTTP(nil, w, r)
+	return w
+}
+
+func TestForwardAlert_OpensDialog(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	p := newCommandTestPlugin(api)
+
+	api.On("GetPost", "post-1").Return(&model.Post{Id: "post-1", ChannelId: "channel-1"}, nil)
+	api.On("HasPermissionToChannel", "user-1", "channel-1", model.PermissionReadChannel).Return(true)
+	api.On("HasPermissionToChannel", "outsider", "channel-1", model.PermissionReadChannel).Return(false)
+	api.On("OpenInteractiveDialog", mock.MatchedBy(func(request model.OpenDialogRequest) bool {
+		return request.TriggerId == "trigger-1" &&
+			request.URL == "/plugins/"+pluginID+forwardDialogPath &&
+			request.Dialog.State == "post-1" &&
+			len(request.Dialog.Elements) == 1 &&
+			request.Dialog.Elements[0].DataSource == "channels"
+	})).Return(nil).Once()
+
+	w := serveAction(t, p, forwardActionPath, "user-1", model.PostActionIntegrationRequest{PostId: "post-1", TriggerId: "trigger-1"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serveAction(t, p, forwardActionPath, "outsider", model.PostActionIntegrationRequest{PostId: "post-1", TriggerId: "trigger-2"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestSubmitForwardAlert(t *testing.T) {
+	newForwardTestPlugin := func(t *testing.T) (*Plugin, *plugintest.API) {
+		api := &plugintest.API{}
+		t.Cleanup(func() { api.AssertExpectations(t) })
+
+		alertPost := &model.Post{Id: "post-1", ChannelId: "channel-1", Type: model.PostTypeSlackAttachment, Message: "@channel :red_circle: **Flash**"}
+		model.ParseSlackAttachment(alertPost, []*model.SlackAttachment{{Text: "### Warehouse fire"}})
+		alertPost.AddProp(poster.AlertIDProp, "alert-1")
+		api.On("GetPost", "post-1").Return(alertPost, nil)
+		api.On("HasPermissionToChannel", "user-1", "channel-1", model.PermissionReadChannel).Return(true)
+
+		p := newCommandTestPlugin(api)
+		p.botID = "bot-id"
+		p.poster = poster.New(api, "bot-id")
+		return p, api
+	}
+
+	submit := func(t *testing.T, p *Plugin, channelID string) (*httptest.ResponseRecorder, model.SubmitDialogResponse) {
+		w := serveAction(t, p, forwardDialogPath, "user-1", model.SubmitDialogRequest{
+			State:      "post-1",
+			Submission: map[string]any{forwardChannelField: channelID},
+		})
+
+		var response model.SubmitDialogResponse
+		if w.Body.Len() > 0 {
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		}
+		return w, response
+	}
+
+	t.Run("forwards the alert", func(t *testing.T) {
+		p, api := newForwardTestPlugin(t)
+		api.On("GetChannelMember", "channel-2", "user-1").Return(&model.ChannelMember{}, nil)
+		api.On("HasPermissionToChannel", "user-1", "channel-2", model.PermissionCreatePost).Return(true)
+		api.On("GetChannel", "channel-1").Return(&model.Channel{Id: "channel-1", Name: "security-ops"}, nil)
+		api.On("GetChannel", "channel-2").Return(&model.Channel{Id: "channel-2", Name: "facilities"}, nil)
+		api.On("GetUser", "user-1").Return(&model.User{Id: "user-1", Username: "alice"}, nil)
+
+		var forwarded *model.Post
+		api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+			forwarded = args.Get(0).(*model.Post)
+		}).Return(&model.Post{Id: "post-2"}, nil).Once()
+		api.On("LogInfo", "Audit: alert forwarded", "userId", "user-1",
+			"alertId", "alert-1", "postId", "post-1", "fromChannelId", "channel-1",
+			"toChannelId", "channel-2", "forwardedPostId", "post-2").Once()
+		api.On("SendEphemeralPost", "user-1", mock.MatchedBy(func(post *model.Post) bool {
+			return post.ChannelId == "channel-1" && post.Message == "Forwarded the alert to ~facilities."
+		})).Return(&model.Post{}).Once()
+
+		w, response := submit(t, p, "channel-2")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, response.Errors)
+
+		require.NotNil(t, forwarded)
+		assert.Equal(t, "channel-2", forwarded.ChannelId)
+		assert.Equal(t, ":red_circle: **Flash**", forwarded.Message)
+		assert.Equal(t, "Forwarded from ~security-ops by @alice", forwarded.Attachments()[0].Footer)
+	})
+
+	t.Run("channel the user doesn't belong to", func(t *testing.T) {
+		p, api := newForwardTestPlugin(t)
+		api.On("GetChannelMember", "channel-3", "user-1").Return(nil, &model.AppError{Message: "not found"})
+
+		w, response := submit(t, p, "channel-3")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "You can only forward alerts to channels you belong to.", response.Errors[forwardChannelField])
+	})
+
+	t.Run("channel the user can't post in", func(t *testing.T) {
+		p, api := newForwardTestPlugin(t)
+		api.On("GetChannelMember", "channel-2", "user-1").Return(&model.ChannelMember{}, nil)
+		api.On("HasPermissionToChannel", "user-1", "channel-2", model.PermissionCreatePost).Return(false)
+
+		_, response := submit(t, p, "channel-2")
+		assert.Equal(t, "You don't have permission to post in this channel.", response.Errors[forwardChannelField])
+	})
+
+	t.Run("same or no channel", func(t *testing.T) {
+		p, _ := newForwardTestPlugin(t)
+
+		_, response := submit(t, p, "channel-1")
+		assert.Equal(t, "The alert is already posted in this channel.", response.Errors[forwardChannelField])
+
+		_, response = submit(t, p, "")
+		assert.Equal(t, "Please select a channel.", response.Errors[forwardChannelField])
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		p := newCommandTestPlugin(api)
+
+		w := serveAction(t, p, forwardDialogPath, "user-1", model.SubmitDialogRequest{State: "post-1", Cancelled: true})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// kvKeyGlobalPause stores whether the global pause is on and the backends it paused
+const kvKeyGlobalPause = "global_pause"
+
+// globalPauseState records the global pause applied to the backends
+type globalPauseState struct {
+	Paused bool `json:"paused"`
+
+	// BackendIDs are the backends paused by the global pause, resumed when it is turned off.
+	// Backends that were already paused on their own are not included, so they stay paused.
+	BackendIDs []string `json:"backendIds"`
+}
+
+// applyGlobalPause pauses every registered backend when the global pause is turned on, and
+// resumes the backends it paused when it is turned off. Backends are paused like with
+// /dataminr pause: they stay registered and keep their state, and a poll cycle in progress
+// finishes before polling stops. Backends added while the global pause is on are paused too.
+// The change is claimed in the KV store so a single cluster node applies it and notifies the
+// subscribed admins.
+func (p *Plugin) applyGlobalPause(paused bool) {
+	data, appErr := p.API.KVGet(kvKeyGlobalPause)
+	if appErr != nil {
+		p.API.LogError("Failed to get global pause state", "error", appErr.Error())
+		return
+	}
+
+	var state globalPauseState
+	if data != nil {
+		if err := json.Unmarshal(data, &state); err != nil {
+			p.API.LogWarn("Failed to unmarshal global pause state", "error", err.Error())
+		}
+	}
+	if !paused && !state.Paused {
+		return
+	}
+
+	next := globalPauseState{Paused: paused}
+	if paused {
+		if state.Paused {
+			next.BackendIDs = slices.Clone(state.BackendIDs)
+		}
+		for _, b := range p.registry.List() {
+			if slices.Contains(next.BackendIDs, b.GetID()) || b.GetStatus().Paused {
+				continue
+			}
+			next.BackendIDs = append(next.BackendIDs, b.GetID())
+		}
+		if state.Paused && len(next.BackendIDs) == len(state.BackendIDs) {
+			return
+		}
+	}
+
+	newData, err := json.Marshal(next)
+	if err != nil {
+		p.API.LogError("Failed to marshal global pause state", "error", err.Error())
+		return
+	}
+	saved, appErr := p.API.KVSetWithOptions(kvKeyGlobalPause, newData, model.PluginKVSetOptions{Atomic: true, OldValue: data})
+	if appErr != nil {
+		p.API.LogError("Failed to save global pause state", "error", appErr.Error())
+		return
+	}
+	if !saved {
+		// Another cluster node applied the change
+		return
+	}
+
+	if !paused {
+		resumed := 0
+		for _, id := range state.BackendIDs {
+			if p.registry.Get(id) == nil {
+				continue
+			}
+			if err := p.registry.Resume(id); err != nil {
+				p.API.LogWarn("Failed to resume backend after the global pause", "id", id, "error", err.Error())
+				continue
+			}
+			resumed++
+		}
+
+		p.API.LogInfo("Global pause turned off", "resumed", resumed)
+		p.notifyAdmins(fmt.Sprintf(":arrow_forward: The global pause was turned off, %d backends resumed polling.", resumed))
+		return
+	}
+
+	var newlyPaused []string
+	if state.Paused {
+		newlyPaused = next.BackendIDs[len(state.BackendIDs):]
+	} else {
+		newlyPaused = next.BackendIDs
+	}
+	for _, id := range newlyPaused {
+		if err := p.registry.Pause(id, time.Time{}); err != nil {
+			p.API.LogWarn("Failed to pause backend for the global pause", "id", id, "error", err.Error())
+		}
+	}
+
+	if !state.Paused {
+		p.API.LogInfo("Global pause turned on", "paused", len(newlyPaused))
+		p.notifyAdmins(fmt.Sprintf(":pause_button: The global pause was turned on, %d backends stopped polling. Poll cycles in progress finish first. Turn off **Global Pause** in the plugin settings to resume.", len(newlyPaused)))
+	}
+}
+
+// notifyAdmins sends a message to the admins subscribed to backend state changes
+func (p *Plugin) notifyAdmins(message string) {
+	if p.statusNotifier != nil {
+		p.statusNotifier.Notify(message)
+	}
+}
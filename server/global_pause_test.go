@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// newGlobalPauseTestPlugin creates a plugin with two running backends and one paused on its
+// own, over an in-memory KV store, and returns it with the messages sent to subscribed admins.
+// Unless claimed, saving the global pause state fails as if another cluster node saved it first.
+func newGlobalPauseTestPlugin(t *testing.T, claimed bool) (*Plugin, map[string][]byte, *[]string) {
+	kv := map[string][]byte{kvKeyStatusSubscribers: []byte(`["admin-id"]`)}
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return kv[key], nil
+	})
+	api.On("KVSetWithOptions", kvKeyGlobalPause, mock.Anything, mock.Anything).Return(func(key string, value []byte, _ model.PluginKVSetOptions) (bool, *model.AppError) {
+		if !claimed {
+			return false, nil
+		}
+		kv[key] = value
+		return true, nil
+	})
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("GetDirectChannel", "admin-id", "bot-id").Return(&model.Channel{Id: "dm-channel"}, nil)
+
+	var notices []string
+	api.On("CreatePost", mock.Anything).Return(func(post *model.Post) (*model.Post, *model.AppError) {
+		notices = append(notices, post.Message)
+		return post, nil
+	})
+
+	p := newCommandTestPlugin(api)
+	p.registry = backend.NewRegistry()
+	p.statusNotifier = NewStatusNotifier(api, "bot-id", p.registry)
+	require.NoError(t, p.registry.Register(&fakeBackend{id: "weather", name: "Weather"}))
+	require.NoError(t, p.registry.Register(&fakeBackend{id: "security", name: "Security"}))
+	require.NoError(t, p.registry.Register(&fakeBackend{id: "muted", name: "Muted", status: backend.Status{Paused: true}}))
+	return p, kv, &notices
+}
+
+// isPaused reports whether a registered backend is paused
+func isPaused(p *Plugin, id string) bool {
+	return p.registry.Get(id).GetStatus().Paused
+}
+
+func TestApplyGlobalPause(t *testing.T) {
+	t.Run("pauses and resumes the backends", func(t *testing.T) {
+		p, kv, notices := newGlobalPauseTestPlugin(t, true)
+
+		p.applyGlobalPause(true)
+		assert.True(t, isPaused(p, "weather"))
+		assert.True(t, isPaused(p, "security"))
+		require.Len(t, *notices, 1)
+		assert.Contains(t, (*notices)[0], "The global pause was turned on, 2 backends stopped polling")
+
+		var state globalPauseState
+		require.NoError(t, json.Unmarshal(kv[kvKeyGlobalPause], &state))
+		assert.True(t, state.Paused)
+		assert.ElementsMatch(t, []string{"weather", "security"}, state.BackendIDs)
+
+		// Applying the same setting again changes nothing
+		p.applyGlobalPause(true)
+		assert.Len(t, *notices, 1)
+
+		p.applyGlobalPause(false)
+		assert.False(t, isPaused(p, "weather"))
+		assert.False(t, isPaused(p, "security"))
+		assert.True(t, isPaused(p, "muted"), "backends paused on their own stay paused")
+		require.Len(t, *notices, 2)
+		assert.Equal(t, ":arrow_forward: The global pause was turned off, 2 backends resumed polling.", (*notices)[1])
+	})
+
+	t.Run("pauses backends added while paused", func(t *testing.T) {
+		p, kv, notices := newGlobalPauseTestPlugin(t, true)
+		p.applyGlobalPause(true)
+
+		require.NoError(t, p.registry.Register(&fakeBackend{id: "added", name: "Added"}))
+		p.applyGlobalPause(true)
+		assert.True(t, isPaused(p, "added"))
+		assert.Len(t, *notices, 1, "only turning the global pause on or off is announced")
+
+		var state globalPauseState
+		require.NoError(t, json.Unmarshal(kv[kvKeyGlobalPause], &state))
+		assert.ElementsMatch(t, []string{"weather", "security", "added"}, state.BackendIDs)
+	})
+
+	t.Run("not paused", func(t *testing.T) {
+		p, kv, notices := newGlobalPauseTestPlugin(t, true)
+
+		p.applyGlobalPause(false)
+		assert.False(t, isPaused(p, "weather"))
+		assert.NotContains(t, kv, kvKeyGlobalPause)
+		assert.Empty(t, *notices)
+	})
+
+	t.Run("another node applied the change", func(t *testing.T) {
+		p, _, notices := newGlobalPauseTestPlugin(t, false)
+
+		p.applyGlobalPause(true)
+		assert.False(t, isPaused(p, "weather"))
+		assert.Empty(t, *notices)
+	})
+}
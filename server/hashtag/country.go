@@ -77,7 +77,7 @@ var stateZipPattern = regexp.MustCompile(`(?i)^([A-Za-z]{2})\s+\S*\d\S*$`)
 // Returns: Array of hashtag strings (e.g., ["#SanFrancisco", "#California", "#UnitedStates"])
 //
 //	Empty array if all parts are dropped or no valid location found
-func extractCountryTags(locationAddress string) []string {
+func extractCountryTags(locationAddress string, table LocaleTable) []string {
 	if locationAddress == "" {
 		return nil
 	}
@@ -114,13 +114,13 @@ func extractCountryTags(locationAddress string) []string {
 	// Step 2: Process based on number of parts
 	switch len(cleanedParts) {
 	case 1:
-		return handleSinglePart(cleanedParts[0])
+		return handleSinglePart(cleanedParts[0], table)
 	case 2:
-		return handleTwoParts(cleanedParts[0], cleanedParts[1])
+		return handleTwoParts(cleanedParts[0], cleanedParts[1], table)
 	default:
 		// Use last 3 parts only
 		startIdx := len(cleanedParts) - 3
-		return handleThreeParts(cleanedParts[startIdx], cleanedParts[startIdx+1], cleanedParts[startIdx+2])
+		return handleThreeParts(cleanedParts[startIdx], cleanedParts[startIdx+1], cleanedParts[startIdx+2], table)
 	}
 }
 
@@ -136,12 +136,12 @@ func containsNumber(s string) bool {
 
 // handleSinglePart processes a single location part.
 // Checks if it's a country code first, expands to full name if possible.
-func handleSinglePart(part string) []string {
+func handleSinglePart(part string, table LocaleTable) []string {
 	// Always check if it's a country first
 	country := detectCountry(part)
 	if country != countries.Unknown {
 		// Use full country name
-		return []string{"#" + camelCase(country.String())}
+		return []string{countryTag(country, table)}
 	}
 
 	// Not a country, just use as-is in CamelCase
@@ -151,7 +151,7 @@ func handleSinglePart(part string) []string {
 // handleTwoParts processes two location parts.
 // First part always becomes a hashtag.
 // Second part: if 2 chars and not a US/Canada state, try to expand as country; if >2 chars, check if country.
-func handleTwoParts(first, second string) []string {
+func handleTwoParts(first, second string, table LocaleTable) []string {
 	var tags []string
 
 	// First part always becomes a hashtag
@@ -173,7 +173,7 @@ func handleTwoParts(first, second string) []string {
 		country := detectCountry(second)
 		if country != countries.Unknown {
 			// It's a valid country code, expand to full name
-			tags = append(tags, "#"+camelCase(country.String()))
+			tags = append(tags, countryTag(country, table))
 		}
 		// If not a country either, skip it
 		return tags
@@ -183,7 +183,7 @@ func handleTwoParts(first, second string) []string {
 	country := detectCountry(second)
 	if country != countries.Unknown {
 		// Use full country name
-		tags = append(tags, "#"+camelCase(country.String()))
+		tags = append(tags, countryTag(country, table))
 	} else {
 		// Not a country but more than 2 chars, use it as-is
 		tags = append(tags, "#"+camelCase(second))
@@ -194,7 +194,7 @@ func handleTwoParts(first, second string) []string {
 
 // handleThreeParts processes three location parts (City, State, Country).
 // Expects: City, State/Province, Country
-func handleThreeParts(city, state, countryPart string) []string {
+func handleThreeParts(city, state, countryPart string, table LocaleTable) []string {
 	var tags []string
 
 	// Detect the country (last part)
@@ -205,12 +205,12 @@ func handleThreeParts(city, state, countryPart string) []string {
 
 	// State/Province hashtag (second from last)
 	// Try to expand based on detected country
-	stateName := expandStateProvince(state, country)
+	stateName := expandStateProvince(state, country, table)
 	tags = append(tags, "#"+camelCase(stateName))
 
 	// Country hashtag (last part)
 	if country != countries.Unknown {
-		tags = append(tags, "#"+camelCase(country.String()))
+		tags = append(tags, countryTag(country, table))
 	} else {
 		// Couldn't detect country, use as-is
 		tags = append(tags, "#"+camelCase(countryPart))
@@ -230,29 +230,28 @@ func detectCountry(s string) countries.CountryCode {
 	return countries.ByName(s)
 }
 
-// expandStateProvince tries to expand a state/province code to full name based on country.
+// countryTag builds the hashtag for a detected country using the locale table.
+// Falls back to the English country name if the table has no translation.
+func countryTag(country countries.CountryCode, table LocaleTable) string {
+	name, exists := table.CountryName(country)
+	if !exists {
+		name = country.String()
+	}
+	return "#" + camelCase(name)
+}
+
+// expandStateProvince tries to expand a state/province code to its localized full name based on country.
 // Only handles USA and Canada.
 // Only expands two-letter codes; longer strings are returned as-is.
-func expandStateProvince(state string, country countries.CountryCode) string {
+func expandStateProvince(state string, country countries.CountryCode, table LocaleTable) string {
 	// Only expand if we have a two-letter code
 	if len(state) != 2 {
 		return state
 	}
 
-	stateUpper := strings.ToUpper(state)
-
-	// USA: expand state codes
-	if country == countries.US {
-		if fullName, exists := usStates[stateUpper]; exists {
-			return fullName
-		}
-	}
-
-	// Canada: expand province/territory codes
-	if country == countries.CA {
-		if fullName, exists := canadianProvinces[stateUpper]; exists {
-			return fullName
-		}
+	// USA and Canada codes are expanded through the locale table
+	if fullName, exists := table.StateName(country, strings.ToUpper(state)); exists {
+		return fullName
 	}
 
 	// Not USA/Canada or code not found, return as-is
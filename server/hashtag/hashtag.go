@@ -2,10 +2,36 @@ package hashtag
 
 import (
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 )
 
+// Options controls how hashtags are generated for an alert.
+// The zero value generates every hashtag category in English with no limit.
+type Options struct {
+	// Locale selects the language used for country and state hashtags (e.g., "fr").
+	// Empty or unsupported locales use English.
+	Locale string
+
+	// SkipAlertLevel omits the alert level hashtag
+	SkipAlertLevel bool
+
+	// SkipLocation omits the city, state and country hashtags
+	SkipLocation bool
+
+	// SkipTopics omits the topic hashtags
+	SkipTopics bool
+
+	// MaxHashtags caps the number of generated hashtags (0 means no limit)
+	MaxHashtags int
+
+	// CustomHashtags are appended to every alert after the generated hashtags.
+	// They are always included and do not count toward MaxHashtags.
+	CustomHashtags []string
+}
+
 // Generate creates formatted hashtag text from alert data.
 //
 // This is the main function that coordinates all hashtag extraction.
@@ -14,34 +40,59 @@ import (
 // 1. Alert level (#Flash, #Urgent, #Alert)
 // 2. Countries (up to 2)
 // 3. Topics (all, deduplicated)
+// 4. Custom hashtags
+//
+// Categories can be skipped and the generated hashtags capped through opts.
 //
 // Returns formatted string (e.g., "🏷️ #Flash, #Ukraine, #Fire")
-func Generate(alert backend.Alert) string {
+func Generate(alert backend.Alert, opts Options) string {
 	var allTags []string
 
 	// 1. Alert level (always first)
-	alertLevelTag := extractAlertLevelTag(alert.AlertType)
-	allTags = append(allTags, alertLevelTag)
+	if !opts.SkipAlertLevel {
+		alertLevelTag := extractAlertLevelTag(alert.AlertType)
+		allTags = append(allTags, alertLevelTag)
+	}
 
 	// 2. Countries (if location available)
-	if alert.Location != nil && alert.Location.Address != "" {
-		countryTags := extractCountryTags(alert.Location.Address)
+	if !opts.SkipLocation && alert.Location != nil && alert.Location.Address != "" {
+		countryTags := extractCountryTags(alert.Location.Address, GetLocaleTable(opts.Locale))
 		allTags = append(allTags, countryTags...)
 	}
 
 	// 3. Topics (all topics, will be deduplicated)
-	if len(alert.Topics) > 0 {
+	if !opts.SkipTopics && len(alert.Topics) > 0 {
 		topicTags := extractTopicTags(alert.Topics)
 		allTags = append(allTags, topicTags...)
 	}
 
-	// Deduplicate while preserving order
+	// Deduplicate while preserving order, then apply the cap
 	uniqueTags := deduplicateTags(allTags)
+	if opts.MaxHashtags > 0 && len(uniqueTags) > opts.MaxHashtags {
+		uniqueTags = uniqueTags[:opts.MaxHashtags]
+	}
+
+	// 4. Custom hashtags (skipping any already generated)
+	if len(opts.CustomHashtags) > 0 {
+		uniqueTags = deduplicateTags(append(uniqueTags, customTags(opts.CustomHashtags)...))
+	}
 
 	// Format and return
 	return formatHashtagText(uniqueTags)
 }
 
+// customTags normalizes configured hashtags so each has a single leading '#'.
+func customTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name := strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if name != "" {
+			result = append(result, "#"+name)
+		}
+	}
+	return result
+}
+
 // extractAlertLevelTag extracts hashtag from alert type.
 func extractAlertLevelTag(alertType string) string {
 	if alertType == "" {
@@ -82,18 +133,15 @@ func formatHashtagText(tags []string) string {
 }
 
 // camelCase converts text to CamelCase by capitalizing first letter of each word
-// and removing spaces.
+// and removing spaces. Works on runes so localized names like "Égypte" stay intact.
 func camelCase(text string) string {
 	words := strings.Fields(text)
 	var result strings.Builder
 
 	for _, word := range words {
-		if len(word) > 0 {
-			result.WriteString(strings.ToUpper(word[:1]))
-			if len(word) > 1 {
-				result.WriteString(word[1:])
-			}
-		}
+		first, size := utf8.DecodeRuneInString(word)
+		result.WriteRune(unicode.ToUpper(first))
+		result.WriteString(word[size:])
 	}
 
 	return result.String()
@@ -25,7 +25,7 @@ func TestGenerate_ComplexMultiCategoryTopics(t *testing.T) {
 		},
 	}
 
-	result := Generate(alert)
+	result := Generate(alert, Options{})
 	// With new logic: All segments treated equally
 	// "Conflicts - Air" → #Conflicts, #Air
 	// "Disasters and Weather - Search and Rescue" → #Disasters, #Weather, #DisastersAndWeather, #Search, #Rescue, #SearchAndRescue
@@ -48,7 +48,7 @@ func TestGenerate_USALocation(t *testing.T) {
 		},
 	}
 
-	result := Generate(alert)
+	result := Generate(alert, Options{})
 
 	// Should have #Urgent, #SanFrancisco, #California, #UnitedStates, #Fire, #Emergency, #Response
 	assert.Contains(t, result, "#Urgent")
@@ -70,7 +70,7 @@ func TestGenerate_NoLocation(t *testing.T) {
 		},
 	}
 
-	result := Generate(alert)
+	result := Generate(alert, Options{})
 	// "Power Outage" is 2 words (no "and"), so creates #Power, #Outage
 	expected := "🏷️ #Alert, #Infrastructure, #Power, #Outage"
 
@@ -91,7 +91,7 @@ func TestGenerate_Deduplication(t *testing.T) {
 		},
 	}
 
-	result := Generate(alert)
+	result := Generate(alert, Options{})
 
 	// With new logic:
 	// "Fire - Structure Fire" → #Fire, #Structure, #Fire (deduped to one #Fire)
@@ -130,7 +130,7 @@ func TestGenerate_ThreeWordAndPhrases(t *testing.T) {
 		},
 	}
 
-	result := Generate(alert)
+	result := Generate(alert, Options{})
 
 	// 3-word phrases should have individual words AND combined version
 	assert.Contains(t, result, "#Command")
@@ -153,7 +153,7 @@ func TestGenerate_EmptyAlert(t *testing.T) {
 	// Test with minimal alert data
 	alert := backend.Alert{}
 
-	result := Generate(alert)
+	result := Generate(alert, Options{})
 	expected := "🏷️ #Alert" // Default alert type
 
 	assert.Equal(t, expected, result, "Should handle empty alert with default type")
@@ -165,7 +165,7 @@ func TestGenerate_OnlyAlertType(t *testing.T) {
 		AlertType: "Flash",
 	}
 
-	result := Generate(alert)
+	result := Generate(alert, Options{})
 	expected := "🏷️ #Flash"
 
 	assert.Equal(t, expected, result, "Should work with only alert type")
@@ -180,7 +180,7 @@ func TestGenerate_MultipleCountries(t *testing.T) {
 		},
 	}
 
-	result := Generate(alert)
+	result := Generate(alert, Options{})
 
 	// With the new logic, we take last 3 parts: France, Germany, Switzerland
 	// Interpreted as: City=France, State=Germany, Country=Switzerland
@@ -314,7 +314,7 @@ func TestExtractCountryTags(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := extractCountryTags(tt.address)
+			result := extractCountryTags(tt.address, englishTable{})
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -495,6 +495,74 @@ func BenchmarkGenerate(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		Generate(alert)
+		Generate(alert, Options{})
+	}
+}
+
+func TestGenerate_Options(t *testing.T) {
+	alert := backend.Alert{
+		AlertType: "Urgent",
+		Location: &backend.Location{
+			Address: "Kyiv, Ukraine",
+		},
+		Topics: []string{"Conflicts - Air", "Transportation - Aviation"},
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{
+			name:     "default options",
+			opts:     Options{},
+			expected: "🏷️ #Urgent, #Kyiv, #Ukraine, #Conflicts, #Air, #Transportation, #Aviation",
+		},
+		{
+			name:     "skip alert level",
+			opts:     Options{SkipAlertLevel: true},
+			expected: "🏷️ #Kyiv, #Ukraine, #Conflicts, #Air, #Transportation, #Aviation",
+		},
+		{
+			name:     "skip location",
+			opts:     Options{SkipLocation: true},
+			expected: "🏷️ #Urgent, #Conflicts, #Air, #Transportation, #Aviation",
+		},
+		{
+			name:     "skip topics",
+			opts:     Options{SkipTopics: true},
+			expected: "🏷️ #Urgent, #Kyiv, #Ukraine",
+		},
+		{
+			name:     "cap generated hashtags",
+			opts:     Options{MaxHashtags: 3},
+			expected: "🏷️ #Urgent, #Kyiv, #Ukraine",
+		},
+		{
+			name:     "custom hashtags are appended after the cap",
+			opts:     Options{MaxHashtags: 2, CustomHashtags: []string{"SOC", "#NightShift"}},
+			expected: "🏷️ #Urgent, #Kyiv, #SOC, #NightShift",
+		},
+		{
+			name:     "custom hashtags are deduplicated",
+			opts:     Options{SkipTopics: true, CustomHashtags: []string{"#ukraine", "SOC", "soc"}},
+			expected: "🏷️ #Urgent, #Kyiv, #Ukraine, #SOC",
+		},
+		{
+			name:     "everything skipped",
+			opts:     Options{SkipAlertLevel: true, SkipLocation: true, SkipTopics: true},
+			expected: "",
+		},
+		{
+			name:     "only custom hashtags",
+			opts:     Options{SkipAlertLevel: true, SkipLocation: true, SkipTopics: true, CustomHashtags: []string{"SOC"}},
+			expected: "🏷️ #SOC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Generate(alert, tt.opts))
+		})
 	}
 }
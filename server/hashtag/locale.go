@@ -0,0 +1,203 @@
+package hashtag
+
+import (
+	"strings"
+
+	"github.com/biter777/countries"
+)
+
+// LocaleTable provides localized names for countries and states/provinces.
+// Lookups report false when the table has no translation so callers can fall back to English.
+type LocaleTable interface {
+	// CountryName returns the localized name of a country
+	CountryName(country countries.CountryCode) (string, bool)
+
+	// StateName returns the localized name of a state/province code within a country
+	StateName(country countries.CountryCode, stateCode string) (string, bool)
+}
+
+// englishTable is the default table backed by the country library and the state maps
+type englishTable struct{}
+
+func (englishTable) CountryName(country countries.CountryCode) (string, bool) {
+	if country == countries.Unknown {
+		return "", false
+	}
+	return country.String(), true
+}
+
+func (englishTable) StateName(country countries.CountryCode, stateCode string) (string, bool) {
+	var name string
+	var exists bool
+	switch country {
+	case countries.US:
+		name, exists = usStates[stateCode]
+	case countries.CA:
+		name, exists = canadianProvinces[stateCode]
+	}
+	return name, exists
+}
+
+// mapTable is a LocaleTable backed by static translation maps
+type mapTable struct {
+	countryNames map[countries.CountryCode]string
+	stateNames   map[countries.CountryCode]map[string]string
+}
+
+func (t mapTable) CountryName(country countries.CountryCode) (string, bool) {
+	name, exists := t.countryNames[country]
+	return name, exists
+}
+
+func (t mapTable) StateName(country countries.CountryCode, stateCode string) (string, bool) {
+	name, exists := t.stateNames[country][stateCode]
+	return name, exists
+}
+
+// fallbackTable looks up names in a localized table first and falls back to English
+type fallbackTable struct {
+	localized LocaleTable
+}
+
+func (t fallbackTable) CountryName(country countries.CountryCode) (string, bool) {
+	if name, exists := t.localized.CountryName(country); exists {
+		return name, true
+	}
+	return englishTable{}.CountryName(country)
+}
+
+func (t fallbackTable) StateName(country countries.CountryCode, stateCode string) (string, bool) {
+	if name, exists := t.localized.StateName(country, stateCode); exists {
+		return name, true
+	}
+	return englishTable{}.StateName(country, stateCode)
+}
+
+// localeTables holds the translation tables for supported non-English locales
+var localeTables = map[string]LocaleTable{
+	"fr": frenchTable,
+	"de": germanTable,
+	"es": spanishTable,
+}
+
+// GetLocaleTable returns the table for a locale, falling back to English for
+// missing translations. Unknown or empty locales use the English table.
+func GetLocaleTable(locale string) LocaleTable {
+	if table, exists := localeTables[strings.ToLower(strings.TrimSpace(locale))]; exists {
+		return fallbackTable{localized: table}
+	}
+	return englishTable{}
+}
+
+var frenchTable = mapTable{
+	countryNames: map[countries.CountryCode]string{
+		countries.US: "États-Unis", countries.GB: "Royaume-Uni", countries.CA: "Canada",
+		countries.MX: "Mexique", countries.BR: "Brésil", countries.AR: "Argentine",
+		countries.CO: "Colombie", countries.CL: "Chili", countries.PE: "Pérou",
+		countries.VE: "Venezuela", countries.DE: "Allemagne", countries.FR: "France",
+		countries.ES: "Espagne", countries.IT: "Italie", countries.PT: "Portugal",
+		countries.NL: "Pays-Bas", countries.BE: "Belgique", countries.CH: "Suisse",
+		countries.AT: "Autriche", countries.PL: "Pologne", countries.SE: "Suède",
+		countries.NO: "Norvège", countries.DK: "Danemark", countries.FI: "Finlande",
+		countries.IE: "Irlande", countries.GR: "Grèce", countries.TR: "Turquie",
+		countries.RU: "Russie", countries.UA: "Ukraine", countries.BY: "Biélorussie",
+		countries.CN: "Chine", countries.JP: "Japon", countries.KR: "Corée du Sud",
+		countries.KP: "Corée du Nord", countries.IN: "Inde", countries.PK: "Pakistan",
+		countries.AF: "Afghanistan", countries.IR: "Iran", countries.IQ: "Irak",
+		countries.SY: "Syrie", countries.IL: "Israël", countries.LB: "Liban",
+		countries.JO: "Jordanie", countries.SA: "Arabie saoudite", countries.AE: "Émirats arabes unis",
+		countries.YE: "Yémen", countries.EG: "Égypte", countries.LY: "Libye",
+		countries.MA: "Maroc", countries.DZ: "Algérie", countries.TN: "Tunisie",
+		countries.NG: "Nigeria", countries.ZA: "Afrique du Sud", countries.KE: "Kenya",
+		countries.ET: "Éthiopie", countries.SD: "Soudan", countries.AU: "Australie",
+		countries.NZ: "Nouvelle-Zélande", countries.ID: "Indonésie", countries.PH: "Philippines",
+		countries.TH: "Thaïlande", countries.VN: "Vietnam", countries.MY: "Malaisie",
+		countries.SG: "Singapour", countries.TW: "Taïwan", countries.HK: "Hong Kong",
+	},
+	stateNames: map[countries.CountryCode]map[string]string{
+		countries.US: {
+			"CA": "Californie", "FL": "Floride", "GA": "Géorgie", "HI": "Hawaï",
+			"LA": "Louisiane", "NC": "Caroline du Nord", "SC": "Caroline du Sud",
+			"ND": "Dakota du Nord", "SD": "Dakota du Sud", "NM": "Nouveau-Mexique",
+			"PA": "Pennsylvanie", "VA": "Virginie", "WV": "Virginie-Occidentale",
+		},
+		countries.CA: {
+			"BC": "Colombie-Britannique", "NB": "Nouveau-Brunswick", "NL": "Terre-Neuve-et-Labrador",
+			"NS": "Nouvelle-Écosse", "NT": "Territoires du Nord-Ouest", "PE": "Île-du-Prince-Édouard",
+			"QC": "Québec",
+		},
+	},
+}
+
+var germanTable = mapTable{
+	countryNames: map[countries.CountryCode]string{
+		countries.US: "Vereinigte Staaten", countries.GB: "Vereinigtes Königreich", countries.CA: "Kanada",
+		countries.MX: "Mexiko", countries.BR: "Brasilien", countries.AR: "Argentinien",
+		countries.CO: "Kolumbien", countries.CL: "Chile", countries.PE: "Peru",
+		countries.VE: "Venezuela", countries.DE: "Deutschland", countries.FR: "Frankreich",
+		countries.ES: "Spanien", countries.IT: "Italien", countries.PT: "Portugal",
+		countries.NL: "Niederlande", countries.BE: "Belgien", countries.CH: "Schweiz",
+		countries.AT: "Österreich", countries.PL: "Polen", countries.SE: "Schweden",
+		countries.NO: "Norwegen", countries.DK: "Dänemark", countries.FI: "Finnland",
+		countries.IE: "Irland", countries.GR: "Griechenland", countries.TR: "Türkei",
+		countries.RU: "Russland", countries.UA: "Ukraine", countries.BY: "Belarus",
+		countries.CN: "China", countries.JP: "Japan", countries.KR: "Südkorea",
+		countries.KP: "Nordkorea", countries.IN: "Indien", countries.PK: "Pakistan",
+		countries.AF: "Afghanistan", countries.IR: "Iran", countries.IQ: "Irak",
+		countries.SY: "Syrien", countries.IL: "Israel", countries.LB: "Libanon",
+		countries.JO: "Jordanien", countries.SA: "Saudi-Arabien", countries.AE: "Vereinigte Arabische Emirate",
+		countries.YE: "Jemen", countries.EG: "Ägypten", countries.LY: "Libyen",
+		countries.MA: "Marokko", countries.DZ: "Algerien", countries.TN: "Tunesien",
+		countries.NG: "Nigeria", countries.ZA: "Südafrika", countries.KE: "Kenia",
+		countries.ET: "Äthiopien", countries.SD: "Sudan", countries.AU: "Australien",
+		countries.NZ: "Neuseeland", countries.ID: "Indonesien", countries.PH: "Philippinen",
+		countries.TH: "Thailand", countries.VN: "Vietnam", countries.MY: "Malaysia",
+		countries.SG: "Singapur", countries.TW: "Taiwan", countries.HK: "Hongkong",
+	},
+	stateNames: map[countries.CountryCode]map[string]string{
+		countries.US: {
+			"CA": "Kalifornien", "PA": "Pennsylvanien",
+		},
+		countries.CA: {
+			"BC": "Britisch-Kolumbien", "NS": "Neuschottland",
+		},
+	},
+}
+
+var spanishTable = mapTable{
+	countryNames: map[countries.CountryCode]string{
+		countries.US: "Estados Unidos", countries.GB: "Reino Unido", countries.CA: "Canadá",
+		countries.MX: "México", countries.BR: "Brasil", countries.AR: "Argentina",
+		countries.CO: "Colombia", countries.CL: "Chile", countries.PE: "Perú",
+		countries.VE: "Venezuela", countries.DE: "Alemania", countries.FR: "Francia",
+		countries.ES: "España", countries.IT: "Italia", countries.PT: "Portugal",
+		countries.NL: "Países Bajos", countries.BE: "Bélgica", countries.CH: "Suiza",
+		countries.AT: "Austria", countries.PL: "Polonia", countries.SE: "Suecia",
+		countries.NO: "Noruega", countries.DK: "Dinamarca", countries.FI: "Finlandia",
+		countries.IE: "Irlanda", countries.GR: "Grecia", countries.TR: "Turquía",
+		countries.RU: "Rusia", countries.UA: "Ucrania", countries.BY: "Bielorrusia",
+		countries.CN: "China", countries.JP: "Japón", countries.KR: "Corea del Sur",
+		countries.KP: "Corea del Norte", countries.IN: "India", countries.PK: "Pakistán",
+		countries.AF: "Afganistán", countries.IR: "Irán", countries.IQ: "Irak",
+		countries.SY: "Siria", countries.IL: "Israel", countries.LB: "Líbano",
+		countries.JO: "Jordania", countries.SA: "Arabia Saudita", countries.AE: "Emiratos Árabes Unidos",
+		countries.YE: "Yemen", countries.EG: "Egipto", countries.LY: "Libia",
+		countries.MA: "Marruecos", countries.DZ: "Argelia", countries.TN: "Túnez",
+		countries.NG: "Nigeria", countries.ZA: "Sudáfrica", countries.KE: "Kenia",
+		countries.ET: "Etiopía", countries.SD: "Sudán", countries.AU: "Australia",
+		countries.NZ: "Nueva Zelanda", countries.ID: "Indonesia", countries.PH: "Filipinas",
+		countries.TH: "Tailandia", countries.VN: "Vietnam", countries.MY: "Malasia",
+		countries.SG: "Singapur", countries.TW: "Taiwán", countries.HK: "Hong Kong",
+	},
+	stateNames: map[countries.CountryCode]map[string]string{
+		countries.US: {
+			"NM": "Nuevo México", "NY": "Nueva York", "NJ": "Nueva Jersey",
+			"NC": "Carolina del Norte", "SC": "Carolina del Sur",
+			"ND": "Dakota del Norte", "SD": "Dakota del Sur",
+			"PA": "Pensilvania", "HI": "Hawái", "LA": "Luisiana",
+		},
+		countries.CA: {
+			"BC": "Columbia Británica", "NS": "Nueva Escocia", "QC": "Quebec",
+		},
+	},
+}
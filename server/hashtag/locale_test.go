@@ -0,0 +1,92 @@
+package hashtag
+
+import (
+	"testing"
+
+	"github.com/biter777/countries"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestGetLocaleTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		country  countries.CountryCode
+		expected string
+	}{
+		{"empty locale uses English", "", countries.DE, "Germany"},
+		{"English locale", "en", countries.DE, "Germany"},
+		{"unknown locale uses English", "xx", countries.DE, "Germany"},
+		{"French", "fr", countries.DE, "Allemagne"},
+		{"German", "de", countries.FR, "Frankreich"},
+		{"Spanish", "es", countries.US, "Estados Unidos"},
+		{"locale is case-insensitive", "FR", countries.DE, "Allemagne"},
+		{"missing translation falls back to English", "fr", countries.IS, "Iceland"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, exists := GetLocaleTable(tt.locale).CountryName(tt.country)
+			assert.True(t, exists)
+			assert.Equal(t, tt.expected, name)
+		})
+	}
+}
+
+func TestGetLocaleTable_StateName(t *testing.T) {
+	table := GetLocaleTable("fr")
+
+	name, exists := table.StateName(countries.CA, "QC")
+	assert.True(t, exists)
+	assert.Equal(t, "Québec", name)
+
+	// Falls back to English when there is no French name
+	name, exists = table.StateName(countries.US, "TX")
+	assert.True(t, exists)
+	assert.Equal(t, "Texas", name)
+
+	_, exists = table.StateName(countries.DE, "BE")
+	assert.False(t, exists)
+}
+
+func TestGetLocaleTable_CoversSupportedLocales(t *testing.T) {
+	for locale := range backend.SupportedHashtagLocales {
+		if locale == "en" {
+			continue
+		}
+		_, exists := localeTables[locale]
+		assert.True(t, exists, "missing locale table for %s", locale)
+	}
+}
+
+func TestGenerate_Localized(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		address  string
+		expected string
+	}{
+		{"country name", "fr", "Berlin, Germany", "🏷️ #Alert, #Berlin, #Allemagne"},
+		{"country code", "de", "Paris, FR", "🏷️ #Alert, #Paris, #Frankreich"},
+		{"state and country", "fr", "Montreal, QC, Canada", "🏷️ #Alert, #Montreal, #Québec, #Canada"},
+		{"multi-word localized name", "es", "Austin, TX, USA", "🏷️ #Alert, #Austin, #Texas, #EstadosUnidos"},
+		{"accented first letter", "fr", "Cairo, Egypt", "🏷️ #Alert, #Cairo, #Égypte"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := backend.Alert{
+				AlertType: "Alert",
+				Location:  &backend.Location{Address: tt.address},
+			}
+			assert.Equal(t, tt.expected, Generate(alert, Options{Locale: tt.locale}))
+		})
+	}
+}
+
+func TestCamelCase_Unicode(t *testing.T) {
+	assert.Equal(t, "ÉmiratsArabesUnis", camelCase("émirats arabes unis"))
+	assert.Equal(t, "CoréeDuSud", camelCase("Corée du Sud"))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// healthState is the overall health of the alerting pipeline reported by the health endpoint
+type healthState string
+
+const (
+	// healthOK means every active backend is polling successfully with a valid token
+	healthOK healthState = "healthy"
+
+	// healthDegraded means some active backends are failing or not authenticated, or every
+	// enabled backend is paused so no alerts are being fetched
+	healthDegraded healthState = "degraded"
+
+	// healthUnavailable means no active backend is polling successfully, or the plugin is not running
+	healthUnavailable healthState = "unhealthy"
+)
+
+// healthCacheTTL is how long a health evaluation is served before the backends are checked
+// again, so frequent probes don't read the state of every backend each time
+const healthCacheTTL = 5 * time.Second
+
+// healthCache holds the last health evaluation
+type healthCache struct {
+	mu       sync.Mutex
+	response *healthResponse
+}
+
+// healthBackends counts registered backends by state
+type healthBackends struct {
+	Total           int `json:"total"`
+	Enabled         int `json:"enabled"`
+	Active          int `json:"active"`
+	Paused          int `json:"paused"`
+	Failing         int `json:"failing"`
+	Unauthenticated int `json:"unauthenticated"`
+}
+
+// healthResponse is the body of GET /health. It only holds counts so it can be served without
+// authentication.
+type healthResponse struct {
+	Status    healthState    `json:"status"`
+	Backends  healthBackends `json:"backends"`
+	CheckedAt time.Time      `json:"checkedAt"`
+}
+
+// evaluateHealth summarizes backend statuses into the overall plugin health.
+// Paused and disabled backends are counted but do not affect the health state, unless every
+// enabled backend is paused. That is reported as degraded rather than unhealthy, since a pause
+// is deliberate and probes should not restart the server over it.
+func evaluateHealth(statuses []backend.Status, now time.Time) healthResponse {
+	response := healthResponse{Status: healthOK, CheckedAt: now}
+
+	for _, status := range statuses {
+		response.Backends.Total++
+		if !status.Enabled {
+			continue
+		}
+		response.Backends.Enabled++
+
+		health := status.Classify(now)
+		if health == backend.HealthPaused {
+			response.Backends.Paused++
+			continue
+		}
+		response.Backends.Active++
+
+		switch health {
+		case backend.HealthFailing:
+			response.Backends.Failing++
+		case backend.HealthUnauthenticated:
+			response.Backends.Unauthenticated++
+		}
+	}
+
+	switch {
+	case response.Backends.Active > 0 && response.Backends.Failing == response.Backends.Active:
+		response.Status = healthUnavailable
+	case response.Backends.Failing > 0 || response.Backends.Unauthenticated > 0,
+		response.Backends.Enabled > 0 && response.Backends.Active == 0:
+		response.Status = healthDegraded
+	}
+
+	return response
+}
+
+// getHealth handles GET /health for load balancers, uptime checks and Kubernetes probes.
+// Responds 200 when healthy or degraded and 503 when unhealthy.
+func (p *Plugin) getHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := p.currentHealth(time.Now())
+
+	code := http.StatusOK
+	if response.Status == healthUnavailable {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("Failed to encode health response", "error", err.Error())
+	}
+}
+
+// currentHealth returns the health evaluated within the cache TTL, or evaluates it again
+func (p *Plugin) currentHealth(now time.Time) healthResponse {
+	if p.registry == nil {
+		return healthResponse{Status: healthUnavailable, CheckedAt: now}
+	}
+
+	p.health.mu.Lock()
+	defer p.health.mu.Unlock()
+
+	if cached := p.health.response; cached != nil && now.Sub(cached.CheckedAt) < healthCacheTTL {
+		return *cached
+	}
+
+	var statuses []backend.Status
+	for _, b := range p.registry.List() {
+		statuses = append(statuses, b.GetStatus())
+	}
+	response := evaluateHealth(statuses, now)
+	p.health.response = &response
+	return response
+}
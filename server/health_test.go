@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestEvaluateHealth(t *testing.T) {
+	now := time.Now()
+	healthy := backend.Status{Enabled: true, IsAuthenticated: true}
+	failing := backend.Status{Enabled: true, IsAuthenticated: true, ConsecutiveFailures: 2}
+	coolingDown := backend.Status{Enabled: true, IsAuthenticated: true, CooldownUntil: now.Add(time.Minute)}
+	unauthenticated := backend.Status{Enabled: true}
+	paused := backend.Status{Enabled: true, Paused: true, ConsecutiveFailures: 1}
+	disabled := backend.Status{ConsecutiveFailures: 5}
+
+	tests := []struct {
+		name     string
+		statuses []backend.Status
+		expected healthState
+	}{
+		{"no backends", nil, healthOK},
+		{"all healthy", []backend.Status{healthy, healthy}, healthOK},
+		{"paused and disabled backends are ignored", []backend.Status{healthy, paused, disabled}, healthOK},
+		{"some failing", []backend.Status{healthy, failing}, healthDegraded},
+		{"not authenticated", []backend.Status{healthy, unauthenticated}, healthDegraded},
+		{"all failing", []backend.Status{failing, coolingDown, paused}, healthUnavailable},
+		{"all paused", []backend.Status{paused, paused, disabled}, healthDegraded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, evaluateHealth(tt.statuses, now).Status)
+		})
+	}
+
+	response := evaluateHealth([]backend.Status{healthy, failing, unauthenticated, paused, disabled}, now)
+	assert.Equal(t, healthBackends{Total: 5, Enabled: 4, Active: 3, Paused: 1, Failing: 1, Unauthenticated: 1}, response.Backends)
+}
+
+func TestServeHTTP_Health(t *testing.T) {
+	p := newCommandTestPlugin(&plugintest.API{})
+	p.registry = backend.NewRegistry()
+
+	b := &fakeBackend{id: "backend-1", name: "Weather Watch", status: backend.Status{Enabled: true, IsAuthenticated: true}}
+	require.NoError(t, p.registry.Register(b))
+
+	// No Mattermost-User-ID header: the health check does not require a session
+	w := httptest.NewRecorder()
+	p.ServeHTTP(nil, w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response healthResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, healthOK, response.Status)
+	assert.Equal(t, 1, response.Backends.Active)
+
+	// The health is cached for a few seconds
+	b.status.ConsecutiveFailures = 3
+	w = httptest.NewRecorder()
+	p.ServeHTTP(nil, w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	p.health.response.CheckedAt = time.Now().Add(-healthCacheTTL)
+	w = httptest.NewRecorder()
+	p.ServeHTTP(nil, w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, healthUnavailable, response.Status)
+	assert.Equal(t, 1, response.Backends.Failing)
+
+	w = httptest.NewRecorder()
+	p.ServeHTTP(nil, w, httptest.NewRequest(http.MethodPost, "/health", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	// Other endpoints still require a session
+	w = httptest.NewRecorder()
+	p.ServeHTTP(nil, w, httptest.NewRequest(http.MethodGet, "/api/v1/overview", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestServeHTTP_HealthNotActivated(t *testing.T) {
+	p := newCommandTestPlugin(&plugintest.API{})
+	p.registry = nil
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(nil, w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
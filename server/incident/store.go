@@ -0,0 +1,327 @@
+// Package incident correlates related alerts posted to a channel into incidents, each shown
+// as a thread under the post of its first alert.
+package incident
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// Retention is how long an incident takes alerts of its linked alert chains after its
+	// latest alert. Alerts matched by location and topic use the shorter incident window.
+	Retention = backend.MaxIncidentWindowMinutes * time.Minute
+
+	// alertKeyPrefix prefixes the KV key mapping an alert or parent alert ID in a channel to
+	// the root post of its incident
+	alertKeyPrefix = "incident_alert_"
+
+	// recentKeyPrefix prefixes the KV key holding the recent incidents of a channel
+	recentKeyPrefix = "incident_recent_"
+
+	// maxRecentIncidents caps the incidents kept per channel; the least recently updated are dropped
+	maxRecentIncidents = 200
+
+	// maxUpdateAttempts is how often a recent incident list update is retried when another node changed it concurrently
+	maxUpdateAttempts = 5
+
+	// earthRadiusKm is the mean Earth radius used for distances between alert locations
+	earthRadiusKm = 6371.0
+)
+
+// Incident is a group of related alerts posted to a channel
+type Incident struct {
+	// RootPostID is the post of the first alert, under which related alerts are posted
+	RootPostID string `json:"rootPostId"`
+
+	// Topics are the topics of the incident's alerts
+	Topics []string `json:"topics,omitempty"`
+
+	// Location is the location of the first located alert
+	Location *backend.Location `json:"location,omitempty"`
+
+	// StartedAt is when the first alert was posted; UpdatedAt when the latest one was
+	StartedAt time.Time `json:"startedAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// AlertCount is the number of alerts in the incident, including the first
+	AlertCount int `json:"alertCount"`
+
+	// LatestHeadline and LatestAlertType describe the latest alert
+	LatestHeadline  string `json:"latestHeadline"`
+	LatestAlertType string `json:"latestAlertType"`
+}
+
+// Store keeps the recent incidents of each channel and an index of their alerts in the KV store.
+// Incident list updates use compare-and-set so multiple cluster nodes can post alerts concurrently.
+type Store struct {
+	api plugin.API
+	now func() time.Time
+}
+
+// NewStore creates a new incident store
+func NewStore(api plugin.API) *Store {
+	return &Store{
+		api: api,
+		now: time.Now,
+	}
+}
+
+// Find returns the incident in a channel an alert belongs to, or nil if it starts a new one.
+// Alerts sharing a linked alert chain belong to the same incident; otherwise the incident
+// updated within the window with a common topic and a location within the radius is used.
+func (s *Store) Find(alert backend.Alert, channelID string, settings backend.IncidentSettings) (*Incident, error) {
+	recent, _, err := s.getRecent(channelID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+
+	for _, id := range chainIDs(alert) {
+		data, appErr := s.api.KVGet(alertKey(channelID, id))
+		if appErr != nil {
+			return nil, fmt.Errorf("failed to get incident of alert: %w", appErr)
+		}
+		if data == nil {
+			continue
+		}
+		if incident := findByRoot(recent, string(data)); incident != nil && now.Sub(incident.UpdatedAt) <= Retention {
+			return incident, nil
+		}
+	}
+
+	for i := range recent {
+		incident := &recent[i]
+		if now.Sub(incident.UpdatedAt) > settings.Window() {
+			continue
+		}
+		if sharesTopic(incident.Topics, alert.Topics) && nearby(incident.Location, alert.Location, settings.Radius()) {
+			return incident, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Open starts an incident with an alert posted as its root post
+func (s *Store) Open(alert backend.Alert, channelID, rootPostID string) (*Incident, error) {
+	now := s.now()
+	incident := Incident{
+		RootPostID:      rootPostID,
+		Topics:          alert.Topics,
+		Location:        alert.Location,
+		StartedAt:       now,
+		UpdatedAt:       now,
+		AlertCount:      1,
+		LatestHeadline:  alert.Headline,
+		LatestAlertType: alert.AlertType,
+	}
+
+	if err := s.updateRecent(channelID, func(recent []Incident) []Incident {
+		return append(recent, incident)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.indexAlert(alert, channelID, rootPostID); err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+// AddAlert records an alert posted in the thread of an incident and returns the updated incident.
+// Returns nil if the incident is no longer kept.
+func (s *Store) AddAlert(alert backend.Alert, channelID, rootPostID string) (*Incident, error) {
+	var updated *Incident
+	if err := s.updateRecent(channelID, func(recent []Incident) []Incident {
+		updated = nil
+		for i := range recent {
+			if recent[i].RootPostID != rootPostID {
+				continue
+			}
+
+			incident := recent[i]
+			incident.UpdatedAt = s.now()
+			incident.AlertCount++
+			incident.LatestHeadline = alert.Headline
+			incident.LatestAlertType = alert.AlertType
+			incident.Topics = mergeTopics(incident.Topics, alert.Topics)
+			if incident.Location == nil {
+				incident.Location = alert.Location
+			}
+			recent[i] = incident
+			updated = &incident
+			break
+		}
+		return recent
+	}); err != nil {
+		return nil, err
+	}
+
+	if updated == nil {
+		return nil, nil
+	}
+
+	if err := s.indexAlert(alert, channelID, rootPostID); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// indexAlert maps the alert's ID and parent alert IDs in a channel to the incident's root post
+func (s *Store) indexAlert(alert backend.Alert, channelID, rootPostID string) error {
+	for _, id := range chainIDs(alert) {
+		if _, appErr := s.api.KVSetWithOptions(alertKey(channelID, id), []byte(rootPostID), model.PluginKVSetOptions{
+			ExpireInSeconds: int64(Retention / time.Second),
+		}); appErr != nil {
+			return fmt.Errorf("failed to index incident alert: %w", appErr)
+		}
+	}
+	return nil
+}
+
+// getRecent returns the recent incidents of a channel and their stored data
+func (s *Store) getRecent(channelID string) ([]Incident, []byte, error) {
+	data, appErr := s.api.KVGet(recentKeyPrefix + channelID)
+	if appErr != nil {
+		return nil, nil, fmt.Errorf("failed to get recent incidents: %w", appErr)
+	}
+
+	var recent []Incident
+	if data != nil {
+		if err := json.Unmarshal(data, &recent); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal recent incidents: %w", err)
+		}
+	}
+	return recent, data, nil
+}
+
+// updateRecent applies a change to the recent incidents of a channel with compare-and-set,
+// dropping incidents past the retention period and the least recently updated beyond the cap
+func (s *Store) updateRecent(channelID string, update func([]Incident) []Incident) error {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		recent, oldData, err := s.getRecent(channelID)
+		if err != nil {
+			return err
+		}
+
+		now := s.now()
+		kept := make([]Incident, 0, len(recent))
+		for _, incident := range update(recent) {
+			if now.Sub(incident.UpdatedAt) <= Retention {
+				kept = append(kept, incident)
+			}
+		}
+		if len(kept) > maxRecentIncidents {
+			kept = kept[len(kept)-maxRecentIncidents:]
+		}
+
+		newData, err := json.Marshal(kept)
+		if err != nil {
+			return fmt.Errorf("failed to marshal recent incidents: %w", err)
+		}
+
+		saved, appErr := s.api.KVSetWithOptions(recentKeyPrefix+channelID, newData, model.PluginKVSetOptions{
+			Atomic:          true,
+			OldValue:        oldData,
+			ExpireInSeconds: int64(Retention / time.Second),
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to save recent incidents: %w", appErr)
+		}
+		if saved {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to save recent incidents: too many concurrent updates")
+}
+
+// alertKey returns the KV key mapping an alert ID in a channel to its incident
+func alertKey(channelID, alertID string) string {
+	return alertKeyPrefix + channelID + "_" + alertID
+}
+
+// chainIDs returns the IDs linking an alert to others: its own ID and its parent alert IDs
+func chainIDs(alert backend.Alert) []string {
+	ids := make([]string, 0, len(alert.ParentAlertIDs)+1)
+	for _, id := range alert.ParentAlertIDs {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if alert.AlertID != "" {
+		ids = append(ids, alert.AlertID)
+	}
+	return ids
+}
+
+// findByRoot returns the incident with the given root post, or nil
+func findByRoot(recent []Incident, rootPostID string) *Incident {
+	for i := range recent {
+		if recent[i].RootPostID == rootPostID {
+			return &recent[i]
+		}
+	}
+	return nil
+}
+
+// sharesTopic reports whether two topic lists have a topic in common, ignoring case
+func sharesTopic(a, b []string) bool {
+	for _, topicA := range a {
+		for _, topicB := range b {
+			if strings.EqualFold(topicA, topicB) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeTopics adds the topics missing from existing, ignoring case
+func mergeTopics(existing, added []string) []string {
+	merged := append([]string(nil), existing...)
+	for _, topic := range added {
+		if !sharesTopic(merged, []string{topic}) {
+			merged = append(merged, topic)
+		}
+	}
+	return merged
+}
+
+// nearby reports whether two locations are within the radius of each other. Locations are
+// compared by coordinates, or by address when either has no coordinates.
+func nearby(a, b *backend.Location, radiusKm float64) bool {
+	if a == nil || b == nil {
+		return false
+	}
+
+	if hasCoordinates(a) && hasCoordinates(b) {
+		return distanceKm(a, b) <= radiusKm
+	}
+	return a.Address != "" && strings.EqualFold(strings.TrimSpace(a.Address), strings.TrimSpace(b.Address))
+}
+
+// hasCoordinates reports whether a location includes coordinates
+func hasCoordinates(loc *backend.Location) bool {
+	return loc.Latitude != 0 || loc.Longitude != 0
+}
+
+// distanceKm returns the great-circle distance between two locations
+func distanceKm(a, b *backend.Location) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
@@ -0,0 +1,141 @@
+package incident
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// newTestStore returns a store backed by an in-memory KV store supporting atomic sets
+func newTestStore(now *time.Time) *Store {
+	kv := make(map[string][]byte)
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return kv[key], nil
+	})
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+			if options.Atomic && !bytes.Equal(kv[key], options.OldValue) {
+				return false, nil
+			}
+			kv[key] = value
+			return true, nil
+		})
+
+	store := NewStore(api)
+	store.now = func() time.Time { return *now }
+	return store
+}
+
+func TestStore_LinkedAlertChain(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store := newTestStore(&now)
+	settings := backend.IncidentSettings{}
+
+	first := backend.Alert{AlertID: "alert-1", AlertType: "Flash", Headline: "Explosion reported"}
+	found, err := store.Find(first, "channel-1", settings)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	_, err = store.Open(first, "channel-1", "post-1")
+	require.NoError(t, err)
+
+	// A later alert linked to the first one joins its incident, even after the window
+	now = now.Add(3 * time.Hour)
+	linked := backend.Alert{AlertID: "alert-2", AlertType: "Urgent", Headline: "Casualties reported", ParentAlertIDs: []string{"alert-1"}}
+	found, err = store.Find(linked, "channel-1", settings)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "post-1", found.RootPostID)
+
+	updated, err := store.AddAlert(linked, "channel-1", "post-1")
+	require.NoError(t, err)
+	require.NotNil(t, updated)
+	assert.Equal(t, 2, updated.AlertCount)
+	assert.Equal(t, "Casualties reported", updated.LatestHeadline)
+	assert.Equal(t, "Urgent", updated.LatestAlertType)
+	assert.Equal(t, now, updated.UpdatedAt)
+
+	// Alerts sharing a parent with an incident alert join it too
+	sibling := backend.Alert{AlertID: "alert-3", ParentAlertIDs: []string{"alert-1"}}
+	found, err = store.Find(sibling, "channel-1", settings)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+
+	// Incidents are kept per channel
+	found, err = store.Find(linked, "channel-2", settings)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	// Past the retention period the chain starts a new incident
+	now = now.Add(Retention + time.Minute)
+	found, err = store.Find(sibling, "channel-1", settings)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestStore_LocationAndTopic(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store := newTestStore(&now)
+	settings := backend.IncidentSettings{WindowMinutes: 30, RadiusKm: 5}
+
+	first := backend.Alert{
+		AlertID:  "alert-1",
+		Topics:   []string{"Fire"},
+		Location: &backend.Location{Address: "Lyon, France", Latitude: 45.764, Longitude: 4.8357},
+	}
+	_, err := store.Open(first, "channel-1", "post-1")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		alert    backend.Alert
+		after    time.Duration
+		expected bool
+	}{
+		{"nearby with a shared topic", backend.Alert{Topics: []string{"fire", "Smoke"}, Location: &backend.Location{Latitude: 45.77, Longitude: 4.84}}, 10 * time.Minute, true},
+		{"same address without coordinates", backend.Alert{Topics: []string{"Fire"}, Location: &backend.Location{Address: "lyon, france"}}, 10 * time.Minute, true},
+		{"too far away", backend.Alert{Topics: []string{"Fire"}, Location: &backend.Location{Latitude: 45.19, Longitude: 5.72}}, 10 * time.Minute, false},
+		{"no shared topic", backend.Alert{Topics: []string{"Flood"}, Location: &backend.Location{Latitude: 45.77, Longitude: 4.84}}, 10 * time.Minute, false},
+		{"no location", backend.Alert{Topics: []string{"Fire"}}, 10 * time.Minute, false},
+		{"after the window", backend.Alert{Topics: []string{"Fire"}, Location: &backend.Location{Latitude: 45.77, Longitude: 4.84}}, 31 * time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := now
+			store.now = func() time.Time { return current.Add(tt.after) }
+
+			found, err := store.Find(tt.alert, "channel-1", settings)
+			require.NoError(t, err)
+			if tt.expected {
+				require.NotNil(t, found)
+				assert.Equal(t, "post-1", found.RootPostID)
+			} else {
+				assert.Nil(t, found)
+			}
+		})
+	}
+}
+
+func TestStore_AddAlertToUnknownIncident(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store := newTestStore(&now)
+
+	updated, err := store.AddAlert(backend.Alert{AlertID: "alert-1"}, "channel-1", "post-1")
+	require.NoError(t, err)
+	assert.Nil(t, updated)
+}
+
+func TestMergeTopics(t *testing.T) {
+	assert.Equal(t, []string{"Fire", "Smoke"}, mergeTopics([]string{"Fire"}, []string{"fire", "Smoke"}))
+	assert.Equal(t, []string{"Fire"}, mergeTopics(nil, []string{"Fire"}))
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// maxCommandLogLines caps the number of captured log lines shown by /dataminr logs
+const maxCommandLogLines = 30
+
+// perNodeLogsNote explains why captured log lines may be missing in a cluster: each server
+// keeps the lines logged on it, and poll cycles run on whichever server holds the poll job.
+const perNodeLogsNote = "_Log lines are captured in memory on each server. In a cluster, poll cycles may run on another server, whose lines are not shown here._"
+
+// executeLogs handles /dataminr logs <backend>, showing the backend's most recent captured log lines
+func (p *Plugin) executeLogs(args *model.CommandArgs, params []string) string {
+	if len(params) == 0 {
+		return fmt.Sprintf("Please specify a backend, e.g. `/%s logs Weather Watch`.", commandTrigger)
+	}
+
+	name := strings.Join(params, " ")
+	b := p.findBackend(name)
+	if b == nil {
+		return fmt.Sprintf("Backend `%s` not found.", name)
+	}
+
+	entries := p.filterReadableLogs(args.UserId, b.GetRecentLogs())
+	if len(entries) == 0 {
+		return fmt.Sprintf("No log lines captured for backend **%s** on this server. Set the backend's `logLevel` to `debug` to capture more detail.\n%s", b.GetName(), perNodeLogsNote)
+	}
+
+	return formatLogEntries(b.GetName(), entries)
+}
+
+// formatLogEntries lists the most recent captured log lines of a backend in a code block
+func formatLogEntries(name string, entries []backend.LogEntry) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("###### Recent log lines of %s on this server\n", name))
+	sb.WriteString(perNodeLogsNote + "\n")
+	if len(entries) > maxCommandLogLines {
+		sb.WriteString(fmt.Sprintf("_Showing the last %d of %d captured lines._\n", maxCommandLogLines, len(entries)))
+		entries = entries[len(entries)-maxCommandLogLines:]
+	}
+
+	sb.WriteString("```\n")
+	for _, entry := range entries {
+		line := fmt.Sprintf("%s %-5s %s", entry.Time.UTC().Format("2006-01-02 15:04:05"), strings.ToUpper(entry.Level), entry.Message)
+		if entry.Fields != "" {
+			line += " " + entry.Fields
+		}
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString("```")
+
+	return sb.String()
+}
+
+// getBackendLogs handles GET /api/v1/backends/{id}/logs and returns the backend's captured
+// log lines on the server node handling the request, oldest first. In a cluster, lines logged
+// on other nodes, such as those of poll cycles run there, are not included.
+func (p *Plugin) getBackendLogs(w http.ResponseWriter, r *http.Request) {
+	b := p.registry.Get(mux.Vars(r)["id"])
+	if b == nil {
+		http.Error(w, "Backend not found", http.StatusNotFound)
+		return
+	}
+
+	entries := p.filterReadableLogs(r.Header.Get("Mattermost-User-ID"), b.GetRecentLogs())
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		p.API.LogError("Failed to encode backend logs response", "error", err.Error())
+	}
+}
+
+// filterReadableLogs returns the log lines that are about no channel or a channel the user can read
+func (p *Plugin) filterReadableLogs(userID string, entries []backend.LogEntry) []backend.LogEntry {
+	canRead := p.readableChannels(userID)
+	results := []backend.LogEntry{}
+	for _, entry := range entries {
+		if entry.ChannelID == "" || canRead(entry.ChannelID) {
+			results = append(results, entry)
+		}
+	}
+	return results
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestExecuteLogs(t *testing.T) {
+	p, b := newPauseTestPlugin(t)
+	logged := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	t.Run("no captured lines", func(t *testing.T) {
+		text := p.executeLogs(&model.CommandArgs{}, []string{"weather", "watch"})
+		assert.Contains(t, text, "No log lines captured for backend **Weather Watch**")
+		assert.Contains(t, text, perNodeLogsNote)
+	})
+
+	t.Run("captured lines", func(t *testing.T) {
+		api := p.API.(*plugintest.API)
+		api.On("HasPermissionToChannel", "operator-id", "channel-1", model.PermissionReadChannel).Return(true)
+		api.On("HasPermissionToChannel", "operator-id", "private-channel", model.PermissionReadChannel).Return(false)
+		b.logs = []backend.LogEntry{
+			{Time: logged, Level: backend.LogLevelInfo, Message: "Poller started"},
+			{Time: logged.Add(time.Second), Level: backend.LogLevelWarn, Message: "Failed to save auth token", Fields: "error=timeout"},
+			{Time: logged.Add(time.Second), Level: backend.LogLevelInfo, Message: "Dry run: would have posted alert", Fields: "channelId=channel-1", ChannelID: "channel-1"},
+			{Time: logged.Add(time.Second), Level: backend.LogLevelInfo, Message: "Dry run: would have posted private alert", Fields: "channelId=private-channel", ChannelID: "private-channel"},
+		}
+
+		// Lines about channels the user can't read are left out
+		text := p.executeLogs(&model.CommandArgs{UserId: "operator-id"}, []string{"Weather", "Watch"})
+		assert.Contains(t, text, "###### Recent log lines of Weather Watch on this server\n"+perNodeLogsNote)
+		assert.Contains(t, text, "2026-10-16 09:00:00 INFO  Poller started\n")
+		assert.Contains(t, text, "2026-10-16 09:00:01 WARN  Failed to save auth token error=timeout\n")
+		assert.Contains(t, text, "would have posted alert channelId=channel-1\n")
+		assert.NotContains(t, text, "private")
+		assert.NotContains(t, text, "Showing the last")
+	})
+
+	t.Run("shows the most recent lines", func(t *testing.T) {
+		b.logs = nil
+		for i := 0; i < maxCommandLogLines+5; i++ {
+			b.logs = append(b.logs, backend.LogEntry{Time: logged, Level: backend.LogLevelInfo, Message: fmt.Sprintf("line %d", i)})
+		}
+
+		text := p.executeLogs(&model.CommandArgs{}, []string{"backend-1"})
+		assert.Contains(t, text, "Showing the last 30 of 35 captured lines")
+		assert.NotContains(t, text, "line 4\n")
+		assert.Contains(t, text, "line 5\n")
+		assert.Contains(t, text, "line 34\n")
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		assert.Equal(t, "Backend `Other` not found.", p.executeLogs(&model.CommandArgs{}, []string{"Other"}))
+		assert.Contains(t, p.executeLogs(&model.CommandArgs{}, nil), "Please specify a backend")
+	})
+}
+
+func TestGetBackendLogs(t *testing.T) {
+	p, b := newPauseTestPlugin(t)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/backends/{id}/logs", p.getBackendLogs).Methods(http.MethodGet)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	w := get("/api/v1/backends/backend-1/logs")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+
+	b.logs = []backend.LogEntry{{Time: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), Level: backend.LogLevelError, Message: "Poll failed"}}
+	w = get("/api/v1/backends/backend-1/logs")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var entries []backend.LogEntry
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&entries))
+	assert.Equal(t, b.logs, entries)
+
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/backends/unknown/logs").Code)
+}
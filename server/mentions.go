@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// checkMentionTargets checks that every user or group named in a backend's mention rules,
+// its on-call user and its workflow owner exist. Returns a warning for each missing target,
+// so misspelled targets are reported without rejecting the configuration: a user deleted or
+// renamed after the backend was saved must not block every later configuration change.
+func (p *Plugin) checkMentionTargets(cfg backend.Config) []string {
+	var warnings []string
+
+	if cfg.AckSLA != nil && cfg.AckSLA.OnCallUser() != "" {
+		if user, appErr := p.API.GetUserByUsername(cfg.AckSLA.OnCallUser()); appErr != nil || user == nil {
+			warnings = append(warnings, fmt.Sprintf("on-call user '@%s' does not exist", cfg.AckSLA.OnCallUser()))
+		}
+	}
+
+	if cfg.Workflow != nil && cfg.Workflow.Owner() != "" {
+		if user, appErr := p.API.GetUserByUsername(cfg.Workflow.Owner()); appErr != nil || user == nil {
+			warnings = append(warnings, fmt.Sprintf("workflow owner '@%s' does not exist", cfg.Workflow.Owner()))
+		}
+	}
+
+	if cfg.Mentions == nil {
+		return warnings
+	}
+
+	for _, name := range cfg.Mentions.NamedTargets() {
+		if user, appErr := p.API.GetUserByUsername(name); appErr == nil && user != nil {
+			continue
+		}
+		if group, appErr := p.API.GetGroupByName(name); appErr == nil && group != nil {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("mention target '@%s' is not an existing user or group", name))
+	}
+
+	return warnings
+}
+
+// warnMissingMentionTargets logs the mention targets of the backends that don't exist
+func (p *Plugin) warnMissingMentionTargets(configs []backend.Config) {
+	for _, cfg := range configs {
+		for _, warning := range p.checkMentionTargets(cfg) {
+			p.API.LogWarn("Backend names a missing user or group", "id", cfg.ID, "name", cfg.Name, "warning", warning)
+		}
+	}
+}
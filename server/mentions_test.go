@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestCheckMentionTargets(t *testing.T) {
+	notFound := model.NewAppError("Get", "not_found", nil, "", 404)
+	cfg := backend.Config{
+		Name: "Corporate Security",
+		Mentions: &backend.MentionRules{
+			Flash:  []string{"@channel", "@security-team"},
+			Urgent: []string{"@john.doe"},
+		},
+	}
+
+	t.Run("existing users and groups", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("GetUserByUsername", "security-team").Return(nil, notFound)
+		api.On("GetGroupByName", "security-team").Return(&model.Group{Id: "group-id"}, nil)
+		api.On("GetUserByUsername", "john.doe").Return(&model.User{Id: "user-id"}, nil)
+
+		p := &Plugin{}
+		p.SetAPI(api)
+
+		assert.Empty(t, p.checkMentionTargets(backend.Config{Name: "No Mentions"}))
+		assert.Empty(t, p.checkMentionTargets(cfg))
+	})
+
+	t.Run("unknown target", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("GetUserByUsername", "security-team").Return(nil, notFound)
+		api.On("GetGroupByName", "security-team").Return(nil, notFound)
+		api.On("GetUserByUsername", "john.doe").Return(nil, notFound)
+		api.On("GetGroupByName", "john.doe").Return(nil, notFound)
+
+		p := &Plugin{}
+		p.SetAPI(api)
+
+		assert.Equal(t, []string{
+			"mention target '@security-team' is not an existing user or group",
+			"mention target '@john.doe' is not an existing user or group",
+		}, p.checkMentionTargets(cfg))
+	})
+
+	t.Run("missing targets don't reject the configuration", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("GetUserByUsername", mock.Anything).Return(nil, notFound)
+		api.On("GetGroupByName", mock.Anything).Return(nil, notFound)
+		api.On("LogWarn", "Backend names a missing user or group", "id", "", "name", "Corporate Security", "warning", mock.Anything).Twice()
+
+		p := &Plugin{}
+		p.SetAPI(api)
+
+		p.warnMissingMentionTargets([]backend.Config{cfg})
+		api.AssertExpectations(t)
+	})
+
+	t.Run("on-call users", func(t *testing.T) {
+		onCall := backend.Config{Name: "Weather Watch", AckSLA: &backend.AckSLASettings{OnCallUsername: "@duty.officer"}}
+
+		api := &plugintest.API{}
+		api.On("GetUserByUsername", "duty.officer").Return(&model.User{Id: "user-id"}, nil).Once()
+		api.On("GetUserByUsername", "duty.officer").Return(nil, notFound).Once()
+
+		p := &Plugin{}
+		p.SetAPI(api)
+
+		assert.Empty(t, p.checkMentionTargets(onCall))
+		assert.Equal(t, []string{"on-call user '@duty.officer' does not exist"}, p.checkMentionTargets(onCall))
+	})
+	t.Run("workflow owners", func(t *testing.T) {
+		withOwner := backend.Config{Name: "Weather Watch", Workflow: &backend.WorkflowSettings{PlaybookID: "playbook1", OwnerUsername: "duty.officer"}}
+
+		api := &plugintest.API{}
+		api.On("GetUserByUsername", "duty.officer").Return(&model.User{Id: "user-id"}, nil).Once()
+		api.On("GetUserByUsername", "duty.officer").Return(nil, notFound).Once()
+
+		p := &Plugin{}
+		p.SetAPI(api)
+
+		assert.Empty(t, p.checkMentionTargets(withOwner))
+		assert.Equal(t, []string{"workflow owner '@duty.officer' does not exist"}, p.checkMentionTargets(withOwner))
+	})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// metricsResponse is the response of the metrics endpoint, describing this node
+type metricsResponse struct {
+	Deduplicator DedupMetrics `json:"deduplicator"`
+}
+
+// getMetrics serves the deduplication cache metrics of the node handling the request
+func (p *Plugin) getMetrics(w http.ResponseWriter, _ *http.Request) {
+	if p.deduplicator == nil {
+		http.Error(w, "Plugin not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(metricsResponse{Deduplicator: p.deduplicator.Metrics()}); err != nil {
+		p.API.LogError("Failed to encode metrics response", "error", err.Error())
+	}
+}
+
+// executeDedup shows the deduplication cache metrics of this node
+func (p *Plugin) executeDedup(_ *model.CommandArgs, _ []string) string {
+	if p.deduplicator == nil {
+		return "The deduplicator is not running."
+	}
+	return formatDedupMetrics(p.deduplicator.Metrics(), p.getConfiguration().Backends)
+}
+
+// formatDedupMetrics describes the cache size, evictions and the hit rate of each backend.
+// Backends are listed by name, falling back to the ID of backends no longer configured.
+func formatDedupMetrics(metrics DedupMetrics, configs []backend.Config) string {
+	var sb strings.Builder
+	sb.WriteString("###### Deduplication cache on this server\n")
+	sb.WriteString(fmt.Sprintf("- **Alert IDs:** %d\n", metrics.SeenAlerts))
+	if metrics.LastCleanup.IsZero() {
+		sb.WriteString("- **Last cleanup:** not run yet\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("- **Last cleanup:** %s, %d evicted\n", formatStateTime(metrics.LastCleanup), metrics.LastEvictions))
+	}
+	sb.WriteString(fmt.Sprintf("- **Evicted since start:** %d\n", metrics.TotalEvictions))
+
+	if len(metrics.Backends) == 0 {
+		sb.WriteString("\nNo alerts recorded since the plugin started.")
+		return sb.String()
+	}
+
+	type row struct {
+		name string
+		DedupLookups
+	}
+	rows := make([]row, 0, len(metrics.Backends))
+	for backendID, lookups := range metrics.Backends {
+		name := backendID
+		if cfg, found := findBackendConfigByID(configs, backendID); found {
+			name = cfg.Name
+		}
+		rows = append(rows, row{name: name, DedupLookups: lookups})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].name < rows[j].name })
+
+	sb.WriteString("\n| Backend | Hits | Misses | Hit rate |\n|:--|--:|--:|--:|\n")
+	for _, r := range rows {
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d | %.0f%% |\n", r.name, r.Hits, r.Misses, r.HitRate()*100))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
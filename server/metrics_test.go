@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// newMetricsTestPlugin creates a plugin with a deduplicator that recorded a duplicate alert
+func newMetricsTestPlugin(t *testing.T) *Plugin {
+	api := plugintest.NewAPI(t)
+	mockAlertClaims(api)
+
+	p := newCommandTestPlugin(api)
+	p.setConfiguration(&configuration{Backends: []backend.Config{{ID: "backend-1", Name: "Weather Watch"}}})
+	p.deduplicator = NewDeduplicator(pluginapi.NewClient(api, &plugintest.Driver{}))
+	t.Cleanup(p.deduplicator.Stop)
+
+	dedup := p.deduplicator.ForBackend("backend-1", DeduplicationCacheTTL)
+	dedup.RecordAlert("dataminr", "alert-1")
+	dedup.RecordAlert("dataminr", "alert-1")
+	return p
+}
+
+func TestGetMetrics(t *testing.T) {
+	p := newMetricsTestPlugin(t)
+
+	w := httptest.NewRecorder()
+	p.getMetrics(w, httptest.NewRequest(http.MethodGet, "/api/v1/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response metricsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 1, response.Deduplicator.SeenAlerts)
+	assert.Equal(t, map[string]DedupLookups{"backend-1": {Hits: 1, Misses: 1}}, response.Deduplicator.Backends)
+}
+
+func TestExecuteDedup(t *testing.T) {
+	p := newMetricsTestPlugin(t)
+	p.deduplicator.ForBackend("removed-backend", DeduplicationCacheTTL).RecordAlert("dataminr", "alert-2")
+
+	text := p.executeDedup(&model.CommandArgs{}, nil)
+	assert.Contains(t, text, "- **Alert IDs:** 2")
+	assert.Contains(t, text, "- **Last cleanup:** not run yet")
+	assert.Contains(t, text, "| Weather Watch | 1 | 1 | 50% |")
+	assert.Contains(t, text, "| removed-backend | 0 | 1 | 0% |")
+}
+
+func TestFormatDedupMetrics(t *testing.T) {
+	text := formatDedupMetrics(DedupMetrics{
+		LastCleanup:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		LastEvictions:  4,
+		TotalEvictions: 10,
+	}, nil)
+	assert.Contains(t, text, "- **Last cleanup:** 2026-03-01 12:00 UTC, 4 evicted")
+	assert.Contains(t, text, "- **Evicted since start:** 10")
+	assert.Contains(t, text, "No alerts recorded since the plugin started.")
+}
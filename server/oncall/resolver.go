@@ -0,0 +1,169 @@
+// Package oncall resolves the current on-call user from an external on-call service.
+package oncall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// requestTimeout bounds a single on-call lookup so alert posting is not held up
+	requestTimeout = 5 * time.Second
+
+	// maxResponseSize is the largest on-call response read
+	maxResponseSize = 64 * 1024
+
+	// errorCacheDuration is how long a failed lookup is returned from the cache, so alerts
+	// posted while the on-call service is down don't each wait for it to fail again
+	errorCacheDuration = 30 * time.Second
+)
+
+// cachedUsername is a resolved username, or the error of a failed lookup, and when it must
+// be looked up again
+type cachedUsername struct {
+	username string
+	err      error
+	expires  time.Time
+}
+
+// Resolver looks up the on-call username from an HTTP endpoint and caches it per endpoint,
+// caching failed lookups for errorCacheDuration. It is safe for concurrent use.
+type Resolver struct {
+	client *http.Client
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedUsername
+}
+
+// NewResolver creates a new on-call resolver
+func NewResolver() *Resolver {
+	return &Resolver{
+		client: &http.Client{Timeout: requestTimeout},
+		now:    time.Now,
+		cache:  make(map[string]cachedUsername),
+	}
+}
+
+// Resolve returns the current on-call username (without a leading '@'), using the cached
+// username or lookup error while it is fresh
+func (r *Resolver) Resolve(ctx context.Context, settings backend.OnCallSettings) (string, error) {
+	key := settings.URL + "\n" + settings.UsernameField
+
+	r.mu.Lock()
+	cached, exists := r.cache[key]
+	r.mu.Unlock()
+	if exists && r.now().Before(cached.expires) {
+		return cached.username, cached.err
+	}
+
+	username, err := r.fetch(ctx, settings)
+	if err != nil {
+		// A lookup cut short by the caller says nothing about the service
+		if ctx.Err() == nil {
+			r.mu.Lock()
+			r.cache[key] = cachedUsername{err: err, expires: r.now().Add(errorCacheDuration)}
+			r.mu.Unlock()
+		}
+		return "", err
+	}
+
+	r.mu.Lock()
+	r.cache[key] = cachedUsername{username: username, expires: r.now().Add(settings.CacheDuration())}
+	r.mu.Unlock()
+
+	return username, nil
+}
+
+// fetch queries the on-call endpoint and extracts the username from the response
+func (r *Resolver) fetch(ctx context.Context, settings backend.OnCallSettings) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, settings.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create on-call request: %w", err)
+	}
+	for name, value := range settings.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Accept", "application/json, text/plain")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query on-call service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("on-call service returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read on-call response: %w", err)
+	}
+
+	username, err := extractUsername(body, settings.UsernameField)
+	if err != nil {
+		return "", err
+	}
+
+	username = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+	if !backend.ValidMentionName(username) {
+		return "", fmt.Errorf("on-call service returned an invalid username '%s'", username)
+	}
+
+	return username, nil
+}
+
+// extractUsername reads the username from a response body. Without a field path, the body
+// may be a plain text username, a JSON string or a JSON object with a "username" field.
+func extractUsername(body []byte, field string) (string, error) {
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		if field != "" {
+			return "", fmt.Errorf("failed to parse on-call response: %w", err)
+		}
+		return string(body), nil
+	}
+
+	path := field
+	if path == "" {
+		if text, ok := document.(string); ok {
+			return text, nil
+		}
+		path = "username"
+	}
+
+	value := document
+	for _, part := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]any:
+			value = node[part]
+		case []any:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(node) {
+				return "", fmt.Errorf("on-call response has no element '%s' in '%s'", part, path)
+			}
+			value = node[index]
+		default:
+			value = nil
+		}
+		if value == nil {
+			return "", fmt.Errorf("on-call response has no field '%s'", path)
+		}
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("on-call response field '%s' is not a string", path)
+	}
+	return text, nil
+}
@@ -0,0 +1,119 @@
+package oncall
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestExtractUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		field    string
+		expected string
+	}{
+		{"plain text", "jane.doe\n", "", "jane.doe\n"},
+		{"JSON string", `"jane.doe"`, "", "jane.doe"},
+		{"JSON object", `{"username": "jane.doe"}`, "", "jane.doe"},
+		{"nested field", `{"data": {"onCall": [{"user": {"name": "jane.doe"}}]}}`, "data.onCall.0.user.name", "jane.doe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			username, err := extractUsername([]byte(tt.body), tt.field)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, username)
+		})
+	}
+
+	_, err := extractUsername([]byte(`{"data": []}`), "data.0.name")
+	assert.EqualError(t, err, "on-call response has no element '0' in 'data.0.name'")
+
+	_, err = extractUsername([]byte(`{"user": {"id": 5}}`), "user.id")
+	assert.EqualError(t, err, "on-call response field 'user.id' is not a string")
+
+	_, err = extractUsername([]byte(`{"name": "jane"}`), "")
+	assert.EqualError(t, err, "on-call response has no field 'username'")
+
+	_, err = extractUsername([]byte("jane"), "user.name")
+	assert.Error(t, err)
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	requests := 0
+	username := "Jane.Doe"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"username": "@` + username + `"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	resolver := NewResolver()
+	resolver.now = func() time.Time { return now }
+
+	settings := backend.OnCallSettings{URL: server.URL, Headers: map[string]string{"Authorization": "Token secret"}, CacheMinutes: 10}
+
+	resolved, err := resolver.Resolve(context.Background(), settings)
+	require.NoError(t, err)
+	assert.Equal(t, "jane.doe", resolved)
+
+	// Cached until the cache duration ends
+	username = "john.smith"
+	now = now.Add(9 * time.Minute)
+	resolved, err = resolver.Resolve(context.Background(), settings)
+	require.NoError(t, err)
+	assert.Equal(t, "jane.doe", resolved)
+	assert.Equal(t, 1, requests)
+
+	now = now.Add(time.Minute)
+	resolved, err = resolver.Resolve(context.Background(), settings)
+	require.NoError(t, err)
+	assert.Equal(t, "john.smith", resolved)
+	assert.Equal(t, 2, requests)
+
+	// Errors are cached briefly
+	now = now.Add(10 * time.Minute)
+	settings.Headers = nil
+	_, err = resolver.Resolve(context.Background(), settings)
+	assert.EqualError(t, err, "on-call service returned status 401")
+	assert.Equal(t, 3, requests)
+
+	settings.Headers = map[string]string{"Authorization": "Token secret"}
+	now = now.Add(errorCacheDuration - time.Second)
+	_, err = resolver.Resolve(context.Background(), settings)
+	assert.EqualError(t, err, "on-call service returned status 401")
+	assert.Equal(t, 3, requests)
+
+	now = now.Add(time.Second)
+	resolved, err = resolver.Resolve(context.Background(), settings)
+	require.NoError(t, err)
+	assert.Equal(t, "john.smith", resolved)
+	assert.Equal(t, 4, requests)
+
+	// Lookups cancelled by the caller are not cached
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	settings.UsernameField = "user"
+	_, err = resolver.Resolve(ctx, settings)
+	assert.Error(t, err)
+	_, err = resolver.Resolve(context.Background(), settings)
+	assert.EqualError(t, err, "on-call response has no field 'user'")
+
+	username = "not a user"
+	settings.UsernameField = "username"
+	_, err = resolver.Resolve(context.Background(), settings)
+	assert.EqualError(t, err, "on-call service returned an invalid username 'not a user'")
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// AlertThroughput counts the alerts a backend posted recently
+type AlertThroughput struct {
+	LastHour    int `json:"lastHour"`
+	Last24Hours int `json:"last24Hours"`
+}
+
+// BackendOverview combines a backend's configuration, runtime status and recent activity
+type BackendOverview struct {
+	// Config is the backend configuration with secrets removed
+	Config backend.Config `json:"config"`
+
+	// Status is the runtime status (nil if the backend is not registered)
+	Status *backend.Status `json:"status"`
+
+	// Throughput counts the alerts posted by the backend recently
+	Throughput AlertThroughput `json:"throughput"`
+}
+
+// GetBackendsWithStatus returns an overview of every configured backend in configuration order.
+// Throughput is left at zero if the alert history cannot be read.
+func (p *Plugin) GetBackendsWithStatus() []BackendOverview {
+	config := p.getConfiguration()
+	throughput := p.alertThroughput(time.Now())
+
+	backends := config.backends()
+	overviews := make([]BackendOverview, 0, len(backends))
+	for _, cfg := range backends {
+		overview := BackendOverview{
+			Config:     redactConfig(cfg),
+			Throughput: throughput[cfg.ID],
+		}
+
+		if b := p.registry.Get(cfg.ID); b != nil {
+			status := b.GetStatus()
+			overview.Status = &status
+		}
+
+		overviews = append(overviews, overview)
+	}
+
+	return overviews
+}
+
+// alertThroughput counts the alerts posted in the last hour and day, keyed by backend ID
+func (p *Plugin) alertThroughput(now time.Time) map[string]AlertThroughput {
+	throughput := make(map[string]AlertThroughput)
+	if p.alertIndex == nil {
+		return throughput
+	}
+
+	entries, err := p.alertIndex.Search(alertindex.Query{Since: now.Add(-24 * time.Hour)})
+	if err != nil {
+		p.API.LogWarn("Failed to load alert history for throughput", "error", err.Error())
+		return throughput
+	}
+
+	hourAgo := now.Add(-time.Hour)
+	for _, entry := range entries {
+		counts := throughput[entry.BackendID]
+		counts.Last24Hours++
+		if !entry.PostedAt.Before(hourAgo) {
+			counts.LastHour++
+		}
+		throughput[entry.BackendID] = counts
+	}
+
+	return throughput
+}
+
+// redactConfig returns a copy of a backend configuration without its API key,
+// TLS client key or proxy password
+func redactConfig(cfg backend.Config) backend.Config {
+	cfg.APIKey = ""
+
+	if cfg.TLS != nil {
+		tls := *cfg.TLS
+		tls.ClientKey = ""
+		cfg.TLS = &tls
+	}
+
+	if cfg.ProxyURL != "" {
+		if parsed, err := url.Parse(cfg.ProxyURL); err == nil {
+			cfg.ProxyURL = parsed.Redacted()
+		} else {
+			cfg.ProxyURL = ""
+		}
+	}
+	cfg.ProxyPassword = ""
+
+	// On-call request headers usually carry API tokens
+	if cfg.Mentions != nil && cfg.Mentions.OnCall != nil && len(cfg.Mentions.OnCall.Headers) > 0 {
+		mentions := *cfg.Mentions
+		onCall := *mentions.OnCall
+		onCall.Headers = make(map[string]string, len(mentions.OnCall.Headers))
+		for name := range mentions.OnCall.Headers {
+			onCall.Headers[name] = ""
+		}
+		mentions.OnCall = &onCall
+		cfg.Mentions = &mentions
+	}
+
+	// So do summarizer request headers
+	if cfg.Summarizer != nil && len(cfg.Summarizer.Headers) > 0 {
+		summarizer := *cfg.Summarizer
+		summarizer.Headers = make(map[string]string, len(cfg.Summarizer.Headers))
+		for name := range cfg.Summarizer.Headers {
+			summarizer.Headers[name] = ""
+		}
+		cfg.Summarizer = &summarizer
+	}
+
+	// And the translator API key and headers
+	if cfg.Translator != nil {
+		translator := *cfg.Translator
+		translator.APIKey = ""
+		translator.Headers = make(map[string]string, len(cfg.Translator.Headers))
+		for name := range cfg.Translator.Headers {
+			translator.Headers[name] = ""
+		}
+		cfg.Translator = &translator
+	}
+
+	// And the failover credentials
+	if cfg.Failover != nil {
+		failover := *cfg.Failover
+		failover.APIKey = ""
+		cfg.Failover = &failover
+	}
+
+	return cfg
+}
+
+// getOverview returns the overview of all configured backends for the status page
+func (p *Plugin) getOverview(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(p.GetBackendsWithStatus()); err != nil {
+		p.API.LogError("Failed to encode overview response", "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// pauseRequest is the optional body of POST /api/v1/backends/{id}/pause
+type pauseRequest struct {
+	// DurationMinutes is how long to pause polling (0 pauses until resumed)
+	DurationMinutes int `json:"durationMinutes"`
+}
+
+// findBackend returns the registered backend with the given ID or case-insensitive name, or nil
+func (p *Plugin) findBackend(nameOrID string) backend.Backend {
+	if b := p.registry.Get(nameOrID); b != nil {
+		return b
+	}
+
+	for _, b := range p.registry.List() {
+		if strings.EqualFold(b.GetName(), nameOrID) {
+			return b
+		}
+	}
+	return nil
+}
+
+// pauseUntil converts a pause duration into the time the pause ends (zero for no end)
+func pauseUntil(duration time.Duration, now time.Time) time.Time {
+	if duration <= 0 {
+		return time.Time{}
+	}
+	return now.Add(duration)
+}
+
+// describePause builds the confirmation text for a paused backend
+func describePause(name string, until time.Time) string {
+	if until.IsZero() {
+		return fmt.Sprintf("Backend **%s** is paused until resumed with `/%s resume %s`.", name, commandTrigger, name)
+	}
+	return fmt.Sprintf("Backend **%s** is paused until %s.", name, until.UTC().Format("2006-01-02 15:04 MST"))
+}
+
+// executePause handles /dataminr pause <backend> [duration].
+// The last word is treated as the duration when it parses as one (e.g., 30m, 1h, 2d).
+func (p *Plugin) executePause(args *model.CommandArgs, params []string) string {
+	if len(params) == 0 {
+		return fmt.Sprintf("Please specify a backend, e.g. `/%s pause Weather Watch 1h`.", commandTrigger)
+	}
+
+	var duration time.Duration
+	if len(params) > 1 {
+		if parsed, err := parseDurationParam(params[len(params)-1]); err == nil {
+			duration = parsed
+			params = params[:len(params)-1]
+		}
+	}
+
+	name := strings.Join(params, " ")
+	b := p.findBackend(name)
+	if b == nil {
+		return fmt.Sprintf("Backend `%s` not found.", name)
+	}
+
+	until := pauseUntil(duration, time.Now())
+	if err := p.registry.Pause(b.GetID(), until); err != nil {
+		p.API.LogError("Failed to pause backend", "id", b.GetID(), "userId", args.UserId, "error", err.Error())
+		return fmt.Sprintf("Failed to pause backend **%s**.", b.GetName())
+	}
+
+	return describePause(b.GetName(), until)
+}
+
+// executeResume handles /dataminr resume <backend>
+func (p *Plugin) executeResume(args *model.CommandArgs, params []string) string {
+	if len(params) == 0 {
+		return fmt.Sprintf("Please specify a backend, e.g. `/%s resume Weather Watch`.", commandTrigger)
+	}
+
+	name := strings.Join(params, " ")
+	b := p.findBackend(name)
+	if b == nil {
+		return fmt.Sprintf("Backend `%s` not found.", name)
+	}
+
+	if err := p.registry.Resume(b.GetID()); err != nil {
+		p.API.LogError("Failed to resume backend", "id", b.GetID(), "userId", args.UserId, "error", err.Error())
+		return fmt.Sprintf("Failed to resume backend **%s**.", b.GetName())
+	}
+
+	return fmt.Sprintf("Backend **%s** has resumed polling.", b.GetName())
+}
+
+// pauseBackend handles POST /api/v1/backends/{id}/pause and returns the updated status
+func (p *Plugin) pauseBackend(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if p.registry.Get(id) == nil {
+		http.Error(w, "Backend not found", http.StatusNotFound)
+		return
+	}
+
+	var req pauseRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.DurationMinutes < 0 {
+		http.Error(w, "durationMinutes must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	until := pauseUntil(time.Duration(req.DurationMinutes)*time.Minute, time.Now())
+	if err := p.registry.Pause(id, until); err != nil {
+		p.API.LogError("Failed to pause backend", "id", id, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	p.writeBackendStatus(w, id)
+}
+
+// resumeBackend handles POST /api/v1/backends/{id}/resume and returns the updated status
+func (p *Plugin) resumeBackend(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if p.registry.Get(id) == nil {
+		http.Error(w, "Backend not found", http.StatusNotFound)
+		return
+	}
+
+	if err := p.registry.Resume(id); err != nil {
+		p.API.LogError("Failed to resume backend", "id", id, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	p.writeBackendStatus(w, id)
+}
+
+// writeBackendStatus writes the status of a single backend as JSON
+func (p *Plugin) writeBackendStatus(w http.ResponseWriter, id string) {
+	b := p.registry.Get(id)
+	if b == nil {
+		http.Error(w, "Backend not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(b.GetStatus()); err != nil {
+		p.API.LogError("Failed to encode backend status response", "error", err.Error())
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func newPauseTestPlugin(t *testing.T) (*Plugin, *fakeBackend) {
+	p := newCommandTestPlugin(&plugintest.API{})
+	p.registry = backend.NewRegistry()
+
+	b := &fakeBackend{id: "backend-1", name: "Weather Watch", status: backend.Status{Enabled: true}}
+	require.NoError(t, p.registry.Register(b))
+	return p, b
+}
+
+func TestExecutePause(t *testing.T) {
+	t.Run("pause by name with duration", func(t *testing.T) {
+		p, b := newPauseTestPlugin(t)
+
+		text := p.executePause(&model.CommandArgs{UserId: "user-id"}, []string{"weather", "watch", "1h"})
+		assert.Contains(t, text, "Backend **Weather Watch** is paused until")
+		assert.True(t, b.status.Paused)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), b.status.PausedUntil, time.Minute)
+	})
+
+	t.Run("pause by ID until resumed", func(t *testing.T) {
+		p, b := newPauseTestPlugin(t)
+
+		text := p.executePause(&model.CommandArgs{UserId: "user-id"}, []string{"backend-1"})
+		assert.Equal(t, "Backend **Weather Watch** is paused until resumed with `/dataminr resume Weather Watch`.", text)
+		assert.True(t, b.status.Paused)
+		assert.True(t, b.status.PausedUntil.IsZero())
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		p, _ := newPauseTestPlugin(t)
+
+		assert.Equal(t, "Backend `Other` not found.", p.executePause(&model.CommandArgs{}, []string{"Other", "2h"}))
+		assert.Contains(t, p.executePause(&model.CommandArgs{}, nil), "Please specify a backend")
+	})
+}
+
+func TestExecuteResume(t *testing.T) {
+	p, b := newPauseTestPlugin(t)
+	require.NoError(t, b.Pause(time.Time{}))
+
+	assert.Equal(t, "Backend **Weather Watch** has resumed polling.", p.executeResume(&model.CommandArgs{}, []string{"Weather", "Watch"}))
+	assert.False(t, b.status.Paused)
+
+	assert.Equal(t, "Backend `Other` not found.", p.executeResume(&model.CommandArgs{}, []string{"Other"}))
+}
+
+func TestPauseResumeEndpoints(t *testing.T) {
+	p, b := newPauseTestPlugin(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/backends/{id}/pause", p.pauseBackend).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/backends/{id}/resume", p.resumeBackend).Methods(http.MethodPost)
+
+	t.Run("pause with duration", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/backends/backend-1/pause", strings.NewReader(`{"durationMinutes": 60}`)))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var status backend.Status
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+		assert.True(t, status.Paused)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), status.PausedUntil, time.Minute)
+	})
+
+	t.Run("pause without body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/backends/backend-1/pause", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, b.status.PausedUntil.IsZero())
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/backends/backend-1/resume", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, b.status.Paused)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/backends/missing/pause", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/backends/backend-1/pause", strings.NewReader(`{"durationMinutes": -5}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/backends/backend-1/pause", strings.NewReader(`not json`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -1,17 +1,31 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
 	"github.com/pkg/errors"
 
+	"github.com/mattermost/mattermost-plugin-dataminr/server/ack"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertposts"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/audit"
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
-	_ "github.com/mattermost/mattermost-plugin-dataminr/server/backend/dataminr" // Register dataminr backend factory
+	_ "github.com/mattermost/mattermost-plugin-dataminr/server/backend/dataminr" // Register dataminr and simulator backend factories
+	"github.com/mattermost/mattermost-plugin-dataminr/server/followup"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/incident"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/oncall"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/pollhistory"
 	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/triage"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/workflow"
 )
 
 // Plugin implements the interface expected by the Mattermost server to communicate between the server and plugin processes.
@@ -32,10 +46,87 @@ type Plugin struct {
 	registry *backend.Registry
 
 	// poster posts alerts to Mattermost channels.
-	poster backend.AlertPoster
+	poster *poster.Poster
 
 	// deduplicator is shared across all backends to prevent duplicate alerts
 	deduplicator *Deduplicator
+
+	// botID is the user ID of the bot that posts alerts and notifications.
+	botID string
+
+	// userAgent identifies the plugin and server versions in backend API requests.
+	userAgent string
+
+	// statusNotifier sends direct messages to subscribers when backends change state.
+	statusNotifier *StatusNotifier
+
+	// botStatus shows the overall backend health in the bot's custom status.
+	botStatus *BotStatus
+
+	// subscriptions stores the channels subscribed to backends with /dataminr subscribe.
+	subscriptions *backend.SubscriptionStore
+
+	// topicMutes stores the topics muted per channel with /dataminr mute topic.
+	topicMutes *backend.TopicMuteStore
+
+	// alertListMutes stores the alert lists muted per channel or backend with /dataminr mute list.
+	alertListMutes *backend.AlertListMuteStore
+
+	// alertIndex records posted alerts for /dataminr search.
+	alertIndex alertindex.Store
+
+	// auditLog records the actions users took on alert posts.
+	auditLog audit.Store
+
+	// pollHistory records the poll cycle outcomes of backends in the database (nil when
+	// backends record them with their state in the KV store).
+	pollHistory *pollhistory.SQLStore
+
+	// storagePruner deletes alert index, audit log and poll history rows past their retention
+	// period when they are stored in the database.
+	storagePruner *StoragePruner
+
+	// alertPosts maps alerts to the post they were posted as.
+	alertPosts *alertposts.Store
+
+	// ackTracker tracks acknowledgment of Flash alerts posted with an Acknowledge button.
+	ackTracker *ack.Tracker
+
+	// ackReminder reminds about Flash alerts not acknowledged within their window.
+	ackReminder *AckReminder
+
+	// deferredMentions posts the mentions held back while users were unavailable.
+	deferredMentions *DeferredMentions
+
+	// dailySummary posts daily alert statistics to backend channels.
+	dailySummary *DailySummary
+
+	// alertExpiry strikes through or deletes alert posts past their backend's TTL.
+	alertExpiry *AlertExpiry
+
+	// taxonomySync caches the alert lists and topics available to each backend.
+	taxonomySync *TaxonomySync
+
+	// credentialCheck validates the credentials of each backend once a day.
+	credentialCheck *CredentialCheck
+
+	// triageStore records the triage state set by reacting to alert posts.
+	triageStore *triage.Store
+
+	// startupReportLock synchronizes access to the startupReport.
+	startupReportLock sync.RWMutex
+
+	// startupReport lists the outcome of each backend started on activation.
+	startupReport StartupReport
+
+	// stopRegistryObserver stops logging backend lifecycle changes.
+	stopRegistryObserver func()
+
+	// failover runs the warm standby of backends that are auto-disabled or failing to authenticate.
+	failover *FailoverMonitor
+
+	// health caches the health reported by the health endpoint.
+	health healthCache
 }
 
 // OnActivate is invoked when the plugin is activated. If an error is returned, the plugin will be deactivated.
@@ -43,6 +134,9 @@ func (p *Plugin) OnActivate() error {
 	p.client = pluginapi.NewClient(p.API, p.Driver)
 	p.registry = backend.NewRegistry()
 	p.deduplicator = NewDeduplicator(p.client)
+	p.subscriptions = backend.NewSubscriptionStore(p.API)
+	p.topicMutes = backend.NewTopicMuteStore(p.API)
+	p.alertListMutes = backend.NewAlertListMuteStore(p.API)
 
 	// Check license
 	if !pluginapi.IsEnterpriseLicensedOrDevelopment(p.API.GetConfig(), p.API.GetLicense()) {
@@ -74,49 +168,316 @@ func (p *Plugin) OnActivate() error {
 	}
 
 	p.API.LogInfo("Bot user initialized", "botID", botID, "username", botUsername)
+	p.botID = botID
+	p.userAgent = p.buildUserAgent()
 
 	// Create poster with bot ID
 	p.poster = poster.New(p.API, botID)
+	p.poster.SetRateLimit(config.rateLimit())
+	p.poster.SetFormatOptions(config.formatOptions())
+	p.poster.SetHashtagOptions(config.hashtagOptions())
+	p.poster.SetHashtagPlacements(config.hashtagPlacements())
+	p.poster.SetBotIdentities(config.botIdentities())
+	p.poster.SetMentionRules(config.mentionRules())
+	p.poster.SetContentLimits(config.contentLimits())
+	p.poster.SetMediaUploads(config.mediaUploads())
+	p.poster.SetMediaBundles(config.mediaBundles())
+	p.poster.SetLocales(config.locales(p.serverLocale()))
+	p.poster.SetTimeDisplays(config.timeDisplays())
+	p.poster.SetTopicStyles(config.topicStyles())
+	p.poster.SetAckSLAs(config.ackSLAs())
+	p.poster.SetIncidentSettings(config.incidentSettings())
+	p.poster.SetWorkflows(config.workflows())
+	p.poster.SetAdminChannel(config.AdminChannelID)
+
+	// Record posted alerts so they can be found with /dataminr search
+	if err := p.openStorage(config.StorageDriver); err != nil {
+		return errors.Wrap(err, "failed to open storage")
+	}
+	p.poster.SetAlertIndex(p.alertIndex)
+	if p.storagePruner != nil {
+		if err := p.storagePruner.Start(); err != nil {
+			return err
+		}
+	}
+
+	// Map posted alerts to their posts so follow-ups can find them
+	p.alertPosts = alertposts.New(p.API)
+	p.poster.SetAlertPostStore(p.alertPosts)
+
+	// Notify the members of a channel when an alert is posted to it
+	p.poster.SetAlertPostedHandler(p.publishAlertPosted)
+
+	// Mention the current on-call user on critical alerts for backends with an on-call service
+	p.poster.SetOnCallResolver(oncall.NewResolver())
+
+	// Hold back the mentions of users in Do Not Disturb or out of office for backends that defer them
+	mentionScheduler := followup.NewScheduler(p.API)
+	p.poster.SetMentionScheduler(mentionScheduler)
+
+	// Track acknowledgment of Flash alerts for backends with an acknowledgment SLA
+	p.ackTracker = ack.NewTracker(p.API)
+	p.poster.SetAckTracker(p.ackTracker, ackActionURL())
+
+	// Group related alerts into incident threads for backends with incident settings
+	p.poster.SetIncidentCorrelator(incident.NewStore(p.API))
+
+	// Start playbook runs and create board cards for critical alerts of backends with a workflow
+	p.poster.SetWorkflowLauncher(workflow.NewLauncher(p.API, botID))
+
+	// Show a signed deep link on each alert post, resolving to the post in its channel, and offer to copy it
+	p.poster.SetLinkAction(linkActionURL())
+	p.poster.SetAlertLinker(p.createAlertLink)
+
+	// Let channel members forward alerts to other channels they belong to
+	p.poster.SetForwardAction(forwardActionURL())
+
+	// Record the triage state set by reacting to alert posts
+	p.triageStore = triage.NewStore(p.API)
+
+	// Collapse near-identical alerts from different backends into one post per channel
+	p.poster.SetContentDeduplicator(p.deduplicator)
+
+	if config.AllowInsecureBackendURLs {
+		p.API.LogWarn("Insecure backend URLs are allowed: backends may poll plain HTTP URLs, exposing API credentials and alerts on the network. Disable allowInsecureBackendURLs outside of test environments.")
+	}
+
+	// Log backend lifecycle changes, including backends disabled or failing while polling
+	p.stopRegistryObserver = p.observeRegistry()
 
 	// Initialize backends from current configuration
-	for _, backendConfig := range config.Backends {
-		p.createAndStartBackend(backendConfig)
+	report := StartupReport{Backends: []StartupResult{}}
+	for _, backendConfig := range config.backends() {
+		report.Backends = append(report.Backends, p.createAndStartBackend(backendConfig))
+	}
+	report.GeneratedAt = time.Now()
+
+	// Watch for backend state changes to notify subscribed admins
+	p.statusNotifier = NewStatusNotifier(p.API, botID, p.registry)
+	if err := p.statusNotifier.Start(); err != nil {
+		return err
+	}
+
+	// Show the overall backend health in the bot's custom status
+	p.botStatus = NewBotStatus(p.API, botID, p.registry)
+	if err := p.botStatus.Start(); err != nil {
+		return err
+	}
+
+	// Keep backends paused while the global pause is on, including backends added while the
+	// plugin was inactive, or resume them if it was turned off meanwhile
+	p.applyGlobalPause(config.GlobalPause)
+
+	// Report which backends started, are disabled or failed to start
+	p.publishStartupReport(report)
+
+	// Run the standby of backends that failed over, including before a restart
+	p.failover = NewFailoverMonitor(p.API, p.registry, p.getConfiguration().backends, p.createAndStartBackend, p.statusNotifier.Notify)
+	p.failover.Start()
+
+	// Remind about Flash alerts that were not acknowledged in time
+	p.ackReminder = NewAckReminder(p.API, botID, p.ackTracker)
+	if err := p.ackReminder.Start(); err != nil {
+		return err
+	}
+
+	// Post the mentions held back while users were unavailable once they are due
+	p.deferredMentions = NewDeferredMentions(p.API, mentionScheduler, p.poster.PostDeferredMentions)
+	if err := p.deferredMentions.Start(); err != nil {
+		return err
+	}
+
+	// Post daily alert statistics when enabled in the configuration
+	p.dailySummary = NewDailySummary(p.API, p.alertIndex, p.getConfiguration, p.poster.PostMessage)
+	if err := p.dailySummary.Start(); err != nil {
+		return err
+	}
+
+	// Expire alert posts past the TTL configured for their backend and alert type
+	p.alertExpiry = NewAlertExpiry(p.API, p.alertIndex, p.getConfiguration, p.poster.ExpireAlertPost)
+	if err := p.alertExpiry.Start(); err != nil {
+		return err
+	}
+
+	// Cache the alert lists and topics of each backend and warn about unknown ones
+	p.taxonomySync = NewTaxonomySync(p.API, p.registry, p.getConfiguration)
+	if err := p.taxonomySync.Start(); err != nil {
+		return err
+	}
+
+	// Validate the credentials of each backend daily and warn about problems before polls fail
+	p.credentialCheck = NewCredentialCheck(p.API, p.registry, p.getConfiguration, p.poster.PostAdminMessage)
+	if err := p.credentialCheck.Start(); err != nil {
+		return err
+	}
+
+	if err := p.registerCommands(); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// serverLocale returns the default locale of the Mattermost server (empty if unset)
+func (p *Plugin) serverLocale() string {
+	serverConfig := p.API.GetConfig()
+	if serverConfig == nil || serverConfig.LocalizationSettings.DefaultServerLocale == nil {
+		return ""
+	}
+	return *serverConfig.LocalizationSettings.DefaultServerLocale
+}
+
+// buildUserAgent returns the User-Agent of backend API requests, naming the plugin and
+// Mattermost server versions. Versions that can't be determined are left out.
+func (p *Plugin) buildUserAgent() string {
+	userAgent := backend.UserAgentProduct
+	if status, appErr := p.API.GetPluginStatus(pluginID); appErr == nil && status.Version != "" {
+		userAgent += "/" + status.Version
+	}
+	if serverVersion := p.API.GetServerVersion(); serverVersion != "" {
+		userAgent += " Mattermost/" + serverVersion
+	}
+	return userAgent
+}
+
 // OnDeactivate is invoked when the plugin is deactivated.
 func (p *Plugin) OnDeactivate() error {
+	if p.statusNotifier != nil {
+		p.statusNotifier.Stop()
+	}
+
+	if p.botStatus != nil {
+		p.botStatus.Stop()
+	}
+
+	if p.taxonomySync != nil {
+		p.taxonomySync.Stop()
+	}
+
+	if p.credentialCheck != nil {
+		p.credentialCheck.Stop()
+	}
+
+	if p.storagePruner != nil {
+		p.storagePruner.Stop()
+	}
+
+	if p.alertExpiry != nil {
+		p.alertExpiry.Stop()
+	}
+
+	if p.dailySummary != nil {
+		p.dailySummary.Stop()
+	}
+
+	if p.ackReminder != nil {
+		p.ackReminder.Stop()
+	}
+
+	if p.deferredMentions != nil {
+		p.deferredMentions.Stop()
+	}
+
+	if p.stopRegistryObserver != nil {
+		p.stopRegistryObserver()
+	}
+
+	if p.failover != nil {
+		p.failover.Stop()
+	}
+
+	// Backends stop in parallel, so the deadline bounds stopping all of them. Backends that
+	// miss it are marked stopped and cleanup continues, so deactivation never hangs the
+	// server shutdown.
+	var stopErr error
 	if p.registry != nil {
-		if err := p.registry.UnregisterAll(); err != nil {
-			p.API.LogError("Failed to unregister all backends during deactivation", "error", err.Error())
-			return err
+		ctx, cancel := context.WithTimeout(context.Background(), backendStopTimeout)
+		defer cancel()
+		if stopErr = p.registry.UnregisterAll(ctx); stopErr != nil {
+			p.API.LogWarn("Failed to stop all backends during deactivation, continuing cleanup", "error", stopErr.Error())
 		}
 	}
 
+	// Backends no longer post alerts, so no more workflows are launched
+	if p.poster != nil {
+		p.poster.WaitForWorkflows()
+	}
+
 	if p.deduplicator != nil {
 		p.deduplicator.Stop()
 	}
 
-	return nil
+	// Close the database connections opened by the SQL storage driver
+	if p.client != nil {
+		if err := p.client.Store.Close(); err != nil {
+			p.API.LogWarn("Failed to close database connections", "error", err.Error())
+		}
+	}
+
+	return stopErr
 }
 
 // createAndStartBackend creates a backend instance and registers it.
 // If the backend is enabled, it also starts the backend.
 // Logs errors but does not fail - errors are non-fatal for individual backends.
-func (p *Plugin) createAndStartBackend(config backend.Config) {
+// Returns the outcome for the startup report.
+func (p *Plugin) createAndStartBackend(config backend.Config) StartupResult {
+	result := StartupResult{ID: config.ID, Name: config.Name, Outcome: startupFailed}
+
+	// Resolve the API key from the secret store or environment so the backend never sees a reference
+	apiKey, err := p.resolveAPIKey(config)
+	if err != nil {
+		p.API.LogError("Failed to resolve backend API key", "id", config.ID, "name", config.Name, "error", err.Error())
+		result.Error = "failed to resolve API key: " + err.Error()
+		return result
+	}
+	config.APIKey = apiKey
+
+	proxyPassword, err := p.resolveProxyPassword(config)
+	if err != nil {
+		p.API.LogError("Failed to resolve backend proxy password", "id", config.ID, "name", config.Name, "error", err.Error())
+		result.Error = "failed to resolve proxy password: " + err.Error()
+		return result
+	}
+	config.ProxyPassword = proxyPassword
+
+	if config.ProxyURL != "" {
+		p.API.LogInfo("Backend uses a proxy", "id", config.ID, "name", config.Name, "proxy", config.RedactedProxyURL())
+	}
+
+	if strings.HasPrefix(config.URL, "http://") {
+		p.API.LogWarn("Backend uses an insecure HTTP URL", "id", config.ID, "name", config.Name, "url", config.URL)
+	}
+
 	// Create backend instance using factory, passing the shared deduplicator and disable callback
-	b, err := backend.Create(config, p.client, p.API, p.poster, p.deduplicator, p.disableBackend)
+	b, err := backend.Create(config, p.client, p.API, p.poster, p.deduplicator.ForBackend(config.ID, config.DedupTTL()), p.disableBackend, p.userAgent)
 	if err != nil {
 		p.API.LogError("Failed to create backend", "id", config.ID, "name", config.Name, "error", err.Error())
-		return
+		result.Error = "failed to create backend: " + err.Error()
+		return result
+	}
+
+	// Record the poll history in the database with the SQL storage driver
+	if p.pollHistory != nil {
+		if recorder, ok := b.(backend.PollHistoryRecorder); ok {
+			recorder.SetPollHistoryStore(p.pollHistory.ForBackend(config.ID))
+		}
+	}
+
+	// Download the backend's media through its proxy and TLS settings
+	if p.poster != nil {
+		mediaTransport, err := backend.NewHTTPTransport(config)
+		if err != nil {
+			p.API.LogWarn("Failed to build media transport, downloading media directly", "id", config.ID, "name", config.Name, "error", err.Error())
+		}
+		p.poster.SetMediaTransport(config.ID, mediaTransport)
 	}
 
 	// Register backend (always register, even if disabled)
 	if err := p.registry.Register(b); err != nil {
 		p.API.LogError("Failed to register backend", "id", config.ID, "name", config.Name, "error", err.Error())
-		return
+		result.Error = "failed to register backend: " + err.Error()
+		return result
 	}
 
 	// Only start the backend if it's enabled
@@ -129,21 +490,25 @@ func (p *Plugin) createAndStartBackend(config backend.Config) {
 			p.API.LogInfo("Cleared operational state for disabled backend", "id", config.ID, "name", config.Name)
 		}
 		p.API.LogInfo("Backend registered but not started (disabled)", "id", config.ID, "name", config.Name)
-		return
+		result.Outcome = startupDisabled
+		return result
 	}
 
 	// Start backend
-	if err := b.Start(); err != nil {
+	if err := b.Start(context.Background()); err != nil {
 		p.API.LogError("Failed to start backend", "id", config.ID, "name", config.Name, "error", err.Error())
 		// Keep backend registered even if start fails - it will show error state in status
-		return
+		result.Error = "failed to start backend: " + err.Error()
+		return result
 	}
 
 	p.API.LogInfo("Backend started successfully", "id", config.ID, "name", config.Name, "type", config.Type)
+	result.Outcome = startupStarted
+	return result
 }
 
 // disableBackend sets a backend's enabled flag to false and persists the configuration change.
-// This is called when a backend reaches MaxConsecutiveFailures and needs to be auto-disabled.
+// This is called when a backend reaches its failure threshold and needs to be auto-disabled.
 // The configuration change will trigger OnConfigurationChange, which will stop the backend.
 func (p *Plugin) disableBackend(backendID string) error {
 	// Get the current configuration (handles locking internally)
@@ -155,11 +520,13 @@ func (p *Plugin) disableBackend(backendID string) error {
 	// Find the backend in the cloned configuration
 	found := false
 	var backendName string
+	var failureThreshold int
 	for i := range configClone.Backends {
 		if configClone.Backends[i].ID == backendID {
 			// Set enabled to false
 			configClone.Backends[i].Enabled = false
 			backendName = configClone.Backends[i].Name
+			failureThreshold = configClone.Backends[i].MaxFailures()
 			found = true
 			break
 		}
@@ -171,6 +538,11 @@ func (p *Plugin) disableBackend(backendID string) error {
 
 	p.API.LogInfo("Disabling backend in configuration", "id", backendID, "name", backendName)
 
+	// Keep alerts flowing with the backend's standby credentials, if it has any
+	if p.failover != nil {
+		p.failover.FailOver(backendID, fmt.Sprintf("it was disabled after %d consecutive failed polls", failureThreshold))
+	}
+
 	// Marshal the configuration to map[string]any for SavePluginConfig
 	marshalBytes, err := json.Marshal(configClone)
 	if err != nil {
@@ -188,6 +560,7 @@ func (p *Plugin) disableBackend(backendID string) error {
 	}
 
 	p.API.LogInfo("Backend disabled and configuration persisted", "id", backendID)
+	p.publishBackendDisabled(backendID, backendName, fmt.Sprintf("%d consecutive failed polls", failureThreshold))
 	return nil
 }
 
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newMemoryKV backs the KV methods of a mock API with a map, honoring compare-and-set, and
+// returns the map
+func newMemoryKV(api *plugintest.API) map[string][]byte {
+	kv := make(map[string][]byte)
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return kv[key], nil
+	}).Maybe()
+	api.On("KVSet", mock.Anything, mock.Anything).Return(func(key string, value []byte) *model.AppError {
+		kv[key] = value
+		return nil
+	}).Maybe()
+	api.On("KVDelete", mock.Anything).Return(func(key string) *model.AppError {
+		delete(kv, key)
+		return nil
+	}).Maybe()
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+			if options.Atomic && !bytes.Equal(kv[key], options.OldValue) {
+				return false, nil
+			}
+			if value == nil {
+				delete(kv, key)
+			} else {
+				kv[key] = value
+			}
+			return true, nil
+		}).Maybe()
+	return kv
+}
+
+func TestBuildUserAgent(t *testing.T) {
+	t.Run("plugin and server versions", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("GetPluginStatus", pluginID).Return(&model.PluginStatus{PluginId: pluginID, Version: "0.9.3"}, nil)
+		api.On("GetServerVersion").Return("10.5.0")
+
+		p := newCommandTestPlugin(api)
+		assert.Equal(t, "mattermost-plugin-dataminr/0.9.3 Mattermost/10.5.0", p.buildUserAgent())
+	})
+
+	t.Run("unknown versions are left out", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("GetPluginStatus", pluginID).Return(nil, model.NewAppError("GetPluginStatus", "not_found", nil, "", 404))
+		api.On("GetServerVersion").Return("")
+
+		p := newCommandTestPlugin(api)
+		assert.Equal(t, "mattermost-plugin-dataminr", p.buildUserAgent())
+	})
+}
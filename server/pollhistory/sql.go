@@ -0,0 +1,178 @@
+// Package pollhistory keeps the poll cycle outcomes of backends in the Mattermost database,
+// for the "sql" storage driver. Backends keep them with their state in the KV store otherwise.
+package pollhistory
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/sqlstore"
+)
+
+const (
+	// tableName is the table holding the poll samples of every backend
+	tableName = "dataminr_poll_history"
+
+	// migrationsTableName records the schema migrations applied to the poll history table
+	migrationsTableName = "dataminr_poll_history_migrations"
+
+	// sampleColumns are the columns a sample is read from, in the order scanned by scanSamples
+	sampleColumns = "sample_time, success, alerts, new_alerts, latency_ms, error"
+)
+
+// migrations are the schema changes of the poll history table, in the order they are applied.
+// Applied migrations must never change; add a new migration instead.
+var migrations = []sqlstore.Migration{
+	{
+		Version: 1,
+		Postgres: []string{
+			`CREATE TABLE IF NOT EXISTS ` + tableName + ` (
+				id VARCHAR(26) NOT NULL PRIMARY KEY,
+				backend_id VARCHAR(36) NOT NULL,
+				sample_time BIGINT NOT NULL,
+				success BOOLEAN NOT NULL,
+				alerts INTEGER NOT NULL,
+				new_alerts INTEGER NOT NULL,
+				latency_ms BIGINT NOT NULL,
+				error TEXT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_dataminr_poll_history_backend ON ` + tableName + ` (backend_id, sample_time)`,
+			`CREATE INDEX IF NOT EXISTS idx_dataminr_poll_history_time ON ` + tableName + ` (sample_time)`,
+		},
+		MySQL: []string{
+			`CREATE TABLE IF NOT EXISTS ` + tableName + ` (
+				id VARCHAR(26) NOT NULL PRIMARY KEY,
+				backend_id VARCHAR(36) NOT NULL,
+				sample_time BIGINT NOT NULL,
+				success BOOLEAN NOT NULL,
+				alerts INTEGER NOT NULL,
+				new_alerts INTEGER NOT NULL,
+				latency_ms BIGINT NOT NULL,
+				error TEXT NOT NULL,
+				INDEX idx_dataminr_poll_history_backend (backend_id, sample_time),
+				INDEX idx_dataminr_poll_history_time (sample_time)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+		},
+	},
+}
+
+// SQLStore stores the poll samples of backends in a table of the Mattermost database.
+// Samples past the retention period are not returned and are deleted by Prune.
+type SQLStore struct {
+	db     *sql.DB
+	driver string
+	now    func() time.Time
+	newID  func() string
+}
+
+// NewSQL creates a poll history store in a Mattermost database of the given driver
+// ("postgres" or "mysql"). Migrate must be called before the store is used.
+func NewSQL(db *sql.DB, driver string) (*SQLStore, error) {
+	if err := sqlstore.CheckDriver(driver); err != nil {
+		return nil, err
+	}
+
+	return &SQLStore{
+		db:     db,
+		driver: driver,
+		now:    time.Now,
+		newID:  model.NewId,
+	}, nil
+}
+
+// Migrate applies the schema migrations not applied yet. Nodes of a cluster must not
+// migrate concurrently.
+func (s *SQLStore) Migrate() error {
+	return sqlstore.Migrate(s.db, s.driver, migrationsTableName, migrations)
+}
+
+// ForBackend returns the poll history of a backend
+func (s *SQLStore) ForBackend(backendID string) *BackendHistory {
+	return &BackendHistory{store: s, backendID: backendID}
+}
+
+// Prune deletes the samples of every backend taken before a time, e.g. past the retention
+// period. Returns the number of deleted samples.
+func (s *SQLStore) Prune(before time.Time) (int64, error) {
+	result, err := s.db.Exec(sqlstore.Rebind(s.driver, `DELETE FROM `+tableName+` WHERE sample_time < ?`), before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune poll history: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune poll history: %w", err)
+	}
+	return deleted, nil
+}
+
+// BackendHistory is the poll history of a single backend in an SQLStore
+type BackendHistory struct {
+	store     *SQLStore
+	backendID string
+}
+
+// RecordPollSample appends a poll cycle outcome. Samples beyond the retention period are
+// deleted by SQLStore.Prune, and samples beyond the sample limit are not returned.
+func (h *BackendHistory) RecordPollSample(sample backend.PollSample, _ time.Time) error {
+	s := h.store
+	_, err := s.db.Exec(sqlstore.Rebind(s.driver, `INSERT INTO `+tableName+` (id, backend_id, `+sampleColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+		s.newID(),
+		h.backendID,
+		sample.Time.UnixMilli(),
+		sample.Success,
+		sample.Alerts,
+		sample.NewAlerts,
+		sample.LatencyMs,
+		sample.Error,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save poll history: %w", err)
+	}
+	return nil
+}
+
+// GetPollHistory returns the newest MaxPollHistorySamples poll cycle outcomes within the
+// retention period, oldest first
+func (h *BackendHistory) GetPollHistory() ([]backend.PollSample, error) {
+	s := h.store
+	rows, err := s.db.Query(sqlstore.Rebind(s.driver, `SELECT `+sampleColumns+` FROM `+tableName+
+		` WHERE backend_id = ? AND sample_time >= ? ORDER BY sample_time DESC LIMIT `+strconv.Itoa(backend.MaxPollHistorySamples)),
+		h.backendID, s.now().Add(-backend.PollHistoryRetention).UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll history: %w", err)
+	}
+
+	samples, err := scanSamples(rows)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(samples)
+	return samples, nil
+}
+
+// scanSamples reads the samples of the rows of a query and closes them
+func scanSamples(rows *sql.Rows) ([]backend.PollSample, error) {
+	defer rows.Close()
+
+	var samples []backend.PollSample
+	for rows.Next() {
+		var sample backend.PollSample
+		var sampleTime int64
+		if err := rows.Scan(&sampleTime, &sample.Success, &sample.Alerts, &sample.NewAlerts, &sample.LatencyMs, &sample.Error); err != nil {
+			return nil, fmt.Errorf("failed to read poll sample: %w", err)
+		}
+		sample.Time = time.UnixMilli(sampleTime).UTC()
+		samples = append(samples, sample)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read poll history: %w", err)
+	}
+	return samples, nil
+}
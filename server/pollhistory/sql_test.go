@@ -0,0 +1,90 @@
+package pollhistory
+
+import (
+	"database/sql/driver"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/sqlstore/sqlstoretest"
+)
+
+// newTestSQLStore creates a poll history store on a fake database at a fixed time
+func newTestSQLStore(t *testing.T, driverName string, now time.Time) (*SQLStore, *sqlstoretest.FakeDatabase) {
+	fake, db := sqlstoretest.Open(t)
+
+	store, err := NewSQL(db, driverName)
+	require.NoError(t, err)
+	store.now = func() time.Time { return now }
+	store.newID = func() string { return "sample-1" }
+	return store, fake
+}
+
+func TestNewSQL(t *testing.T) {
+	_, err := NewSQL(nil, "sqlite3")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported database driver 'sqlite3'")
+}
+
+func TestSQLStore_Migrate(t *testing.T) {
+	store, fake := newTestSQLStore(t, "mysql", time.Now())
+
+	require.NoError(t, store.Migrate())
+
+	require.Len(t, fake.Execs, 2+len(migrations[0].MySQL))
+	assert.Contains(t, fake.Execs[0].Query, "CREATE TABLE IF NOT EXISTS dataminr_poll_history_migrations")
+	assert.Contains(t, fake.Execs[1].Query, "CREATE TABLE IF NOT EXISTS dataminr_poll_history")
+}
+
+func TestBackendHistory_RecordPollSample(t *testing.T) {
+	store, fake := newTestSQLStore(t, "postgres", time.Now())
+	at := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	require.NoError(t, store.ForBackend("backend-1").RecordPollSample(backend.PollSample{
+		Time: at, Success: false, Alerts: 3, NewAlerts: 1, LatencyMs: 250, Error: "timeout",
+	}, at))
+
+	require.Len(t, fake.Execs, 1)
+	assert.Equal(t, "INSERT INTO dataminr_poll_history (id, backend_id, sample_time, success, alerts, new_alerts, latency_ms, error)"+
+		" VALUES ($1, $2, $3, $4, $5, $6, $7, $8)", fake.Execs[0].Query)
+	assert.Equal(t, []driver.Value{"sample-1", "backend-1", at.UnixMilli(), false, int64(3), int64(1), int64(250), "timeout"}, fake.Execs[0].Args)
+}
+
+func TestBackendHistory_GetPollHistory(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	store, fake := newTestSQLStore(t, "postgres", now)
+	fake.Rows["FROM dataminr_poll_history"] = [][]driver.Value{
+		{now.Add(-time.Minute).UnixMilli(), true, int64(2), int64(2), int64(100), ""},
+		{now.Add(-2 * time.Minute).UnixMilli(), false, int64(0), int64(0), int64(30000), "timeout"},
+	}
+
+	samples, err := store.ForBackend("backend-1").GetPollHistory()
+	require.NoError(t, err)
+
+	require.Len(t, fake.Queries, 1)
+	assert.Equal(t, "SELECT sample_time, success, alerts, new_alerts, latency_ms, error FROM dataminr_poll_history"+
+		" WHERE backend_id = $1 AND sample_time >= $2 ORDER BY sample_time DESC LIMIT "+strconv.Itoa(backend.MaxPollHistorySamples), fake.Queries[0].Query)
+	assert.Equal(t, []driver.Value{"backend-1", now.Add(-backend.PollHistoryRetention).UnixMilli()}, fake.Queries[0].Args)
+	assert.Equal(t, []backend.PollSample{
+		{Time: now.Add(-2 * time.Minute), Success: false, LatencyMs: 30000, Error: "timeout"},
+		{Time: now.Add(-time.Minute), Success: true, Alerts: 2, NewAlerts: 2, LatencyMs: 100},
+	}, samples)
+}
+
+func TestSQLStore_Prune(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	store, fake := newTestSQLStore(t, "postgres", now)
+	fake.RowsAffected = 4
+
+	deleted, err := store.Prune(now.Add(-backend.PollHistoryRetention))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+	assert.Equal(t, "DELETE FROM dataminr_poll_history WHERE sample_time < $1", fake.Execs[0].Query)
+}
+
+// The SQL store records poll history like the backend state in the KV store
+var _ backend.PollHistoryStore = (*BackendHistory)(nil)
@@ -0,0 +1,125 @@
+package poster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/ack"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// ackActionID is the ID of the Acknowledge button on alert posts
+const ackActionID = "acknowledge"
+
+// AckTracker tracks the acknowledgment of alert posts with an Acknowledge button.
+type AckTracker interface {
+	Track(entry ack.Entry) error
+}
+
+// SetAckTracker configures the tracker for alerts posted with an Acknowledge button and the
+// integration URL the button calls. Must be called before alerts are posted.
+func (p *Poster) SetAckTracker(tracker AckTracker, actionURL string) {
+	p.ackTracker = tracker
+	p.ackActionURL = actionURL
+}
+
+// SetAckSLAs replaces the acknowledgment SLA settings, keyed by backend ID.
+// Alerts from backends without an entry are posted without an Acknowledge button.
+func (p *Poster) SetAckSLAs(settings map[string]backend.AckSLASettings) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.ackSLAs = settings
+}
+
+// getAckSLA returns the acknowledgment SLA settings for an alert, and whether the alert
+// needs acknowledgment (only Flash alerts from backends with an SLA do)
+func (p *Poster) getAckSLA(alert backend.Alert) (backend.AckSLASettings, bool) {
+	if p.ackTracker == nil || alert.Priority() != 0 {
+		return backend.AckSLASettings{}, false
+	}
+
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	settings, exists := p.ackSLAs[alert.BackendID]
+	return settings, exists
+}
+
+// addAckAction adds the Acknowledge button to an alert attachment
+func (p *Poster) addAckAction(attachment *model.SlackAttachment, alert backend.Alert) {
+	attachment.Actions = append(attachment.Actions, &model.PostAction{
+		Id:    ackActionID,
+		Name:  "Acknowledge",
+		Type:  model.PostActionTypeButton,
+		Style: "primary",
+		Integration: &model.PostActionIntegration{
+			URL: p.ackActionURL,
+			Context: map[string]any{
+				"backend_id": alert.BackendID,
+				"alert_id":   alert.AlertID,
+			},
+		},
+	})
+}
+
+// trackAck starts the acknowledgment deadline of a posted Flash alert.
+// Failures are logged since the alert itself has already been posted.
+func (p *Poster) trackAck(alert backend.Alert, post *model.Post, settings backend.AckSLASettings) {
+	postedAt := p.now()
+	entry := ack.Entry{
+		PostID:         post.Id,
+		ChannelID:      post.ChannelId,
+		BackendID:      alert.BackendID,
+		BackendName:    alert.BackendName,
+		AlertID:        alert.AlertID,
+		Headline:       alert.Headline,
+		PostedAt:       postedAt,
+		Deadline:       postedAt.Add(settings.Window()),
+		OnCallUsername: settings.OnCallUser(),
+	}
+
+	if err := p.ackTracker.Track(entry); err != nil {
+		p.api.LogWarn("Failed to track alert acknowledgment", "alertId", alert.AlertID, "error", err.Error())
+	}
+}
+
+// MarkAcknowledged replaces the Acknowledge button on an alert post with who acknowledged it and when
+func (p *Poster) MarkAcknowledged(postID, username string, at time.Time) error {
+	post, appErr := p.api.GetPost(postID)
+	if appErr != nil {
+		return fmt.Errorf("failed to get alert post: %w", appErr)
+	}
+
+	attachments := post.Attachments()
+	for _, attachment := range attachments {
+		actions := make([]*model.PostAction, 0, len(attachment.Actions))
+		removed := false
+		for _, action := range attachment.Actions {
+			if action.Id == ackActionID {
+				removed = true
+				continue
+			}
+			actions = append(actions, action)
+		}
+		if !removed {
+			continue
+		}
+
+		attachment.Actions = actions
+		attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{
+			Title: "Acknowledged",
+			Value: fmt.Sprintf(":white_check_mark: @%s at %s", username, at.UTC().Format("2006-01-02 15:04:05 MST")),
+			Short: false,
+		})
+	}
+	model.ParseSlackAttachment(post, attachments)
+
+	if _, appErr := p.api.UpdatePost(post); appErr != nil {
+		return fmt.Errorf("failed to update alert post: %w", appErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package poster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/ack"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// fakeAckTracker records tracked acknowledgment entries
+type fakeAckTracker struct {
+	entries []ack.Entry
+}
+
+func (f *fakeAckTracker) Track(entry ack.Entry) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func TestPostAlert_AckButton(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	var posts []*model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posts = append(posts, args.Get(0).(*model.Post))
+	}).Return(func(post *model.Post) (*model.Post, *model.AppError) {
+		created := post.Clone()
+		created.Id = "post-" + post.ChannelId
+		return created, nil
+	})
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tracker := &fakeAckTracker{}
+	poster := New(api, "bot-user-id")
+	poster.now = func() time.Time { return now }
+	poster.SetAckTracker(tracker, "/plugins/test/actions/acknowledge")
+	poster.SetAckSLAs(map[string]backend.AckSLASettings{
+		"backend-sla": {WindowMinutes: 10, OnCallUsername: "@duty.officer"},
+	})
+
+	flash := backend.Alert{BackendID: "backend-sla", BackendName: "Weather Watch", AlertID: "alert-1", AlertType: "Flash", Headline: "Tornado warning"}
+	require.NoError(t, poster.PostAlert(context.Background(), flash, "channel-1"))
+
+	urgent := flash
+	urgent.AlertID = "alert-2"
+	urgent.AlertType = "Urgent"
+	require.NoError(t, poster.PostAlert(context.Background(), urgent, "channel-2"))
+
+	other := flash
+	other.AlertID = "alert-3"
+	other.BackendID = "backend-other"
+	require.NoError(t, poster.PostAlert(context.Background(), other, "channel-3"))
+
+	require.Len(t, posts, 3)
+	actions := posts[0].Attachments()[0].Actions
+	require.Len(t, actions, 1)
+	assert.Equal(t, "Acknowledge", actions[0].Name)
+	assert.Equal(t, "/plugins/test/actions/acknowledge", actions[0].Integration.URL)
+	assert.Equal(t, "alert-1", actions[0].Integration.Context["alert_id"])
+	assert.Empty(t, posts[1].Attachments()[0].Actions, "only Flash alerts need acknowledgment")
+	assert.Empty(t, posts[2].Attachments()[0].Actions, "backends without an SLA have no button")
+
+	require.Len(t, tracker.entries, 1)
+	assert.Equal(t, ack.Entry{
+		PostID:         "post-channel-1",
+		ChannelID:      "channel-1",
+		BackendID:      "backend-sla",
+		BackendName:    "Weather Watch",
+		AlertID:        "alert-1",
+		Headline:       "Tornado warning",
+		PostedAt:       now,
+		Deadline:       now.Add(10 * time.Minute),
+		OnCallUsername: "duty.officer",
+	}, tracker.entries[0])
+}
+
+func TestMarkAcknowledged(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	post := &model.Post{Id: "post-1", ChannelId: "channel-1"}
+	poster := New(api, "bot-user-id")
+	poster.SetAckTracker(&fakeAckTracker{}, "/plugins/test/actions/acknowledge")
+	attachment := &model.SlackAttachment{Title: "Tornado warning"}
+	poster.addAckAction(attachment, backend.Alert{AlertID: "alert-1"})
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{attachment})
+
+	var updated *model.Post
+	api.On("GetPost", "post-1").Return(post, nil)
+	api.On("UpdatePost", mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(0).(*model.Post)
+	}).Return(post, nil)
+
+	require.NoError(t, poster.MarkAcknowledged("post-1", "jane", time.Date(2026, 10, 16, 9, 5, 0, 0, time.UTC)))
+
+	require.NotNil(t, updated)
+	attachments := updated.Attachments()
+	require.Len(t, attachments, 1)
+	assert.Empty(t, attachments[0].Actions)
+	require.Len(t, attachments[0].Fields, 1)
+	assert.Equal(t, "Acknowledged", attachments[0].Fields[0].Title)
+	assert.Equal(t, ":white_check_mark: @jane at 2026-10-16 09:05:00 UTC", attachments[0].Fields[0].Value)
+}
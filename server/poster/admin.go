@@ -0,0 +1,32 @@
+package poster
+
+import "github.com/mattermost/mattermost/server/public/model"
+
+// SetAdminChannel sets the channel operator warnings are posted to (empty disables them)
+func (p *Poster) SetAdminChannel(channelID string) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.adminChannelID = channelID
+}
+
+// PostAdminMessage posts a plain text message from the bot to the admin channel, e.g. a
+// warning that a backend is about to be disabled. Does nothing without an admin channel.
+func (p *Poster) PostAdminMessage(message string) error {
+	p.optionsLock.RLock()
+	channelID := p.adminChannelID
+	p.optionsLock.RUnlock()
+
+	if channelID == "" {
+		return nil
+	}
+
+	if _, err := p.api.CreatePost(&model.Post{
+		UserId:    p.botID,
+		ChannelId: channelID,
+		Message:   message,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package poster
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostAdminMessage(t *testing.T) {
+	t.Run("posts to the admin channel", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+			return post.UserId == "bot-user-id" && post.ChannelId == "admin-channel" && post.Message == "warning"
+		})).Return(&model.Post{Id: "post1"}, nil)
+
+		poster := New(api, "bot-user-id")
+		poster.SetAdminChannel("admin-channel")
+		require.NoError(t, poster.PostAdminMessage("warning"))
+	})
+
+	t.Run("without an admin channel", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		poster := New(api, "bot-user-id")
+		require.NoError(t, poster.PostAdminMessage("warning"))
+		api.AssertNotCalled(t, "CreatePost", mock.Anything)
+	})
+
+	t.Run("post fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("CreatePost", mock.Anything).Return(nil, model.NewAppError("CreatePost", "error", nil, "", 500))
+
+		poster := New(api, "bot-user-id")
+		poster.SetAdminChannel("admin-channel")
+		assert.Error(t, poster.PostAdminMessage("warning"))
+	})
+}
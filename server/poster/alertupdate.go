@@ -0,0 +1,115 @@
+package poster
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// PostAlertUpdate replies in the thread of an alert's post in every channel it was posted to
+// with a summary of what changed when the alert is reissued under the same alert ID with new
+// content. Does nothing if the alert's post is unknown, its content wasn't recorded, or the
+// compared content didn't change. A failed reply in one channel doesn't keep the others from
+// being posted.
+func (p *Poster) PostAlertUpdate(alert backend.Alert) error {
+	if p.alertPosts == nil {
+		return nil
+	}
+
+	// The update is recorded first so an update fetched again isn't summarized twice
+	previous, err := p.alertPosts.UpdateContent(alert)
+	if err != nil {
+		return err
+	}
+	if previous == nil || !previous.HasContent() {
+		return nil
+	}
+
+	diff := previous.Alert().Diff(alert)
+	if diff.IsEmpty() {
+		return nil
+	}
+
+	// The first post is replied to first, then the copies in channel order
+	posts := previous.Posts()
+	postIDs := []string{previous.PostID}
+	for _, channelID := range slices.Sorted(maps.Keys(posts)) {
+		if channelID != previous.ChannelID {
+			postIDs = append(postIDs, posts[channelID])
+		}
+	}
+
+	message := formatAlertUpdate(diff)
+	var failed int
+	var firstErr error
+	for _, postID := range postIDs {
+		if err := p.replyAlertUpdate(postID, message); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if firstErr != nil {
+		return fmt.Errorf("failed to post alert update in %d of %d channels: %w", failed, len(postIDs), firstErr)
+	}
+	return nil
+}
+
+// replyAlertUpdate posts the summary of an alert update in the thread of an alert post
+func (p *Poster) replyAlertUpdate(postID, message string) error {
+	alertPost, appErr := p.api.GetPost(postID)
+	if appErr != nil {
+		return fmt.Errorf("failed to get alert post: %w", appErr)
+	}
+
+	// Rate-limited alerts are already replies, so attach to the same thread
+	rootID := alertPost.RootId
+	if rootID == "" {
+		rootID = alertPost.Id
+	}
+
+	if _, appErr := p.api.CreatePost(&model.Post{
+		UserId:    p.botID,
+		ChannelId: alertPost.ChannelId,
+		RootId:    rootID,
+		Message:   message,
+	}); appErr != nil {
+		return fmt.Errorf("failed to post alert update: %w", appErr)
+	}
+	return nil
+}
+
+// formatAlertUpdate builds the thread reply summarizing the changes of an alert update
+func formatAlertUpdate(diff backend.AlertDiff) string {
+	lines := []string{"**Alert updated**"}
+
+	if diff.Headline != "" || diff.PreviousHeadline != "" {
+		lines = append(lines, fmt.Sprintf("- Headline: ~~%s~~ → %s", diff.PreviousHeadline, diff.Headline))
+	}
+
+	if diff.LocationChanged {
+		switch {
+		case diff.PreviousLocation == "":
+			lines = append(lines, "- Location added: "+diff.Location)
+		case diff.Location == "":
+			lines = append(lines, "- Location removed: "+diff.PreviousLocation)
+		default:
+			lines = append(lines, fmt.Sprintf("- Location: %s → %s", diff.PreviousLocation, diff.Location))
+		}
+	}
+
+	if len(diff.AddedTopics) > 0 {
+		lines = append(lines, "- Topics added: "+strings.Join(diff.AddedTopics, ", "))
+	}
+	if len(diff.RemovedTopics) > 0 {
+		lines = append(lines, "- Topics removed: "+strings.Join(diff.RemovedTopics, ", "))
+	}
+
+	return strings.Join(lines, "\n")
+}
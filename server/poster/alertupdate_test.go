@@ -0,0 +1,182 @@
+package poster
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertposts"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestFormatAlertUpdate(t *testing.T) {
+	t.Run("every change", func(t *testing.T) {
+		message := formatAlertUpdate(backend.AlertDiff{
+			PreviousHeadline: "Flooding reported downtown",
+			Headline:         "Flooding reported across the city",
+			LocationChanged:  true,
+			PreviousLocation: "Austin, TX, USA",
+			Location:         "Round Rock, TX, USA",
+			AddedTopics:      []string{"Evacuation", "Road Closure"},
+			RemovedTopics:    []string{"Weather"},
+		})
+		assert.Equal(t, "**Alert updated**\n"+
+			"- Headline: ~~Flooding reported downtown~~ → Flooding reported across the city\n"+
+			"- Location: Austin, TX, USA → Round Rock, TX, USA\n"+
+			"- Topics added: Evacuation, Road Closure\n"+
+			"- Topics removed: Weather", message)
+	})
+
+	t.Run("location added", func(t *testing.T) {
+		message := formatAlertUpdate(backend.AlertDiff{LocationChanged: true, Location: "Austin, TX, USA"})
+		assert.Equal(t, "**Alert updated**\n- Location added: Austin, TX, USA", message)
+	})
+
+	t.Run("location removed", func(t *testing.T) {
+		message := formatAlertUpdate(backend.AlertDiff{LocationChanged: true, PreviousLocation: "Austin, TX, USA"})
+		assert.Equal(t, "**Alert updated**\n- Location removed: Austin, TX, USA", message)
+	})
+}
+
+func TestPostAlertUpdate(t *testing.T) {
+	original := alertposts.Record{
+		BackendID: "backend-id",
+		AlertID:   "alert-123",
+		PostID:    "alert-post-id",
+		ChannelID: "channel-id",
+		Headline:  "Flooding reported downtown",
+		Topics:    []string{"Weather"},
+	}
+	update := backend.Alert{
+		BackendID: "backend-id",
+		AlertID:   "alert-123",
+		Headline:  "Flooding reported across the city",
+		Topics:    []string{"Weather", "Evacuation"},
+	}
+
+	t.Run("replies with the changes in the thread of the alert post", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("GetPost", "alert-post-id").Return(&model.Post{Id: "alert-post-id", ChannelId: "channel-id"}, nil).Once()
+		var reply *model.Post
+		api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+			reply = args.Get(0).(*model.Post)
+		}).Return(&model.Post{Id: "reply-id"}, nil).Once()
+
+		store := &recordingAlertPosts{records: []alertposts.Record{original}}
+		poster := New(api, "bot-user-id")
+		poster.SetAlertPostStore(store)
+
+		require.NoError(t, poster.PostAlertUpdate(update))
+
+		require.NotNil(t, reply)
+		assert.Equal(t, "alert-post-id", reply.RootId)
+		assert.Equal(t, "channel-id", reply.ChannelId)
+		assert.Equal(t, "bot-user-id", reply.UserId)
+		assert.Equal(t, "**Alert updated**\n"+
+			"- Headline: ~~Flooding reported downtown~~ → Flooding reported across the city\n"+
+			"- Topics added: Evacuation", reply.Message)
+		assert.Equal(t, "Flooding reported across the city", store.records[0].Headline)
+
+		// The same update fetched again is not summarized twice
+		require.NoError(t, poster.PostAlertUpdate(update))
+	})
+
+	t.Run("replies in every channel the alert was posted to", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("GetPost", "alert-post-id").Return(&model.Post{Id: "alert-post-id", ChannelId: "channel-id"}, nil).Once()
+		api.On("GetPost", "subscribed-post-id").Return(&model.Post{Id: "subscribed-post-id", ChannelId: "subscribed-channel-id"}, nil).Once()
+		api.On("GetPost", "routed-post-id").Return(nil, model.NewAppError("GetPost", "app.error", nil, "", 404)).Once()
+
+		var replies []*model.Post
+		api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+			replies = append(replies, args.Get(0).(*model.Post))
+		}).Return(&model.Post{Id: "reply-id"}, nil).Twice()
+
+		copied := original
+		copied.Copies = map[string]string{"subscribed-channel-id": "subscribed-post-id", "routed-channel-id": "routed-post-id"}
+		poster := New(api, "bot-user-id")
+		poster.SetAlertPostStore(&recordingAlertPosts{records: []alertposts.Record{copied}})
+
+		// The deleted routed copy doesn't keep the other channels from being updated
+		err := poster.PostAlertUpdate(update)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to post alert update in 1 of 3 channels")
+
+		require.Len(t, replies, 2)
+		assert.Equal(t, "alert-post-id", replies[0].RootId)
+		assert.Equal(t, "channel-id", replies[0].ChannelId)
+		assert.Equal(t, "subscribed-post-id", replies[1].RootId)
+		assert.Equal(t, "subscribed-channel-id", replies[1].ChannelId)
+		assert.Equal(t, replies[0].Message, replies[1].Message)
+	})
+
+	t.Run("rate-limited alert posts are replied to in their thread", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("GetPost", "alert-post-id").Return(&model.Post{Id: "alert-post-id", RootId: "overflow-id", ChannelId: "channel-id"}, nil).Once()
+		api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool { return post.RootId == "overflow-id" })).
+			Return(&model.Post{Id: "reply-id"}, nil).Once()
+
+		poster := New(api, "bot-user-id")
+		poster.SetAlertPostStore(&recordingAlertPosts{records: []alertposts.Record{original}})
+
+		require.NoError(t, poster.PostAlertUpdate(update))
+	})
+
+	t.Run("nothing is posted without a change", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		unchanged := update
+		unchanged.Headline = original.Headline
+		unchanged.Topics = original.Topics
+
+		poster := New(api, "bot-user-id")
+		poster.SetAlertPostStore(&recordingAlertPosts{records: []alertposts.Record{original}})
+
+		require.NoError(t, poster.PostAlertUpdate(unchanged))
+	})
+
+	t.Run("nothing is posted for unknown alerts or records without content", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		withoutContent := alertposts.Record{BackendID: "backend-id", AlertID: "alert-123", PostID: "alert-post-id"}
+		store := &recordingAlertPosts{records: []alertposts.Record{withoutContent}}
+		poster := New(api, "bot-user-id")
+		poster.SetAlertPostStore(store)
+
+		require.NoError(t, poster.PostAlertUpdate(update))
+		assert.Equal(t, update.Headline, store.records[0].Headline, "The content should be recorded for later updates")
+
+		unknown := update
+		unknown.AlertID = "alert-unknown"
+		require.NoError(t, poster.PostAlertUpdate(unknown))
+
+		require.NoError(t, New(api, "bot-user-id").PostAlertUpdate(update))
+	})
+
+	t.Run("reply failure", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("GetPost", "alert-post-id").Return(&model.Post{Id: "alert-post-id", ChannelId: "channel-id"}, nil).Once()
+		api.On("CreatePost", mock.Anything).Return(nil, model.NewAppError("CreatePost", "app.error", nil, "", 500)).Once()
+
+		poster := New(api, "bot-user-id")
+		poster.SetAlertPostStore(&recordingAlertPosts{records: []alertposts.Record{original}})
+
+		err := poster.PostAlertUpdate(update)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to post alert update")
+	})
+}
@@ -0,0 +1,63 @@
+package poster
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// ExpiredProp is the post prop marking an alert post struck through after its TTL, holding
+	// when it expired in milliseconds
+	ExpiredProp = "dataminr_expired_at"
+
+	// expiredColor greys out the attachment of expired alert posts
+	expiredColor = "#8A8A8A"
+
+	// headlinePrefix starts the text of alert attachments, followed by the headline
+	headlinePrefix = "### "
+)
+
+// ExpireAlertPost applies an alert TTL action to an alert post: the post is deleted, or its
+// headline is struck through and its attachment greyed out. Posts that were already deleted
+// or expired are left as they are.
+func (p *Poster) ExpireAlertPost(postID, action string, at time.Time) error {
+	post, appErr := p.api.GetPost(postID)
+	if appErr != nil {
+		if appErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to get alert post: %w", appErr)
+	}
+	if post.DeleteAt != 0 || post.GetProp(ExpiredProp) != nil {
+		return nil
+	}
+
+	if action == backend.AlertTTLActionDelete {
+		if appErr := p.api.DeletePost(postID); appErr != nil {
+			return fmt.Errorf("failed to delete expired alert post: %w", appErr)
+		}
+		return nil
+	}
+
+	attachments := post.Attachments()
+	if len(attachments) > 0 {
+		alertAttachment := attachments[0]
+		if headline, ok := strings.CutPrefix(alertAttachment.Text, headlinePrefix); ok && headline != "" {
+			alertAttachment.Text = headlinePrefix + "~~" + headline + "~~"
+		}
+		alertAttachment.Color = expiredColor
+		model.ParseSlackAttachment(post, attachments)
+	}
+	post.AddProp(ExpiredProp, at.UnixMilli())
+
+	if _, appErr := p.api.UpdatePost(post); appErr != nil {
+		return fmt.Errorf("failed to update expired alert post: %w", appErr)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package poster
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// newAlertPost returns an alert post with the attachment layout of posted alerts
+func newAlertPost() *model.Post {
+	post := &model.Post{Id: "post-1", ChannelId: "channel-1"}
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{
+		{Text: "### Flood advisory for the river valley", Color: "#FFD700"},
+		{Text: "Map"},
+	})
+	return post
+}
+
+func TestExpireAlertPost_Strikethrough(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	expiredAt := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	var updated *model.Post
+	api.On("GetPost", "post-1").Return(newAlertPost(), nil)
+	api.On("UpdatePost", mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(0).(*model.Post)
+	}).Return(nil, nil)
+
+	poster := New(api, "bot-user-id")
+	require.NoError(t, poster.ExpireAlertPost("post-1", backend.AlertTTLActionStrikethrough, expiredAt))
+
+	require.NotNil(t, updated)
+	attachments := updated.Attachments()
+	require.Len(t, attachments, 2)
+	assert.Equal(t, "### ~~Flood advisory for the river valley~~", attachments[0].Text)
+	assert.Equal(t, expiredColor, attachments[0].Color)
+	assert.Equal(t, "Map", attachments[1].Text)
+	assert.EqualValues(t, expiredAt.UnixMilli(), updated.GetProp(ExpiredProp))
+}
+
+func TestExpireAlertPost_Delete(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("GetPost", "post-1").Return(newAlertPost(), nil)
+	api.On("DeletePost", "post-1").Return(nil)
+
+	poster := New(api, "bot-user-id")
+	require.NoError(t, poster.ExpireAlertPost("post-1", backend.AlertTTLActionDelete, time.Now()))
+}
+
+func TestExpireAlertPost_AlreadyGone(t *testing.T) {
+	t.Run("deleted post", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("GetPost", "post-1").Return(nil, model.NewAppError("GetPost", "app.post.get.app_error", nil, "", http.StatusNotFound))
+
+		poster := New(api, "bot-user-id")
+		require.NoError(t, poster.ExpireAlertPost("post-1", backend.AlertTTLActionDelete, time.Now()))
+	})
+
+	t.Run("already expired post", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		post := newAlertPost()
+		post.AddProp(ExpiredProp, int64(1))
+		api.On("GetPost", "post-1").Return(post, nil)
+
+		poster := New(api, "bot-user-id")
+		require.NoError(t, poster.ExpireAlertPost("post-1", backend.AlertTTLActionStrikethrough, time.Now()))
+	})
+
+	t.Run("lookup failure", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("GetPost", "post-1").Return(nil, model.NewAppError("GetPost", "app.post.get.app_error", nil, "", http.StatusInternalServerError))
+
+		poster := New(api, "bot-user-id")
+		err := poster.ExpireAlertPost("post-1", backend.AlertTTLActionStrikethrough, time.Now())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get alert post")
+	})
+}
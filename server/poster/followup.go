@@ -0,0 +1,167 @@
+package poster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/followup"
+)
+
+// MentionScheduler holds back the mentions of unavailable users until they are due.
+type MentionScheduler interface {
+	Schedule(mention followup.Mention) error
+}
+
+// SetMentionScheduler configures the scheduler for the mentions of users in Do Not Disturb or
+// out of office. Without one, the unavailable mention settings of backends are ignored.
+// Must be called before alerts are posted.
+func (p *Poster) SetMentionScheduler(scheduler MentionScheduler) {
+	p.mentionScheduler = scheduler
+}
+
+// holdUnavailableMentions removes the mentions of unavailable users from an alert's mentions.
+// They are replaced by the on-call user when the rules route them to on-call and the on-call
+// user is available, otherwise they are returned as the usernames to mention later.
+func (p *Poster) holdUnavailableMentions(ctx context.Context, alert backend.Alert, rules backend.MentionRules, mentions []string) ([]string, []string) {
+	unavailable := p.unavailableUsernames(alert, mentions)
+	if len(unavailable) == 0 {
+		return mentions, nil
+	}
+
+	kept := make([]string, 0, len(mentions))
+	var held []string
+	for _, mention := range mentions {
+		username := strings.TrimPrefix(mention, "@")
+		if unavailable[username] {
+			held = append(held, username)
+		} else {
+			kept = append(kept, mention)
+		}
+	}
+
+	if rules.Unavailable.ActionOrDefault() == backend.UnavailableMentionOnCall && rules.OnCall != nil {
+		onCall := p.resolveOnCall(ctx, alert, *rules.OnCall)
+		if onCall != "" && !unavailable[strings.TrimPrefix(onCall, "@")] && len(p.unavailableUsernames(alert, []string{onCall})) == 0 {
+			p.api.LogDebug("Mentioning the on-call user instead of unavailable users",
+				"alertId", alert.AlertID,
+				"backendName", alert.BackendName,
+				"unavailable", strings.Join(held, ", "))
+			return appendMention(kept, onCall), nil
+		}
+	}
+
+	return kept, held
+}
+
+// unavailableUsernames returns the mentioned users who are in Do Not Disturb or out of office.
+// Group and channel mentions are never unavailable. If statuses can't be checked, every user
+// is treated as available so critical alerts still notify someone.
+func (p *Poster) unavailableUsernames(alert backend.Alert, mentions []string) map[string]bool {
+	usernames := make([]string, 0, len(mentions))
+	for _, mention := range mentions {
+		usernames = append(usernames, strings.TrimPrefix(mention, "@"))
+	}
+
+	users, appErr := p.api.GetUsersByUsernames(usernames)
+	if appErr != nil {
+		p.api.LogWarn("Failed to check the status of mentioned users, mentioning them now",
+			"alertId", alert.AlertID, "error", appErr.Error())
+		return nil
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	usernamesByID := make(map[string]string, len(users))
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		usernamesByID[user.Id] = user.Username
+		userIDs = append(userIDs, user.Id)
+	}
+
+	statuses, appErr := p.api.GetUserStatusesByIds(userIDs)
+	if appErr != nil {
+		p.api.LogWarn("Failed to check the status of mentioned users, mentioning them now",
+			"alertId", alert.AlertID, "error", appErr.Error())
+		return nil
+	}
+
+	unavailable := make(map[string]bool)
+	for _, status := range statuses {
+		if status.Status == model.StatusDnd || status.Status == model.StatusOutOfOffice {
+			unavailable[usernamesByID[status.UserId]] = true
+		}
+	}
+	return unavailable
+}
+
+// deferMentions schedules the reply mentioning the users held back from an alert post.
+// If it can't be scheduled, the reply is posted right away so the users are still notified.
+func (p *Poster) deferMentions(alert backend.Alert, post *model.Post, usernames []string) {
+	if len(usernames) == 0 {
+		return
+	}
+
+	p.optionsLock.RLock()
+	rules := p.mentionRules[alert.BackendID]
+	p.optionsLock.RUnlock()
+
+	delay := backend.UnavailableMentionSettings{}.DeferDelay()
+	if rules.Unavailable != nil {
+		delay = rules.Unavailable.DeferDelay()
+	}
+
+	// Reply in the thread the alert was posted to, such as its incident or overflow thread
+	rootID := post.RootId
+	if rootID == "" {
+		rootID = post.Id
+	}
+
+	mention := followup.Mention{
+		PostID:    rootID,
+		ChannelID: post.ChannelId,
+		BackendID: alert.BackendID,
+		AlertID:   alert.AlertID,
+		Usernames: usernames,
+		DueAt:     p.now().Add(delay),
+	}
+	if err := p.mentionScheduler.Schedule(mention); err != nil {
+		p.api.LogWarn("Failed to defer mentions of unavailable users, mentioning them now",
+			"alertId", alert.AlertID, "error", err.Error())
+		if err := p.PostDeferredMentions(mention); err != nil {
+			p.api.LogError("Failed to post mentions of unavailable users", "alertId", alert.AlertID, "error", err.Error())
+		}
+	}
+}
+
+// PostDeferredMentions replies to an alert post, mentioning the users who were unavailable
+// when it was posted.
+func (p *Poster) PostDeferredMentions(mention followup.Mention) error {
+	mentions := make([]string, 0, len(mention.Usernames))
+	for _, username := range mention.Usernames {
+		mentions = append(mentions, "@"+username)
+	}
+
+	if _, appErr := p.api.CreatePost(&model.Post{
+		UserId:    p.botID,
+		ChannelId: mention.ChannelID,
+		RootId:    mention.PostID,
+		Message:   fmt.Sprintf(":bell: %s this alert was posted while you were in Do Not Disturb or out of office.", strings.Join(mentions, " ")),
+	}); appErr != nil {
+		return fmt.Errorf("failed to post deferred mentions: %w", appErr)
+	}
+	return nil
+}
+
+// appendMention adds a mention unless it is empty or already included
+func appendMention(mentions []string, mention string) []string {
+	if mention == "" || slices.Contains(mentions, mention) {
+		return mentions
+	}
+	return append(mentions, mention)
+}
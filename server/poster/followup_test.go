@@ -0,0 +1,245 @@
+package poster
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/followup"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/formatter"
+)
+
+// recordingScheduler is a MentionScheduler that keeps the scheduled mentions in memory
+type recordingScheduler struct {
+	mentions []followup.Mention
+	err      error
+}
+
+func (r *recordingScheduler) Schedule(mention followup.Mention) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.mentions = append(r.mentions, mention)
+	return nil
+}
+
+// mockUserStatuses makes the users with a status known to the API, using their username as user ID
+func mockUserStatuses(api *plugintest.API, statuses map[string]string) {
+	api.On("GetUsersByUsernames", mock.Anything).Return(func(usernames []string) ([]*model.User, *model.AppError) {
+		var users []*model.User
+		for _, username := range usernames {
+			if _, ok := statuses[username]; ok {
+				users = append(users, &model.User{Id: username, Username: username})
+			}
+		}
+		return users, nil
+	})
+	api.On("GetUserStatusesByIds", mock.Anything).Return(func(userIDs []string) ([]*model.Status, *model.AppError) {
+		result := make([]*model.Status, 0, len(userIDs))
+		for _, id := range userIDs {
+			result = append(result, &model.Status{UserId: id, Status: statuses[id]})
+		}
+		return result, nil
+	})
+}
+
+func TestPostAlert_DefersMentionsOfUnavailableUsers(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	mockUserStatuses(api, map[string]string{"alice": model.StatusDnd, "bob": model.StatusOnline, "carol": model.StatusOutOfOffice})
+
+	alert := backend.Alert{
+		BackendID:   "backend-security",
+		BackendName: "Corporate Security",
+		AlertID:     "alert-123",
+		AlertType:   "Flash",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+	}
+
+	var message string
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		message = args.Get(0).(*model.Post).Message
+	}).Return(&model.Post{Id: "post-id", ChannelId: "channel-id"}, nil).Once()
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	scheduler := &recordingScheduler{}
+	poster := New(api, "bot-user-id")
+	poster.now = func() time.Time { return now }
+	poster.SetMentionScheduler(scheduler)
+	poster.SetMentionRules(map[string]backend.MentionRules{
+		"backend-security": {
+			Flash:       []string{"@security-team", "@alice", "@bob", "@carol"},
+			Unavailable: &backend.UnavailableMentionSettings{DeferMinutes: 30},
+		},
+	})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	assert.True(t, strings.HasPrefix(message, "@security-team @bob "+formatter.FormatAlertTypeText(backend.Alert{AlertType: "Flash"}, formatter.Options{})))
+	assert.Equal(t, []followup.Mention{{
+		PostID:    "post-id",
+		ChannelID: "channel-id",
+		BackendID: "backend-security",
+		AlertID:   "alert-123",
+		Usernames: []string{"alice", "carol"},
+		DueAt:     now.Add(30 * time.Minute),
+	}}, scheduler.mentions)
+}
+
+func TestPostAlert_RoutesMentionsOfUnavailableUsersToOnCall(t *testing.T) {
+	alert := backend.Alert{
+		BackendID:   "backend-security",
+		BackendName: "Corporate Security",
+		AlertID:     "alert-123",
+		AlertType:   "Urgent",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+	}
+	rules := map[string]backend.MentionRules{
+		"backend-security": {
+			Urgent:      []string{"@alice"},
+			OnCall:      &backend.OnCallSettings{URL: "https://oncall.example.com"},
+			Unavailable: &backend.UnavailableMentionSettings{Action: backend.UnavailableMentionOnCall},
+		},
+	}
+
+	t.Run("available on-call user", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		mockUserStatuses(api, map[string]string{"alice": model.StatusDnd, "jane.doe": model.StatusAway})
+		api.On("LogDebug", "Mentioning the on-call user instead of unavailable users",
+			"alertId", "alert-123", "backendName", "Corporate Security", "unavailable", "alice").Once()
+
+		var message string
+		api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+			message = args.Get(0).(*model.Post).Message
+		}).Return(&model.Post{Id: "post-id"}, nil).Once()
+
+		scheduler := &recordingScheduler{}
+		poster := New(api, "bot-user-id")
+		poster.SetOnCallResolver(&fakeOnCallResolver{username: "jane.doe"})
+		poster.SetMentionScheduler(scheduler)
+		poster.SetMentionRules(rules)
+
+		require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+		assert.True(t, strings.HasPrefix(message, "@jane.doe "+formatter.FormatAlertTypeText(backend.Alert{AlertType: "Urgent"}, formatter.Options{})))
+		assert.Empty(t, scheduler.mentions)
+	})
+
+	t.Run("unavailable on-call user", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		mockUserStatuses(api, map[string]string{"alice": model.StatusDnd, "jane.doe": model.StatusOutOfOffice})
+
+		var message string
+		api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+			message = args.Get(0).(*model.Post).Message
+		}).Return(&model.Post{Id: "post-id"}, nil).Once()
+
+		scheduler := &recordingScheduler{}
+		poster := New(api, "bot-user-id")
+		poster.SetOnCallResolver(&fakeOnCallResolver{username: "jane.doe"})
+		poster.SetMentionScheduler(scheduler)
+		poster.SetMentionRules(rules)
+
+		require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+		assert.NotContains(t, message, "@")
+		require.Len(t, scheduler.mentions, 1)
+		assert.Equal(t, []string{"alice"}, scheduler.mentions[0].Usernames)
+	})
+}
+
+func TestPostAlert_MentionsUnavailableUsersWhenDeferralFails(t *testing.T) {
+	alert := backend.Alert{
+		BackendID:   "backend-security",
+		BackendName: "Corporate Security",
+		AlertID:     "alert-123",
+		AlertType:   "Flash",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+	}
+	rules := map[string]backend.MentionRules{
+		"backend-security": {
+			Flash:       []string{"@alice"},
+			Unavailable: &backend.UnavailableMentionSettings{},
+		},
+	}
+
+	t.Run("status lookup fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("GetUsersByUsernames", []string{"alice"}).Return(nil, &model.AppError{Message: "lookup failed"}).Once()
+		api.On("LogWarn", "Failed to check the status of mentioned users, mentioning them now",
+			"alertId", "alert-123", "error", mock.Anything).Once()
+
+		var message string
+		api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+			message = args.Get(0).(*model.Post).Message
+		}).Return(&model.Post{Id: "post-id"}, nil).Once()
+
+		scheduler := &recordingScheduler{}
+		poster := New(api, "bot-user-id")
+		poster.SetMentionScheduler(scheduler)
+		poster.SetMentionRules(rules)
+
+		require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+		assert.True(t, strings.HasPrefix(message, "@alice "))
+		assert.Empty(t, scheduler.mentions)
+	})
+
+	t.Run("scheduling fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		mockUserStatuses(api, map[string]string{"alice": model.StatusDnd})
+		api.On("LogWarn", "Failed to defer mentions of unavailable users, mentioning them now",
+			"alertId", "alert-123", "error", "kv unavailable").Once()
+
+		var posts []*model.Post
+		api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+			posts = append(posts, args.Get(0).(*model.Post))
+		}).Return(&model.Post{Id: "post-id", ChannelId: "channel-id"}, nil).Twice()
+
+		poster := New(api, "bot-user-id")
+		poster.SetMentionScheduler(&recordingScheduler{err: errors.New("kv unavailable")})
+		poster.SetMentionRules(rules)
+
+		require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+		require.Len(t, posts, 2)
+		assert.NotContains(t, posts[0].Message, "@alice")
+		assert.Equal(t, "post-id", posts[1].RootId)
+		assert.True(t, strings.HasPrefix(posts[1].Message, ":bell: @alice "))
+	})
+}
+
+func TestPostDeferredMentions(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("CreatePost", &model.Post{
+		UserId:    "bot-user-id",
+		ChannelId: "channel-id",
+		RootId:    "post-id",
+		Message:   ":bell: @alice @carol this alert was posted while you were in Do Not Disturb or out of office.",
+	}).Return(&model.Post{Id: "reply-id"}, nil).Once()
+
+	poster := New(api, "bot-user-id")
+	require.NoError(t, poster.PostDeferredMentions(followup.Mention{
+		PostID:    "post-id",
+		ChannelID: "channel-id",
+		Usernames: []string{"alice", "carol"},
+	}))
+}
@@ -0,0 +1,87 @@
+package poster
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// forwardActionID is the ID of the Forward button on alert posts
+	forwardActionID = "forward"
+
+	// ForwardedFromProp is the post prop holding the ID of the alert post a forwarded copy was made of
+	ForwardedFromProp = "dataminr_forwarded_from"
+)
+
+// SetForwardAction adds a Forward button calling the integration URL to every alert post
+// (empty disables the button). Must be called before alerts are posted.
+func (p *Poster) SetForwardAction(actionURL string) {
+	p.forwardActionURL = actionURL
+}
+
+// addForwardAction adds the Forward button to an alert attachment
+func (p *Poster) addForwardAction(attachment *model.SlackAttachment, alert backend.Alert) {
+	attachment.Actions = append(attachment.Actions, &model.PostAction{
+		Id:   forwardActionID,
+		Name: "Forward",
+		Type: model.PostActionTypeButton,
+		Integration: &model.PostActionIntegration{
+			URL: p.forwardActionURL,
+			Context: map[string]any{
+				"backend_id": alert.BackendID,
+				"alert_id":   alert.AlertID,
+			},
+		},
+	})
+}
+
+// ForwardAlertPost posts a copy of an alert post to another channel, noting in the footer
+// which channel it was forwarded from and by whom. The copy keeps the alert's content and
+// identity but not its mentions or buttons, so it notifies no one and can't be acknowledged.
+func (p *Poster) ForwardAlertPost(original *model.Post, channelID, fromChannelName, username string) (*model.Post, error) {
+	forwarded := &model.Post{
+		UserId:    p.botID,
+		ChannelId: channelID,
+		Type:      original.Type,
+		Message:   stripMentions(original.Message),
+		Props:     maps.Clone(original.GetProps()),
+	}
+
+	// Copy the attachments, since they may be shared with the original post's props
+	attachments := make([]*model.SlackAttachment, 0, len(original.Attachments()))
+	for _, attachment := range original.Attachments() {
+		copied := *attachment
+		copied.Actions = nil
+		attachments = append(attachments, &copied)
+	}
+	if len(attachments) > 0 {
+		note := fmt.Sprintf("Forwarded from ~%s by @%s", fromChannelName, username)
+		if attachments[0].Footer == "" {
+			attachments[0].Footer = note
+		} else {
+			attachments[0].Footer += footerSeparator + note
+		}
+	}
+	model.ParseSlackAttachment(forwarded, attachments)
+	forwarded.AddProp(ForwardedFromProp, original.Id)
+
+	created, appErr := p.api.CreatePost(forwarded)
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to post forwarded alert: %w", appErr)
+	}
+	return created, nil
+}
+
+// stripMentions removes the mentions prepended to an alert post message
+func stripMentions(message string) string {
+	fields := strings.Fields(message)
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "@") {
+		fields = fields[1:]
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,94 @@
+package poster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestPostAlert_ForwardButton(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	var posts []*model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posts = append(posts, args.Get(0).(*model.Post))
+	}).Return(&model.Post{Id: "post-1"}, nil)
+
+	alert := backend.Alert{BackendID: "backend-1", AlertID: "alert-1", AlertType: "Alert", Headline: "Road closed"}
+
+	poster := New(api, "bot-user-id")
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-1"))
+
+	poster.SetForwardAction("/plugins/test/actions/forward")
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-1"))
+
+	require.Len(t, posts, 2)
+	assert.Empty(t, posts[0].Attachments()[0].Actions, "no button without an action URL")
+
+	actions := posts[1].Attachments()[0].Actions
+	require.Len(t, actions, 1)
+	assert.Equal(t, "Forward", actions[0].Name)
+	assert.Equal(t, "/plugins/test/actions/forward", actions[0].Integration.URL)
+	assert.Equal(t, map[string]any{"backend_id": "backend-1", "alert_id": "alert-1"}, actions[0].Integration.Context)
+}
+
+func TestForwardAlertPost(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	original := &model.Post{
+		Id:        "post-1",
+		UserId:    "bot-user-id",
+		ChannelId: "channel-1",
+		Type:      model.PostTypeSlackAttachment,
+		Message:   "@channel @security-team :red_circle: **Flash** #fire",
+	}
+	model.ParseSlackAttachment(original, []*model.SlackAttachment{{
+		Text:    "### Warehouse fire",
+		Footer:  "Corporate Security",
+		Actions: []*model.PostAction{{Id: ackActionID, Name: "Acknowledge"}},
+	}})
+	original.AddProp(AlertIDProp, "alert-1")
+	original.AddProp(BackendIDProp, "backend-1")
+
+	var forwarded *model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		forwarded = args.Get(0).(*model.Post)
+	}).Return(&model.Post{Id: "post-2"}, nil).Once()
+
+	poster := New(api, "bot-user-id")
+	created, err := poster.ForwardAlertPost(original, "channel-2", "security-ops", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "post-2", created.Id)
+
+	require.NotNil(t, forwarded)
+	assert.Equal(t, "bot-user-id", forwarded.UserId)
+	assert.Equal(t, "channel-2", forwarded.ChannelId)
+	assert.Equal(t, ":red_circle: **Flash** #fire", forwarded.Message)
+	assert.Equal(t, "alert-1", forwarded.GetProp(AlertIDProp))
+	assert.Equal(t, "post-1", forwarded.GetProp(ForwardedFromProp))
+
+	attachments := forwarded.Attachments()
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "### Warehouse fire", attachments[0].Text)
+	assert.Empty(t, attachments[0].Actions)
+	assert.Equal(t, "Corporate Security | Forwarded from ~security-ops by @alice", attachments[0].Footer)
+
+	// The original post is left unchanged
+	assert.Len(t, original.Attachments()[0].Actions, 1)
+	assert.Nil(t, original.GetProp(ForwardedFromProp))
+}
+
+func TestStripMentions(t *testing.T) {
+	assert.Equal(t, ":red_circle: **Flash** #fire", stripMentions("@channel @sre :red_circle: **Flash** #fire"))
+	assert.Equal(t, ":large_blue_circle: **Alert**", stripMentions(":large_blue_circle: **Alert**"))
+	assert.Empty(t, stripMentions(""))
+}
@@ -0,0 +1,60 @@
+package poster
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/hashtag"
+)
+
+// FooterHashtagsProp is the post prop holding the hashtags shown in the alert attachment
+// footer, so they are kept when the footer is rewritten
+const FooterHashtagsProp = "dataminr_footer_hashtags"
+
+// SetHashtagPlacements replaces where hashtags are shown, keyed by backend ID.
+// Alerts from backends without an entry show the hashtags in the post message.
+func (p *Poster) SetHashtagPlacements(placements map[string]string) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.hashtagPlacements = placements
+}
+
+// getHashtagPlacement returns where the hashtags of a backend's alerts are shown
+func (p *Poster) getHashtagPlacement(backendID string) string {
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	if placement, exists := p.hashtagPlacements[backendID]; exists && placement != "" {
+		return placement
+	}
+	return backend.HashtagPlacementMessage
+}
+
+// postHashtagReply posts the alert's hashtags as a reply in the alert's thread for backends
+// placing hashtags in a reply. Failures are logged since the alert itself has already been posted.
+func (p *Poster) postHashtagReply(alert backend.Alert, alertPost *model.Post) {
+	if alertPost == nil || p.getHashtagPlacement(alert.BackendID) != backend.HashtagPlacementReply {
+		return
+	}
+
+	hashtags := hashtag.Generate(alert, p.getHashtagOptions(alert.BackendID))
+	if hashtags == "" {
+		return
+	}
+
+	// Rate-limited and incident alerts are already replies, so attach to the same thread
+	rootID := alertPost.RootId
+	if rootID == "" {
+		rootID = alertPost.Id
+	}
+
+	if _, appErr := p.api.CreatePost(&model.Post{
+		UserId:    p.botID,
+		ChannelId: alertPost.ChannelId,
+		RootId:    rootID,
+		Message:   hashtags,
+	}); appErr != nil {
+		p.api.LogWarn("Failed to post alert hashtags", "alertId", alert.AlertID, "error", appErr.Error())
+	}
+}
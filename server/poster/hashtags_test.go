@@ -0,0 +1,100 @@
+package poster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestPostAlert_HashtagPlacement(t *testing.T) {
+	alert := backend.Alert{
+		BackendID:   "backend-id",
+		BackendName: "Weather Watch",
+		AlertID:     "alert-123",
+		AlertType:   "Flash",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+		Topics:      []string{"Weather"},
+	}
+
+	postAlert := func(t *testing.T, placement string) []*model.Post {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		var posts []*model.Post
+		api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+			posts = append(posts, args.Get(0).(*model.Post))
+		}).Return(&model.Post{Id: "post-id", ChannelId: "channel-id"}, nil)
+
+		poster := New(api, "bot-user-id")
+		poster.SetHashtagPlacements(map[string]string{"backend-id": placement})
+		require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+		return posts
+	}
+
+	t.Run("message", func(t *testing.T) {
+		posts := postAlert(t, backend.HashtagPlacementMessage)
+
+		require.Len(t, posts, 1)
+		assert.Contains(t, posts[0].Message, "🏷️ #Flash, #Weather")
+		assert.Equal(t, "Weather Watch", posts[0].Attachments()[0].Footer)
+	})
+
+	t.Run("footer", func(t *testing.T) {
+		posts := postAlert(t, backend.HashtagPlacementFooter)
+
+		require.Len(t, posts, 1)
+		assert.NotContains(t, posts[0].Message, "#")
+		assert.Equal(t, "Weather Watch | 🏷️ #Flash, #Weather", posts[0].Attachments()[0].Footer)
+		assert.Equal(t, "🏷️ #Flash, #Weather", posts[0].GetProp(FooterHashtagsProp))
+	})
+
+	t.Run("reply", func(t *testing.T) {
+		posts := postAlert(t, backend.HashtagPlacementReply)
+
+		require.Len(t, posts, 2)
+		assert.NotContains(t, posts[0].Message, "#")
+		assert.Equal(t, "post-id", posts[1].RootId)
+		assert.Equal(t, "🏷️ #Flash, #Weather", posts[1].Message)
+	})
+}
+
+func TestPostAlert_HashtagReplyFailureIsLogged(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == ""
+	})).Return(&model.Post{Id: "post-id"}, nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "post-id"
+	})).Return(nil, model.NewAppError("CreatePost", "app.post.save.app_error", nil, "", 500)).Once()
+	api.On("LogWarn", "Failed to post alert hashtags", "alertId", "alert-b", "error", mock.Anything).Once()
+
+	poster := New(api, "bot-user-id")
+	poster.SetHashtagPlacements(map[string]string{"backend-b": backend.HashtagPlacementReply})
+	require.NoError(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+}
+
+func TestUpdateMatchedFooter_KeepsFooterHashtags(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	post := attachedPost("post-a", "Backend A | #Urgent")
+	post.AddProp(FooterHashtagsProp, "#Urgent")
+	api.On("GetPost", "post-a").Return(post, nil).Once()
+	api.On("UpdatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.Attachments()[0].Footer == "Backend A | also matched: Backend B | #Urgent"
+	})).Return(post, nil).Once()
+
+	poster := New(api, "bot-user-id")
+	poster.updateMatchedFooter("post-a", "backend-a", "Backend A", []string{"Backend B"})
+}
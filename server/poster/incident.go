@@ -0,0 +1,143 @@
+package poster
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/incident"
+)
+
+// IncidentProp is the post prop marking the root post of an incident, holding its alert count.
+// The incident summary is the last attachment of posts with this prop.
+const IncidentProp = "dataminr_incident_alerts"
+
+// IncidentCorrelator groups related alerts posted to a channel into incidents.
+type IncidentCorrelator interface {
+	// Find returns the incident an alert belongs to, or nil if it starts a new one
+	Find(alert backend.Alert, channelID string, settings backend.IncidentSettings) (*incident.Incident, error)
+
+	// Open starts an incident with an alert posted as its root post
+	Open(alert backend.Alert, channelID, rootPostID string) (*incident.Incident, error)
+
+	// AddAlert records an alert posted in the thread of an incident and returns the updated incident
+	AddAlert(alert backend.Alert, channelID, rootPostID string) (*incident.Incident, error)
+}
+
+// SetIncidentCorrelator configures the grouping of related alerts into incident threads.
+// Must be called before alerts are posted.
+func (p *Poster) SetIncidentCorrelator(correlator IncidentCorrelator) {
+	p.incidents = correlator
+}
+
+// SetIncidentSettings replaces the incident grouping settings, keyed by backend ID.
+// Alerts from backends without an entry are not grouped into incidents.
+func (p *Poster) SetIncidentSettings(settings map[string]backend.IncidentSettings) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.incidentSettings = settings
+}
+
+// getIncidentSettings returns the incident grouping settings for a backend, and whether its
+// alerts are grouped into incidents
+func (p *Poster) getIncidentSettings(backendID string) (backend.IncidentSettings, bool) {
+	if p.incidents == nil {
+		return backend.IncidentSettings{}, false
+	}
+
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	settings, exists := p.incidentSettings[backendID]
+	return settings, exists
+}
+
+// incidentLock returns the lock serializing incident grouping in a channel, so related alerts
+// posted at the same time don't start separate incidents
+func (p *Poster) incidentLock(channelID string) *sync.Mutex {
+	lock, _ := p.incidentLocks.LoadOrStore(channelID, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+// createIncidentPost posts an alert as a reply in the thread of the incident it belongs to,
+// or as the root post of a new incident. Grouping failures are logged and the alert is
+// posted on its own.
+func (p *Poster) createIncidentPost(post *model.Post, alert backend.Alert, channelID string, settings backend.IncidentSettings, createRoot func() (*model.Post, error)) (*model.Post, error) {
+	lock := p.incidentLock(channelID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	existing, err := p.incidents.Find(alert, channelID, settings)
+	if err != nil {
+		p.api.LogWarn("Failed to find incident of alert", "alertId", alert.AlertID, "error", err.Error())
+	}
+
+	if existing != nil {
+		post.RootId = existing.RootPostID
+		created, appErr := p.api.CreatePost(post)
+		if appErr == nil {
+			updated, err := p.incidents.AddAlert(alert, channelID, existing.RootPostID)
+			if err != nil {
+				p.api.LogWarn("Failed to add alert to incident", "alertId", alert.AlertID, "rootPostId", existing.RootPostID, "error", err.Error())
+			} else if updated != nil {
+				p.updateIncidentSummary(updated)
+			}
+			return created, nil
+		}
+
+		// The root post may have been deleted; start a new incident instead
+		p.api.LogWarn("Failed to post alert in incident thread", "alertId", alert.AlertID, "rootPostId", existing.RootPostID, "error", appErr.Error())
+		post.RootId = ""
+	}
+
+	created, err := createRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	// Alerts collapsed into the rate limit overflow thread can't be thread roots
+	if created.RootId == "" {
+		if _, err := p.incidents.Open(alert, channelID, created.Id); err != nil {
+			p.api.LogWarn("Failed to start incident", "alertId", alert.AlertID, "error", err.Error())
+		}
+	}
+	return created, nil
+}
+
+// updateIncidentSummary shows the incident's alert count and latest alert on its root post.
+// Failures are logged since the alert itself has already been posted.
+func (p *Poster) updateIncidentSummary(inc *incident.Incident) {
+	root, appErr := p.api.GetPost(inc.RootPostID)
+	if appErr != nil {
+		p.api.LogWarn("Failed to get incident root post", "postId", inc.RootPostID, "error", appErr.Error())
+		return
+	}
+
+	attachments := root.Attachments()
+	if root.GetProp(IncidentProp) != nil && len(attachments) > 0 {
+		attachments = attachments[:len(attachments)-1]
+	}
+	attachments = append(attachments, formatIncidentSummary(inc))
+	model.ParseSlackAttachment(root, attachments)
+	root.AddProp(IncidentProp, inc.AlertCount)
+
+	if _, appErr := p.api.UpdatePost(root); appErr != nil {
+		p.api.LogWarn("Failed to update incident summary", "postId", inc.RootPostID, "error", appErr.Error())
+	}
+}
+
+// formatIncidentSummary builds the summary attachment of an incident root post
+func formatIncidentSummary(inc *incident.Incident) *model.SlackAttachment {
+	return &model.SlackAttachment{
+		Fallback: fmt.Sprintf("Incident: %d related alerts", inc.AlertCount),
+		Text: fmt.Sprintf(":link: **Incident: %d related alerts since %s, see thread**\nLatest (%s, %s): %s",
+			inc.AlertCount,
+			inc.StartedAt.UTC().Format("15:04 MST"),
+			inc.LatestAlertType,
+			inc.UpdatedAt.UTC().Format("15:04 MST"),
+			inc.LatestHeadline),
+	}
+}
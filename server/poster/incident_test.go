@@ -0,0 +1,136 @@
+package poster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/incident"
+)
+
+// fakeIncidents returns a fixed incident for every alert and records the calls made by the poster
+type fakeIncidents struct {
+	found  *incident.Incident
+	opened []string
+	added  []string
+}
+
+func (f *fakeIncidents) Find(backend.Alert, string, backend.IncidentSettings) (*incident.Incident, error) {
+	return f.found, nil
+}
+
+func (f *fakeIncidents) Open(alert backend.Alert, _, rootPostID string) (*incident.Incident, error) {
+	f.opened = append(f.opened, alert.AlertID+":"+rootPostID)
+	return &incident.Incident{RootPostID: rootPostID, AlertCount: 1}, nil
+}
+
+func (f *fakeIncidents) AddAlert(alert backend.Alert, _, rootPostID string) (*incident.Incident, error) {
+	f.added = append(f.added, alert.AlertID+":"+rootPostID)
+	updated := *f.found
+	updated.AlertCount++
+	updated.LatestHeadline = alert.Headline
+	updated.LatestAlertType = alert.AlertType
+	return &updated, nil
+}
+
+func newIncidentTestPoster(api *plugintest.API, incidents *fakeIncidents) *Poster {
+	poster := New(api, "bot-user-id")
+	poster.SetIncidentCorrelator(incidents)
+	poster.SetIncidentSettings(map[string]backend.IncidentSettings{"backend-b": {}})
+	return poster
+}
+
+func TestPostAlert_OpensIncident(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == ""
+	})).Return(&model.Post{Id: "post-b"}, nil).Once()
+
+	incidents := &fakeIncidents{}
+	poster := newIncidentTestPoster(api, incidents)
+
+	require.NoError(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+	assert.Equal(t, []string{"alert-b:post-b"}, incidents.opened)
+}
+
+func TestPostAlert_RepliesInIncidentThread(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	started := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	incidents := &fakeIncidents{found: &incident.Incident{RootPostID: "post-a", StartedAt: started, UpdatedAt: started, AlertCount: 2}}
+
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "post-a"
+	})).Return(&model.Post{Id: "post-b", RootId: "post-a"}, nil).Once()
+
+	// The summary is added to the root post, then replaced as the incident grows
+	root := attachedPost("post-a", "Backend A")
+	api.On("GetPost", "post-a").Return(root, nil)
+	api.On("UpdatePost", mock.Anything).Run(func(args mock.Arguments) {
+		root = args.Get(0).(*model.Post)
+	}).Return(&model.Post{Id: "post-a"}, nil)
+
+	poster := newIncidentTestPoster(api, incidents)
+	require.NoError(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+
+	assert.Equal(t, []string{"alert-b:post-a"}, incidents.added)
+	assert.Empty(t, incidents.opened)
+
+	attachments := root.Attachments()
+	require.Len(t, attachments, 2)
+	assert.Equal(t, "Backend A", attachments[0].Footer)
+	assert.Equal(t, ":link: **Incident: 3 related alerts since 09:00 UTC, see thread**\nLatest (Urgent, 09:00 UTC): Explosion reported downtown", attachments[1].Text)
+	assert.Equal(t, 3, root.GetProp(IncidentProp))
+
+	incidents.found.AlertCount = 3
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "post-c", RootId: "post-a"}, nil).Once()
+	require.NoError(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+
+	attachments = root.Attachments()
+	require.Len(t, attachments, 2)
+	assert.Contains(t, attachments[1].Text, "Incident: 4 related alerts")
+}
+
+func TestPostAlert_IncidentRootDeleted(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	incidents := &fakeIncidents{found: &incident.Incident{RootPostID: "post-a", AlertCount: 1}}
+
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "post-a"
+	})).Return(nil, model.NewAppError("CreatePost", "invalid root", nil, "", 400)).Once()
+	api.On("LogWarn", "Failed to post alert in incident thread", "alertId", "alert-b", "rootPostId", "post-a", "error", mock.Anything).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == ""
+	})).Return(&model.Post{Id: "post-b"}, nil).Once()
+
+	poster := newIncidentTestPoster(api, incidents)
+	require.NoError(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+	assert.Equal(t, []string{"alert-b:post-b"}, incidents.opened)
+}
+
+func TestPostAlert_NoIncidentSettings(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "post-b"}, nil).Once()
+
+	incidents := &fakeIncidents{found: &incident.Incident{RootPostID: "post-a"}}
+	poster := New(api, "bot-user-id")
+	poster.SetIncidentCorrelator(incidents)
+
+	require.NoError(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+	assert.Empty(t, incidents.opened)
+	assert.Empty(t, incidents.added)
+}
@@ -0,0 +1,57 @@
+package poster
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// linkActionID is the ID of the Copy alert link button on alert posts
+const linkActionID = "copy_link"
+
+// SetLinkAction adds a Copy alert link button calling the integration URL to every alert post
+// (empty disables the button). Must be called before alerts are posted.
+func (p *Poster) SetLinkAction(actionURL string) {
+	p.linkActionURL = actionURL
+}
+
+// AlertLinker returns the signed deep link to the post of an alert in a channel
+type AlertLinker func(backendID, alertID, channelID string) (string, error)
+
+// SetAlertLinker shows the deep link created by the linker on every alert post (nil omits
+// the link). Must be called before alerts are posted.
+func (p *Poster) SetAlertLinker(linker AlertLinker) {
+	p.alertLinker = linker
+}
+
+// alertLink returns the deep link to the post of an alert in a channel, or an empty string.
+// Failures are logged and the alert is posted without the link.
+func (p *Poster) alertLink(alert backend.Alert, channelID string) string {
+	if p.alertLinker == nil {
+		return ""
+	}
+
+	link, err := p.alertLinker(alert.BackendID, alert.AlertID, channelID)
+	if err != nil {
+		p.api.LogWarn("Failed to create alert link, posting without it", "alertId", alert.AlertID, "error", err.Error())
+		return ""
+	}
+	return link
+}
+
+// addLinkAction adds the Copy alert link button to an alert attachment
+func (p *Poster) addLinkAction(attachment *model.SlackAttachment, alert backend.Alert) {
+	attachment.Actions = append(attachment.Actions, &model.PostAction{
+		Id:   linkActionID,
+		Name: "Copy alert link",
+		Type: model.PostActionTypeButton,
+		Integration: &model.PostActionIntegration{
+			URL: p.linkActionURL,
+			Context: map[string]any{
+				"backend_id": alert.BackendID,
+				"alert_id":   alert.AlertID,
+				"alert_url":  alert.AlertURL,
+			},
+		},
+	})
+}
@@ -0,0 +1,83 @@
+package poster
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestPostAlert_LinkButton(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	var posts []*model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posts = append(posts, args.Get(0).(*model.Post))
+	}).Return(&model.Post{Id: "post-1"}, nil)
+
+	alert := backend.Alert{BackendID: "backend-1", AlertID: "alert-1", AlertType: "Alert", Headline: "Road closed", AlertURL: "https://app.dataminr.com/alerts/alert-1"}
+
+	poster := New(api, "bot-user-id")
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-1"))
+
+	poster.SetLinkAction("/plugins/test/actions/copy-link")
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-1"))
+
+	require.Len(t, posts, 2)
+	assert.Empty(t, posts[0].Attachments()[0].Actions, "no button without an action URL")
+
+	actions := posts[1].Attachments()[0].Actions
+	require.Len(t, actions, 1)
+	assert.Equal(t, "Copy alert link", actions[0].Name)
+	assert.Equal(t, "/plugins/test/actions/copy-link", actions[0].Integration.URL)
+	assert.Equal(t, map[string]any{
+		"backend_id": "backend-1",
+		"alert_id":   "alert-1",
+		"alert_url":  "https://app.dataminr.com/alerts/alert-1",
+	}, actions[0].Integration.Context)
+}
+
+func TestPostAlert_AlertLink(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	var posts []*model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posts = append(posts, args.Get(0).(*model.Post))
+	}).Return(&model.Post{Id: "post-1"}, nil)
+	api.On("LogWarn", "Failed to create alert link, posting without it", "alertId", "alert-1", "error", "no signing key").Once()
+
+	alert := backend.Alert{BackendID: "backend-1", AlertID: "alert-1", AlertType: "Alert", Headline: "Road closed"}
+
+	poster := New(api, "bot-user-id")
+	poster.SetAlertLinker(func(backendID, alertID, channelID string) (string, error) {
+		return "https://chat.example.com/plugins/test/alert/" + backendID + "/" + alertID + "?channel=" + channelID, nil
+	})
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-1"))
+
+	poster.SetAlertLinker(func(string, string, string) (string, error) {
+		return "", errors.New("no signing key")
+	})
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-2"))
+
+	require.Len(t, posts, 2)
+	var link string
+	for _, field := range posts[0].Attachments()[0].Fields {
+		if field.Title == "Post Link" {
+			link, _ = field.Value.(string)
+		}
+	}
+	assert.Equal(t, "**[Link to this post](https://chat.example.com/plugins/test/alert/backend-1/alert-1?channel=channel-1)**", link)
+
+	for _, field := range posts[1].Attachments()[0].Fields {
+		assert.NotEqual(t, "Post Link", field.Title, "the alert is posted without the link when it can't be created")
+	}
+}
@@ -0,0 +1,253 @@
+package poster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// mediaDownloadTimeout bounds how long downloading a media item may delay an alert post
+const mediaDownloadTimeout = 15 * time.Second
+
+// uploadableMediaTypes lists the media types uploaded to Mattermost, mapped to their file extension
+var uploadableMediaTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// SetMediaUploads replaces the media upload settings, keyed by backend ID.
+// Alerts from backends without an entry link their media by URL.
+func (p *Poster) SetMediaUploads(settings map[string]backend.MediaUploadSettings) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.mediaUploads = settings
+}
+
+// getMediaUpload returns the media upload settings for a backend and whether uploads are enabled
+func (p *Poster) getMediaUpload(backendID string) (backend.MediaUploadSettings, bool) {
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	settings, enabled := p.mediaUploads[backendID]
+	return settings, enabled
+}
+
+// SetMediaBundles replaces the media bundle settings, keyed by backend ID.
+// Alerts from backends without an entry embed only their first media item.
+func (p *Poster) SetMediaBundles(settings map[string]backend.MediaBundleSettings) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.mediaBundles = settings
+}
+
+// getMediaBundle returns the media bundle settings for a backend and whether bundling is enabled
+func (p *Poster) getMediaBundle(backendID string) (backend.MediaBundleSettings, bool) {
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	settings, enabled := p.mediaBundles[backendID]
+	return settings, enabled
+}
+
+// attachPostMedia uploads the alert's media to the post: every bundled media item when the
+// backend bundles media as files, otherwise the first item if media uploads are enabled
+func (p *Poster) attachPostMedia(ctx context.Context, alert backend.Alert, post *model.Post) {
+	if settings, bundled := p.getMediaBundle(alert.BackendID); bundled && settings.ModeOrDefault() == backend.MediaBundleModeFiles {
+		p.attachMediaBundle(ctx, alert, post, settings)
+		return
+	}
+	p.attachMedia(ctx, alert, post)
+}
+
+// attachMedia uploads the alert's first media item and attaches it to the post in place of the
+// hot-linked image. Failures are logged and the post keeps linking the original URL.
+func (p *Poster) attachMedia(ctx context.Context, alert backend.Alert, post *model.Post) {
+	if len(alert.MediaURLs) == 0 {
+		return
+	}
+	settings, enabled := p.getMediaUpload(alert.BackendID)
+	if !enabled {
+		return
+	}
+
+	mediaURL := alert.MediaURLs[0]
+	data, filename, err := p.downloadMedia(ctx, alert.BackendID, mediaURL, settings.MaxSizeBytes())
+	if err != nil {
+		p.api.LogWarn("Failed to download alert media, linking it instead", "alertId", alert.AlertID, "error", err.Error())
+		return
+	}
+
+	fileInfo, appErr := p.api.UploadFile(data, post.ChannelId, filename)
+	if appErr != nil {
+		p.api.LogWarn("Failed to upload alert media, linking it instead", "alertId", alert.AlertID, "error", appErr.Error())
+		return
+	}
+
+	post.FileIds = append(post.FileIds, fileInfo.Id)
+	for _, attachment := range post.Attachments() {
+		if attachment.ImageURL == mediaURL {
+			attachment.ImageURL = ""
+		}
+	}
+}
+
+// downloadMedia fetches a media item through the backend's media client, rejecting hosts that
+// aren't public, files over maxBytes and anything but images.
+// Returns the file contents and a file name for the upload.
+func (p *Poster) downloadMedia(ctx context.Context, backendID, mediaURL string, maxBytes int64) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mediaURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid media URL: %w", err)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, "", fmt.Errorf("invalid media URL: unsupported scheme '%s'", req.URL.Scheme)
+	}
+	if err := p.checkMediaHost(ctx, req.URL.Hostname()); err != nil {
+		return nil, "", err
+	}
+
+	resp, err := p.getMediaClient(backendID).Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download media: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to download media: unexpected status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxBytes {
+		return nil, "", fmt.Errorf("media is too large (%d bytes, limit %d)", resp.ContentLength, maxBytes)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read media: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, "", fmt.Errorf("media is too large (limit %d bytes)", maxBytes)
+	}
+
+	// Trust the content rather than the declared type, which some CDNs get wrong
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	extension, allowed := uploadableMediaTypes[mediaType]
+	if !allowed {
+		return nil, "", fmt.Errorf("unsupported media type '%s'", mediaType)
+	}
+
+	return data, mediaFilename(mediaURL, extension), nil
+}
+
+// mediaFilename derives the upload file name from the media URL, using the
+// extension of the detected media type
+func mediaFilename(mediaURL, extension string) string {
+	name := "media"
+	if parsed, err := url.Parse(mediaURL); err == nil {
+		if base := path.Base(parsed.Path); base != "." && base != "/" {
+			name = strings.TrimSuffix(base, path.Ext(base))
+		}
+	}
+	return name + extension
+}
+
+// attachMediaBundle uploads the bundled media items of an alert and attaches them to the post
+// in place of their image attachments, until the total size limit is reached. The media upload
+// size limit also applies to each file if the backend enables media uploads. Media that can't
+// be uploaded keeps its image attachment linking the original URL. The items are downloaded in
+// parallel within a single download timeout, so a bundle delays the post no longer than one item.
+func (p *Poster) attachMediaBundle(ctx context.Context, alert backend.Alert, post *model.Post, settings backend.MediaBundleSettings) {
+	count := min(settings.Items(), len(alert.MediaURLs))
+	if count == 0 {
+		return
+	}
+
+	limit := settings.MaxTotalSizeBytes()
+	if upload, enabled := p.getMediaUpload(alert.BackendID); enabled {
+		limit = min(limit, upload.MaxSizeBytes())
+	}
+
+	downloads := p.downloadMediaBundle(ctx, alert.BackendID, alert.MediaURLs[:count], limit)
+
+	remaining := settings.MaxTotalSizeBytes()
+	uploaded := make(map[string]bool)
+	for i, mediaURL := range alert.MediaURLs[:count] {
+		download := downloads[i]
+		if download.err == nil && int64(len(download.data)) > remaining {
+			download.err = fmt.Errorf("media is too large (limit %d bytes)", remaining)
+		}
+		if download.err != nil {
+			p.api.LogWarn("Failed to download alert media, linking it instead", "alertId", alert.AlertID, "error", download.err.Error())
+			continue
+		}
+
+		fileInfo, appErr := p.api.UploadFile(download.data, post.ChannelId, download.filename)
+		if appErr != nil {
+			p.api.LogWarn("Failed to upload alert media, linking it instead", "alertId", alert.AlertID, "error", appErr.Error())
+			continue
+		}
+
+		post.FileIds = append(post.FileIds, fileInfo.Id)
+		uploaded[mediaURL] = true
+		remaining -= int64(len(download.data))
+	}
+	if len(uploaded) == 0 {
+		return
+	}
+
+	// The primary attachment keeps its fields; image attachments of uploaded media are dropped
+	attachments := post.Attachments()
+	kept := attachments[:0]
+	for i, attachment := range attachments {
+		if !uploaded[attachment.ImageURL] {
+			kept = append(kept, attachment)
+			continue
+		}
+		if i == 0 {
+			attachment.ImageURL = ""
+			kept = append(kept, attachment)
+		}
+	}
+	model.ParseSlackAttachment(post, kept)
+}
+
+// mediaDownload is the outcome of downloading one media item of a bundle
+type mediaDownload struct {
+	data     []byte
+	filename string
+	err      error
+}
+
+// downloadMediaBundle downloads media items in parallel, each up to maxBytes, all within one
+// download timeout. Returns the outcomes in the order of the URLs.
+func (p *Poster) downloadMediaBundle(ctx context.Context, backendID string, mediaURLs []string, maxBytes int64) []mediaDownload {
+	ctx, cancel := context.WithTimeout(ctx, mediaDownloadTimeout)
+	defer cancel()
+
+	downloads := make([]mediaDownload, len(mediaURLs))
+	var wg sync.WaitGroup
+	for i, mediaURL := range mediaURLs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, filename, err := p.downloadMedia(ctx, backendID, mediaURL, maxBytes)
+			downloads[i] = mediaDownload{data: data, filename: filename, err: err}
+		}()
+	}
+	wg.Wait()
+
+	return downloads
+}
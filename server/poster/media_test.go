@@ -0,0 +1,294 @@
+package poster
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// pngData is the signature of a PNG file, enough for content type detection
+var pngData = []byte("\x89PNG\r\n\x1a\n0000000000")
+
+// largePNGData is a PNG file of 700 KB
+var largePNGData = append(append([]byte{}, pngData...), make([]byte, 700*1024)...)
+
+// newMediaServer serves PNG data at /image.png, a large PNG at /large.png, HTML at /page
+// and 404 elsewhere
+func newMediaServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image.png", "/photos/large":
+			_, _ = w.Write(pngData)
+		case "/large.png", "/other/large.png":
+			_, _ = w.Write(largePNGData)
+		case "/page":
+			_, _ = w.Write([]byte("<html><body>not an image</body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// allowLocalMedia lets a poster download media from the local test server
+func allowLocalMedia(p *Poster) *Poster {
+	p.mediaAddressAllowed = func(net.IP) bool { return true }
+	return p
+}
+
+func TestPostAlert_UploadsMedia(t *testing.T) {
+	server := newMediaServer(t)
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-1",
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+		MediaURLs:   []string{server.URL + "/image.png", server.URL + "/second.png"},
+	}
+
+	api.On("UploadFile", pngData, "channel-id", "image.png").Return(&model.FileInfo{Id: "file-id"}, nil).Once()
+
+	var posted *model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posted = args.Get(0).(*model.Post)
+	}).Return(&model.Post{Id: "post-id"}, nil).Once()
+
+	poster := allowLocalMedia(New(api, "bot-user-id"))
+	poster.SetMediaUploads(map[string]backend.MediaUploadSettings{"backend-1": {}})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	require.NotNil(t, posted)
+	assert.Equal(t, model.StringArray{"file-id"}, posted.FileIds)
+	assert.Empty(t, posted.Attachments()[0].ImageURL, "the uploaded media replaces the hot-linked image")
+}
+
+func TestPostAlert_MediaUploadFallsBackToURL(t *testing.T) {
+	server := newMediaServer(t)
+
+	tests := []struct {
+		name     string
+		mediaURL string
+		settings backend.MediaUploadSettings
+		upload   bool
+		logMsg   string
+	}{
+		{
+			name:     "download fails",
+			mediaURL: server.URL + "/missing.png",
+			logMsg:   "Failed to download alert media, linking it instead",
+		},
+		{
+			name:     "not an image",
+			mediaURL: server.URL + "/page",
+			logMsg:   "Failed to download alert media, linking it instead",
+		},
+		{
+			name:     "upload fails",
+			mediaURL: server.URL + "/image.png",
+			upload:   true,
+			logMsg:   "Failed to upload alert media, linking it instead",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &plugintest.API{}
+			defer api.AssertExpectations(t)
+
+			if tt.upload {
+				api.On("UploadFile", mock.Anything, "channel-id", "image.png").
+					Return(nil, model.NewAppError("UploadFile", "upload_failed", nil, "", http.StatusInternalServerError)).Once()
+			}
+			api.On("LogWarn", tt.logMsg, "alertId", "alert-123", "error", mock.Anything).Once()
+
+			var posted *model.Post
+			api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+				posted = args.Get(0).(*model.Post)
+			}).Return(&model.Post{Id: "post-id"}, nil).Once()
+
+			poster := allowLocalMedia(New(api, "bot-user-id"))
+			poster.SetMediaUploads(map[string]backend.MediaUploadSettings{"backend-1": tt.settings})
+
+			require.NoError(t, poster.PostAlert(context.Background(), backend.Alert{
+				BackendID:   "backend-1",
+				BackendName: "Test Backend",
+				AlertID:     "alert-123",
+				AlertType:   "Alert",
+				Headline:    "Test Alert",
+				EventTime:   time.Now(),
+				MediaURLs:   []string{tt.mediaURL},
+			}, "channel-id"))
+
+			require.NotNil(t, posted)
+			assert.Empty(t, posted.FileIds)
+			assert.Equal(t, tt.mediaURL, posted.Attachments()[0].ImageURL)
+		})
+	}
+}
+
+func TestPostAlert_BundlesMediaAsAttachments(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	var posted *model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posted = args.Get(0).(*model.Post)
+	}).Return(&model.Post{Id: "post-id"}, nil).Once()
+
+	poster := allowLocalMedia(New(api, "bot-user-id"))
+	poster.SetMediaBundles(map[string]backend.MediaBundleSettings{"backend-1": {MaxItems: 3}})
+
+	require.NoError(t, poster.PostAlert(context.Background(), backend.Alert{
+		BackendID:   "backend-1",
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+		MediaURLs:   []string{"https://example.com/1.jpg", "https://example.com/2.jpg", "https://example.com/3.jpg", "https://example.com/4.jpg"},
+	}, "channel-id"))
+
+	require.NotNil(t, posted)
+	attachments := posted.Attachments()
+	require.Len(t, attachments, 3)
+	assert.Equal(t, "https://example.com/1.jpg", attachments[0].ImageURL)
+	assert.Equal(t, "https://example.com/2.jpg", attachments[1].ImageURL)
+	assert.Equal(t, "https://example.com/3.jpg", attachments[2].ImageURL)
+	assert.Empty(t, posted.FileIds)
+}
+
+func TestPostAlert_BundlesMediaAsFiles(t *testing.T) {
+	server := newMediaServer(t)
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("UploadFile", largePNGData, "channel-id", "large.png").Return(&model.FileInfo{Id: "file-1"}, nil).Once()
+	api.On("UploadFile", pngData, "channel-id", "image.png").Return(&model.FileInfo{Id: "file-2"}, nil).Once()
+	api.On("LogWarn", "Failed to download alert media, linking it instead", "alertId", "alert-123", "error", mock.Anything).Twice()
+
+	var posted *model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posted = args.Get(0).(*model.Post)
+	}).Return(&model.Post{Id: "post-id"}, nil).Once()
+
+	poster := allowLocalMedia(New(api, "bot-user-id"))
+	poster.SetMediaBundles(map[string]backend.MediaBundleSettings{
+		"backend-1": {Mode: backend.MediaBundleModeFiles, MaxItems: 4, MaxTotalSizeMB: 1},
+	})
+
+	require.NoError(t, poster.PostAlert(context.Background(), backend.Alert{
+		BackendID:   "backend-1",
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+		MediaURLs: []string{
+			server.URL + "/large.png",
+			server.URL + "/other/large.png",
+			server.URL + "/missing.png",
+			server.URL + "/image.png",
+		},
+	}, "channel-id"))
+
+	// The second large image exceeds the total size limit and the third can't be downloaded,
+	// so both keep linking the original URL
+	require.NotNil(t, posted)
+	assert.Equal(t, model.StringArray{"file-1", "file-2"}, posted.FileIds)
+	attachments := posted.Attachments()
+	require.Len(t, attachments, 3)
+	assert.Empty(t, attachments[0].ImageURL)
+	assert.Equal(t, "### Test Alert", attachments[0].Text)
+	assert.Equal(t, server.URL+"/other/large.png", attachments[1].ImageURL)
+	assert.Equal(t, server.URL+"/missing.png", attachments[2].ImageURL)
+}
+
+func TestPostAlert_BundledMediaDownloadsInParallel(t *testing.T) {
+	// Each request waits until all three arrived, so serial downloads would time out
+	arrived := make(chan struct{}, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		for len(arrived) < cap(arrived) {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+		_, _ = w.Write(pngData)
+	}))
+	defer server.Close()
+
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("UploadFile", pngData, "channel-id", "first.png").Return(&model.FileInfo{Id: "file-1"}, nil).Once()
+	api.On("UploadFile", pngData, "channel-id", "second.png").Return(&model.FileInfo{Id: "file-2"}, nil).Once()
+	api.On("UploadFile", pngData, "channel-id", "third.png").Return(&model.FileInfo{Id: "file-3"}, nil).Once()
+
+	var posted *model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posted = args.Get(0).(*model.Post)
+	}).Return(&model.Post{Id: "post-id"}, nil).Once()
+
+	poster := allowLocalMedia(New(api, "bot-user-id"))
+	poster.SetMediaBundles(map[string]backend.MediaBundleSettings{
+		"backend-1": {Mode: backend.MediaBundleModeFiles, MaxItems: 3},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, poster.PostAlert(ctx, backend.Alert{
+		BackendID: "backend-1",
+		AlertID:   "alert-123",
+		AlertType: "Alert",
+		Headline:  "Test Alert",
+		MediaURLs: []string{server.URL + "/first", server.URL + "/second", server.URL + "/third"},
+	}, "channel-id"))
+
+	// Uploads keep the order of the media items
+	require.NotNil(t, posted)
+	assert.Equal(t, model.StringArray{"file-1", "file-2", "file-3"}, posted.FileIds)
+}
+
+func TestDownloadMedia(t *testing.T) {
+	server := newMediaServer(t)
+	poster := allowLocalMedia(New(&plugintest.API{}, "bot-user-id"))
+
+	t.Run("derives the file name from the URL and content", func(t *testing.T) {
+		data, filename, err := poster.downloadMedia(context.Background(), "backend-1", server.URL+"/photos/large", 1024)
+		require.NoError(t, err)
+		assert.Equal(t, pngData, data)
+		assert.Equal(t, "large.png", filename)
+	})
+
+	t.Run("rejects media over the size limit", func(t *testing.T) {
+		_, _, err := poster.downloadMedia(context.Background(), "backend-1", server.URL+"/image.png", 4)
+		require.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "media is too large"))
+	})
+
+	t.Run("rejects other content", func(t *testing.T) {
+		_, _, err := poster.downloadMedia(context.Background(), "backend-1", server.URL+"/page", 1024)
+		assert.EqualError(t, err, "unsupported media type 'text/html'")
+	})
+}
@@ -0,0 +1,120 @@
+package poster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// mediaDialTimeout bounds connecting to a media host
+const mediaDialTimeout = 10 * time.Second
+
+// maxMediaRedirects is the number of redirects followed when downloading a media item
+const maxMediaRedirects = 10
+
+// errNonPublicAddress is returned for media hosted on an address that isn't publicly routable
+var errNonPublicAddress = errors.New("media host is not a public address")
+
+// sharedAddressSpace is the carrier-grade NAT range, internal to the provider network
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicAddress reports whether an IP address is publicly routable. Media URLs come from the
+// backend API, so they must not reach loopback, private or link-local services on the server's network.
+func publicAddress(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
+
+// SetMediaTransport sets the transport a backend's media is downloaded through, carrying the
+// backend's proxy and TLS settings. A nil transport downloads the backend's media directly.
+func (p *Poster) SetMediaTransport(backendID string, transport *http.Transport) {
+	client := p.newMediaClient(transport)
+
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	if transport == nil {
+		delete(p.mediaClients, backendID)
+		return
+	}
+	if p.mediaClients == nil {
+		p.mediaClients = make(map[string]*http.Client)
+	}
+	p.mediaClients[backendID] = client
+}
+
+// getMediaClient returns the client downloading a backend's media
+func (p *Poster) getMediaClient(backendID string) *http.Client {
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	if client, ok := p.mediaClients[backendID]; ok {
+		return client
+	}
+	return p.httpClient
+}
+
+// newMediaClient builds a client downloading media through a transport, or directly when the
+// transport is nil. Direct connections to addresses that aren't public are refused when dialing,
+// which also covers redirects and host names re-resolving to another address. A proxy makes the
+// connection itself, so there only the host names are checked, before each request and redirect.
+func (p *Poster) newMediaClient(transport *http.Transport) *http.Client {
+	if transport == nil {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+	} else {
+		transport = transport.Clone()
+	}
+
+	if transport.Proxy == nil {
+		dialer := &net.Dialer{Timeout: mediaDialTimeout, Control: p.checkMediaConnection}
+		transport.DialContext = dialer.DialContext
+	}
+
+	return &http.Client{
+		Timeout:   mediaDownloadTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxMediaRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxMediaRedirects)
+			}
+			return p.checkMediaHost(req.Context(), req.URL.Hostname())
+		},
+	}
+}
+
+// checkMediaConnection refuses a connection to an address that isn't public
+func (p *Poster) checkMediaConnection(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !p.mediaAddressAllowed(ip) {
+		return fmt.Errorf("%w: %s", errNonPublicAddress, host)
+	}
+	return nil
+}
+
+// checkMediaHost resolves a media host and rejects it if any of its addresses isn't public
+func (p *Poster) checkMediaHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !p.mediaAddressAllowed(ip) {
+			return fmt.Errorf("%w: %s", errNonPublicAddress, host)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve media host: %w", err)
+	}
+	for _, addr := range addrs {
+		if !p.mediaAddressAllowed(addr.IP) {
+			return fmt.Errorf("%w: %s (%s)", errNonPublicAddress, host, addr.IP)
+		}
+	}
+	return nil
+}
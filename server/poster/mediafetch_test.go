@@ -0,0 +1,86 @@
+package poster
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicAddress(t *testing.T) {
+	for _, addr := range []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"} {
+		assert.True(t, publicAddress(net.ParseIP(addr)), addr)
+	}
+	for _, addr := range []string{
+		"127.0.0.1", "::1", "10.0.0.5", "172.16.3.4", "192.168.1.1", "169.254.169.254",
+		"fe80::1", "fd00::1", "100.64.0.1", "0.0.0.0", "::", "224.0.0.1", "::ffff:127.0.0.1",
+	} {
+		assert.False(t, publicAddress(net.ParseIP(addr)), addr)
+	}
+}
+
+func TestDownloadMedia_RejectsNonPublicHosts(t *testing.T) {
+	server := newMediaServer(t)
+	poster := New(&plugintest.API{}, "bot-user-id")
+
+	t.Run("host checked before the request", func(t *testing.T) {
+		_, _, err := poster.downloadMedia(context.Background(), "backend-1", server.URL+"/image.png", 1024)
+		assert.True(t, errors.Is(err, errNonPublicAddress))
+	})
+
+	t.Run("connection refused when dialing", func(t *testing.T) {
+		// Bypasses the host check, as a host name re-resolving to another address would
+		_, err := poster.httpClient.Get(server.URL + "/image.png")
+		assert.True(t, errors.Is(err, errNonPublicAddress))
+	})
+
+	t.Run("redirect to a private host", func(t *testing.T) {
+		redirect := httptest.NewServer(http.RedirectHandler("http://10.0.0.5/image.png", http.StatusFound))
+		defer redirect.Close()
+
+		allowed := allowLocalMedia(New(&plugintest.API{}, "bot-user-id"))
+		allowed.mediaAddressAllowed = func(ip net.IP) bool { return ip.IsLoopback() }
+
+		_, _, err := allowed.downloadMedia(context.Background(), "backend-1", redirect.URL, 1024)
+		assert.True(t, errors.Is(err, errNonPublicAddress))
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		_, _, err := poster.downloadMedia(context.Background(), "backend-1", "file:///etc/passwd", 1024)
+		assert.EqualError(t, err, "invalid media URL: unsupported scheme 'file'")
+	})
+}
+
+func TestDownloadMedia_UsesBackendTransport(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		_, _ = w.Write(pngData)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	poster := New(&plugintest.API{}, "bot-user-id")
+	poster.SetMediaTransport("backend-1", &http.Transport{Proxy: http.ProxyURL(proxyURL)})
+
+	// The proxy makes the connection, so it may sit on the server's network
+	data, _, err := poster.downloadMedia(context.Background(), "backend-1", "http://93.184.216.34/image.png", 1024)
+	require.NoError(t, err)
+	assert.Equal(t, pngData, data)
+	assert.Equal(t, "http://93.184.216.34/image.png", proxied)
+
+	// Other backends download directly
+	assert.Same(t, poster.httpClient, poster.getMediaClient("backend-2"))
+
+	poster.SetMediaTransport("backend-1", nil)
+	assert.Same(t, poster.httpClient, poster.getMediaClient("backend-1"))
+}
@@ -1,47 +1,555 @@
 package poster
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertposts"
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
 	"github.com/mattermost/mattermost-plugin-dataminr/server/formatter"
 	"github.com/mattermost/mattermost-plugin-dataminr/server/hashtag"
 )
 
+const (
+	// AlertIDProp is the post prop holding the ID of the alert an alert post shows
+	AlertIDProp = "dataminr_alert_id"
+
+	// BackendIDProp is the post prop holding the ID of the backend that reported the alert
+	BackendIDProp = "dataminr_backend_id"
+)
+
+// AlertIndex records posted alerts so they can be searched later and reissued alerts recognized.
+type AlertIndex interface {
+	Add(entry alertindex.Entry) error
+
+	// FindByFingerprint returns the latest alert posted to a channel with a content fingerprint, or nil
+	FindByFingerprint(channelID, fingerprint string) (*alertindex.Entry, error)
+}
+
+// AlertPostStore maps alerts to the post they were posted as.
+type AlertPostStore interface {
+	// Put records the post of an alert unless it already has one, and returns the recorded post
+	Put(record alertposts.Record) (alertposts.Record, error)
+
+	// UpdateContent replaces the recorded content of an alert with an update, returning the
+	// record as it was before, or nil if the alert has no recorded post
+	UpdateContent(alert backend.Alert) (*alertposts.Record, error)
+}
+
 // Poster posts alerts to Mattermost channels.
-// This struct is stateless - it only holds immutable configuration (API and botID).
+// Besides immutable configuration (API and botID), it tracks per-channel posting
+// rates so bursts of alerts can be collapsed into an overflow thread.
 type Poster struct {
-	api   plugin.API
-	botID string
+	api     plugin.API
+	botID   string
+	limiter *rateLimiter
+	now     func() time.Time
+
+	// index records posted alerts for search (nil disables indexing)
+	index AlertIndex
+
+	// alertPosts maps alerts to their posts (nil disables the mapping)
+	alertPosts AlertPostStore
+
+	// onAlertPosted is called after each alert post is created (nil disables it)
+	onAlertPosted func(alert backend.Alert, post *model.Post)
+
+	// contentDedup collapses similar alerts from different backends (nil disables it)
+	contentDedup ContentDeduplicator
+
+	// onCallResolver looks up the on-call user mentioned on critical alerts (nil uses the fallback mention)
+	onCallResolver OnCallResolver
+
+	// mentionScheduler holds back the mentions of unavailable users (nil mentions them right away)
+	mentionScheduler MentionScheduler
+
+	// ackTracker tracks Flash alerts posted with an Acknowledge button calling ackActionURL
+	// (nil disables the button)
+	ackTracker   AckTracker
+	ackActionURL string
+
+	// linkActionURL is called by the Copy alert link button (empty disables the button)
+	linkActionURL string
+
+	// alertLinker creates the deep link shown on alert posts (nil omits the link)
+	alertLinker AlertLinker
+
+	// forwardActionURL is called by the Forward button (empty disables the button)
+	forwardActionURL string
+
+	// incidents groups related alerts into incident threads (nil disables grouping);
+	// incidentLocks serializes grouping per channel
+	incidents     IncidentCorrelator
+	incidentLocks sync.Map
+
+	// workflowLauncher starts playbook runs and creates board cards for alerts (nil disables workflows);
+	// workflowLaunches tracks the launches still in progress
+	workflowLauncher WorkflowLauncher
+	workflowLaunches sync.WaitGroup
+
+	// optionsLock guards the per-backend options below, which can change with the plugin configuration
+	optionsLock       sync.RWMutex
+	formatOptions     formatter.Options
+	hashtagOptions    map[string]hashtag.Options
+	hashtagPlacements map[string]string
+	botIdentities     map[string]backend.BotIdentity
+	mentionRules      map[string]backend.MentionRules
+	contentLimits     map[string]backend.ContentLimits
+	mediaUploads      map[string]backend.MediaUploadSettings
+	mediaBundles      map[string]backend.MediaBundleSettings
+	locales           map[string]string
+	timeDisplays      map[string]backend.TimeDisplaySettings
+	ackSLAs           map[string]backend.AckSLASettings
+	topicStyles       map[string][]backend.TopicStyle
+	incidentSettings  map[string]backend.IncidentSettings
+	workflows         map[string]backend.WorkflowSettings
+	adminChannelID    string
+
+	// httpClient downloads alert media for upload, unless the backend has a client in mediaClients
+	// for its proxy and TLS settings; mediaAddressAllowed decides which hosts media may come from
+	httpClient          *http.Client
+	mediaClients        map[string]*http.Client
+	mediaAddressAllowed func(net.IP) bool
 }
 
 // New creates a new Poster instance.
 func New(api plugin.API, botID string) *Poster {
-	return &Poster{
-		api:   api,
-		botID: botID,
+	p := &Poster{
+		api:                 api,
+		botID:               botID,
+		limiter:             newRateLimiter(),
+		now:                 time.Now,
+		mediaAddressAllowed: publicAddress,
+	}
+	p.httpClient = p.newMediaClient(nil)
+	return p
+}
+
+// SetRateLimit configures the per-channel rate limit.
+// Alerts exceeding the limit are posted as replies to a single summary post.
+func (p *Poster) SetRateLimit(limit RateLimit) {
+	p.limiter.setLimit(limit)
+}
+
+// OnCallResolver looks up the current on-call username from an external on-call service.
+type OnCallResolver interface {
+	Resolve(ctx context.Context, settings backend.OnCallSettings) (string, error)
+}
+
+// SetAlertPostStore configures the store mapping alerts to their posts.
+// Must be called before alerts are posted.
+func (p *Poster) SetAlertPostStore(store AlertPostStore) {
+	p.alertPosts = store
+}
+
+// SetAlertPostedHandler configures a function called after each alert post is created, e.g.
+// to notify the webapp. Must be called before alerts are posted.
+func (p *Poster) SetAlertPostedHandler(handler func(alert backend.Alert, post *model.Post)) {
+	p.onAlertPosted = handler
+}
+
+// SetOnCallResolver configures the resolver for on-call mentions.
+// Must be called before alerts are posted.
+func (p *Poster) SetOnCallResolver(resolver OnCallResolver) {
+	p.onCallResolver = resolver
+}
+
+// SetAlertIndex configures the index that records posted alerts for search.
+// Must be called before alerts are posted.
+func (p *Poster) SetAlertIndex(index AlertIndex) {
+	p.index = index
+}
+
+// SetFormatOptions replaces the formatting options used for new alert posts.
+func (p *Poster) SetFormatOptions(opts formatter.Options) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.formatOptions = opts
+}
+
+// getFormatOptions returns the active formatting options
+func (p *Poster) getFormatOptions() formatter.Options {
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	return p.formatOptions
+}
+
+// SetHashtagOptions replaces the hashtag generation options, keyed by backend ID.
+// Alerts from backends without an entry use the default options.
+func (p *Poster) SetHashtagOptions(opts map[string]hashtag.Options) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.hashtagOptions = opts
+}
+
+// getHashtagOptions returns the hashtag generation options for a backend
+func (p *Poster) getHashtagOptions(backendID string) hashtag.Options {
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	return p.hashtagOptions[backendID]
+}
+
+// SetBotIdentities replaces the bot name and icon overrides, keyed by backend ID.
+// Alerts from backends without an entry are posted with the default bot identity.
+func (p *Poster) SetBotIdentities(identities map[string]backend.BotIdentity) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.botIdentities = identities
+}
+
+// getBotIdentity returns the bot identity override for a backend
+func (p *Poster) getBotIdentity(backendID string) backend.BotIdentity {
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	return p.botIdentities[backendID]
+}
+
+// SetMentionRules replaces the mention rules, keyed by backend ID.
+// Alerts from backends without an entry are posted without mentions.
+func (p *Poster) SetMentionRules(rules map[string]backend.MentionRules) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.mentionRules = rules
+}
+
+// getMentions returns the mention text for an alert based on its backend's rules,
+// including the current on-call user when the rules look one up. Users who are unavailable
+// are left out when the rules hold back their mentions, and returned as the usernames to
+// mention later.
+func (p *Poster) getMentions(ctx context.Context, alert backend.Alert) (string, []string) {
+	p.optionsLock.RLock()
+	rules, exists := p.mentionRules[alert.BackendID]
+	p.optionsLock.RUnlock()
+
+	if !exists {
+		return "", nil
+	}
+
+	mentions := strings.Fields(rules.Mentions(alert.AlertType))
+	if rules.OnCall != nil && rules.OnCall.AppliesTo(alert.AlertType) {
+		mentions = appendMention(mentions, p.resolveOnCall(ctx, alert, *rules.OnCall))
+	}
+
+	var deferred []string
+	if rules.Unavailable != nil && p.mentionScheduler != nil && len(mentions) > 0 {
+		mentions, deferred = p.holdUnavailableMentions(ctx, alert, rules, mentions)
+	}
+	return strings.Join(mentions, " "), deferred
+}
+
+// resolveOnCall returns the mention of the current on-call user, or the fallback mention
+// when the on-call service cannot be reached
+func (p *Poster) resolveOnCall(ctx context.Context, alert backend.Alert, settings backend.OnCallSettings) string {
+	if p.onCallResolver == nil {
+		return settings.FallbackMention()
+	}
+
+	username, err := p.onCallResolver.Resolve(ctx, settings)
+	if err != nil {
+		p.api.LogWarn("Failed to resolve on-call user, using fallback mention",
+			"alertId", alert.AlertID,
+			"backendName", alert.BackendName,
+			"error", err.Error())
+		return settings.FallbackMention()
 	}
+	return "@" + username
+}
+
+// SetContentLimits replaces the content limits, keyed by backend ID.
+// Alerts from backends without an entry use the default limits.
+func (p *Poster) SetContentLimits(limits map[string]backend.ContentLimits) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.contentLimits = limits
+}
+
+// getContentLimits returns the content limits for a backend
+func (p *Poster) getContentLimits(backendID string) backend.ContentLimits {
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	return p.contentLimits[backendID]
+}
+
+// SetLocales replaces the locales of alert post text, keyed by backend ID.
+// Alerts from backends without an entry are formatted in English.
+func (p *Poster) SetLocales(locales map[string]string) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.locales = locales
+}
+
+// getLocale returns the locale of alert post text for a backend
+func (p *Poster) getLocale(backendID string) string {
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	return p.locales[backendID]
+}
+
+// SetTimeDisplays replaces the event time display settings, keyed by backend ID.
+// Alerts from backends without an entry show the event time in its own UTC offset.
+func (p *Poster) SetTimeDisplays(settings map[string]backend.TimeDisplaySettings) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.timeDisplays = settings
+}
+
+// getTimeDisplay returns the event time display settings for a backend
+func (p *Poster) getTimeDisplay(backendID string) backend.TimeDisplaySettings {
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	return p.timeDisplays[backendID]
+}
+
+// SetTopicStyles replaces the topic styles, keyed by backend ID.
+// Alerts from backends without an entry use the alert type emoji and color.
+func (p *Poster) SetTopicStyles(styles map[string][]backend.TopicStyle) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.topicStyles = styles
+}
+
+// getTopicStyles returns the topic styles for a backend (nil if none)
+func (p *Poster) getTopicStyles(backendID string) []backend.TopicStyle {
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	return p.topicStyles[backendID]
 }
 
 // PostAlert posts a formatted alert to a Mattermost channel as a single post.
 //
 // Parameters:
+//   - ctx: Cancels optional work such as media downloads
 //   - alert: The normalized alert to post
 //   - channelID: The target channel ID
 //
 // Returns an error if the post fails.
-func (p *Poster) PostAlert(alert backend.Alert, channelID string) error {
-	// Format alert attachment with all fields
-	attachment := formatter.FormatAlert(alert)
+func (p *Poster) PostAlert(ctx context.Context, alert backend.Alert, channelID string) error {
+	// Collapse the alert into a similar post from another backend instead of posting it again
+	if p.contentDedup != nil {
+		if match := p.contentDedup.ClaimContent(alert, channelID); match != nil {
+			p.api.LogDebug("Collapsed alert into similar post from another backend",
+				"alertId", alert.AlertID,
+				"backendName", alert.BackendName,
+				"matchedBackend", match.BackendName)
+			p.updateMatchedFooter(match.PostID, match.BackendID, match.BackendName, match.MatchedBackends)
+			return nil
+		}
+	}
+
+	created, deferred, err := p.createAlertPost(ctx, alert, channelID)
+	if err != nil {
+		if p.contentDedup != nil {
+			p.contentDedup.ReleaseContent(alert, channelID)
+		}
+		return err
+	}
+
+	p.recordAlert(alert, created)
+	p.deferMentions(alert, created, deferred)
+	if settings, needsAck := p.getAckSLA(alert); needsAck {
+		p.trackAck(alert, created, settings)
+	}
+	p.postHashtagReply(alert, created)
+	p.postRawPayload(alert, created)
+	p.launchWorkflow(alert, created)
+	if p.onAlertPosted != nil {
+		p.onAlertPosted(alert, created)
+	}
+
+	// Similar alerts may have arrived while this one was being posted
+	if p.contentDedup != nil {
+		if matched := p.contentDedup.SetContentPost(alert, channelID, created.Id); len(matched) > 0 {
+			p.updateMatchedFooter(created.Id, alert.BackendID, alert.BackendName, matched)
+		}
+	}
+
+	return nil
+}
+
+// createAlertPost creates the post for an alert, in the thread of its incident when the
+// backend groups alerts into incidents. Returns the post and the usernames whose mentions
+// were held back.
+func (p *Poster) createAlertPost(ctx context.Context, alert backend.Alert, channelID string) (*model.Post, []string, error) {
+	post, deferred := p.buildPost(ctx, alert, channelID)
+
+	// A throttled alert links its media from the overflow thread rather than uploading files for it
+	if !p.throttled(channelID) {
+		p.attachPostMedia(ctx, alert, post)
+	}
+
+	var created *model.Post
+	var err error
+	if settings, grouped := p.getIncidentSettings(alert.BackendID); grouped {
+		created, err = p.createIncidentPost(post, alert, channelID, settings, func() (*model.Post, error) {
+			return p.createRateLimitedPost(post, channelID)
+		})
+	} else {
+		created, err = p.createRateLimitedPost(post, channelID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return created, deferred, nil
+}
+
+// createRateLimitedPost creates a post, applying the per-channel rate limit
+func (p *Poster) createRateLimitedPost(post *model.Post, channelID string) (*model.Post, error) {
+	limit := p.limiter.getLimit()
+	if !limit.Enabled() {
+		created, err := p.api.CreatePost(post)
+		if err != nil {
+			return nil, err
+		}
+		return created, nil
+	}
+
+	// Serialize posting per channel so the overflow thread is created only once
+	state := p.limiter.channel(channelID)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	now := p.now()
+	if state.allow(limit, now) {
+		created, err := p.api.CreatePost(post)
+		if err != nil {
+			return nil, err
+		}
+		state.posted = append(state.posted, now)
+		return created, nil
+	}
+
+	return p.postOverflow(state, post, limit, now)
+}
+
+// throttled reports whether the rate limit sends the next alert for a channel to the overflow thread
+func (p *Poster) throttled(channelID string) bool {
+	limit := p.limiter.getLimit()
+	if !limit.Enabled() {
+		return false
+	}
+
+	state := p.limiter.channel(channelID)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	return !state.allow(limit, p.now())
+}
+
+// recordAlert adds a posted alert to the search index and maps it to its post.
+// Failures are logged since the alert itself has already been posted.
+func (p *Poster) recordAlert(alert backend.Alert, post *model.Post) {
+	if post == nil {
+		return
+	}
+
+	if p.index != nil {
+		if err := p.index.Add(alertindex.NewEntry(alert, post, p.now())); err != nil {
+			p.api.LogWarn("Failed to index posted alert", "alertId", alert.AlertID, "error", err.Error())
+		}
+	}
+
+	if p.alertPosts != nil {
+		if _, err := p.alertPosts.Put(alertposts.NewRecord(alert, post, p.now())); err != nil {
+			p.api.LogWarn("Failed to record the post of an alert", "alertId", alert.AlertID, "error", err.Error())
+		}
+	}
+}
+
+// PostMessage posts a plain text message from the bot to a channel.
+func (p *Poster) PostMessage(message, channelID string) error {
+	if _, err := p.api.CreatePost(&model.Post{
+		UserId:    p.botID,
+		ChannelId: channelID,
+		Message:   message,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// buildPost creates the post for an alert with the mentions, alert type and hashtags in the
+// message. Returns the post and the usernames whose mentions were held back.
+func (p *Poster) buildPost(ctx context.Context, alert backend.Alert, channelID string) (*model.Post, []string) {
+	mentions, deferred := p.getMentions(ctx, alert)
+	return p.formatPost(alert, channelID, mentions, p.alertLink(alert, channelID)), deferred
+}
+
+// BuildTestPost creates the post for an alert as PostAlert formats it, but without mentions,
+// so the formatting can be checked without notifying anyone. Media is linked rather than
+// uploaded, and nothing is recorded for the alert.
+func (p *Poster) BuildTestPost(alert backend.Alert, channelID string) *model.Post {
+	return p.formatPost(alert, channelID, "", "")
+}
+
+// formatPost creates the post for an alert with the mentions, alert type and hashtags in the
+// message, and the deep link to the post if one is given
+func (p *Poster) formatPost(alert backend.Alert, channelID, mentions, postLink string) *model.Post {
+	opts := p.getFormatOptions()
+	opts.PostLink = postLink
+	opts.Limits = p.getContentLimits(alert.BackendID)
+	opts.Locale = p.getLocale(alert.BackendID)
+	opts.TopicStyles = p.getTopicStyles(alert.BackendID)
+	if bundle, bundled := p.getMediaBundle(alert.BackendID); bundled {
+		opts.MediaItems = bundle.Items()
+	}
+
+	timeDisplay := p.getTimeDisplay(alert.BackendID)
+	opts.Timezone, _ = timeDisplay.Location() // Validated with the configuration
+	opts.RelativeTime = timeDisplay.ShowRelative
+	opts.Now = p.now()
+
+	// Format alert attachment with all fields, plus bundled media and a map attachment when needed
+	attachments := []*model.SlackAttachment{formatter.FormatAlert(alert, opts)}
+	if _, needsAck := p.getAckSLA(alert); needsAck {
+		p.addAckAction(attachments[0], alert)
+	}
+	if p.linkActionURL != "" {
+		p.addLinkAction(attachments[0], alert)
+	}
+	if p.forwardActionURL != "" {
+		p.addForwardAction(attachments[0], alert)
+	}
+	attachments = append(attachments, formatter.FormatMediaAttachments(alert, opts)...)
+	if mapAttachment := formatter.FormatMapAttachment(alert, opts); mapAttachment != nil {
+		attachments = append(attachments, mapAttachment)
+	}
 
 	// Generate alert type text and hashtags for searchability
-	alertTypeText := formatter.GetAlertTypeText(alert.AlertType)
-	hashtagText := hashtag.Generate(alert)
+	alertTypeText := formatter.FormatAlertTypeText(alert, opts)
+	hashtagText := hashtag.Generate(alert, p.getHashtagOptions(alert.BackendID))
+	hashtagPlacement := p.getHashtagPlacement(alert.BackendID)
 
-	// Create post with alert type and hashtags in message
+	// Create post with mentions, alert type and hashtags in message, unless the hashtags
+	// are shown in the footer or posted as a reply
 	message := alertTypeText
-	if hashtagText != "" {
+	if mentions != "" {
+		message = mentions + " " + message
+	}
+	if hashtagText != "" && hashtagPlacement == backend.HashtagPlacementMessage {
 		message += " " + hashtagText
 	}
 
@@ -49,17 +557,108 @@ func (p *Poster) PostAlert(alert backend.Alert, channelID string) error {
 		UserId:    p.botID,
 		ChannelId: channelID,
 		Type:      model.PostTypeSlackAttachment,
-		Message:   message, // Alert type + hashtags
+		Message:   message, // Mentions + alert type + hashtags
 		Props:     model.StringInterface{},
 	}
 
-	// Add attachment to post props
-	model.ParseSlackAttachment(post, []*model.SlackAttachment{attachment})
+	// Note reissued alerts and footer hashtags in the footer
+	if repostOf := p.findRepostOf(alert, channelID); repostOf != "" {
+		post.AddProp(RepostOfProp, repostOf)
+	}
+	if hashtagText != "" && hashtagPlacement == backend.HashtagPlacementFooter {
+		post.AddProp(FooterHashtagsProp, hashtagText)
+	}
+	attachments[0].Footer = p.decorateFooter(attachments[0].Footer, post, alert.BackendID)
+
+	// Add attachments to post props, and the alert's identity so hooks can recognize alert posts
+	model.ParseSlackAttachment(post, attachments)
+	post.AddProp(AlertIDProp, alert.AlertID)
+	post.AddProp(BackendIDProp, alert.BackendID)
+
+	applyBotIdentity(post, p.getBotIdentity(alert.BackendID))
+
+	if trimmed := fitPostSize(post, formatter.TrimOrder(opts.Locale)); len(trimmed) > 0 {
+		p.api.LogWarn("Trimmed oversized alert post to fit the post size limit",
+			"alertId", alert.AlertID,
+			"backendName", alert.BackendName,
+			"trimmed", strings.Join(trimmed, ", "))
+	}
+
+	return post
+}
+
+// applyBotIdentity sets the post-level username and icon override props for a backend identity.
+// Mattermost only renders the overrides on posts marked as coming from a webhook.
+func applyBotIdentity(post *model.Post, identity backend.BotIdentity) {
+	if identity == (backend.BotIdentity{}) {
+		return
+	}
+
+	post.AddProp(model.PostPropsFromWebhook, "true")
+	if identity.DisplayName != "" {
+		post.AddProp(model.PostPropsOverrideUsername, identity.DisplayName)
+	}
+	if identity.IconURL != "" {
+		post.AddProp(model.PostPropsOverrideIconURL, identity.IconURL)
+	}
+	if emoji := identity.EmojiName(); emoji != "" {
+		post.AddProp(model.PostPropsOverrideIconEmoji, emoji)
+	}
+}
+
+// postOverflow posts a rate-limited alert as a reply in the channel's overflow thread,
+// creating the summary post if needed and keeping its suppressed count up to date.
+// Returns the created reply. The caller must hold the channel state lock.
+func (p *Poster) postOverflow(state *channelState, post *model.Post, limit RateLimit, now time.Time) (*model.Post, error) {
+	summaryID := state.activeSummary(limit, now)
+	if summaryID == "" {
+		summary, err := p.api.CreatePost(&model.Post{
+			UserId:    p.botID,
+			ChannelId: post.ChannelId,
+			Message:   formatSummaryMessage(0, limit),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		state.summaryPostID = summary.Id
+		state.summaryCreated = now
+		state.suppressed = 0
+		summaryID = summary.Id
+	}
 
-	// Post to channel
-	_, err := p.api.CreatePost(post)
+	post.RootId = summaryID
+	created, err := p.api.CreatePost(post)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	state.suppressed++
+	p.updateSummary(state, limit)
+	return created, nil
+}
+
+// updateSummary refreshes the suppressed count on the overflow summary post.
+// Failures are logged since the alert itself has already been posted.
+func (p *Poster) updateSummary(state *channelState, limit RateLimit) {
+	summary, appErr := p.api.GetPost(state.summaryPostID)
+	if appErr != nil {
+		p.api.LogWarn("Failed to get rate limit summary post", "postId", state.summaryPostID, "error", appErr.Error())
+		return
+	}
+
+	summary.Message = formatSummaryMessage(state.suppressed, limit)
+	if _, appErr := p.api.UpdatePost(summary); appErr != nil {
+		p.api.LogWarn("Failed to update rate limit summary post", "postId", state.summaryPostID, "error", appErr.Error())
+	}
+}
+
+// formatSummaryMessage builds the overflow summary text
+func formatSummaryMessage(suppressed int, limit RateLimit) string {
+	noun := "alerts"
+	if suppressed == 1 {
+		noun = "alert"
+	}
+	return fmt.Sprintf(":warning: **%d additional %s suppressed, see thread** (limit: %d alerts per %s)",
+		suppressed, noun, limit.MaxAlerts, limit.Window)
 }
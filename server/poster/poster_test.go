@@ -1,6 +1,9 @@
 package poster
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,7 +13,11 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertposts"
 	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/formatter"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/hashtag"
 )
 
 func TestPostAlert_Success(t *testing.T) {
@@ -43,13 +50,14 @@ func TestPostAlert_Success(t *testing.T) {
 		attachments, ok := post.Props["attachments"]
 		assert.True(t, ok, "Post props should contain attachments")
 		assert.NotNil(t, attachments, "Post attachments should not be nil")
+		assert.Equal(t, "alert-123", post.GetProp(AlertIDProp), "Post should identify the alert")
 
 		return true
 	})).Return(&model.Post{Id: postID}, nil).Once()
 
 	// Create poster and call PostAlert
 	poster := New(api, botID)
-	err := poster.PostAlert(alert, channelID)
+	err := poster.PostAlert(context.Background(), alert, channelID)
 
 	// Verify no error
 	require.NoError(t, err)
@@ -81,7 +89,7 @@ func TestPostAlert_PostError(t *testing.T) {
 
 	// Create poster and call PostAlert
 	poster := New(api, botID)
-	err := poster.PostAlert(alert, channelID)
+	err := poster.PostAlert(context.Background(), alert, channelID)
 
 	// Verify error is returned
 	require.Error(t, err)
@@ -115,7 +123,7 @@ func TestPostAlert_ChannelNotFound(t *testing.T) {
 
 	// Create poster and call PostAlert
 	poster := New(api, botID)
-	err := poster.PostAlert(alert, channelID)
+	err := poster.PostAlert(context.Background(), alert, channelID)
 
 	// Verify error is returned
 	require.Error(t, err)
@@ -149,7 +157,7 @@ func TestPostAlert_PermissionError(t *testing.T) {
 
 	// Create poster and call PostAlert
 	poster := New(api, botID)
-	err := poster.PostAlert(alert, channelID)
+	err := poster.PostAlert(context.Background(), alert, channelID)
 
 	// Verify error is returned
 	require.Error(t, err)
@@ -207,8 +215,481 @@ func TestPostAlert_WithRichAlert(t *testing.T) {
 
 	// Create poster and call PostAlert
 	poster := New(api, botID)
-	err := poster.PostAlert(alert, channelID)
+	err := poster.PostAlert(context.Background(), alert, channelID)
 
 	// Verify no error
 	require.NoError(t, err)
 }
+
+func TestPostAlert_UsesBackendHashtagOptions(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-fr",
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+		Location:    &backend.Location{Address: "Berlin, Germany"},
+	}
+
+	var messages []string
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		messages = append(messages, args.Get(0).(*model.Post).Message)
+	}).Return(&model.Post{Id: "post-id"}, nil).Twice()
+
+	poster := New(api, "bot-user-id")
+	poster.SetHashtagOptions(map[string]hashtag.Options{
+		"backend-fr": {Locale: "fr"},
+	})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	alert.BackendID = "backend-other"
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	require.Len(t, messages, 2)
+	assert.Contains(t, messages[0], "#Allemagne")
+	assert.Contains(t, messages[1], "#Germany")
+}
+
+func TestPostAlert_UsesBackendBotIdentity(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-weather",
+		BackendName: "Weather Watch",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+	}
+
+	var posts []*model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posts = append(posts, args.Get(0).(*model.Post))
+	}).Return(&model.Post{Id: "post-id"}, nil).Twice()
+
+	poster := New(api, "bot-user-id")
+	poster.SetBotIdentities(map[string]backend.BotIdentity{
+		"backend-weather": {DisplayName: "Weather Watch", IconEmoji: ":cloud:"},
+	})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	alert.BackendID = "backend-other"
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	require.Len(t, posts, 2)
+	assert.Equal(t, "bot-user-id", posts[0].UserId)
+	assert.Equal(t, "true", posts[0].GetProp(model.PostPropsFromWebhook))
+	assert.Equal(t, "Weather Watch", posts[0].GetProp(model.PostPropsOverrideUsername))
+	assert.Equal(t, "cloud", posts[0].GetProp(model.PostPropsOverrideIconEmoji))
+	assert.Nil(t, posts[0].GetProp(model.PostPropsOverrideIconURL))
+
+	assert.Nil(t, posts[1].GetProp(model.PostPropsFromWebhook))
+	assert.Nil(t, posts[1].GetProp(model.PostPropsOverrideUsername))
+}
+
+func TestPostAlert_PrependsMentions(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-security",
+		BackendName: "Corporate Security",
+		AlertID:     "alert-123",
+		AlertType:   "Flash",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+	}
+
+	var messages []string
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		messages = append(messages, args.Get(0).(*model.Post).Message)
+	}).Return(&model.Post{Id: "post-id"}, nil).Times(3)
+
+	poster := New(api, "bot-user-id")
+	poster.SetMentionRules(map[string]backend.MentionRules{
+		"backend-security": {Flash: []string{"@channel", "security-team"}},
+	})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	alert.AlertType = "Alert"
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	alert.AlertType = "Flash"
+	alert.BackendID = "backend-other"
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	require.Len(t, messages, 3)
+	assert.True(t, strings.HasPrefix(messages[0], "@channel @security-team "+formatter.FormatAlertTypeText(backend.Alert{AlertType: "Flash"}, formatter.Options{})))
+	assert.NotContains(t, messages[1], "@")
+	assert.NotContains(t, messages[2], "@channel")
+}
+
+// fakeOnCallResolver returns a fixed username or error
+type fakeOnCallResolver struct {
+	username string
+	err      error
+}
+
+func (f *fakeOnCallResolver) Resolve(_ context.Context, _ backend.OnCallSettings) (string, error) {
+	return f.username, f.err
+}
+
+func TestPostAlert_MentionsOnCallUser(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-security",
+		BackendName: "Corporate Security",
+		AlertID:     "alert-123",
+		AlertType:   "Flash",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+	}
+
+	var messages []string
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		messages = append(messages, args.Get(0).(*model.Post).Message)
+	}).Return(&model.Post{Id: "post-id"}, nil).Times(3)
+	api.On("LogWarn", "Failed to resolve on-call user, using fallback mention",
+		"alertId", "alert-123", "backendName", "Corporate Security", "error", "service unavailable").Once()
+
+	resolver := &fakeOnCallResolver{username: "jane.doe"}
+	poster := New(api, "bot-user-id")
+	poster.SetOnCallResolver(resolver)
+	poster.SetMentionRules(map[string]backend.MentionRules{
+		"backend-security": {
+			Flash:  []string{"@security-team"},
+			OnCall: &backend.OnCallSettings{URL: "https://oncall.example.com", Fallback: "@sre"},
+		},
+	})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	// Falls back to the static mention when the on-call service fails
+	resolver.err = errors.New("service unavailable")
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	// Only Flash alerts mention the on-call user by default
+	alert.AlertType = "Urgent"
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	require.Len(t, messages, 3)
+	assert.True(t, strings.HasPrefix(messages[0], "@security-team @jane.doe "+formatter.FormatAlertTypeText(backend.Alert{AlertType: "Flash"}, formatter.Options{})))
+	assert.True(t, strings.HasPrefix(messages[1], "@security-team @sre "+formatter.FormatAlertTypeText(backend.Alert{AlertType: "Flash"}, formatter.Options{})))
+	assert.NotContains(t, messages[2], "@")
+}
+
+// recordingIndex is an AlertIndex that keeps the added entries in memory
+type recordingIndex struct {
+	entries []alertindex.Entry
+}
+
+func (r *recordingIndex) Add(entry alertindex.Entry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *recordingIndex) FindByFingerprint(channelID, fingerprint string) (*alertindex.Entry, error) {
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if r.entries[i].ChannelID == channelID && r.entries[i].Fingerprint == fingerprint {
+			return &r.entries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func TestPostAlert_RecordsInIndex(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-id",
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Flash",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+	}
+
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "post-id", ChannelId: "channel-id"}, nil).Once()
+
+	index := &recordingIndex{}
+	poster := New(api, "bot-user-id")
+	poster.SetAlertIndex(index)
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	require.Len(t, index.entries, 1)
+	assert.Equal(t, "alert-123", index.entries[0].AlertID)
+	assert.Equal(t, "post-id", index.entries[0].PostID)
+	assert.Equal(t, "channel-id", index.entries[0].ChannelID)
+}
+
+// recordingAlertPosts is an AlertPostStore that keeps the recorded posts in memory
+type recordingAlertPosts struct {
+	records []alertposts.Record
+}
+
+func (r *recordingAlertPosts) Put(record alertposts.Record) (alertposts.Record, error) {
+	r.records = append(r.records, record)
+	return record, nil
+}
+
+func (r *recordingAlertPosts) UpdateContent(alert backend.Alert) (*alertposts.Record, error) {
+	for i := range r.records {
+		if r.records[i].BackendID == alert.BackendID && r.records[i].AlertID == alert.AlertID {
+			previous := r.records[i]
+			r.records[i].Headline = alert.Headline
+			r.records[i].Location = alert.Location
+			r.records[i].Topics = alert.Topics
+			return &previous, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestPostAlert_RecordsAlertPost(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-id",
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Flash",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+	}
+
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "post-id", ChannelId: "channel-id"}, nil).Once()
+
+	store := &recordingAlertPosts{}
+	poster := New(api, "bot-user-id")
+	poster.SetAlertPostStore(store)
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	require.Len(t, store.records, 1)
+	assert.Equal(t, "backend-id", store.records[0].BackendID)
+	assert.Equal(t, "alert-123", store.records[0].AlertID)
+	assert.Equal(t, "post-id", store.records[0].PostID)
+	assert.Equal(t, "channel-id", store.records[0].ChannelID)
+}
+
+func TestPostAlert_CallsAlertPostedHandler(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID: "backend-id",
+		AlertID:   "alert-123",
+		AlertType: "Flash",
+		Headline:  "Test Alert",
+		EventTime: time.Now(),
+	}
+
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "post-id", ChannelId: "channel-id"}, nil).Once()
+	api.On("CreatePost", mock.Anything).Return(nil, model.NewAppError("CreatePost", "app.error", nil, "", 500)).Once()
+
+	var posted []string
+	poster := New(api, "bot-user-id")
+	poster.SetAlertPostedHandler(func(alert backend.Alert, post *model.Post) {
+		posted = append(posted, alert.AlertID+"/"+post.Id)
+	})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+	require.Error(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	assert.Equal(t, []string{"alert-123/post-id"}, posted, "Only created posts are reported")
+}
+
+func TestPostMessage(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("CreatePost", &model.Post{
+		UserId:    "bot-user-id",
+		ChannelId: "channel-id",
+		Message:   "summary",
+	}).Return(&model.Post{Id: "post-id"}, nil).Once()
+
+	poster := New(api, "bot-user-id")
+	require.NoError(t, poster.PostMessage("summary", "channel-id"))
+}
+
+func TestPostAlert_UsesBackendContentLimits(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-short",
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+		SourceText:  strings.Repeat("a", 600),
+	}
+
+	var sourceTexts []string
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		for _, field := range args.Get(0).(*model.Post).Attachments()[0].Fields {
+			if field.Title == "Original Source Text" {
+				sourceTexts = append(sourceTexts, field.Value.(string))
+			}
+		}
+	}).Return(&model.Post{Id: "post-id"}, nil).Twice()
+
+	poster := New(api, "bot-user-id")
+	poster.SetContentLimits(map[string]backend.ContentLimits{
+		"backend-short": {MaxSourceTextChars: 100},
+	})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	alert.BackendID = "backend-other"
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	require.Len(t, sourceTexts, 2)
+	assert.Len(t, sourceTexts[0], 103)
+	assert.Len(t, sourceTexts[1], 503)
+}
+
+func TestPostAlert_UsesBackendTimeDisplay(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-tokyo",
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		EventTime:   time.Date(2024, 3, 7, 14, 5, 0, 0, time.UTC),
+	}
+
+	var eventTimes []string
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		for _, field := range args.Get(0).(*model.Post).Attachments()[0].Fields {
+			if field.Title == "Event Time" {
+				eventTimes = append(eventTimes, field.Value.(string))
+			}
+		}
+	}).Return(&model.Post{Id: "post-id"}, nil).Twice()
+
+	poster := New(api, "bot-user-id")
+	poster.now = func() time.Time { return alert.EventTime.Add(90 * time.Minute) }
+	poster.SetTimeDisplays(map[string]backend.TimeDisplaySettings{
+		"backend-tokyo": {Timezone: "Asia/Tokyo", ShowRelative: true},
+	})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	alert.BackendID = "backend-other"
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	assert.Equal(t, []string{"2024-03-07 23:05:00 JST · 1 h ago", "2024-03-07 14:05:00 UTC"}, eventTimes)
+}
+
+func TestPostAlert_UsesBackendTopicStyles(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-cyber",
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		EventTime:   time.Date(2024, 3, 7, 14, 5, 0, 0, time.UTC),
+		Topics:      []string{"Cyber"},
+	}
+
+	var messages, colors []string
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		post := args.Get(0).(*model.Post)
+		messages = append(messages, post.Message)
+		colors = append(colors, post.Attachments()[0].Color)
+	}).Return(&model.Post{Id: "post-id"}, nil).Twice()
+
+	poster := New(api, "bot-user-id")
+	poster.SetTopicStyles(map[string][]backend.TopicStyle{
+		"backend-cyber": {{Match: "Cyber", Emoji: ":computer:", Color: "#6A0DAD"}},
+	})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	alert.BackendID = "backend-other"
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	require.Len(t, messages, 2)
+	assert.True(t, strings.HasPrefix(messages[0], ":computer: **ALERT**"))
+	assert.True(t, strings.HasPrefix(messages[1], formatter.EmojiAlert+" **ALERT**"))
+	assert.Equal(t, []string{"#6A0DAD", formatter.ColorAlert}, colors)
+}
+
+func TestPostAlert_UsesBackendLocale(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-fr",
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		EventTime:   time.Date(2024, 3, 7, 14, 5, 9, 0, time.UTC),
+	}
+
+	var fields []string
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		for _, field := range args.Get(0).(*model.Post).Attachments()[0].Fields {
+			fields = append(fields, field.Title+": "+field.Value.(string))
+		}
+	}).Return(&model.Post{Id: "post-id"}, nil).Twice()
+
+	poster := New(api, "bot-user-id")
+	poster.SetLocales(map[string]string{"backend-fr": "fr"})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	alert.BackendID = "backend-other"
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	assert.Equal(t, []string{"Heure de l'événement: 07/03/2024 14:05:09 UTC", "Event Time: 2024-03-07 14:05:09 UTC"}, fields)
+}
+
+func TestBuildTestPost(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID:   "backend-security",
+		BackendName: "Corporate Security",
+		AlertID:     "selftest-1",
+		AlertType:   "Flash",
+		Headline:    "[Self-test] Test Alert",
+		EventTime:   time.Now(),
+	}
+
+	poster := New(api, "bot-user-id")
+	poster.SetMentionRules(map[string]backend.MentionRules{
+		"backend-security": {Flash: []string{"@channel"}},
+	})
+
+	post := poster.BuildTestPost(alert, "channel-id")
+	assert.Equal(t, "bot-user-id", post.UserId)
+	assert.Equal(t, "channel-id", post.ChannelId)
+	assert.True(t, strings.HasPrefix(post.Message, formatter.FormatAlertTypeText(backend.Alert{AlertType: "Flash"}, formatter.Options{})), "test posts mention no one")
+	require.Len(t, post.Attachments(), 1)
+	assert.Equal(t, "selftest-1", post.GetProp(AlertIDProp))
+}
@@ -0,0 +1,151 @@
+package poster
+
+import (
+	"slices"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	// truncatedMarker is appended to post content trimmed to fit Mattermost's size limits
+	truncatedMarker = "… _(truncated)_"
+
+	// maxMessageRunes is the longest post message Mattermost accepts
+	maxMessageRunes = model.PostMessageMaxRunesV2
+
+	// maxPropsRunes is the largest serialized post props Mattermost accepts from a user post
+	maxPropsRunes = model.PostPropsMaxUserRunes
+
+	// minTrimmedFieldRunes is the least content worth keeping of a trimmed field; fields that
+	// would be shorter are removed instead
+	minTrimmedFieldRunes = 80
+)
+
+// propsSize estimates the size of a post's props as Mattermost measures it when creating the post
+func propsSize(post *model.Post) int {
+	return utf8.RuneCountInString(model.StringInterfaceToJSON(post.GetProps()))
+}
+
+// fitPostSize trims an alert post so CreatePost doesn't reject it for exceeding Mattermost's
+// message and props size limits. Attachment fields are trimmed by priority, starting with the
+// field titled first in trimOrder; a field is truncated if enough of it can be kept, otherwise
+// it is removed. If the post is still too large, the longest attachment text or field is
+// shortened, so the headline and short fields like links and times are kept whenever possible.
+// Returns a description of each trimmed part, or nil if the post fits.
+func fitPostSize(post *model.Post, trimOrder []string) []string {
+	var trimmed []string
+
+	if utf8.RuneCountInString(post.Message) > maxMessageRunes {
+		post.Message = truncateRunes(post.Message, maxMessageRunes)
+		trimmed = append(trimmed, "message (truncated)")
+	}
+
+	attachments := post.Attachments()
+	for _, title := range trimOrder {
+		if propsSize(post) <= maxPropsRunes {
+			return trimmed
+		}
+		if outcome := trimField(post, attachments, title); outcome != "" {
+			trimmed = append(trimmed, title+" ("+outcome+")")
+		}
+	}
+
+	for {
+		excess := propsSize(post) - maxPropsRunes
+		if excess <= 0 {
+			break
+		}
+
+		name, value, set := longestAttachmentValue(attachments)
+		length := utf8.RuneCountInString(value)
+		if set == nil || length <= utf8.RuneCountInString(truncatedMarker) {
+			// Nothing left to trim; let CreatePost report the error
+			break
+		}
+
+		set(truncateRunes(value, max(length-excess, 0)))
+		if part := name + " (truncated)"; !slices.Contains(trimmed, part) {
+			trimmed = append(trimmed, part)
+		}
+	}
+
+	return trimmed
+}
+
+// trimField shortens the field with a title until the post fits, removing the field instead
+// if less than minTrimmedFieldRunes of it would be kept. Returns "truncated" or "removed",
+// or an empty string if there is no such field.
+func trimField(post *model.Post, attachments []*model.SlackAttachment, title string) string {
+	attachment, index := findField(attachments, title)
+	if attachment == nil {
+		return ""
+	}
+
+	field := attachment.Fields[index]
+	for {
+		// Escaping in the serialized props may exceed the estimate, so check again after trimming
+		excess := propsSize(post) - maxPropsRunes
+		if excess <= 0 {
+			return "truncated"
+		}
+
+		value, _ := field.Value.(string)
+		keep := utf8.RuneCountInString(value) - excess
+		if keep-utf8.RuneCountInString(truncatedMarker) < minTrimmedFieldRunes {
+			attachment.Fields = append(attachment.Fields[:index], attachment.Fields[index+1:]...)
+			return "removed"
+		}
+		field.Value = truncateRunes(value, keep)
+	}
+}
+
+// findField returns the attachment holding the string field with a title, and the index of
+// the field (nil when there is none)
+func findField(attachments []*model.SlackAttachment, title string) (*model.SlackAttachment, int) {
+	for _, attachment := range attachments {
+		for i, field := range attachment.Fields {
+			if _, ok := field.Value.(string); ok && field.Title == title {
+				return attachment, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// longestAttachmentValue returns the longest attachment text or string field value along
+// with its name, for logging, and a function replacing it (nil when there are no values)
+func longestAttachmentValue(attachments []*model.SlackAttachment) (string, string, func(string)) {
+	name := ""
+	longest := ""
+	var set func(string)
+
+	for _, attachment := range attachments {
+		if len(attachment.Text) > len(longest) {
+			name = "attachment text"
+			longest = attachment.Text
+			set = func(value string) { attachment.Text = value }
+		}
+
+		for _, field := range attachment.Fields {
+			if value, ok := field.Value.(string); ok && len(value) > len(longest) {
+				name = field.Title
+				longest = value
+				set = func(value string) { field.Value = value }
+			}
+		}
+	}
+
+	return name, longest, set
+}
+
+// truncateRunes shortens text to at most maxRunes runes including the truncated marker
+func truncateRunes(text string, maxRunes int) string {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+
+	keep := max(maxRunes-utf8.RuneCountInString(truncatedMarker), 0)
+	return string(runes[:keep]) + truncatedMarker
+}
@@ -0,0 +1,126 @@
+package poster
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/formatter"
+)
+
+func TestFitPostSize(t *testing.T) {
+	t.Run("leaves posts within the limits unchanged", func(t *testing.T) {
+		post := &model.Post{Message: "message", Props: model.StringInterface{}}
+		model.ParseSlackAttachment(post, []*model.SlackAttachment{{Text: "text"}})
+
+		assert.Nil(t, fitPostSize(post, formatter.TrimOrder("")))
+		assert.Equal(t, "message", post.Message)
+		assert.Equal(t, "text", post.Attachments()[0].Text)
+	})
+
+	t.Run("truncates an oversized message", func(t *testing.T) {
+		post := &model.Post{Message: strings.Repeat("a", maxMessageRunes+10)}
+
+		assert.Equal(t, []string{"message (truncated)"}, fitPostSize(post, nil))
+		assert.Equal(t, maxMessageRunes, utf8.RuneCountInString(post.Message))
+		assert.True(t, strings.HasSuffix(post.Message, truncatedMarker))
+	})
+
+	t.Run("trims the longest attachment values first", func(t *testing.T) {
+		post := &model.Post{Props: model.StringInterface{}}
+		model.ParseSlackAttachment(post, []*model.SlackAttachment{{
+			Text: "### Headline",
+			Fields: []*model.SlackAttachmentField{
+				{Title: "Event Time", Value: "2024-01-01 00:00:00 UTC"},
+				{Title: "Additional Context", Value: strings.Repeat("b", maxPropsRunes/2)},
+				{Title: "Original Source Text", Value: strings.Repeat("c", maxPropsRunes)},
+			},
+		}})
+
+		assert.Equal(t, []string{"Original Source Text (truncated)"}, fitPostSize(post, nil))
+		assert.LessOrEqual(t, propsSize(post), maxPropsRunes)
+
+		attachment := post.Attachments()[0]
+		assert.Equal(t, "### Headline", attachment.Text)
+		assert.Equal(t, "2024-01-01 00:00:00 UTC", attachment.Fields[0].Value)
+		assert.Equal(t, strings.Repeat("b", maxPropsRunes/2), attachment.Fields[1].Value)
+		assert.True(t, strings.HasSuffix(attachment.Fields[2].Value.(string), truncatedMarker))
+	})
+
+	t.Run("trims the lowest priority fields first", func(t *testing.T) {
+		post := &model.Post{Props: model.StringInterface{}}
+		model.ParseSlackAttachment(post, []*model.SlackAttachment{{
+			Text: "### Headline",
+			Fields: []*model.SlackAttachmentField{
+				{Title: "Event Time", Value: "2024-01-01 00:00:00 UTC"},
+				{Title: "Additional Context", Value: strings.Repeat("b", maxPropsRunes/2)},
+				{Title: "Topics", Value: strings.Repeat("c", maxPropsRunes/2)},
+				{Title: "Alert Lists", Value: strings.Repeat("d", 50)},
+			},
+		}})
+
+		trimmed := fitPostSize(post, formatter.TrimOrder(""))
+		assert.Equal(t, []string{"Alert Lists (removed)", "Topics (truncated)"}, trimmed)
+		assert.LessOrEqual(t, propsSize(post), maxPropsRunes)
+
+		attachment := post.Attachments()[0]
+		require.Len(t, attachment.Fields, 3)
+		assert.Equal(t, strings.Repeat("b", maxPropsRunes/2), attachment.Fields[1].Value)
+		assert.Equal(t, "Topics", attachment.Fields[2].Title)
+		assert.True(t, strings.HasSuffix(attachment.Fields[2].Value.(string), truncatedMarker))
+	})
+
+	t.Run("removes fields too large to keep a useful part of", func(t *testing.T) {
+		post := &model.Post{Props: model.StringInterface{}}
+		context := &model.SlackAttachmentField{Title: "Additional Context"}
+		model.ParseSlackAttachment(post, []*model.SlackAttachment{{
+			Text:   "### Headline",
+			Fields: []*model.SlackAttachmentField{context},
+		}})
+
+		// Leave room for less of the media links than is worth keeping
+		context.Value = strings.Repeat("b", maxPropsRunes-propsSize(post)-minTrimmedFieldRunes)
+		attachment := post.Attachments()[0]
+		attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{Title: "Additional Media", Value: strings.Repeat("m", 500)})
+
+		assert.Equal(t, []string{"Additional Media (removed)"}, fitPostSize(post, formatter.TrimOrder("")))
+		require.Len(t, attachment.Fields, 1)
+		assert.Equal(t, "Additional Context", attachment.Fields[0].Title)
+	})
+}
+
+func TestPostAlert_TrimsOversizedAlert(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		SubHeadline: strings.Repeat("x", maxPropsRunes+1000),
+		EventTime:   time.Now(),
+	}
+
+	var posted *model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posted = args.Get(0).(*model.Post)
+	}).Return(&model.Post{Id: "post-id"}, nil).Once()
+	api.On("LogWarn", "Trimmed oversized alert post to fit the post size limit",
+		"alertId", "alert-123", "backendName", "Test Backend", "trimmed", "Additional Context (truncated)").Once()
+
+	require.NoError(t, New(api, "bot-user-id").PostAlert(context.Background(), alert, "channel-id"))
+
+	require.NotNil(t, posted)
+	assert.LessOrEqual(t, utf8.RuneCountInString(model.StringInterfaceToJSON(posted.GetProps())), maxPropsRunes)
+	assert.Equal(t, "### Test Alert", posted.Attachments()[0].Text)
+}
@@ -0,0 +1,106 @@
+package poster
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimit defines how many alerts may be posted to a single channel within a time window.
+// A MaxAlerts value of zero disables rate limiting.
+type RateLimit struct {
+	MaxAlerts int
+	Window    time.Duration
+}
+
+// Enabled reports whether the rate limit is active
+func (r RateLimit) Enabled() bool {
+	return r.MaxAlerts > 0 && r.Window > 0
+}
+
+// channelState tracks recent posts and the overflow thread for a single channel.
+// The embedded mutex serializes posting to the channel so ordering is preserved
+// and only one overflow summary is created per window.
+type channelState struct {
+	mu sync.Mutex
+
+	// posted holds the times of alerts posted as root posts within the window
+	posted []time.Time
+
+	// summaryPostID is the root post of the current overflow thread
+	summaryPostID string
+
+	// summaryCreated is when the current overflow thread was started
+	summaryCreated time.Time
+
+	// suppressed is the number of alerts added to the current overflow thread
+	suppressed int
+}
+
+// rateLimiter tracks per-channel posting rates
+type rateLimiter struct {
+	mu       sync.Mutex
+	limit    RateLimit
+	channels map[string]*channelState
+}
+
+// newRateLimiter creates a new rate limiter with rate limiting disabled
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		channels: make(map[string]*channelState),
+	}
+}
+
+// setLimit replaces the active rate limit
+func (r *rateLimiter) setLimit(limit RateLimit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.limit = limit
+}
+
+// getLimit returns the active rate limit
+func (r *rateLimiter) getLimit() RateLimit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.limit
+}
+
+// channel returns the state for a channel, creating it if needed
+func (r *rateLimiter) channel(channelID string) *channelState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, exists := r.channels[channelID]
+	if !exists {
+		state = &channelState{}
+		r.channels[channelID] = state
+	}
+
+	return state
+}
+
+// allow prunes posts that fell out of the window and reports whether another root post
+// fits within the limit. The caller must hold the channel state lock.
+func (c *channelState) allow(limit RateLimit, now time.Time) bool {
+	cutoff := now.Add(-limit.Window)
+
+	kept := c.posted[:0]
+	for _, t := range c.posted {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	c.posted = kept
+
+	return len(c.posted) < limit.MaxAlerts
+}
+
+// activeSummary returns the overflow thread root if it was started within the window.
+// The caller must hold the channel state lock.
+func (c *channelState) activeSummary(limit RateLimit, now time.Time) string {
+	if c.summaryPostID == "" || now.Sub(c.summaryCreated) >= limit.Window {
+		return ""
+	}
+	return c.summaryPostID
+}
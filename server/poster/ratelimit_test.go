@@ -0,0 +1,149 @@
+package poster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestRateLimit_Enabled(t *testing.T) {
+	assert.False(t, RateLimit{}.Enabled())
+	assert.False(t, RateLimit{MaxAlerts: 20}.Enabled())
+	assert.False(t, RateLimit{Window: time.Minute}.Enabled())
+	assert.True(t, RateLimit{MaxAlerts: 20, Window: 5 * time.Minute}.Enabled())
+}
+
+func TestChannelState_Allow(t *testing.T) {
+	limit := RateLimit{MaxAlerts: 2, Window: time.Minute}
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	state := &channelState{}
+	assert.True(t, state.allow(limit, now))
+	state.posted = append(state.posted, now)
+	assert.True(t, state.allow(limit, now))
+	state.posted = append(state.posted, now)
+	assert.False(t, state.allow(limit, now), "Limit reached within the window")
+
+	later := now.Add(time.Minute)
+	assert.True(t, state.allow(limit, later), "Posts outside the window are pruned")
+	assert.Empty(t, state.posted)
+}
+
+func TestPostAlert_RateLimited(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	botID := "bot-user-id"
+	channelID := "channel-id"
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	alert := backend.Alert{
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		EventTime:   now,
+	}
+
+	// First alert fits within the limit and is posted as a root post
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.Type == model.PostTypeSlackAttachment && post.RootId == ""
+	})).Return(&model.Post{Id: "root-post"}, nil).Once()
+
+	// Overflow creates a single summary post
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.Type == "" && post.RootId == ""
+	})).Return(&model.Post{Id: "summary-post"}, nil).Once()
+
+	// Overflow alerts are posted as replies to the summary
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.Type == model.PostTypeSlackAttachment && post.RootId == "summary-post"
+	})).Return(&model.Post{Id: "reply-post"}, nil).Twice()
+
+	var summaryMessages []string
+	api.On("GetPost", "summary-post").Return(&model.Post{Id: "summary-post"}, nil).Twice()
+	api.On("UpdatePost", mock.MatchedBy(func(post *model.Post) bool {
+		summaryMessages = append(summaryMessages, post.Message)
+		return post.Id == "summary-post"
+	})).Return(&model.Post{Id: "summary-post"}, nil).Twice()
+
+	poster := New(api, botID)
+	poster.now = func() time.Time { return now }
+	poster.SetRateLimit(RateLimit{MaxAlerts: 1, Window: 5 * time.Minute})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, poster.PostAlert(context.Background(), alert, channelID))
+	}
+
+	require.Len(t, summaryMessages, 2)
+	assert.Contains(t, summaryMessages[0], "1 additional alert suppressed, see thread")
+	assert.Contains(t, summaryMessages[1], "2 additional alerts suppressed, see thread")
+}
+
+func TestPostAlert_RateLimitIsPerChannel(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{AlertID: "alert-123", AlertType: "Alert", Headline: "Test Alert"}
+
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == ""
+	})).Return(&model.Post{Id: "post-id"}, nil).Twice()
+
+	poster := New(api, "bot-user-id")
+	poster.SetRateLimit(RateLimit{MaxAlerts: 1, Window: 5 * time.Minute})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-1"))
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-2"))
+}
+
+func TestPostAlert_RateLimitedAlertLinksMedia(t *testing.T) {
+	server := newMediaServer(t)
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	alert := backend.Alert{
+		BackendID: "backend-1",
+		AlertID:   "alert-123",
+		AlertType: "Alert",
+		Headline:  "Test Alert",
+		MediaURLs: []string{server.URL + "/image.png"},
+	}
+
+	// Only the alert within the limit uploads its media
+	api.On("UploadFile", pngData, "channel-id", "image.png").Return(&model.FileInfo{Id: "file-id"}, nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.Type == model.PostTypeSlackAttachment && post.RootId == "" && len(post.FileIds) == 1
+	})).Return(&model.Post{Id: "root-post"}, nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.Type == "" && post.RootId == ""
+	})).Return(&model.Post{Id: "summary-post"}, nil).Once()
+
+	var reply *model.Post
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "summary-post"
+	})).Run(func(args mock.Arguments) {
+		reply = args.Get(0).(*model.Post)
+	}).Return(&model.Post{Id: "reply-post"}, nil).Once()
+	api.On("GetPost", "summary-post").Return(&model.Post{Id: "summary-post"}, nil).Once()
+	api.On("UpdatePost", mock.Anything).Return(&model.Post{Id: "summary-post"}, nil).Once()
+
+	poster := allowLocalMedia(New(api, "bot-user-id"))
+	poster.SetRateLimit(RateLimit{MaxAlerts: 1, Window: 5 * time.Minute})
+	poster.SetMediaUploads(map[string]backend.MediaUploadSettings{"backend-1": {}})
+
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	require.NotNil(t, reply)
+	assert.Empty(t, reply.FileIds)
+	assert.Equal(t, server.URL+"/image.png", reply.Attachments()[0].ImageURL)
+}
@@ -0,0 +1,67 @@
+package poster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// maxRawPayloadLength is the longest raw payload included in a reply, well below the post size limit
+const maxRawPayloadLength = 12000
+
+// postRawPayload posts the alert's original JSON as a reply in the alert's thread.
+// Failures are logged since the alert itself has already been posted.
+func (p *Poster) postRawPayload(alert backend.Alert, alertPost *model.Post) {
+	if alert.RawPayload == "" || alertPost == nil {
+		return
+	}
+
+	// Rate-limited alerts are already replies, so attach to the same thread
+	rootID := alertPost.RootId
+	if rootID == "" {
+		rootID = alertPost.Id
+	}
+
+	if _, appErr := p.api.CreatePost(&model.Post{
+		UserId:    p.botID,
+		ChannelId: alertPost.ChannelId,
+		RootId:    rootID,
+		Message:   formatRawPayload(alert.RawPayload),
+	}); appErr != nil {
+		p.api.LogWarn("Failed to post raw alert payload", "alertId", alert.AlertID, "error", appErr.Error())
+	}
+}
+
+// formatRawPayload pretty-prints the payload in a JSON code block, truncating oversized payloads
+func formatRawPayload(raw string) string {
+	payload := raw
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(raw), "", "  "); err == nil {
+		payload = indented.String()
+	}
+
+	truncated := false
+	if len(payload) > maxRawPayloadLength {
+		payload = payload[:maxRawPayloadLength]
+		// Don't cut a multi-byte character in half
+		for !utf8.ValidString(payload) {
+			payload = payload[:len(payload)-1]
+		}
+		truncated = true
+	}
+
+	// Keep the payload from closing the code block early
+	payload = strings.ReplaceAll(payload, "```", "` ` `")
+
+	message := "**Raw alert payload**\n```json\n" + payload + "\n```"
+	if truncated {
+		message += fmt.Sprintf("\n_Payload truncated to %d bytes._", maxRawPayloadLength)
+	}
+	return message
+}
@@ -0,0 +1,96 @@
+package poster
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestFormatRawPayload(t *testing.T) {
+	t.Run("pretty prints JSON", func(t *testing.T) {
+		message := formatRawPayload(`{"alertId":"123","alertType":{"name":"Flash"}}`)
+		assert.Equal(t, "**Raw alert payload**\n```json\n{\n  \"alertId\": \"123\",\n  \"alertType\": {\n    \"name\": \"Flash\"\n  }\n}\n```", message)
+	})
+
+	t.Run("keeps invalid JSON as is", func(t *testing.T) {
+		assert.Contains(t, formatRawPayload("not json"), "```json\nnot json\n```")
+	})
+
+	t.Run("escapes code fences", func(t *testing.T) {
+		message := formatRawPayload(`{"text":"` + "```" + `"}`)
+		assert.Equal(t, 2, strings.Count(message, "```"))
+	})
+
+	t.Run("truncates large payloads on a character boundary", func(t *testing.T) {
+		message := formatRawPayload(`"` + strings.Repeat("é", maxRawPayloadLength) + `"`)
+		assert.Contains(t, message, "_Payload truncated to 12000 bytes._")
+		assert.Less(t, len(message), maxRawPayloadLength+200)
+		assert.True(t, utf8.ValidString(message))
+		assert.Contains(t, message, "é\n```")
+	})
+}
+
+func TestPostAlert_AttachesRawPayload(t *testing.T) {
+	alert := backend.Alert{
+		BackendID:   "backend-id",
+		BackendName: "Test Backend",
+		AlertID:     "alert-123",
+		AlertType:   "Alert",
+		Headline:    "Test Alert",
+		EventTime:   time.Now(),
+		RawPayload:  `{"alertId":"alert-123"}`,
+	}
+
+	t.Run("reply under the alert post", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		var reply *model.Post
+		api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool { return post.RootId == "" })).
+			Return(&model.Post{Id: "alert-post-id", ChannelId: "channel-id"}, nil).Once()
+		api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool { return post.RootId != "" })).Run(func(args mock.Arguments) {
+			reply = args.Get(0).(*model.Post)
+		}).Return(&model.Post{Id: "reply-id"}, nil).Once()
+
+		require.NoError(t, New(api, "bot-user-id").PostAlert(context.Background(), alert, "channel-id"))
+
+		require.NotNil(t, reply)
+		assert.Equal(t, "alert-post-id", reply.RootId)
+		assert.Equal(t, "channel-id", reply.ChannelId)
+		assert.Equal(t, "bot-user-id", reply.UserId)
+		assert.Contains(t, reply.Message, `"alertId": "alert-123"`)
+	})
+
+	t.Run("reply failure does not fail the alert", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool { return post.RootId == "" })).
+			Return(&model.Post{Id: "alert-post-id", ChannelId: "channel-id"}, nil).Once()
+		api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool { return post.RootId != "" })).
+			Return(nil, &model.AppError{Message: "too large"}).Once()
+		api.On("LogWarn", "Failed to post raw alert payload", "alertId", "alert-123", "error", mock.Anything).Once()
+
+		require.NoError(t, New(api, "bot-user-id").PostAlert(context.Background(), alert, "channel-id"))
+	})
+
+	t.Run("no reply without payload", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "alert-post-id", ChannelId: "channel-id"}, nil).Once()
+
+		withoutPayload := alert
+		withoutPayload.RawPayload = ""
+		require.NoError(t, New(api, "bot-user-id").PostAlert(context.Background(), withoutPayload, "channel-id"))
+	})
+}
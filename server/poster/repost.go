@@ -0,0 +1,66 @@
+package poster
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/formatter"
+)
+
+// RepostOfProp is the post prop holding the post of an earlier alert with the same content,
+// posted to the channel under another alert ID
+const RepostOfProp = "dataminr_repost_of"
+
+// footerSeparator separates the parts of an alert attachment footer
+const footerSeparator = " | "
+
+// findRepostOf returns the post of an earlier alert in the channel with the same content as
+// the alert but another alert ID, or an empty string. Lookup failures are logged and the
+// alert is posted as new.
+func (p *Poster) findRepostOf(alert backend.Alert, channelID string) string {
+	if p.index == nil {
+		return ""
+	}
+
+	original, err := p.index.FindByFingerprint(channelID, alert.Fingerprint())
+	if err != nil {
+		p.api.LogWarn("Failed to look up earlier alerts with the same content", "alertId", alert.AlertID, "error", err.Error())
+		return ""
+	}
+	if original == nil || original.AlertID == alert.AlertID {
+		return ""
+	}
+
+	p.api.LogDebug("Alert matches the content of an earlier alert",
+		"alertId", alert.AlertID,
+		"backendName", alert.BackendName,
+		"originalAlertId", original.AlertID)
+	return original.PostID
+}
+
+// decorateFooter appends the repost note and footer hashtags recorded on an alert post to
+// its attachment footer, so they are kept when the footer is rewritten
+func (p *Poster) decorateFooter(footer string, post *model.Post, backendID string) string {
+	parts := []string{}
+	if footer != "" {
+		parts = append(parts, footer)
+	}
+	if repostOf, _ := post.GetProp(RepostOfProp).(string); repostOf != "" {
+		parts = append(parts, formatter.FormatRepostNote(p.siteURL()+"/_redirect/pl/"+repostOf, p.getLocale(backendID)))
+	}
+	if hashtags, _ := post.GetProp(FooterHashtagsProp).(string); hashtags != "" {
+		parts = append(parts, hashtags)
+	}
+	return strings.Join(parts, footerSeparator)
+}
+
+// siteURL returns the configured site URL without a trailing slash
+func (p *Poster) siteURL() string {
+	config := p.api.GetConfig()
+	if config == nil || config.ServiceSettings.SiteURL == nil {
+		return ""
+	}
+	return strings.TrimSuffix(*config.ServiceSettings.SiteURL, "/")
+}
@@ -0,0 +1,77 @@
+package poster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestPostAlert_MarksRepost(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	siteURL := "https://chat.example.com/"
+	api.On("GetConfig").Return(&model.Config{ServiceSettings: model.ServiceSettings{SiteURL: &siteURL}})
+	api.On("LogDebug", "Alert matches the content of an earlier alert",
+		"alertId", "alert-2", "backendName", "Weather Watch", "originalAlertId", "alert-1").Once()
+
+	var posts []*model.Post
+	postIDs := []string{"post-1", "post-2", "post-3"}
+	api.On("CreatePost", mock.Anything).Return(func(post *model.Post) (*model.Post, *model.AppError) {
+		posts = append(posts, post)
+		return &model.Post{Id: postIDs[len(posts)-1], ChannelId: post.ChannelId}, nil
+	})
+
+	poster := New(api, "bot-user-id")
+	poster.SetAlertIndex(&recordingIndex{})
+
+	alert := backend.Alert{
+		BackendID:   "backend-id",
+		BackendName: "Weather Watch",
+		AlertID:     "alert-1",
+		AlertType:   "Urgent",
+		Headline:    "Flooding reported downtown",
+		EventTime:   time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+
+	// Reissued under a new alert ID
+	reissued := alert
+	reissued.AlertID = "alert-2"
+	require.NoError(t, poster.PostAlert(context.Background(), reissued, "channel-id"))
+
+	// The same alert in another channel is not a repost there
+	require.NoError(t, poster.PostAlert(context.Background(), reissued, "other-channel-id"))
+
+	require.Len(t, posts, 3)
+	assert.Equal(t, "Weather Watch", posts[0].Attachments()[0].Footer)
+	assert.Equal(t, "Weather Watch | possible repost of https://chat.example.com/_redirect/pl/post-1", posts[1].Attachments()[0].Footer)
+	assert.Equal(t, "post-1", posts[1].GetProp(RepostOfProp))
+	assert.Equal(t, "Weather Watch", posts[2].Attachments()[0].Footer)
+}
+
+func TestUpdateMatchedFooter_KeepsRepostNote(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	siteURL := "https://chat.example.com"
+	api.On("GetConfig").Return(&model.Config{ServiceSettings: model.ServiceSettings{SiteURL: &siteURL}})
+
+	post := attachedPost("post-a", "Backend A | possible repost of https://chat.example.com/_redirect/pl/post-0")
+	post.AddProp(RepostOfProp, "post-0")
+	api.On("GetPost", "post-a").Return(post, nil).Once()
+	api.On("UpdatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.Attachments()[0].Footer == "Backend A | also matched: Backend B | possible repost of https://chat.example.com/_redirect/pl/post-0"
+	})).Return(post, nil).Once()
+
+	poster := New(api, "bot-user-id")
+	poster.updateMatchedFooter("post-a", "backend-a", "Backend A", []string{"Backend B"})
+}
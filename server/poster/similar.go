@@ -0,0 +1,72 @@
+package poster
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/formatter"
+)
+
+// ContentMatch describes an earlier post for a similar alert from another backend.
+type ContentMatch struct {
+	// PostID is the ID of the earlier post (empty while it is still being created)
+	PostID string
+
+	// BackendID is the ID of the backend that posted the earlier alert
+	BackendID string
+
+	// BackendName is the name of the backend that posted the earlier alert
+	BackendName string
+
+	// MatchedBackends lists the other backends whose alerts were collapsed into the post
+	MatchedBackends []string
+}
+
+// ContentDeduplicator collapses near-identical alerts from different backends that are
+// destined for the same channel into a single post.
+type ContentDeduplicator interface {
+	// ClaimContent returns the earlier post of a similar alert from another backend, or nil
+	// if the alert is new, in which case its content is reserved until SetContentPost or
+	// ReleaseContent is called.
+	ClaimContent(alert backend.Alert, channelID string) *ContentMatch
+
+	// SetContentPost records the post created for a claimed alert and returns the backends
+	// whose similar alerts were collapsed into it while it was being posted.
+	SetContentPost(alert backend.Alert, channelID, postID string) []string
+
+	// ReleaseContent drops the reservation of an alert that could not be posted.
+	ReleaseContent(alert backend.Alert, channelID string)
+}
+
+// SetContentDeduplicator configures cross-backend collapsing of similar alerts.
+// Must be called before alerts are posted.
+func (p *Poster) SetContentDeduplicator(dedup ContentDeduplicator) {
+	p.contentDedup = dedup
+}
+
+// updateMatchedFooter lists the backends whose similar alerts were collapsed into a post
+// in the alert attachment footer. Failures are logged since the alert is already posted.
+func (p *Poster) updateMatchedFooter(postID, backendID, backendName string, matched []string) {
+	if postID == "" {
+		// The earlier post is still being created; its footer is updated once it exists
+		return
+	}
+
+	post, appErr := p.api.GetPost(postID)
+	if appErr != nil {
+		p.api.LogWarn("Failed to get post to list matching backends", "postId", postID, "error", appErr.Error())
+		return
+	}
+
+	attachments := post.Attachments()
+	if len(attachments) == 0 {
+		return
+	}
+
+	attachments[0].Footer = p.decorateFooter(formatter.FormatMatchedFooter(backendName, matched, p.getLocale(backendID)), post, backendID)
+	model.ParseSlackAttachment(post, attachments)
+
+	if _, appErr := p.api.UpdatePost(post); appErr != nil {
+		p.api.LogWarn("Failed to list matching backends on post", "postId", postID, "error", appErr.Error())
+	}
+}
@@ -0,0 +1,134 @@
+package poster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// fakeContentDedup returns a fixed match and records the calls made by the poster
+type fakeContentDedup struct {
+	match    *ContentMatch
+	matched  []string
+	postIDs  []string
+	released int
+}
+
+func (f *fakeContentDedup) ClaimContent(backend.Alert, string) *ContentMatch {
+	return f.match
+}
+
+func (f *fakeContentDedup) SetContentPost(_ backend.Alert, _, postID string) []string {
+	f.postIDs = append(f.postIDs, postID)
+	return f.matched
+}
+
+func (f *fakeContentDedup) ReleaseContent(backend.Alert, string) {
+	f.released++
+}
+
+func similarTestAlert() backend.Alert {
+	return backend.Alert{
+		BackendID:   "backend-b",
+		BackendName: "Backend B",
+		AlertID:     "alert-b",
+		AlertType:   "Urgent",
+		Headline:    "Explosion reported downtown",
+		EventTime:   time.Now(),
+	}
+}
+
+func attachedPost(postID, footer string) *model.Post {
+	post := &model.Post{Id: postID, Props: model.StringInterface{}}
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{{Text: "### Explosion reported downtown", Footer: footer}})
+	return post
+}
+
+func TestPostAlert_CollapsesSimilarAlert(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+	api.On("GetPost", "post-a").Return(attachedPost("post-a", "Backend A"), nil).Once()
+	api.On("UpdatePost", mock.MatchedBy(func(post *model.Post) bool {
+		attachments := post.Attachments()
+		return len(attachments) == 1 && attachments[0].Footer == "Backend A | also matched: Backend B"
+	})).Return(&model.Post{Id: "post-a"}, nil).Once()
+
+	dedup := &fakeContentDedup{match: &ContentMatch{PostID: "post-a", BackendName: "Backend A", MatchedBackends: []string{"Backend B"}}}
+	poster := New(api, "bot-user-id")
+	poster.SetContentDeduplicator(dedup)
+
+	require.NoError(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+}
+
+func TestPostAlert_CollapsesIntoPendingPost(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+
+	// The earlier post is still being created, so there is nothing to update yet
+	dedup := &fakeContentDedup{match: &ContentMatch{BackendName: "Backend A", MatchedBackends: []string{"Backend B"}}}
+	poster := New(api, "bot-user-id")
+	poster.SetContentDeduplicator(dedup)
+
+	require.NoError(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+}
+
+func TestPostAlert_RecordsContentPost(t *testing.T) {
+	t.Run("new alert", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "post-b"}, nil).Once()
+
+		dedup := &fakeContentDedup{}
+		poster := New(api, "bot-user-id")
+		poster.SetContentDeduplicator(dedup)
+
+		require.NoError(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+		assert.Equal(t, []string{"post-b"}, dedup.postIDs)
+	})
+
+	t.Run("similar alert arrived while posting", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "post-b"}, nil).Once()
+		api.On("GetPost", "post-b").Return(attachedPost("post-b", "Backend B"), nil).Once()
+		api.On("UpdatePost", mock.MatchedBy(func(post *model.Post) bool {
+			return post.Attachments()[0].Footer == "Backend B | also matched: Backend C"
+		})).Return(&model.Post{Id: "post-b"}, nil).Once()
+
+		dedup := &fakeContentDedup{matched: []string{"Backend C"}}
+		poster := New(api, "bot-user-id")
+		poster.SetContentDeduplicator(dedup)
+
+		require.NoError(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+	})
+
+	t.Run("failed post releases the content", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("CreatePost", mock.Anything).Return(nil, model.NewAppError("CreatePost", "error", nil, "", 500)).Once()
+
+		dedup := &fakeContentDedup{}
+		poster := New(api, "bot-user-id")
+		poster.SetContentDeduplicator(dedup)
+
+		require.Error(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+		assert.Equal(t, 1, dedup.released)
+		assert.Empty(t, dedup.postIDs)
+	})
+}
@@ -0,0 +1,50 @@
+package poster
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/triage"
+)
+
+// triageFooterSeparator separates the triage state from the rest of an alert post footer
+const triageFooterSeparator = " | Triage: "
+
+// triageColors are the attachment colors of triaged alert posts
+var triageColors = map[triage.State]string{
+	triage.StateInvestigating: "#FFBC1F",
+	triage.StateResolved:      "#3DB887",
+	triage.StateFalsePositive: "#8A8A8A",
+}
+
+// MarkTriaged shows the triage state of an alert post in its attachment color and footer.
+// A later triage state replaces the earlier one.
+func (p *Poster) MarkTriaged(postID string, state triage.State, username string, at time.Time) error {
+	post, appErr := p.api.GetPost(postID)
+	if appErr != nil {
+		return fmt.Errorf("failed to get alert post: %w", appErr)
+	}
+
+	attachments := post.Attachments()
+	if len(attachments) == 0 {
+		return fmt.Errorf("alert post %s has no attachment", postID)
+	}
+
+	// Only the alert attachment is marked, not the map attachment following it
+	attachment := attachments[0]
+	if color, ok := triageColors[state]; ok {
+		attachment.Color = color
+	}
+	footer, _, _ := strings.Cut(attachment.Footer, triageFooterSeparator)
+	attachment.Footer = fmt.Sprintf("%s%s%s by @%s at %s", footer, triageFooterSeparator, state.Label(), username, at.UTC().Format("2006-01-02 15:04 MST"))
+	model.ParseSlackAttachment(post, attachments)
+
+	if _, appErr := p.api.UpdatePost(post); appErr != nil {
+		return fmt.Errorf("failed to update alert post: %w", appErr)
+	}
+
+	return nil
+}
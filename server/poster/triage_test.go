@@ -0,0 +1,57 @@
+package poster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/triage"
+)
+
+func TestMarkTriaged(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	post := &model.Post{Id: "post-1", ChannelId: "channel-1"}
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{
+		{Title: "Tornado warning", Color: "#D24B4E", Footer: "Weather Watch"},
+		{Title: "Map", Color: "#D24B4E"},
+	})
+
+	var updated *model.Post
+	api.On("GetPost", "post-1").Return(post, nil)
+	api.On("UpdatePost", mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(0).(*model.Post)
+	}).Return(post, nil)
+
+	poster := New(api, "bot-user-id")
+	at := time.Date(2026, 10, 16, 9, 5, 0, 0, time.UTC)
+
+	require.NoError(t, poster.MarkTriaged("post-1", triage.StateInvestigating, "jane", at))
+	require.NotNil(t, updated)
+	attachments := updated.Attachments()
+	require.Len(t, attachments, 2)
+	assert.Equal(t, "#FFBC1F", attachments[0].Color)
+	assert.Equal(t, "Weather Watch | Triage: Investigating by @jane at 2026-10-16 09:05 UTC", attachments[0].Footer)
+	assert.Equal(t, "#D24B4E", attachments[1].Color)
+
+	// A later state replaces the earlier one
+	require.NoError(t, poster.MarkTriaged("post-1", triage.StateResolved, "john", at.Add(time.Hour)))
+	attachments = updated.Attachments()
+	assert.Equal(t, "#3DB887", attachments[0].Color)
+	assert.Equal(t, "Weather Watch | Triage: Resolved by @john at 2026-10-16 10:05 UTC", attachments[0].Footer)
+}
+
+func TestMarkTriaged_NoAttachment(t *testing.T) {
+	api := &plugintest.API{}
+	api.On("GetPost", "post-1").Return(&model.Post{Id: "post-1"}, nil)
+
+	err := New(api, "bot-user-id").MarkTriaged("post-1", triage.StateResolved, "jane", time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no attachment")
+}
@@ -0,0 +1,114 @@
+package poster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// workflowLaunchKeyPrefix prefixes the KV keys marking the alerts a workflow was launched for
+	workflowLaunchKeyPrefix = "workflow_launch_"
+
+	// workflowLaunchTTL is how long a launch is remembered, well beyond the time it takes to
+	// post an alert to all of its channels
+	workflowLaunchTTL = 24 * time.Hour
+)
+
+// WorkflowLauncher starts the playbook run and creates the board card configured for an alert.
+type WorkflowLauncher interface {
+	Launch(ctx context.Context, settings backend.WorkflowSettings, alert backend.Alert, post *model.Post) error
+}
+
+// SetWorkflowLauncher configures the launcher of playbook runs and board cards for alerts.
+// Must be called before alerts are posted.
+func (p *Poster) SetWorkflowLauncher(launcher WorkflowLauncher) {
+	p.workflowLauncher = launcher
+}
+
+// SetWorkflows replaces the workflow settings, keyed by backend ID.
+// Alerts from backends without an entry start no workflow.
+func (p *Poster) SetWorkflows(settings map[string]backend.WorkflowSettings) {
+	p.optionsLock.Lock()
+	defer p.optionsLock.Unlock()
+
+	p.workflows = settings
+}
+
+// getWorkflow returns the workflow settings for an alert, and whether the alert starts a workflow
+func (p *Poster) getWorkflow(alert backend.Alert) (backend.WorkflowSettings, bool) {
+	if p.workflowLauncher == nil {
+		return backend.WorkflowSettings{}, false
+	}
+
+	p.optionsLock.RLock()
+	defer p.optionsLock.RUnlock()
+
+	settings, exists := p.workflows[alert.BackendID]
+	if !exists || !settings.AppliesTo(alert.AlertType) {
+		return backend.WorkflowSettings{}, false
+	}
+	return settings, true
+}
+
+// launchWorkflow starts the workflow configured for a posted alert in the background, once
+// per alert across all the channels and servers it is posted from. Failures are logged since
+// the alert itself has already been posted.
+func (p *Poster) launchWorkflow(alert backend.Alert, post *model.Post) {
+	settings, launches := p.getWorkflow(alert)
+	if !launches || post == nil {
+		return
+	}
+
+	if !p.claimWorkflowLaunch(alert) {
+		p.api.LogDebug("Workflow already launched for alert", "alertId", alert.AlertID, "postId", post.Id)
+		return
+	}
+
+	p.workflowLaunches.Add(1)
+	go func() {
+		defer p.workflowLaunches.Done()
+
+		// The launch outlives the posting of the alert, so it doesn't inherit its context
+		if err := p.workflowLauncher.Launch(context.Background(), settings, alert, post); err != nil {
+			p.api.LogWarn("Failed to start workflow for alert",
+				"alertId", alert.AlertID,
+				"backendName", alert.BackendName,
+				"error", err.Error())
+		}
+	}()
+}
+
+// WaitForWorkflows waits for the workflow launches still in progress, which are each bounded
+// by the launcher's request timeout
+func (p *Poster) WaitForWorkflows() {
+	p.workflowLaunches.Wait()
+}
+
+// claimWorkflowLaunch records in the KV store that the workflow of an alert is being launched.
+// Returns false if it already was. If the claim can't be made the workflow is launched anyway,
+// since a duplicate run is preferable to none.
+func (p *Poster) claimWorkflowLaunch(alert backend.Alert) bool {
+	claimed, appErr := p.api.KVSetWithOptions(workflowLaunchKey(alert), []byte(p.now().UTC().Format(time.RFC3339)), model.PluginKVSetOptions{
+		Atomic:          true,
+		OldValue:        nil,
+		ExpireInSeconds: int64(workflowLaunchTTL / time.Second),
+	})
+	if appErr != nil {
+		p.api.LogWarn("Failed to record workflow launch for alert, launching anyway", "alertId", alert.AlertID, "error", appErr.Error())
+		return true
+	}
+	return claimed
+}
+
+// workflowLaunchKey returns the KV key marking the workflow launch of an alert. Alert IDs are
+// hashed to stay within the KV key length limit.
+func workflowLaunchKey(alert backend.Alert) string {
+	sum := sha256.Sum256([]byte(alert.BackendID + ":" + alert.AlertID))
+	return workflowLaunchKeyPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,109 @@
+package poster
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// fakeWorkflowLauncher records the alert posts workflows were launched for
+type fakeWorkflowLauncher struct {
+	mu       sync.Mutex
+	launched []string
+	err      error
+}
+
+func (f *fakeWorkflowLauncher) Launch(_ context.Context, settings backend.WorkflowSettings, alert backend.Alert, post *model.Post) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.launched = append(f.launched, settings.PlaybookID+":"+alert.AlertID+":"+post.Id)
+	return f.err
+}
+
+func TestPostAlert_LaunchesWorkflow(t *testing.T) {
+	newPoster := func(api *plugintest.API, launcher *fakeWorkflowLauncher) *Poster {
+		api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "post-b"}, nil)
+		api.On("KVSetWithOptions", mock.AnythingOfType("string"), mock.Anything, mock.Anything).Return(true, nil).Maybe()
+		poster := New(api, "bot-user-id")
+		poster.SetWorkflowLauncher(launcher)
+		poster.SetWorkflows(map[string]backend.WorkflowSettings{"backend-b": {PlaybookID: "playbook1"}})
+		return poster
+	}
+
+	t.Run("flash alert", func(t *testing.T) {
+		launcher := &fakeWorkflowLauncher{}
+		poster := newPoster(&plugintest.API{}, launcher)
+
+		alert := similarTestAlert()
+		alert.AlertType = "Flash"
+		require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+		poster.WaitForWorkflows()
+		assert.Equal(t, []string{"playbook1:alert-b:post-b"}, launcher.launched)
+	})
+
+	t.Run("other alert types", func(t *testing.T) {
+		launcher := &fakeWorkflowLauncher{}
+		poster := newPoster(&plugintest.API{}, launcher)
+
+		require.NoError(t, poster.PostAlert(context.Background(), similarTestAlert(), "channel-id"))
+		assert.Empty(t, launcher.launched)
+	})
+
+	t.Run("backend without workflow", func(t *testing.T) {
+		launcher := &fakeWorkflowLauncher{}
+		poster := newPoster(&plugintest.API{}, launcher)
+
+		alert := similarTestAlert()
+		alert.AlertType = "Flash"
+		alert.BackendID = "backend-a"
+		require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+		assert.Empty(t, launcher.launched)
+	})
+
+	t.Run("launch failure is logged", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		api.On("LogWarn", "Failed to start workflow for alert",
+			"alertId", "alert-b", "backendName", "Backend B", "error", "plugin 'playbooks' is not available").Once()
+
+		launcher := &fakeWorkflowLauncher{err: errors.New("plugin 'playbooks' is not available")}
+		poster := newPoster(api, launcher)
+
+		alert := similarTestAlert()
+		alert.AlertType = "Flash"
+		require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+		poster.WaitForWorkflows()
+		assert.Len(t, launcher.launched, 1)
+	})
+
+	t.Run("launched once for an alert posted to several channels", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+		launchKey := workflowLaunchKey(backend.Alert{BackendID: "backend-b", AlertID: "alert-b"})
+		api.On("KVSetWithOptions", launchKey, mock.Anything, mock.MatchedBy(func(opts model.PluginKVSetOptions) bool {
+			return opts.Atomic && opts.OldValue == nil && opts.ExpireInSeconds == int64(workflowLaunchTTL/time.Second)
+		})).Return(true, nil).Once()
+		api.On("KVSetWithOptions", launchKey, mock.Anything, mock.Anything).Return(false, nil).Once()
+		api.On("LogDebug", "Workflow already launched for alert", "alertId", "alert-b", "postId", "post-b").Once()
+
+		launcher := &fakeWorkflowLauncher{}
+		poster := newPoster(api, launcher)
+
+		alert := similarTestAlert()
+		alert.AlertType = "Flash"
+		require.NoError(t, poster.PostAlert(context.Background(), alert, "channel-id"))
+		require.NoError(t, poster.PostAlert(context.Background(), alert, "other-channel-id"))
+		poster.WaitForWorkflows()
+		assert.Equal(t, []string{"playbook1:alert-b:post-b"}, launcher.launched)
+	})
+}
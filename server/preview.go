@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend/dataminr"
+)
+
+const (
+	// previewFormatDataminr is an alert as returned by the Dataminr API
+	previewFormatDataminr = "dataminr"
+
+	// previewFormatNormalized is an alert already normalized to a backend.Alert
+	previewFormatNormalized = "normalized"
+
+	// previewBackendName is the backend name shown in previews not rendered for a backend
+	previewBackendName = "Preview"
+
+	// maxPreviewRequestBytes caps the size of a preview request
+	maxPreviewRequestBytes = 1 << 20
+)
+
+// previewRequest is an alert to render as it would be posted
+type previewRequest struct {
+	// Format is the format of the alert: previewFormatDataminr (default) or previewFormatNormalized
+	Format string `json:"format"`
+
+	// BackendID optionally renders the alert with a backend's name, hashtag, topic style
+	// and content settings
+	BackendID string `json:"backendId"`
+
+	// ChannelID optionally renders the alert for a channel
+	ChannelID string `json:"channelId"`
+
+	// Alert is the alert in the given format
+	Alert json.RawMessage `json:"alert"`
+}
+
+// previewResponse is the post an alert renders to, and a markdown approximation of it
+type previewResponse struct {
+	Message     string                   `json:"message"`
+	Attachments []*model.SlackAttachment `json:"attachments"`
+	Markdown    string                   `json:"markdown"`
+}
+
+// previewAlert handles POST /api/v1/preview, rendering an alert through the formatter and
+// hashtag generator without posting it, so formatting changes can be checked before they go
+// live. Mentions are left out and nothing is recorded for the alert.
+func (p *Plugin) previewAlert(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxPreviewRequestBytes+1))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(data) > maxPreviewRequestBytes {
+		http.Error(w, "Preview request is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var request previewRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(request.Alert) == 0 {
+		http.Error(w, "Request has no 'alert' field", http.StatusBadRequest)
+		return
+	}
+
+	backendName := previewBackendName
+	if request.BackendID != "" {
+		cfg, found := findBackendConfigByID(p.getConfiguration().backends(), request.BackendID)
+		if !found {
+			http.Error(w, "Backend not found", http.StatusNotFound)
+			return
+		}
+		backendName = cfg.Name
+	}
+
+	alert, err := parsePreviewAlert(request.Format, request.Alert, backendName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	alert.BackendID = request.BackendID
+
+	post := p.poster.BuildTestPost(*alert, request.ChannelID)
+	response := previewResponse{
+		Message:     post.Message,
+		Attachments: post.Attachments(),
+		Markdown:    renderPreviewMarkdown(post),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("Failed to encode alert preview", "error", err.Error())
+	}
+}
+
+// parsePreviewAlert parses the alert of a preview request in its format
+func parsePreviewAlert(format string, data []byte, backendName string) (*backend.Alert, error) {
+	switch format {
+	case "", previewFormatDataminr:
+		return dataminr.ParseAlert(data, backendName)
+	case previewFormatNormalized:
+		var alert backend.Alert
+		if err := json.Unmarshal(data, &alert); err != nil {
+			return nil, fmt.Errorf("invalid normalized alert: %w", err)
+		}
+		if alert.AlertID == "" {
+			return nil, fmt.Errorf("alert is missing 'alertId'")
+		}
+		alert.BackendName = backendName
+		return &alert, nil
+	default:
+		return nil, fmt.Errorf("unknown alert format '%s' (must be %s or %s)", format, previewFormatDataminr, previewFormatNormalized)
+	}
+}
+
+// renderPreviewMarkdown approximates how Mattermost displays a post as markdown: the message,
+// then the pretext, title, text, fields, image and footer of each attachment
+func renderPreviewMarkdown(post *model.Post) string {
+	var parts []string
+	if post.Message != "" {
+		parts = append(parts, post.Message)
+	}
+
+	for _, attachment := range post.Attachments() {
+		if attachment.Pretext != "" {
+			parts = append(parts, attachment.Pretext)
+		}
+
+		switch {
+		case attachment.Title != "" && attachment.TitleLink != "":
+			parts = append(parts, fmt.Sprintf("**[%s](%s)**", attachment.Title, attachment.TitleLink))
+		case attachment.Title != "":
+			parts = append(parts, fmt.Sprintf("**%s**", attachment.Title))
+		}
+
+		if attachment.Text != "" {
+			parts = append(parts, attachment.Text)
+		}
+
+		var fields []string
+		for _, field := range attachment.Fields {
+			if value := fmt.Sprint(field.Value); value != "" {
+				fields = append(fields, fmt.Sprintf("**%s:** %s", field.Title, value))
+			}
+		}
+		if len(fields) > 0 {
+			parts = append(parts, strings.Join(fields, "\n"))
+		}
+
+		if attachment.ImageURL != "" {
+			parts = append(parts, fmt.Sprintf("![image](%s)", attachment.ImageURL))
+		}
+		if attachment.Footer != "" {
+			parts = append(parts, "_"+attachment.Footer+"_")
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
+)
+
+func TestPreviewAlert(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	p := newCommandTestPlugin(api)
+	p.poster = poster.New(api, "bot-id")
+	p.setConfiguration(&configuration{Backends: []backend.Config{{ID: "backend-1", Name: "Weather Watch", Type: "dataminr"}}})
+
+	preview := func(body string) (int, previewResponse) {
+		w := httptest.NewRecorder()
+		p.previewAlert(w, httptest.NewRequest(http.MethodPost, "/api/v1/preview", strings.NewReader(body)))
+
+		var response previewResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		}
+		return w.Code, response
+	}
+
+	t.Run("raw Dataminr alert", func(t *testing.T) {
+		code, response := preview(`{"alert": {
+			"alertId": "alert-1",
+			"alertType": {"name": "Flash"},
+			"eventTime": 1700000000000,
+			"headline": "Explosion reported at harbor"
+		}}`)
+		require.Equal(t, http.StatusOK, code)
+		require.NotEmpty(t, response.Attachments)
+		assert.Contains(t, response.Attachments[0].Text, "Explosion reported at harbor")
+		assert.Contains(t, response.Attachments[0].Footer, "Preview")
+		assert.Contains(t, response.Markdown, "Explosion reported at harbor")
+		assert.Contains(t, response.Markdown, "_"+response.Attachments[0].Footer+"_")
+	})
+
+	t.Run("normalized alert rendered for a backend", func(t *testing.T) {
+		code, response := preview(`{"format": "normalized", "backendId": "backend-1", "alert": {
+			"alertId": "alert-2",
+			"alertType": "Urgent",
+			"eventTime": "2024-05-01T12:00:00Z",
+			"headline": "Flooding downtown",
+			"topics": ["Weather"]
+		}}`)
+		require.Equal(t, http.StatusOK, code)
+		require.NotEmpty(t, response.Attachments)
+		assert.Contains(t, response.Attachments[0].Text, "Flooding downtown")
+		assert.Contains(t, response.Attachments[0].Footer, "Weather Watch")
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		code, _ := preview(`{"backendId": "missing", "alert": {"alertId": "alert-1"}}`)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, body := range []string{
+			`not json`,
+			`{}`,
+			`{"alert": {"headline": "No ID"}}`,
+			`{"format": "normalized", "alert": {"alertId": "alert-1", "eventTime": 5}}`,
+			`{"format": "xml", "alert": {"alertId": "alert-1"}}`,
+		} {
+			code, _ := preview(body)
+			assert.Equal(t, http.StatusBadRequest, code, body)
+		}
+	})
+}
+
+func TestRenderPreviewMarkdown(t *testing.T) {
+	post := &model.Post{Message: "@here #weather"}
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{{
+		Pretext:   "Flash alert",
+		Title:     "Open in Dataminr",
+		TitleLink: "https://example.com/alert",
+		Text:      "### Explosion reported",
+		Fields: []*model.SlackAttachmentField{
+			{Title: "Location", Value: "Harbor"},
+			{Title: "Empty", Value: ""},
+		},
+		ImageURL: "https://example.com/map.png",
+		Footer:   "Weather Watch",
+	}})
+
+	assert.Equal(t, strings.Join([]string{
+		"@here #weather",
+		"Flash alert",
+		"**[Open in Dataminr](https://example.com/alert)**",
+		"### Explosion reported",
+		"**Location:** Harbor",
+		"![image](https://example.com/map.png)",
+		"_Weather Watch_",
+	}, "\n\n"), renderPreviewMarkdown(post))
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+)
+
+const (
+	// defaultRecentAlertsLimit is how many alerts the recent alerts endpoint returns without a limit
+	defaultRecentAlertsLimit = 50
+
+	// maxRecentAlertsLimit caps the limit of the recent alerts endpoint
+	maxRecentAlertsLimit = 500
+)
+
+// recentAlertsResponse lists the most recently posted alerts of a backend, newest first
+type recentAlertsResponse struct {
+	Alerts []alertindex.Entry `json:"alerts"`
+}
+
+// getRecentAlerts serves the alerts a backend posted most recently, with the IDs of their
+// posts, from the alert index. Alerts posted to several channels are listed once per post.
+// Alerts in channels the user can't read are left out, so fewer than limit may be listed.
+func (p *Plugin) getRecentAlerts(w http.ResponseWriter, r *http.Request) {
+	b := p.registry.Get(mux.Vars(r)["id"])
+	if b == nil {
+		http.Error(w, "Backend not found", http.StatusNotFound)
+		return
+	}
+
+	limit := defaultRecentAlertsLimit
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		var err error
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > maxRecentAlertsLimit {
+			http.Error(w, "limit must be between 1 and "+strconv.Itoa(maxRecentAlertsLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries, err := p.alertIndex.Search(alertindex.Query{BackendID: b.GetID(), Limit: limit})
+	if err != nil {
+		p.API.LogError("Failed to search recent alerts", "id", b.GetID(), "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Only alerts posted in channels the user can read are listed
+	response := recentAlertsResponse{Alerts: p.filterReadable(r.Header.Get("Mattermost-User-ID"), entries)}
+	if response.Alerts == nil {
+		response.Alerts = []alertindex.Entry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("Failed to encode recent alerts response", "error", err.Error())
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestGetRecentAlerts(t *testing.T) {
+	api := &plugintest.API{}
+	mockMemoryKV(api)
+
+	api.On("HasPermissionToChannel", "operator-id", "channel-1", model.PermissionReadChannel).Return(true)
+	api.On("HasPermissionToChannel", "operator-id", "channel-2", model.PermissionReadChannel).Return(true)
+	api.On("HasPermissionToChannel", "operator-id", "private-channel", model.PermissionReadChannel).Return(false)
+
+	p := newCommandTestPlugin(api)
+	p.registry = backend.NewRegistry()
+	require.NoError(t, p.registry.Register(&fakeBackend{id: "backend-1", name: "Weather Watch"}))
+	require.NoError(t, p.registry.Register(&fakeBackend{id: "backend-2", name: "Quiet"}))
+	p.alertIndex = alertindex.New(api)
+
+	posted := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+	for i, entry := range []alertindex.Entry{
+		{AlertID: "alert-0", BackendID: "backend-1", AlertType: "Flash", Headline: "Private", ChannelID: "private-channel", PostID: "post-0"},
+		{AlertID: "alert-1", BackendID: "backend-1", AlertType: "Flash", Headline: "Flood warning", ChannelID: "channel-1", PostID: "post-1"},
+		{AlertID: "alert-2", BackendID: "backend-1", AlertType: "Alert", Headline: "Road closed", ChannelID: "channel-1", PostID: "post-2"},
+		{AlertID: "alert-3", BackendID: "backend-1", AlertType: "Urgent", Headline: "Power outage", ChannelID: "channel-2", PostID: "post-3"},
+		{AlertID: "alert-4", BackendID: "backend-3", AlertType: "Flash", Headline: "Other backend", ChannelID: "channel-2", PostID: "post-4"},
+	} {
+		entry.PostedAt = posted.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, p.alertIndex.Add(entry))
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/backends/{id}/recent", p.getRecentAlerts).Methods(http.MethodGet)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		r.Header.Set("Mattermost-User-ID", "operator-id")
+		router.ServeHTTP(w, r)
+		return w
+	}
+	postIDs := func(t *testing.T, w *httptest.ResponseRecorder) []string {
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var response recentAlertsResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		ids := []string{}
+		for _, entry := range response.Alerts {
+			ids = append(ids, entry.PostID)
+		}
+		return ids
+	}
+
+	// The alert posted in a channel the user can't read is left out
+	t.Run("newest first", func(t *testing.T) {
+		assert.Equal(t, []string{"post-3", "post-2", "post-1"}, postIDs(t, get("/api/v1/backends/backend-1/recent")))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		assert.Equal(t, []string{"post-3", "post-2"}, postIDs(t, get("/api/v1/backends/backend-1/recent?limit=2")))
+	})
+
+	t.Run("no alerts", func(t *testing.T) {
+		w := get("/api/v1/backends/backend-2/recent")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"alerts":[]}`, w.Body.String())
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, get("/api/v1/backends/unknown/recent").Code)
+		assert.Equal(t, http.StatusBadRequest, get("/api/v1/backends/backend-1/recent?limit=0").Code)
+		assert.Equal(t, http.StatusBadRequest, get("/api/v1/backends/backend-1/recent?limit=501").Code)
+		assert.Equal(t, http.StatusBadRequest, get("/api/v1/backends/backend-1/recent?limit=all").Code)
+	})
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// observeRegistry logs backend lifecycle changes and publishes the status changes backends report
+// to the webapp. Status changes are reported by the server node running the backend's poll
+// cycle, so each change is published once. Returns the function stopping observing.
+func (p *Plugin) observeRegistry() func() {
+	return p.registry.Observe(backend.RegistryObserver{
+		OnRegistered: func(b backend.Backend) {
+			p.API.LogDebug("Backend registered", "id", b.GetID(), "name", b.GetName())
+		},
+		OnUnregistered: func(b backend.Backend) {
+			p.API.LogDebug("Backend unregistered", "id", b.GetID(), "name", b.GetName())
+		},
+		OnStatusChanged: func(b backend.Backend, previous, current backend.Status) {
+			p.API.LogInfo("Backend status changed",
+				"id", b.GetID(),
+				"name", b.GetName(),
+				"previous", describeLifecycleStatus(previous),
+				"current", describeLifecycleStatus(current))
+			p.publishBackendStatusChanged(b, previous, current)
+		},
+	})
+}
+
+// describeLifecycleStatus summarizes a backend status for lifecycle logs
+func describeLifecycleStatus(status backend.Status) string {
+	switch {
+	case status.Paused:
+		return "paused"
+	case !status.CooldownUntil.IsZero():
+		return "cooling down"
+	default:
+		return string(classifyStatus(status))
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestObserveRegistry(t *testing.T) {
+	api := &plugintest.API{}
+	p := newCommandTestPlugin(api)
+	p.registry = backend.NewRegistry()
+
+	registered := make(chan struct{})
+	changed := make(chan struct{})
+	unregistered := make(chan struct{})
+	api.On("LogDebug", "Backend registered", "id", "backend-1", "name", "Weather Watch").Run(func(_ mock.Arguments) { close(registered) }).Once()
+	api.On("LogInfo", "Backend status changed", "id", "backend-1", "name", "Weather Watch", "previous", "healthy", "current", "paused").Run(func(_ mock.Arguments) { close(changed) }).Once()
+	api.On("PublishWebSocketEvent", wsEventBackendStatusChanged, map[string]any{
+		"backend_id":           "backend-1",
+		"backend_name":         "Weather Watch",
+		"previous":             "healthy",
+		"current":              "paused",
+		"consecutive_failures": 0,
+		"last_error":           "",
+	}, adminBroadcast()).Once()
+	api.On("LogDebug", "Backend unregistered", "id", "backend-1", "name", "Weather Watch").Run(func(_ mock.Arguments) { close(unregistered) }).Once()
+
+	stop := p.observeRegistry()
+	defer stop()
+
+	b := &fakeBackend{id: "backend-1", name: "Weather Watch", status: backend.Status{Enabled: true}}
+	require.NoError(t, p.registry.Register(b))
+	require.NoError(t, p.registry.Pause("backend-1", time.Time{}))
+	require.NoError(t, p.registry.Unregister(context.Background(), "backend-1"))
+
+	for _, delivered := range []chan struct{}{registered, changed, unregistered} {
+		select {
+		case <-delivered:
+		case <-time.After(time.Second):
+			t.Fatal("registry event was not logged")
+		}
+	}
+	api.AssertExpectations(t)
+}
+
+func TestDescribeLifecycleStatus(t *testing.T) {
+	assert.Equal(t, "healthy", describeLifecycleStatus(backend.Status{Enabled: true}))
+	assert.Equal(t, "failing", describeLifecycleStatus(backend.Status{Enabled: true, ConsecutiveFailures: 2}))
+	assert.Equal(t, "disabled", describeLifecycleStatus(backend.Status{}))
+	assert.Equal(t, "paused", describeLifecycleStatus(backend.Status{Enabled: true, Paused: true}))
+	assert.Equal(t, "cooling down", describeLifecycleStatus(backend.Status{Enabled: true, ConsecutiveFailures: 5, CooldownUntil: time.Now().Add(time.Minute)}))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+)
+
+const (
+	// defaultSearchWindow is how far back /dataminr search looks without a since: filter
+	defaultSearchWindow = 24 * time.Hour
+
+	// maxSearchResults caps the number of alerts listed in a search response
+	maxSearchResults = 10
+)
+
+// parseSearchQuery parses /dataminr search parameters into an index query.
+// Plain words are keywords; type:<alert type> and since:<duration> (e.g., 30m, 6h, 2d) are filters.
+func parseSearchQuery(params []string, now time.Time) (alertindex.Query, error) {
+	query := alertindex.Query{Since: now.Add(-defaultSearchWindow)}
+
+	for _, param := range params {
+		switch {
+		case strings.HasPrefix(strings.ToLower(param), "type:"):
+			query.AlertType = param[len("type:"):]
+			if query.AlertType == "" {
+				return query, errors.New("missing alert type after `type:`")
+			}
+		case strings.HasPrefix(strings.ToLower(param), "since:"):
+			window, err := parseDurationParam(param[len("since:"):])
+			if err != nil {
+				return query, err
+			}
+			query.Since = now.Add(-window)
+		default:
+			query.Keywords = append(query.Keywords, param)
+		}
+	}
+
+	return query, nil
+}
+
+// parseDurationParam parses a positive duration, additionally accepting whole days (e.g., 2d)
+func parseDurationParam(value string) (time.Duration, error) {
+	var window time.Duration
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, errors.Errorf("invalid duration `%s`", value)
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, errors.Errorf("invalid duration `%s`", value)
+		}
+		window = parsed
+	}
+
+	if window <= 0 {
+		return 0, errors.Errorf("duration `%s` must be positive", value)
+	}
+	return window, nil
+}
+
+// executeSearch handles /dataminr search
+func (p *Plugin) executeSearch(args *model.CommandArgs, params []string) string {
+	if len(params) == 0 {
+		return "Please provide keywords to search for, e.g. `/dataminr search flood type:flash since:6h`."
+	}
+
+	query, err := parseSearchQuery(params, time.Now())
+	if err != nil {
+		return fmt.Sprintf("Invalid search: %s.", err.Error())
+	}
+
+	entries, err := p.alertIndex.Search(query)
+	if err != nil {
+		p.API.LogError("Failed to search alerts", "userId", args.UserId, "error", err.Error())
+		return "Failed to search alerts."
+	}
+
+	// Only list alerts posted in channels the user can read
+	results := p.filterReadable(args.UserId, entries)
+	if len(results) == 0 {
+		return fmt.Sprintf("No alerts found matching `%s`.", strings.Join(params, " "))
+	}
+
+	return formatSearchResults(params, results, p.siteURL())
+}
+
+// filterReadable returns the entries posted in channels the user can read
+func (p *Plugin) filterReadable(userID string, entries []alertindex.Entry) []alertindex.Entry {
+	canRead := p.readableChannels(userID)
+	var results []alertindex.Entry
+	for _, entry := range entries {
+		if canRead(entry.ChannelID) {
+			results = append(results, entry)
+		}
+	}
+	return results
+}
+
+// formatSearchResults lists matching alerts with permalinks to their posts
+func formatSearchResults(params []string, results []alertindex.Entry, siteURL string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("###### Alerts matching `%s`\n", strings.Join(params, " ")))
+
+	for i, entry := range results {
+		if i == maxSearchResults {
+			sb.WriteString(fmt.Sprintf("_Showing the %d most recent of %d matching alerts. Refine the search to narrow the results._\n", maxSearchResults, len(results)))
+			break
+		}
+
+		sb.WriteString(fmt.Sprintf("* **%s** %s (%s, %s) [View post](%s/_redirect/pl/%s)\n",
+			entry.AlertType,
+			entry.Headline,
+			entry.BackendName,
+			entry.PostedAt.UTC().Format("2006-01-02 15:04 MST"),
+			siteURL,
+			entry.PostID))
+	}
+
+	return sb.String()
+}
+
+// siteURL returns the configured site URL without a trailing slash
+func (p *Plugin) siteURL() string {
+	config := p.API.GetConfig()
+	if config == nil || config.ServiceSettings.SiteURL == nil {
+		return ""
+	}
+	return strings.TrimSuffix(*config.ServiceSettings.SiteURL, "/")
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	t.Run("keywords with default window", func(t *testing.T) {
+		query, err := parseSearchQuery([]string{"flood", "downtown"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"flood", "downtown"}, query.Keywords)
+		assert.Equal(t, now.Add(-24*time.Hour), query.Since)
+		assert.Empty(t, query.AlertType)
+	})
+
+	t.Run("type and since filters", func(t *testing.T) {
+		query, err := parseSearchQuery([]string{"Type:Flash", "flood", "since:2d"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"flood"}, query.Keywords)
+		assert.Equal(t, "Flash", query.AlertType)
+		assert.Equal(t, now.Add(-48*time.Hour), query.Since)
+
+		query, err = parseSearchQuery([]string{"since:90m"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(-90*time.Minute), query.Since)
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		_, err := parseSearchQuery([]string{"type:"}, now)
+		assert.EqualError(t, err, "missing alert type after `type:`")
+
+		_, err = parseSearchQuery([]string{"since:soon"}, now)
+		assert.EqualError(t, err, "invalid duration `soon`")
+
+		_, err = parseSearchQuery([]string{"since:-1h"}, now)
+		assert.EqualError(t, err, "duration `-1h` must be positive")
+	})
+}
+
+func TestExecuteSearch(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	entries := []alertindex.Entry{
+		{AlertID: "a", AlertType: "Flash", Headline: "Flood warning", BackendName: "Weather", ChannelID: "channel-1", PostID: "post-a", PostedAt: time.Now().Add(-time.Hour)},
+		{AlertID: "b", AlertType: "Urgent", Headline: "Flood in restricted area", BackendName: "Security", ChannelID: "channel-2", PostID: "post-b", PostedAt: time.Now().Add(-time.Hour)},
+	}
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		if key == "alert_index_"+time.Now().Add(-time.Hour).UTC().Format("2006010215") {
+			return data, nil
+		}
+		return nil, nil
+	})
+	api.On("HasPermissionToChannel", "user-id", "channel-1", model.PermissionReadChannel).Return(true).Once()
+	api.On("HasPermissionToChannel", "user-id", "channel-2", model.PermissionReadChannel).Return(false).Once()
+	siteURL := "https://chat.example.com/"
+	api.On("GetConfig").Return(&model.Config{ServiceSettings: model.ServiceSettings{SiteURL: &siteURL}})
+
+	p := newCommandTestPlugin(api)
+	p.alertIndex = alertindex.New(api)
+
+	text := p.executeSearch(&model.CommandArgs{UserId: "user-id"}, []string{"flood"})
+	assert.Contains(t, text, "Alerts matching `flood`")
+	assert.Contains(t, text, "**Flash** Flood warning (Weather,")
+	assert.Contains(t, text, "[View post](https://chat.example.com/_redirect/pl/post-a)")
+	assert.NotContains(t, text, "restricted area")
+}
+
+func TestExecuteSearch_NoResults(t *testing.T) {
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(nil, nil)
+
+	p := newCommandTestPlugin(api)
+	p.alertIndex = alertindex.New(api)
+
+	assert.Equal(t, "No alerts found matching `flood`.", p.executeSearch(&model.CommandArgs{UserId: "user-id"}, []string{"flood"}))
+	assert.Contains(t, p.executeSearch(&model.CommandArgs{UserId: "user-id"}, nil), "Please provide keywords")
+}
+
+func TestFormatSearchResults_Truncates(t *testing.T) {
+	results := make([]alertindex.Entry, maxSearchResults+2)
+	for i := range results {
+		results[i] = alertindex.Entry{AlertType: "Alert", Headline: "Headline", PostID: "post"}
+	}
+
+	text := formatSearchResults([]string{"headline"}, results, "https://chat.example.com")
+	assert.Contains(t, text, "Showing the 10 most recent of 12 matching alerts")
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/secrets"
+)
+
+const (
+	// apiKeyMigrationMutexKey serializes moving API keys and proxy passwords into the secret
+	// store across the cluster, since every node runs OnConfigurationChange
+	apiKeyMigrationMutexKey = "api_key_migration"
+
+	// kvKeyEncryptionKeyMarker records the fingerprint of the encryption key secrets are
+	// stored with, claimed with compare-and-set by the node that generates it
+	kvKeyEncryptionKeyMarker = "secrets_encryption_key" //nolint:gosec // False positive: this is a key name, not a credential
+)
+
+// storeSecrets encrypts plaintext backend API keys and proxy passwords into the KV store and
+// marks them as stored, generating the encryption key on first use. Environment variable
+// references are left as is. Returns true if any secret was stored, in which case the
+// configuration must be saved with the secrets blanked.
+//
+// Every node runs this for the same configuration change, so the migration runs under a cluster
+// mutex: a node that finds an encryption key generated by another node whose configuration has
+// not reached it yet leaves the secrets to that node, and secrets another node already stored
+// are only marked as stored.
+func (p *Plugin) storeSecrets(config *configuration) (bool, error) {
+	if !hasPlaintextSecrets(config) {
+		return false, nil
+	}
+
+	mutex, err := cluster.NewMutex(p.API, apiKeyMigrationMutexKey)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create API key migration mutex")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	marker, appErr := p.API.KVGet(kvKeyEncryptionKeyMarker)
+	if appErr != nil {
+		return false, errors.Wrap(appErr, "failed to get encryption key marker")
+	}
+
+	if config.EncryptionKey == "" {
+		if marker != nil {
+			p.API.LogInfo("Secrets are being stored by another server, waiting for its configuration")
+			return false, nil
+		}
+
+		key, err := secrets.GenerateKey()
+		if err != nil {
+			return false, err
+		}
+		config.EncryptionKey = key
+	}
+
+	fingerprint := encryptionKeyFingerprint(config.EncryptionKey)
+	if marker == nil {
+		saved, appErr := p.API.KVSetWithOptions(kvKeyEncryptionKeyMarker, fingerprint, model.PluginKVSetOptions{Atomic: true, OldValue: nil})
+		if appErr != nil {
+			return false, errors.Wrap(appErr, "failed to save encryption key marker")
+		}
+		if !saved {
+			p.API.LogInfo("Secrets are being stored by another server, waiting for its configuration")
+			return false, nil
+		}
+	} else if !bytes.Equal(marker, fingerprint) {
+		return false, errors.New("the encryption key does not match the key stored secrets are encrypted with")
+	}
+
+	store, err := secrets.NewStore(p.API, config.EncryptionKey)
+	if err != nil {
+		return false, err
+	}
+
+	migrated := false
+	for i := range config.Backends {
+		cfg := &config.Backends[i]
+
+		if isPlaintextSecret(cfg.APIKey) {
+			stored, err := storeSecret(cfg.APIKey, store.GetAPIKey, store.SaveAPIKey, cfg.ID)
+			if err != nil {
+				return false, errors.Wrapf(err, "backend '%s'", cfg.Name)
+			}
+			cfg.APIKeyStored = true
+			if stored {
+				migrated = true
+				p.API.LogInfo("Moved backend API key to the encrypted secret store", "id", cfg.ID, "name", cfg.Name)
+			}
+		}
+
+		if isPlaintextSecret(cfg.ProxyPassword) {
+			stored, err := storeSecret(cfg.ProxyPassword, store.GetProxyPassword, store.SaveProxyPassword, cfg.ID)
+			if err != nil {
+				return false, errors.Wrapf(err, "backend '%s'", cfg.Name)
+			}
+			cfg.ProxyPasswordStored = true
+			if stored {
+				migrated = true
+				p.API.LogInfo("Moved backend proxy password to the encrypted secret store", "id", cfg.ID, "name", cfg.Name)
+			}
+		}
+	}
+
+	return migrated, nil
+}
+
+// storeSecret saves a backend's secret unless another node already stored the same value and
+// saves the configuration. Returns whether the secret was saved.
+func storeSecret(value string, get func(string) (string, error), save func(string, string) error, backendID string) (bool, error) {
+	if stored, err := get(backendID); err == nil && stored == value {
+		return false, nil
+	}
+	if err := save(backendID, value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// hasPlaintextSecrets reports whether any backend has an API key or proxy password to move to
+// the secret store
+func hasPlaintextSecrets(config *configuration) bool {
+	for _, cfg := range config.Backends {
+		if isPlaintextSecret(cfg.APIKey) || isPlaintextSecret(cfg.ProxyPassword) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlaintextSecret reports whether a configured secret is set in plaintext rather than
+// referencing an environment variable
+func isPlaintextSecret(value string) bool {
+	return value != "" && !secrets.IsEnvReference(value)
+}
+
+// encryptionKeyFingerprint identifies an encryption key without revealing it
+func encryptionKeyFingerprint(key string) []byte {
+	sum := sha256.Sum256([]byte("dataminr-encryption-key:" + key))
+	return []byte(hex.EncodeToString(sum[:16]))
+}
+
+// blankStoredSecrets removes the plaintext of API keys and proxy passwords kept in the secret store
+func blankStoredSecrets(config *configuration) {
+	for i := range config.Backends {
+		cfg := &config.Backends[i]
+		if cfg.APIKeyStored && !secrets.IsEnvReference(cfg.APIKey) {
+			cfg.APIKey = ""
+		}
+		if cfg.ProxyPasswordStored && !secrets.IsEnvReference(cfg.ProxyPassword) {
+			cfg.ProxyPassword = ""
+		}
+	}
+}
+
+// saveMigratedConfig persists a configuration whose secrets were moved to the secret store.
+// It runs in a goroutine since saving triggers OnConfigurationChange.
+func (p *Plugin) saveMigratedConfig(config *configuration) {
+	if err := p.savePluginConfig(config); err != nil {
+		p.API.LogError("Failed to save configuration after storing secrets", "error", err.Error())
+	}
+}
+
+// savePluginConfig saves a configuration to the Mattermost server configuration.
+// Saving triggers OnConfigurationChange, so it must not be called from within that hook.
+func (p *Plugin) savePluginConfig(config *configuration) error {
+	marshalBytes, err := json.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal configuration")
+	}
+
+	configMap := make(map[string]any)
+	if err := json.Unmarshal(marshalBytes, &configMap); err != nil {
+		return errors.Wrap(err, "failed to unmarshal configuration")
+	}
+
+	if appErr := p.API.SavePluginConfig(configMap); appErr != nil {
+		return errors.Wrap(appErr, "failed to save configuration")
+	}
+	return nil
+}
+
+// resolveAPIKey returns the API key of a backend from an environment variable reference,
+// the configuration, or the encrypted secret store. Backends that need no credentials have no API key.
+func (p *Plugin) resolveAPIKey(cfg backend.Config) (string, error) {
+	if !cfg.RequiresCredentials() {
+		return "", nil
+	}
+	if secrets.IsEnvReference(cfg.APIKey) {
+		return secrets.ResolveEnvReference(cfg.APIKey)
+	}
+	if cfg.APIKey != "" {
+		return cfg.APIKey, nil
+	}
+	if !cfg.APIKeyStored {
+		return "", errors.New("no API key configured")
+	}
+
+	store, err := secrets.NewStore(p.API, p.getConfiguration().EncryptionKey)
+	if err != nil {
+		return "", err
+	}
+	return store.GetAPIKey(cfg.ID)
+}
+
+// resolveProxyPassword returns the proxy password of a backend from an environment variable
+// reference, the configuration, or the encrypted secret store. A warm standby uses the proxy
+// password stored for its primary backend.
+func (p *Plugin) resolveProxyPassword(cfg backend.Config) (string, error) {
+	if secrets.IsEnvReference(cfg.ProxyPassword) {
+		return secrets.ResolveEnvReference(cfg.ProxyPassword)
+	}
+	if cfg.ProxyPassword != "" || !cfg.ProxyPasswordStored {
+		return cfg.ProxyPassword, nil
+	}
+
+	store, err := secrets.NewStore(p.API, p.getConfiguration().EncryptionKey)
+	if err != nil {
+		return "", err
+	}
+	return store.GetProxyPassword(backend.PrimaryID(cfg.ID))
+}
+
+// deleteSecrets removes the stored API key and proxy password of a backend removed from the
+// configuration
+func (p *Plugin) deleteSecrets(config *configuration, backendID string) {
+	if config.EncryptionKey == "" {
+		return
+	}
+
+	store, err := secrets.NewStore(p.API, config.EncryptionKey)
+	if err == nil {
+		err = store.DeleteAPIKey(backendID)
+	}
+	if err == nil {
+		err = store.DeleteProxyPassword(backendID)
+	}
+	if err != nil {
+		p.API.LogWarn("Failed to delete stored secrets", "id", backendID, "error", err.Error())
+	}
+}
@@ -0,0 +1,183 @@
+// Package secrets stores backend API keys and proxy passwords encrypted in the plugin KV store,
+// so they do not appear in the plugin configuration or configuration exports.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+)
+
+const (
+	// kvKeyAPIKey is the KV key of a backend's encrypted API key
+	kvKeyAPIKey = "backend_%s_api_key" //nolint:gosec // False positive: this is a key name format, not a credential
+
+	// kvKeyProxyPassword is the KV key of a backend's encrypted proxy password
+	kvKeyProxyPassword = "backend_%s_proxy_password" //nolint:gosec // False positive: this is a key name format, not a credential
+
+	// keySize is the AES-256 key size in bytes
+	keySize = 32
+
+	// EnvPrefix marks an API key value that references an environment variable (e.g., env:DATAMINR_API_KEY)
+	EnvPrefix = "env:"
+)
+
+// GenerateKey returns a new random base64-encoded encryption key
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// IsEnvReference reports whether an API key value references an environment variable
+func IsEnvReference(value string) bool {
+	return strings.HasPrefix(value, EnvPrefix)
+}
+
+// ResolveEnvReference returns the value of the environment variable an API key references
+func ResolveEnvReference(value string) (string, error) {
+	name := strings.TrimPrefix(value, EnvPrefix)
+	if name == "" {
+		return "", fmt.Errorf("missing environment variable name after '%s'", EnvPrefix)
+	}
+
+	resolved := os.Getenv(name)
+	if resolved == "" {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return resolved, nil
+}
+
+// Store encrypts API keys with AES-GCM before saving them in the KV store
+type Store struct {
+	api  plugin.API
+	aead cipher.AEAD
+}
+
+// NewStore creates a secret store using a base64-encoded 32-byte encryption key
+func NewStore(api plugin.API, encryptionKey string) (*Store, error) {
+	key, err := base64.StdEncoding.DecodeString(encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("invalid encryption key: expected %d bytes (got %d)", keySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return &Store{api: api, aead: aead}, nil
+}
+
+// secretKind describes a kind of secret kept for each backend
+type secretKind struct {
+	// keyFormat formats the KV key of the secret with the backend ID
+	keyFormat string
+
+	// name describes the secret in errors
+	name string
+
+	// dataSuffix is appended to the backend ID authenticated with the secret, so the
+	// ciphertext of one kind of secret cannot be used as another
+	dataSuffix string
+}
+
+var (
+	apiKeySecret        = secretKind{keyFormat: kvKeyAPIKey, name: "API key"}
+	proxyPasswordSecret = secretKind{keyFormat: kvKeyProxyPassword, name: "proxy password", dataSuffix: ":proxy"}
+)
+
+// SaveAPIKey encrypts and stores a backend's API key
+func (s *Store) SaveAPIKey(backendID, apiKey string) error {
+	return s.save(apiKeySecret, backendID, apiKey)
+}
+
+// GetAPIKey loads and decrypts a backend's API key.
+// Returns an error if no key is stored or it cannot be decrypted with the current encryption key.
+func (s *Store) GetAPIKey(backendID string) (string, error) {
+	return s.get(apiKeySecret, backendID)
+}
+
+// DeleteAPIKey removes a backend's stored API key
+func (s *Store) DeleteAPIKey(backendID string) error {
+	return s.delete(apiKeySecret, backendID)
+}
+
+// SaveProxyPassword encrypts and stores a backend's proxy password
+func (s *Store) SaveProxyPassword(backendID, password string) error {
+	return s.save(proxyPasswordSecret, backendID, password)
+}
+
+// GetProxyPassword loads and decrypts a backend's proxy password.
+// Returns an error if no password is stored or it cannot be decrypted with the current encryption key.
+func (s *Store) GetProxyPassword(backendID string) (string, error) {
+	return s.get(proxyPasswordSecret, backendID)
+}
+
+// DeleteProxyPassword removes a backend's stored proxy password
+func (s *Store) DeleteProxyPassword(backendID string) error {
+	return s.delete(proxyPasswordSecret, backendID)
+}
+
+// save encrypts and stores a backend's secret
+func (s *Store) save(kind secretKind, backendID, secret string) error {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// The backend ID is authenticated with the key, so a ciphertext cannot be moved to another backend
+	sealed := s.aead.Seal(nonce, nonce, []byte(secret), []byte(backendID+kind.dataSuffix))
+
+	if appErr := s.api.KVSet(fmt.Sprintf(kind.keyFormat, backendID), sealed); appErr != nil {
+		return fmt.Errorf("failed to save %s: %w", kind.name, appErr)
+	}
+	return nil
+}
+
+// get loads and decrypts a backend's secret
+func (s *Store) get(kind secretKind, backendID string) (string, error) {
+	sealed, appErr := s.api.KVGet(fmt.Sprintf(kind.keyFormat, backendID))
+	if appErr != nil {
+		return "", fmt.Errorf("failed to get %s: %w", kind.name, appErr)
+	}
+	if sealed == nil {
+		return "", fmt.Errorf("no %s stored for backend %s", kind.name, backendID)
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", fmt.Errorf("stored %s for backend %s is corrupted", kind.name, backendID)
+	}
+
+	plaintext, err := s.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(backendID+kind.dataSuffix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s for backend %s (was the encryption key changed?)", kind.name, backendID)
+	}
+	return string(plaintext), nil
+}
+
+// delete removes a backend's stored secret
+func (s *Store) delete(kind secretKind, backendID string) error {
+	if appErr := s.api.KVDelete(fmt.Sprintf(kind.keyFormat, backendID)); appErr != nil {
+		return fmt.Errorf("failed to delete %s: %w", kind.name, appErr)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newMemoryKVAPI returns a plugin API mock backed by an in-memory KV store
+func newMemoryKVAPI() (*plugintest.API, map[string][]byte) {
+	kv := make(map[string][]byte)
+	api := &plugintest.API{}
+	api.On("KVSet", mock.Anything, mock.Anything).Return(func(key string, value []byte) *model.AppError {
+		kv[key] = value
+		return nil
+	})
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return kv[key], nil
+	})
+	api.On("KVDelete", mock.Anything).Return(func(key string) *model.AppError {
+		delete(kv, key)
+		return nil
+	})
+	return api, kv
+}
+
+func TestStore_APIKeyRoundTrip(t *testing.T) {
+	api, kv := newMemoryKVAPI()
+	key, err := GenerateKey()
+	require.NoError(t, err)
+
+	store, err := NewStore(api, key)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveAPIKey("backend-1", "super-secret"))
+	assert.NotContains(t, string(kv["backend_backend-1_api_key"]), "super-secret", "the key must be stored encrypted")
+
+	apiKey, err := store.GetAPIKey("backend-1")
+	require.NoError(t, err)
+	assert.Equal(t, "super-secret", apiKey)
+
+	require.NoError(t, store.DeleteAPIKey("backend-1"))
+	_, err = store.GetAPIKey("backend-1")
+	assert.EqualError(t, err, "no API key stored for backend backend-1")
+}
+
+func TestStore_ProxyPasswordRoundTrip(t *testing.T) {
+	api, kv := newMemoryKVAPI()
+	key, err := GenerateKey()
+	require.NoError(t, err)
+
+	store, err := NewStore(api, key)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveProxyPassword("backend-1", "proxy-secret"))
+	assert.NotContains(t, string(kv["backend_backend-1_proxy_password"]), "proxy-secret", "the password must be stored encrypted")
+
+	password, err := store.GetProxyPassword("backend-1")
+	require.NoError(t, err)
+	assert.Equal(t, "proxy-secret", password)
+
+	// The password's ciphertext can't be used as the API key
+	kv["backend_backend-1_api_key"] = kv["backend_backend-1_proxy_password"]
+	_, err = store.GetAPIKey("backend-1")
+	assert.Error(t, err)
+
+	require.NoError(t, store.DeleteProxyPassword("backend-1"))
+	_, err = store.GetProxyPassword("backend-1")
+	assert.EqualError(t, err, "no proxy password stored for backend backend-1")
+}
+
+func TestStore_GetAPIKeyFailures(t *testing.T) {
+	api, kv := newMemoryKVAPI()
+	key, err := GenerateKey()
+	require.NoError(t, err)
+	store, err := NewStore(api, key)
+	require.NoError(t, err)
+	require.NoError(t, store.SaveAPIKey("backend-1", "super-secret"))
+
+	t.Run("different encryption key", func(t *testing.T) {
+		otherKey, err := GenerateKey()
+		require.NoError(t, err)
+		other, err := NewStore(api, otherKey)
+		require.NoError(t, err)
+
+		_, err = other.GetAPIKey("backend-1")
+		assert.ErrorContains(t, err, "was the encryption key changed?")
+	})
+
+	t.Run("ciphertext moved to another backend", func(t *testing.T) {
+		kv["backend_backend-2_api_key"] = kv["backend_backend-1_api_key"]
+
+		_, err := store.GetAPIKey("backend-2")
+		assert.ErrorContains(t, err, "failed to decrypt API key for backend backend-2")
+	})
+
+	t.Run("corrupted ciphertext", func(t *testing.T) {
+		kv["backend_backend-3_api_key"] = []byte("short")
+
+		_, err := store.GetAPIKey("backend-3")
+		assert.EqualError(t, err, "stored API key for backend backend-3 is corrupted")
+	})
+}
+
+func TestNewStore_InvalidKey(t *testing.T) {
+	_, err := NewStore(&plugintest.API{}, "not base64!")
+	assert.ErrorContains(t, err, "invalid encryption key")
+
+	_, err = NewStore(&plugintest.API{}, "c2hvcnQ=")
+	assert.EqualError(t, err, "invalid encryption key: expected 32 bytes (got 5)")
+}
+
+func TestResolveEnvReference(t *testing.T) {
+	t.Setenv("DATAMINR_TEST_API_KEY", "from-env")
+
+	assert.True(t, IsEnvReference("env:DATAMINR_TEST_API_KEY"))
+	assert.False(t, IsEnvReference("plain-key"))
+
+	value, err := ResolveEnvReference("env:DATAMINR_TEST_API_KEY")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", value)
+
+	_, err = ResolveEnvReference("env:DATAMINR_TEST_UNSET_KEY")
+	assert.EqualError(t, err, "environment variable DATAMINR_TEST_UNSET_KEY is not set")
+
+	_, err = ResolveEnvReference("env:")
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/secrets"
+)
+
+func TestStoreSecrets(t *testing.T) {
+	api := &plugintest.API{}
+	kv := newMemoryKV(api)
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	p := newCommandTestPlugin(api)
+	config := &configuration{Backends: []backend.Config{
+		{ID: "backend-1", Name: "Plaintext", APIKey: "plain-key"},
+		{ID: "backend-2", Name: "Environment", APIKey: "env:DATAMINR_TEST_API_KEY"},
+		{ID: "backend-3", Name: "Already stored", APIKeyStored: true},
+		{ID: "backend-4", Name: "Proxy", APIKey: "env:DATAMINR_TEST_API_KEY", ProxyUsername: "proxy-user", ProxyPassword: "proxy-pass"},
+		{ID: "backend-5", Name: "Proxy environment", APIKeyStored: true, ProxyUsername: "proxy-user", ProxyPassword: "env:DATAMINR_TEST_PROXY_PASSWORD"},
+	}}
+
+	migrated, err := p.storeSecrets(config)
+	require.NoError(t, err)
+	assert.True(t, migrated)
+	assert.NotEmpty(t, config.EncryptionKey, "an encryption key is generated on first use")
+	assert.True(t, config.Backends[0].APIKeyStored)
+	assert.False(t, config.Backends[1].APIKeyStored, "environment references are not stored")
+	assert.Contains(t, kv, "backend_backend-1_api_key")
+	assert.True(t, config.Backends[3].ProxyPasswordStored)
+	assert.False(t, config.Backends[4].ProxyPasswordStored, "environment references are not stored")
+	assert.Contains(t, kv, "backend_backend-4_proxy_password")
+	assert.Equal(t, encryptionKeyFingerprint(config.EncryptionKey), kv[kvKeyEncryptionKeyMarker])
+	assert.NotContains(t, kv, "mutex_"+apiKeyMigrationMutexKey, "the migration mutex is released")
+
+	blankStoredSecrets(config)
+	assert.Empty(t, config.Backends[0].APIKey)
+	assert.Equal(t, "env:DATAMINR_TEST_API_KEY", config.Backends[1].APIKey)
+	assert.Empty(t, config.Backends[3].ProxyPassword)
+	assert.Equal(t, "env:DATAMINR_TEST_PROXY_PASSWORD", config.Backends[4].ProxyPassword)
+
+	// The stored key is resolved with the saved encryption key
+	p.setConfiguration(config)
+	apiKey, err := p.resolveAPIKey(config.Backends[0])
+	require.NoError(t, err)
+	assert.Equal(t, "plain-key", apiKey)
+	password, err := p.resolveProxyPassword(config.Backends[3])
+	require.NoError(t, err)
+	assert.Equal(t, "proxy-pass", password)
+
+	// The standby of a backend uses the proxy password stored for the backend
+	standby := config.Backends[3]
+	standby.ID = backend.StandbyID(standby.ID)
+	password, err = p.resolveProxyPassword(standby)
+	require.NoError(t, err)
+	assert.Equal(t, "proxy-pass", password)
+
+	// Nothing is left to migrate once the keys are blanked
+	migrated, err = p.storeSecrets(config)
+	require.NoError(t, err)
+	assert.False(t, migrated)
+}
+
+func TestStoreSecrets_Cluster(t *testing.T) {
+	api := &plugintest.API{}
+	kv := newMemoryKV(api)
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything).Maybe()
+	p := newCommandTestPlugin(api)
+
+	newConfig := func() *configuration {
+		return &configuration{Backends: []backend.Config{{ID: "backend-1", Name: "Plaintext", APIKey: "plain-key"}}}
+	}
+
+	// The first node generates the encryption key and stores the API key
+	first := newConfig()
+	migrated, err := p.storeSecrets(first)
+	require.NoError(t, err)
+	require.True(t, migrated)
+	stored := kv["backend_backend-1_api_key"]
+
+	t.Run("a node without the generated key leaves the migration to the first node", func(t *testing.T) {
+		other := newConfig()
+		migrated, err := p.storeSecrets(other)
+		require.NoError(t, err)
+		assert.False(t, migrated)
+		assert.Empty(t, other.EncryptionKey, "no second encryption key is generated")
+		assert.False(t, other.Backends[0].APIKeyStored)
+		assert.Equal(t, stored, kv["backend_backend-1_api_key"], "the stored key is not overwritten")
+	})
+
+	t.Run("a node with the generated key only marks stored keys", func(t *testing.T) {
+		other := newConfig()
+		other.EncryptionKey = first.EncryptionKey
+		migrated, err := p.storeSecrets(other)
+		require.NoError(t, err)
+		assert.False(t, migrated, "the configuration is saved by the first node")
+		assert.True(t, other.Backends[0].APIKeyStored)
+		assert.Equal(t, stored, kv["backend_backend-1_api_key"])
+	})
+
+	t.Run("a different encryption key is rejected", func(t *testing.T) {
+		other := newConfig()
+		other.EncryptionKey, err = secrets.GenerateKey()
+		require.NoError(t, err)
+		_, err := p.storeSecrets(other)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match")
+	})
+}
+
+func TestResolveAPIKey(t *testing.T) {
+	t.Setenv("DATAMINR_TEST_API_KEY", "from-env")
+	p := newCommandTestPlugin(&plugintest.API{})
+
+	apiKey, err := p.resolveAPIKey(backend.Config{APIKey: "env:DATAMINR_TEST_API_KEY"})
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", apiKey)
+
+	apiKey, err = p.resolveAPIKey(backend.Config{APIKey: "plain-key"})
+	require.NoError(t, err)
+	assert.Equal(t, "plain-key", apiKey)
+
+	_, err = p.resolveAPIKey(backend.Config{})
+	assert.EqualError(t, err, "no API key configured")
+}
+
+func TestResolveProxyPassword(t *testing.T) {
+	t.Setenv("DATAMINR_TEST_PROXY_PASSWORD", "from-env")
+	p := newCommandTestPlugin(&plugintest.API{})
+
+	password, err := p.resolveProxyPassword(backend.Config{ProxyPassword: "env:DATAMINR_TEST_PROXY_PASSWORD"})
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", password)
+
+	password, err = p.resolveProxyPassword(backend.Config{ProxyPassword: "plain-pass"})
+	require.NoError(t, err)
+	assert.Equal(t, "plain-pass", password)
+
+	password, err = p.resolveProxyPassword(backend.Config{})
+	require.NoError(t, err)
+	assert.Empty(t, password, "backends may use a proxy without a password")
+
+	_, err = p.resolveProxyPassword(backend.Config{ProxyPassword: "env:DATAMINR_TEST_PROXY_PASSWORD_UNSET"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend/dataminr"
+)
+
+// executeSelfTest handles /dataminr selftest <backend> [preview]. It runs a synthetic alert
+// through normalization, formatting and posting as the backend's alerts would be, posting it
+// to the current channel or, with preview, showing it only to the caller. The Dataminr API is
+// not called and the test alert is not recorded, so alert history and statistics are unaffected.
+func (p *Plugin) executeSelfTest(args *model.CommandArgs, params []string) string {
+	preview := len(params) > 0 && strings.EqualFold(params[len(params)-1], "preview")
+	if preview {
+		params = params[:len(params)-1]
+	}
+	if len(params) == 0 {
+		return fmt.Sprintf("Please specify a backend, e.g. `/%s selftest Weather Watch`.", commandTrigger)
+	}
+
+	name := strings.Join(params, " ")
+	b := p.findBackend(name)
+	if b == nil {
+		return fmt.Sprintf("Backend `%s` not found.", name)
+	}
+
+	report := []string{fmt.Sprintf("###### Self-test of %s", b.GetName())}
+	pass := func(step string) { report = append(report, ":white_check_mark: "+step) }
+	fail := func(step string) string {
+		report = append(report, ":x: "+step)
+		return strings.Join(report, "\n")
+	}
+
+	alert, err := dataminr.SelfTestAlert(b.GetID(), b.GetName(), time.Now())
+	if err != nil {
+		return fail("Failed to create the synthetic alert: " + err.Error())
+	}
+	pass(fmt.Sprintf("Normalized a synthetic %s alert with %d topics", alert.AlertType, len(alert.Topics)))
+
+	post := p.poster.BuildTestPost(*alert, args.ChannelId)
+	pass(fmt.Sprintf("Formatted the alert post with %d fields: `%s`", countPostFields(post), post.Message))
+
+	canPost := p.API.HasPermissionToChannel(p.botID, args.ChannelId, model.PermissionCreatePost)
+	if canPost {
+		pass("The bot may post in this channel")
+	}
+
+	if preview {
+		p.API.SendEphemeralPost(args.UserId, post)
+		if !canPost {
+			return fail("The bot may not post in this channel. Add it to the channel before routing alerts here.")
+		}
+		pass("Showed the test alert as a preview only visible to you")
+		return strings.Join(report, "\n")
+	}
+
+	if !canPost {
+		return fail("The bot may not post in this channel. Add it to the channel and run the self-test again.")
+	}
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		return fail("Failed to post the test alert: " + appErr.Error())
+	}
+	pass("Posted the test alert to this channel")
+
+	return strings.Join(report, "\n")
+}
+
+// countPostFields returns the number of attachment fields of a post
+func countPostFields(post *model.Post) int {
+	count := 0
+	for _, attachment := range post.Attachments() {
+		count += len(attachment.Fields)
+	}
+	return count
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
+)
+
+// newSelfTestPlugin creates a plugin with a registered backend and a bot that may or may not
+// post in the test channel
+func newSelfTestPlugin(t *testing.T, canPost bool) (*Plugin, *plugintest.API) {
+	api := &plugintest.API{}
+	t.Cleanup(func() { api.AssertExpectations(t) })
+	api.On("HasPermissionToChannel", "bot-id", "channel-id", model.PermissionCreatePost).Return(canPost).Once()
+
+	p := newCommandTestPlugin(api)
+	p.botID = "bot-id"
+	p.poster = poster.New(api, "bot-id")
+	p.registry = backend.NewRegistry()
+	require.NoError(t, p.registry.Register(&fakeBackend{id: "backend-1", name: "Weather Watch"}))
+	return p, api
+}
+
+func TestExecuteSelfTest(t *testing.T) {
+	args := &model.CommandArgs{UserId: "user-id", ChannelId: "channel-id"}
+
+	t.Run("posts the test alert", func(t *testing.T) {
+		p, api := newSelfTestPlugin(t, true)
+
+		var posted *model.Post
+		api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+			posted = args.Get(0).(*model.Post)
+		}).Return(&model.Post{Id: "post-id"}, nil).Once()
+
+		text := p.executeSelfTest(args, []string{"weather", "watch"})
+		assert.Contains(t, text, "###### Self-test of Weather Watch")
+		assert.Contains(t, text, ":white_check_mark: Normalized a synthetic Flash alert with 2 topics")
+		assert.Contains(t, text, ":white_check_mark: Formatted the alert post with")
+		assert.Contains(t, text, ":white_check_mark: The bot may post in this channel")
+		assert.Contains(t, text, ":white_check_mark: Posted the test alert to this channel")
+		assert.NotContains(t, text, ":x:")
+
+		require.NotNil(t, posted)
+		assert.Equal(t, "bot-id", posted.UserId)
+		assert.Equal(t, "channel-id", posted.ChannelId)
+		assert.Equal(t, "backend-1", posted.GetProp(poster.BackendIDProp))
+		require.NotEmpty(t, posted.Attachments())
+		assert.Contains(t, posted.Attachments()[0].Text, "[Self-test]")
+	})
+
+	t.Run("previews the test alert", func(t *testing.T) {
+		p, api := newSelfTestPlugin(t, true)
+		api.On("SendEphemeralPost", "user-id", mock.AnythingOfType("*model.Post")).Return(&model.Post{}).Once()
+
+		text := p.executeSelfTest(args, []string{"backend-1", "preview"})
+		assert.Contains(t, text, ":white_check_mark: Showed the test alert as a preview only visible to you")
+		assert.NotContains(t, text, "Posted the test alert")
+	})
+
+	t.Run("bot can't post", func(t *testing.T) {
+		p, _ := newSelfTestPlugin(t, false)
+
+		text := p.executeSelfTest(args, []string{"Weather", "Watch"})
+		assert.Contains(t, text, ":x: The bot may not post in this channel")
+		assert.NotContains(t, text, "Posted the test alert")
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		p := newCommandTestPlugin(&plugintest.API{})
+		p.registry = backend.NewRegistry()
+
+		assert.Contains(t, p.executeSelfTest(args, nil), "Please specify a backend")
+		assert.Contains(t, p.executeSelfTest(args, []string{"preview"}), "Please specify a backend")
+		assert.Equal(t, "Backend `Other` not found.", p.executeSelfTest(args, []string{"Other"}))
+	})
+}
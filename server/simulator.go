@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend/dataminr"
+)
+
+// uploadSimulatorFixture handles PUT /api/v1/simulator/fixtures/{name}, storing the alerts in the
+// request body as a fixture that simulator backends can replay instead of the bundled fixture
+func (p *Plugin) uploadSimulatorFixture(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if err := backend.ValidateFixtureName(name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, dataminr.MaxSimulatorFixtureBytes+1))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(data) > dataminr.MaxSimulatorFixtureBytes {
+		http.Error(w, "Simulator fixture is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	alerts, err := dataminr.ParseSimulatorFixture(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if appErr := p.API.KVSet(dataminr.SimulatorFixtureKey(name), data); appErr != nil {
+		p.API.LogError("Failed to save simulator fixture", "name", name, "error", appErr.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	p.API.LogInfo("Uploaded simulator fixture", "name", name, "alerts", len(alerts), "userId", r.Header.Get("Mattermost-User-ID"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteSimulatorFixture handles DELETE /api/v1/simulator/fixtures/{name}
+func (p *Plugin) deleteSimulatorFixture(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if err := backend.ValidateFixtureName(name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if appErr := p.API.KVDelete(dataminr.SimulatorFixtureKey(name)); appErr != nil {
+		p.API.LogError("Failed to delete simulator fixture", "name", name, "error", appErr.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	p.API.LogInfo("Deleted simulator fixture", "name", name, "userId", r.Header.Get("Mattermost-User-ID"))
+	w.WriteHeader(http.StatusNoContent)
+}
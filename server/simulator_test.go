@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend/dataminr"
+)
+
+func TestSimulatorFixtures(t *testing.T) {
+	kv := make(map[string][]byte)
+	api := &plugintest.API{}
+	api.On("KVSet", mock.Anything, mock.Anything).Return(func(key string, value []byte) *model.AppError {
+		kv[key] = value
+		return nil
+	})
+	api.On("KVDelete", mock.Anything).Return(func(key string) *model.AppError {
+		delete(kv, key)
+		return nil
+	})
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	p := newCommandTestPlugin(api)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/simulator/fixtures/{name}", p.uploadSimulatorFixture).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/simulator/fixtures/{name}", p.deleteSimulatorFixture).Methods(http.MethodDelete)
+
+	request := func(method, name, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/api/v1/simulator/fixtures/"+name, strings.NewReader(body)))
+		return w
+	}
+
+	fixture := `[{"alertId":"qa-1","headline":"QA alert"}]`
+
+	t.Run("upload", func(t *testing.T) {
+		w := request(http.MethodPut, "qa", fixture)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, fixture, string(kv[dataminr.SimulatorFixtureKey("qa")]))
+	})
+
+	t.Run("invalid uploads", func(t *testing.T) {
+		w := request(http.MethodPut, "Not-Valid", fixture)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = request(http.MethodPut, "qa", `[]`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "contains no alerts")
+
+		w = request(http.MethodPut, "qa", strings.Repeat(" ", dataminr.MaxSimulatorFixtureBytes+1))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+		assert.Equal(t, fixture, string(kv[dataminr.SimulatorFixtureKey("qa")]))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		w := request(http.MethodDelete, "qa", "")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.NotContains(t, kv, dataminr.SimulatorFixtureKey("qa"))
+
+		w = request(http.MethodDelete, "Not-Valid", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestResolveAPIKey_Simulator(t *testing.T) {
+	p := newCommandTestPlugin(&plugintest.API{})
+
+	apiKey, err := p.resolveAPIKey(backend.Config{ID: "simulator-1", Type: backend.SimulatorType})
+	assert.NoError(t, err)
+	assert.Empty(t, apiKey)
+}
@@ -0,0 +1,117 @@
+// Package sqlstore applies the schema migrations of the plugin's tables in the Mattermost
+// database, for the stores kept in the database by the "sql" storage driver.
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// Migration is a schema change, with the statements applying it to each database driver
+type Migration struct {
+	Version  int
+	Postgres []string
+	MySQL    []string
+}
+
+// CheckDriver returns an error unless the database driver is supported ("postgres" or "mysql")
+func CheckDriver(driver string) error {
+	if driver != model.DatabaseDriverPostgres && driver != model.DatabaseDriverMysql {
+		return fmt.Errorf("unsupported database driver '%s'", driver)
+	}
+	return nil
+}
+
+// Migrate applies the migrations not recorded in the migrations table yet, each in a
+// transaction. Every store records its migrations in its own table, so their versions are
+// independent. Nodes of a cluster must not migrate concurrently.
+func Migrate(db *sql.DB, driver, migrationsTable string, migrations []Migration) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + migrationsTable + ` (version INTEGER NOT NULL PRIMARY KEY)`); err != nil {
+		return fmt.Errorf("failed to create schema migrations table: %w", err)
+	}
+
+	applied, err := appliedMigrations(db, migrationsTable)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+		if err := apply(db, driver, migrationsTable, m); err != nil {
+			return fmt.Errorf("failed to apply schema migration %d: %w", m.Version, err)
+		}
+	}
+	return nil
+}
+
+// appliedMigrations returns the versions recorded in a migrations table
+func appliedMigrations(db *sql.DB, migrationsTable string) (map[int]bool, error) {
+	rows, err := db.Query(`SELECT version FROM ` + migrationsTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to read schema migration: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get schema migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// apply runs the statements of a migration for the database driver and records it
+func apply(db *sql.DB, driver, migrationsTable string, m Migration) error {
+	statements := m.Postgres
+	if driver == model.DatabaseDriverMysql {
+		statements = m.MySQL
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(Rebind(driver, `INSERT INTO `+migrationsTable+` (version) VALUES (?)`), m.Version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Rebind replaces the ? placeholders of a statement with the numbered placeholders of
+// PostgreSQL. MySQL statements are returned unchanged.
+func Rebind(driver, statement string) string {
+	if driver != model.DatabaseDriverPostgres {
+		return statement
+	}
+
+	var sb strings.Builder
+	n := 0
+	for _, r := range statement {
+		if r != '?' {
+			sb.WriteRune(r)
+			continue
+		}
+		n++
+		sb.WriteString("$" + strconv.Itoa(n))
+	}
+	return sb.String()
+}
@@ -0,0 +1,76 @@
+package sqlstore
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/sqlstore/sqlstoretest"
+)
+
+var testMigrations = []Migration{
+	{
+		Version:  1,
+		Postgres: []string{"CREATE TABLE test_table (id BIGINT)"},
+		MySQL:    []string{"CREATE TABLE test_table (id BIGINT) ENGINE=InnoDB"},
+	},
+	{
+		Version:  2,
+		Postgres: []string{"CREATE INDEX idx_test ON test_table (id)"},
+		MySQL:    []string{"CREATE INDEX idx_test ON test_table (id)"},
+	},
+}
+
+func TestCheckDriver(t *testing.T) {
+	assert.NoError(t, CheckDriver("postgres"))
+	assert.NoError(t, CheckDriver("mysql"))
+
+	err := CheckDriver("sqlite3")
+	require.Error(t, err)
+	assert.Equal(t, "unsupported database driver 'sqlite3'", err.Error())
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("applies pending migrations", func(t *testing.T) {
+		fake, db := sqlstoretest.Open(t)
+
+		require.NoError(t, Migrate(db, "postgres", "test_migrations", testMigrations))
+
+		require.Len(t, fake.Execs, 5)
+		assert.Equal(t, "CREATE TABLE IF NOT EXISTS test_migrations (version INTEGER NOT NULL PRIMARY KEY)", fake.Execs[0].Query)
+		assert.Equal(t, "CREATE TABLE test_table (id BIGINT)", fake.Execs[1].Query)
+		assert.Equal(t, "INSERT INTO test_migrations (version) VALUES ($1)", fake.Execs[2].Query)
+		assert.Equal(t, []driver.Value{int64(1)}, fake.Execs[2].Args)
+		assert.Equal(t, []driver.Value{int64(2)}, fake.Execs[4].Args)
+		assert.Equal(t, 2, fake.Commits)
+	})
+
+	t.Run("skips applied migrations", func(t *testing.T) {
+		fake, db := sqlstoretest.Open(t)
+		fake.Rows["SELECT version FROM test_migrations"] = [][]driver.Value{{int64(1)}}
+
+		require.NoError(t, Migrate(db, "mysql", "test_migrations", testMigrations))
+
+		require.Len(t, fake.Execs, 3)
+		assert.Equal(t, "CREATE INDEX idx_test ON test_table (id)", fake.Execs[1].Query)
+		assert.Equal(t, "INSERT INTO test_migrations (version) VALUES (?)", fake.Execs[2].Query)
+		assert.Equal(t, 1, fake.Commits)
+	})
+
+	t.Run("uses the statements of the driver", func(t *testing.T) {
+		fake, db := sqlstoretest.Open(t)
+
+		require.NoError(t, Migrate(db, "mysql", "test_migrations", testMigrations[:1]))
+
+		require.Len(t, fake.Execs, 3)
+		assert.Contains(t, fake.Execs[1].Query, "ENGINE=InnoDB")
+	})
+}
+
+func TestRebind(t *testing.T) {
+	statement := "SELECT id FROM test_table WHERE a = ? AND b = ?"
+	assert.Equal(t, "SELECT id FROM test_table WHERE a = $1 AND b = $2", Rebind("postgres", statement))
+	assert.Equal(t, statement, Rebind("mysql", statement))
+}
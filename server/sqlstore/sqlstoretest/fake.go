@@ -0,0 +1,106 @@
+// Package sqlstoretest provides a fake database for testing the stores of package sqlstore.
+package sqlstoretest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Statement is a statement run on a FakeDatabase, with its arguments
+type Statement struct {
+	Query string
+	Args  []driver.Value
+}
+
+// FakeDatabase is a database/sql connector recording the statements run on it. Queries
+// return the rows registered under a key the query contains, or no rows.
+type FakeDatabase struct {
+	Execs   []Statement
+	Queries []Statement
+	Rows    map[string][][]driver.Value
+	Commits int
+
+	// RowsAffected is the result of every executed statement
+	RowsAffected int64
+}
+
+// Open creates a fake database and a database handle on it, closed when the test ends
+func Open(t *testing.T) (*FakeDatabase, *sql.DB) {
+	fake := &FakeDatabase{Rows: make(map[string][][]driver.Value)}
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() { _ = db.Close() })
+	return fake, db
+}
+
+func (f *FakeDatabase) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: f}, nil }
+func (f *FakeDatabase) Driver() driver.Driver                        { return nil }
+
+// fakeConn is a connection to a FakeDatabase
+type fakeConn struct {
+	db *FakeDatabase
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return &fakeTx{db: c.db}, nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.Execs = append(c.db.Execs, Statement{Query: query, Args: values(args)})
+	return driver.RowsAffected(c.db.RowsAffected), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.Queries = append(c.db.Queries, Statement{Query: query, Args: values(args)})
+	for key, rows := range c.db.Rows {
+		if strings.Contains(query, key) {
+			return &fakeRows{rows: rows}, nil
+		}
+	}
+	return &fakeRows{}, nil
+}
+
+// fakeTx is a transaction on a FakeDatabase
+type fakeTx struct {
+	db *FakeDatabase
+}
+
+func (t *fakeTx) Commit() error   { t.db.Commits++; return nil }
+func (t *fakeTx) Rollback() error { return nil }
+
+// fakeRows are the rows returned by a query on a FakeDatabase
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// values returns the values of named statement arguments
+func values(args []driver.NamedValue) []driver.Value {
+	result := make([]driver.Value, len(args))
+	for i, arg := range args {
+		result[i] = arg.Value
+	}
+	return result
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	// kvKeyStartupReportSent marks that a server node sent the startup report of the current
+	// activation, since every node of a cluster activates the plugin and publishes a report
+	kvKeyStartupReportSent = "startup_report_sent"
+
+	// startupReportWindow is how long after a node sent the startup report the reports of
+	// the other nodes activating the plugin are not sent
+	startupReportWindow = 5 * time.Minute
+)
+
+// Startup outcomes of a backend
+const (
+	startupStarted  = "started"
+	startupDisabled = "disabled"
+	startupFailed   = "failed"
+)
+
+// StartupResult is the outcome of creating and starting one backend
+type StartupResult struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// Outcome is "started", "disabled" or "failed"
+	Outcome string `json:"outcome"`
+
+	// Error explains why the backend failed to be created, registered or started
+	Error string `json:"error,omitempty"`
+}
+
+// StartupReport lists the outcome of every backend started when the plugin was activated
+type StartupReport struct {
+	// GeneratedAt is when the backends finished starting
+	GeneratedAt time.Time `json:"generatedAt"`
+
+	// Backends lists each backend's outcome in configuration order
+	Backends []StartupResult `json:"backends"`
+}
+
+// Count returns the number of backends with the given outcome
+func (r StartupReport) Count(outcome string) int {
+	count := 0
+	for _, result := range r.Backends {
+		if result.Outcome == outcome {
+			count++
+		}
+	}
+	return count
+}
+
+// GetStartupReport returns the report of the backends started on plugin activation
+// (the zero value before activation finished)
+func (p *Plugin) GetStartupReport() StartupReport {
+	p.startupReportLock.RLock()
+	defer p.startupReportLock.RUnlock()
+
+	return p.startupReport
+}
+
+// publishStartupReport stores the startup report, logs it and sends it to the users
+// subscribed to backend state changes, so failed startups don't go unnoticed. Only the first
+// server node activating the plugin within startupReportWindow sends its report.
+func (p *Plugin) publishStartupReport(report StartupReport) {
+	p.startupReportLock.Lock()
+	p.startupReport = report
+	p.startupReportLock.Unlock()
+
+	if len(report.Backends) == 0 {
+		return
+	}
+
+	started, disabled, failed := report.Count(startupStarted), report.Count(startupDisabled), report.Count(startupFailed)
+	if failed > 0 {
+		p.API.LogWarn("Some backends failed to start", "started", started, "disabled", disabled, "failed", failed)
+	} else {
+		p.API.LogInfo("All enabled backends started", "started", started, "disabled", disabled)
+	}
+
+	if p.statusNotifier != nil && p.claimStartupReport(report) {
+		p.statusNotifier.Notify(formatStartupReport(report))
+	}
+}
+
+// claimStartupReport records in the KV store that the startup report of this activation was
+// sent. Returns false if another server node already sent one within startupReportWindow. If the
+// claim can't be made the report is sent anyway, since a duplicate report is preferable to none.
+func (p *Plugin) claimStartupReport(report StartupReport) bool {
+	claimed, appErr := p.API.KVSetWithOptions(kvKeyStartupReportSent, []byte(report.GeneratedAt.UTC().Format(time.RFC3339)), model.PluginKVSetOptions{
+		Atomic:          true,
+		OldValue:        nil,
+		ExpireInSeconds: int64(startupReportWindow / time.Second),
+	})
+	if appErr != nil {
+		p.API.LogWarn("Failed to record the startup report, sending it anyway", "error", appErr.Error())
+		return true
+	}
+	return claimed
+}
+
+// formatStartupReport builds the direct message text of a startup report
+func formatStartupReport(report StartupReport) string {
+	failed := report.Count(startupFailed)
+
+	var sb strings.Builder
+	if failed > 0 {
+		sb.WriteString(fmt.Sprintf(":warning: **Dataminr plugin started: %d of %d backends failed to start**\n", failed, len(report.Backends)))
+	} else {
+		sb.WriteString(fmt.Sprintf(":white_check_mark: **Dataminr plugin started: %d of %d backends running**\n", report.Count(startupStarted), len(report.Backends)))
+	}
+
+	for _, result := range report.Backends {
+		switch result.Outcome {
+		case startupStarted:
+			sb.WriteString(fmt.Sprintf("- **%s**: started\n", result.Name))
+		case startupDisabled:
+			sb.WriteString(fmt.Sprintf("- **%s**: disabled\n", result.Name))
+		default:
+			sb.WriteString(fmt.Sprintf("- **%s**: failed: `%s`\n", result.Name, result.Error))
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// getStartupReport handles GET /api/v1/startup-report
+func (p *Plugin) getStartupReport(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(p.GetStartupReport()); err != nil {
+		p.API.LogError("Failed to encode startup report", "error", err.Error())
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestCreateAndStartBackend_Outcome(t *testing.T) {
+	backend.RegisterBackendFactory("startup-test", func(config backend.Config, _ *pluginapi.Client, _ plugin.API, _ backend.AlertPoster, _ backend.Deduplicator, _ backend.DisableCallback, _ string) (backend.Backend, error) {
+		return &fakeBackend{id: config.ID, name: config.Name}, nil
+	})
+
+	api := &plugintest.API{}
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	p := newCommandTestPlugin(api)
+	p.registry = backend.NewRegistry()
+
+	result := p.createAndStartBackend(backend.Config{ID: "backend-1", Name: "Weather Watch", Type: "startup-test", APIKey: "key", Enabled: true})
+	assert.Equal(t, StartupResult{ID: "backend-1", Name: "Weather Watch", Outcome: startupStarted}, result)
+
+	result = p.createAndStartBackend(backend.Config{ID: "backend-2", Name: "Cyber Watch", Type: "startup-test", APIKey: "key"})
+	assert.Equal(t, StartupResult{ID: "backend-2", Name: "Cyber Watch", Outcome: startupDisabled}, result)
+
+	result = p.createAndStartBackend(backend.Config{ID: "backend-3", Name: "Broken", Type: "unknown", APIKey: "key", Enabled: true})
+	assert.Equal(t, StartupResult{ID: "backend-3", Name: "Broken", Outcome: startupFailed, Error: "failed to create backend: unknown backend type: unknown"}, result)
+
+	result = p.createAndStartBackend(backend.Config{ID: "backend-1", Name: "Duplicate", Type: "startup-test", APIKey: "key", Enabled: true})
+	assert.Equal(t, startupFailed, result.Outcome)
+	assert.Contains(t, result.Error, "failed to register backend")
+}
+
+func TestFormatStartupReport(t *testing.T) {
+	report := StartupReport{Backends: []StartupResult{
+		{ID: "backend-1", Name: "Weather Watch", Outcome: startupStarted},
+		{ID: "backend-2", Name: "Cyber Watch", Outcome: startupDisabled},
+	}}
+	assert.Equal(t, ":white_check_mark: **Dataminr plugin started: 1 of 2 backends running**\n"+
+		"- **Weather Watch**: started\n"+
+		"- **Cyber Watch**: disabled", formatStartupReport(report))
+
+	report.Backends = append(report.Backends, StartupResult{ID: "backend-3", Name: "Broken", Outcome: startupFailed, Error: "failed to create backend: API key is required"})
+	assert.Equal(t, ":warning: **Dataminr plugin started: 1 of 3 backends failed to start**\n"+
+		"- **Weather Watch**: started\n"+
+		"- **Cyber Watch**: disabled\n"+
+		"- **Broken**: failed: `failed to create backend: API key is required`", formatStartupReport(report))
+}
+
+func TestPublishStartupReport(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	p := newCommandTestPlugin(api)
+	p.statusNotifier = NewStatusNotifier(api, "bot-id", backend.NewRegistry())
+
+	report := StartupReport{
+		GeneratedAt: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		Backends: []StartupResult{
+			{ID: "backend-1", Name: "Weather Watch", Outcome: startupStarted},
+			{ID: "backend-2", Name: "Broken", Outcome: startupFailed, Error: "failed to start backend: boom"},
+		},
+	}
+
+	api.On("LogWarn", "Some backends failed to start", "started", 1, "disabled", 0, "failed", 1).Once()
+	api.On("KVSetWithOptions", kvKeyStartupReportSent, []byte("2026-10-16T09:00:00Z"), model.PluginKVSetOptions{
+		Atomic:          true,
+		ExpireInSeconds: int64(startupReportWindow / time.Second),
+	}).Return(true, nil).Once()
+	api.On("KVGet", kvKeyStatusSubscribers).Return([]byte(`["admin-1"]`), nil)
+	api.On("GetDirectChannel", "admin-1", "bot-id").Return(&model.Channel{Id: "dm-1"}, nil)
+	var posted *model.Post
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		posted = args.Get(0).(*model.Post)
+	}).Return(&model.Post{}, nil).Once()
+
+	p.publishStartupReport(report)
+
+	require.NotNil(t, posted)
+	assert.Equal(t, "dm-1", posted.ChannelId)
+	assert.Equal(t, formatStartupReport(report), posted.Message)
+	assert.Equal(t, report, p.GetStartupReport())
+
+	// The report is served to system admins
+	w := httptest.NewRecorder()
+	p.getStartupReport(w, httptest.NewRequest(http.MethodGet, "/api/v1/startup-report", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var served StartupReport
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&served))
+	assert.Equal(t, report, served)
+}
+
+func TestPublishStartupReport_SentByAnotherNode(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	p := newCommandTestPlugin(api)
+	p.statusNotifier = NewStatusNotifier(api, "bot-id", backend.NewRegistry())
+
+	report := StartupReport{
+		GeneratedAt: time.Date(2026, 10, 16, 9, 0, 5, 0, time.UTC),
+		Backends:    []StartupResult{{ID: "backend-1", Name: "Weather Watch", Outcome: startupStarted}},
+	}
+
+	// The node still logs and serves its own report, but doesn't send it again
+	api.On("LogInfo", "All enabled backends started", "started", 1, "disabled", 0).Once()
+	api.On("KVSetWithOptions", kvKeyStartupReportSent, mock.Anything, mock.Anything).Return(false, nil).Once()
+
+	p.publishStartupReport(report)
+	assert.Equal(t, report, p.GetStartupReport())
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+}
+
+func TestPublishStartupReport_NoBackends(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	p := newCommandTestPlugin(api)
+	p.statusNotifier = NewStatusNotifier(api, "bot-id", backend.NewRegistry())
+
+	p.publishStartupReport(StartupReport{Backends: []StartupResult{}})
+	assert.Empty(t, p.GetStartupReport().Backends)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+)
+
+const (
+	// defaultStatsWindow is the period covered by /dataminr stats without an argument
+	defaultStatsWindow = 24 * time.Hour
+
+	// maxStatsItems is how many topics, locations and hours are listed in a ranking
+	maxStatsItems = 5
+)
+
+// statsCount is a ranked value with its number of alerts
+type statsCount struct {
+	Name  string
+	Count int
+}
+
+// alertStats summarizes the alerts posted within a period
+type alertStats struct {
+	Total     int
+	ByType    []statsCount
+	Topics    []statsCount
+	Locations []statsCount
+	Hours     []statsCount
+}
+
+// computeStats counts alerts by type, topic, location and posting hour (UTC).
+// Rankings are sorted by count, then by name.
+func computeStats(entries []alertindex.Entry) alertStats {
+	types := make(map[string]int)
+	topics := make(map[string]int)
+	locations := make(map[string]int)
+	hours := make(map[string]int)
+
+	for _, entry := range entries {
+		alertType := entry.AlertType
+		if alertType == "" {
+			alertType = "Unknown"
+		}
+		types[alertType]++
+
+		for _, topic := range entry.Topics {
+			topics[topic]++
+		}
+		if entry.Location != "" {
+			locations[entry.Location]++
+		}
+
+		hour := entry.PostedAt.UTC().Hour()
+		hours[fmt.Sprintf("%02d:00-%02d:00", hour, (hour+1)%24)]++
+	}
+
+	return alertStats{
+		Total:     len(entries),
+		ByType:    rankCounts(types, 0),
+		Topics:    rankCounts(topics, maxStatsItems),
+		Locations: rankCounts(locations, maxStatsItems),
+		Hours:     rankCounts(hours, 3),
+	}
+}
+
+// rankCounts sorts counts in descending order and keeps at most limit entries (0 keeps all)
+func rankCounts(counts map[string]int, limit int) []statsCount {
+	ranked := make([]statsCount, 0, len(counts))
+	for name, count := range counts {
+		ranked = append(ranked, statsCount{Name: name, Count: count})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// formatStats renders alert statistics as markdown under the given title
+func formatStats(title string, stats alertStats) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("###### %s\n", title))
+
+	if stats.Total == 0 {
+		sb.WriteString("No alerts were posted in this period.\n")
+		return sb.String()
+	}
+
+	typeCounts := make([]string, 0, len(stats.ByType))
+	for _, c := range stats.ByType {
+		typeCounts = append(typeCounts, fmt.Sprintf("%s: %d", c.Name, c.Count))
+	}
+	sb.WriteString(fmt.Sprintf("**Total alerts:** %d (%s)\n", stats.Total, strings.Join(typeCounts, ", ")))
+
+	writeRanking(&sb, "Top topics", stats.Topics)
+	writeRanking(&sb, "Top locations", stats.Locations)
+	writeRanking(&sb, "Busiest hours (UTC)", stats.Hours)
+
+	return sb.String()
+}
+
+// writeRanking appends a ranked list, skipping empty rankings
+func writeRanking(sb *strings.Builder, title string, counts []statsCount) {
+	if len(counts) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("\n**%s**\n", title))
+	for _, c := range counts {
+		sb.WriteString(fmt.Sprintf("* %s (%d)\n", c.Name, c.Count))
+	}
+}
+
+// parseStatsWindow parses the optional /dataminr stats period (e.g., 24h or 7d).
+// The period is limited to how long alerts are kept in the index.
+func parseStatsWindow(params []string) (time.Duration, error) {
+	if len(params) == 0 {
+		return defaultStatsWindow, nil
+	}
+	if len(params) > 1 {
+		return 0, errors.New("expected a single period, e.g. `24h` or `7d`")
+	}
+
+	window, err := parseDurationParam(params[0])
+	if err != nil {
+		return 0, err
+	}
+	if window > alertindex.Retention {
+		return 0, errors.Errorf("period `%s` exceeds the %d day alert history", params[0], int(alertindex.Retention.Hours()/24))
+	}
+	return window, nil
+}
+
+// executeStats handles /dataminr stats
+func (p *Plugin) executeStats(args *model.CommandArgs, params []string) string {
+	window, err := parseStatsWindow(params)
+	if err != nil {
+		return fmt.Sprintf("Invalid period: %s.", err.Error())
+	}
+
+	entries, err := p.alertIndex.Search(alertindex.Query{Since: time.Now().Add(-window)})
+	if err != nil {
+		p.API.LogError("Failed to load alert history for statistics", "userId", args.UserId, "error", err.Error())
+		return "Failed to load alert statistics."
+	}
+
+	period := "24h"
+	if len(params) > 0 {
+		period = params[0]
+	}
+
+	// Only count alerts posted in channels the user can read
+	stats := computeStats(p.filterReadable(args.UserId, entries))
+	return formatStats(fmt.Sprintf("Alert statistics for the last %s", period), stats)
+}
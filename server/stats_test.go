@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+)
+
+func statsTestEntries() []alertindex.Entry {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	return []alertindex.Entry{
+		{AlertType: "Flash", Topics: []string{"Fire", "Explosion"}, Location: "Paris, France", ChannelID: "channel-1", PostedAt: day.Add(9 * time.Hour)},
+		{AlertType: "Urgent", Topics: []string{"Fire"}, Location: "Paris, France", ChannelID: "channel-1", PostedAt: day.Add(9*time.Hour + 30*time.Minute)},
+		{AlertType: "Alert", Topics: []string{"Flood"}, Location: "Lyon, France", ChannelID: "channel-2", PostedAt: day.Add(14 * time.Hour)},
+		{AlertType: "Urgent", ChannelID: "channel-1", PostedAt: day.Add(23 * time.Hour)},
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	stats := computeStats(statsTestEntries())
+
+	assert.Equal(t, 4, stats.Total)
+	assert.Equal(t, []statsCount{{"Urgent", 2}, {"Alert", 1}, {"Flash", 1}}, stats.ByType)
+	assert.Equal(t, []statsCount{{"Fire", 2}, {"Explosion", 1}, {"Flood", 1}}, stats.Topics)
+	assert.Equal(t, []statsCount{{"Paris, France", 2}, {"Lyon, France", 1}}, stats.Locations)
+	assert.Equal(t, []statsCount{{"09:00-10:00", 2}, {"14:00-15:00", 1}, {"23:00-00:00", 1}}, stats.Hours)
+}
+
+func TestFormatStats(t *testing.T) {
+	t.Run("with alerts", func(t *testing.T) {
+		text := formatStats("Alert statistics for the last 24h", computeStats(statsTestEntries()))
+		assert.Contains(t, text, "###### Alert statistics for the last 24h")
+		assert.Contains(t, text, "**Total alerts:** 4 (Urgent: 2, Alert: 1, Flash: 1)")
+		assert.Contains(t, text, "**Top topics**\n* Fire (2)\n")
+		assert.Contains(t, text, "**Top locations**\n* Paris, France (2)\n")
+		assert.Contains(t, text, "**Busiest hours (UTC)**\n* 09:00-10:00 (2)\n")
+	})
+
+	t.Run("without alerts", func(t *testing.T) {
+		text := formatStats("Daily alert summary for the last 24h", computeStats(nil))
+		assert.Contains(t, text, "No alerts were posted in this period.")
+		assert.NotContains(t, text, "Top topics")
+	})
+}
+
+func TestParseStatsWindow(t *testing.T) {
+	window, err := parseStatsWindow(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, window)
+
+	window, err = parseStatsWindow([]string{"7d"})
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, window)
+
+	_, err = parseStatsWindow([]string{"30d"})
+	assert.EqualError(t, err, "period `30d` exceeds the 7 day alert history")
+
+	_, err = parseStatsWindow([]string{"24h", "7d"})
+	assert.Error(t, err)
+}
+
+func TestExecuteStats(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	postedAt := time.Now().Add(-time.Hour)
+	entries := []alertindex.Entry{
+		{AlertType: "Flash", Topics: []string{"Fire"}, ChannelID: "channel-1", PostedAt: postedAt},
+		{AlertType: "Urgent", Topics: []string{"Restricted"}, ChannelID: "channel-2", PostedAt: postedAt},
+	}
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		if key == "alert_index_"+postedAt.UTC().Format("2006010215") {
+			return data, nil
+		}
+		return nil, nil
+	})
+	api.On("HasPermissionToChannel", "user-id", "channel-1", model.PermissionReadChannel).Return(true).Once()
+	api.On("HasPermissionToChannel", "user-id", "channel-2", model.PermissionReadChannel).Return(false).Once()
+
+	p := newCommandTestPlugin(api)
+	p.alertIndex = alertindex.New(api)
+
+	text := p.executeStats(&model.CommandArgs{UserId: "user-id"}, nil)
+	assert.Contains(t, text, "Alert statistics for the last 24h")
+	assert.Contains(t, text, "**Total alerts:** 1 (Flash: 1)")
+	assert.NotContains(t, text, "Restricted")
+
+	assert.Contains(t, p.executeStats(&model.CommandArgs{UserId: "user-id"}, []string{"forever"}), "Invalid period")
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// kvKeyStatusSubscribers stores the user IDs subscribed to backend state changes
+	kvKeyStatusSubscribers = "status_subscribers"
+
+	// kvKeyBackendStates stores the last observed state of each backend
+	kvKeyBackendStates = "status_backend_states"
+
+	// statusCheckInterval is how often backend states are checked for transitions
+	statusCheckInterval = time.Minute
+
+	// statusNotifierJobID is the cluster job ID for the state check
+	statusNotifierJobID = "dataminr_status_notifier"
+)
+
+// backendState is the coarse health state of a backend used for change notifications
+type backendState string
+
+const (
+	stateHealthy  backendState = "healthy"
+	stateFailing  backendState = "failing"
+	stateDisabled backendState = "disabled"
+)
+
+// classifyStatus maps a backend status to its coarse health state
+func classifyStatus(status backend.Status) backendState {
+	if !status.Enabled {
+		return stateDisabled
+	}
+	if status.ConsecutiveFailures > 0 {
+		return stateFailing
+	}
+	return stateHealthy
+}
+
+// StatusNotifier watches backend states and sends a direct message from the bot to
+// subscribed users whenever a backend transitions between healthy, failing and disabled.
+// Previous states are kept in the KV store so the check can run on any cluster node.
+type StatusNotifier struct {
+	api      plugin.API
+	botID    string
+	registry *backend.Registry
+	mu       sync.Mutex
+	job      *cluster.Job
+}
+
+// NewStatusNotifier creates a new status notifier
+func NewStatusNotifier(api plugin.API, botID string, registry *backend.Registry) *StatusNotifier {
+	return &StatusNotifier{
+		api:      api,
+		botID:    botID,
+		registry: registry,
+	}
+}
+
+// Start schedules the periodic cluster-aware state check
+func (n *StatusNotifier) Start() error {
+	job, err := cluster.Schedule(n.api, statusNotifierJobID, cluster.MakeWaitForInterval(statusCheckInterval), n.check)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule status notifier job")
+	}
+
+	n.job = job
+	return nil
+}
+
+// Stop cancels the periodic state check
+func (n *StatusNotifier) Stop() {
+	if n.job == nil {
+		return
+	}
+
+	if err := n.job.Close(); err != nil {
+		n.api.LogWarn("Failed to close status notifier job", "error", err.Error())
+	}
+	n.job = nil
+}
+
+// Subscribe adds a user to the subscriber list.
+// Returns false if the user was already subscribed.
+func (n *StatusNotifier) Subscribe(userID string) (bool, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	subscribers, err := n.getSubscribers()
+	if err != nil {
+		return false, err
+	}
+
+	for _, id := range subscribers {
+		if id == userID {
+			return false, nil
+		}
+	}
+
+	return true, n.saveSubscribers(append(subscribers, userID))
+}
+
+// Unsubscribe removes a user from the subscriber list.
+// Returns false if the user was not subscribed.
+func (n *StatusNotifier) Unsubscribe(userID string) (bool, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	subscribers, err := n.getSubscribers()
+	if err != nil {
+		return false, err
+	}
+
+	remaining := make([]string, 0, len(subscribers))
+	for _, id := range subscribers {
+		if id != userID {
+			remaining = append(remaining, id)
+		}
+	}
+
+	if len(remaining) == len(subscribers) {
+		return false, nil
+	}
+
+	return true, n.saveSubscribers(remaining)
+}
+
+// check compares the current state of every registered backend with the last observed
+// state and notifies subscribers about transitions
+func (n *StatusNotifier) check() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	previous, err := n.getStates()
+	if err != nil {
+		n.api.LogError("Failed to load previous backend states", "error", err.Error())
+		return
+	}
+
+	current := make(map[string]backendState)
+	var messages []string
+
+	for _, b := range n.registry.List() {
+		status := b.GetStatus()
+		state := classifyStatus(status)
+		current[b.GetID()] = state
+
+		// Backends seen for the first time are recorded without notifying
+		prevState, seen := previous[b.GetID()]
+		if !seen || prevState == state {
+			continue
+		}
+
+		messages = append(messages, describeTransition(b.GetName(), prevState, state, status))
+	}
+
+	if err := n.saveStates(current); err != nil {
+		n.api.LogError("Failed to save backend states", "error", err.Error())
+	}
+
+	if len(messages) == 0 {
+		return
+	}
+
+	subscribers, err := n.getSubscribers()
+	if err != nil {
+		n.api.LogError("Failed to load status subscribers", "error", err.Error())
+		return
+	}
+
+	for _, message := range messages {
+		for _, userID := range subscribers {
+			n.sendDirectMessage(userID, message)
+		}
+	}
+}
+
+// Notify sends a message to every subscribed user
+func (n *StatusNotifier) Notify(message string) {
+	subscribers, err := n.getSubscribers()
+	if err != nil {
+		n.api.LogError("Failed to load status subscribers", "error", err.Error())
+		return
+	}
+
+	for _, userID := range subscribers {
+		n.sendDirectMessage(userID, message)
+	}
+}
+
+// describeTransition builds the notification text for a state change
+func describeTransition(name string, from, to backendState, status backend.Status) string {
+	var message string
+	switch {
+	case to == stateFailing:
+		message = fmt.Sprintf(":warning: Backend **%s** is failing (%d consecutive failures)", name, status.ConsecutiveFailures)
+	case to == stateDisabled:
+		message = fmt.Sprintf(":no_entry: Backend **%s** has been disabled", name)
+	case from == stateDisabled:
+		message = fmt.Sprintf(":white_check_mark: Backend **%s** has been re-enabled", name)
+	default:
+		message = fmt.Sprintf(":white_check_mark: Backend **%s** has recovered", name)
+	}
+
+	if to != stateHealthy && status.LastError != "" {
+		message += fmt.Sprintf("\nLast error: `%s`", status.LastError)
+	}
+
+	return message
+}
+
+// sendDirectMessage posts a message from the bot to a user's direct message channel
+func (n *StatusNotifier) sendDirectMessage(userID, message string) {
+	channel, appErr := n.api.GetDirectChannel(userID, n.botID)
+	if appErr != nil {
+		n.api.LogError("Failed to get direct channel for status notification", "userId", userID, "error", appErr.Error())
+		return
+	}
+
+	if _, appErr := n.api.CreatePost(&model.Post{
+		UserId:    n.botID,
+		ChannelId: channel.Id,
+		Message:   message,
+	}); appErr != nil {
+		n.api.LogError("Failed to send status notification", "userId", userID, "error", appErr.Error())
+	}
+}
+
+// getSubscribers loads the subscribed user IDs from the KV store
+func (n *StatusNotifier) getSubscribers() ([]string, error) {
+	data, appErr := n.api.KVGet(kvKeyStatusSubscribers)
+	if appErr != nil {
+		return nil, errors.Wrap(appErr, "failed to get status subscribers")
+	}
+
+	subscribers := []string{}
+	if data == nil {
+		return subscribers, nil
+	}
+
+	if err := json.Unmarshal(data, &subscribers); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal status subscribers")
+	}
+
+	return subscribers, nil
+}
+
+// saveSubscribers stores the subscribed user IDs in the KV store
+func (n *StatusNotifier) saveSubscribers(subscribers []string) error {
+	data, err := json.Marshal(subscribers)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal status subscribers")
+	}
+
+	if appErr := n.api.KVSet(kvKeyStatusSubscribers, data); appErr != nil {
+		return errors.Wrap(appErr, "failed to save status subscribers")
+	}
+
+	return nil
+}
+
+// getStates loads the last observed backend states from the KV store
+func (n *StatusNotifier) getStates() (map[string]backendState, error) {
+	data, appErr := n.api.KVGet(kvKeyBackendStates)
+	if appErr != nil {
+		return nil, errors.Wrap(appErr, "failed to get backend states")
+	}
+
+	states := make(map[string]backendState)
+	if data == nil {
+		return states, nil
+	}
+
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal backend states")
+	}
+
+	return states, nil
+}
+
+// saveStates stores the observed backend states in the KV store
+func (n *StatusNotifier) saveStates(states map[string]backendState) error {
+	data, err := json.Marshal(states)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal backend states")
+	}
+
+	if appErr := n.api.KVSet(kvKeyBackendStates, data); appErr != nil {
+		return errors.Wrap(appErr, "failed to save backend states")
+	}
+
+	return nil
+}
+
+// executeSubscribeStatus handles /dataminr subscribe-status
+func (p *Plugin) executeSubscribeStatus(args *model.CommandArgs, _ []string) string {
+	added, err := p.statusNotifier.Subscribe(args.UserId)
+	if err != nil {
+		p.API.LogError("Failed to subscribe to status changes", "userId", args.UserId, "error", err.Error())
+		return "Failed to subscribe to backend status changes."
+	}
+
+	if !added {
+		return "You are already subscribed to backend status changes."
+	}
+	return "You will now receive a direct message whenever a backend changes state."
+}
+
+// executeUnsubscribeStatus handles /dataminr unsubscribe-status
+func (p *Plugin) executeUnsubscribeStatus(args *model.CommandArgs, _ []string) string {
+	removed, err := p.statusNotifier.Unsubscribe(args.UserId)
+	if err != nil {
+		p.API.LogError("Failed to unsubscribe from status changes", "userId", args.UserId, "error", err.Error())
+		return "Failed to unsubscribe from backend status changes."
+	}
+
+	if !removed {
+		return "You are not subscribed to backend status changes."
+	}
+	return "You will no longer receive backend status change messages."
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// fakeBackend is a minimal backend.Backend implementation for plugin-level tests
+type fakeBackend struct {
+	id      string
+	name    string
+	status  backend.Status
+	history []backend.PollSample
+	logs    []backend.LogEntry
+	state   backend.StateSnapshot
+	resets  []backend.StateResetScope
+
+	taxonomy    backend.Taxonomy
+	taxonomyErr error
+
+	credentials    backend.CredentialCheck
+	credentialsErr error
+
+	updates   []backend.Config
+	updateErr error
+}
+
+func (f *fakeBackend) Start(context.Context) error  { return nil }
+func (f *fakeBackend) Stop(context.Context) error   { return nil }
+func (f *fakeBackend) GetID() string                { return f.id }
+func (f *fakeBackend) GetName() string              { return f.name }
+func (f *fakeBackend) GetType() string              { return "dataminr" }
+func (f *fakeBackend) GetStatus() backend.Status    { return f.status }
+func (f *fakeBackend) ClearOperationalState() error { return nil }
+
+func (f *fakeBackend) UpdateConfig(_ context.Context, config backend.Config) error {
+	if f.updateErr != nil {
+		return f.updateErr
+	}
+	f.updates = append(f.updates, config)
+	f.name = config.Name
+	return nil
+}
+
+func (f *fakeBackend) Pause(until time.Time) error {
+	f.status.Paused = true
+	f.status.PausedUntil = until
+	return nil
+}
+
+func (f *fakeBackend) Resume() error {
+	f.status.Paused = false
+	f.status.PausedUntil = time.Time{}
+	return nil
+}
+
+func (f *fakeBackend) GetPollHistory() ([]backend.PollSample, error) {
+	return f.history, nil
+}
+
+func (f *fakeBackend) GetRecentLogs() []backend.LogEntry {
+	return f.logs
+}
+
+func (f *fakeBackend) FetchTaxonomy(context.Context) (backend.Taxonomy, error) {
+	return f.taxonomy, f.taxonomyErr
+}
+
+func (f *fakeBackend) CheckCredentials(context.Context) (backend.CredentialCheck, error) {
+	return f.credentials, f.credentialsErr
+}
+
+func (f *fakeBackend) GetState() (backend.StateSnapshot, error) {
+	return f.state, nil
+}
+
+func (f *fakeBackend) ResetState(scope backend.StateResetScope) error {
+	f.resets = append(f.resets, scope)
+	return nil
+}
+
+func TestClassifyStatus(t *testing.T) {
+	assert.Equal(t, stateDisabled, classifyStatus(backend.Status{Enabled: false, ConsecutiveFailures: 3}))
+	assert.Equal(t, stateFailing, classifyStatus(backend.Status{Enabled: true, ConsecutiveFailures: 1}))
+	assert.Equal(t, stateHealthy, classifyStatus(backend.Status{Enabled: true}))
+}
+
+func TestDescribeTransition(t *testing.T) {
+	failing := describeTransition("Prod", stateHealthy, stateFailing, backend.Status{Enabled: true, ConsecutiveFailures: 2, LastError: "timeout"})
+	assert.Contains(t, failing, "**Prod** is failing (2 consecutive failures)")
+	assert.Contains(t, failing, "timeout")
+
+	disabled := describeTransition("Prod", stateFailing, stateDisabled, backend.Status{})
+	assert.Contains(t, disabled, "has been disabled")
+
+	reenabled := describeTransition("Prod", stateDisabled, stateHealthy, backend.Status{Enabled: true, LastError: "old"})
+	assert.Contains(t, reenabled, "has been re-enabled")
+	assert.NotContains(t, reenabled, "old", "Healthy transitions should not include errors")
+
+	recovered := describeTransition("Prod", stateFailing, stateHealthy, backend.Status{Enabled: true})
+	assert.Contains(t, recovered, "has recovered")
+}
+
+func TestStatusNotifier_Subscribe(t *testing.T) {
+	t.Run("adds new subscriber", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("KVGet", kvKeyStatusSubscribers).Return([]byte(`["user-1"]`), nil)
+		api.On("KVSet", kvKeyStatusSubscribers, []byte(`["user-1","user-2"]`)).Return(nil)
+
+		notifier := NewStatusNotifier(api, "bot-id", backend.NewRegistry())
+		added, err := notifier.Subscribe("user-2")
+		require.NoError(t, err)
+		assert.True(t, added)
+	})
+
+	t.Run("existing subscriber is not added twice", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("KVGet", kvKeyStatusSubscribers).Return([]byte(`["user-1"]`), nil)
+
+		notifier := NewStatusNotifier(api, "bot-id", backend.NewRegistry())
+		added, err := notifier.Subscribe("user-1")
+		require.NoError(t, err)
+		assert.False(t, added)
+	})
+
+	t.Run("removes subscriber", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("KVGet", kvKeyStatusSubscribers).Return([]byte(`["user-1","user-2"]`), nil)
+		api.On("KVSet", kvKeyStatusSubscribers, []byte(`["user-2"]`)).Return(nil)
+
+		notifier := NewStatusNotifier(api, "bot-id", backend.NewRegistry())
+		removed, err := notifier.Unsubscribe("user-1")
+		require.NoError(t, err)
+		assert.True(t, removed)
+	})
+}
+
+func TestStatusNotifier_Check(t *testing.T) {
+	t.Run("notifies subscribers about transitions", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		registry := backend.NewRegistry()
+		require.NoError(t, registry.Register(&fakeBackend{id: "b1", name: "Prod", status: backend.Status{Enabled: true, ConsecutiveFailures: 1}}))
+		require.NoError(t, registry.Register(&fakeBackend{id: "b2", name: "Staging", status: backend.Status{Enabled: true}}))
+		require.NoError(t, registry.Register(&fakeBackend{id: "b3", name: "New", status: backend.Status{Enabled: true}}))
+
+		previous, _ := json.Marshal(map[string]backendState{"b1": stateHealthy, "b2": stateHealthy})
+		api.On("KVGet", kvKeyBackendStates).Return(previous, nil)
+		api.On("KVSet", kvKeyBackendStates, mock.MatchedBy(func(data []byte) bool {
+			var saved map[string]backendState
+			require.NoError(t, json.Unmarshal(data, &saved))
+			return saved["b1"] == stateFailing && saved["b2"] == stateHealthy && saved["b3"] == stateHealthy
+		})).Return(nil)
+		api.On("KVGet", kvKeyStatusSubscribers).Return([]byte(`["admin-1"]`), nil)
+		api.On("GetDirectChannel", "admin-1", "bot-id").Return(&model.Channel{Id: "dm-channel"}, nil).Once()
+		api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+			return post.ChannelId == "dm-channel" && post.UserId == "bot-id"
+		})).Return(&model.Post{}, nil).Once()
+
+		notifier := NewStatusNotifier(api, "bot-id", registry)
+		notifier.check()
+	})
+
+	t.Run("no notifications without transitions", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		registry := backend.NewRegistry()
+		require.NoError(t, registry.Register(&fakeBackend{id: "b1", name: "Prod", status: backend.Status{Enabled: true}}))
+
+		previous, _ := json.Marshal(map[string]backendState{"b1": stateHealthy})
+		api.On("KVGet", kvKeyBackendStates).Return(previous, nil)
+		api.On("KVSet", kvKeyBackendStates, mock.Anything).Return(nil)
+
+		notifier := NewStatusNotifier(api, "bot-id", registry)
+		notifier.check()
+	})
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/audit"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/pollhistory"
+)
+
+const (
+	// storageDriverKV stores the alert index, audit log and poll history in the plugin KV
+	// store (the default)
+	storageDriverKV = "kv"
+
+	// storageDriverSQL stores the alert index, audit log and poll history in tables of the
+	// Mattermost database
+	storageDriverSQL = "sql"
+
+	// sqlMigrationMutexKey is the cluster mutex held while migrating the database schema, so
+	// nodes activating together don't migrate concurrently
+	sqlMigrationMutexKey = "dataminr_sql_migration"
+
+	// storagePruneJobID is the cluster job ID for deleting the rows of the stores in the
+	// database past their retention period
+	storagePruneJobID = "dataminr_storage_prune"
+
+	// storagePruneInterval is how often rows past the retention period are deleted
+	storagePruneInterval = time.Hour
+)
+
+// validateStorageDriver checks the storage driver setting (empty uses the KV store)
+func validateStorageDriver(driver string) error {
+	switch driver {
+	case "", storageDriverKV, storageDriverSQL:
+		return nil
+	default:
+		return errors.Errorf("storage driver must be '%s' or '%s' (got '%s')", storageDriverKV, storageDriverSQL, driver)
+	}
+}
+
+// openStorage opens the alert index, audit log and poll history of a storage driver. When the
+// SQL driver can't use the database, activation fails rather than falling back to the KV
+// store, so all nodes of a cluster record to and search the same storage.
+func (p *Plugin) openStorage(driver string) error {
+	p.storagePruner = nil
+	if driver != storageDriverSQL {
+		p.alertIndex = alertindex.New(p.API)
+		p.auditLog = audit.New(p.API)
+		p.pollHistory = nil
+		return nil
+	}
+
+	db, err := p.client.Store.GetMasterDB()
+	if err != nil {
+		return errors.Wrap(err, "failed to get database")
+	}
+	driverName := p.client.Store.DriverName()
+
+	index, err := alertindex.NewSQL(db, driverName)
+	if err != nil {
+		return err
+	}
+	auditLog, err := audit.NewSQL(db, driverName)
+	if err != nil {
+		return err
+	}
+	pollHistory, err := pollhistory.NewSQL(db, driverName)
+	if err != nil {
+		return err
+	}
+
+	mutex, err := cluster.NewMutex(p.API, sqlMigrationMutexKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to create schema migration mutex")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for _, migrate := range []func() error{index.Migrate, auditLog.Migrate, pollHistory.Migrate} {
+		if err := migrate(); err != nil {
+			return errors.Wrap(err, "failed to migrate database schema")
+		}
+	}
+
+	p.alertIndex = index
+	p.auditLog = auditLog
+	p.pollHistory = pollHistory
+	p.storagePruner = NewStoragePruner(p.API, []prunableStore{
+		{name: "alert index", retention: alertindex.Retention, prune: index.Prune},
+		{name: "audit log", retention: audit.Retention, prune: auditLog.Prune},
+		{name: "poll history", retention: backend.PollHistoryRetention, prune: pollHistory.Prune},
+	})
+	p.API.LogInfo("Alert index, audit log and poll history stored in the database", "driver", driverName)
+	return nil
+}
+
+// prunableStore is a store in the database whose rows past the retention period are deleted
+// by the StoragePruner
+type prunableStore struct {
+	name      string
+	retention time.Duration
+	prune     func(before time.Time) (int64, error)
+}
+
+// StoragePruner periodically deletes the rows of the stores in the database past their
+// retention period, which unlike the KV buckets don't expire on their own. It runs as a
+// cluster job so rows are deleted by a single node.
+type StoragePruner struct {
+	api    plugin.API
+	stores []prunableStore
+	job    *cluster.Job
+	now    func() time.Time
+}
+
+// NewStoragePruner creates a prune job for stores in the database
+func NewStoragePruner(api plugin.API, stores []prunableStore) *StoragePruner {
+	return &StoragePruner{
+		api:    api,
+		stores: stores,
+		now:    time.Now,
+	}
+}
+
+// Start schedules the periodic cluster-aware pruning of the stores
+func (r *StoragePruner) Start() error {
+	job, err := cluster.Schedule(r.api, storagePruneJobID, cluster.MakeWaitForInterval(storagePruneInterval), r.run)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule storage prune job")
+	}
+
+	r.job = job
+	return nil
+}
+
+// Stop cancels the storage prune job
+func (r *StoragePruner) Stop() {
+	if r.job == nil {
+		return
+	}
+
+	if err := r.job.Close(); err != nil {
+		r.api.LogWarn("Failed to close storage prune job", "error", err.Error())
+	}
+	r.job = nil
+}
+
+// run deletes the rows of each store recorded before its retention period
+func (r *StoragePruner) run() {
+	now := r.now()
+	for _, store := range r.stores {
+		deleted, err := store.prune(now.Add(-store.retention))
+		if err != nil {
+			r.api.LogError("Failed to prune "+store.name, "error", err.Error())
+			continue
+		}
+		if deleted > 0 {
+			r.api.LogDebug("Pruned "+store.name, "count", deleted)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/alertindex"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/audit"
+)
+
+func TestValidateStorageDriver(t *testing.T) {
+	assert.NoError(t, validateStorageDriver(""))
+	assert.NoError(t, validateStorageDriver(storageDriverKV))
+	assert.NoError(t, validateStorageDriver(storageDriverSQL))
+
+	err := validateStorageDriver("redis")
+	require.Error(t, err)
+	assert.Equal(t, "storage driver must be 'kv' or 'sql' (got 'redis')", err.Error())
+}
+
+func TestOpenStorage(t *testing.T) {
+	t.Run("KV store by default", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		p := &Plugin{}
+		p.SetAPI(api)
+
+		for _, driver := range []string{"", storageDriverKV} {
+			require.NoError(t, p.openStorage(driver))
+			assert.IsType(t, &alertindex.Index{}, p.alertIndex)
+			assert.IsType(t, &audit.Log{}, p.auditLog)
+			assert.Nil(t, p.pollHistory)
+			assert.Nil(t, p.storagePruner)
+		}
+	})
+
+	t.Run("fails without a database", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		p := &Plugin{client: pluginapi.NewClient(api, nil)}
+		p.SetAPI(api)
+
+		err := p.openStorage(storageDriverSQL)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no db driver was provided")
+		assert.Nil(t, p.alertIndex)
+	})
+}
+
+func TestStoragePruner_run(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+	api.On("LogError", "Failed to prune alert index", "error", "database is closed").Once()
+	api.On("LogDebug", "Pruned audit log", "count", int64(2)).Once()
+
+	var auditCutoff time.Time
+	pruner := NewStoragePruner(api, []prunableStore{
+		{name: "alert index", retention: alertindex.Retention, prune: func(time.Time) (int64, error) {
+			return 0, errors.New("database is closed")
+		}},
+		{name: "audit log", retention: audit.Retention, prune: func(before time.Time) (int64, error) {
+			auditCutoff = before
+			return 2, nil
+		}},
+		{name: "poll history", retention: time.Hour, prune: func(time.Time) (int64, error) {
+			return 0, nil
+		}},
+	})
+	pruner.now = func() time.Time { return now }
+
+	pruner.run()
+
+	assert.Equal(t, now.Add(-audit.Retention), auditCutoff)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// parseSubscribeParams parses /dataminr subscribe parameters.
+// type:<alert type> and keyword:<word> are filters (each may be repeated); the remaining
+// words are the backend name.
+func parseSubscribeParams(params []string) (string, backend.ChannelSubscription, error) {
+	var subscription backend.ChannelSubscription
+	var nameParts []string
+
+	for _, param := range params {
+		switch {
+		case strings.HasPrefix(strings.ToLower(param), "type:"):
+			alertType := param[len("type:"):]
+			if alertType == "" {
+				return "", subscription, errors.New("missing alert type after `type:`")
+			}
+			subscription.AlertTypes = append(subscription.AlertTypes, alertType)
+		case strings.HasPrefix(strings.ToLower(param), "keyword:"):
+			keyword := param[len("keyword:"):]
+			if keyword == "" {
+				return "", subscription, errors.New("missing keyword after `keyword:`")
+			}
+			subscription.Keywords = append(subscription.Keywords, keyword)
+		default:
+			nameParts = append(nameParts, param)
+		}
+	}
+
+	return strings.Join(nameParts, " "), subscription, nil
+}
+
+// describeSubscriptionFilters summarizes the filters of a subscription
+func describeSubscriptionFilters(subscription backend.ChannelSubscription) string {
+	var filters []string
+	if len(subscription.AlertTypes) > 0 {
+		filters = append(filters, "types: "+strings.Join(subscription.AlertTypes, ", "))
+	}
+	if len(subscription.Keywords) > 0 {
+		filters = append(filters, "keywords: "+strings.Join(subscription.Keywords, ", "))
+	}
+	if len(filters) == 0 {
+		return "all alerts"
+	}
+	return strings.Join(filters, "; ")
+}
+
+// canManageChannelSubscriptions reports whether the user may change the channel's subscriptions.
+// Channel admins, team admins and system admins hold the permission.
+func (p *Plugin) canManageChannelSubscriptions(userID, channelID string) bool {
+	return p.API.HasPermissionToChannel(userID, channelID, model.PermissionManageChannelRoles)
+}
+
+// executeSubscribe handles /dataminr subscribe <backend> [type:<alert type>] [keyword:<word>]
+func (p *Plugin) executeSubscribe(args *model.CommandArgs, params []string) string {
+	name, subscription, err := parseSubscribeParams(params)
+	if err != nil {
+		return fmt.Sprintf("Invalid subscription: %s.", err.Error())
+	}
+	if name == "" {
+		return fmt.Sprintf("Please specify a backend, e.g. `/%s subscribe Weather Watch type:flash keyword:flood`.", commandTrigger)
+	}
+
+	if !p.canManageChannelSubscriptions(args.UserId, args.ChannelId) {
+		return "You must be a channel administrator to subscribe this channel."
+	}
+
+	b := p.findBackend(name)
+	if b == nil {
+		return fmt.Sprintf("Backend `%s` not found.", name)
+	}
+
+	subscription.ChannelID = args.ChannelId
+	subscription.CreatedBy = args.UserId
+	subscription.CreatedAt = time.Now().UTC()
+
+	added, err := p.subscriptions.Add(b.GetID(), subscription)
+	if err != nil {
+		p.API.LogError("Failed to subscribe channel", "id", b.GetID(), "channelId", args.ChannelId, "error", err.Error())
+		return fmt.Sprintf("Failed to subscribe this channel to **%s**.", b.GetName())
+	}
+
+	if !added {
+		return fmt.Sprintf("Updated this channel's subscription to **%s** (%s).", b.GetName(), describeSubscriptionFilters(subscription))
+	}
+	return fmt.Sprintf("Subscribed this channel to **%s** (%s).", b.GetName(), describeSubscriptionFilters(subscription))
+}
+
+// executeUnsubscribe handles /dataminr unsubscribe <backend>
+func (p *Plugin) executeUnsubscribe(args *model.CommandArgs, params []string) string {
+	if len(params) == 0 {
+		return fmt.Sprintf("Please specify a backend, e.g. `/%s unsubscribe Weather Watch`.", commandTrigger)
+	}
+
+	if !p.canManageChannelSubscriptions(args.UserId, args.ChannelId) {
+		return "You must be a channel administrator to unsubscribe this channel."
+	}
+
+	name := strings.Join(params, " ")
+	b := p.findBackend(name)
+	if b == nil {
+		return fmt.Sprintf("Backend `%s` not found.", name)
+	}
+
+	removed, err := p.subscriptions.Remove(b.GetID(), args.ChannelId)
+	if err != nil {
+		p.API.LogError("Failed to unsubscribe channel", "id", b.GetID(), "channelId", args.ChannelId, "error", err.Error())
+		return fmt.Sprintf("Failed to unsubscribe this channel from **%s**.", b.GetName())
+	}
+
+	if !removed {
+		return fmt.Sprintf("This channel is not subscribed to **%s**.", b.GetName())
+	}
+	return fmt.Sprintf("Unsubscribed this channel from **%s**.", b.GetName())
+}
+
+// executeSubscriptions handles /dataminr subscriptions, listing the current channel's subscriptions
+func (p *Plugin) executeSubscriptions(args *model.CommandArgs, _ []string) string {
+	backends := p.registry.List()
+	sort.Slice(backends, func(i, j int) bool { return backends[i].GetName() < backends[j].GetName() })
+
+	var sb strings.Builder
+	for _, b := range backends {
+		subscriptions, err := p.subscriptions.List(b.GetID())
+		if err != nil {
+			p.API.LogError("Failed to list channel subscriptions", "id", b.GetID(), "error", err.Error())
+			continue
+		}
+
+		for _, subscription := range subscriptions {
+			if subscription.ChannelID == args.ChannelId {
+				sb.WriteString(fmt.Sprintf("* **%s** - %s\n", b.GetName(), describeSubscriptionFilters(subscription)))
+			}
+		}
+	}
+
+	if sb.Len() == 0 {
+		return fmt.Sprintf("This channel is not subscribed to any backends. Use `/%s subscribe <backend name>` to subscribe.", commandTrigger)
+	}
+	return "###### Channel subscriptions\n" + sb.String()
+}
+
+// deleteSubscriptions removes the channel subscriptions of a removed backend
+func (p *Plugin) deleteSubscriptions(backendID string) {
+	if p.subscriptions == nil {
+		return
+	}
+
+	if err := p.subscriptions.DeleteAll(backendID); err != nil {
+		p.API.LogWarn("Failed to delete channel subscriptions", "id", backendID, "error", err.Error())
+	}
+}
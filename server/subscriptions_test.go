@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func newSubscriptionTestPlugin(t *testing.T) (*Plugin, *plugintest.API, *[]byte) {
+	api := &plugintest.API{}
+	p := newCommandTestPlugin(api)
+	p.registry = backend.NewRegistry()
+	require.NoError(t, p.registry.Register(&fakeBackend{id: "backend-1", name: "Weather Watch"}))
+
+	stored := new([]byte)
+	api.On("KVGet", "backend_backend-1_subscriptions").Return(func(string) []byte { return *stored }, nil)
+	api.On("KVSetWithOptions", "backend_backend-1_subscriptions", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*stored = args.Get(1).([]byte)
+	}).Return(true, nil)
+	return p, api, stored
+}
+
+func TestParseSubscribeParams(t *testing.T) {
+	name, subscription, err := parseSubscribeParams([]string{"Weather", "type:flash", "Watch", "keyword:flood", "TYPE:Urgent"})
+	require.NoError(t, err)
+	assert.Equal(t, "Weather Watch", name)
+	assert.Equal(t, []string{"flash", "Urgent"}, subscription.AlertTypes)
+	assert.Equal(t, []string{"flood"}, subscription.Keywords)
+
+	_, _, err = parseSubscribeParams([]string{"Weather", "keyword:"})
+	assert.Error(t, err)
+}
+
+func TestExecuteSubscribe(t *testing.T) {
+	args := &model.CommandArgs{UserId: "user-id", ChannelId: "channel-1"}
+
+	t.Run("subscribes and updates the channel", func(t *testing.T) {
+		p, api, stored := newSubscriptionTestPlugin(t)
+		api.On("HasPermissionToChannel", "user-id", "channel-1", model.PermissionManageChannelRoles).Return(true)
+
+		text := p.executeSubscribe(args, []string{"weather", "watch", "type:flash"})
+		assert.Equal(t, "Subscribed this channel to **Weather Watch** (types: flash).", text)
+
+		text = p.executeSubscribe(args, []string{"weather", "watch", "keyword:flood"})
+		assert.Equal(t, "Updated this channel's subscription to **Weather Watch** (keywords: flood).", text)
+
+		var subscriptions []backend.ChannelSubscription
+		require.NoError(t, json.Unmarshal(*stored, &subscriptions))
+		require.Len(t, subscriptions, 1)
+		assert.Equal(t, "channel-1", subscriptions[0].ChannelID)
+		assert.Equal(t, "user-id", subscriptions[0].CreatedBy)
+		assert.Equal(t, []string{"flood"}, subscriptions[0].Keywords)
+		assert.Empty(t, subscriptions[0].AlertTypes)
+	})
+
+	t.Run("requires channel admin", func(t *testing.T) {
+		p, api, _ := newSubscriptionTestPlugin(t)
+		api.On("HasPermissionToChannel", "user-id", "channel-1", model.PermissionManageChannelRoles).Return(false)
+
+		assert.Equal(t, "You must be a channel administrator to subscribe this channel.", p.executeSubscribe(args, []string{"Weather", "Watch"}))
+		api.AssertNotCalled(t, "KVSet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		p, api, _ := newSubscriptionTestPlugin(t)
+		api.On("HasPermissionToChannel", "user-id", "channel-1", model.PermissionManageChannelRoles).Return(true)
+
+		assert.Contains(t, p.executeSubscribe(args, nil), "Please specify a backend")
+		assert.Equal(t, "Invalid subscription: missing alert type after `type:`.", p.executeSubscribe(args, []string{"Weather", "type:"}))
+		assert.Equal(t, "Backend `Other` not found.", p.executeSubscribe(args, []string{"Other"}))
+	})
+}
+
+func TestExecuteUnsubscribe(t *testing.T) {
+	args := &model.CommandArgs{UserId: "user-id", ChannelId: "channel-1"}
+	p, api, _ := newSubscriptionTestPlugin(t)
+	api.On("HasPermissionToChannel", "user-id", "channel-1", model.PermissionManageChannelRoles).Return(true)
+
+	assert.Equal(t, "This channel is not subscribed to **Weather Watch**.", p.executeUnsubscribe(args, []string{"Weather", "Watch"}))
+
+	p.executeSubscribe(args, []string{"Weather", "Watch"})
+	assert.Equal(t, "Unsubscribed this channel from **Weather Watch**.", p.executeUnsubscribe(args, []string{"Weather", "Watch"}))
+	assert.Equal(t, "This channel is not subscribed to **Weather Watch**.", p.executeUnsubscribe(args, []string{"Weather", "Watch"}))
+}
+
+func TestExecuteSubscriptions(t *testing.T) {
+	p, api, _ := newSubscriptionTestPlugin(t)
+	api.On("HasPermissionToChannel", "user-id", "channel-1", model.PermissionManageChannelRoles).Return(true)
+
+	assert.Contains(t, p.executeSubscriptions(&model.CommandArgs{ChannelId: "channel-1"}, nil), "not subscribed to any backends")
+
+	p.executeSubscribe(&model.CommandArgs{UserId: "user-id", ChannelId: "channel-1"}, []string{"Weather", "Watch", "type:flash", "keyword:flood"})
+
+	assert.Equal(t, "###### Channel subscriptions\n* **Weather Watch** - types: flash; keywords: flood\n",
+		p.executeSubscriptions(&model.CommandArgs{ChannelId: "channel-1"}, nil))
+	assert.Contains(t, p.executeSubscriptions(&model.CommandArgs{ChannelId: "channel-2"}, nil), "not subscribed to any backends")
+}
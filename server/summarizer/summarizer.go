@@ -0,0 +1,75 @@
+// Package summarizer summarizes long alert text with an OpenAI-compatible chat completions endpoint.
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/textapi"
+)
+
+// chatMessage is a message of a chat completions request or response
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatRequest is the body of a chat completions request
+type chatRequest struct {
+	Model    string        `json:"model,omitempty"`
+	Messages []chatMessage `json:"messages"`
+}
+
+// chatResponse holds the parts of a chat completions response used for the summary
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// HTTPSummarizer implements backend.Summarizer by sending the text to a chat completions
+// endpoint, such as the Mattermost AI plugin or any OpenAI-compatible API.
+// It is safe for concurrent use.
+type HTTPSummarizer struct {
+	client   *textapi.Client
+	settings backend.SummarizerSettings
+}
+
+// NewHTTPSummarizer creates a summarizer for the configured endpoint
+func NewHTTPSummarizer(settings backend.SummarizerSettings) *HTTPSummarizer {
+	return &HTTPSummarizer{
+		client:   textapi.NewClient("summarizer", settings.URL, settings.Headers),
+		settings: settings,
+	}
+}
+
+// Summarize asks the endpoint for a summary of the text, truncated to backend.MaxSummaryChars
+func (s *HTTPSummarizer) Summarize(ctx context.Context, text string) (string, error) {
+	var completion chatResponse
+	err := s.client.Post(ctx, chatRequest{
+		Model: s.settings.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: s.settings.Instruction()},
+			{Role: "user", Content: text},
+		},
+	}, &completion)
+	if err != nil {
+		return "", err
+	}
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("summarizer response has no choices")
+	}
+
+	return truncate(strings.TrimSpace(completion.Choices[0].Message.Content), backend.MaxSummaryChars), nil
+}
+
+// truncate shortens the text to at most maxChars characters, ending it with an ellipsis
+func truncate(text string, maxChars int) string {
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text
+	}
+	return string(runes[:maxChars-1]) + "…"
+}
@@ -0,0 +1,87 @@
+package summarizer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestHTTPSummarizer_Summarize(t *testing.T) {
+	var received chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "  A fire broke out downtown.\n"}}]}`))
+	}))
+	defer server.Close()
+
+	summarizer := NewHTTPSummarizer(backend.SummarizerSettings{
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+		Model:   "gpt-4o-mini",
+	})
+
+	summary, err := summarizer.Summarize(context.Background(), "Long source text")
+	require.NoError(t, err)
+	assert.Equal(t, "A fire broke out downtown.", summary)
+
+	assert.Equal(t, "gpt-4o-mini", received.Model)
+	require.Len(t, received.Messages, 2)
+	assert.Equal(t, chatMessage{Role: "system", Content: backend.SummarizerSettings{}.Instruction()}, received.Messages[0])
+	assert.Equal(t, chatMessage{Role: "user", Content: "Long source text"}, received.Messages[1])
+}
+
+func TestHTTPSummarizer_TruncatesLongSummaries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []any{map[string]any{"message": map[string]any{"content": strings.Repeat("a", backend.MaxSummaryChars+50)}}},
+		})
+	}))
+	defer server.Close()
+
+	summary, err := NewHTTPSummarizer(backend.SummarizerSettings{URL: server.URL}).Summarize(context.Background(), "text")
+	require.NoError(t, err)
+	assert.Len(t, []rune(summary), backend.MaxSummaryChars)
+	assert.True(t, strings.HasSuffix(summary, "…"))
+}
+
+func TestHTTPSummarizer_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected string
+	}{
+		{"error status", http.StatusServiceUnavailable, "", "summarizer returned status 503"},
+		{"invalid JSON", http.StatusOK, "not json", "failed to parse summarizer response"},
+		{"no choices", http.StatusOK, `{"choices": []}`, "summarizer response has no choices"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewHTTPSummarizer(backend.SummarizerSettings{URL: server.URL}).Summarize(context.Background(), "text")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// taxonomySyncJobID is the cluster job ID for fetching the taxonomy of each backend
+	taxonomySyncJobID = "dataminr_taxonomy_sync"
+
+	// taxonomySyncInterval is how often the taxonomy of each backend is fetched
+	taxonomySyncInterval = 6 * time.Hour
+
+	// taxonomyFetchTimeout bounds how long fetching the taxonomy of a backend may take
+	taxonomyFetchTimeout = time.Minute
+
+	// taxonomyKeyPrefix is the KV key prefix of the cached taxonomy of a backend
+	taxonomyKeyPrefix = "taxonomy_"
+)
+
+// TaxonomySync periodically fetches the alert lists and topics available to each running
+// backend and caches them in the KV store, for configuration UIs and to warn about alert
+// list IDs and topic styles the account doesn't know. It runs as a cluster job so each
+// taxonomy is fetched by a single node.
+type TaxonomySync struct {
+	api      plugin.API
+	registry *backend.Registry
+	config   func() *configuration
+	job      *cluster.Job
+}
+
+// NewTaxonomySync creates a taxonomy sync job over the backends of a registry
+func NewTaxonomySync(api plugin.API, registry *backend.Registry, config func() *configuration) *TaxonomySync {
+	return &TaxonomySync{
+		api:      api,
+		registry: registry,
+		config:   config,
+	}
+}
+
+// Start schedules the periodic cluster-aware taxonomy sync
+func (s *TaxonomySync) Start() error {
+	job, err := cluster.Schedule(s.api, taxonomySyncJobID, cluster.MakeWaitForInterval(taxonomySyncInterval), s.run)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule taxonomy sync job")
+	}
+
+	s.job = job
+	return nil
+}
+
+// Stop cancels the taxonomy sync job
+func (s *TaxonomySync) Stop() {
+	if s.job == nil {
+		return
+	}
+
+	if err := s.job.Close(); err != nil {
+		s.api.LogWarn("Failed to close taxonomy sync job", "error", err.Error())
+	}
+	s.job = nil
+}
+
+// run fetches and caches the taxonomy of each running backend, and logs a warning for each
+// alert list ID and topic style of its configuration the taxonomy doesn't know. Backends
+// whose source has no taxonomy are skipped.
+func (s *TaxonomySync) run() {
+	configs := s.config().backends()
+	for _, b := range s.registry.List() {
+		ctx, cancel := context.WithTimeout(context.Background(), taxonomyFetchTimeout)
+		taxonomy, err := b.FetchTaxonomy(ctx)
+		cancel()
+		if errors.Is(err, backend.ErrTaxonomyUnsupported) {
+			continue
+		}
+		if err != nil {
+			s.api.LogWarn("Failed to fetch backend taxonomy", "backendId", b.GetID(), "error", err.Error())
+			continue
+		}
+
+		if err := saveTaxonomy(s.api, b.GetID(), taxonomy); err != nil {
+			s.api.LogError("Failed to cache backend taxonomy", "backendId", b.GetID(), "error", err.Error())
+			continue
+		}
+
+		if cfg, found := findBackendConfigByID(configs, b.GetID()); found {
+			for _, warning := range taxonomy.Check(cfg) {
+				s.api.LogWarn("Backend configuration doesn't match its taxonomy", "backendId", cfg.ID, "warning", warning)
+			}
+		}
+	}
+}
+
+// saveTaxonomy caches the taxonomy of a backend
+func saveTaxonomy(api plugin.API, backendID string, taxonomy backend.Taxonomy) error {
+	data, err := json.Marshal(taxonomy)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal taxonomy")
+	}
+	if appErr := api.KVSet(taxonomyKeyPrefix+backendID, data); appErr != nil {
+		return errors.Wrap(appErr, "failed to save taxonomy")
+	}
+	return nil
+}
+
+// loadTaxonomy returns the cached taxonomy of a backend, or nil if none was fetched yet
+func loadTaxonomy(api plugin.API, backendID string) (*backend.Taxonomy, error) {
+	data, appErr := api.KVGet(taxonomyKeyPrefix + backendID)
+	if appErr != nil {
+		return nil, errors.Wrap(appErr, "failed to get taxonomy")
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var taxonomy backend.Taxonomy
+	if err := json.Unmarshal(data, &taxonomy); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal taxonomy")
+	}
+	return &taxonomy, nil
+}
+
+// taxonomyResponse is the cached taxonomy of a backend, with the warnings about its configuration
+type taxonomyResponse struct {
+	backend.Taxonomy
+
+	// Warnings lists the alert list IDs and topic styles of the backend the taxonomy doesn't know
+	Warnings []string `json:"warnings"`
+}
+
+// getBackendTaxonomy handles GET /api/v1/backends/{id}/taxonomy, returning the alert lists
+// and topics last fetched for a backend.
+func (p *Plugin) getBackendTaxonomy(w http.ResponseWriter, r *http.Request) {
+	cfg, found := findBackendConfigByID(p.getConfiguration().backends(), mux.Vars(r)["id"])
+	if !found {
+		http.Error(w, "Backend not found", http.StatusNotFound)
+		return
+	}
+
+	taxonomy, err := loadTaxonomy(p.API, cfg.ID)
+	if err != nil {
+		p.API.LogError("Failed to load backend taxonomy", "backendId", cfg.ID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if taxonomy == nil {
+		http.Error(w, "Taxonomy not fetched yet", http.StatusNotFound)
+		return
+	}
+
+	response := taxonomyResponse{Taxonomy: *taxonomy, Warnings: taxonomy.Check(cfg)}
+	if response.Warnings == nil {
+		response.Warnings = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("Failed to encode backend taxonomy response", "error", err.Error())
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// newTaxonomyTestAPI creates a plugin API backed by an in-memory KV store
+func newTaxonomyTestAPI() (*plugintest.API, map[string][]byte) {
+	kv := make(map[string][]byte)
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return kv[key], nil
+	})
+	api.On("KVSet", mock.Anything, mock.Anything).Return(func(key string, value []byte) *model.AppError {
+		kv[key] = value
+		return nil
+	})
+	return api, kv
+}
+
+var testTaxonomy = backend.Taxonomy{
+	AlertLists: []backend.TaxonomyList{{ID: "101", Name: "Severe Weather", Type: "TOPIC"}},
+	Topics:     []string{"Severe Weather"},
+	FetchedAt:  time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+}
+
+func TestTaxonomySync_run(t *testing.T) {
+	api, kv := newTaxonomyTestAPI()
+	api.On("LogWarn", "Backend configuration doesn't match its taxonomy", "backendId", "weather", "warning", "alert list ID '999' is not available to the account").Once()
+	api.On("LogWarn", "Backend configuration doesn't match its taxonomy", "backendId", "inherited", "warning", "alert list ID '888' is not available to the account").Once()
+	api.On("LogWarn", "Failed to fetch backend taxonomy", "backendId", "failing", "error", "unexpected HTTP status 502").Once()
+
+	registry := backend.NewRegistry()
+	require.NoError(t, registry.Register(&fakeBackend{id: "weather", taxonomy: testTaxonomy}))
+	require.NoError(t, registry.Register(&fakeBackend{id: "inherited", taxonomy: testTaxonomy}))
+	require.NoError(t, registry.Register(&fakeBackend{id: "simulator", taxonomyErr: backend.ErrTaxonomyUnsupported}))
+	require.NoError(t, registry.Register(&fakeBackend{id: "failing", taxonomyErr: errors.New("unexpected HTTP status 502")}))
+
+	// Backends without alert lists are checked against the default alert lists
+	config := &configuration{
+		Defaults: &backend.Defaults{AlertListIDs: []string{"888"}},
+		Backends: []backend.Config{
+			{ID: "weather", AlertListIDs: []string{"101", "999"}},
+			{ID: "inherited"},
+		},
+	}
+	NewTaxonomySync(api, registry, func() *configuration { return config }).run()
+
+	cached, err := loadTaxonomy(api, "weather")
+	require.NoError(t, err)
+	assert.Equal(t, &testTaxonomy, cached)
+	assert.NotContains(t, kv, taxonomyKeyPrefix+"simulator")
+	assert.NotContains(t, kv, taxonomyKeyPrefix+"failing")
+	api.AssertExpectations(t)
+}
+
+func TestGetBackendTaxonomy(t *testing.T) {
+	api, _ := newTaxonomyTestAPI()
+	p := newCommandTestPlugin(api)
+	p.setConfiguration(&configuration{
+		Defaults: &backend.Defaults{AlertListIDs: []string{"888"}},
+		Backends: []backend.Config{
+			{ID: "weather", AlertListIDs: []string{"999"}},
+			{ID: "inherited"},
+			{ID: "new"},
+		},
+	})
+	require.NoError(t, saveTaxonomy(api, "weather", testTaxonomy))
+	require.NoError(t, saveTaxonomy(api, "inherited", testTaxonomy))
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/backends/{id}/taxonomy", p.getBackendTaxonomy).Methods(http.MethodGet)
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	w := get("/api/v1/backends/weather/taxonomy")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response taxonomyResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, testTaxonomy.AlertLists, response.AlertLists)
+	assert.Equal(t, testTaxonomy.Topics, response.Topics)
+	assert.Equal(t, []string{"alert list ID '999' is not available to the account"}, response.Warnings)
+
+	w = get("/api/v1/backends/inherited/taxonomy")
+	require.Equal(t, http.StatusOK, w.Code)
+	response = taxonomyResponse{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, []string{"alert list ID '888' is not available to the account"}, response.Warnings, "defaults are checked")
+
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/backends/new/taxonomy").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/backends/unknown/taxonomy").Code)
+}
+
+func TestValidateConfig_TaxonomyWarnings(t *testing.T) {
+	p, api := newConfigTransferTestPlugin(t)
+	require.NoError(t, saveTaxonomy(api, "550e8400-e29b-41d4-a716-446655440000", testTaxonomy))
+
+	w := httptest.NewRecorder()
+	p.validateConfig(w, httptest.NewRequest(http.MethodPost, "/api/v1/config/validate", strings.NewReader(`{"backends": [
+		{"id": "550e8400-e29b-41d4-a716-446655440000", "name": "Stored", "type": "dataminr", "url": "https://api.example.com",
+		 "apiId": "api-user", "apiKey": "key", "channelId": "channel-1", "pollIntervalSeconds": 30,
+		 "alertListIds": ["101", "999"], "topicStyles": [{"match": "weather", "emoji": ":cloud:"}]}
+	]}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response configValidationResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.True(t, response.Valid)
+	require.Len(t, response.Backends, 1)
+	assert.Equal(t, []string{"alert list ID '999' is not available to the account"}, response.Backends[0].Warnings)
+}
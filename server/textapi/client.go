@@ -0,0 +1,76 @@
+// Package textapi sends alert text to the HTTP JSON services that summarize and translate it.
+package textapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// requestTimeout bounds a single request so alert posting is not held up for long
+	requestTimeout = 15 * time.Second
+
+	// maxResponseSize is the largest response read
+	maxResponseSize = 256 * 1024
+)
+
+// Client posts JSON requests to a text service endpoint. It is safe for concurrent use.
+type Client struct {
+	client  *http.Client
+	service string
+	url     string
+	headers map[string]string
+}
+
+// NewClient creates a client for an endpoint. The service name, e.g. "summarizer", is used
+// in error messages; the headers are added to each request.
+func NewClient(service, url string, headers map[string]string) *Client {
+	return &Client{
+		client:  &http.Client{Timeout: requestTimeout},
+		service: service,
+		url:     url,
+		headers: headers,
+	}
+}
+
+// Post sends the request as JSON to the endpoint and decodes the JSON response into response
+func (c *Client) Post(ctx context.Context, request, response any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", c.service, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", c.service, err)
+	}
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %w", c.service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", c.service, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", c.service, err)
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", c.service, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package textapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Post(t *testing.T) {
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"text": "reply"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("summarizer", server.URL, map[string]string{"Authorization": "Bearer token"})
+
+	var response struct {
+		Text string `json:"text"`
+	}
+	require.NoError(t, client.Post(context.Background(), map[string]string{"q": "text"}, &response))
+	assert.Equal(t, map[string]string{"q": "text"}, received)
+	assert.Equal(t, "reply", response.Text)
+}
+
+func TestClient_PostErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected string
+	}{
+		{"error status", http.StatusServiceUnavailable, "", "translator returned status 503"},
+		{"invalid JSON", http.StatusOK, "not json", "failed to parse translator response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			var response map[string]string
+			err := NewClient("translator", server.URL, nil).Post(context.Background(), map[string]string{}, &response)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
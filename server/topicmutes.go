@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// parseMuteTopicParams splits /dataminr mute topic parameters into the topic and mute duration.
+// The last word is treated as the duration when it parses as one (e.g., 4h, 2d).
+func parseMuteTopicParams(params []string) (string, time.Duration) {
+	duration := backend.DefaultTopicMuteDuration
+	if len(params) > 1 {
+		if parsed, err := parseDurationParam(params[len(params)-1]); err == nil {
+			duration = parsed
+			params = params[:len(params)-1]
+		}
+	}
+	return strings.Join(params, " "), duration
+}
+
+// executeMute handles /dataminr mute topic <name> [duration], /dataminr mute list
+// "<alert list>" [duration] [backend:<name>] and /dataminr mute list
+func (p *Plugin) executeMute(args *model.CommandArgs, params []string) string {
+	if !p.API.HasPermissionToChannel(args.UserId, args.ChannelId, model.PermissionReadChannel) {
+		return "You must be a member of this channel to mute topics."
+	}
+
+	if len(params) > 0 && strings.EqualFold(params[0], "list") {
+		if len(params) > 1 {
+			return p.executeMuteAlertList(args, params[1:])
+		}
+		return p.listMutes(args.ChannelId)
+	}
+
+	if len(params) < 2 || !strings.EqualFold(params[0], "topic") {
+		return fmt.Sprintf("Please specify a topic, e.g. `/%s mute topic Severe Weather 4h`.", commandTrigger)
+	}
+
+	topic, duration := parseMuteTopicParams(params[1:])
+	if duration > backend.MaxTopicMuteDuration {
+		return fmt.Sprintf("Topics can be muted for at most %d days.", int(backend.MaxTopicMuteDuration.Hours()/24))
+	}
+
+	now := time.Now()
+	mute := backend.TopicMute{Topic: topic, Until: now.Add(duration).UTC(), CreatedBy: args.UserId}
+	if err := p.topicMutes.Mute(args.ChannelId, mute, now); err != nil {
+		p.API.LogError("Failed to mute topic", "channelId", args.ChannelId, "topic", topic, "error", err.Error())
+		return fmt.Sprintf("Failed to mute topic **%s**.", topic)
+	}
+
+	return fmt.Sprintf("Alerts with the topic **%s** are muted in this channel until %s.", topic, mute.Until.Format(time.RFC1123))
+}
+
+// executeUnmute handles /dataminr unmute topic <name> and /dataminr unmute list "<alert list>" [backend:<name>]
+func (p *Plugin) executeUnmute(args *model.CommandArgs, params []string) string {
+	if !p.API.HasPermissionToChannel(args.UserId, args.ChannelId, model.PermissionReadChannel) {
+		return "You must be a member of this channel to unmute topics."
+	}
+
+	if len(params) > 0 && strings.EqualFold(params[0], "list") {
+		return p.executeUnmuteAlertList(args, params[1:])
+	}
+
+	if len(params) < 2 || !strings.EqualFold(params[0], "topic") {
+		return fmt.Sprintf("Please specify a topic, e.g. `/%s unmute topic Severe Weather`.", commandTrigger)
+	}
+
+	topic := strings.Join(params[1:], " ")
+	removed, err := p.topicMutes.Unmute(args.ChannelId, topic, time.Now())
+	if err != nil {
+		p.API.LogError("Failed to unmute topic", "channelId", args.ChannelId, "topic", topic, "error", err.Error())
+		return fmt.Sprintf("Failed to unmute topic **%s**.", topic)
+	}
+
+	if !removed {
+		return fmt.Sprintf("The topic **%s** is not muted in this channel.", topic)
+	}
+	return fmt.Sprintf("Alerts with the topic **%s** are shown in this channel again.", topic)
+}
+
+// listMutes describes the topics and alert lists muted in a channel
+func (p *Plugin) listMutes(channelID string) string {
+	mutes, err := p.topicMutes.List(channelID, time.Now())
+	if err != nil {
+		p.API.LogError("Failed to list topic mutes", "channelId", channelID, "error", err.Error())
+		return "Failed to list the muted topics."
+	}
+
+	alertLists, err := p.listAlertListMutes(channelID)
+	if err != nil {
+		p.API.LogError("Failed to list alert list mutes", "channelId", channelID, "error", err.Error())
+		return "Failed to list the muted alert lists."
+	}
+
+	if len(mutes) == 0 && alertLists == "" {
+		return "No topics or alert lists are muted in this channel."
+	}
+
+	var sb strings.Builder
+	if len(mutes) > 0 {
+		sb.WriteString("###### Muted topics\n")
+		for _, mute := range mutes {
+			sb.WriteString(fmt.Sprintf("* **%s** until %s\n", mute.Topic, mute.Until.Format(time.RFC1123)))
+		}
+	}
+	sb.WriteString(alertLists)
+	return sb.String()
+}
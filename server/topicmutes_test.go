@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func newTopicMuteTestPlugin(allowed bool) (*Plugin, *plugintest.API) {
+	api := &plugintest.API{}
+	p := newCommandTestPlugin(api)
+	api.On("HasPermissionToChannel", "user-id", "channel-1", model.PermissionReadChannel).Return(allowed)
+
+	var stored []byte
+	api.On("KVGet", "channel_channel-1_topic_mutes").Return(func(string) []byte { return stored }, nil)
+	api.On("KVSet", "channel_channel-1_topic_mutes", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).([]byte)
+	}).Return(nil)
+	api.On("KVDelete", "channel_channel-1_topic_mutes").Run(func(mock.Arguments) { stored = nil }).Return(nil)
+	api.On("KVGet", "channel_channel-1_alert_list_mutes").Return(nil, nil).Maybe()
+	p.registry = backend.NewRegistry()
+	return p, api
+}
+
+func TestParseMuteTopicParams(t *testing.T) {
+	topic, duration := parseMuteTopicParams([]string{"Severe", "Weather", "4h"})
+	assert.Equal(t, "Severe Weather", topic)
+	assert.Equal(t, 4*time.Hour, duration)
+
+	topic, duration = parseMuteTopicParams([]string{"2d"})
+	assert.Equal(t, "2d", topic, "a single word is always the topic")
+	assert.Equal(t, backend.DefaultTopicMuteDuration, duration)
+}
+
+func TestExecuteMute(t *testing.T) {
+	args := &model.CommandArgs{UserId: "user-id", ChannelId: "channel-1"}
+
+	t.Run("mute, list and unmute", func(t *testing.T) {
+		p, _ := newTopicMuteTestPlugin(true)
+
+		assert.Equal(t, "No topics or alert lists are muted in this channel.", p.executeMute(args, []string{"list"}))
+
+		text := p.executeMute(args, []string{"topic", "Severe", "Weather", "2h"})
+		assert.Contains(t, text, "Alerts with the topic **Severe Weather** are muted in this channel until")
+
+		assert.Contains(t, p.executeMute(args, []string{"list"}), "* **Severe Weather** until")
+
+		assert.Equal(t, "Alerts with the topic **severe weather** are shown in this channel again.",
+			p.executeUnmute(args, []string{"topic", "severe", "weather"}))
+		assert.Equal(t, "The topic **severe weather** is not muted in this channel.",
+			p.executeUnmute(args, []string{"topic", "severe", "weather"}))
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		p, _ := newTopicMuteTestPlugin(true)
+
+		assert.Contains(t, p.executeMute(args, nil), "Please specify a topic")
+		assert.Contains(t, p.executeMute(args, []string{"Flooding"}), "Please specify a topic")
+		assert.Contains(t, p.executeUnmute(args, []string{"topic"}), "Please specify a topic")
+		assert.Equal(t, "Topics can be muted for at most 30 days.", p.executeMute(args, []string{"topic", "Flooding", "31d"}))
+	})
+
+	t.Run("requires channel membership", func(t *testing.T) {
+		p, api := newTopicMuteTestPlugin(false)
+
+		assert.Equal(t, "You must be a member of this channel to mute topics.", p.executeMute(args, []string{"topic", "Flooding"}))
+		assert.Equal(t, "You must be a member of this channel to unmute topics.", p.executeUnmute(args, []string{"topic", "Flooding"}))
+		api.AssertNotCalled(t, "KVSet", mock.Anything, mock.Anything)
+	})
+}
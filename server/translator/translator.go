@@ -0,0 +1,62 @@
+// Package translator translates alert text with a LibreTranslate-compatible endpoint.
+package translator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/textapi"
+)
+
+// translateRequest is the body of a translate request
+type translateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+// translateResponse is the body of a translate response
+type translateResponse struct {
+	TranslatedText string `json:"translatedText"`
+}
+
+// HTTPTranslator implements backend.Translator by sending the text to a translate endpoint,
+// such as a self-hosted LibreTranslate server, with the source language detected automatically.
+// It is safe for concurrent use.
+type HTTPTranslator struct {
+	client   *textapi.Client
+	settings backend.TranslatorSettings
+}
+
+// NewHTTPTranslator creates a translator for the configured endpoint
+func NewHTTPTranslator(settings backend.TranslatorSettings) *HTTPTranslator {
+	return &HTTPTranslator{
+		client:   textapi.NewClient("translator", settings.URL, settings.Headers),
+		settings: settings,
+	}
+}
+
+// Translate asks the endpoint to translate the text into the target language
+func (t *HTTPTranslator) Translate(ctx context.Context, text, targetLanguage string) (string, error) {
+	var translation translateResponse
+	err := t.client.Post(ctx, translateRequest{
+		Q:      text,
+		Source: "auto",
+		Target: targetLanguage,
+		Format: "text",
+		APIKey: t.settings.APIKey,
+	}, &translation)
+	if err != nil {
+		return "", err
+	}
+
+	translated := strings.TrimSpace(translation.TranslatedText)
+	if translated == "" {
+		return "", fmt.Errorf("translator returned an empty translation")
+	}
+	return translated, nil
+}
@@ -0,0 +1,68 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestHTTPTranslator_Translate(t *testing.T) {
+	var received translateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("X-Tenant") != "ops" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"translatedText": " Incendie au centre-ville "}`))
+	}))
+	defer server.Close()
+
+	translator := NewHTTPTranslator(backend.TranslatorSettings{
+		URL:     server.URL,
+		APIKey:  "secret",
+		Headers: map[string]string{"X-Tenant": "ops"},
+	})
+
+	translated, err := translator.Translate(context.Background(), "Fire downtown", "fr")
+	require.NoError(t, err)
+	assert.Equal(t, "Incendie au centre-ville", translated)
+	assert.Equal(t, translateRequest{Q: "Fire downtown", Source: "auto", Target: "fr", Format: "text", APIKey: "secret"}, received)
+}
+
+func TestHTTPTranslator_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected string
+	}{
+		{"error status", http.StatusTooManyRequests, "", "translator returned status 429"},
+		{"invalid JSON", http.StatusOK, "not json", "failed to parse translator response"},
+		{"empty translation", http.StatusOK, `{"translatedText": ""}`, "translator returned an empty translation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewHTTPTranslator(backend.TranslatorSettings{URL: server.URL}).Translate(context.Background(), "text", "de")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/triage"
+)
+
+const (
+	// defaultTriageInvestigatingEmoji is the default reaction marking an alert as being investigated (👀)
+	defaultTriageInvestigatingEmoji = "eyes"
+
+	// defaultTriageResolvedEmoji is the default reaction marking an alert as resolved (✅)
+	defaultTriageResolvedEmoji = "white_check_mark"
+
+	// defaultTriageFalsePositiveEmoji is the default reaction marking an alert as a false positive (🚫)
+	defaultTriageFalsePositiveEmoji = "no_entry_sign"
+)
+
+// ReactionHasBeenAdded records the triage state of an alert post when a user reacts to it
+// with a triage emoji, and shows the state on the post when configured.
+// Reactions on other posts and reactions by the bot are ignored.
+func (p *Plugin) ReactionHasBeenAdded(_ *plugin.Context, reaction *model.Reaction) {
+	config := p.getConfiguration()
+	if !config.TriageReactionsEnabled || reaction.UserId == p.botID {
+		return
+	}
+
+	state, ok := config.triageReactions()[reaction.EmojiName]
+	if !ok {
+		return
+	}
+
+	post, appErr := p.API.GetPost(reaction.PostId)
+	if appErr != nil {
+		p.API.LogWarn("Failed to get reacted post for triage", "postId", reaction.PostId, "error", appErr.Error())
+		return
+	}
+
+	alertID, _ := post.GetProp(poster.AlertIDProp).(string)
+	if post.UserId != p.botID || alertID == "" {
+		return
+	}
+	backendID, _ := post.GetProp(poster.BackendIDProp).(string)
+
+	entry, err := p.triageStore.Record(triage.Entry{
+		PostID:    post.Id,
+		ChannelID: post.ChannelId,
+		AlertID:   alertID,
+		BackendID: backendID,
+	}, state, reaction.UserId)
+	if err != nil {
+		p.API.LogError("Failed to record alert triage", "postId", post.Id, "userId", reaction.UserId, "error", err.Error())
+		return
+	}
+
+	p.API.LogInfo("Alert triaged", "postId", post.Id, "alertId", alertID, "state", string(state), "userId", reaction.UserId)
+
+	if !config.TriageUpdatePosts {
+		return
+	}
+
+	if err := p.poster.MarkTriaged(post.Id, state, p.username(reaction.UserId), entry.Current().At); err != nil {
+		p.API.LogWarn("Failed to mark alert post as triaged", "postId", post.Id, "error", err.Error())
+	}
+}
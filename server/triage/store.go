@@ -0,0 +1,138 @@
+// Package triage records the triage state of alert posts set by reacting to them with emojis.
+package triage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+)
+
+const (
+	// Retention is how long the triage state of a post is kept
+	Retention = 30 * 24 * time.Hour
+
+	// entryKeyPrefix prefixes the KV key holding the triage state of a post
+	entryKeyPrefix = "triage_post_"
+
+	// maxHistory caps the number of triage changes kept per post
+	maxHistory = 20
+)
+
+// State is the triage state of an alert
+type State string
+
+const (
+	// StateInvestigating marks an alert someone is looking into
+	StateInvestigating State = "investigating"
+
+	// StateResolved marks an alert that was handled
+	StateResolved State = "resolved"
+
+	// StateFalsePositive marks an alert that needed no action
+	StateFalsePositive State = "false_positive"
+)
+
+// Label returns the display name of the state
+func (s State) Label() string {
+	switch s {
+	case StateInvestigating:
+		return "Investigating"
+	case StateResolved:
+		return "Resolved"
+	case StateFalsePositive:
+		return "False positive"
+	default:
+		return string(s)
+	}
+}
+
+// Change is a triage state change made by a user
+type Change struct {
+	State  State     `json:"state"`
+	UserID string    `json:"userId"`
+	At     time.Time `json:"at"`
+}
+
+// Entry is the triage state of an alert post with the changes that led to it, oldest first
+type Entry struct {
+	PostID    string   `json:"postId"`
+	ChannelID string   `json:"channelId"`
+	AlertID   string   `json:"alertId,omitempty"`
+	BackendID string   `json:"backendId,omitempty"`
+	History   []Change `json:"history"`
+}
+
+// Current returns the latest triage change of the post
+func (e Entry) Current() Change {
+	if len(e.History) == 0 {
+		return Change{}
+	}
+	return e.History[len(e.History)-1]
+}
+
+// Store keeps the triage state of alert posts in the KV store
+type Store struct {
+	api plugin.API
+	now func() time.Time
+}
+
+// NewStore creates a new triage store
+func NewStore(api plugin.API) *Store {
+	return &Store{
+		api: api,
+		now: time.Now,
+	}
+}
+
+// Get returns the triage state of a post, or nil if the post was never triaged
+func (s *Store) Get(postID string) (*Entry, error) {
+	data, appErr := s.api.KVGet(entryKeyPrefix + postID)
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get triage state: %w", appErr)
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal triage state: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// Record sets the triage state of a post on behalf of a user and returns the updated entry.
+// The previous states are kept as the post's triage history. The post fields of the entry
+// are only used the first time the post is triaged.
+func (s *Store) Record(post Entry, state State, userID string) (*Entry, error) {
+	entry, err := s.Get(post.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		entry = &Entry{PostID: post.PostID, ChannelID: post.ChannelID, AlertID: post.AlertID, BackendID: post.BackendID}
+	}
+
+	entry.History = append(entry.History, Change{State: state, UserID: userID, At: s.now()})
+	if len(entry.History) > maxHistory {
+		entry.History = entry.History[len(entry.History)-maxHistory:]
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal triage state: %w", err)
+	}
+
+	if _, appErr := s.api.KVSetWithOptions(entryKeyPrefix+post.PostID, data, model.PluginKVSetOptions{
+		ExpireInSeconds: int64(Retention / time.Second),
+	}); appErr != nil {
+		return nil, fmt.Errorf("failed to save triage state: %w", appErr)
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,78 @@
+package triage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStore returns a store backed by an in-memory KV store
+func newTestStore(now *time.Time) *Store {
+	kv := make(map[string][]byte)
+	api := &plugintest.API{}
+	api.On("KVGet", mock.Anything).Return(func(key string) ([]byte, *model.AppError) {
+		return kv[key], nil
+	})
+	api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+			kv[key] = value
+			return true, nil
+		})
+
+	store := NewStore(api)
+	store.now = func() time.Time { return *now }
+	return store
+}
+
+func TestStore_Record(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store := newTestStore(&now)
+
+	entry, err := store.Get("post-1")
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+
+	post := Entry{PostID: "post-1", ChannelID: "channel-1", AlertID: "alert-1", BackendID: "backend-1"}
+	entry, err = store.Record(post, StateInvestigating, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, Change{State: StateInvestigating, UserID: "user-1", At: now}, entry.Current())
+
+	now = now.Add(time.Hour)
+	_, err = store.Record(Entry{PostID: "post-1"}, StateResolved, "user-2")
+	require.NoError(t, err)
+
+	entry, err = store.Get("post-1")
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "channel-1", entry.ChannelID)
+	assert.Equal(t, "alert-1", entry.AlertID)
+	require.Len(t, entry.History, 2)
+	assert.Equal(t, StateInvestigating, entry.History[0].State)
+	assert.Equal(t, Change{State: StateResolved, UserID: "user-2", At: now}, entry.Current())
+}
+
+func TestStore_RecordCapsHistory(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store := newTestStore(&now)
+
+	for i := 0; i < maxHistory+5; i++ {
+		_, err := store.Record(Entry{PostID: "post-1"}, StateInvestigating, "user-1")
+		require.NoError(t, err)
+	}
+
+	entry, err := store.Get("post-1")
+	require.NoError(t, err)
+	assert.Len(t, entry.History, maxHistory)
+}
+
+func TestState_Label(t *testing.T) {
+	assert.Equal(t, "Investigating", StateInvestigating.Label())
+	assert.Equal(t, "Resolved", StateResolved.Label())
+	assert.Equal(t, "False positive", StateFalsePositive.Label())
+	assert.Equal(t, "custom", State("custom").Label())
+}
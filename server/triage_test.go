@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/poster"
+	"github.com/mattermost/mattermost-plugin-dataminr/server/triage"
+)
+
+// newTriageTestPlugin creates a plugin with triage reactions enabled and an alert post "post-1"
+func newTriageTestPlugin(t *testing.T, updatePosts bool) (*Plugin, *plugintest.API, *model.Post) {
+	api := &plugintest.API{}
+	mockMemoryKV(api)
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	post := &model.Post{Id: "post-1", ChannelId: "channel-1", UserId: "bot-id", Props: model.StringInterface{}}
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{{Title: "Tornado warning", Footer: "Weather Watch"}})
+	post.AddProp(poster.AlertIDProp, "alert-1")
+	post.AddProp(poster.BackendIDProp, "backend-1")
+	api.On("GetPost", "post-1").Return(post, nil)
+
+	p := newCommandTestPlugin(api)
+	p.botID = "bot-id"
+	p.poster = poster.New(api, "bot-id")
+	p.triageStore = triage.NewStore(api)
+	p.setConfiguration(&configuration{TriageReactionsEnabled: true, TriageUpdatePosts: updatePosts})
+	return p, api, post
+}
+
+func TestReactionHasBeenAdded(t *testing.T) {
+	t.Run("records the triage state", func(t *testing.T) {
+		p, api, _ := newTriageTestPlugin(t, false)
+		defer api.AssertExpectations(t)
+
+		p.ReactionHasBeenAdded(nil, &model.Reaction{PostId: "post-1", UserId: "user-1", EmojiName: "eyes"})
+
+		entry, err := p.triageStore.Get("post-1")
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		assert.Equal(t, "alert-1", entry.AlertID)
+		assert.Equal(t, "backend-1", entry.BackendID)
+		assert.Equal(t, triage.StateInvestigating, entry.Current().State)
+		assert.Equal(t, "user-1", entry.Current().UserID)
+		api.AssertNotCalled(t, "UpdatePost", mock.Anything)
+	})
+
+	t.Run("updates the alert post", func(t *testing.T) {
+		p, api, _ := newTriageTestPlugin(t, true)
+		defer api.AssertExpectations(t)
+
+		var updated *model.Post
+		api.On("GetUser", "user-1").Return(&model.User{Id: "user-1", Username: "jane"}, nil)
+		api.On("UpdatePost", mock.Anything).Run(func(args mock.Arguments) {
+			updated = args.Get(0).(*model.Post)
+		}).Return(&model.Post{}, nil)
+
+		p.ReactionHasBeenAdded(nil, &model.Reaction{PostId: "post-1", UserId: "user-1", EmojiName: "white_check_mark"})
+
+		require.NotNil(t, updated)
+		assert.Contains(t, updated.Attachments()[0].Footer, "Weather Watch | Triage: Resolved by @jane")
+	})
+
+	t.Run("ignores other reactions and posts", func(t *testing.T) {
+		p, api, _ := newTriageTestPlugin(t, true)
+		api.On("GetPost", "post-2").Return(&model.Post{Id: "post-2", UserId: "user-2"}, nil)
+
+		p.ReactionHasBeenAdded(nil, &model.Reaction{PostId: "post-1", UserId: "user-1", EmojiName: "tada"})
+		p.ReactionHasBeenAdded(nil, &model.Reaction{PostId: "post-1", UserId: "bot-id", EmojiName: "eyes"})
+		p.ReactionHasBeenAdded(nil, &model.Reaction{PostId: "post-2", UserId: "user-1", EmojiName: "eyes"})
+
+		entry, err := p.triageStore.Get("post-1")
+		require.NoError(t, err)
+		assert.Nil(t, entry)
+		entry, err = p.triageStore.Get("post-2")
+		require.NoError(t, err)
+		assert.Nil(t, entry)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		p, api, _ := newTriageTestPlugin(t, true)
+		p.setConfiguration(&configuration{})
+
+		p.ReactionHasBeenAdded(nil, &model.Reaction{PostId: "post-1", UserId: "user-1", EmojiName: "eyes"})
+
+		api.AssertNotCalled(t, "GetPost", "post-1")
+	})
+}
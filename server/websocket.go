@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// WebSocket events published for the webapp, which receives them prefixed with
+// "custom_<plugin ID>_". Events signal what to refresh and carry the current state, so a
+// webapp can update without polling the REST endpoints. In a cluster, each node watching the
+// backends may publish the same status change.
+const (
+	// wsEventBackendStatusChanged is published to system admins when a backend is enabled or
+	// disabled, paused or resumed, starts or stops failing, or enters or leaves a cool-down
+	wsEventBackendStatusChanged = "backend_status_changed"
+
+	// wsEventBackendDisabled is published to system admins when a backend is disabled in the
+	// configuration, after repeated failures or by a user
+	wsEventBackendDisabled = "backend_disabled"
+
+	// wsEventAlertPosted is published to the members of a channel when an alert is posted to it
+	wsEventAlertPosted = "alert_posted"
+)
+
+// adminBroadcast sends an event to system admins only, since backend details are not shown
+// to other users
+func adminBroadcast() *model.WebsocketBroadcast {
+	return &model.WebsocketBroadcast{ContainsSensitiveData: true}
+}
+
+// publishBackendStatusChanged publishes the previous and current status of a backend
+func (p *Plugin) publishBackendStatusChanged(b backend.Backend, previous, current backend.Status) {
+	p.API.PublishWebSocketEvent(wsEventBackendStatusChanged, map[string]any{
+		"backend_id":           b.GetID(),
+		"backend_name":         b.GetName(),
+		"previous":             describeLifecycleStatus(previous),
+		"current":              describeLifecycleStatus(current),
+		"consecutive_failures": current.ConsecutiveFailures,
+		"last_error":           current.LastError,
+	}, adminBroadcast())
+}
+
+// publishBackendDisabled publishes that a backend was disabled and why
+func (p *Plugin) publishBackendDisabled(backendID, backendName, reason string) {
+	p.API.PublishWebSocketEvent(wsEventBackendDisabled, map[string]any{
+		"backend_id":   backendID,
+		"backend_name": backendName,
+		"reason":       reason,
+	}, adminBroadcast())
+}
+
+// publishAlertPosted publishes a posted alert to the members of its channel
+func (p *Plugin) publishAlertPosted(alert backend.Alert, post *model.Post) {
+	p.API.PublishWebSocketEvent(wsEventAlertPosted, map[string]any{
+		"backend_id":   alert.BackendID,
+		"backend_name": alert.BackendName,
+		"alert_id":     alert.AlertID,
+		"alert_type":   alert.AlertType,
+		"headline":     alert.Headline,
+		"post_id":      post.Id,
+		"channel_id":   post.ChannelId,
+	}, &model.WebsocketBroadcast{ChannelId: post.ChannelId})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+func TestPublishBackendStatusChanged(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("PublishWebSocketEvent", wsEventBackendStatusChanged, map[string]any{
+		"backend_id":           "backend-1",
+		"backend_name":         "Weather Watch",
+		"previous":             "healthy",
+		"current":              "failing",
+		"consecutive_failures": 2,
+		"last_error":           "timeout",
+	}, &model.WebsocketBroadcast{ContainsSensitiveData: true}).Once()
+
+	p := newCommandTestPlugin(api)
+	p.publishBackendStatusChanged(
+		&fakeBackend{id: "backend-1", name: "Weather Watch"},
+		backend.Status{Enabled: true},
+		backend.Status{Enabled: true, ConsecutiveFailures: 2, LastError: "timeout"},
+	)
+}
+
+func TestPublishBackendDisabled(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("PublishWebSocketEvent", wsEventBackendDisabled, map[string]any{
+		"backend_id":   "backend-1",
+		"backend_name": "Weather Watch",
+		"reason":       "5 consecutive failed polls",
+	}, &model.WebsocketBroadcast{ContainsSensitiveData: true}).Once()
+
+	p := newCommandTestPlugin(api)
+	p.publishBackendDisabled("backend-1", "Weather Watch", "5 consecutive failed polls")
+}
+
+func TestPublishAlertPosted(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	// Alerts are only published to the members of their channel
+	api.On("PublishWebSocketEvent", wsEventAlertPosted, map[string]any{
+		"backend_id":   "backend-1",
+		"backend_name": "Weather Watch",
+		"alert_id":     "alert-1",
+		"alert_type":   "Flash",
+		"headline":     "Flooding reported downtown",
+		"post_id":      "post-1",
+		"channel_id":   "channel-1",
+	}, &model.WebsocketBroadcast{ChannelId: "channel-1"}).Once()
+
+	p := newCommandTestPlugin(api)
+	p.publishAlertPosted(
+		backend.Alert{BackendID: "backend-1", BackendName: "Weather Watch", AlertID: "alert-1", AlertType: "Flash", Headline: "Flooding reported downtown"},
+		&model.Post{Id: "post-1", ChannelId: "channel-1"},
+	)
+}
@@ -0,0 +1,211 @@
+// Package workflow starts Playbooks runs and creates Boards cards for alerts through the
+// APIs of the Playbooks and Boards plugins installed on the same server.
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+const (
+	// PlaybooksPluginID and BoardsPluginID are the IDs of the plugins whose APIs are called
+	PlaybooksPluginID = "playbooks"
+	BoardsPluginID    = "focalboard"
+
+	// requestTimeout bounds a single plugin API request so alert posting is not held up
+	requestTimeout = 10 * time.Second
+
+	// maxErrorBodySize is the largest part of an error response included in errors
+	maxErrorBodySize = 512
+)
+
+// Launcher starts the playbook run and creates the board card configured for an alert.
+// Requests are made on behalf of the configured owner, or the plugin bot.
+type Launcher struct {
+	api   plugin.API
+	botID string
+}
+
+// NewLauncher creates a new workflow launcher
+func NewLauncher(api plugin.API, botID string) *Launcher {
+	return &Launcher{
+		api:   api,
+		botID: botID,
+	}
+}
+
+// Launch starts the playbook run and creates the board card configured in settings for an
+// alert, both linking back to the alert post. Both are attempted even when one fails.
+func (l *Launcher) Launch(ctx context.Context, settings backend.WorkflowSettings, alert backend.Alert, post *model.Post) error {
+	ownerID, err := l.ownerID(settings)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	name := settings.Name(alert)
+	description := l.description(alert, post)
+
+	var errs []error
+	if settings.PlaybookID != "" {
+		if err := l.startRun(ctx, settings.PlaybookID, name, description, ownerID, post); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if settings.BoardID != "" {
+		if err := l.createCard(ctx, settings.BoardID, name, description, ownerID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ownerID returns the ID of the user the requests are made for
+func (l *Launcher) ownerID(settings backend.WorkflowSettings) (string, error) {
+	if settings.Owner() == "" {
+		return l.botID, nil
+	}
+
+	user, appErr := l.api.GetUserByUsername(settings.Owner())
+	if appErr != nil {
+		return "", fmt.Errorf("failed to get workflow owner '@%s': %w", settings.Owner(), appErr)
+	}
+	return user.Id, nil
+}
+
+// description builds the run summary and card text, linking to the alert post
+func (l *Launcher) description(alert backend.Alert, post *model.Post) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%s alert** from %s: %s\n\n", alert.AlertType, alert.BackendName, alert.Headline))
+	if alert.Location != nil && alert.Location.Address != "" {
+		sb.WriteString(fmt.Sprintf("Location: %s\n", alert.Location.Address))
+	}
+	if len(alert.Topics) > 0 {
+		sb.WriteString(fmt.Sprintf("Topics: %s\n", strings.Join(alert.Topics, ", ")))
+	}
+	sb.WriteString(fmt.Sprintf("[View alert post](%s/_redirect/pl/%s)", l.siteURL(), post.Id))
+	if alert.AlertURL != "" {
+		sb.WriteString(fmt.Sprintf(" | [Open in Dataminr](%s)", alert.AlertURL))
+	}
+	return sb.String()
+}
+
+// startRun starts a run of a playbook in the team of the alert post's channel
+func (l *Launcher) startRun(ctx context.Context, playbookID, name, description, ownerID string, post *model.Post) error {
+	channel, appErr := l.api.GetChannel(post.ChannelId)
+	if appErr != nil {
+		return fmt.Errorf("failed to get alert channel: %w", appErr)
+	}
+
+	body := map[string]any{
+		"name":          name,
+		"description":   description,
+		"owner_user_id": ownerID,
+		"team_id":       channel.TeamId,
+		"playbook_id":   playbookID,
+		"post_id":       post.Id,
+	}
+
+	var run struct {
+		ID string `json:"id"`
+	}
+	if err := l.request(ctx, PlaybooksPluginID, "/api/v0/runs", ownerID, body, &run); err != nil {
+		return fmt.Errorf("failed to start playbook run: %w", err)
+	}
+
+	l.api.LogInfo("Started playbook run for alert", "playbookId", playbookID, "runId", run.ID, "postId", post.Id)
+	return nil
+}
+
+// createCard adds a card with the alert description as its content to a board
+func (l *Launcher) createCard(ctx context.Context, boardID, title, description, ownerID string) error {
+	cardID := "c" + model.NewId()
+	textID := "a" + model.NewId()
+
+	blocks := []map[string]any{
+		{
+			"id":       cardID,
+			"boardId":  boardID,
+			"parentId": boardID,
+			"type":     "card",
+			"title":    title,
+			"fields": map[string]any{
+				"properties":   map[string]any{},
+				"contentOrder": []string{textID},
+			},
+		},
+		{
+			"id":       textID,
+			"boardId":  boardID,
+			"parentId": cardID,
+			"type":     "text",
+			"title":    description,
+			"fields":   map[string]any{},
+		},
+	}
+
+	if err := l.request(ctx, BoardsPluginID, "/api/v2/boards/"+boardID+"/blocks", ownerID, blocks, nil); err != nil {
+		return fmt.Errorf("failed to create board card: %w", err)
+	}
+
+	l.api.LogInfo("Created board card for alert", "boardId", boardID, "cardId", cardID)
+	return nil
+}
+
+// request posts a JSON body to another plugin's API on behalf of a user and decodes the
+// response into result (when not nil)
+func (l *Launcher) request(ctx context.Context, pluginID, path, userID string, body, result any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/"+pluginID+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Mattermost-User-ID", userID)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+
+	resp := l.api.PluginHTTP(req)
+	if resp == nil {
+		return fmt.Errorf("plugin '%s' is not available", pluginID)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("plugin '%s' returned status %d: %s", pluginID, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// siteURL returns the configured site URL without a trailing slash
+func (l *Launcher) siteURL() string {
+	config := l.api.GetConfig()
+	if config == nil || config.ServiceSettings.SiteURL == nil {
+		return ""
+	}
+	return strings.TrimSuffix(*config.ServiceSettings.SiteURL, "/")
+}
@@ -0,0 +1,143 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-dataminr/server/backend"
+)
+
+// pluginRequest is a request made to another plugin's API
+type pluginRequest struct {
+	path   string
+	userID string
+	body   []byte
+}
+
+// mockPluginHTTP answers plugin API requests with a status and body, recording the requests
+func mockPluginHTTP(api *plugintest.API, status int, body string) *[]pluginRequest {
+	requests := &[]pluginRequest{}
+	api.On("PluginHTTP", mock.Anything).Return(func(req *http.Request) *http.Response {
+		data, _ := io.ReadAll(req.Body)
+		*requests = append(*requests, pluginRequest{path: req.URL.Path, userID: req.Header.Get("Mattermost-User-ID"), body: data})
+
+		recorder := httptest.NewRecorder()
+		recorder.WriteHeader(status)
+		_, _ = recorder.WriteString(body)
+		return recorder.Result()
+	})
+	return requests
+}
+
+func newTestAPI() *plugintest.API {
+	siteURL := "https://chat.example.com/"
+	api := &plugintest.API{}
+	api.On("GetConfig").Return(&model.Config{ServiceSettings: model.ServiceSettings{SiteURL: &siteURL}}).Maybe()
+	api.On("GetChannel", "channel-id").Return(&model.Channel{Id: "channel-id", TeamId: "team-id"}, nil).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return api
+}
+
+func testAlert() backend.Alert {
+	return backend.Alert{
+		BackendName: "Weather Watch",
+		AlertID:     "alert-1",
+		AlertType:   "Flash",
+		Headline:    "Explosion reported downtown",
+		AlertURL:    "https://app.dataminr.com/alerts/alert-1",
+		Location:    &backend.Location{Address: "Oakland, CA"},
+	}
+}
+
+func TestLauncher_StartsPlaybookRun(t *testing.T) {
+	api := newTestAPI()
+	requests := mockPluginHTTP(api, http.StatusCreated, `{"id":"run-1"}`)
+
+	launcher := NewLauncher(api, "bot-user-id")
+	post := &model.Post{Id: "post-1", ChannelId: "channel-id"}
+	require.NoError(t, launcher.Launch(context.Background(), backend.WorkflowSettings{PlaybookID: "playbook1"}, testAlert(), post))
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "/playbooks/api/v0/runs", (*requests)[0].path)
+	assert.Equal(t, "bot-user-id", (*requests)[0].userID)
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal((*requests)[0].body, &body))
+	assert.Equal(t, "Flash: Explosion reported downtown", body["name"])
+	assert.Equal(t, "playbook1", body["playbook_id"])
+	assert.Equal(t, "team-id", body["team_id"])
+	assert.Equal(t, "bot-user-id", body["owner_user_id"])
+	assert.Equal(t, "post-1", body["post_id"])
+	assert.Contains(t, body["description"], "[View alert post](https://chat.example.com/_redirect/pl/post-1)")
+	assert.Contains(t, body["description"], "Location: Oakland, CA")
+}
+
+func TestLauncher_CreatesBoardCard(t *testing.T) {
+	api := newTestAPI()
+	api.On("GetUserByUsername", "duty.officer").Return(&model.User{Id: "owner-id"}, nil)
+	requests := mockPluginHTTP(api, http.StatusOK, `[]`)
+
+	launcher := NewLauncher(api, "bot-user-id")
+	settings := backend.WorkflowSettings{BoardID: "board1", OwnerUsername: "@duty.officer", NameTemplate: "{headline} ({location})"}
+	require.NoError(t, launcher.Launch(context.Background(), settings, testAlert(), &model.Post{Id: "post-1", ChannelId: "channel-id"}))
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "/focalboard/api/v2/boards/board1/blocks", (*requests)[0].path)
+	assert.Equal(t, "owner-id", (*requests)[0].userID)
+
+	var blocks []map[string]any
+	require.NoError(t, json.Unmarshal((*requests)[0].body, &blocks))
+	require.Len(t, blocks, 2)
+	assert.Equal(t, "card", blocks[0]["type"])
+	assert.Equal(t, "Explosion reported downtown (Oakland, CA)", blocks[0]["title"])
+	assert.Equal(t, "text", blocks[1]["type"])
+	assert.Equal(t, blocks[0]["id"], blocks[1]["parentId"])
+	assert.Contains(t, blocks[1]["title"], "/_redirect/pl/post-1")
+}
+
+func TestLauncher_Errors(t *testing.T) {
+	t.Run("plugin error response", func(t *testing.T) {
+		api := newTestAPI()
+		requests := mockPluginHTTP(api, http.StatusForbidden, "not a playbook member\n")
+
+		launcher := NewLauncher(api, "bot-user-id")
+		settings := backend.WorkflowSettings{PlaybookID: "playbook1", BoardID: "board1"}
+		err := launcher.Launch(context.Background(), settings, testAlert(), &model.Post{Id: "post-1", ChannelId: "channel-id"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to start playbook run: plugin 'playbooks' returned status 403: not a playbook member")
+		assert.Contains(t, err.Error(), "failed to create board card: plugin 'focalboard' returned status 403")
+		assert.Len(t, *requests, 2)
+	})
+
+	t.Run("plugin not installed", func(t *testing.T) {
+		api := newTestAPI()
+		api.On("PluginHTTP", mock.Anything).Return(nil)
+
+		launcher := NewLauncher(api, "bot-user-id")
+		err := launcher.Launch(context.Background(), backend.WorkflowSettings{PlaybookID: "playbook1"}, testAlert(), &model.Post{Id: "post-1", ChannelId: "channel-id"})
+		assert.EqualError(t, err, "failed to start playbook run: plugin 'playbooks' is not available")
+	})
+
+	t.Run("unknown owner", func(t *testing.T) {
+		api := newTestAPI()
+		api.On("GetUserByUsername", "nobody").Return(nil, model.NewAppError("Get", "not_found", nil, "", http.StatusNotFound))
+
+		launcher := NewLauncher(api, "bot-user-id")
+		err := launcher.Launch(context.Background(), backend.WorkflowSettings{PlaybookID: "playbook1", OwnerUsername: "nobody"}, testAlert(), &model.Post{Id: "post-1"})
+		require.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "failed to get workflow owner '@nobody'"))
+	})
+}
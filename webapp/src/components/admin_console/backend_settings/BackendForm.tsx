@@ -149,8 +149,8 @@ const BackendForm = (props: Props) => {
                     type='password'
                     onChange={(e) => handleFieldChange('apiKey', e.target.value)}
                     onBlur={() => handleFieldBlur('apiKey')}
-                    placeholder='your_api_key'
-                    helptext='API key/password for authentication'
+                    placeholder={props.backend.apiKeyStored ? 'Stored securely - enter a new key to replace it' : 'your_api_key'}
+                    helptext='API key/password for authentication. Keys are stored encrypted; use env:NAME to read the key from an environment variable.'
                     hasError={Boolean(getFieldError('apiKey'))}
                 />
                 {getFieldError('apiKey') && <ErrorMessage>{getFieldError('apiKey')}</ErrorMessage>}
@@ -18,6 +18,7 @@ export interface BackendConfig {
     url: string;
     apiId: string;
     apiKey: string;
+    apiKeyStored?: boolean; // API key is kept encrypted by the plugin and blank here
     channelId: string;
     pollIntervalSeconds: number;
 }
@@ -225,6 +225,12 @@ describe('validation utilities', () => {
             expect(errors.apiKey).toBe('API Key is required');
         });
 
+        it('should accept a blank apiKey that is stored by the plugin', () => {
+            const config = {...validConfig, apiKey: '', apiKeyStored: true};
+            const errors = validateBackendConfig(config, []);
+            expect(errors.apiKey).toBeUndefined();
+        });
+
         it('should return error for missing channelId', () => {
             const config = {...validConfig, channelId: ''};
             const errors = validateBackendConfig(config, []);
@@ -113,7 +113,7 @@ export function validateBackendConfig(
         errors.apiId = 'API ID is required';
     }
 
-    if (!config.apiKey || config.apiKey.trim() === '') {
+    if ((!config.apiKey || config.apiKey.trim() === '') && !config.apiKeyStored) {
         errors.apiKey = 'API Key is required';
     }
 